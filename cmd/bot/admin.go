@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	discordadapter "death-level-tracker/internal/adapters/discord"
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/adapters/storage/postgres"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+	"death-level-tracker/internal/core/services"
+)
+
+// defaultPruneAge matches the tracker's own cleanup of players not seen online.
+const defaultPruneAge = 30 * time.Minute
+
+// maxImportErrorsShown keeps the import summary readable for badly broken
+// files.
+const maxImportErrorsShown = 20
+
+// defaultBroadcastInterval spaces broadcast messages well below Discord's
+// rate limits.
+const defaultBroadcastInterval = time.Second
+
+type operatorNotifier interface {
+	SendTestNotification(guild domain.GuildConfig, message string) error
+	services.Broadcaster
+}
+
+type adminDeps struct {
+	store    ports.Repository
+	notifier operatorNotifier
+}
+
+type adminCommand func(ctx context.Context, deps adminDeps, args []string, out io.Writer) error
+
+// adminCommands are operator subcommands that reuse the storage and notifier
+// layers without starting the bot.
+var adminCommands = map[string]adminCommand{
+	"backup":        backupCommand,
+	"broadcast":     broadcastCommand,
+	"import":        importCommand,
+	"list-guilds":   listGuildsCommand,
+	"prune-players": prunePlayersCommand,
+	"restore":       restoreCommand,
+	"send-test":     sendTestCommand,
+	"set-premium":   setPremiumCommand,
+	"set-quota":     setQuotaCommand,
+}
+
+func adminMain(run adminCommand, args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	store, err := postgres.NewPostgresStore(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to connect to storage", "error", err)
+		return 1
+	}
+	defer store.Close()
+
+	session, err := discordadapter.NewSession(cfg)
+	if err != nil {
+		return 1
+	}
+
+	deps := adminDeps{store: store, notifier: discordadapter.NewAdapter(session, cfg)}
+	if err := run(ctx, deps, args, os.Stdout); err != nil {
+		slog.Error("Command failed", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func listGuildsCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("list-guilds")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	configs, err := deps.store.GetAllGuildConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("get guild configs: %w", err)
+	}
+	if len(configs) == 0 {
+		fmt.Fprintln(out, "No guilds configured")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GUILD ID\tWORLD\tLANGUAGE\tTIBIA GUILDS\tPREMIUM\tMUTED UNTIL")
+	for _, cfg := range configs {
+		muted := "-"
+		if cfg.IsMuted(now) {
+			muted = cfg.MutedUntil.Format(time.RFC3339)
+		}
+		premium := "-"
+		if cfg.Premium {
+			premium = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", cfg.DiscordGuildID, orDash(cfg.World), orDash(cfg.Language), orDash(strings.Join(cfg.TibiaGuilds, ", ")), premium, muted)
+	}
+	return w.Flush()
+}
+
+func prunePlayersCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("prune-players")
+	world := flags.String("world", "", "world to prune (required)")
+	maxAge := flags.Duration("max-age", defaultPruneAge, "delete players not seen for this long")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *world == "" {
+		return errors.New("-world is required")
+	}
+	if *maxAge <= 0 {
+		return errors.New("-max-age must be positive")
+	}
+
+	deleted, err := deps.store.DeleteOldPlayers(ctx, *world, *maxAge)
+	if err != nil {
+		return fmt.Errorf("prune players: %w", err)
+	}
+	fmt.Fprintf(out, "Deleted %d players from %s not seen for %v\n", deleted, *world, *maxAge)
+	return nil
+}
+
+func sendTestCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("send-test")
+	guildID := flags.String("guild", "", "Discord guild ID (required)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *guildID == "" {
+		return errors.New("-guild is required")
+	}
+
+	cfg, err := deps.store.GetGuildConfig(ctx, *guildID)
+	if err != nil {
+		return fmt.Errorf("get guild config: %w", err)
+	}
+	if cfg == nil {
+		return fmt.Errorf("guild %s is not configured", *guildID)
+	}
+
+	if err := deps.notifier.SendTestNotification(*cfg, formatting.MsgTestNotification); err != nil {
+		return fmt.Errorf("send test notification: %w", err)
+	}
+	fmt.Fprintf(out, "Sent test notification to guild %s\n", *guildID)
+	return nil
+}
+
+// broadcastCommand posts an announcement from the bot operator to the death
+// channel of every configured guild that has not opted out.
+func broadcastCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("broadcast")
+	message := flags.String("message", "", "announcement to send (required)")
+	interval := flags.Duration("interval", defaultBroadcastInterval, "pause between two guilds")
+	dryRun := flags.Bool("dry-run", false, "count the recipients without sending")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	text := strings.TrimSpace(*message)
+	if text == "" {
+		return errors.New("-message is required")
+	}
+	if n := utf8.RuneCountInString(text); n > formatting.MaxBroadcastLength {
+		return fmt.Errorf("-message is %d characters, the limit is %d", n, formatting.MaxBroadcastLength)
+	}
+	if *interval < 0 {
+		return errors.New("-interval must not be negative")
+	}
+
+	svc := services.NewBroadcastService(deps.store, deps.notifier, *interval)
+	result, err := svc.Broadcast(ctx, formatting.MsgBroadcast(text), *dryRun)
+	verb := "Sent to"
+	if *dryRun {
+		verb = "Dry run: would send to"
+	}
+	fmt.Fprintf(out, "%s %d guilds, skipped %d opted out and %d muted\n", verb, result.Sent, result.OptedOut, result.Muted)
+	if len(result.Failed) > 0 {
+		fmt.Fprintf(out, "Failed for %d guilds: %s\n", len(result.Failed), strings.Join(result.Failed, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("broadcast: %w", err)
+	}
+	return nil
+}
+
+func importCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("import")
+	file := flags.String("file", "", "CSV file to import, or - for stdin (required)")
+	world := flags.String("world", "", "world for rows without a world column")
+	dryRun := flags.Bool("dry-run", false, "validate and summarize without writing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	in := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	summary, err := services.NewImportService(deps.store).ImportLevels(ctx, in, *world, *dryRun)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	for i, invalid := range summary.Invalid {
+		if i == maxImportErrorsShown {
+			fmt.Fprintf(out, "... and %d more invalid rows\n", len(summary.Invalid)-i)
+			break
+		}
+		fmt.Fprintf(out, "line %d: %s\n", invalid.Line, invalid.Reason)
+	}
+	verb := "Imported"
+	if *dryRun {
+		verb = "Dry run: would import"
+	}
+	fmt.Fprintf(out, "%s %d rows: %d added, %d updated, %d unchanged, %d duplicates, %d invalid\n",
+		verb, summary.Rows, summary.Added, summary.Updated, summary.Unchanged, summary.Duplicate, len(summary.Invalid))
+	return nil
+}
+
+func setQuotaCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("set-quota")
+	guildID := flags.String("guild", "", "Discord guild ID (required)")
+	tibiaGuilds := flags.Int("tibia-guilds", 0, "Tibia guilds the guild may track; 0 uses MAX_TIBIA_GUILDS, -1 lifts the limit")
+	ignoredPlayers := flags.Int("ignored-players", 0, "characters the guild may ignore; 0 uses MAX_IGNORED_PLAYERS, -1 lifts the limit")
+	watchedPlayers := flags.Int("watched-players", 0, "characters the guild may watch skills of; 0 uses MAX_WATCHED_PLAYERS, -1 lifts the limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *guildID == "" {
+		return errors.New("-guild is required")
+	}
+
+	cfg, err := deps.store.GetGuildConfig(ctx, *guildID)
+	if err != nil {
+		return fmt.Errorf("get guild config: %w", err)
+	}
+	if cfg == nil {
+		return fmt.Errorf("guild %s is not configured", *guildID)
+	}
+
+	quota := domain.Quota{TibiaGuilds: *tibiaGuilds, IgnoredPlayers: *ignoredPlayers, WatchedPlayers: *watchedPlayers}
+	if err := deps.store.SetGuildQuota(ctx, *guildID, quota); err != nil {
+		return fmt.Errorf("set quota: %w", err)
+	}
+	fmt.Fprintf(out, "Set quota of guild %s: %s Tibia guilds, %s ignored players, %s watched players\n", *guildID, describeQuota(quota.TibiaGuilds), describeQuota(quota.IgnoredPlayers), describeQuota(quota.WatchedPlayers))
+	return nil
+}
+
+// setPremiumCommand sets the guild's stored premium flag. Guilds listed in
+// PREMIUM_GUILDS are premium regardless.
+func setPremiumCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("set-premium")
+	guildID := flags.String("guild", "", "Discord guild ID (required)")
+	enabled := flags.Bool("enabled", true, "grant premium; -enabled=false revokes it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *guildID == "" {
+		return errors.New("-guild is required")
+	}
+
+	cfg, err := deps.store.GetGuildConfig(ctx, *guildID)
+	if err != nil {
+		return fmt.Errorf("get guild config: %w", err)
+	}
+	if cfg == nil {
+		return fmt.Errorf("guild %s is not configured", *guildID)
+	}
+
+	if err := deps.store.SetGuildPremium(ctx, *guildID, *enabled); err != nil {
+		return fmt.Errorf("set premium: %w", err)
+	}
+	if *enabled {
+		fmt.Fprintf(out, "Guild %s is now premium\n", *guildID)
+	} else {
+		fmt.Fprintf(out, "Guild %s is no longer premium\n", *guildID)
+	}
+	return nil
+}
+
+func describeQuota(n int) string {
+	switch {
+	case n < 0:
+		return "unlimited"
+	case n == 0:
+		return "default"
+	}
+	return strconv.Itoa(n)
+}
+
+func backupCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("backup")
+	path := flags.String("out", "", "file to write the backup to (required)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-out is required")
+	}
+
+	f, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	summary, err := services.NewBackupService(deps.store).Backup(ctx, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	fmt.Fprintf(out, "Backed up %s to %s\n", describeBackup(summary), *path)
+	return nil
+}
+
+func restoreCommand(ctx context.Context, deps adminDeps, args []string, out io.Writer) error {
+	flags := newAdminFlags("restore")
+	path := flags.String("in", "", "backup file to restore (required)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-in is required")
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	summary, err := services.NewBackupService(deps.store).Restore(ctx, f)
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	fmt.Fprintf(out, "Restored %s from %s\n", describeBackup(summary), *path)
+	return nil
+}
+
+func describeBackup(s services.BackupSummary) string {
+	return fmt.Sprintf("%d guilds, %d players, %d guild members, %d deaths and %d level ups", s.Guilds, s.Players, s.Members, s.Deaths, s.LevelUps)
+}
+
+func newAdminFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/storage/memory"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+type adminStore struct {
+	ports.Repository
+	configs     []domain.GuildConfig
+	prunedWorld string
+	prunedAge   time.Duration
+	imported    []domain.PlayerLevel
+	quotas      map[string]domain.Quota
+	premium     map[string]bool
+}
+
+func (s *adminStore) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	if s.quotas == nil {
+		s.quotas = make(map[string]domain.Quota)
+	}
+	s.quotas[guildID] = quota
+	return nil
+}
+
+func (s *adminStore) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	if s.premium == nil {
+		s.premium = make(map[string]bool)
+	}
+	s.premium[guildID] = premium
+	return nil
+}
+
+func (s *adminStore) GetAllGuildConfigs(ctx context.Context) ([]domain.GuildConfig, error) {
+	return s.configs, nil
+}
+
+func (s *adminStore) GetGuildConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+	for _, cfg := range s.configs {
+		if cfg.DiscordGuildID == guildID {
+			return &cfg, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *adminStore) DeleteOldPlayers(ctx context.Context, world string, maxAge time.Duration) (int64, error) {
+	s.prunedWorld, s.prunedAge = world, maxAge
+	return 7, nil
+}
+
+func (s *adminStore) GetPlayersLevels(ctx context.Context, world string) (map[string]int, error) {
+	return nil, nil
+}
+
+func (s *adminStore) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	s.imported = append(s.imported, levels...)
+	return nil
+}
+
+type adminNotifier struct {
+	sent     []string
+	messages []string
+	err      error
+}
+
+func (n *adminNotifier) SendTestNotification(guild domain.GuildConfig, message string) error {
+	n.sent = append(n.sent, guild.DiscordGuildID)
+	return n.err
+}
+
+func (n *adminNotifier) SendBroadcast(guild domain.GuildConfig, message string) error {
+	n.sent = append(n.sent, guild.DiscordGuildID)
+	n.messages = append(n.messages, message)
+	return n.err
+}
+
+func TestListGuildsCommand(t *testing.T) {
+	store := &adminStore{configs: []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica", Language: "en", TibiaGuilds: []string{"Red Rose", "Blue Moon"}},
+		{DiscordGuildID: "g2", MutedUntil: time.Now().Add(time.Hour)},
+	}}
+
+	var out bytes.Buffer
+	if err := listGuildsCommand(context.Background(), adminDeps{store: store}, nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", out.String())
+	}
+	if !strings.Contains(lines[1], "Antica") || !strings.Contains(lines[1], "Red Rose, Blue Moon") {
+		t.Errorf("unexpected first row: %q", lines[1])
+	}
+	if strings.HasSuffix(strings.TrimSpace(lines[2]), "-") {
+		t.Errorf("expected muted guild to show mute end, got %q", lines[2])
+	}
+}
+
+func TestPrunePlayersCommand(t *testing.T) {
+	t.Run("requires world", func(t *testing.T) {
+		err := prunePlayersCommand(context.Background(), adminDeps{store: &adminStore{}}, nil, &bytes.Buffer{})
+		if err == nil {
+			t.Error("expected error without -world")
+		}
+	})
+
+	t.Run("prunes world", func(t *testing.T) {
+		store := &adminStore{}
+		var out bytes.Buffer
+		err := prunePlayersCommand(context.Background(), adminDeps{store: store}, []string{"--world", "Antica", "--max-age", "2h"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if store.prunedWorld != "Antica" || store.prunedAge != 2*time.Hour {
+			t.Errorf("unexpected prune call: %s %v", store.prunedWorld, store.prunedAge)
+		}
+		if !strings.Contains(out.String(), "Deleted 7 players") {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	})
+}
+
+func TestSendTestCommand(t *testing.T) {
+	store := &adminStore{configs: []domain.GuildConfig{{DiscordGuildID: "g1"}}}
+
+	t.Run("unknown guild", func(t *testing.T) {
+		notifier := &adminNotifier{}
+		err := sendTestCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--guild", "missing"}, &bytes.Buffer{})
+		if err == nil || len(notifier.sent) != 0 {
+			t.Errorf("expected error and no send, got %v, %v", err, notifier.sent)
+		}
+	})
+
+	t.Run("sends", func(t *testing.T) {
+		notifier := &adminNotifier{}
+		err := sendTestCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--guild", "g1"}, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notifier.sent) != 1 || notifier.sent[0] != "g1" {
+			t.Errorf("expected send to g1, got %v", notifier.sent)
+		}
+	})
+
+	t.Run("send failure", func(t *testing.T) {
+		notifier := &adminNotifier{err: errors.New("missing access")}
+		err := sendTestCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--guild", "g1"}, &bytes.Buffer{})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestBroadcastCommand(t *testing.T) {
+	store := &adminStore{configs: []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica"},
+		{DiscordGuildID: "g2", World: "Antica", BroadcastOptOut: true},
+		{DiscordGuildID: "g3", World: "Secura"},
+	}}
+
+	t.Run("requires message", func(t *testing.T) {
+		err := broadcastCommand(context.Background(), adminDeps{store: store, notifier: &adminNotifier{}}, []string{"--message", "  "}, &bytes.Buffer{})
+		if err == nil {
+			t.Error("expected error without -message")
+		}
+	})
+
+	t.Run("rejects long message", func(t *testing.T) {
+		notifier := &adminNotifier{}
+		err := broadcastCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--message", strings.Repeat("a", 2000)}, &bytes.Buffer{})
+		if err == nil || len(notifier.sent) != 0 {
+			t.Errorf("expected error and no send, got %v, %v", err, notifier.sent)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		notifier := &adminNotifier{}
+		var out bytes.Buffer
+		err := broadcastCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--message", "Maintenance", "--dry-run"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notifier.sent) != 0 || !strings.Contains(out.String(), "Dry run: would send to 2 guilds, skipped 1 opted out") {
+			t.Errorf("unexpected dry run: %v, %q", notifier.sent, out.String())
+		}
+	})
+
+	t.Run("sends", func(t *testing.T) {
+		notifier := &adminNotifier{}
+		var out bytes.Buffer
+		err := broadcastCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--message", "Maintenance at 10:00 CET", "--interval", "0"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(notifier.sent, []string{"g1", "g3"}) {
+			t.Errorf("expected g1 and g3, got %v", notifier.sent)
+		}
+		if !strings.Contains(notifier.messages[0], "Maintenance at 10:00 CET") {
+			t.Errorf("unexpected message: %q", notifier.messages[0])
+		}
+		if !strings.Contains(out.String(), "Sent to 2 guilds") {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		notifier := &adminNotifier{err: errors.New("missing access")}
+		var out bytes.Buffer
+		err := broadcastCommand(context.Background(), adminDeps{store: store, notifier: notifier}, []string{"--message", "Maintenance", "--interval", "0"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), "Failed for 2 guilds: g1, g3") {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	})
+}
+
+func TestImportCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "levels.csv")
+	if err := os.WriteFile(path, []byte("name,level\nAlice,300\nBob,0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("requires file", func(t *testing.T) {
+		if err := importCommand(context.Background(), adminDeps{store: &adminStore{}}, nil, &bytes.Buffer{}); err == nil {
+			t.Error("expected error without -file")
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		store := &adminStore{}
+		var out bytes.Buffer
+		err := importCommand(context.Background(), adminDeps{store: store}, []string{"--file", path, "--world", "Antica", "--dry-run"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(store.imported) != 0 {
+			t.Errorf("expected nothing written, got %+v", store.imported)
+		}
+		if !strings.Contains(out.String(), `line 3: invalid level "0"`) || !strings.Contains(out.String(), "Dry run: would import 2 rows: 1 added") {
+			t.Errorf("unexpected output: %q", out.String())
+		}
+	})
+
+	t.Run("imports", func(t *testing.T) {
+		store := &adminStore{}
+		var out bytes.Buffer
+		err := importCommand(context.Background(), adminDeps{store: store}, []string{"--file", path, "--world", "Antica"}, &out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(store.imported) != 1 || store.imported[0] != (domain.PlayerLevel{Name: "Alice", Level: 300, World: "Antica"}) {
+			t.Errorf("unexpected import: %+v", store.imported)
+		}
+	})
+}
+
+func TestBackupAndRestoreCommands(t *testing.T) {
+	ctx := context.Background()
+	source := memory.NewStore()
+	source.SaveGuildWorld(ctx, "g1", "Antica")
+	source.AddGuildToConfig(ctx, "g1", "Red Rose")
+	source.SetGuildChannel(ctx, "g1", domain.ChannelDeaths, "chan-1")
+	source.SetGuildLanguage(ctx, "g1", "pl")
+	source.SetGuildTemplate(ctx, "g1", domain.ChannelLevels, "{player} is {level}")
+	source.SetGuildEmoji(ctx, "g1", domain.ChannelDeaths, "", true)
+	source.SetGuildPollInterval(ctx, "g1", 2*time.Minute)
+	source.SetGuildQuietHours(ctx, "g1", domain.QuietHours{Start: 120, End: 480, CatchUp: true})
+	source.SetDeathRoute(ctx, "g1", 500, "chan-2")
+	source.AddIgnoredPlayer(ctx, "g1", "Alt")
+	source.SetGuildChannel(ctx, "g1", domain.ChannelSkills, "chan-3")
+	source.AddWatchedPlayer(ctx, "g1", "Hero")
+	source.SetSkillThreshold(ctx, "g1", domain.SkillMagic, 100)
+	source.SetGuildMassDeathAlert(ctx, "g1", domain.MassDeathAlert{Deaths: 5, Window: 15 * time.Minute})
+	source.SetGuildLowLevelDeaths(ctx, "g1", false)
+	source.SetGuildQuota(ctx, "g1", domain.Quota{TibiaGuilds: 10})
+	source.AddGuildMembers(ctx, "Red Rose", []string{"Hero"})
+	source.UpsertPlayerLevel(ctx, "Hero", 301, "Antica")
+	source.RecordDeath(ctx, "Hero", "Antica", domain.Kill{
+		Time:    time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC),
+		Level:   300,
+		Reason:  "Killed at Level 300 by Villain",
+		Killers: []domain.Killer{{Name: "Villain", IsPlayer: true}},
+	})
+	reachedAt := time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC)
+	source.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Hero", World: "Antica", OldLevel: 300, NewLevel: 301, ReachedAt: reachedAt})
+
+	path := filepath.Join(t.TempDir(), "backup.json")
+	var out bytes.Buffer
+	if err := backupCommand(ctx, adminDeps{store: source}, []string{"--out", path}, &out); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 guilds, 1 players, 1 guild members, 1 deaths and 1 level ups") {
+		t.Errorf("unexpected backup output: %q", out.String())
+	}
+
+	target := memory.NewStore()
+	if err := restoreCommand(ctx, adminDeps{store: target}, []string{"--in", path}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	want, _ := source.GetGuildConfig(ctx, "g1")
+	got, _ := target.GetGuildConfig(ctx, "g1")
+	want.LastNotifiedAt, got.LastNotifiedAt = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected guild config\n%+v\ngot\n%+v", want, got)
+	}
+	if members, _ := target.GetGuildMemberNames(ctx, "Red Rose"); len(members) != 1 || members[0] != "Hero" {
+		t.Errorf("unexpected members: %v", members)
+	}
+	if levels, _ := target.GetPlayersLevels(ctx, "Antica"); levels["Hero"] != 301 {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+	deaths, _ := target.GetDeathsPage(ctx, "Antica", nil, time.Time{}, 0, 10)
+	if len(deaths) != 1 || deaths[0].Reason != "Killed at Level 300 by Villain" || len(deaths[0].Killers) != 1 {
+		t.Errorf("unexpected deaths: %+v", deaths)
+	}
+	levelUps, _ := target.GetLevelUpsPage(ctx, "Antica", nil, time.Time{}, 0, 10)
+	if len(levelUps) != 1 || !levelUps[0].ReachedAt.Equal(reachedAt) {
+		t.Errorf("expected the level up time to be kept, got %+v", levelUps)
+	}
+
+	if err := restoreCommand(ctx, adminDeps{store: target}, []string{"--in", path}, &bytes.Buffer{}); err == nil {
+		t.Error("expected restoring into configured storage to fail")
+	}
+}
+
+func TestSetQuotaCommand(t *testing.T) {
+	store := &adminStore{configs: []domain.GuildConfig{{DiscordGuildID: "g1"}}}
+
+	if err := setQuotaCommand(context.Background(), adminDeps{store: store}, []string{"--guild", "missing", "--tibia-guilds", "3"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for an unknown guild")
+	}
+
+	var out bytes.Buffer
+	err := setQuotaCommand(context.Background(), adminDeps{store: store}, []string{"--guild", "g1", "--tibia-guilds", "10", "--ignored-players", "-1", "--watched-players", "40"}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.quotas["g1"] != (domain.Quota{TibiaGuilds: 10, IgnoredPlayers: -1, WatchedPlayers: 40}) {
+		t.Errorf("unexpected quota: %+v", store.quotas)
+	}
+	if !strings.Contains(out.String(), "10 Tibia guilds, unlimited ignored players, 40 watched players") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestSetPremiumCommand(t *testing.T) {
+	store := &adminStore{configs: []domain.GuildConfig{{DiscordGuildID: "g1"}}}
+
+	if err := setPremiumCommand(context.Background(), adminDeps{store: store}, []string{"--guild", "missing"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for an unknown guild")
+	}
+
+	var out bytes.Buffer
+	if err := setPremiumCommand(context.Background(), adminDeps{store: store}, []string{"--guild", "g1"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !store.premium["g1"] {
+		t.Error("expected g1 to be premium")
+	}
+	if !strings.Contains(out.String(), "is now premium") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	if err := setPremiumCommand(context.Background(), adminDeps{store: store}, []string{"--guild", "g1", "--enabled=false"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.premium["g1"] {
+		t.Error("expected premium to be revoked")
+	}
+}
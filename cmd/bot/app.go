@@ -3,14 +3,20 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	discordadapter "death-level-tracker/internal/adapters/discord"
 	"death-level-tracker/internal/adapters/discord/commands"
+	"death-level-tracker/internal/adapters/storage/memory"
 	"death-level-tracker/internal/adapters/storage/postgres"
 	"death-level-tracker/internal/adapters/tibiadata"
 	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/adapters/tibiadata/replay"
 	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/ports"
 	"death-level-tracker/internal/core/services"
@@ -20,25 +26,55 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Cooldowns keep commands that query the database, Discord or TibiaData from
+// being spammed by a single user.
+const (
+	queryCommandCooldown = 10 * time.Second
+	syncCommandCooldown  = time.Minute
+)
+
+// cycleRunner schedules tracker cycles until its context is cancelled and
+// lets shutdown wait for the ones still running.
+type cycleRunner interface {
+	Start(ctx context.Context)
+	Drain(ctx context.Context) error
+}
+
+// retryQueue periodically retries failed notifications; shutdown runs one
+// last pass before storage closes.
+type retryQueue interface {
+	Start(ctx context.Context)
+	RetryDue(ctx context.Context) (services.RetryResult, error)
+}
+
 type App struct {
 	config         *config.Config
 	store          ports.Repository
 	discord        *discordgo.Session
-	trackerService *tracker.Service
+	trackerService cycleRunner
+	notifications  retryQueue
+	quietHours     *services.QuietHoursNotifier
+	houses         *services.HouseService
+	skills         *services.SkillService
+	rashid         *services.RashidService
+	boosted        *services.BoostedService
+	wars           *services.WarService
+	leader         ports.LeaderElector
 	router         *commands.Router
 
 	metricsServer *http.Server
+	debugServer   *http.Server
+	dumpSignals   chan os.Signal
 
 	trackerCtx    context.Context
 	trackerCancel context.CancelFunc
-
-	registeredCommands []*discordgo.ApplicationCommand
+	// workers tracks the background loops started by Run.
+	workers sync.WaitGroup
 }
 
 func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
-	store, err := postgres.NewPostgresStore(ctx, cfg.DatabaseURL)
+	store, leader, err := openStorage(ctx, cfg)
 	if err != nil {
-		slog.Error("Failed to connect to storage", "error", err)
 		return nil, err
 	}
 
@@ -47,28 +83,101 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, err
 	}
 
-	client := api.NewClient()
-	fetcher := tibiadata.NewAdapter(client, cfg)
-	notifier := discordadapter.NewAdapter(discord, cfg)
+	fetcher := newFetcher(cfg)
+
+	discordNotifier := discordadapter.NewAdapter(discord, cfg)
+	if cfg.NotifyDryRun {
+		slog.Warn("Notification dry run, messages are logged instead of sent to Discord")
+	}
+	notifier := services.NewNotificationQueue(store, discordNotifier, leader, cfg.NotificationMaxAge)
+	quietHours := services.NewQuietHoursNotifier(store, notifier)
+	capabilities := services.NewCapabilityService(premiumFromConfig(cfg))
 
 	trackerService := tracker.NewService(tracker.Dependencies{
-		Config:   cfg,
-		Storage:  store,
-		Fetcher:  fetcher,
-		Notifier: notifier,
+		Config:       cfg,
+		Storage:      store,
+		Fetcher:      fetcher,
+		Notifier:     quietHours,
+		Leader:       leader,
+		Capabilities: capabilities,
 	})
 
-	configService := services.NewConfigurationService(store)
-	botHandlers := &commands.BotHandler{Config: cfg, Service: configService}
+	houseService := services.NewHouseService(cfg, store, fetcher, quietHours, leader)
+	skillService := services.NewSkillService(cfg, store, fetcher, quietHours, leader)
+	rashidService := services.NewRashidService(store, quietHours, leader)
+	boostedService := services.NewBoostedService(store, fetcher, quietHours, leader)
+	warService := services.NewWarService(store, fetcher, quietHours, leader)
+	configService := services.NewConfigurationService(store, fetcher, limitsFromConfig(cfg), capabilities)
+	backfillService := services.NewBackfillService(store, fetcher, cfg.MinLevelTrack)
+	statsService := services.NewStatsService(store, fetcher)
+	exportService := services.NewExportService(store)
+	botHandlers := &commands.BotHandler{Config: cfg, Service: configService, Backfill: backfillService, Stats: statsService, Retries: notifier, Exports: exportService, Capabilities: capabilities, Pages: commands.NewPaginator()}
 
+	audited := commands.WithAudit(discordNotifier, cfg.DiscordChannelAudit)
+	queryCooldown := commands.WithCooldown(queryCommandCooldown)
+	syncCooldown := commands.WithCooldown(syncCommandCooldown)
 	router := commands.NewRouter()
-	router.Register("track-world", commands.WithAdmin(botHandlers.TrackWorld))
-	router.Register("stop-tracking", commands.WithAdmin(botHandlers.StopTracking))
-	router.Register("add-guild", commands.WithAdmin(botHandlers.AddGuild))
-	router.Register("unset-guild", commands.WithAdmin(botHandlers.UnsetGuild))
-	router.Register("list-guilds", commands.WithAdmin(botHandlers.ListGuilds))
+	botHandlers.Commands = router.Commands
+	router.Use(commands.WithRecovery, commands.WithLogging, commands.WithMetrics, commands.WithAdmin, commands.WithResponseVisibility(configService))
+	router.Register("track-world", botHandlers.TrackWorld, audited)
+	router.RegisterComponent(commands.SetupRoute, botHandlers.Setup)
+	router.RegisterComponent(commands.PageRoute, botHandlers.Pages.HandleButton)
+	router.Register("stop-tracking", botHandlers.StopTracking)
+	router.RegisterComponent(commands.StopTrackingConfirmRoute, botHandlers.StopTrackingConfirm, audited)
+	router.RegisterComponent(commands.StopTrackingCancelRoute, botHandlers.StopTrackingCancel)
+	router.Register("resume-tracking", botHandlers.ResumeTracking, audited)
+	router.Register("purge-data", botHandlers.PurgeData)
+	router.RegisterComponent(commands.PurgeDataConfirmRoute, botHandlers.PurgeDataConfirm, audited)
+	router.RegisterComponent(commands.PurgeDataCancelRoute, botHandlers.PurgeDataCancel)
+	router.Register("add-guild", botHandlers.AddGuild, audited)
+	router.Register("unset-guild", botHandlers.UnsetGuild, audited)
+	router.Register("ignore-player", botHandlers.IgnorePlayer, audited)
+	router.Register("unignore-player", botHandlers.UnignorePlayer, audited)
+	router.Register("list-guilds", botHandlers.ListGuilds)
+	router.Register("sync-guild", botHandlers.SyncGuild, syncCooldown)
+	router.Register("set-language", botHandlers.SetLanguage, audited)
+	router.Register("set-channel", botHandlers.SetChannel, audited)
+	router.Register("set-ping-role", botHandlers.SetPingRole, audited)
+	router.Register("set-poll-interval", botHandlers.SetPollInterval, audited)
+	router.Register("mute-tracker", botHandlers.MuteTracker, audited)
+	router.Register("set-low-level-deaths", botHandlers.SetLowLevelDeaths, audited)
+	router.Register("set-min-level", botHandlers.SetMinLevel, audited)
+	router.Register("set-timezone", botHandlers.SetTimezone, audited)
+	router.Register("set-template", botHandlers.SetTemplate, audited)
+	router.Register("set-emoji", botHandlers.SetEmoji, audited)
+	router.Register("route-deaths", botHandlers.RouteDeaths, audited)
+	router.Register("set-quiet-hours", botHandlers.SetQuietHours, audited)
+	router.Register("set-share-range", botHandlers.SetShareRange, audited)
+	router.Register("set-death-location", botHandlers.SetDeathLocation, audited)
+	router.Register("set-level-downs", botHandlers.SetLevelDowns, audited)
+	router.Register("set-response-visibility", botHandlers.SetResponseVisibility, audited)
+	router.Register("set-death-verbosity", botHandlers.SetDeathVerbosity, audited)
+	router.Register("set-announcements", botHandlers.SetAnnouncements, audited)
+	router.Register("track-skills", botHandlers.TrackSkills, audited)
+	router.Register("watch-player", botHandlers.WatchPlayer, audited)
+	router.Register("unwatch-player", botHandlers.UnwatchPlayer, audited)
+	router.Register("set-skill-threshold", botHandlers.SetSkillThreshold, audited)
+	router.Register("set-mass-death-alert", botHandlers.SetMassDeathAlert, audited)
+	router.Register("track-houses", botHandlers.TrackHouses, audited)
+	router.Register("deaths-today", botHandlers.DeathsToday, queryCooldown)
+	router.Register("top-killers", botHandlers.TopKillers, queryCooldown)
+	router.Register("activity", botHandlers.Activity, queryCooldown)
+	router.Register("compare", botHandlers.Compare, queryCooldown)
+	router.Register("rashid", botHandlers.Rashid, queryCooldown)
+	router.Register("pace", botHandlers.Pace, queryCooldown)
+	router.Register("frags", botHandlers.Frags, queryCooldown)
+	router.Register("war-score", botHandlers.WarScore, queryCooldown)
+	router.Register("export", botHandlers.Export, syncCooldown)
+	router.Register("retry-failed", botHandlers.RetryFailed, queryCooldown)
+	router.Register("check-permissions", botHandlers.CheckPermissions, queryCooldown)
+	router.Register("help", botHandlers.Help, queryCooldown)
+	router.Register("track-status", botHandlers.TrackStatus, queryCooldown)
 
+	guildJoin := commands.NewGuildJoinHandler(cfg, configService, leader)
 	discord.AddHandler(commands.ReadyHandler)
+	discord.AddHandler(guildJoin.Ready)
+	discord.AddHandler(guildJoin.GuildCreate)
+	discord.AddHandler(guildJoin.GuildDelete)
 	discord.AddHandler(router.HandleFunc())
 
 	return &App{
@@ -76,36 +185,144 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		store:          store,
 		discord:        discord,
 		trackerService: trackerService,
+		notifications:  notifier,
+		quietHours:     quietHours,
+		houses:         houseService,
+		skills:         skillService,
+		rashid:         rashidService,
+		boosted:        boostedService,
+		wars:           warService,
+		leader:         leader,
 		router:         router,
 	}, nil
 }
 
+func premiumFromConfig(cfg *config.Config) services.Premium {
+	return services.Premium{
+		Gating:              cfg.PremiumGating,
+		Guilds:              cfg.PremiumGuilds,
+		FreeMinPollInterval: cfg.FreeMinPollInterval,
+		DefaultPollInterval: cfg.TrackerInterval,
+		WorldPollIntervals:  cfg.WorldPollIntervals,
+		TibiaGuilds:         cfg.PremiumMaxTibiaGuilds,
+	}
+}
+
+func limitsFromConfig(cfg *config.Config) services.Limits {
+	return services.Limits{
+		TibiaGuilds:    cfg.MaxTibiaGuilds,
+		IgnoredPlayers: cfg.MaxIgnoredPlayers,
+		WatchedPlayers: cfg.MaxWatchedPlayers,
+		Worlds:         cfg.MaxWorlds,
+	}
+}
+
+// openStorage connects the storage selected by STORAGE_DRIVER. Leader election
+// relies on Postgres advisory locks, so with the memory driver the bot always
+// runs as the only replica.
+func openStorage(ctx context.Context, cfg *config.Config) (ports.Repository, ports.LeaderElector, error) {
+	if cfg.StorageDriver == config.StorageDriverMemory {
+		slog.Warn("Using in-memory storage, all data is lost on restart")
+		return memory.NewStore(), nil, nil
+	}
+
+	if cfg.MigrateOnStart {
+		if err := applyMigrations(ctx, cfg.DatabaseURL); err != nil {
+			slog.Error("Failed to apply database migrations", "error", err)
+			return nil, nil, err
+		}
+	}
+
+	store, err := postgres.NewPostgresStore(ctx, cfg)
+	if err != nil {
+		slog.Error("Failed to connect to storage", "error", err)
+		return nil, nil, err
+	}
+
+	var leader ports.LeaderElector
+	if cfg.LeaderElection {
+		leader = store.NewLeader(postgres.TrackerLockKey)
+	}
+	return store, leader, nil
+}
+
+// newFetcher builds the Tibia fetcher, recording its responses or replaying
+// earlier recordings when REPLAY_MODE asks for it.
+func newFetcher(cfg *config.Config) ports.TibiaFetcher {
+	switch cfg.ReplayMode {
+	case config.ReplayModeReplay:
+		slog.Warn("Replaying recorded Tibia responses, no live data is fetched", "dir", cfg.ReplayDir)
+		return replay.NewReplayer(cfg.ReplayDir)
+	case config.ReplayModeRecord:
+		slog.Info("Recording Tibia responses", "dir", cfg.ReplayDir)
+		return replay.NewRecorder(tibiadata.NewAdapter(api.NewClient(cfg), cfg), cfg.ReplayDir)
+	}
+	return tibiadata.NewAdapter(api.NewClient(cfg), cfg)
+}
+
 func (a *App) Run() error {
 	a.startMetricsServer()
+	a.startDebugServer()
 
 	if err := a.discord.Open(); err != nil {
 		slog.Error("Failed to open discord session", "error", err)
 		return err
 	}
 
-	cmds := commands.GetApplicationCommands()
-	commands.CleanupCommands(a.discord, a.registeredCommands, a.discord.State.User.ID, a.config.DiscordGuildID)
-	a.registeredCommands = commands.RegisterCommands(a.discord, cmds, a.discord.State.User.ID, a.config.DiscordGuildID)
+	commands.SyncCommands(a.discord, commands.GetApplicationCommands(), a.discord.State.User.ID, a.config.DiscordGuildID)
 
 	slog.Info("Players Tracker is online!")
 
+	a.startBackgroundWork()
+	return nil
+}
+
+// startBackgroundWork starts the tracker and the other background loops. It
+// needs no gateway connection, so tests can run the bot without opening one.
+func (a *App) startBackgroundWork() {
 	a.trackerCtx, a.trackerCancel = context.WithCancel(context.Background())
-	go a.trackerService.Start(a.trackerCtx)
+	a.startWorker(a.trackerService.Start)
+	a.startWorker(a.notifications.Start)
+	a.startWorker(a.quietHours.Start)
+	a.startWorker(a.houses.Start)
+	a.startWorker(a.skills.Start)
+	a.startWorker(a.wars.Start)
+	if a.config.RashidDailyPost {
+		a.startWorker(a.rashid.Start)
+	}
+	if a.config.BoostedDailyPost {
+		a.startWorker(a.boosted.Start)
+	}
+}
 
-	return nil
+func (a *App) startWorker(run func(ctx context.Context)) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		run(a.trackerCtx)
+	}()
 }
 
+// Shutdown stops scheduling new work, waits until ctx's deadline for tracker
+// cycles to deliver their notifications, retries notifications that are due
+// and only then closes Discord and storage. Work still running at the
+// deadline is cancelled and reported in the returned error.
 func (a *App) Shutdown(ctx context.Context) error {
 	slog.Info("Shutting down application...")
+	var errs []error
 
 	if a.trackerCancel != nil {
 		a.trackerCancel()
 	}
+	if err := waitGroup(ctx, &a.workers); err != nil {
+		errs = append(errs, fmt.Errorf("stop background loops: %w", err))
+	}
+	if a.trackerService != nil {
+		if err := a.trackerService.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("drain tracker cycles: %w", err))
+		}
+	}
+	a.flushNotifications(ctx)
 
 	if a.metricsServer != nil {
 		if err := a.metricsServer.Shutdown(ctx); err != nil {
@@ -113,18 +330,62 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if a.debugServer != nil {
+		if err := a.debugServer.Shutdown(ctx); err != nil {
+			slog.Error("Failed to shutdown debug server", "error", err)
+		}
+	}
+	a.stopDebugServer()
+
 	if a.discord != nil {
 		if err := a.discord.Close(); err != nil {
 			slog.Error("Failed to close discord session", "error", err)
 		}
 	}
 
+	if a.leader != nil {
+		a.leader.Release(ctx)
+	}
+
 	if a.store != nil {
 		a.store.Close()
 	}
 
 	slog.Info("Shutdown complete")
-	return nil
+	return errors.Join(errs...)
+}
+
+// flushNotifications retries due notifications while Discord and storage are
+// still open. Only the leader retries, as during normal operation.
+func (a *App) flushNotifications(ctx context.Context) {
+	if a.notifications == nil || ctx.Err() != nil {
+		return
+	}
+	if a.leader != nil && !a.leader.IsLeader(ctx) {
+		return
+	}
+	result, err := a.notifications.RetryDue(ctx)
+	if err != nil {
+		slog.Error("Failed to flush notifications", "error", err)
+		return
+	}
+	slog.Info("Flushed notifications", "sent", result.Sent, "failed", result.Failed)
+}
+
+// waitGroup waits for wg until ctx is done.
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (a *App) startMetricsServer() {
@@ -2,21 +2,136 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
 	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/ports"
+	"death-level-tracker/internal/core/services"
 )
 
 type mockStore struct {
 	ports.Repository
 	closed bool
+	events *eventLog
 }
 
 func (m *mockStore) Close() {
 	m.closed = true
+	m.events.add("store closed")
+}
+
+// eventLog records the order shutdown steps happen in.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.events)
+}
+
+type mockTracker struct {
+	events *eventLog
+	// cycle is how long the cycle in flight at shutdown keeps running.
+	cycle time.Duration
+}
+
+func (m *mockTracker) Start(ctx context.Context) {
+	<-ctx.Done()
+	m.events.add("tracker stopped")
+}
+
+func (m *mockTracker) Drain(ctx context.Context) error {
+	select {
+	case <-time.After(m.cycle):
+		m.events.add("tracker drained")
+		return nil
+	case <-ctx.Done():
+		m.events.add("tracker aborted")
+		return ctx.Err()
+	}
+}
+
+type mockRetryQueue struct {
+	events *eventLog
+}
+
+func (m *mockRetryQueue) Start(ctx context.Context) {
+	<-ctx.Done()
+	m.events.add("retries stopped")
+}
+
+func (m *mockRetryQueue) RetryDue(ctx context.Context) (services.RetryResult, error) {
+	m.events.add("notifications flushed")
+	return services.RetryResult{}, nil
+}
+
+func newShutdownTestApp(events *eventLog, cycle time.Duration) *App {
+	app := &App{
+		config:         &config.Config{},
+		store:          &mockStore{events: events},
+		trackerService: &mockTracker{events: events, cycle: cycle},
+		notifications:  &mockRetryQueue{events: events},
+	}
+	app.trackerCtx, app.trackerCancel = context.WithCancel(context.Background())
+	app.startWorker(app.trackerService.Start)
+	app.startWorker(app.notifications.Start)
+	return app
+}
+
+func TestApp_Shutdown_Order(t *testing.T) {
+	events := &eventLog{}
+	app := newShutdownTestApp(events, 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	got := events.list()
+	// The loops stop concurrently, so only their position is fixed.
+	slices.Sort(got[:2])
+	want := []string{"retries stopped", "tracker stopped", "tracker drained", "notifications flushed", "store closed"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected shutdown order %v, got %v", want, got)
+	}
+}
+
+func TestApp_Shutdown_DrainDeadline(t *testing.T) {
+	events := &eventLog{}
+	app := newShutdownTestApp(events, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := app.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	got := events.list()
+	if slices.Contains(got, "notifications flushed") {
+		t.Error("expected no flush after the deadline passed")
+	}
+	if !slices.Contains(got, "tracker aborted") || got[len(got)-1] != "store closed" {
+		t.Errorf("expected cycles aborted and the store still closed last, got %v", got)
+	}
 }
 
 func TestApp_Shutdown(t *testing.T) {
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"time"
+)
+
+// startDebugServer exposes net/http/pprof on DEBUG_ADDR and enables profile
+// dumps on dumpSignal. It does nothing when DEBUG_ADDR is empty.
+func (a *App) startDebugServer() {
+	if a.config.DebugAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	a.debugServer = &http.Server{
+		Addr:    a.config.DebugAddr,
+		Handler: mux,
+	}
+
+	go func() {
+		slog.Info("Starting debug server", "addr", a.config.DebugAddr)
+		if err := a.debugServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Debug server failed", "error", err)
+		}
+	}()
+
+	a.watchDumpSignal()
+}
+
+func (a *App) watchDumpSignal() {
+	if dumpSignal == nil {
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, dumpSignal)
+	a.dumpSignals = sigs
+
+	go func() {
+		for range sigs {
+			files, err := dumpProfiles(a.config.DebugDumpDir, time.Now())
+			if err != nil {
+				slog.Error("Failed to dump profiles", "error", err)
+				continue
+			}
+			slog.Info("Dumped runtime profiles", "files", files)
+		}
+	}()
+}
+
+// stopDebugServer ends the dump signal watcher by closing its channel, so it
+// must run only once.
+func (a *App) stopDebugServer() {
+	if a.dumpSignals != nil {
+		signal.Stop(a.dumpSignals)
+		close(a.dumpSignals)
+	}
+}
+
+// dumpProfiles writes goroutine and heap profiles into dir and returns the
+// created file paths.
+func dumpProfiles(dir string, now time.Time) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create dump dir: %w", err)
+	}
+
+	runtime.GC()
+
+	stamp := now.Format("20060102-150405")
+	profiles := []struct {
+		name  string
+		debug int
+	}{
+		{"goroutine", 2},
+		{"heap", 0},
+	}
+
+	var files []string
+	for _, p := range profiles {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.pprof", p.name, stamp))
+		if err := writeProfile(path, p.name, p.debug); err != nil {
+			return files, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func writeProfile(path, name string, debug int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := rpprof.Lookup(name).WriteTo(f, debug); err != nil {
+		return fmt.Errorf("write %s profile: %w", name, err)
+	}
+	return nil
+}
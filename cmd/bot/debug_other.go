@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// dumpSignal is nil where SIGUSR1 is unavailable, disabling signal dumps.
+var dumpSignal os.Signal
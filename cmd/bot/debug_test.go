@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+)
+
+func TestStartDebugServer_Disabled(t *testing.T) {
+	app := &App{config: &config.Config{}}
+
+	app.startDebugServer()
+
+	if app.debugServer != nil {
+		t.Error("Debug server should not start without DEBUG_ADDR")
+	}
+	if app.dumpSignals != nil {
+		t.Error("Dump signal should not be watched without DEBUG_ADDR")
+	}
+}
+
+func TestStartDebugServer_Enabled(t *testing.T) {
+	app := &App{config: &config.Config{DebugAddr: "127.0.0.1:0", DebugDumpDir: t.TempDir()}}
+
+	app.startDebugServer()
+	defer app.stopDebugServer()
+
+	if app.debugServer == nil {
+		t.Fatal("Debug server not initialized")
+	}
+	_ = app.debugServer.Close()
+}
+
+func TestDumpProfiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 12, 13, 10, 30, 0, 0, time.UTC)
+
+	files, err := dumpProfiles(dir, now)
+	if err != nil {
+		t.Fatalf("dumpProfiles failed: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+	for _, f := range files {
+		if !strings.Contains(f, "20241213-103000") {
+			t.Errorf("Expected timestamped file name, got %s", f)
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			t.Errorf("Expected %s to exist: %v", f, err)
+			continue
+		}
+		if info.Size() == 0 {
+			t.Errorf("Expected %s to be non-empty", f)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/testharness"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const e2eTimeout = 5 * time.Second
+
+// startE2EApp boots the bot on in-memory storage against the fakes without
+// connecting to the Discord gateway.
+func startE2EApp(t *testing.T, tibia *testharness.TibiaData, discord *testharness.Discord) *App {
+	t.Helper()
+	app, err := NewApp(context.Background(), testharness.Config(tibia.URL()))
+	if err != nil {
+		t.Fatalf("NewApp: %v", err)
+	}
+	discord.Attach(app.discord)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), e2eTimeout)
+		defer cancel()
+		if err := app.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	})
+	return app
+}
+
+// runCommand sends a slash command from a guild administrator through the
+// app's router, as the gateway would.
+func runCommand(app *App, guildID, name string, options map[string]string) {
+	var opts []*discordgo.ApplicationCommandInteractionDataOption
+	for key, value := range options {
+		opts = append(opts, &discordgo.ApplicationCommandInteractionDataOption{
+			Name: key, Type: discordgo.ApplicationCommandOptionString, Value: value,
+		})
+	}
+	app.router.HandleFunc()(app.discord, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:             "interaction-" + name,
+			Token:          "token",
+			Type:           discordgo.InteractionApplicationCommand,
+			GuildID:        guildID,
+			AppPermissions: discordgo.PermissionAdministrator,
+			Member: &discordgo.Member{
+				User:        &discordgo.User{ID: "admin-1"},
+				Permissions: discordgo.PermissionAdministrator,
+			},
+			Data: discordgo.ApplicationCommandInteractionData{Name: name, Options: opts},
+		},
+	})
+}
+
+func TestE2E_TrackWorldAnnouncesDeathsAndLevelUps(t *testing.T) {
+	tibia := testharness.NewTibiaData(t)
+	discord := testharness.NewDiscord(t)
+	app := startE2EApp(t, tibia, discord)
+
+	runCommand(app, "guild-1", "track-world", map[string]string{"name": "antica"})
+
+	responses := discord.Responses()
+	if len(responses) != 1 || responses[0].Data == nil {
+		t.Fatalf("expected one response to /track-world, got %+v", responses)
+	}
+	if !strings.HasPrefix(responses[0].Data.Content, "Tracking world **Antica** configured!") {
+		t.Fatalf("unexpected /track-world response %q", responses[0].Data.Content)
+	}
+	deathChannel := discord.ChannelID("guild-1", "death-tracker")
+	levelChannel := discord.ChannelID("guild-1", "level-tracker")
+	if deathChannel == "" || levelChannel == "" {
+		t.Fatal("expected /track-world to create the tracker channels")
+	}
+
+	if err := app.store.UpsertPlayerLevel(context.Background(), "Bob", 599, "Antica"); err != nil {
+		t.Fatalf("seed level: %v", err)
+	}
+	tibia.SetOnline("Antica",
+		api.OnlinePlayer{Name: "Alice", Level: 250, Vocation: "Elite Knight"},
+		api.OnlinePlayer{Name: "Bob", Level: 600, Vocation: "Master Sorcerer"},
+	)
+	tibia.SetCharacter(api.CharacterInfo{Name: "Alice", Level: 250, Vocation: "Elite Knight", World: "Antica"},
+		api.Death{
+			Time:    time.Now().UTC(),
+			Level:   251,
+			Reason:  "Killed at Level 251 by a dragon lord.",
+			Killers: []api.Killer{{Name: "dragon lord"}},
+		},
+	)
+	tibia.SetCharacter(api.CharacterInfo{Name: "Bob", Level: 600, Vocation: "Master Sorcerer", World: "Antica"})
+
+	app.startBackgroundWork()
+
+	discord.WaitForMessage(t, deathChannel, "dragon lord", e2eTimeout)
+	discord.WaitForMessage(t, levelChannel, "600", e2eTimeout)
+
+	if got := discord.Messages(levelChannel); len(got) != 1 {
+		t.Errorf("expected only Bob's level up, got %+v", got)
+	}
+}
+
+func TestE2E_UntrackedWorldStaysSilent(t *testing.T) {
+	tibia := testharness.NewTibiaData(t)
+	discord := testharness.NewDiscord(t)
+	deathChannel := discord.AddChannel("guild-1", "death-tracker")
+	app := startE2EApp(t, tibia, discord)
+
+	tibia.SetOnline("Antica", api.OnlinePlayer{Name: "Alice", Level: 250})
+	tibia.SetCharacter(api.CharacterInfo{Name: "Alice", Level: 250, World: "Antica"},
+		api.Death{Time: time.Now().UTC(), Level: 251, Reason: "Killed at Level 251 by a dragon lord."},
+	)
+
+	app.startBackgroundWork()
+	time.Sleep(200 * time.Millisecond)
+
+	if got := discord.Messages(deathChannel); len(got) != 0 {
+		t.Errorf("expected no messages without a tracked world, got %+v", got)
+	}
+}
@@ -3,9 +3,13 @@ package main
 import (
 	"log/slog"
 	"os"
+
+	"death-level-tracker/internal/logging"
 )
 
-func InitLogger() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+// InitLogger installs the default logger. format is "json" (default) or
+// "text"; records also carry attributes attached via logging.WithAttrs.
+func InitLogger(format string) {
+	logger := slog.New(logging.NewHandler(os.Stdout, format))
 	slog.SetDefault(logger)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -10,7 +11,16 @@ import (
 )
 
 func main() {
-	InitLogger()
+	InitLogger(os.Getenv("LOG_FORMAT"))
+
+	if len(os.Args) > 1 {
+		if os.Args[1] == "migrate" {
+			os.Exit(migrateMain(os.Args[2:]))
+		}
+		if cmd, ok := adminCommands[os.Args[1]]; ok {
+			os.Exit(adminMain(cmd, os.Args[2:]))
+		}
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -41,3 +51,23 @@ func main() {
 
 	WaitForShutdown()
 }
+
+func migrateMain(args []string) int {
+	opts, err := parseMigrateArgs(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "usage: death-level-tracker migrate [up|down|status] [-dry-run] [-steps N]:", err)
+		return 2
+	}
+
+	dbURL, err := config.LoadDatabaseURL()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		return 1
+	}
+
+	if err := runMigrate(context.Background(), dbURL, opts, os.Stdout); err != nil {
+		slog.Error("Migration failed", "error", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"death-level-tracker/internal/adapters/storage/postgres"
+	"death-level-tracker/sql/migrations"
+)
+
+type migrateOptions struct {
+	action string
+	dryRun bool
+	steps  int
+}
+
+// parseMigrateArgs accepts "[up|down|status] [-dry-run] [-steps N]".
+func parseMigrateArgs(args []string) (migrateOptions, error) {
+	opts := migrateOptions{action: "up"}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		opts.action, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the plan without changing the database")
+	flags.IntVar(&opts.steps, "steps", 1, "number of migrations to roll back")
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	switch opts.action {
+	case "up", "down", "status":
+	default:
+		return opts, fmt.Errorf("unknown migrate action %q (want up, down or status)", opts.action)
+	}
+	if opts.steps < 1 {
+		return opts, errors.New("-steps must be at least 1")
+	}
+	return opts, nil
+}
+
+func newMigrator(ctx context.Context, databaseURL string) (*postgres.Migrator, error) {
+	all, err := postgres.LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return postgres.NewMigrator(ctx, databaseURL, all)
+}
+
+// applyMigrations brings the schema up to date before the bot starts.
+func applyMigrations(ctx context.Context, databaseURL string) error {
+	migrator, err := newMigrator(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close(ctx)
+
+	_, err = migrator.Up(ctx, false)
+	return err
+}
+
+// runMigrate implements the "migrate" subcommand.
+func runMigrate(ctx context.Context, databaseURL string, opts migrateOptions, out io.Writer) error {
+	migrator, err := newMigrator(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close(ctx)
+
+	switch opts.action {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04")
+			}
+			if s.Modified {
+				state += " (modified)"
+			}
+			fmt.Fprintf(out, "%s  %-32s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	case "down":
+		plan, err := migrator.Down(ctx, opts.steps, opts.dryRun)
+		printPlan(out, "Rolled back", "Would roll back", plan, opts.dryRun)
+		return err
+	default:
+		plan, err := migrator.Up(ctx, opts.dryRun)
+		printPlan(out, "Applied", "Would apply", plan, opts.dryRun)
+		return err
+	}
+}
+
+func printPlan(out io.Writer, done, planned string, plan []postgres.Migration, dryRun bool) {
+	if len(plan) == 0 {
+		fmt.Fprintln(out, "Nothing to do")
+		return
+	}
+	verb := done
+	if dryRun {
+		verb = planned
+	}
+	for _, m := range plan {
+		fmt.Fprintf(out, "%s %s_%s\n", verb, m.Version, m.Name)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestParseMigrateArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    migrateOptions
+		wantErr bool
+	}{
+		{"default up", nil, migrateOptions{action: "up", steps: 1}, false},
+		{"dry run up", []string{"up", "-dry-run"}, migrateOptions{action: "up", dryRun: true, steps: 1}, false},
+		{"flags without action", []string{"-dry-run"}, migrateOptions{action: "up", dryRun: true, steps: 1}, false},
+		{"down with steps", []string{"down", "-steps", "3"}, migrateOptions{action: "down", steps: 3}, false},
+		{"status", []string{"status"}, migrateOptions{action: "status", steps: 1}, false},
+		{"unknown action", []string{"sideways"}, migrateOptions{}, true},
+		{"zero steps", []string{"down", "-steps", "0"}, migrateOptions{}, true},
+		{"unknown flag", []string{"up", "-force"}, migrateOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMigrateArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
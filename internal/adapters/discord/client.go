@@ -1,9 +1,11 @@
 package discord
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"death-level-tracker/internal/adapters/discord/formatting"
 	"death-level-tracker/internal/adapters/metrics"
@@ -16,12 +18,18 @@ import (
 type DiscordSession interface {
 	GuildChannels(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error)
 	ChannelMessageSend(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	MessageReactionAdd(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error
 }
 
 type Adapter struct {
 	session DiscordSession
 	config  *config.Config
 	cache   *channelCache
+	// levelThrottle limits level up messages per player and guild; deaths
+	// are never throttled.
+	levelThrottle *playerThrottle
 }
 
 func NewAdapter(session DiscordSession, cfg *config.Config) *Adapter {
@@ -29,18 +37,249 @@ func NewAdapter(session DiscordSession, cfg *config.Config) *Adapter {
 		session: session,
 		config:  cfg,
 		cache:   newChannelCache(),
+
+		levelThrottle: newPlayerThrottle(cfg.LevelUpCooldown),
+	}
+}
+
+func (a *Adapter) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+	content := a.levelUpContent(guild, levelUp)
+
+	if a.levelThrottle.Throttled(guild.DiscordGuildID, levelUp.PlayerName) {
+		slog.Debug("Level up notification throttled", "guild_id", guild.DiscordGuildID, "name", levelUp.PlayerName, "cooldown", a.config.LevelUpCooldown)
+		metrics.DiscordMessagesSent.WithLabelValues("level", "throttled").Inc()
+		return nil
+	}
+	var err error
+	if guild.LevelReaction {
+		reaction := reactionFor(guild.LevelEmoji, formatting.DefaultLevelReaction)
+		err = a.sendComplexNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, &discordgo.MessageSend{Content: content}, reaction)
+	} else {
+		err = a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, content)
+	}
+	if err != nil {
+		return err
+	}
+	a.levelThrottle.Record(guild.DiscordGuildID, levelUp.PlayerName)
+	return nil
+}
+
+// SendLevelDownNotification posts a level drop to the level channel. Unlike
+// level ups it gets no emoji, reaction or cooldown.
+func (a *Adapter) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	catalog := formatting.CatalogFor(guild.Language)
+	name := catalog.PlayerLabel(levelDown.PlayerName, levelDown.Vocation, levelDown.GuildName, levelDown.GuildRank, levelDown.WorldPvPType)
+	content := catalog.LevelDown(name, levelDown.OldLevel, levelDown.NewLevel)
+	if guild.LevelDownTemplate != "" {
+		detectedAt := levelDown.DetectedAt
+		if detectedAt.IsZero() {
+			detectedAt = time.Now()
+		}
+		timeStr := formatting.EventTime(detectedAt, guild.Location(), a.config.DiscordTimestamps)
+		content = formatting.RenderTemplate(guild.LevelDownTemplate, formatting.LevelUpTemplateValues(name, timeStr, levelDown.OldLevel, levelDown.NewLevel))
+	}
+	return a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, content)
+}
+
+func (a *Adapter) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+	content := a.deathContent(guild, player, kill)
+
+	var reaction string
+	if guild.DeathReaction {
+		reaction = reactionFor(guild.DeathEmoji, formatting.DefaultDeathReaction)
+	}
+	channel := guild.DeathChannelFor(kill.Level)
+	ping := shouldPingRole(guild, kill)
+	if !ping && reaction == "" {
+		return a.sendNotification(guild.DiscordGuildID, channel, a.config.DiscordChannelDeath, content)
+	}
+
+	msg := &discordgo.MessageSend{Content: content}
+	if ping {
+		msg.Content = formatting.MsgRoleMention(guild.PingRoleID) + " " + content
+		msg.AllowedMentions = &discordgo.MessageAllowedMentions{
+			Roles: []string{guild.PingRoleID},
+		}
+	}
+	return a.sendComplexNotification(guild.DiscordGuildID, channel, a.config.DiscordChannelDeath, msg, reaction)
+}
+
+func (a *Adapter) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	content := formatting.CatalogFor(guild.Language).DeathStreak(playerName, deaths)
+	return a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, content)
+}
+
+// SendMassDeathNotification posts the possible war or raid alert to the
+// guild's death channel.
+func (a *Adapter) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	content := formatting.CatalogFor(guild.Language).MassDeath(massDeath)
+	return a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, content)
+}
+
+// SendMembershipNotification posts one line per character that joined or left
+// the Tibia guild to the level channel.
+func (a *Adapter) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	catalog := formatting.CatalogFor(guild.Language)
+	var lines []string
+	for _, name := range change.Joined {
+		lines = append(lines, catalog.GuildJoined(name, change.GuildName))
+	}
+	for _, name := range change.Left {
+		lines = append(lines, catalog.GuildLeft(name, change.GuildName))
 	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, strings.Join(lines, "\n"))
+}
+
+// SendGuildWarNotification posts a war declaration or end to the guild's
+// death channel, where the war's deaths are announced.
+func (a *Adapter) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	content := formatting.CatalogFor(guild.Language).GuildWar(war)
+	return a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, content)
+}
+
+// SendHouseAuctionNotification posts a started or ended auction to the guild's
+// house channel. Guilds without one have not opted in and get nothing.
+func (a *Adapter) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	if guild.HouseChannelID == "" {
+		return nil
+	}
+	catalog := formatting.CatalogFor(guild.Language)
+	content := catalog.HouseAuction(auction)
+	if ended {
+		content = catalog.HouseAuctionEnded(auction)
+	}
+	return a.sendToChannel(guild.DiscordGuildID, guild.HouseChannelID, "house", content)
+}
+
+// SendSkillAdvanceNotification posts a watched character's skill advance to
+// the guild's skill channel. Guilds without one have not opted in.
+func (a *Adapter) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	if guild.SkillChannelID == "" {
+		return nil
+	}
+	content := formatting.CatalogFor(guild.Language).SkillAdvance(advance)
+	return a.sendToChannel(guild.DiscordGuildID, guild.SkillChannelID, "skill", content)
+}
+
+// SendRashidNotification posts Rashid's city to the guild's misc channel, or
+// the level channel when none is set.
+func (a *Adapter) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	content := formatting.CatalogFor(guild.Language).Rashid(city)
+	if guild.MiscChannelID != "" {
+		return a.sendToChannel(guild.DiscordGuildID, guild.MiscChannelID, "misc", content)
+	}
+	return a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, content)
+}
+
+// SendBoostedNotification posts today's boosted creature and boss as two
+// embeds with their pictures as thumbnails, to the guild's misc channel or the
+// level channel when none is set.
+func (a *Adapter) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	catalog := formatting.CatalogFor(guild.Language)
+	msg := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			boostedEmbed(catalog.BoostedCreature(), boosted.Creature),
+			boostedEmbed(catalog.BoostedBoss(), boosted.Boss),
+		},
+	}
+	channelID := guild.MiscChannelID
+	if channelID == "" {
+		channelID = guild.LevelChannelID
+	}
+	return a.sendComplexNotification(guild.DiscordGuildID, channelID, a.config.DiscordChannelLevel, msg, "")
 }
 
-func (a *Adapter) SendLevelUpNotification(guildID string, levelUp domain.LevelUp) error {
-	content := formatting.MsgLevelUp(levelUp.PlayerName, levelUp.OldLevel, levelUp.NewLevel)
-	return a.SendGenericMessage(guildID, a.config.DiscordChannelLevel, content)
+// SendCatchUpNotification posts the deaths held back during quiet hours to the
+// death channel and the level ups to the level channel, one message each. Each
+// line reads as the live notification would have.
+func (a *Adapter) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+	catalog := formatting.CatalogFor(guild.Language)
+	var errs []error
+	if len(catchUp.Deaths) > 0 {
+		lines := make([]string, len(catchUp.Deaths))
+		for i, d := range catchUp.Deaths {
+			lines[i] = a.deathContent(guild, d.Player, d.Kill)
+		}
+		errs = append(errs, a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, catalog.CatchUp(lines)))
+	}
+	if len(catchUp.LevelUps) > 0 {
+		lines := make([]string, len(catchUp.LevelUps))
+		for i, l := range catchUp.LevelUps {
+			lines[i] = a.levelUpContent(guild, l)
+		}
+		errs = append(errs, a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, catalog.CatchUp(lines)))
+	}
+	return errors.Join(errs...)
 }
 
-func (a *Adapter) SendDeathNotification(guildID string, playerName string, kill domain.Kill) error {
-	timeStr := kill.Time.Local().Format(formatting.DcLongTimeFormat)
-	content := formatting.MsgDeath(playerName, timeStr, kill.Reason)
-	return a.SendGenericMessage(guildID, a.config.DiscordChannelDeath, content)
+// levelUpContent formats a level up with the guild's template and emoji.
+func (a *Adapter) levelUpContent(guild domain.GuildConfig, levelUp domain.LevelUp) string {
+	catalog := formatting.CatalogFor(guild.Language)
+	name := catalog.PlayerLabel(levelUp.PlayerName, levelUp.Vocation, levelUp.GuildName, levelUp.GuildRank, levelUp.WorldPvPType)
+	var shareRange string
+	if guild.ShareRange {
+		shareRange = " " + catalog.ShareRange(domain.PartyShareRange(levelUp.NewLevel))
+	}
+	content := catalog.LevelUp(name, levelUp.OldLevel, levelUp.NewLevel) + shareRange
+	if guild.LevelTemplate != "" {
+		reachedAt := levelUp.ReachedAt
+		if reachedAt.IsZero() {
+			reachedAt = time.Now()
+		}
+		timeStr := formatting.EventTime(reachedAt, guild.Location(), a.config.DiscordTimestamps)
+		content = formatting.RenderTemplate(guild.LevelTemplate, formatting.LevelUpTemplateValues(name, timeStr, levelUp.OldLevel, levelUp.NewLevel)) + shareRange
+	} else if a.config.DiscordTimestamps && !levelUp.ReachedAt.IsZero() {
+		content += " - " + formatting.RelativeTime(levelUp.ReachedAt)
+	}
+	if guild.LevelEmoji != "" {
+		content = guild.LevelEmoji + " " + content
+	}
+	return content
+}
+
+// deathContent formats a death with the guild's verbosity, template and emoji.
+func (a *Adapter) deathContent(guild domain.GuildConfig, player domain.Player, kill domain.Kill) string {
+	content := formatting.DeathFormatterFor(guild.DeathVerbosity).Format(formatting.CatalogFor(guild.Language), formatting.DeathMessage{
+		Player:   player,
+		Kill:     kill,
+		Time:     formatting.EventTime(kill.Time, guild.Location(), a.config.DiscordTimestamps),
+		Template: guild.DeathTemplate,
+		Location: guild.DeathLocation,
+	})
+	if guild.DeathEmoji != "" {
+		content = guild.DeathEmoji + " " + content
+	}
+	return content
+}
+
+// SendCharacterChangeNotification posts a rename or transfer to the guild's
+// misc channel, or its level channel when it has none.
+func (a *Adapter) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	content := formatting.CatalogFor(guild.Language).CharacterChange(change)
+	if content == "" {
+		return nil
+	}
+	if guild.MiscChannelID != "" {
+		return a.sendToChannel(guild.DiscordGuildID, guild.MiscChannelID, "misc", content)
+	}
+	return a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, content)
+}
+
+// SendTestNotification posts message to the guild's death and level channels
+// so operators can confirm delivery end to end.
+func (a *Adapter) SendTestNotification(guild domain.GuildConfig, message string) error {
+	return errors.Join(
+		a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, message),
+		a.sendNotification(guild.DiscordGuildID, guild.LevelChannelID, a.config.DiscordChannelLevel, message),
+	)
+}
+
+// SendBroadcast posts an operator announcement to the guild's death channel.
+func (a *Adapter) SendBroadcast(guild domain.GuildConfig, message string) error {
+	return a.sendNotification(guild.DiscordGuildID, guild.DeathChannelID, a.config.DiscordChannelDeath, message)
 }
 
 func (a *Adapter) SendGenericMessage(guildID, channelName, message string) error {
@@ -50,17 +289,118 @@ func (a *Adapter) SendGenericMessage(guildID, channelName, message string) error
 		return err
 	}
 
-	if _, err := a.session.ChannelMessageSend(channelID, message); err != nil {
+	if err := a.sendToChannel(guildID, channelID, channelType(channelName), message); err != nil {
+		a.cache.Invalidate(guildID, channelName)
+		return err
+	}
+	return nil
+}
+
+// SendAuditLog posts message to the guild's channelName without pinging the
+// users or roles it mentions. Servers without that channel get an error and
+// nothing is posted.
+func (a *Adapter) SendAuditLog(guildID, channelName, message string) error {
+	channelID, err := a.resolveChannelID(guildID, channelName)
+	if err != nil {
+		return err
+	}
+
+	if a.dryRun(guildID, channelID, "audit", message) {
+		return nil
+	}
+
+	msg := &discordgo.MessageSend{
+		Content:         message,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	}
+	if _, err := a.session.ChannelMessageSendComplex(channelID, msg); err != nil {
+		a.cache.Invalidate(guildID, channelName)
+		metrics.DiscordMessagesSent.WithLabelValues("audit", "failure").Inc()
+		return err
+	}
+
+	metrics.DiscordMessagesSent.WithLabelValues("audit", "success").Inc()
+	return nil
+}
+
+// sendNotification prefers the channel ID stored for the guild and falls back
+// to looking the channel up by its configured name.
+func (a *Adapter) sendNotification(guildID, channelID, channelName, message string) error {
+	if channelID == "" {
+		return a.SendGenericMessage(guildID, channelName, message)
+	}
+	return a.sendToChannel(guildID, channelID, channelType(channelName), message)
+}
+
+// sendComplexNotification posts msg like sendNotification and, when reaction
+// is set, reacts to the posted message with it. A failed reaction is logged
+// and does not fail the notification.
+func (a *Adapter) sendComplexNotification(guildID, channelID, channelName string, msg *discordgo.MessageSend, reaction string) error {
+	if channelID == "" {
+		resolved, err := a.resolveChannelID(guildID, channelName)
+		if err != nil {
+			slog.Error("Failed to get channel ID", "guild_id", guildID, "channel_name", channelName, "error", err)
+			return err
+		}
+		channelID = resolved
+	}
+
+	kind := channelType(channelName)
+	if a.dryRun(guildID, channelID, kind, msg.Content) {
+		return nil
+	}
+	var sent *discordgo.Message
+	err := a.postToThreadOrChannel(channelID, func() error {
+		var err error
+		sent, err = a.session.ChannelMessageSendComplex(channelID, msg)
+		return err
+	})
+	if err != nil {
 		slog.Error("Failed to send message", "channel_id", channelID, "error", err)
 		a.cache.Invalidate(guildID, channelName)
-		metrics.DiscordMessagesSent.WithLabelValues(channelType(channelName), "failure").Inc()
+		metrics.DiscordMessagesSent.WithLabelValues(kind, "failure").Inc()
 		return err
 	}
 
-	metrics.DiscordMessagesSent.WithLabelValues(channelType(channelName), "success").Inc()
+	metrics.DiscordMessagesSent.WithLabelValues(kind, "success").Inc()
+	if reaction != "" && sent != nil {
+		if err := a.session.MessageReactionAdd(channelID, sent.ID, reaction); err != nil {
+			slog.Warn("Failed to add reaction", "channel_id", channelID, "message_id", sent.ID, "emoji", reaction, "error", err)
+		}
+	}
 	return nil
 }
 
+func (a *Adapter) sendToChannel(guildID, channelID, kind, message string) error {
+	if a.dryRun(guildID, channelID, kind, message) {
+		return nil
+	}
+	err := a.postToThreadOrChannel(channelID, func() error {
+		_, err := a.session.ChannelMessageSend(channelID, message)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to send message", "channel_id", channelID, "error", err)
+		metrics.DiscordMessagesSent.WithLabelValues(kind, "failure").Inc()
+		return err
+	}
+
+	metrics.DiscordMessagesSent.WithLabelValues(kind, "success").Inc()
+	return nil
+}
+
+// dryRun logs a message instead of posting it when NOTIFY_DRY_RUN is set and
+// reports whether it did. Channels are still resolved, so the log shows where
+// the message would have gone.
+func (a *Adapter) dryRun(guildID, channelID, kind, content string) bool {
+	if !a.config.NotifyDryRun {
+		return false
+	}
+	slog.Info("Dry run, message not sent", "guild_id", guildID, "channel_id", channelID, "kind", kind, "content", content)
+	metrics.DiscordMessagesSent.WithLabelValues(kind, "dry_run").Inc()
+	return true
+}
+
 func (a *Adapter) resolveChannelID(guildID, channelName string) (string, error) {
 	if id, ok := a.cache.Get(guildID, channelName); ok {
 		return id, nil
@@ -91,6 +431,28 @@ func (a *Adapter) fetchChannelID(guildID, channelName string) (string, error) {
 	return "", fmt.Errorf("channel %s not found", channelName)
 }
 
+// reactionFor returns the reaction for a guild's emoji, or fallback when the
+// guild has not chosen one.
+func reactionFor(emoji, fallback string) string {
+	if emoji == "" {
+		return fallback
+	}
+	return formatting.ReactionEmoji(emoji)
+}
+
+// boostedEmbed shows a boosted creature or boss with its image, when known.
+func boostedEmbed(title string, creature domain.BoostedCreature) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: title, Description: "**" + creature.Name + "**"}
+	if creature.ImageURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: creature.ImageURL}
+	}
+	return embed
+}
+
+func shouldPingRole(guild domain.GuildConfig, kill domain.Kill) bool {
+	return guild.PingRoleID != "" && kill.Level >= guild.PingMinLevel
+}
+
 func channelType(name string) string {
 	switch {
 	case strings.Contains(name, "death"):
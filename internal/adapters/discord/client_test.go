@@ -1,6 +1,8 @@
 package discord
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -12,8 +14,11 @@ import (
 )
 
 type mockDiscordSession struct {
-	guildChannelsFunc      func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error)
-	channelMessageSendFunc func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	guildChannelsFunc             func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error)
+	channelMessageSendFunc        func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	channelMessageSendComplexFunc func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	channelEditComplexFunc        func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	messageReactionAddFunc        func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error
 }
 
 func (m *mockDiscordSession) GuildChannels(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
@@ -30,6 +35,27 @@ func (m *mockDiscordSession) ChannelMessageSend(channelID, content string, optio
 	return &discordgo.Message{}, nil
 }
 
+func (m *mockDiscordSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if m.channelMessageSendComplexFunc != nil {
+		return m.channelMessageSendComplexFunc(channelID, data, options...)
+	}
+	return &discordgo.Message{}, nil
+}
+
+func (m *mockDiscordSession) ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.channelEditComplexFunc != nil {
+		return m.channelEditComplexFunc(channelID, data, options...)
+	}
+	return &discordgo.Channel{ID: channelID}, nil
+}
+
+func (m *mockDiscordSession) MessageReactionAdd(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+	if m.messageReactionAddFunc != nil {
+		return m.messageReactionAddFunc(channelID, messageID, emojiID, options...)
+	}
+	return nil
+}
+
 var testConfig = &config.Config{
 	DiscordChannelDeath: "death-tracker",
 	DiscordChannelLevel: "level-tracker",
@@ -75,7 +101,7 @@ func TestAdapter_SendLevelUpNotification(t *testing.T) {
 		NewLevel:   101,
 	}
 
-	err := adapter.SendLevelUpNotification("guild-1", levelUp)
+	err := adapter.SendLevelUpNotification(domain.GuildConfig{DiscordGuildID: "guild-1"}, levelUp)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -111,7 +137,7 @@ func TestAdapter_SendDeathNotification(t *testing.T) {
 		Reason: "Dragon",
 	}
 
-	err := adapter.SendDeathNotification("guild-1", "Hero", kill)
+	err := adapter.SendDeathNotification(domain.GuildConfig{DiscordGuildID: "guild-1"}, domain.Player{Name: "Hero"}, kill)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -125,6 +151,678 @@ func TestAdapter_SendDeathNotification(t *testing.T) {
 	}
 }
 
+func TestAdapter_SendLevelUpNotification_UsesGuildLanguage(t *testing.T) {
+	var sentContent string
+
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+			return []*discordgo.Channel{
+				{ID: "channel-level-123", Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
+			}, nil
+		},
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	levelUp := domain.LevelUp{
+		PlayerName: "Hero",
+		OldLevel:   100,
+		NewLevel:   101,
+	}
+
+	err := adapter.SendLevelUpNotification(domain.GuildConfig{DiscordGuildID: "guild-1", Language: "pl"}, levelUp)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "Hero awansował z poziomu 100 na 101"
+	if sentContent != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sentContent)
+	}
+}
+
+func TestAdapter_SendDeathNotification_PlayerLabel(t *testing.T) {
+	var sentContent string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death"}
+	player := domain.Player{Name: "Hero", Vocation: "Elite Knight", GuildName: "Red Rose", GuildRank: "Leader", WorldPvPType: "Open PvP"}
+
+	if err := adapter.SendDeathNotification(guild, player, domain.Kill{Time: time.Now(), Reason: "Dragon"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(sentContent, "Hero (Elite Knight, Leader of Red Rose, Open PvP) - ") {
+		t.Errorf("Expected labelled player, got '%s'", sentContent)
+	}
+}
+
+func TestAdapter_SendLevelUpNotification_PvPType(t *testing.T) {
+	var sentContent string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "custom-level"}
+	levelUp := domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101, WorldPvPType: "Optional PvP"}
+
+	if err := adapter.SendLevelUpNotification(guild, levelUp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(sentContent, "Hero (Optional PvP)") {
+		t.Errorf("Expected the world PvP type, got '%s'", sentContent)
+	}
+}
+
+func TestAdapter_SendDeathNotification_GuildTimezone(t *testing.T) {
+	var sentContent string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", Timezone: "America/Sao_Paulo"}
+	kill := domain.Kill{Time: time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC), Reason: "Dragon"}
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, kill); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(sentContent, "2026-01-15 09:30") {
+		t.Errorf("Expected death time in America/Sao_Paulo, got '%s'", sentContent)
+	}
+}
+
+func TestAdapter_DiscordTimestamps(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	cfg := *testConfig
+	cfg.DiscordTimestamps = true
+	adapter := NewAdapter(session, &cfg)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", LevelChannelID: "custom-level"}
+	at := time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: at, Reason: "Dragon"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101, ReachedAt: at}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"Hero - <t:1768480200:R> - Dragon", "Hero advanced from level 100 to 101 - <t:1768480200:R>"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected %q, got %q", want, sent)
+	}
+}
+
+func TestAdapter_GuildTemplates(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{
+		DiscordGuildID: "guild-1",
+		DeathChannelID: "custom-death",
+		LevelChannelID: "custom-level",
+		Timezone:       "UTC",
+		DeathTemplate:  "☠️ {player} fell at {level} ({reason}, {time})",
+		LevelTemplate:  "🆙 {player} {old_level} → {level}",
+	}
+	at := time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: at, Reason: "Dragon", Level: 500}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"☠️ Hero fell at 500 (Dragon, 2026-01-15 12:30)", "🆙 Hero 100 → 101"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected %q, got %q", want, sent)
+	}
+}
+
+func TestAdapter_ShareRange(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "custom-level", ShareRange: true}
+
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	guild.LevelTemplate = "🆙 {player} {level}"
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Other", OldLevel: 299, NewLevel: 300}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"Hero advanced from level 100 to 101 (shares XP with 68-151)", "🆙 Other 300 (shares XP with 200-450)"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected %q, got %q", want, sent)
+	}
+}
+
+func TestAdapter_LevelDown(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "custom-level", LevelDowns: true}
+
+	if err := adapter.SendLevelDownNotification(guild, domain.LevelDown{PlayerName: "Hero", OldLevel: 500, NewLevel: 497}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	guild.LevelDownTemplate = "{player} fell from {old_level} to {level}"
+	if err := adapter.SendLevelDownNotification(guild, domain.LevelDown{PlayerName: "Other", OldLevel: 300, NewLevel: 290}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []string{"📉 Hero dropped from level 500 to 497", "Other fell from 300 to 290"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("Expected %q, got %q", want, sent)
+	}
+}
+
+func TestAdapter_DeathLocation(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", DeathLocation: true}
+	when := time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+
+	kills := []domain.Kill{
+		{Time: when, Reason: "Killed by a frazzlemaw", Killers: []domain.Killer{{Name: "frazzlemaw"}}},
+		{Time: when, Reason: "Killed by a dragon", Killers: []domain.Killer{{Name: "dragon"}}},
+	}
+	for _, kill := range kills {
+		if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, kill); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(sent))
+	}
+	if !strings.HasSuffix(sent[0], "Killed by a frazzlemaw (likely at Roshamuul)") {
+		t.Errorf("Expected the location to be appended, got %q", sent[0])
+	}
+	if !strings.HasSuffix(sent[1], "Killed by a dragon") {
+		t.Errorf("Expected no location for a creature found everywhere, got %q", sent[1])
+	}
+}
+
+func TestAdapter_DeathVerbosity(t *testing.T) {
+	var sent []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	player := domain.Player{Name: "Hero", Vocation: "Elite Knight"}
+	kill := domain.Kill{Time: time.Now(), Level: 500, Reason: "Killed by a dragon", Killers: []domain.Killer{{Name: "dragon"}}}
+	for _, verbosity := range []domain.DeathVerbosity{domain.VerbosityCompact, domain.VerbosityDetailed} {
+		guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", DeathVerbosity: verbosity, DeathEmoji: "💀"}
+		if err := adapter.SendDeathNotification(guild, player, kill); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(sent))
+	}
+	if sent[0] != "💀 Hero - Killed by a dragon" {
+		t.Errorf("Expected a compact message, got %q", sent[0])
+	}
+	if !strings.HasPrefix(sent[1], "💀 Hero (Elite Knight)") || !strings.Contains(sent[1], "\nKillers: dragon\n") {
+		t.Errorf("Expected a detailed message, got %q", sent[1])
+	}
+}
+
+func TestAdapter_EmojisAndReactions(t *testing.T) {
+	var sent []string
+	var reactions []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{ID: "plain"}, nil
+		},
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, data.Content)
+			return &discordgo.Message{ID: "msg-" + channelID}, nil
+		},
+		messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+			reactions = append(reactions, messageID+" "+emojiID)
+			return errors.New("missing access")
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{
+		DiscordGuildID: "guild-1",
+		DeathChannelID: "deaths",
+		LevelChannelID: "levels",
+		DeathEmoji:     "<:rip:123>",
+		DeathReaction:  true,
+		LevelEmoji:     "⭐",
+	}
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: time.Now(), Reason: "Dragon"}); err != nil {
+		t.Fatalf("Expected a failed reaction not to fail the notification, got %v", err)
+	}
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	guild.LevelEmoji = ""
+	guild.LevelReaction = true
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Other", OldLevel: 100, NewLevel: 101}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sent) != 3 || !strings.HasPrefix(sent[0], "<:rip:123> Hero - ") || sent[1] != "⭐ Hero advanced from level 100 to 101" || sent[2] != "Other advanced from level 100 to 101" {
+		t.Errorf("unexpected messages: %q", sent)
+	}
+	want := []string{"msg-deaths rip:123", "msg-levels 🎉"}
+	if !reflect.DeepEqual(reactions, want) {
+		t.Errorf("Expected reactions %q, got %q", want, reactions)
+	}
+}
+
+func TestAdapter_SendBoostedNotification(t *testing.T) {
+	var channel string
+	var embeds []*discordgo.MessageEmbed
+	session := &mockDiscordSession{
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			channel = channelID
+			embeds = data.Embeds
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "levels", MiscChannelID: "misc", Language: "pl"}
+	boosted := domain.Boosted{
+		Creature: domain.BoostedCreature{Name: "Dragon", ImageURL: "https://static.tibia.com/dragon.gif"},
+		Boss:     domain.BoostedCreature{Name: "Ferumbras"},
+	}
+	if err := adapter.SendBoostedNotification(guild, boosted); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if channel != "misc" {
+		t.Errorf("Expected the misc channel, got %q", channel)
+	}
+	if len(embeds) != 2 {
+		t.Fatalf("Expected 2 embeds, got %d", len(embeds))
+	}
+	if e := embeds[0]; e.Title != "🐾 Wzmocniony potwór" || e.Description != "**Dragon**" || e.Thumbnail == nil || e.Thumbnail.URL != boosted.Creature.ImageURL {
+		t.Errorf("unexpected creature embed: %+v", e)
+	}
+	if e := embeds[1]; e.Description != "**Ferumbras**" || e.Thumbnail != nil {
+		t.Errorf("Expected a boss embed without thumbnail, got %+v", e)
+	}
+}
+
+func TestAdapter_SendDeathNotification_PrefersStoredChannel(t *testing.T) {
+	var sentChannelID string
+	lookups := 0
+
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+			lookups++
+			return nil, nil
+		},
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannelID = channelID
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death"}
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: time.Now()}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sentChannelID != "custom-death" {
+		t.Errorf("Expected channel ID 'custom-death', got '%s'", sentChannelID)
+	}
+	if lookups != 0 {
+		t.Errorf("Expected no channel lookups, got %d", lookups)
+	}
+}
+
+func TestAdapter_SendDeathNotification_RoutesByLevel(t *testing.T) {
+	var sentChannels []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannels = append(sentChannels, channelID)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{
+		DiscordGuildID: "guild-1",
+		DeathChannelID: "deaths",
+		DeathRoutes:    []domain.DeathRoute{{MinLevel: 1000, ChannelID: "high-deaths"}},
+	}
+
+	for _, level := range []int{999, 1000} {
+		if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: time.Now(), Level: level}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	want := []string{"deaths", "high-deaths"}
+	if !reflect.DeepEqual(sentChannels, want) {
+		t.Errorf("Expected channels %q, got %q", want, sentChannels)
+	}
+}
+
+func TestAdapter_SendCatchUpNotification(t *testing.T) {
+	sent := make(map[string]string)
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent[channelID] = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "deaths", LevelChannelID: "levels"}
+	catchUp := domain.CatchUp{
+		Deaths:   []domain.CatchUpDeath{{Player: domain.Player{Name: "Hero"}, Kill: domain.Kill{Time: time.Now(), Level: 300, Reason: "Killed by a dragon"}}},
+		LevelUps: []domain.LevelUp{{PlayerName: "Hero", OldLevel: 299, NewLevel: 300}, {PlayerName: "Other", OldLevel: 99, NewLevel: 100}},
+	}
+
+	if err := adapter.SendCatchUpNotification(guild, catchUp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(sent["deaths"], "🌙 While quiet hours were on:\nHero - ") || !strings.Contains(sent["deaths"], "Killed by a dragon") {
+		t.Errorf("unexpected death catch-up: %q", sent["deaths"])
+	}
+	if want := "🌙 While quiet hours were on:\nHero advanced from level 299 to 300\nOther advanced from level 99 to 100"; sent["levels"] != want {
+		t.Errorf("Expected %q, got %q", want, sent["levels"])
+	}
+}
+
+func TestAdapter_SendCatchUpNotification_MatchesLive(t *testing.T) {
+	sent := make(map[string]string)
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent[channelID] = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{
+		DiscordGuildID: "guild-1",
+		DeathChannelID: "deaths",
+		LevelChannelID: "levels",
+		DeathVerbosity: domain.VerbosityCompact,
+		DeathEmoji:     "💀",
+		LevelEmoji:     "🎉",
+		LevelTemplate:  "{player} {old_level} → {level}",
+	}
+	player := domain.Player{Name: "Hero", Vocation: "Elite Knight"}
+	kill := domain.Kill{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Level: 300, Reason: "Killed at Level 300 by a dragon"}
+	levelUp := domain.LevelUp{PlayerName: "Hero", OldLevel: 299, NewLevel: 300, ReachedAt: kill.Time}
+
+	if err := adapter.SendDeathNotification(guild, player, kill); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := adapter.SendLevelUpNotification(guild, levelUp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	liveDeath, liveLevel := sent["deaths"], sent["levels"]
+
+	catchUp := domain.CatchUp{
+		Deaths:   []domain.CatchUpDeath{{Player: player, Kill: kill}},
+		LevelUps: []domain.LevelUp{levelUp},
+	}
+	if err := adapter.SendCatchUpNotification(guild, catchUp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := "🌙 While quiet hours were on:\n" + liveDeath; sent["deaths"] != want {
+		t.Errorf("Expected %q, got %q", want, sent["deaths"])
+	}
+	if want := "🌙 While quiet hours were on:\n" + liveLevel; sent["levels"] != want {
+		t.Errorf("Expected %q, got %q", want, sent["levels"])
+	}
+	if !strings.HasPrefix(liveLevel, "🎉 Hero 299 → 300") {
+		t.Errorf("Expected the level template, got %q", liveLevel)
+	}
+}
+
+func TestAdapter_SendDeathStreakNotification(t *testing.T) {
+	var sentChannelID, sentContent string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannelID = channelID
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death"}
+
+	if err := adapter.SendDeathStreakNotification(guild, "Hero", 3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sentChannelID != "custom-death" {
+		t.Errorf("Expected channel ID 'custom-death', got '%s'", sentChannelID)
+	}
+	expected := "Hero is on a death streak: 3 deaths in the last hour!"
+	if sentContent != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sentContent)
+	}
+}
+
+func TestAdapter_SendMembershipNotification(t *testing.T) {
+	var sentChannelID, sentContent string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannelID = channelID
+			sentContent = content
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "custom-level"}
+	change := domain.MembershipChange{GuildName: "Red Rose", Joined: []string{"Hero"}, Left: []string{"Villain"}}
+
+	if err := adapter.SendMembershipNotification(guild, change); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sentChannelID != "custom-level" {
+		t.Errorf("Expected channel ID 'custom-level', got '%s'", sentChannelID)
+	}
+	expected := "Hero joined Red Rose\nVillain left Red Rose"
+	if sentContent != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sentContent)
+	}
+}
+
+func TestAdapter_SendTestNotification(t *testing.T) {
+	var sentChannels []string
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannels = append(sentChannels, channelID)
+			if channelID == "custom-level" {
+				return nil, errors.New("missing access")
+			}
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", LevelChannelID: "custom-level"}
+
+	err := adapter.SendTestNotification(guild, "ping")
+	if err == nil || !strings.Contains(err.Error(), "missing access") {
+		t.Errorf("Expected level channel error, got %v", err)
+	}
+	if len(sentChannels) != 2 || sentChannels[0] != "custom-death" || sentChannels[1] != "custom-level" {
+		t.Errorf("Expected both channels to be tried, got %v", sentChannels)
+	}
+}
+
+func TestAdapter_SendBroadcast(t *testing.T) {
+	var sentChannels []string
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentChannels = append(sentChannels, channelID)
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "custom-death", LevelChannelID: "custom-level"}
+
+	if err := adapter.SendBroadcast(guild, "maintenance"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sentChannels) != 1 || sentChannels[0] != "custom-death" {
+		t.Errorf("Expected only the death channel, got %v", sentChannels)
+	}
+}
+
+func TestAdapter_SendDeathNotification_PingRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     int
+		wantPing  bool
+		minLevel  int
+		roleID    string
+		channelID string
+	}{
+		{"above threshold", 600, true, 500, "role-1", "custom-death"},
+		{"at threshold", 500, true, 500, "role-1", "custom-death"},
+		{"below threshold", 499, false, 500, "role-1", "custom-death"},
+		{"no role configured", 900, false, 500, "", "custom-death"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plain string
+			var complexMsg *discordgo.MessageSend
+
+			session := &mockDiscordSession{
+				channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					plain = content
+					return &discordgo.Message{}, nil
+				},
+				channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					complexMsg = data
+					return &discordgo.Message{}, nil
+				},
+			}
+
+			adapter := NewAdapter(session, testConfig)
+			guild := domain.GuildConfig{
+				DiscordGuildID: "guild-1",
+				DeathChannelID: tt.channelID,
+				PingRoleID:     tt.roleID,
+				PingMinLevel:   tt.minLevel,
+			}
+
+			if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Time: time.Now(), Level: tt.level}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !tt.wantPing {
+				if complexMsg != nil || plain == "" {
+					t.Fatal("Expected plain message without mention")
+				}
+				return
+			}
+
+			if complexMsg == nil {
+				t.Fatal("Expected message with mention")
+			}
+			if !strings.HasPrefix(complexMsg.Content, "<@&role-1> ") {
+				t.Errorf("Expected role mention prefix, got '%s'", complexMsg.Content)
+			}
+			if complexMsg.AllowedMentions == nil || len(complexMsg.AllowedMentions.Roles) != 1 || complexMsg.AllowedMentions.Roles[0] != "role-1" {
+				t.Errorf("Expected allowed mentions limited to role-1, got %+v", complexMsg.AllowedMentions)
+			}
+		})
+	}
+}
+
 func TestAdapter_SendGenericMessage_CacheRequests(t *testing.T) {
 	guildChannelsCalled := 0
 
@@ -155,6 +853,84 @@ func TestAdapter_SendGenericMessage_CacheRequests(t *testing.T) {
 	}
 }
 
+func TestAdapter_SendAuditLog(t *testing.T) {
+	var sent *discordgo.MessageSend
+	var sentTo string
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+			return []*discordgo.Channel{
+				{ID: "audit-id", Name: "tracker-audit", Type: discordgo.ChannelTypeGuildText},
+			}, nil
+		},
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sentTo, sent = channelID, data
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	if err := adapter.SendAuditLog("guild-1", "tracker-audit", "<@1> used /set-ping-role role: <@&2>"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sentTo != "audit-id" {
+		t.Errorf("expected audit channel, got %q", sentTo)
+	}
+	if sent.AllowedMentions == nil || len(sent.AllowedMentions.Parse) != 0 || len(sent.AllowedMentions.Roles) != 0 || len(sent.AllowedMentions.Users) != 0 {
+		t.Errorf("expected mentions to be suppressed, got %+v", sent.AllowedMentions)
+	}
+
+	if err := adapter.SendAuditLog("guild-2", "missing", "entry"); err == nil {
+		t.Error("expected error when the audit channel does not exist")
+	}
+}
+
+func TestAdapter_DryRun(t *testing.T) {
+	var channelLookups int
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+			channelLookups++
+			return []*discordgo.Channel{
+				{ID: "death-id", Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+				{ID: "audit-id", Name: "tracker-audit", Type: discordgo.ChannelTypeGuildText},
+			}, nil
+		},
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			t.Errorf("dry run sent %q to %s", content, channelID)
+			return &discordgo.Message{}, nil
+		},
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			t.Errorf("dry run sent %q to %s", data.Content, channelID)
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	cfg := *testConfig
+	cfg.NotifyDryRun = true
+	adapter := NewAdapter(session, &cfg)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", HouseChannelID: "house-id"}
+	kill := domain.Kill{Time: time.Now(), Level: 600, Reason: "Killed by a dragon"}
+
+	if err := adapter.SendDeathNotification(guild, domain.Player{Name: "Alice"}, kill); err != nil {
+		t.Errorf("death: %v", err)
+	}
+	pinged := guild
+	pinged.PingRoleID = "role-1"
+	if err := adapter.SendDeathNotification(pinged, domain.Player{Name: "Alice"}, kill); err != nil {
+		t.Errorf("death with ping: %v", err)
+	}
+	if err := adapter.SendAuditLog("guild-1", "tracker-audit", "entry"); err != nil {
+		t.Errorf("audit: %v", err)
+	}
+	if err := adapter.SendHouseAuctionNotification(guild, domain.HouseAuction{Name: "Villa"}, false); err != nil {
+		t.Errorf("house: %v", err)
+	}
+
+	if channelLookups == 0 {
+		t.Error("expected channels to be resolved in a dry run")
+	}
+}
+
 func TestChannelCache_Invalidate(t *testing.T) {
 	cache := newChannelCache()
 
@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"death-level-tracker/internal/adapters/discord/formatting"
 	"death-level-tracker/internal/config"
@@ -14,8 +18,20 @@ import (
 )
 
 type BotHandler struct {
-	Config  *config.Config
-	Service *services.ConfigurationService
+	Config   *config.Config
+	Service  *services.ConfigurationService
+	Backfill *services.BackfillService
+	Stats    *services.StatsService
+	Retries  *services.NotificationQueue
+	Exports  *services.ExportService
+	// Capabilities gates premium features; nil gates nothing.
+	Capabilities *services.CapabilityService
+	// Commands lists the registered slash commands for /help; nil lists
+	// every command in GetApplicationCommands.
+	Commands func() []string
+	// Pages pages list replies too long for one message; nil sends only
+	// their first page.
+	Pages *Paginator
 }
 
 func ReadyHandler(session *discordgo.Session, ready *discordgo.Ready) {
@@ -29,52 +45,227 @@ func (h *BotHandler) TrackWorld(s DiscordSession, i *discordgo.InteractionCreate
 		return
 	}
 
-	if _, err := ensureChannel(s, i.GuildID, h.Config.DiscordChannelDeath); err != nil {
-		slog.Error("Failed to ensure death-tracker channel", "error", err)
-		respond(s, i, formatting.MsgChannelError(h.Config.DiscordChannelDeath), true)
+	if missing := missingPermissions(i.AppPermissions, guildPermissions); len(missing) > 0 {
+		respond(s, i, formatting.MsgMissingPermissions(missing), true)
 		return
 	}
 
-	if _, err := ensureChannel(s, i.GuildID, h.Config.DiscordChannelLevel); err != nil {
-		slog.Error("Failed to ensure level-tracker channel", "error", err)
-		respond(s, i, formatting.MsgChannelError(h.Config.DiscordChannelLevel), true)
+	// The world is saved only once both channels exist. The wizard is
+	// offered only when no world was tracked before.
+	formattedWorld, firstSetup, err := h.Service.TrackWorld(context.Background(), i.GuildID, worldName, func(ctx context.Context) error {
+		for _, name := range []string{h.Config.DiscordChannelDeath, h.Config.DiscordChannelLevel} {
+			if _, err := ensureChannel(s, i.GuildID, name); err != nil {
+				return &channelError{name: name, err: err}
+			}
+		}
+		return nil
+	})
+	var limitErr *services.LimitError
+	var chErr *channelError
+	switch {
+	case errors.As(err, &limitErr):
+		respond(s, i, limitMessage(limitErr), true)
 		return
-	}
-
-	formattedWorld, err := h.Service.SetWorld(context.Background(), i.GuildID, worldName)
-	if err != nil {
+	case errors.As(err, &chErr):
+		slog.Error("Failed to ensure channel", "channel", chErr.name, "error", chErr.err)
+		respond(s, i, formatting.MsgChannelError(chErr.name), true)
+		return
+	case err != nil:
 		slog.Error("Failed to save world", "error", err)
 		respond(s, i, formatting.MsgSaveError, true)
 		return
 	}
 
 	respond(s, i, formatting.MsgTrackSuccess(formattedWorld, h.Config.DiscordChannelDeath, h.Config.DiscordChannelLevel), false)
+	if firstSetup {
+		sendSetupWizard(s, i)
+	}
 }
 
+// Component routes of the /stop-tracking confirmation buttons.
+const (
+	StopTrackingConfirmRoute = "stop-tracking-confirm"
+	StopTrackingCancelRoute  = "stop-tracking-cancel"
+)
+
+// StopTracking asks for confirmation before removing the configuration; the
+// deletion happens in StopTrackingConfirm.
 func (h *BotHandler) StopTracking(s DiscordSession, i *discordgo.InteractionCreate) {
+	respondConfirm(s, i, formatting.MsgStopConfirm, formatting.MsgStopConfirmButton, StopTrackingConfirmRoute, StopTrackingCancelRoute)
+}
+
+func (h *BotHandler) StopTrackingConfirm(s DiscordSession, i *discordgo.InteractionCreate) {
+	if confirmExpired(i) {
+		respond(s, i, formatting.MsgConfirmExpired, true)
+		return
+	}
+
 	if err := h.Service.StopTracking(context.Background(), i.GuildID); err != nil {
 		slog.Error("Failed to delete guild config", "guild_id", i.GuildID, "error", err)
 		respond(s, i, formatting.MsgStopError, true)
 		return
 	}
 
-	respond(s, i, formatting.MsgStopSuccess, false)
+	updateMessage(s, i, formatting.MsgStopSuccess)
+}
+
+func (h *BotHandler) StopTrackingCancel(s DiscordSession, i *discordgo.InteractionCreate) {
+	updateMessage(s, i, formatting.MsgStopCancelled)
+}
+
+// ResumeTracking restores the configuration removed by /stop-tracking.
+func (h *BotHandler) ResumeTracking(s DiscordSession, i *discordgo.InteractionCreate) {
+	world, err := h.Service.ResumeTracking(context.Background(), i.GuildID)
+	var limitErr *services.LimitError
+	switch {
+	case errors.Is(err, services.ErrNothingToResume):
+		respond(s, i, formatting.MsgNothingToResume, true)
+	case errors.Is(err, services.ErrWorldTracked):
+		respond(s, i, formatting.MsgResumeWorldTracked, true)
+	case errors.As(err, &limitErr):
+		respond(s, i, limitMessage(limitErr), true)
+	case err != nil:
+		slog.Error("Failed to resume tracking", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgResumeError, true)
+	default:
+		respond(s, i, formatting.MsgResumeSuccess(world), false)
+	}
+}
+
+// Component routes of the /purge-data confirmation buttons.
+const (
+	PurgeDataConfirmRoute = "purge-data-confirm"
+	PurgeDataCancelRoute  = "purge-data-cancel"
+)
+
+// PurgeData asks for confirmation before wiping everything stored for the
+// server; the deletion happens in PurgeDataConfirm.
+func (h *BotHandler) PurgeData(s DiscordSession, i *discordgo.InteractionCreate) {
+	respondConfirm(s, i, formatting.MsgPurgeConfirm, formatting.MsgPurgeConfirmButton, PurgeDataConfirmRoute, PurgeDataCancelRoute)
+}
+
+func (h *BotHandler) PurgeDataConfirm(s DiscordSession, i *discordgo.InteractionCreate) {
+	if confirmExpired(i) {
+		respond(s, i, formatting.MsgConfirmExpired, true)
+		return
+	}
+
+	result, err := h.Service.PurgeGuildData(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to purge guild data", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgPurgeError, true)
+		return
+	}
+
+	slog.Info("Purged guild data", "guild_id", i.GuildID, "configs", result.Configs, "notifications", result.Notifications,
+		"guild_members", result.GuildMembers, "deaths", result.Deaths, "level_ups", result.LevelUps, "players", result.Players)
+	updateMessage(s, i, formatting.MsgPurgeComplete(result))
+}
+
+func (h *BotHandler) PurgeDataCancel(s DiscordSession, i *discordgo.InteractionCreate) {
+	updateMessage(s, i, formatting.MsgPurgeCancelled)
 }
 
 func (h *BotHandler) AddGuild(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handleWorldGuildAutocomplete(s, i)
+		return
+	}
+
 	guildName := getStringOption(i.ApplicationCommandData().Options, "name")
 	if guildName == "" {
 		respond(s, i, formatting.MsgGuildNameRequired, true)
 		return
 	}
 
-	if err := h.Service.AddGuildToTrack(context.Background(), i.GuildID, guildName); err != nil {
+	// Limits are checked before deferring so that hitting one gets a private
+	// reply.
+	var limitErr *services.LimitError
+	err := h.Service.CheckAddGuild(context.Background(), i.GuildID, guildName)
+	switch {
+	case errors.Is(err, services.ErrNoWorldTracked):
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	case errors.As(err, &limitErr):
+		respond(s, i, limitMessage(limitErr), true)
+		return
+	case err != nil:
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	// The guild is looked up on TibiaData, so the reply is deferred.
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		msg, _ := h.addGuild(ctx, i.GuildID, guildName)
+		return msg
+	})
+}
+
+// addGuild tracks the Tibia guild and starts seeding its members' levels. It
+// returns the reply for the user and whether the guild was added.
+func (h *BotHandler) addGuild(ctx context.Context, guildID, guildName string) (string, bool) {
+	added, err := h.Service.AddGuildToTrack(ctx, guildID, guildName)
+	var limitErr *services.LimitError
+	var worldErr *services.GuildWorldError
+	switch {
+	case errors.Is(err, services.ErrNoWorldTracked):
+		return formatting.MsgWorldNotTracked, false
+	case errors.As(err, &limitErr):
+		return limitMessage(limitErr), false
+	case errors.As(err, &worldErr):
+		return formatting.MsgGuildOtherWorld(worldErr.Guild, worldErr.World, worldErr.TrackedWorld), false
+	case errors.Is(err, domain.ErrNotFound):
+		return formatting.MsgGuildNotFound(guildName), false
+	case lookupFailed(err):
+		slog.Error("Failed to look up guild", "guild", guildName, "error", err)
+		return formatting.MsgGuildLookupError, false
+	case err != nil:
 		slog.Error("Failed to add guild", "error", err)
-		respond(s, i, formatting.MsgSaveError, true)
+		return formatting.MsgSaveError, false
+	}
+
+	h.startBackfill(added)
+	return formatting.MsgGuildAdded(added), true
+}
+
+func (h *BotHandler) SyncGuild(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handleGuildAutocomplete(s, i)
+		return
+	}
+
+	guildName := getStringOption(i.ApplicationCommandData().Options, "name")
+	if guildName == "" {
+		respond(s, i, formatting.MsgGuildNameRequired, true)
+		return
+	}
+
+	respondDeferred(s, i, true, func(ctx context.Context) string {
+		if h.Backfill == nil {
+			return formatting.MsgGuildSynced(guildName, 0)
+		}
+		seeded, err := h.Backfill.SyncGuild(ctx, guildName)
+		if err != nil {
+			slog.Error("Failed to sync guild members", "guild", guildName, "error", err)
+			return formatting.MsgGuildSyncError
+		}
+		return formatting.MsgGuildSynced(guildName, seeded)
+	})
+}
+
+// startBackfill seeds the guild's member levels in the background so the
+// interaction can be answered within Discord's response deadline.
+func (h *BotHandler) startBackfill(guildName string) {
+	if h.Backfill == nil {
 		return
 	}
 
-	respond(s, i, formatting.MsgGuildAdded(guildName), false)
+	go func() {
+		if _, err := h.Backfill.SyncGuild(context.Background(), guildName); err != nil {
+			slog.Error("Failed to backfill guild members", "guild", guildName, "error", err)
+		}
+	}()
 }
 
 func (h *BotHandler) UnsetGuild(s DiscordSession, i *discordgo.InteractionCreate) {
@@ -113,6 +304,141 @@ func (h *BotHandler) handleGuildAutocomplete(s DiscordSession, i *discordgo.Inte
 	}
 }
 
+// handleWorldGuildAutocomplete suggests the guilds of the tracked world as
+// listed on TibiaData.
+func (h *BotHandler) handleWorldGuildAutocomplete(s DiscordSession, i *discordgo.InteractionCreate) {
+	query := getFocusedOption(i.ApplicationCommandData().Options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+	defer cancel()
+
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to fetch guild config for autocomplete", "error", err)
+		return
+	}
+
+	var names []string
+	if cfg != nil && cfg.World != "" {
+		names, err = h.Service.WorldGuildNames(ctx, cfg.World)
+		if err != nil {
+			slog.Warn("Failed to fetch world guilds for autocomplete", "world", cfg.World, "error", err)
+			return
+		}
+	}
+	if err := respondAutocomplete(s, i, buildChoices(names, query)); err != nil {
+		slog.Error("Failed to send autocomplete response", "error", err)
+	}
+}
+
+// handlePlayerAutocomplete suggests characters stored on the tracked world.
+func (h *BotHandler) handlePlayerAutocomplete(s DiscordSession, i *discordgo.InteractionCreate) {
+	query := getFocusedOption(i.ApplicationCommandData().Options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+	defer cancel()
+
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to fetch guild config for autocomplete", "error", err)
+		return
+	}
+
+	var names []string
+	if cfg != nil && cfg.World != "" {
+		names, err = h.Service.PlayerNames(ctx, cfg.World, query)
+		if err != nil {
+			slog.Warn("Failed to fetch players for autocomplete", "world", cfg.World, "error", err)
+			return
+		}
+	}
+	if err := respondAutocomplete(s, i, nameChoices(names)); err != nil {
+		slog.Error("Failed to send autocomplete response", "error", err)
+	}
+}
+
+func (h *BotHandler) IgnorePlayer(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handlePlayerAutocomplete(s, i)
+		return
+	}
+
+	name := getStringOption(i.ApplicationCommandData().Options, "name")
+	if strings.TrimSpace(name) == "" {
+		respond(s, i, formatting.MsgPlayerNameRequired, true)
+		return
+	}
+
+	var limitErr *services.LimitError
+	err := h.Service.CheckIgnorePlayer(context.Background(), i.GuildID, name)
+	switch {
+	case errors.As(err, &limitErr):
+		respond(s, i, limitMessage(limitErr), true)
+		return
+	case err != nil:
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	// The character is looked up on TibiaData, so the reply is deferred.
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		ignored, err := h.Service.IgnorePlayer(ctx, i.GuildID, name)
+		switch {
+		case errors.As(err, &limitErr):
+			return limitMessage(limitErr)
+		case errors.Is(err, domain.ErrNotFound):
+			return formatting.MsgCharacterNotFound(strings.TrimSpace(name))
+		case lookupFailed(err):
+			slog.Error("Failed to look up character", "name", name, "error", err)
+			return formatting.MsgCharacterLookupError
+		case err != nil:
+			slog.Error("Failed to ignore player", "guild_id", i.GuildID, "error", err)
+			return formatting.MsgSaveError
+		}
+		return formatting.MsgPlayerIgnored(ignored)
+	})
+}
+
+func (h *BotHandler) UnignorePlayer(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handleIgnoredAutocomplete(s, i)
+		return
+	}
+
+	name := getStringOption(i.ApplicationCommandData().Options, "name")
+	if strings.TrimSpace(name) == "" {
+		respond(s, i, formatting.MsgPlayerNameRequired, true)
+		return
+	}
+
+	if err := h.Service.UnignorePlayer(context.Background(), i.GuildID, name); err != nil {
+		slog.Error("Failed to unignore player", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgPlayerUnignored(name), false)
+}
+
+func (h *BotHandler) handleIgnoredAutocomplete(s DiscordSession, i *discordgo.InteractionCreate) {
+	query := getFocusedOption(i.ApplicationCommandData().Options)
+
+	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to fetch guild config for autocomplete", "error", err)
+		return
+	}
+
+	var ignored []string
+	if cfg != nil {
+		ignored = cfg.IgnoredPlayers
+	}
+	if err := respondAutocomplete(s, i, buildChoices(ignored, query)); err != nil {
+		slog.Error("Failed to send autocomplete response", "error", err)
+	}
+}
+
 func (h *BotHandler) ListGuilds(s DiscordSession, i *discordgo.InteractionCreate) {
 	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
 	if err != nil {
@@ -126,20 +452,1000 @@ func (h *BotHandler) ListGuilds(s DiscordSession, i *discordgo.InteractionCreate
 		return
 	}
 
-	respond(s, i, formatting.MsgGuildsList(cfg.TibiaGuilds), false)
+	h.Pages.Respond(s, i, formatting.MsgGuildsList(cfg.TibiaGuilds), false)
 }
 
-func buildGuildChoices(cfg *domain.GuildConfig, query string) []*discordgo.ApplicationCommandOptionChoice {
-	if cfg == nil {
-		return nil
+func (h *BotHandler) SetLanguage(s DiscordSession, i *discordgo.InteractionCreate) {
+	language := getStringOption(i.ApplicationCommandData().Options, "language")
+	if !formatting.IsSupportedLanguage(language) {
+		respond(s, i, formatting.MsgLanguageInvalid, true)
+		return
+	}
+
+	if err := h.Service.SetLanguage(context.Background(), i.GuildID, language); err != nil {
+		slog.Error("Failed to set language", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgLanguageSet(formatting.CatalogFor(language).Name), false)
+}
+
+func (h *BotHandler) SetChannel(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	kind := domain.NotificationChannel(getStringOption(opts, "type"))
+	channelID := getChannelOption(opts, "channel")
+
+	if (kind != domain.ChannelDeaths && kind != domain.ChannelLevels && kind != domain.ChannelMisc) || channelID == "" {
+		respond(s, i, formatting.MsgChannelInvalid, true)
+		return
+	}
+	if ch := resolvedChannel(i, channelID); ch != nil && ch.IsThread() && ch.ThreadMetadata != nil && ch.ThreadMetadata.Locked {
+		respond(s, i, formatting.MsgThreadLocked, true)
+		return
+	}
+
+	if err := h.Service.SetChannel(context.Background(), i.GuildID, kind, channelID); err != nil {
+		slog.Error("Failed to set channel", "guild_id", i.GuildID, "type", kind, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgChannelSet(string(kind), channelID), false)
+}
+
+func (h *BotHandler) SetPingRole(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	roleID := getRoleOption(opts, "role")
+	if roleID == "" {
+		respond(s, i, formatting.MsgRoleRequired, true)
+		return
+	}
+
+	minLevel := getIntOption(opts, "min-level", h.Config.MinLevelTrack)
+	if minLevel < 0 {
+		respond(s, i, formatting.MsgMinLevelInvalid, true)
+		return
+	}
+
+	if err := h.Service.SetPingRole(context.Background(), i.GuildID, roleID, minLevel); err != nil {
+		slog.Error("Failed to set ping role", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgPingRoleSet(roleID, minLevel), false)
+}
+
+func (h *BotHandler) SetPollInterval(s DiscordSession, i *discordgo.InteractionCreate) {
+	minutes := getIntOption(i.ApplicationCommandData().Options, "minutes", -1)
+	if minutes < 0 || minutes > int(maxPollMinutes) {
+		respond(s, i, formatting.MsgPollIntervalInvalid, true)
+		return
+	}
+
+	interval := time.Duration(minutes) * time.Minute
+	if interval > 0 {
+		cfg, err := h.capabilityConfig(context.Background(), i.GuildID)
+		if err != nil {
+			slog.Error("Failed to get guild config", "guild_id", i.GuildID, "error", err)
+			respond(s, i, formatting.MsgSaveError, true)
+			return
+		}
+		if floor := h.Capabilities.MinPollInterval(cfg); interval < floor {
+			respond(s, i, formatting.MsgPollIntervalPremium(floor), true)
+			return
+		}
+	}
+
+	if err := h.Service.SetPollInterval(context.Background(), i.GuildID, interval); err != nil {
+		slog.Error("Failed to set poll interval", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgPollIntervalSet(interval), false)
+}
+
+func (h *BotHandler) MuteTracker(s DiscordSession, i *discordgo.InteractionCreate) {
+	hours := getIntOption(i.ApplicationCommandData().Options, "hours", -1)
+	if hours < 0 || hours > int(maxMuteHours) {
+		respond(s, i, formatting.MsgMuteInvalid, true)
+		return
+	}
+
+	until, err := h.Service.Mute(context.Background(), i.GuildID, time.Duration(hours)*time.Hour)
+	if err != nil {
+		slog.Error("Failed to mute tracker", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgMuted(until), false)
+}
+
+func (h *BotHandler) SetLowLevelDeaths(s DiscordSession, i *discordgo.InteractionCreate) {
+	enabled := getBoolOption(i.ApplicationCommandData().Options, "enabled", true)
+
+	if err := h.Service.SetLowLevelDeaths(context.Background(), i.GuildID, enabled); err != nil {
+		slog.Error("Failed to set low-level deaths", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgLowLevelDeathsSet(enabled, h.Config.MinLevelTrack), false)
+}
+
+func (h *BotHandler) SetMinLevel(s DiscordSession, i *discordgo.InteractionCreate) {
+	level := getIntOption(i.ApplicationCommandData().Options, "level", -1)
+
+	err := h.Service.SetMinLevel(context.Background(), i.GuildID, level)
+	if errors.Is(err, services.ErrInvalidMinLevel) {
+		respond(s, i, formatting.MsgGuildMinLevelInvalid, true)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to set minimum level", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgMinLevelSet(level, h.Config.MinLevelTrack), false)
+}
+
+func (h *BotHandler) SetShareRange(s DiscordSession, i *discordgo.InteractionCreate) {
+	enabled := getBoolOption(i.ApplicationCommandData().Options, "enabled", true)
+
+	if err := h.Service.SetShareRange(context.Background(), i.GuildID, enabled); err != nil {
+		slog.Error("Failed to set share range", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgShareRangeSet(enabled), false)
+}
+
+func (h *BotHandler) SetLevelDowns(s DiscordSession, i *discordgo.InteractionCreate) {
+	enabled := getBoolOption(i.ApplicationCommandData().Options, "enabled", true)
+
+	if err := h.Service.SetLevelDowns(context.Background(), i.GuildID, enabled); err != nil {
+		slog.Error("Failed to set level downs", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
 	}
 
+	respond(s, i, formatting.MsgLevelDownsSet(enabled), false)
+}
+
+// SetResponseVisibility answers with the new visibility already applied, as
+// WithResponseVisibility looks it up on the first answer.
+func (h *BotHandler) SetResponseVisibility(s DiscordSession, i *discordgo.InteractionCreate) {
+	visibility := domain.ResponseVisibility(getStringOption(i.ApplicationCommandData().Options, "visibility"))
+	if visibility == visibilityDefaultChoice {
+		visibility = domain.VisibilityDefault
+	}
+
+	err := h.Service.SetResponseVisibility(context.Background(), i.GuildID, visibility)
+	if errors.Is(err, services.ErrInvalidVisibility) {
+		respond(s, i, formatting.MsgVisibilityInvalid, true)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to set response visibility", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgResponseVisibilitySet(visibility), false)
+}
+
+func (h *BotHandler) SetDeathVerbosity(s DiscordSession, i *discordgo.InteractionCreate) {
+	verbosity := domain.DeathVerbosity(getStringOption(i.ApplicationCommandData().Options, "verbosity"))
+	if verbosity == verbosityNormalChoice {
+		verbosity = domain.VerbosityNormal
+	}
+
+	err := h.Service.SetDeathVerbosity(context.Background(), i.GuildID, verbosity)
+	if errors.Is(err, services.ErrInvalidVerbosity) {
+		respond(s, i, formatting.MsgVerbosityInvalid, true)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to set death verbosity", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgDeathVerbositySet(verbosity), false)
+}
+
+func (h *BotHandler) SetDeathLocation(s DiscordSession, i *discordgo.InteractionCreate) {
+	enabled := getBoolOption(i.ApplicationCommandData().Options, "enabled", true)
+
+	if err := h.Service.SetDeathLocation(context.Background(), i.GuildID, enabled); err != nil {
+		slog.Error("Failed to set death location", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgDeathLocationSet(enabled), false)
+}
+
+func (h *BotHandler) SetMassDeathAlert(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	deaths := getIntOption(opts, "deaths", -1)
+	minutes := getIntOption(opts, "minutes", defaultMassDeathMinutes)
+	if deaths == 1 || deaths < 0 || deaths > int(maxMassDeaths) || minutes < int(minMassDeathMinutes) || minutes > int(maxMassDeathMinutes) {
+		respond(s, i, formatting.MsgMassDeathAlertInvalid, true)
+		return
+	}
+
+	var alert domain.MassDeathAlert
+	if deaths > 0 {
+		alert = domain.MassDeathAlert{Deaths: deaths, Window: time.Duration(minutes) * time.Minute}
+	}
+	if err := h.Service.SetMassDeathAlert(context.Background(), i.GuildID, alert); err != nil {
+		slog.Error("Failed to set mass death alert", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgMassDeathAlertSet(alert), false)
+}
+
+func (h *BotHandler) SetAnnouncements(s DiscordSession, i *discordgo.InteractionCreate) {
+	enabled := getBoolOption(i.ApplicationCommandData().Options, "enabled", true)
+
+	if err := h.Service.SetAnnouncements(context.Background(), i.GuildID, enabled); err != nil {
+		slog.Error("Failed to set announcements", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgAnnouncementsSet(enabled), false)
+}
+
+func (h *BotHandler) SetTimezone(s DiscordSession, i *discordgo.InteractionCreate) {
+	timezone, err := h.Service.SetTimezone(context.Background(), i.GuildID, getStringOption(i.ApplicationCommandData().Options, "timezone"))
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		respond(s, i, formatting.MsgTimezoneInvalid, true)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to set timezone", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgTimezoneSet(timezone), false)
+}
+
+func (h *BotHandler) SetQuietHours(s DiscordSession, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	opts := i.ApplicationCommandData().Options
+	quiet, err := h.Service.SetQuietHours(ctx, i.GuildID, getStringOption(opts, "window"), getBoolOption(opts, "catch-up", true))
+	if errors.Is(err, services.ErrInvalidQuietHours) {
+		respond(s, i, formatting.MsgQuietHoursInvalid, true)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to set quiet hours", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	var timezone string
+	if cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID); err == nil && cfg != nil {
+		timezone = cfg.Timezone
+	}
+	respond(s, i, formatting.MsgQuietHoursSet(quiet, timezone), false)
+}
+
+func (h *BotHandler) SetTemplate(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	kind := domain.NotificationChannel(getStringOption(opts, "type"))
+	template := strings.TrimSpace(getStringOption(opts, "template"))
+
+	if err := formatting.ValidateTemplate(kind, template); err != nil {
+		respond(s, i, formatting.MsgTemplateInvalid(err), true)
+		return
+	}
+
+	if template != "" {
+		cfg, err := h.capabilityConfig(context.Background(), i.GuildID)
+		if err != nil {
+			slog.Error("Failed to get guild config", "guild_id", i.GuildID, "error", err)
+			respond(s, i, formatting.MsgSaveError, true)
+			return
+		}
+		if !h.Capabilities.Has(cfg, services.CapabilityCustomTemplates) {
+			respond(s, i, formatting.MsgTemplatesPremium, true)
+			return
+		}
+	}
+
+	if err := h.Service.SetTemplate(context.Background(), i.GuildID, kind, template); err != nil {
+		slog.Error("Failed to set template", "guild_id", i.GuildID, "type", kind, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	if template == "" {
+		respond(s, i, formatting.MsgTemplateReset(string(kind)), false)
+		return
+	}
+	respond(s, i, formatting.MsgTemplateSet(string(kind), formatting.TemplatePreview(kind, template)), false)
+}
+
+func (h *BotHandler) SetEmoji(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	kind := domain.NotificationChannel(getStringOption(opts, "type"))
+	emoji := strings.TrimSpace(getStringOption(opts, "emoji"))
+	react := getBoolOption(opts, "react", false)
+
+	if kind != domain.ChannelDeaths && kind != domain.ChannelLevels {
+		respond(s, i, formatting.MsgChannelInvalid, true)
+		return
+	}
+	if formatting.ValidateEmoji(emoji) != nil {
+		respond(s, i, formatting.MsgEmojiInvalid, true)
+		return
+	}
+
+	if err := h.Service.SetEmoji(context.Background(), i.GuildID, kind, emoji, react); err != nil {
+		slog.Error("Failed to set emoji", "guild_id", i.GuildID, "type", kind, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgEmojiSet(string(kind), emoji, react), false)
+}
+
+func (h *BotHandler) RouteDeaths(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	minLevel := getIntOption(opts, "min-level", -1)
+	if minLevel < 0 {
+		respond(s, i, formatting.MsgMinLevelInvalid, true)
+		return
+	}
+
+	ctx := context.Background()
+	channelID := getChannelOption(opts, "channel")
+	if channelID == "" {
+		removed, err := h.Service.RemoveDeathRoute(ctx, i.GuildID, minLevel)
+		if err != nil {
+			slog.Error("Failed to remove death route", "guild_id", i.GuildID, "min_level", minLevel, "error", err)
+			respond(s, i, formatting.MsgSaveError, true)
+			return
+		}
+		respond(s, i, formatting.MsgDeathRouteRemoved(minLevel, removed), !removed)
+		return
+	}
+
+	if ch := resolvedChannel(i, channelID); ch != nil && ch.IsThread() && ch.ThreadMetadata != nil && ch.ThreadMetadata.Locked {
+		respond(s, i, formatting.MsgThreadLocked, true)
+		return
+	}
+
+	err := h.Service.SetDeathRoute(ctx, i.GuildID, minLevel, channelID)
+	switch {
+	case errors.Is(err, services.ErrNoWorldTracked):
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+	case errors.Is(err, services.ErrTooManyDeathRoutes):
+		respond(s, i, formatting.MsgTooManyDeathRoutes, true)
+	case err != nil:
+		slog.Error("Failed to set death route", "guild_id", i.GuildID, "min_level", minLevel, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+	default:
+		respond(s, i, formatting.MsgDeathRouteSet(minLevel, channelID), false)
+	}
+}
+
+func (h *BotHandler) TrackHouses(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	channelID := ""
+	if getBoolOption(opts, "enabled", true) {
+		channelID = getChannelOption(opts, "channel")
+		if channelID == "" {
+			channelID = i.ChannelID
+		}
+	}
+
+	if err := h.Service.SetChannel(context.Background(), i.GuildID, domain.ChannelHouses, channelID); err != nil {
+		slog.Error("Failed to set house channel", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgTrackHouses(channelID), false)
+}
+
+func (h *BotHandler) TrackSkills(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	channelID := ""
+	if getBoolOption(opts, "enabled", true) {
+		channelID = getChannelOption(opts, "channel")
+		if channelID == "" {
+			channelID = i.ChannelID
+		}
+	}
+	if ch := resolvedChannel(i, channelID); ch != nil && ch.IsThread() && ch.ThreadMetadata != nil && ch.ThreadMetadata.Locked {
+		respond(s, i, formatting.MsgThreadLocked, true)
+		return
+	}
+
+	if err := h.Service.SetChannel(context.Background(), i.GuildID, domain.ChannelSkills, channelID); err != nil {
+		slog.Error("Failed to set skill channel", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgTrackSkills(channelID), false)
+}
+
+func (h *BotHandler) WatchPlayer(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handlePlayerAutocomplete(s, i)
+		return
+	}
+
+	name := getStringOption(i.ApplicationCommandData().Options, "name")
+	if strings.TrimSpace(name) == "" {
+		respond(s, i, formatting.MsgPlayerNameRequired, true)
+		return
+	}
+
+	// The character is looked up on TibiaData, so the reply is deferred.
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		watched, err := h.Service.WatchPlayer(ctx, i.GuildID, name)
+		var worldErr *services.PlayerWorldError
+		var limitErr *services.LimitError
+		switch {
+		case errors.Is(err, services.ErrNoWorldTracked):
+			return formatting.MsgWorldNotTracked
+		case errors.As(err, &limitErr):
+			return limitMessage(limitErr)
+		case errors.As(err, &worldErr):
+			return formatting.MsgPlayerOtherWorld(worldErr.Player, worldErr.World, worldErr.TrackedWorld)
+		case errors.Is(err, domain.ErrNotFound):
+			return formatting.MsgCharacterNotFound(strings.TrimSpace(name))
+		case lookupFailed(err):
+			slog.Error("Failed to look up character", "name", name, "error", err)
+			return formatting.MsgCharacterLookupError
+		case err != nil:
+			slog.Error("Failed to watch player", "guild_id", i.GuildID, "error", err)
+			return formatting.MsgSaveError
+		}
+		return formatting.MsgPlayerWatched(watched)
+	})
+}
+
+func (h *BotHandler) UnwatchPlayer(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handleWatchedAutocomplete(s, i)
+		return
+	}
+
+	name := getStringOption(i.ApplicationCommandData().Options, "name")
+	if strings.TrimSpace(name) == "" {
+		respond(s, i, formatting.MsgPlayerNameRequired, true)
+		return
+	}
+
+	if err := h.Service.UnwatchPlayer(context.Background(), i.GuildID, name); err != nil {
+		slog.Error("Failed to unwatch player", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+		return
+	}
+
+	respond(s, i, formatting.MsgPlayerUnwatched(name), false)
+}
+
+func (h *BotHandler) handleWatchedAutocomplete(s DiscordSession, i *discordgo.InteractionCreate) {
+	query := getFocusedOption(i.ApplicationCommandData().Options)
+
+	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to fetch guild config for autocomplete", "error", err)
+		return
+	}
+
+	var watched []string
+	if cfg != nil {
+		watched = cfg.WatchedPlayers
+	}
+	if err := respondAutocomplete(s, i, buildChoices(watched, query)); err != nil {
+		slog.Error("Failed to send autocomplete response", "error", err)
+	}
+}
+
+func (h *BotHandler) SetSkillThreshold(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	skill := getStringOption(opts, "skill")
+	if !domain.IsSkill(skill) {
+		respond(s, i, formatting.MsgSkillInvalid, true)
+		return
+	}
+	skillName := formatting.CatalogFor(formatting.DefaultLanguage).SkillName(domain.Skill(skill))
+
+	ctx := context.Background()
+	minValue := getIntOption(opts, "min-value", 0)
+	if minValue <= 0 {
+		removed, err := h.Service.RemoveSkillThreshold(ctx, i.GuildID, domain.Skill(skill))
+		if err != nil {
+			slog.Error("Failed to remove skill threshold", "guild_id", i.GuildID, "skill", skill, "error", err)
+			respond(s, i, formatting.MsgSaveError, true)
+			return
+		}
+		respond(s, i, formatting.MsgSkillThresholdRemoved(skillName, removed), !removed)
+		return
+	}
+
+	err := h.Service.SetSkillThreshold(ctx, i.GuildID, domain.Skill(skill), minValue)
+	switch {
+	case errors.Is(err, services.ErrNoWorldTracked):
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+	case err != nil:
+		slog.Error("Failed to set skill threshold", "guild_id", i.GuildID, "skill", skill, "error", err)
+		respond(s, i, formatting.MsgSaveError, true)
+	default:
+		respond(s, i, formatting.MsgSkillThresholdSet(skillName, minValue), false)
+	}
+}
+
+func (h *BotHandler) Rashid(s DiscordSession, i *discordgo.InteractionCreate) {
+	now := time.Now()
+	next := domain.NextServerSave(now)
+	respond(s, i, formatting.MsgRashid(domain.RashidCity(now), domain.RashidCity(next), next), false)
+}
+
+func (h *BotHandler) DeathsToday(s DiscordSession, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	counts, err := h.Stats.DeathsToday(ctx, cfg.World)
+	if err != nil {
+		slog.Error("Failed to get today's deaths", "world", cfg.World, "error", err)
+		respond(s, i, formatting.MsgStatsError, true)
+		return
+	}
+
+	h.Pages.Respond(s, i, formatting.MsgDeathsToday(cfg.World, counts), false)
+}
+
+// Frags ranks the tracked characters by their frags since server save, or
+// shows one character's frags and deaths.
+func (h *BotHandler) Frags(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handlePlayerAutocomplete(s, i)
+		return
+	}
+
+	ctx := context.Background()
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	name := strings.TrimSpace(getStringOption(i.ApplicationCommandData().Options, "player"))
+	counts, err := h.Stats.Frags(ctx, *cfg, name)
+	if err != nil {
+		slog.Error("Failed to get frags", "world", cfg.World, "player", name, "error", err)
+		respond(s, i, formatting.MsgStatsError, true)
+		return
+	}
+
+	if name == "" {
+		h.Pages.Respond(s, i, formatting.MsgFrags(cfg.World, counts), false)
+		return
+	}
+	count := domain.FragCount{Name: domain.NormalizeName(name)}
+	if len(counts) > 0 {
+		count = counts[0]
+	}
+	respond(s, i, formatting.MsgPlayerFrags(count), false)
+}
+
+// statsWindow is a time range offered by leaderboard commands.
+type statsWindow struct {
+	value  string
+	label  string
+	length time.Duration
+}
+
+// topKillersWindows are the /top-killers window choices; the first is the
+// default.
+var topKillersWindows = []statsWindow{
+	{"7d", "Last 7 days", 7 * 24 * time.Hour},
+	{"24h", "Last 24 hours", 24 * time.Hour},
+	{"30d", "Last 30 days", 30 * 24 * time.Hour},
+}
+
+func findStatsWindow(windows []statsWindow, value string) statsWindow {
+	for _, w := range windows {
+		if w.value == value {
+			return w
+		}
+	}
+	return windows[0]
+}
+
+func (h *BotHandler) TopKillers(s DiscordSession, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	window := findStatsWindow(topKillersWindows, getStringOption(i.ApplicationCommandData().Options, "window"))
+	killers, err := h.Stats.TopKillers(ctx, *cfg, window.length)
+	if err != nil {
+		slog.Error("Failed to get top killers", "world", cfg.World, "error", err)
+		respond(s, i, formatting.MsgStatsError, true)
+		return
+	}
+
+	respondEmbed(s, i, formatting.TopKillersEmbed(cfg.World, window.label, killers), false)
+}
+
+// Activity charts when the tracked characters are most online.
+func (h *BotHandler) Activity(s DiscordSession, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	heatmap, err := h.Stats.Activity(ctx, *cfg)
+	if err != nil {
+		slog.Error("Failed to get activity", "world", cfg.World, "error", err)
+		respond(s, i, formatting.MsgStatsError, true)
+		return
+	}
+
+	respondEmbed(s, i, formatting.ActivityEmbed(cfg.World, len(cfg.TibiaGuilds) > 0, heatmap, cfg.Timezone), false)
+}
+
+// exportWindows are the /export window choices; the first is the default.
+var exportWindows = []statsWindow{
+	{"7d", "Last 7 days", 7 * 24 * time.Hour},
+	{"24h", "Last 24 hours", 24 * time.Hour},
+	{"30d", "Last 30 days", 30 * 24 * time.Hour},
+	{"all", "Everything recorded", 0},
+}
+
+// Export uploads the guild's deaths or level ups as a CSV or JSON file. Large
+// exports take a while to page through storage, so the reply is deferred.
+func (h *BotHandler) Export(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	kind := domain.NotificationChannel(getStringOption(opts, "type"))
+	if kind != domain.ChannelDeaths && kind != domain.ChannelLevels {
+		respond(s, i, formatting.MsgChannelInvalid, true)
+		return
+	}
+	format := services.ExportFormat(getStringOption(opts, "format"))
+	if format == "" {
+		format = services.ExportCSV
+	}
+	window := findStatsWindow(exportWindows, getStringOption(opts, "window"))
+
+	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	if err := deferResponse(s, i, false); err != nil {
+		slog.Warn("Failed to defer response", "command", interactionName(i), "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deferredTimeout)
+	defer cancel()
+
+	var since time.Time
+	if window.length > 0 {
+		since = time.Now().Add(-window.length)
+	}
+	var buf bytes.Buffer
+	result, err := h.Exports.Export(ctx, *cfg, kind, format, since, &buf)
+	if err != nil {
+		slog.Error("Failed to export", "guild_id", i.GuildID, "type", kind, "format", format, "error", err)
+		editResponse(s, i, formatting.MsgExportError)
+		return
+	}
+	if result.Rows == 0 {
+		editResponse(s, i, formatting.MsgExportEmpty(string(kind), window.label))
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.%s", kind, strings.ToLower(cfg.World), window.value, format)
+	editResponseFile(s, i, formatting.MsgExport(string(kind), window.label, result.Rows, result.Truncated), &discordgo.File{
+		Name:        name,
+		ContentType: exportContentType(format),
+		Reader:      &buf,
+	})
+}
+
+func exportContentType(format services.ExportFormat) string {
+	if format == services.ExportJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// Compare races two characters' levels. Both are looked up on TibiaData, so
+// the reply is deferred.
+func (h *BotHandler) Compare(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handlePlayerAutocomplete(s, i)
+		return
+	}
+
+	opts := i.ApplicationCommandData().Options
+	first := strings.TrimSpace(getStringOption(opts, "player1"))
+	second := strings.TrimSpace(getStringOption(opts, "player2"))
+	if first == "" || second == "" || domain.SameName(first, second) {
+		respond(s, i, formatting.MsgCompareNamesInvalid, true)
+		return
+	}
+
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		a, err := h.Stats.LevelPace(ctx, first)
+		if err != nil {
+			slog.Error("Failed to get level pace", "name", first, "error", err)
+			return formatting.MsgCompareError
+		}
+		b, err := h.Stats.LevelPace(ctx, second)
+		if err != nil {
+			slog.Error("Failed to get level pace", "name", second, "error", err)
+			return formatting.MsgCompareError
+		}
+
+		crossover, ok := domain.ProjectCrossover(a, b, time.Now())
+		return formatting.MsgCompare(a, b, crossover, ok)
+	})
+}
+
+// WarScore tallies the kills between a tracked guild and an opponent during
+// the tracked guild's last war. Both member lists are looked up on TibiaData,
+// so the reply is deferred.
+func (h *BotHandler) WarScore(s DiscordSession, i *discordgo.InteractionCreate) {
+	opts := i.ApplicationCommandData().Options
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		if getFocusedOptionName(opts) == "guild" {
+			h.handleGuildAutocomplete(s, i)
+		} else {
+			h.handleWorldGuildAutocomplete(s, i)
+		}
+		return
+	}
+
+	opponent := strings.TrimSpace(getStringOption(opts, "opponent"))
+	if opponent == "" {
+		respond(s, i, formatting.MsgGuildNameRequired, true)
+		return
+	}
+
+	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config", "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+	if cfg == nil || cfg.World == "" {
+		respond(s, i, formatting.MsgWorldNotTracked, true)
+		return
+	}
+
+	guildName, msg := warGuild(cfg.TibiaGuilds, strings.TrimSpace(getStringOption(opts, "guild")))
+	if msg != "" {
+		respond(s, i, msg, true)
+		return
+	}
+
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		score, err := h.Stats.WarScore(ctx, *cfg, guildName, opponent)
+		if errors.Is(err, services.ErrNoGuildWar) {
+			return formatting.MsgNoGuildWar(guildName)
+		}
+		if err != nil {
+			slog.Error("Failed to get war score", "guild", guildName, "opponent", opponent, "error", err)
+			return formatting.MsgWarScoreError
+		}
+		return formatting.MsgWarScore(guildName, score)
+	})
+}
+
+// warGuild picks the tracked guild /war-score scores: the one named, or the
+// only one tracked. The message explains why there is none.
+func warGuild(tracked []string, name string) (string, string) {
+	if name == "" {
+		switch len(tracked) {
+		case 0:
+			return "", formatting.MsgNoGuildsTracked
+		case 1:
+			return tracked[0], ""
+		}
+		return "", formatting.MsgWarGuildRequired
+	}
+	for _, g := range tracked {
+		if domain.SameName(g, name) {
+			return g, ""
+		}
+	}
+	return "", formatting.MsgWarGuildNotTracked(name)
+}
+
+// paceProjectionDays is how far ahead /pace projects a character's level.
+const paceProjectionDays = 30
+
+// Pace estimates a character's levels per day and projects its level. The
+// character is looked up on TibiaData, so the reply is deferred.
+func (h *BotHandler) Pace(s DiscordSession, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		h.handlePlayerAutocomplete(s, i)
+		return
+	}
+
+	name := strings.TrimSpace(getStringOption(i.ApplicationCommandData().Options, "player"))
+	if name == "" {
+		respond(s, i, formatting.MsgPlayerNameRequired, true)
+		return
+	}
+
+	respondDeferred(s, i, false, func(ctx context.Context) string {
+		forecast, err := h.Stats.LevelForecast(ctx, name)
+		if err != nil {
+			slog.Error("Failed to get level forecast", "name", name, "error", err)
+			return formatting.MsgPaceError
+		}
+		return formatting.MsgPace(forecast, paceProjectionDays)
+	})
+}
+
+func (h *BotHandler) RetryFailed(s DiscordSession, i *discordgo.InteractionCreate) {
+	respondDeferred(s, i, true, func(ctx context.Context) string {
+		result, err := h.Retries.Flush(ctx, i.GuildID)
+		if err != nil {
+			slog.Error("Failed to retry notifications", "guild_id", i.GuildID, "error", err)
+			return formatting.MsgRetryError
+		}
+		return formatting.MsgRetryFailed(result.Sent, result.Failed, result.Dropped)
+	})
+}
+
+func (h *BotHandler) CheckPermissions(s DiscordSession, i *discordgo.InteractionCreate) {
+	respondDeferred(s, i, true, func(ctx context.Context) string {
+		cfg, err := h.Service.GetGuildConfig(ctx, i.GuildID)
+		if err != nil {
+			slog.Error("Failed to get guild config", "error", err)
+			return formatting.MsgConfigError
+		}
+
+		missing := missingPermissions(i.AppPermissions, guildPermissions)
+		for _, channelID := range notificationChannelIDs(s, cfg, i.GuildID, h.Config.DiscordChannelDeath, h.Config.DiscordChannelLevel) {
+			missing = append(missing, auditChannel(s, i.AppID, channelID)...)
+		}
+
+		if len(missing) == 0 {
+			return formatting.MsgPermissionsOK
+		}
+		return formatting.MsgMissingPermissions(missing)
+	})
+}
+
+// TrackStatus is deferred because the world's PvP type may come from
+// TibiaData.
+func (h *BotHandler) TrackStatus(s DiscordSession, i *discordgo.InteractionCreate) {
+	respondDeferred(s, i, true, func(ctx context.Context) string {
+		status, err := h.Service.TrackStatus(ctx, i.GuildID)
+		if err != nil {
+			slog.Error("Failed to get track status", "guild_id", i.GuildID, "error", err)
+			return formatting.MsgConfigError
+		}
+		if status == nil {
+			return formatting.MsgWorldNotTracked
+		}
+		return formatting.MsgTrackStatus(*status, h.Config.MinLevelTrack, h.Config.DiscordChannelDeath, h.Config.DiscordChannelLevel, time.Now())
+	})
+}
+
+// Help lists the registered commands together with the guild's setup and
+// which optional features are on.
+func (h *BotHandler) Help(s DiscordSession, i *discordgo.InteractionCreate) {
+	cfg, err := h.Service.GetGuildConfig(context.Background(), i.GuildID)
+	if err != nil {
+		slog.Error("Failed to get guild config for help", "guild_id", i.GuildID, "error", err)
+		respond(s, i, formatting.MsgConfigError, true)
+		return
+	}
+
+	// Servers without a configuration, where cfg is nil, still get the
+	// command list.
+	respondEmbed(s, i, formatting.HelpEmbed(h.helpCommands(), cfg, h.Config.MinLevelTrack, h.Config.DiscordChannelDeath, h.Config.DiscordChannelLevel), true)
+}
+
+// helpCommands returns the definitions of the registered commands in
+// registry order.
+func (h *BotHandler) helpCommands() []formatting.HelpCommand {
+	var registered map[string]bool
+	if h.Commands != nil {
+		registered = make(map[string]bool)
+		for _, name := range h.Commands() {
+			registered[name] = true
+		}
+	}
+
+	var cmds []formatting.HelpCommand
+	for _, cmd := range GetApplicationCommands() {
+		if registered == nil || registered[cmd.Name] {
+			cmds = append(cmds, formatting.HelpCommand{Name: cmd.Name, Description: cmd.Description})
+		}
+	}
+	return cmds
+}
+
+func buildGuildChoices(cfg *domain.GuildConfig, query string) []*discordgo.ApplicationCommandOptionChoice {
+	if cfg == nil {
+		return nil
+	}
+	return buildChoices(cfg.TibiaGuilds, query)
+}
+
+// buildChoices returns up to 25 values containing query, Discord's limit for
+// autocomplete results.
+// nameChoices offers every name, already matched by the caller.
+func nameChoices(names []string) []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(names))
+	for _, name := range names {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+	return choices
+}
+
+func buildChoices(values []string, query string) []*discordgo.ApplicationCommandOptionChoice {
 	var choices []*discordgo.ApplicationCommandOptionChoice
-	for _, g := range cfg.TibiaGuilds {
-		if strings.Contains(strings.ToLower(g), strings.ToLower(query)) {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), strings.ToLower(query)) {
 			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
-				Name:  g,
-				Value: g,
+				Name:  v,
+				Value: v,
 			})
 		}
 		if len(choices) >= 25 {
@@ -3,23 +3,86 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"death-level-tracker/internal/adapters/discord/formatting"
 	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
 	"death-level-tracker/internal/core/services"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 type mockStorage struct {
-	saveGuildWorldFunc        func(ctx context.Context, guildID, world string) error
-	deleteGuildConfigFunc     func(ctx context.Context, guildID string) error
-	getGuildConfigFunc        func(ctx context.Context, guildID string) (*domain.GuildConfig, error)
-	addGuildToConfigFunc      func(ctx context.Context, guildID, tibiaGuild string) error
-	removeGuildFromConfigFunc func(ctx context.Context, guildID, tibiaGuild string) error
+	saveGuildWorldFunc              func(ctx context.Context, guildID, world string) error
+	deleteGuildConfigFunc           func(ctx context.Context, guildID string) error
+	getGuildConfigFunc              func(ctx context.Context, guildID string) (*domain.GuildConfig, error)
+	addGuildToConfigFunc            func(ctx context.Context, guildID, tibiaGuild string) error
+	removeGuildFromConfigFunc       func(ctx context.Context, guildID, tibiaGuild string) error
+	setGuildLanguageFunc            func(ctx context.Context, guildID, language string) error
+	setGuildChannelFunc             func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error
+	setGuildPingRoleFunc            func(ctx context.Context, guildID, roleID string, minLevel int) error
+	getDeathCountsSinceFunc         func(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error)
+	setGuildPollIntervalFunc        func(ctx context.Context, guildID string, interval time.Duration) error
+	getGuildFailedNotificationsFunc func(ctx context.Context, guildID string) ([]domain.FailedNotification, error)
+	setGuildMutedUntilFunc          func(ctx context.Context, guildID string, until time.Time) error
+	addIgnoredPlayerFunc            func(ctx context.Context, guildID, name string) error
+	removeIgnoredPlayerFunc         func(ctx context.Context, guildID, name string) error
+	setGuildLowLevelDeathsFunc      func(ctx context.Context, guildID string, enabled bool) error
+	setGuildTimezoneFunc            func(ctx context.Context, guildID, timezone string) error
+	setGuildTemplateFunc            func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error
+	setGuildEmojiFunc               func(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error
+	setDeathRouteFunc               func(ctx context.Context, guildID string, minLevel int, channelID string) error
+	deleteDeathRouteFunc            func(ctx context.Context, guildID string, minLevel int) (bool, error)
+	setGuildQuietHoursFunc          func(ctx context.Context, guildID string, quiet domain.QuietHours) error
+	getDeathsPageFunc               func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error)
+	getLevelUpsPageFunc             func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error)
+	setGuildLastNotifiedFunc        func(ctx context.Context, guildID string, at time.Time) error
+	markGuildRemovedFunc            func(ctx context.Context, guildID string, at time.Time) error
+	restoreGuildConfigFunc          func(ctx context.Context, guildID string) (bool, error)
+	deleteRemovedGuildConfigsFunc   func(ctx context.Context, removedBefore time.Time) (int64, error)
+	purgeGuildDataFunc              func(ctx context.Context, guildID string) (domain.PurgeResult, error)
+	deleteDeathsBeforeFunc          func(ctx context.Context, diedBefore time.Time) (int64, error)
+	getTopKillersSinceFunc          func(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error)
+	recordLevelUpFunc               func(ctx context.Context, levelUp domain.LevelUp) error
+	getLevelUpsSinceFunc            func(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error)
+	getDailyLevelGainsFunc          func(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error)
+	deleteLevelUpsBeforeFunc        func(ctx context.Context, reachedBefore time.Time) (int64, error)
+	getHouseAuctionsFunc            func(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error)
+	replaceHouseAuctionsFunc        func(ctx context.Context, world string, auctions []domain.HouseAuction) error
+	setGuildQuotaFunc               func(ctx context.Context, guildID string, quota domain.Quota) error
+	setGuildPremiumFunc             func(ctx context.Context, guildID string, premium bool) error
+	setGuildShareRangeFunc          func(ctx context.Context, guildID string, enabled bool) error
+	addWatchedPlayerFunc            func(ctx context.Context, guildID, name string) error
+	removeWatchedPlayerFunc         func(ctx context.Context, guildID, name string) error
+	setSkillThresholdFunc           func(ctx context.Context, guildID string, skill domain.Skill, minValue int) error
+	deleteSkillThresholdFunc        func(ctx context.Context, guildID string, skill domain.Skill) (bool, error)
+	setGuildMassDeathAlertFunc      func(ctx context.Context, guildID string, alert domain.MassDeathAlert) error
+	setGuildBroadcastOptOutFunc     func(ctx context.Context, guildID string, optOut bool) error
+	setGuildMinLevelFunc            func(ctx context.Context, guildID string, level int) error
+	getPlayersByPrefixFunc          func(ctx context.Context, world, prefix string) ([]string, error)
+	commits, rollbacks              int
+	saveStoppedGuildConfigFunc      func(ctx context.Context, stopped domain.StoppedGuildConfig) error
+	getStoppedGuildConfigFunc       func(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error)
+	deleteStoppedGuildConfigFunc    func(ctx context.Context, guildID string) error
+	getOnlineCountsSinceFunc        func(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error)
+	getWorldOnlineCountsSinceFunc   func(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error)
+	getOnlineTimeSinceFunc          func(ctx context.Context, name string, since time.Time) (time.Duration, error)
+	setGuildDeathLocationFunc       func(ctx context.Context, guildID string, enabled bool) error
+	setGuildLevelDownsFunc          func(ctx context.Context, guildID string, enabled bool) error
+	setGuildResponseVisibilityFunc  func(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error
+	setGuildDeathVerbosityFunc      func(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error
+	getGuildWarFunc                 func(ctx context.Context, guildName string) (*domain.GuildWar, error)
+	startGuildWarFunc               func(ctx context.Context, guildName string, startedAt time.Time) error
+	endGuildWarFunc                 func(ctx context.Context, guildName string, endedAt time.Time) error
+	countKillsBetweenFunc           func(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error)
+	getFragCountsSinceFunc          func(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error)
 }
 
 func (m *mockStorage) SaveGuildWorld(ctx context.Context, guildID, world string) error {
@@ -81,539 +144,3829 @@ func (m *mockStorage) GetOfflinePlayers(ctx context.Context, world string, onlin
 	return nil, nil
 }
 
-func (m *mockStorage) Close() {}
+func (m *mockStorage) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	return nil
+}
 
-type mockDiscordSession struct {
-	guildChannelsFunc      func(guildID string) ([]*discordgo.Channel, error)
-	guildChannelCreateFunc func(guildID, name string, ctype discordgo.ChannelType) (*discordgo.Channel, error)
-	interactionRespondFunc func(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error
+func (m *mockStorage) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	if m.setGuildLanguageFunc != nil {
+		return m.setGuildLanguageFunc(ctx, guildID, language)
+	}
+	return nil
+}
 
-	lastInteractionResponse *discordgo.InteractionResponse
+func (m *mockStorage) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	if m.setGuildChannelFunc != nil {
+		return m.setGuildChannelFunc(ctx, guildID, kind, channelID)
+	}
+	return nil
 }
 
-func (m *mockDiscordSession) GuildChannels(guildID string, opts ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
-	if m.guildChannelsFunc != nil {
-		return m.guildChannelsFunc(guildID)
+func (m *mockStorage) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	if m.setGuildPingRoleFunc != nil {
+		return m.setGuildPingRoleFunc(ctx, guildID, roleID, minLevel)
 	}
-	return []*discordgo.Channel{}, nil
+	return nil
 }
 
-func (m *mockDiscordSession) GuildChannelCreate(guildID, name string, ctype discordgo.ChannelType, opts ...discordgo.RequestOption) (*discordgo.Channel, error) {
-	if m.guildChannelCreateFunc != nil {
-		return m.guildChannelCreateFunc(guildID, name, ctype)
+func (m *mockStorage) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	return nil
+}
+
+func (m *mockStorage) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	if m.getDeathCountsSinceFunc != nil {
+		return m.getDeathCountsSinceFunc(ctx, world, since)
 	}
-	return &discordgo.Channel{ID: "mock-id", Name: name, Type: ctype}, nil
+	return nil, nil
 }
 
-func (m *mockDiscordSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, opts ...discordgo.RequestOption) error {
-	m.lastInteractionResponse = resp
-	if m.interactionRespondFunc != nil {
-		return m.interactionRespondFunc(interaction, resp)
+func (m *mockStorage) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	if m.setGuildPollIntervalFunc != nil {
+		return m.setGuildPollIntervalFunc(ctx, guildID, interval)
 	}
 	return nil
 }
 
-func newTestHandler(storage *mockStorage) *BotHandler {
-	return &BotHandler{
-		Config: &config.Config{
-			DiscordChannelDeath: "death-tracker",
-			DiscordChannelLevel: "level-tracker",
+func (m *mockStorage) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	return nil
+}
+
+func (m *mockStorage) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetGuildFailedNotifications(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+	if m.getGuildFailedNotificationsFunc != nil {
+		return m.getGuildFailedNotificationsFunc(ctx, guildID)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	return nil
+}
+
+func (m *mockStorage) DeleteFailedNotification(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (m *mockStorage) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	if m.setGuildMutedUntilFunc != nil {
+		return m.setGuildMutedUntilFunc(ctx, guildID, until)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+
+func (m *mockStorage) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+
+func (m *mockStorage) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	return nil
+}
+
+func (m *mockStorage) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	if m.addIgnoredPlayerFunc != nil {
+		return m.addIgnoredPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockStorage) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	if m.removeIgnoredPlayerFunc != nil {
+		return m.removeIgnoredPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildLowLevelDeathsFunc != nil {
+		return m.setGuildLowLevelDeathsFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	if m.setGuildTimezoneFunc != nil {
+		return m.setGuildTimezoneFunc(ctx, guildID, timezone)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	if m.setGuildTemplateFunc != nil {
+		return m.setGuildTemplateFunc(ctx, guildID, kind, template)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	if m.setGuildEmojiFunc != nil {
+		return m.setGuildEmojiFunc(ctx, guildID, kind, emoji, react)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	if m.setDeathRouteFunc != nil {
+		return m.setDeathRouteFunc(ctx, guildID, minLevel, channelID)
+	}
+	return nil
+}
+
+func (m *mockStorage) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	if m.deleteDeathRouteFunc != nil {
+		return m.deleteDeathRouteFunc(ctx, guildID, minLevel)
+	}
+	return false, nil
+}
+
+func (m *mockStorage) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	if m.setGuildQuietHoursFunc != nil {
+		return m.setGuildQuietHoursFunc(ctx, guildID, quiet)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	if m.getDeathsPageFunc != nil {
+		return m.getDeathsPageFunc(ctx, world, guildNames, since, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	if m.getLevelUpsPageFunc != nil {
+		return m.getLevelUpsPageFunc(ctx, world, guildNames, since, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	if m.setGuildLastNotifiedFunc != nil {
+		return m.setGuildLastNotifiedFunc(ctx, guildID, at)
+	}
+	return nil
+}
+
+func (m *mockStorage) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	if m.markGuildRemovedFunc != nil {
+		return m.markGuildRemovedFunc(ctx, guildID, at)
+	}
+	return nil
+}
+
+func (m *mockStorage) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	if m.restoreGuildConfigFunc != nil {
+		return m.restoreGuildConfigFunc(ctx, guildID)
+	}
+	return false, nil
+}
+
+func (m *mockStorage) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	if m.deleteRemovedGuildConfigsFunc != nil {
+		return m.deleteRemovedGuildConfigsFunc(ctx, removedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockStorage) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	if m.purgeGuildDataFunc != nil {
+		return m.purgeGuildDataFunc(ctx, guildID)
+	}
+	return domain.PurgeResult{}, nil
+}
+
+func (m *mockStorage) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	if m.deleteDeathsBeforeFunc != nil {
+		return m.deleteDeathsBeforeFunc(ctx, diedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockStorage) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	if m.getTopKillersSinceFunc != nil {
+		return m.getTopKillersSinceFunc(ctx, world, guildNames, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	if m.recordLevelUpFunc != nil {
+		return m.recordLevelUpFunc(ctx, levelUp)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	if m.getLevelUpsSinceFunc != nil {
+		return m.getLevelUpsSinceFunc(ctx, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	if m.getDailyLevelGainsFunc != nil {
+		return m.getDailyLevelGainsFunc(ctx, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	if m.deleteLevelUpsBeforeFunc != nil {
+		return m.deleteLevelUpsBeforeFunc(ctx, reachedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockStorage) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	if m.getHouseAuctionsFunc != nil {
+		return m.getHouseAuctionsFunc(ctx, world, seenSince)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	if m.replaceHouseAuctionsFunc != nil {
+		return m.replaceHouseAuctionsFunc(ctx, world, auctions)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	if m.setGuildQuotaFunc != nil {
+		return m.setGuildQuotaFunc(ctx, guildID, quota)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	if m.setGuildPremiumFunc != nil {
+		return m.setGuildPremiumFunc(ctx, guildID, premium)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildShareRangeFunc != nil {
+		return m.setGuildShareRangeFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockStorage) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	if m.addWatchedPlayerFunc != nil {
+		return m.addWatchedPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockStorage) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	if m.removeWatchedPlayerFunc != nil {
+		return m.removeWatchedPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	if m.setSkillThresholdFunc != nil {
+		return m.setSkillThresholdFunc(ctx, guildID, skill, minValue)
+	}
+	return nil
+}
+
+func (m *mockStorage) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	if m.deleteSkillThresholdFunc != nil {
+		return m.deleteSkillThresholdFunc(ctx, guildID, skill)
+	}
+	return false, nil
+}
+
+func (m *mockStorage) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	return nil
+}
+
+func (m *mockStorage) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	if m.setGuildMassDeathAlertFunc != nil {
+		return m.setGuildMassDeathAlertFunc(ctx, guildID, alert)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	return nil
+}
+
+func (m *mockStorage) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	if m.setGuildBroadcastOptOutFunc != nil {
+		return m.setGuildBroadcastOptOutFunc(ctx, guildID, optOut)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	if m.setGuildMinLevelFunc != nil {
+		return m.setGuildMinLevelFunc(ctx, guildID, level)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	if m.getPlayersByPrefixFunc != nil {
+		return m.getPlayersByPrefixFunc(ctx, world, prefix)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	return &mockStorageTx{mockStorage: m}, nil
+}
+
+func (m *mockStorage) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	if m.saveStoppedGuildConfigFunc != nil {
+		return m.saveStoppedGuildConfigFunc(ctx, stopped)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	if m.getStoppedGuildConfigFunc != nil {
+		return m.getStoppedGuildConfigFunc(ctx, guildID)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	if m.deleteStoppedGuildConfigFunc != nil {
+		return m.deleteStoppedGuildConfigFunc(ctx, guildID)
+	}
+	return nil
+}
+
+func (m *mockStorage) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	return nil
+}
+
+func (m *mockStorage) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	if m.getOnlineCountsSinceFunc != nil {
+		return m.getOnlineCountsSinceFunc(ctx, world, guildNames, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	return nil
+}
+
+func (m *mockStorage) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	if m.getWorldOnlineCountsSinceFunc != nil {
+		return m.getWorldOnlineCountsSinceFunc(ctx, world, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	return nil
+}
+
+func (m *mockStorage) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	if m.getOnlineTimeSinceFunc != nil {
+		return m.getOnlineTimeSinceFunc(ctx, name, since)
+	}
+	return 0, nil
+}
+
+func (m *mockStorage) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildDeathLocationFunc != nil {
+		return m.setGuildDeathLocationFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildLevelDownsFunc != nil {
+		return m.setGuildLevelDownsFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	if m.setGuildResponseVisibilityFunc != nil {
+		return m.setGuildResponseVisibilityFunc(ctx, guildID, visibility)
+	}
+	return nil
+}
+
+func (m *mockStorage) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	if m.setGuildDeathVerbosityFunc != nil {
+		return m.setGuildDeathVerbosityFunc(ctx, guildID, verbosity)
+	}
+	return nil
+}
+
+func (m *mockStorage) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	if m.getGuildWarFunc != nil {
+		return m.getGuildWarFunc(ctx, guildName)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	if m.startGuildWarFunc != nil {
+		return m.startGuildWarFunc(ctx, guildName, startedAt)
+	}
+	return nil
+}
+
+func (m *mockStorage) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	if m.endGuildWarFunc != nil {
+		return m.endGuildWarFunc(ctx, guildName, endedAt)
+	}
+	return nil
+}
+
+func (m *mockStorage) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	if m.countKillsBetweenFunc != nil {
+		return m.countKillsBetweenFunc(ctx, world, victims, killers, since, until)
+	}
+	return 0, nil
+}
+
+func (m *mockStorage) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	if m.getFragCountsSinceFunc != nil {
+		return m.getFragCountsSinceFunc(ctx, world, guildNames, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockStorage) Close() {}
+
+type mockDiscordSession struct {
+	guildChannelsFunc      func(guildID string) ([]*discordgo.Channel, error)
+	guildChannelCreateFunc func(guildID, name string, ctype discordgo.ChannelType) (*discordgo.Channel, error)
+	interactionRespondFunc func(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error
+	channelPermissionsFunc func(userID, channelID string) (int64, error)
+
+	lastInteractionResponse *discordgo.InteractionResponse
+	lastResponseEdit        *discordgo.WebhookEdit
+	lastFollowup            *discordgo.WebhookParams
+}
+
+func (m *mockDiscordSession) GuildChannels(guildID string, opts ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+	if m.guildChannelsFunc != nil {
+		return m.guildChannelsFunc(guildID)
+	}
+	return []*discordgo.Channel{}, nil
+}
+
+func (m *mockDiscordSession) GuildChannelCreate(guildID, name string, ctype discordgo.ChannelType, opts ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.guildChannelCreateFunc != nil {
+		return m.guildChannelCreateFunc(guildID, name, ctype)
+	}
+	return &discordgo.Channel{ID: "mock-id", Name: name, Type: ctype}, nil
+}
+
+func (m *mockDiscordSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, opts ...discordgo.RequestOption) error {
+	m.lastInteractionResponse = resp
+	if m.interactionRespondFunc != nil {
+		return m.interactionRespondFunc(interaction, resp)
+	}
+	return nil
+}
+
+func (m *mockDiscordSession) InteractionResponseEdit(interaction *discordgo.Interaction, edit *discordgo.WebhookEdit, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.lastResponseEdit = edit
+	return &discordgo.Message{ID: "response-message"}, nil
+}
+
+func (m *mockDiscordSession) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.lastFollowup = data
+	return &discordgo.Message{}, nil
+}
+
+// editedContent returns the message a deferred response was edited with.
+func (m *mockDiscordSession) editedContent() string {
+	if m.lastResponseEdit == nil || m.lastResponseEdit.Content == nil {
+		return ""
+	}
+	return *m.lastResponseEdit.Content
+}
+
+func (m *mockDiscordSession) UserChannelPermissions(userID, channelID string, opts ...discordgo.RequestOption) (int64, error) {
+	if m.channelPermissionsFunc != nil {
+		return m.channelPermissionsFunc(userID, channelID)
+	}
+	return discordgo.PermissionViewChannel | discordgo.PermissionSendMessages, nil
+}
+
+func newTestHandler(storage *mockStorage) *BotHandler {
+	return &BotHandler{
+		Config: &config.Config{
+			DiscordChannelDeath: "death-tracker",
+			DiscordChannelLevel: "level-tracker",
+		},
+		Service: services.NewConfigurationService(storage, nil, services.Limits{}, nil),
+		Stats:   services.NewStatsService(storage, nil),
+		Retries: services.NewNotificationQueue(storage, nil, nil, 24*time.Hour),
+		Exports: services.NewExportService(storage),
+		Pages:   NewPaginator(),
+	}
+}
+
+func makeCommandInteraction(guildID, optName, optValue string) *discordgo.InteractionCreate {
+	var opts []*discordgo.ApplicationCommandInteractionDataOption
+	if optName != "" {
+		opts = []*discordgo.ApplicationCommandInteractionDataOption{
+			{Name: optName, Type: discordgo.ApplicationCommandOptionString, Value: optValue},
+		}
+	}
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:           discordgo.InteractionApplicationCommand,
+			GuildID:        guildID,
+			AppID:          "bot-1",
+			AppPermissions: discordgo.PermissionViewChannel | discordgo.PermissionManageChannels | discordgo.PermissionSendMessages,
+			Data:           discordgo.ApplicationCommandInteractionData{Options: opts},
+		},
+	}
+}
+
+func sessionWithChannels(channels ...*discordgo.Channel) *mockDiscordSession {
+	return &mockDiscordSession{
+		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
+			return channels, nil
+		},
+	}
+}
+
+func TestTrackWorld_Success(t *testing.T) {
+	var savedWorld string
+	storage := &mockStorage{
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			savedWorld = world
+			return nil
+		},
+	}
+
+	session := sessionWithChannels(
+		&discordgo.Channel{ID: "1", Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+		&discordgo.Channel{ID: "2", Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
+	)
+
+	handler := newTestHandler(storage)
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+
+	if savedWorld != "Antica" {
+		t.Errorf("expected world 'Antica', got '%s'", savedWorld)
+	}
+	if storage.commits != 1 {
+		t.Errorf("expected the world committed once, got %d commits", storage.commits)
+	}
+	if session.lastInteractionResponse.Data.Flags != 0 {
+		t.Error("expected non-ephemeral success message")
+	}
+}
+
+func TestTrackWorld_MissingWorldName(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldRequired {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgWorldRequired, session.lastInteractionResponse.Data.Content)
+	}
+	if session.lastInteractionResponse.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Error("expected ephemeral error message")
+	}
+}
+
+func TestTrackWorld_ChannelCreation(t *testing.T) {
+	var created []string
+	storage := &mockStorage{}
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
+			return []*discordgo.Channel{}, nil
+		},
+		guildChannelCreateFunc: func(guildID, name string, ctype discordgo.ChannelType) (*discordgo.Channel, error) {
+			created = append(created, name)
+			return &discordgo.Channel{ID: "new-" + name, Name: name, Type: ctype}, nil
+		},
+	}
+
+	handler := newTestHandler(storage)
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "secura"))
+
+	if len(created) != 2 {
+		t.Fatalf("expected 2 channels created, got %d", len(created))
+	}
+	if created[0] != "death-tracker" || created[1] != "level-tracker" {
+		t.Errorf("expected [death-tracker, level-tracker], got %v", created)
+	}
+}
+
+func TestTrackWorld_ChannelError(t *testing.T) {
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
+			return nil, errors.New("api error")
+		},
+	}
+
+	storage := &mockStorage{}
+	handler := newTestHandler(storage)
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+
+	expected := formatting.MsgChannelError("death-tracker")
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+	if storage.commits != 0 || storage.rollbacks != 1 {
+		t.Errorf("expected the saved world rolled back, got %d commits and %d rollbacks", storage.commits, storage.rollbacks)
+	}
+}
+
+func TestTrackWorld_StorageError(t *testing.T) {
+	storage := &mockStorage{
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := sessionWithChannels(
+		&discordgo.Channel{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+		&discordgo.Channel{Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
+	)
+
+	handler := newTestHandler(storage)
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestTrackWorld_WorldNameFormatting(t *testing.T) {
+	tests := []struct{ input, expected string }{
+		{"antica", "Antica"},
+		{"SECURA", "Secura"},
+		{"beLaBonA", "Belabona"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var saved string
+			storage := &mockStorage{
+				saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+					saved = world
+					return nil
+				},
+			}
+
+			session := sessionWithChannels(
+				&discordgo.Channel{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+				&discordgo.Channel{Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
+			)
+
+			handler := newTestHandler(storage)
+			handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", tt.input))
+
+			if saved != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, saved)
+			}
+		})
+	}
+}
+
+func makeComponentInteraction(guildID, customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionMessageComponent,
+			GuildID: guildID,
+			Data:    discordgo.MessageComponentInteractionData{CustomID: customID},
+		},
+	}
+}
+
+func TestStopTracking_AsksForConfirmation(t *testing.T) {
+	storage := &mockStorage{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			t.Error("config should not be deleted before confirmation")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.StopTracking(session, makeCommandInteraction("guild-123", "", ""))
+
+	data := session.lastInteractionResponse.Data
+	if data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Error("confirmation should be ephemeral")
+	}
+	if len(data.Components) != 1 {
+		t.Fatalf("expected one row of buttons, got %d", len(data.Components))
+	}
+	buttons := data.Components[0].(discordgo.ActionsRow).Components
+	if len(buttons) != 2 {
+		t.Fatalf("expected confirm and cancel buttons, got %d", len(buttons))
+	}
+
+	confirm := buttons[0].(discordgo.Button)
+	route, expiry, _ := strings.Cut(confirm.CustomID, ":")
+	if route != StopTrackingConfirmRoute || confirm.Style != discordgo.DangerButton {
+		t.Errorf("unexpected confirm button: %+v", confirm)
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		t.Fatalf("expected expiry in custom ID, got %q", confirm.CustomID)
+	}
+	if d := time.Until(time.Unix(unix, 0)); d < 25*time.Second || d > confirmTimeout {
+		t.Errorf("expected expiry about 30s away, got %v", d)
+	}
+	if cancel := buttons[1].(discordgo.Button); cancel.CustomID != StopTrackingCancelRoute {
+		t.Errorf("unexpected cancel button: %+v", cancel)
+	}
+}
+
+func TestStopTrackingConfirm_Success(t *testing.T) {
+	var deleted string
+	storage := &mockStorage{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			deleted = guildID
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	handler.StopTrackingConfirm(session, makeComponentInteraction("guild-123", componentID(StopTrackingConfirmRoute, expires)))
+
+	if deleted != "guild-123" {
+		t.Errorf("expected 'guild-123', got '%s'", deleted)
+	}
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != formatting.MsgStopSuccess {
+		t.Errorf("expected prompt to be replaced with '%s', got %+v", formatting.MsgStopSuccess, resp)
+	}
+	if resp.Data.Components == nil || len(resp.Data.Components) != 0 {
+		t.Error("expected buttons to be removed")
+	}
+}
+
+func TestStopTrackingConfirm_Expired(t *testing.T) {
+	storage := &mockStorage{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			t.Error("config should not be deleted after the timeout")
+			return nil
+		},
+	}
+
+	for _, customID := range []string{
+		componentID(StopTrackingConfirmRoute, strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)),
+		componentID(StopTrackingConfirmRoute, "garbage"),
+		componentID(StopTrackingConfirmRoute),
+	} {
+		session := &mockDiscordSession{}
+		handler := newTestHandler(storage)
+		handler.StopTrackingConfirm(session, makeComponentInteraction("guild-1", customID))
+
+		if session.lastInteractionResponse.Data.Content != formatting.MsgConfirmExpired {
+			t.Errorf("%s: expected '%s', got '%s'", customID, formatting.MsgConfirmExpired, session.lastInteractionResponse.Data.Content)
+		}
+	}
+}
+
+func TestStopTrackingConfirm_Error(t *testing.T) {
+	storage := &mockStorage{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	handler.StopTrackingConfirm(session, makeComponentInteraction("guild-1", componentID(StopTrackingConfirmRoute, expires)))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgStopError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgStopError, session.lastInteractionResponse.Data.Content)
+	}
+	if session.lastInteractionResponse.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Error("error should be ephemeral")
+	}
+}
+
+func TestStopTrackingCancel(t *testing.T) {
+	storage := &mockStorage{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			t.Error("config should not be deleted on cancel")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.StopTrackingCancel(session, makeComponentInteraction("guild-1", componentID(StopTrackingCancelRoute)))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != formatting.MsgStopCancelled {
+		t.Errorf("expected prompt to be replaced with '%s', got %+v", formatting.MsgStopCancelled, resp)
+	}
+}
+
+func TestResumeTracking(t *testing.T) {
+	snapshot := []byte(`{"world": "Antica", "tibia_guilds": ["Red Rose"]}`)
+	tests := []struct {
+		name      string
+		stopped   *domain.StoppedGuildConfig
+		current   *domain.GuildConfig
+		expected  string
+		ephemeral bool
+	}{
+		{"Restored", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now()}, nil, formatting.MsgResumeSuccess("Antica"), false},
+		{"Nothing stopped", nil, nil, formatting.MsgNothingToResume, true},
+		{"World tracked again", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now()}, &domain.GuildConfig{World: "Secura"}, formatting.MsgResumeWorldTracked, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				getStoppedGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+					return tt.stopped, nil
+				},
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.current, nil
+				},
+			}
+			session := &mockDiscordSession{}
+
+			newTestHandler(storage).ResumeTracking(session, makeCommandInteraction("guild-1", "", ""))
+
+			resp := session.lastInteractionResponse
+			if resp.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, resp.Data.Content)
+			}
+			if ephemeral := resp.Data.Flags == discordgo.MessageFlagsEphemeral; ephemeral != tt.ephemeral {
+				t.Errorf("expected ephemeral=%v, got %v", tt.ephemeral, ephemeral)
+			}
+			wantCommits := 0
+			if tt.expected == formatting.MsgResumeSuccess("Antica") {
+				wantCommits = 1
+			}
+			if storage.commits != wantCommits {
+				t.Errorf("expected %d commits, got %d", wantCommits, storage.commits)
+			}
+		})
+	}
+}
+
+func TestPurgeData_AsksForConfirmation(t *testing.T) {
+	storage := &mockStorage{
+		purgeGuildDataFunc: func(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+			t.Error("data should not be purged before confirmation")
+			return domain.PurgeResult{}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.PurgeData(session, makeCommandInteraction("guild-123", "", ""))
+
+	data := session.lastInteractionResponse.Data
+	if data.Flags != discordgo.MessageFlagsEphemeral || len(data.Components) != 1 {
+		t.Fatalf("expected ephemeral prompt with buttons, got %+v", data)
+	}
+	buttons := data.Components[0].(discordgo.ActionsRow).Components
+	if route, _, _ := strings.Cut(buttons[0].(discordgo.Button).CustomID, ":"); route != PurgeDataConfirmRoute {
+		t.Errorf("expected confirm route %s, got %s", PurgeDataConfirmRoute, route)
+	}
+	if cancel := buttons[1].(discordgo.Button); cancel.CustomID != PurgeDataCancelRoute {
+		t.Errorf("unexpected cancel button: %+v", cancel)
+	}
+}
+
+func TestPurgeDataConfirm_Success(t *testing.T) {
+	var purged string
+	storage := &mockStorage{
+		purgeGuildDataFunc: func(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+			purged = guildID
+			return domain.PurgeResult{Configs: 1, Notifications: 2, GuildMembers: 3, Deaths: 4, Players: 5}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	handler.PurgeDataConfirm(session, makeComponentInteraction("guild-123", componentID(PurgeDataConfirmRoute, expires)))
+
+	if purged != "guild-123" {
+		t.Errorf("expected 'guild-123', got '%s'", purged)
+	}
+	resp := session.lastInteractionResponse
+	want := formatting.MsgPurgeComplete(domain.PurgeResult{Configs: 1, Notifications: 2, GuildMembers: 3, Deaths: 4, Players: 5})
+	if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != want {
+		t.Errorf("expected prompt to be replaced with '%s', got %+v", want, resp)
+	}
+}
+
+func TestPurgeDataConfirm_Expired(t *testing.T) {
+	storage := &mockStorage{
+		purgeGuildDataFunc: func(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+			t.Error("data should not be purged after the timeout")
+			return domain.PurgeResult{}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	expired := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	handler.PurgeDataConfirm(session, makeComponentInteraction("guild-1", componentID(PurgeDataConfirmRoute, expired)))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgConfirmExpired {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgConfirmExpired, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestPurgeDataConfirm_Error(t *testing.T) {
+	storage := &mockStorage{
+		purgeGuildDataFunc: func(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+			return domain.PurgeResult{}, errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	expires := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	handler.PurgeDataConfirm(session, makeComponentInteraction("guild-1", componentID(PurgeDataConfirmRoute, expires)))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgPurgeError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgPurgeError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestPurgeDataCancel(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.PurgeDataCancel(session, makeComponentInteraction("guild-1", componentID(PurgeDataCancelRoute)))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != formatting.MsgPurgeCancelled {
+		t.Errorf("expected prompt to be replaced with '%s', got %+v", formatting.MsgPurgeCancelled, resp)
+	}
+}
+
+func guildHandler(storage *mockStorage, guild *domain.Guild, err error) *BotHandler {
+	if storage.getGuildConfigFunc == nil {
+		storage.getGuildConfigFunc = func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+		}
+	}
+	fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+		return guild, err
+	}}
+	handler := newTestHandler(storage)
+	handler.Service = services.NewConfigurationService(storage, fetcher, services.Limits{}, nil)
+	return handler
+}
+
+func TestAddGuild_Success(t *testing.T) {
+	var added string
+	storage := &mockStorage{
+		addGuildToConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
+			added = tibiaGuild
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := guildHandler(storage, &domain.Guild{Name: "Red Rose", World: "Antica"}, nil)
+	handler.AddGuild(session, makeCommandInteraction("guild-1", "name", "red rose"))
+
+	if added != "Red Rose" {
+		t.Errorf("expected 'Red Rose', got '%s'", added)
+	}
+
+	if session.lastInteractionResponse.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("expected a deferred response, got %+v", session.lastInteractionResponse)
+	}
+	expected := formatting.MsgGuildAdded("Red Rose")
+	if session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+}
+
+func TestAddGuild_MissingName(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.AddGuild(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgGuildNameRequired {
+		t.Errorf("expected '%s'", formatting.MsgGuildNameRequired)
+	}
+}
+
+func TestAddGuild_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *mockStorage
+		guild   *domain.Guild
+		err     error
+		want    string
+	}{
+		{
+			name: "save fails",
+			storage: &mockStorage{addGuildToConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
+				return errors.New("db error")
+			}},
+			guild: &domain.Guild{Name: "Test", World: "Antica"},
+			want:  formatting.MsgSaveError,
+		},
+		{
+			name:    "other world",
+			storage: &mockStorage{},
+			guild:   &domain.Guild{Name: "Test", World: "Secura"},
+			want:    formatting.MsgGuildOtherWorld("Test", "Secura", "Antica"),
+		},
+		{
+			name:    "not found",
+			storage: &mockStorage{},
+			err:     fmt.Errorf("guild Test: %w", domain.ErrNotFound),
+			want:    formatting.MsgGuildNotFound("Test"),
+		},
+		{
+			name:    "upstream down",
+			storage: &mockStorage{},
+			err:     domain.ErrUpstreamDown,
+			want:    formatting.MsgGuildLookupError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &mockDiscordSession{}
+			handler := guildHandler(tt.storage, tt.guild, tt.err)
+			handler.AddGuild(session, makeCommandInteraction("guild-1", "name", "Test"))
+
+			if session.editedContent() != tt.want {
+				t.Errorf("expected '%s', got '%s'", tt.want, session.editedContent())
+			}
+		})
+	}
+}
+
+func TestAddGuild_PrivateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *domain.GuildConfig
+		want string
+	}{
+		{"no world tracked", nil, formatting.MsgWorldNotTracked},
+		{"limit reached", &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose", "Blue Moon"}}, formatting.MsgTibiaGuildLimit(2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return tt.cfg, nil
+			}}
+			fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+				t.Error("expected no lookup")
+				return nil, nil
+			}}
+
+			session := &mockDiscordSession{}
+			handler := newTestHandler(storage)
+			handler.Service = services.NewConfigurationService(storage, fetcher, services.Limits{TibiaGuilds: 2}, nil)
+			handler.AddGuild(session, makeCommandInteraction("guild-1", "name", "Test"))
+
+			resp := session.lastInteractionResponse
+			if resp.Data.Content != tt.want || resp.Data.Flags != discordgo.MessageFlagsEphemeral {
+				t.Errorf("expected private '%s', got %+v", tt.want, resp.Data)
+			}
+		})
+	}
+}
+
+func TestUnsetGuild_Success(t *testing.T) {
+	var removed string
+	storage := &mockStorage{
+		removeGuildFromConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
+			removed = tibiaGuild
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "name", "Red Rose"))
+
+	if removed != "Red Rose" {
+		t.Errorf("expected 'Red Rose', got '%s'", removed)
+	}
+
+	expected := formatting.MsgGuildRemoved("Red Rose")
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestUnsetGuild_MissingName(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgGuildNameRequired {
+		t.Errorf("expected '%s'", formatting.MsgGuildNameRequired)
+	}
+}
+
+func TestUnsetGuild_Autocomplete(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army"}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			GuildID: "guild-1",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "red", Focused: true},
+				},
+			},
+		},
+	}
+
+	handler.UnsetGuild(session, interaction)
+
+	if session.lastInteractionResponse.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Error("expected autocomplete response type")
+	}
+	if len(session.lastInteractionResponse.Data.Choices) != 1 {
+		t.Errorf("expected 1 choice matching 'red', got %d", len(session.lastInteractionResponse.Data.Choices))
+	}
+}
+
+func makeAutocompleteInteraction(guildID, optName, query string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			GuildID: guildID,
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: optName, Type: discordgo.ApplicationCommandOptionString, Value: query, Focused: true},
+				},
+			},
+		},
+	}
+}
+
+func TestAddGuild_Autocomplete(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+	}
+	var fetchedWorld string
+	fetcher := &mockFetcher{fetchGuildsFunc: func(ctx context.Context, world string) ([]string, error) {
+		fetchedWorld = world
+		return []string{"Red Rose", "Blue Army", "Redemption"}, nil
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Service = services.NewConfigurationService(storage, fetcher, services.Limits{}, nil)
+	handler.AddGuild(session, makeAutocompleteInteraction("guild-1", "name", "red"))
+
+	if fetchedWorld != "Antica" {
+		t.Errorf("expected guilds of the tracked world, fetched '%s'", fetchedWorld)
+	}
+	if session.lastInteractionResponse.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Fatal("expected autocomplete response type")
+	}
+	if len(session.lastInteractionResponse.Data.Choices) != 2 {
+		t.Errorf("expected 2 choices matching 'red', got %d", len(session.lastInteractionResponse.Data.Choices))
+	}
+}
+
+func TestAddGuild_AutocompleteWithoutWorld(t *testing.T) {
+	fetcher := &mockFetcher{fetchGuildsFunc: func(ctx context.Context, world string) ([]string, error) {
+		t.Error("expected no lookup without a tracked world")
+		return nil, nil
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.Service = services.NewConfigurationService(&mockStorage{}, fetcher, services.Limits{}, nil)
+	handler.AddGuild(session, makeAutocompleteInteraction("guild-1", "name", ""))
+
+	if session.lastInteractionResponse == nil || len(session.lastInteractionResponse.Data.Choices) != 0 {
+		t.Errorf("expected an empty autocomplete response, got %+v", session.lastInteractionResponse)
+	}
+}
+
+func TestPlayerAutocomplete(t *testing.T) {
+	var world, prefix string
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+		getPlayersByPrefixFunc: func(ctx context.Context, w, p string) ([]string, error) {
+			world, prefix = w, p
+			return []string{"Bubble", "Bubblegum"}, nil
+		},
+	}
+	handler := newTestHandler(storage)
+
+	handlers := map[string]func(DiscordSession, *discordgo.InteractionCreate){
+		"ignore-player": handler.IgnorePlayer,
+		"watch-player":  handler.WatchPlayer,
+		"pace":          handler.Pace,
+		"compare":       handler.Compare,
+	}
+	for name, handle := range handlers {
+		session := &mockDiscordSession{}
+		handle(session, makeAutocompleteInteraction("guild-1", "name", "bub"))
+
+		if world != "Antica" || prefix != "bub" {
+			t.Errorf("%s: expected players on Antica starting with 'bub', got %q and %q", name, world, prefix)
+		}
+		resp := session.lastInteractionResponse
+		if resp.Type != discordgo.InteractionApplicationCommandAutocompleteResult || len(resp.Data.Choices) != 2 || resp.Data.Choices[1].Value != "Bubblegum" {
+			t.Errorf("%s: unexpected response %+v", name, resp)
+		}
+	}
+}
+
+func TestPlayerAutocomplete_NothingTyped(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+		getPlayersByPrefixFunc: func(ctx context.Context, world, prefix string) ([]string, error) {
+			t.Error("expected no lookup before anything is typed")
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).WatchPlayer(session, makeAutocompleteInteraction("guild-1", "name", " "))
+
+	if session.lastInteractionResponse == nil || len(session.lastInteractionResponse.Data.Choices) != 0 {
+		t.Errorf("expected an empty autocomplete response, got %+v", session.lastInteractionResponse)
+	}
+}
+
+func TestListGuilds_WithGuilds(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army"}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+
+	expected := formatting.MsgGuildsList([]string{"Red Rose", "Blue Army"})[0]
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestListGuilds_NoGuilds(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{TibiaGuilds: []string{}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgNoGuildsTracked {
+		t.Errorf("expected '%s'", formatting.MsgNoGuildsTracked)
+	}
+}
+
+func TestListGuilds_NilConfig(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgNoGuildsTracked {
+		t.Errorf("expected '%s'", formatting.MsgNoGuildsTracked)
+	}
+}
+
+func TestListGuilds_Error(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgConfigError {
+		t.Errorf("expected '%s'", formatting.MsgConfigError)
+	}
+}
+
+func TestBuildGuildChoices(t *testing.T) {
+	t.Run("filters by query", func(t *testing.T) {
+		cfg := &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army", "Red Dragons"}}
+		choices := buildGuildChoices(cfg, "red")
+
+		if len(choices) != 2 {
+			t.Fatalf("expected 2 choices, got %d", len(choices))
+		}
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		cfg := &domain.GuildConfig{TibiaGuilds: []string{"Red Rose"}}
+		choices := buildGuildChoices(cfg, "RED")
+
+		if len(choices) != 1 {
+			t.Error("expected case-insensitive match")
+		}
+	})
+
+	t.Run("empty query returns all", func(t *testing.T) {
+		cfg := &domain.GuildConfig{TibiaGuilds: []string{"A", "B", "C"}}
+		choices := buildGuildChoices(cfg, "")
+
+		if len(choices) != 3 {
+			t.Errorf("expected 3, got %d", len(choices))
+		}
+	})
+
+	t.Run("limits to 25", func(t *testing.T) {
+		guilds := make([]string, 30)
+		for i := range guilds {
+			guilds[i] = "Guild"
+		}
+		cfg := &domain.GuildConfig{TibiaGuilds: guilds}
+		choices := buildGuildChoices(cfg, "")
+
+		if len(choices) != 25 {
+			t.Errorf("expected 25 max, got %d", len(choices))
+		}
+	})
+
+	t.Run("nil config returns nil", func(t *testing.T) {
+		choices := buildGuildChoices(nil, "test")
+		if choices != nil {
+			t.Error("expected nil")
+		}
+	})
+}
+
+func TestReadyHandler(t *testing.T) {
+	session := &discordgo.Session{State: discordgo.NewState()}
+	session.State.User = &discordgo.User{Username: "TestBot", Discriminator: "1234"}
+
+	ReadyHandler(session, &discordgo.Ready{})
+}
+
+func TestTrackWorld_LevelChannelError(t *testing.T) {
+	callCount := 0
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
+			callCount++
+			if callCount == 1 {
+				return []*discordgo.Channel{
+					{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+				}, nil
+			}
+			return nil, errors.New("level channel error")
+		},
+	}
+
+	handler := newTestHandler(&mockStorage{})
+	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+
+	expected := formatting.MsgChannelError("level-tracker")
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestUnsetGuild_Error(t *testing.T) {
+	storage := &mockStorage{
+		removeGuildFromConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "name", "Test Guild"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s'", formatting.MsgSaveError)
+	}
+}
+
+func TestUnsetGuild_AutocompleteError(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			GuildID: "guild-1",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "test", Focused: true},
+				},
+			},
+		},
+	}
+
+	handler.UnsetGuild(session, interaction)
+
+	if session.lastInteractionResponse != nil {
+		t.Error("expected no response when GetGuildConfig fails in autocomplete")
+	}
+}
+
+func TestSetLanguage_Success(t *testing.T) {
+	var saved string
+	storage := &mockStorage{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			saved = language
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetLanguage(session, makeCommandInteraction("guild-1", "language", formatting.LangPolish))
+
+	if saved != formatting.LangPolish {
+		t.Errorf("expected '%s', got '%s'", formatting.LangPolish, saved)
+	}
+
+	expected := formatting.MsgLanguageSet(formatting.CatalogFor(formatting.LangPolish).Name)
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetLanguage_Unsupported(t *testing.T) {
+	called := false
+	storage := &mockStorage{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			called = true
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetLanguage(session, makeCommandInteraction("guild-1", "language", "xx"))
+
+	if called {
+		t.Error("expected storage not to be called")
+	}
+	if session.lastInteractionResponse.Data.Content != formatting.MsgLanguageInvalid {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgLanguageInvalid, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetLanguage_Error(t *testing.T) {
+	storage := &mockStorage{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetLanguage(session, makeCommandInteraction("guild-1", "language", formatting.LangSpanish))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetTimezone_Success(t *testing.T) {
+	var saved string
+	storage := &mockStorage{
+		setGuildTimezoneFunc: func(ctx context.Context, guildID, timezone string) error {
+			saved = timezone
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetTimezone(session, makeCommandInteraction("guild-1", "timezone", "Europe/Warsaw"))
+
+	if saved != "Europe/Warsaw" {
+		t.Errorf("expected 'Europe/Warsaw', got '%s'", saved)
+	}
+
+	expected := formatting.MsgTimezoneSet("Europe/Warsaw")
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetTimezone_Invalid(t *testing.T) {
+	called := false
+	storage := &mockStorage{
+		setGuildTimezoneFunc: func(ctx context.Context, guildID, timezone string) error {
+			called = true
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetTimezone(session, makeCommandInteraction("guild-1", "timezone", "Europe/Atlantis"))
+
+	if called {
+		t.Error("expected storage not to be called")
+	}
+	if session.lastInteractionResponse.Data.Content != formatting.MsgTimezoneInvalid {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgTimezoneInvalid, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetTimezone_Error(t *testing.T) {
+	storage := &mockStorage{
+		setGuildTimezoneFunc: func(ctx context.Context, guildID, timezone string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetTimezone(session, makeCommandInteraction("guild-1", "timezone", "UTC"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestRouteDeaths(t *testing.T) {
+	levelOpt := &discordgo.ApplicationCommandInteractionDataOption{Name: "min-level", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(500)}
+	channelOpt := &discordgo.ApplicationCommandInteractionDataOption{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "high-deaths"}
+
+	tests := []struct {
+		name     string
+		options  []*discordgo.ApplicationCommandInteractionDataOption
+		world    string
+		existed  bool
+		expected string
+	}{
+		{"set", []*discordgo.ApplicationCommandInteractionDataOption{levelOpt, channelOpt}, "Antica", false, formatting.MsgDeathRouteSet(500, "high-deaths")},
+		{"set without world", []*discordgo.ApplicationCommandInteractionDataOption{levelOpt, channelOpt}, "", false, formatting.MsgWorldNotTracked},
+		{"remove", []*discordgo.ApplicationCommandInteractionDataOption{levelOpt}, "Antica", true, formatting.MsgDeathRouteRemoved(500, true)},
+		{"remove missing", []*discordgo.ApplicationCommandInteractionDataOption{levelOpt}, "Antica", false, formatting.MsgDeathRouteRemoved(500, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return &domain.GuildConfig{DiscordGuildID: guildID, World: tt.world}, nil
+				},
+				setDeathRouteFunc: func(ctx context.Context, guildID string, minLevel int, channelID string) error {
+					if minLevel != 500 || channelID != "high-deaths" {
+						t.Errorf("unexpected route: %d -> %s", minLevel, channelID)
+					}
+					return nil
+				},
+				deleteDeathRouteFunc: func(ctx context.Context, guildID string, minLevel int) (bool, error) {
+					return tt.existed, nil
+				},
+			}
+			interaction := &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					Type:    discordgo.InteractionApplicationCommand,
+					GuildID: "guild-1",
+					Data:    discordgo.ApplicationCommandInteractionData{Options: tt.options},
+				},
+			}
+
+			session := &mockDiscordSession{}
+			newTestHandler(storage).RouteDeaths(session, interaction)
+
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
+	}
+}
+
+func TestSetQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   string
+		expected string
+	}{
+		{"window", "02:00-08:00", formatting.MsgQuietHoursSet(domain.QuietHours{Start: 120, End: 480, CatchUp: true}, "Europe/Warsaw")},
+		{"off", "off", formatting.MsgQuietHoursSet(domain.QuietHours{}, "Europe/Warsaw")},
+		{"invalid", "night", formatting.MsgQuietHoursInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return &domain.GuildConfig{DiscordGuildID: guildID, Timezone: "Europe/Warsaw"}, nil
+				},
+			}
+
+			session := &mockDiscordSession{}
+			newTestHandler(storage).SetQuietHours(session, makeCommandInteraction("guild-1", "window", tt.window))
+
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
+	}
+}
+
+func makeSetTemplateInteraction(guildID, kind, template string) *discordgo.InteractionCreate {
+	options := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: kind},
+	}
+	if template != "" {
+		options = append(options, &discordgo.ApplicationCommandInteractionDataOption{Name: "template", Type: discordgo.ApplicationCommandOptionString, Value: template})
+	}
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: guildID,
+			Data:    discordgo.ApplicationCommandInteractionData{Options: options},
+		},
+	}
+}
+
+func TestSetTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		template string
+		saved    bool
+		expected string
+	}{
+		{"death template", "deaths", "{player} died to {reason}", true, formatting.MsgTemplateSet("deaths", "Knight Hero died to Killed at Level 500 by a dragon lord")},
+		{"reset", "levels", "", true, formatting.MsgTemplateReset("levels")},
+		{"unknown placeholder", "levels", "{player} {reason}", false, formatting.MsgTemplateInvalid(formatting.ValidateTemplate(domain.ChannelLevels, "{reason}"))},
+		{"unsupported type", "misc", "{player}", false, formatting.MsgTemplateInvalid(formatting.ValidateTemplate(domain.ChannelMisc, "{player}"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedTemplate string
+			saved := false
+			storage := &mockStorage{
+				setGuildTemplateFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+					saved = true
+					savedTemplate = template
+					return nil
+				},
+			}
+
+			session := &mockDiscordSession{}
+			newTestHandler(storage).SetTemplate(session, makeSetTemplateInteraction("guild-1", tt.kind, tt.template))
+
+			if saved != tt.saved {
+				t.Errorf("expected saved=%v, got %v", tt.saved, saved)
+			}
+			if saved && savedTemplate != tt.template {
+				t.Errorf("expected template %q, got %q", tt.template, savedTemplate)
+			}
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
+	}
+}
+
+func TestSetTemplate_Premium(t *testing.T) {
+	var saved []string
+	storage := &mockStorage{
+		setGuildTemplateFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+			saved = append(saved, template)
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Capabilities = services.NewCapabilityService(services.Premium{Gating: true})
+
+	handler.SetTemplate(session, makeSetTemplateInteraction("guild-1", "deaths", "{player} died"))
+	if session.lastInteractionResponse.Data.Content != formatting.MsgTemplatesPremium {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgTemplatesPremium, session.lastInteractionResponse.Data.Content)
+	}
+
+	handler.SetTemplate(session, makeSetTemplateInteraction("guild-1", "deaths", ""))
+	if len(saved) != 1 || saved[0] != "" {
+		t.Errorf("expected only the reset to be saved, got %q", saved)
+	}
+}
+
+func TestSetTemplate_UnconfiguredGuild(t *testing.T) {
+	var saved string
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
+		},
+		setGuildTemplateFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+			saved = template
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Capabilities = services.NewCapabilityService(services.Premium{Gating: true, Guilds: []string{"premium-1"}})
+
+	handler.SetTemplate(session, makeSetTemplateInteraction("guild-1", "deaths", "{player} died"))
+	if session.lastInteractionResponse.Data.Content != formatting.MsgTemplatesPremium {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgTemplatesPremium, session.lastInteractionResponse.Data.Content)
+	}
+
+	handler.SetTemplate(session, makeSetTemplateInteraction("premium-1", "deaths", "{player} died"))
+	if saved != "{player} died" {
+		t.Errorf("expected premium guilds to save before tracking a world, got %q", saved)
+	}
+}
+
+func TestSetTemplate_LookupError(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("db error")
+		},
+		setGuildTemplateFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+			t.Error("expected no save")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).SetTemplate(session, makeSetTemplateInteraction("guild-1", "deaths", "{player} died"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []*discordgo.ApplicationCommandInteractionDataOption
+		saved    bool
+		expected string
+	}{
+		{
+			name: "custom emoji with reaction",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: "deaths"},
+				{Name: "emoji", Type: discordgo.ApplicationCommandOptionString, Value: "<:rip:123>"},
+				{Name: "react", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+			},
+			saved:    true,
+			expected: formatting.MsgEmojiSet("deaths", "<:rip:123>", true),
+		},
+		{
+			name: "clear",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: "levels"},
+			},
+			saved:    true,
+			expected: formatting.MsgEmojiSet("levels", "", false),
+		},
+		{
+			name: "text instead of emoji",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: "levels"},
+				{Name: "emoji", Type: discordgo.ApplicationCommandOptionString, Value: "gz"},
+			},
+			expected: formatting.MsgEmojiInvalid,
+		},
+		{
+			name: "unsupported type",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: "misc"},
+			},
+			expected: formatting.MsgChannelInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			storage := &mockStorage{
+				setGuildEmojiFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+					saved = true
+					return nil
+				},
+			}
+			interaction := &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					Type:    discordgo.InteractionApplicationCommand,
+					GuildID: "guild-1",
+					Data:    discordgo.ApplicationCommandInteractionData{Options: tt.options},
+				},
+			}
+
+			session := &mockDiscordSession{}
+			newTestHandler(storage).SetEmoji(session, interaction)
+
+			if saved != tt.saved {
+				t.Errorf("expected saved=%v, got %v", tt.saved, saved)
+			}
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
+	}
+}
+
+func makeSetChannelInteraction(guildID, kind, channelID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: guildID,
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: kind},
+					{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: channelID},
+				},
+			},
+		},
+	}
+}
+
+func TestSetChannel_Success(t *testing.T) {
+	var savedKind domain.NotificationChannel
+	var savedID string
+	storage := &mockStorage{
+		setGuildChannelFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+			savedKind = kind
+			savedID = channelID
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetChannel(session, makeSetChannelInteraction("guild-1", "deaths", "chan-1"))
+
+	if savedKind != domain.ChannelDeaths || savedID != "chan-1" {
+		t.Errorf("unexpected args: kind=%s id=%s", savedKind, savedID)
+	}
+
+	expected := formatting.MsgChannelSet("deaths", "chan-1")
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetChannel_Thread(t *testing.T) {
+	tests := []struct {
+		name     string
+		locked   bool
+		saved    bool
+		expected string
+	}{
+		{"open thread", false, true, formatting.MsgChannelSet("deaths", "thread-1")},
+		{"locked thread", true, false, formatting.MsgThreadLocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			storage := &mockStorage{
+				setGuildChannelFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+					saved = true
+					return nil
+				},
+			}
+
+			interaction := makeSetChannelInteraction("guild-1", "deaths", "thread-1")
+			data := interaction.Data.(discordgo.ApplicationCommandInteractionData)
+			data.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{
+				Channels: map[string]*discordgo.Channel{
+					"thread-1": {ID: "thread-1", Type: discordgo.ChannelTypeGuildPublicThread, ThreadMetadata: &discordgo.ThreadMetadata{Archived: true, Locked: tt.locked}},
+				},
+			}
+			interaction.Data = data
+
+			session := &mockDiscordSession{}
+			newTestHandler(storage).SetChannel(session, interaction)
+
+			if saved != tt.saved {
+				t.Errorf("expected saved=%v, got %v", tt.saved, saved)
+			}
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
+	}
+}
+
+func TestSetChannel_InvalidType(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.SetChannel(session, makeSetChannelInteraction("guild-1", "kills", "chan-1"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgChannelInvalid {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgChannelInvalid, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetChannel_Error(t *testing.T) {
+	storage := &mockStorage{
+		setGuildChannelFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetChannel(session, makeSetChannelInteraction("guild-1", "levels", "chan-1"))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func makePingRoleInteraction(guildID, roleID string, minLevel *int) *discordgo.InteractionCreate {
+	opts := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: roleID},
+	}
+	if minLevel != nil {
+		opts = append(opts, &discordgo.ApplicationCommandInteractionDataOption{
+			Name: "min-level", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(*minLevel),
+		})
+	}
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: guildID,
+			Data:    discordgo.ApplicationCommandInteractionData{Options: opts},
+		},
+	}
+}
+
+func TestSetPingRole_Success(t *testing.T) {
+	var savedRole string
+	var savedLevel int
+	storage := &mockStorage{
+		setGuildPingRoleFunc: func(ctx context.Context, guildID, roleID string, minLevel int) error {
+			savedRole = roleID
+			savedLevel = minLevel
+			return nil
+		},
+	}
+
+	level := 800
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetPingRole(session, makePingRoleInteraction("guild-1", "role-1", &level))
+
+	if savedRole != "role-1" || savedLevel != 800 {
+		t.Errorf("unexpected args: role=%s level=%d", savedRole, savedLevel)
+	}
+
+	expected := formatting.MsgPingRoleSet("role-1", 800)
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetPingRole_DefaultsToMinLevelTrack(t *testing.T) {
+	var savedLevel int
+	storage := &mockStorage{
+		setGuildPingRoleFunc: func(ctx context.Context, guildID, roleID string, minLevel int) error {
+			savedLevel = minLevel
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Config.MinLevelTrack = 500
+	handler.SetPingRole(session, makePingRoleInteraction("guild-1", "role-1", nil))
+
+	if savedLevel != 500 {
+		t.Errorf("expected 500, got %d", savedLevel)
+	}
+}
+
+func TestSetPingRole_Error(t *testing.T) {
+	storage := &mockStorage{
+		setGuildPingRoleFunc: func(ctx context.Context, guildID, roleID string, minLevel int) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetPingRole(session, makePingRoleInteraction("guild-1", "role-1", nil))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+type mockFetcher struct {
+	ports.TibiaFetcher
+	fetchGuildFunc     func(ctx context.Context, guildName string) (*domain.Guild, error)
+	fetchCharacterFunc func(ctx context.Context, name string) (*domain.Player, error)
+	fetchGuildsFunc    func(ctx context.Context, world string) ([]string, error)
+}
+
+func (m *mockFetcher) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	return m.fetchGuildsFunc(ctx, world)
+}
+
+func (m *mockFetcher) FetchGuild(ctx context.Context, guildName string) (*domain.Guild, error) {
+	return m.fetchGuildFunc(ctx, guildName)
+}
+
+func (m *mockFetcher) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	return m.fetchCharacterFunc(ctx, name)
+}
+
+func TestSyncGuild_Deferred(t *testing.T) {
+	storage := &mockStorage{}
+	fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, guildName string) (*domain.Guild, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected sync to run with a deadline")
+		}
+		return &domain.Guild{World: "Antica", Members: []domain.Player{{Name: "A", Level: 100}, {Name: "B", Level: 200}}}, nil
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Backfill = services.NewBackfillService(storage, fetcher, 1)
+	handler.SyncGuild(session, makeCommandInteraction("guild-1", "name", "Red Rose"))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || resp.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected an ephemeral deferred response, got %+v", resp)
+	}
+	if expected := formatting.MsgGuildSynced("Red Rose", 2); session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+}
+
+func makeCompareInteraction(first, second string) *discordgo.InteractionCreate {
+	i := makeCommandInteraction("guild-1", "player1", first)
+	data := i.Data.(discordgo.ApplicationCommandInteractionData)
+	data.Options = append(data.Options, &discordgo.ApplicationCommandInteractionDataOption{
+		Name: "player2", Type: discordgo.ApplicationCommandOptionString, Value: second,
+	})
+	i.Data = data
+	return i
+}
+
+func TestCompare_Deferred(t *testing.T) {
+	characters := map[string]*domain.Player{
+		"hero":    {Name: "Hero", Level: 300},
+		"villain": {Name: "Villain", Level: 290},
+	}
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return characters[name], nil
+	}}
+	storage := &mockStorage{
+		getLevelUpsSinceFunc: func(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+			if name == "Villain" {
+				return []domain.LevelUp{{OldLevel: 269, NewLevel: 270}}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Stats = services.NewStatsService(storage, fetcher)
+	handler.Compare(session, makeCompareInteraction("hero", "villain"))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || resp.Data.Flags == discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected a public deferred response, got %+v", resp)
+	}
+	if got := session.editedContent(); !strings.Contains(got, "**Villain** overtakes **Hero**") {
+		t.Errorf("expected a crossover projection, got '%s'", got)
+	}
+}
+
+func TestCompare_InvalidNames(t *testing.T) {
+	for _, names := range [][2]string{{"Hero", ""}, {"Hero", "hero"}} {
+		session := &mockDiscordSession{}
+		handler := newTestHandler(&mockStorage{})
+		handler.Compare(session, makeCompareInteraction(names[0], names[1]))
+
+		if session.lastInteractionResponse.Data.Content != formatting.MsgCompareNamesInvalid {
+			t.Errorf("%v: expected '%s', got '%s'", names, formatting.MsgCompareNamesInvalid, session.lastInteractionResponse.Data.Content)
+		}
+	}
+}
+
+func TestCompare_FetchError(t *testing.T) {
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return nil, errors.New("not found")
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.Stats = services.NewStatsService(&mockStorage{}, fetcher)
+	handler.Compare(session, makeCompareInteraction("Hero", "Villain"))
+
+	if session.editedContent() != formatting.MsgCompareError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgCompareError, session.editedContent())
+	}
+}
+
+func TestPace_Deferred(t *testing.T) {
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return &domain.Player{Name: "Hero", Level: 300}, nil
+	}}
+	storage := &mockStorage{
+		getDailyLevelGainsFunc: func(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+			return []domain.DailyLevelGain{{Day: since, Levels: 7}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Stats = services.NewStatsService(storage, fetcher)
+	handler.Pace(session, makeCommandInteraction("guild-1", "player", "hero"))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || resp.Data.Flags == discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected a public deferred response, got %+v", resp)
+	}
+	if got := session.editedContent(); !strings.Contains(got, "**Hero**: level 300") || !strings.Contains(got, "in 30 days") {
+		t.Errorf("expected a forecast for Hero, got '%s'", got)
+	}
+}
+
+func TestPace_Errors(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.Pace(session, makeCommandInteraction("guild-1", "player", "  "))
+	if session.lastInteractionResponse.Data.Content != formatting.MsgPlayerNameRequired {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgPlayerNameRequired, session.lastInteractionResponse.Data.Content)
+	}
+
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return nil, errors.New("not found")
+	}}
+	session = &mockDiscordSession{}
+	handler.Stats = services.NewStatsService(&mockStorage{}, fetcher)
+	handler.Pace(session, makeCommandInteraction("guild-1", "player", "Nobody"))
+	if session.editedContent() != formatting.MsgPaceError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgPaceError, session.editedContent())
+	}
+}
+
+func makeExportInteraction(kind, window, format string) *discordgo.InteractionCreate {
+	i := makeCommandInteraction("guild-1", "type", kind)
+	data := i.Data.(discordgo.ApplicationCommandInteractionData)
+	for name, value := range map[string]string{"window": window, "format": format} {
+		if value != "" {
+			data.Options = append(data.Options, &discordgo.ApplicationCommandInteractionDataOption{
+				Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value,
+			})
+		}
+	}
+	i.Data = data
+	return i
+}
+
+func makeWarScoreInteraction(opponent, guild string) *discordgo.InteractionCreate {
+	i := makeCommandInteraction("guild-1", "opponent", opponent)
+	if guild != "" {
+		data := i.Data.(discordgo.ApplicationCommandInteractionData)
+		data.Options = append(data.Options, &discordgo.ApplicationCommandInteractionDataOption{
+			Name: "guild", Type: discordgo.ApplicationCommandOptionString, Value: guild,
+		})
+		i.Data = data
+	}
+	return i
+}
+
+func TestWarScore_Deferred(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose", "White Lily"}}, nil
+		},
+		getGuildWarFunc: func(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+			return &domain.GuildWar{GuildName: guildName, StartedAt: time.Now().Add(-time.Hour)}, nil
+		},
+		countKillsBetweenFunc: func(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+			if victims[0] == "Bob" {
+				return 5, nil
+			}
+			return 3, nil
+		},
+	}
+	fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, guildName string) (*domain.Guild, error) {
+		if guildName == "Red Rose" {
+			return &domain.Guild{Name: guildName, Members: []domain.Player{{Name: "Alice"}}}, nil
+		}
+		return &domain.Guild{Name: "Black Thorn", Members: []domain.Player{{Name: "Bob"}}}, nil
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Stats = services.NewStatsService(storage, fetcher)
+	handler.WarScore(session, makeWarScoreInteraction("black thorn", "red rose"))
+
+	if resp := session.lastInteractionResponse; resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("expected a deferred response, got %+v", resp)
+	}
+	if got := session.editedContent(); !strings.Contains(got, "**Red Rose** vs **Black Thorn**") || !strings.Contains(got, "Kills: **5** · Deaths: **3**") {
+		t.Errorf("unexpected war score: '%s'", got)
+	}
+}
+
+func TestWarScore_GuildChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracked []string
+		guild   string
+		want    string
+	}{
+		{"several tracked", []string{"Red Rose", "White Lily"}, "", formatting.MsgWarGuildRequired},
+		{"none tracked", nil, "", formatting.MsgNoGuildsTracked},
+		{"not tracked", []string{"Red Rose"}, "Black Thorn", formatting.MsgWarGuildNotTracked("Black Thorn")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return &domain.GuildConfig{World: "Antica", TibiaGuilds: tt.tracked}, nil
+				},
+			}
+			session := &mockDiscordSession{}
+			newTestHandler(storage).WarScore(session, makeWarScoreInteraction("Black Thorn", tt.guild))
+
+			if got := session.lastInteractionResponse.Data.Content; got != tt.want {
+				t.Errorf("expected '%s', got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWarScore_NoWar(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose"}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).WarScore(session, makeWarScoreInteraction("Black Thorn", ""))
+
+	if got := session.editedContent(); got != formatting.MsgNoGuildWar("Red Rose") {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgNoGuildWar("Red Rose"), got)
+	}
+}
+
+func TestWarScore_AutocompletesTrackedGuild(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose", "White Lily"}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).WarScore(session, makeAutocompleteInteraction("guild-1", "guild", "red"))
+
+	if choices := session.lastInteractionResponse.Data.Choices; len(choices) != 1 || choices[0].Name != "Red Rose" {
+		t.Errorf("expected the tracked guild to be suggested, got %+v", choices)
+	}
+}
+
+func TestExport_File(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+		},
+		getDeathsPageFunc: func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+			if afterID > 0 {
+				return nil, nil
+			}
+			if since.IsZero() {
+				t.Error("expected the 7 day window by default")
+			}
+			return []domain.DeathRecord{{ID: 1, Name: "Hero", World: "Antica", Level: 300}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Export(session, makeExportInteraction("deaths", "", "json"))
+
+	resp := session.lastInteractionResponse
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || resp.Data.Flags == discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected a public deferred response, got %+v", resp)
+	}
+	if expected := formatting.MsgExport("deaths", exportWindows[0].label, 1, false); session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+	files := session.lastResponseEdit.Files
+	if len(files) != 1 || files[0].Name != "deaths-antica-7d.json" || files[0].ContentType != "application/json" {
+		t.Fatalf("unexpected attachment: %+v", files)
+	}
+	body, _ := io.ReadAll(files[0].Reader)
+	if !strings.Contains(string(body), `"name":"Hero"`) {
+		t.Errorf("expected the death in the file, got %s", body)
+	}
+}
+
+func TestExport_Empty(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Export(session, makeExportInteraction("levels", "all", ""))
+
+	if expected := formatting.MsgExportEmpty("levels", exportWindows[3].label); session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+	if len(session.lastResponseEdit.Files) != 0 {
+		t.Errorf("expected no attachment, got %+v", session.lastResponseEdit.Files)
+	}
+}
+
+func TestExport_NoWorld(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.Export(session, makeExportInteraction("deaths", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldNotTracked {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgWorldNotTracked, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSyncGuild_FetchError(t *testing.T) {
+	fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, guildName string) (*domain.Guild, error) {
+		return nil, errors.New("not found")
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.Backfill = services.NewBackfillService(&mockStorage{}, fetcher, 1)
+	handler.SyncGuild(session, makeCommandInteraction("guild-1", "name", "Red Rose"))
+
+	if session.editedContent() != formatting.MsgGuildSyncError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgGuildSyncError, session.editedContent())
+	}
+}
+
+func TestSyncGuild_MissingName(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := newTestHandler(&mockStorage{})
+	handler.SyncGuild(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgGuildNameRequired {
+		t.Errorf("expected '%s'", formatting.MsgGuildNameRequired)
+	}
+}
+
+func TestDeathsToday_Success(t *testing.T) {
+	counts := []domain.DeathCount{{Name: "Hero", Count: 3}}
+	var queriedWorld string
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+		getDeathCountsSinceFunc: func(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+			queriedWorld = world
+			return counts, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.DeathsToday(session, makeCommandInteraction("guild-1", "", ""))
+
+	if queriedWorld != "Antica" {
+		t.Errorf("expected world 'Antica', got '%s'", queriedWorld)
+	}
+	expected := formatting.MsgDeathsToday("Antica", counts)[0]
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestFrags_Leaderboard(t *testing.T) {
+	var guildNames []string
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose"}}, nil
+		},
+		getFragCountsSinceFunc: func(ctx context.Context, world string, names []string, name string, since time.Time) ([]domain.FragCount, error) {
+			guildNames = names
+			return []domain.FragCount{{Name: "Alice", Frags: 3, Deaths: 2}, {Name: "Bob", Frags: 1}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).Frags(session, makeCommandInteraction("guild-1", "", ""))
+
+	content := session.lastInteractionResponse.Data.Content
+	if !strings.Contains(content, "1. Alice - 3 frags, 2 deaths (K/D 1.50)") || !strings.Contains(content, "2. Bob - 1 frag, 0 deaths (K/D 1.00)") {
+		t.Errorf("unexpected leaderboard: '%s'", content)
+	}
+	if len(guildNames) != 1 || guildNames[0] != "Red Rose" {
+		t.Errorf("expected tracked guilds to be passed, got %v", guildNames)
+	}
+}
+
+func TestFrags_Player(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).Frags(session, makeCommandInteraction("guild-1", "player", "Alice"))
+
+	if got, want := session.lastInteractionResponse.Data.Content, "🗡️ **Alice** since server save: 0 frags, 0 deaths (K/D 0.00)"; got != want {
+		t.Errorf("expected '%s', got '%s'", want, got)
+	}
+}
+
+func TestTopKillers_Success(t *testing.T) {
+	killers := []domain.KillerCount{{Name: "Enemy Knight", Kills: 4}}
+	var since time.Time
+	var guildNames []string
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose"}}, nil
+		},
+		getTopKillersSinceFunc: func(ctx context.Context, world string, names []string, s time.Time) ([]domain.KillerCount, error) {
+			guildNames = names
+			since = s
+			return killers, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.TopKillers(session, makeCommandInteraction("guild-1", "window", "24h"))
+
+	if len(guildNames) != 1 || guildNames[0] != "Red Rose" {
+		t.Errorf("expected tracked guilds to be queried, got %v", guildNames)
+	}
+	if d := time.Since(since); d < 24*time.Hour || d > 24*time.Hour+time.Minute {
+		t.Errorf("expected a 24 hour window, got %v", d)
+	}
+	embeds := session.lastInteractionResponse.Data.Embeds
+	want := formatting.TopKillersEmbed("Antica", "Last 24 hours", killers)
+	if len(embeds) != 1 || embeds[0].Description != want.Description || embeds[0].Footer.Text != want.Footer.Text {
+		t.Errorf("expected top killers embed, got %+v", embeds)
+	}
+}
+
+func TestTopKillers_DefaultWindow(t *testing.T) {
+	var since time.Time
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+		getTopKillersSinceFunc: func(ctx context.Context, world string, names []string, s time.Time) ([]domain.KillerCount, error) {
+			since = s
+			return nil, nil
+		},
+	}
+
+	handler := newTestHandler(storage)
+	handler.TopKillers(&mockDiscordSession{}, makeCommandInteraction("guild-1", "", ""))
+
+	if d := time.Since(since); d < 7*24*time.Hour || d > 7*24*time.Hour+time.Minute {
+		t.Errorf("expected a 7 day window, got %v", d)
+	}
+}
+
+func TestTopKillers_NoWorld(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.TopKillers(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldNotTracked {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgWorldNotTracked, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestActivity_Success(t *testing.T) {
+	var since time.Time
+	var guildNames []string
+	sunday := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose"}}, nil
+		},
+		getOnlineCountsSinceFunc: func(ctx context.Context, world string, names []string, s time.Time) ([]domain.OnlineCount, error) {
+			guildNames = names
+			since = s
+			return []domain.OnlineCount{{Hour: sunday, Players: 6}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).Activity(session, makeCommandInteraction("guild-1", "", ""))
+
+	if len(guildNames) != 1 || guildNames[0] != "Red Rose" {
+		t.Errorf("expected tracked guilds to be queried, got %v", guildNames)
+	}
+	if d := time.Since(since); d < domain.ActivityWindow || d > domain.ActivityWindow+time.Minute {
+		t.Errorf("expected a two week window, got %v", d)
+	}
+	embeds := session.lastInteractionResponse.Data.Embeds
+	if len(embeds) != 1 || !strings.Contains(embeds[0].Description, "Sunday around 20:00") {
+		t.Errorf("expected the activity embed, got %+v", embeds)
+	}
+}
+
+func TestActivity_NoWorld(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).Activity(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldNotTracked {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgWorldNotTracked, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestDeathsToday_NoWorld(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.DeathsToday(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldNotTracked {
+		t.Errorf("expected '%s'", formatting.MsgWorldNotTracked)
+	}
+}
+
+func TestDeathsToday_Error(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{World: "Antica"}, nil
+		},
+		getDeathCountsSinceFunc: func(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+			return nil, errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.DeathsToday(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgStatsError {
+		t.Errorf("expected '%s'", formatting.MsgStatsError)
+	}
+}
+
+func TestTrackStatus(t *testing.T) {
+	t.Run("configured", func(t *testing.T) {
+		storage := &mockStorage{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+			},
+		}
+
+		session := &mockDiscordSession{}
+		handler := newTestHandler(storage)
+		handler.TrackStatus(session, makeCommandInteraction("guild-1", "", ""))
+
+		if session.lastInteractionResponse.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+			t.Errorf("expected a deferred response, got %v", session.lastInteractionResponse.Type)
+		}
+		content := session.editedContent()
+		if !strings.Contains(content, "World: **Antica**") || !strings.Contains(content, "#death-tracker") {
+			t.Errorf("unexpected status: %s", content)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		storage := &mockStorage{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return nil, nil
+			},
+		}
+
+		session := &mockDiscordSession{}
+		handler := newTestHandler(storage)
+		handler.TrackStatus(session, makeCommandInteraction("guild-1", "", ""))
+
+		if session.editedContent() != formatting.MsgWorldNotTracked {
+			t.Errorf("expected '%s', got '%s'", formatting.MsgWorldNotTracked, session.editedContent())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		storage := &mockStorage{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return nil, errors.New("db error")
+			},
+		}
+
+		session := &mockDiscordSession{}
+		handler := newTestHandler(storage)
+		handler.TrackStatus(session, makeCommandInteraction("guild-1", "", ""))
+
+		if session.editedContent() != formatting.MsgConfigError {
+			t.Errorf("expected '%s', got '%s'", formatting.MsgConfigError, session.editedContent())
+		}
+	})
+}
+
+func makePollIntervalInteraction(guildID string, minutes int) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: guildID,
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "minutes", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(minutes)},
+				},
+			},
+		},
+	}
+}
+
+func TestSetPollInterval_Success(t *testing.T) {
+	var saved time.Duration
+	storage := &mockStorage{
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			saved = interval
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", 2))
+
+	if saved != 2*time.Minute {
+		t.Errorf("expected 2m, got %v", saved)
+	}
+	expected := formatting.MsgPollIntervalSet(2 * time.Minute)
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestSetPollInterval_Invalid(t *testing.T) {
+	storage := &mockStorage{
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			t.Error("expected no save")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", -5))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgPollIntervalInvalid {
+		t.Errorf("expected '%s'", formatting.MsgPollIntervalInvalid)
+	}
+}
+
+func TestSetPollInterval_Error(t *testing.T) {
+	storage := &mockStorage{
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			return errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", 0))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s'", formatting.MsgSaveError)
+	}
+}
+
+func TestSetPollInterval_Premium(t *testing.T) {
+	var saved time.Duration
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+		},
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			saved = interval
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Capabilities = services.NewCapabilityService(services.Premium{Gating: true, Guilds: []string{"premium-1"}, FreeMinPollInterval: 15 * time.Minute})
+
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", 2))
+	if saved != 0 {
+		t.Errorf("expected no save, got %v", saved)
+	}
+	if expected := formatting.MsgPollIntervalPremium(15 * time.Minute); session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", 20))
+	if saved != 20*time.Minute {
+		t.Errorf("expected 20m, got %v", saved)
+	}
+
+	handler.SetPollInterval(session, makePollIntervalInteraction("premium-1", 2))
+	if saved != 2*time.Minute {
+		t.Errorf("expected premium guilds to save 2m, got %v", saved)
+	}
+}
+
+func TestSetPollInterval_UnconfiguredGuild(t *testing.T) {
+	var saved time.Duration
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
+		},
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			saved = interval
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.Capabilities = services.NewCapabilityService(services.Premium{Gating: true, Guilds: []string{"premium-1"}, FreeMinPollInterval: 15 * time.Minute})
+
+	handler.SetPollInterval(session, makePollIntervalInteraction("guild-1", 2))
+	if expected := formatting.MsgPollIntervalPremium(15 * time.Minute); session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+
+	handler.SetPollInterval(session, makePollIntervalInteraction("premium-1", 2))
+	if saved != 2*time.Minute {
+		t.Errorf("expected premium guilds to save 2m before tracking a world, got %v", saved)
+	}
+}
+
+func TestSetPollInterval_LookupError(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("db error")
+		},
+		setGuildPollIntervalFunc: func(ctx context.Context, guildID string, interval time.Duration) error {
+			t.Error("expected no save")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).SetPollInterval(session, makePollIntervalInteraction("guild-1", 2))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestRetryFailed_NothingQueued(t *testing.T) {
+	var flushedGuild string
+	storage := &mockStorage{
+		getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+			flushedGuild = guildID
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.RetryFailed(session, makeCommandInteraction("guild-1", "", ""))
+
+	if flushedGuild != "guild-1" {
+		t.Errorf("expected guild 'guild-1', got '%s'", flushedGuild)
+	}
+	expected := formatting.MsgRetryFailed(0, 0, 0)
+	if session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+}
+
+func TestRetryFailed_Error(t *testing.T) {
+	storage := &mockStorage{
+		getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+			return nil, errors.New("db error")
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.RetryFailed(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.editedContent() != formatting.MsgRetryError {
+		t.Errorf("expected '%s'", formatting.MsgRetryError)
+	}
+}
+
+func TestTrackWorld_MissingPermissions(t *testing.T) {
+	storage := &mockStorage{
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			t.Error("world should not be saved without permissions")
+			return nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	i := makeCommandInteraction("guild-1", "name", "antica")
+	i.AppPermissions = discordgo.PermissionViewChannel
+	handler.TrackWorld(session, i)
+
+	expected := formatting.MsgMissingPermissions([]string{"Manage Channels", "Send Messages"})
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
+
+func TestCheckPermissions_AllGranted(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DeathChannelID: "c-death", LevelChannelID: "c-level"}, nil
+		},
+	}
+
+	var checked []string
+	session := &mockDiscordSession{
+		channelPermissionsFunc: func(userID, channelID string) (int64, error) {
+			if userID != "bot-1" {
+				t.Errorf("expected bot user 'bot-1', got '%s'", userID)
+			}
+			checked = append(checked, channelID)
+			return discordgo.PermissionAllText, nil
+		},
+	}
+	handler := newTestHandler(storage)
+	handler.CheckPermissions(session, makeCommandInteraction("guild-1", "", ""))
+
+	if len(checked) != 2 {
+		t.Errorf("expected both notification channels checked, got %v", checked)
+	}
+	if session.editedContent() != formatting.MsgPermissionsOK {
+		t.Errorf("expected '%s', got '%s'", formatting.MsgPermissionsOK, session.editedContent())
+	}
+}
+
+func TestCheckPermissions_MissingInChannel(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
+		},
+	}
+
+	session := &mockDiscordSession{
+		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
+			return []*discordgo.Channel{
+				{ID: "c-death", Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
+				{ID: "c-level", Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
+			}, nil
+		},
+		channelPermissionsFunc: func(userID, channelID string) (int64, error) {
+			if channelID == "c-death" {
+				return discordgo.PermissionViewChannel, nil
+			}
+			return discordgo.PermissionAllText, nil
 		},
-		Service: services.NewConfigurationService(storage),
+	}
+	handler := newTestHandler(storage)
+	handler.CheckPermissions(session, makeCommandInteraction("guild-1", "", ""))
+
+	expected := formatting.MsgMissingPermissions([]string{formatting.MsgChannelPermission("c-death", "Send Messages")})
+	if session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
 	}
 }
 
-func makeCommandInteraction(guildID, optName, optValue string) *discordgo.InteractionCreate {
-	var opts []*discordgo.ApplicationCommandInteractionDataOption
-	if optName != "" {
-		opts = []*discordgo.ApplicationCommandInteractionDataOption{
-			{Name: optName, Type: discordgo.ApplicationCommandOptionString, Value: optValue},
-		}
+func TestMissingPermissions(t *testing.T) {
+	if missing := missingPermissions(discordgo.PermissionAdministrator, guildPermissions); len(missing) != 0 {
+		t.Errorf("administrator should satisfy every permission, got %v", missing)
 	}
+	if missing := missingPermissions(0, guildPermissions); len(missing) != len(guildPermissions) {
+		t.Errorf("expected all permissions missing, got %v", missing)
+	}
+}
+
+func makeMuteInteraction(guildID string, hours int) *discordgo.InteractionCreate {
 	return &discordgo.InteractionCreate{
 		Interaction: &discordgo.Interaction{
 			Type:    discordgo.InteractionApplicationCommand,
 			GuildID: guildID,
-			Data:    discordgo.ApplicationCommandInteractionData{Options: opts},
-		},
-	}
-}
-
-func sessionWithChannels(channels ...*discordgo.Channel) *mockDiscordSession {
-	return &mockDiscordSession{
-		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
-			return channels, nil
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "hours", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(hours)},
+				},
+			},
 		},
 	}
 }
 
-func TestTrackWorld_Success(t *testing.T) {
-	var savedWorld string
+func TestMuteTracker_Success(t *testing.T) {
+	var saved time.Time
 	storage := &mockStorage{
-		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
-			savedWorld = world
+		setGuildMutedUntilFunc: func(ctx context.Context, guildID string, until time.Time) error {
+			saved = until
 			return nil
 		},
 	}
 
-	session := sessionWithChannels(
-		&discordgo.Channel{ID: "1", Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
-		&discordgo.Channel{ID: "2", Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
-	)
-
+	session := &mockDiscordSession{}
 	handler := newTestHandler(storage)
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+	handler.MuteTracker(session, makeMuteInteraction("guild-1", 4))
 
-	if savedWorld != "Antica" {
-		t.Errorf("expected world 'Antica', got '%s'", savedWorld)
+	if time.Until(saved) < 3*time.Hour {
+		t.Errorf("expected mute about 4h ahead, got %v", saved)
 	}
-	if session.lastInteractionResponse.Data.Flags != 0 {
-		t.Error("expected non-ephemeral success message")
+	expected := formatting.MsgMuted(saved)
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
 	}
 }
 
-func TestTrackWorld_MissingWorldName(t *testing.T) {
+func TestMuteTracker_Unmute(t *testing.T) {
 	session := &mockDiscordSession{}
 	handler := newTestHandler(&mockStorage{})
+	handler.MuteTracker(session, makeMuteInteraction("guild-1", 0))
 
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "", ""))
+	expected := formatting.MsgMuted(time.Time{})
+	if session.lastInteractionResponse.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	}
+}
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgWorldRequired {
-		t.Errorf("expected '%s', got '%s'", formatting.MsgWorldRequired, session.lastInteractionResponse.Data.Content)
+func TestMuteTracker_Invalid(t *testing.T) {
+	storage := &mockStorage{
+		setGuildMutedUntilFunc: func(ctx context.Context, guildID string, until time.Time) error {
+			t.Error("expected no save")
+			return nil
+		},
 	}
-	if session.lastInteractionResponse.Data.Flags != discordgo.MessageFlagsEphemeral {
-		t.Error("expected ephemeral error message")
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.MuteTracker(session, makeMuteInteraction("guild-1", 500))
+
+	if session.lastInteractionResponse.Data.Content != formatting.MsgMuteInvalid {
+		t.Errorf("expected '%s'", formatting.MsgMuteInvalid)
 	}
 }
 
-func TestTrackWorld_ChannelCreation(t *testing.T) {
-	var created []string
-	storage := &mockStorage{}
-	session := &mockDiscordSession{
-		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
-			return []*discordgo.Channel{}, nil
-		},
-		guildChannelCreateFunc: func(guildID, name string, ctype discordgo.ChannelType) (*discordgo.Channel, error) {
-			created = append(created, name)
-			return &discordgo.Channel{ID: "new-" + name, Name: name, Type: ctype}, nil
+func TestIgnorePlayer_Success(t *testing.T) {
+	var ignored string
+	storage := &mockStorage{
+		addIgnoredPlayerFunc: func(ctx context.Context, guildID, name string) error {
+			ignored = name
+			return nil
 		},
 	}
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return &domain.Player{Name: "Bubble Bot"}, nil
+	}}
 
+	session := &mockDiscordSession{}
 	handler := newTestHandler(storage)
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "secura"))
+	handler.Service = services.NewConfigurationService(storage, fetcher, services.Limits{}, nil)
+	handler.IgnorePlayer(session, makeCommandInteraction("guild-1", "name", "bubble bot"))
 
-	if len(created) != 2 {
-		t.Fatalf("expected 2 channels created, got %d", len(created))
+	if ignored != "Bubble Bot" {
+		t.Errorf("expected 'Bubble Bot', got '%s'", ignored)
 	}
-	if created[0] != "death-tracker" || created[1] != "level-tracker" {
-		t.Errorf("expected [death-tracker, level-tracker], got %v", created)
+	if session.lastInteractionResponse.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("expected a deferred response, got %+v", session.lastInteractionResponse)
+	}
+	expected := formatting.MsgPlayerIgnored("Bubble Bot")
+	if session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
 	}
 }
 
-func TestTrackWorld_ChannelError(t *testing.T) {
-	session := &mockDiscordSession{
-		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
-			return nil, errors.New("api error")
-		},
+func TestIgnorePlayer_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		lookup  error
+		saveErr error
+		want    string
+	}{
+		{"not found", fmt.Errorf("character Ghost: %w", domain.ErrNotFound), nil, formatting.MsgCharacterNotFound("Ghost")},
+		{"rate limited", domain.ErrRateLimited, nil, formatting.MsgCharacterLookupError},
+		{"save fails", nil, errors.New("db error"), formatting.MsgSaveError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				addIgnoredPlayerFunc: func(ctx context.Context, guildID, name string) error {
+					return tt.saveErr
+				},
+			}
+			fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+				if tt.lookup != nil {
+					return nil, tt.lookup
+				}
+				return &domain.Player{Name: name}, nil
+			}}
+
+			session := &mockDiscordSession{}
+			handler := newTestHandler(storage)
+			handler.Service = services.NewConfigurationService(storage, fetcher, services.Limits{}, nil)
+			handler.IgnorePlayer(session, makeCommandInteraction("guild-1", "name", " Ghost "))
+
+			if session.editedContent() != tt.want {
+				t.Errorf("expected '%s', got '%s'", tt.want, session.editedContent())
+			}
+		})
+	}
+}
+
+func TestIgnorePlayer_Limit(t *testing.T) {
+	storage := &mockStorage{getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+		return &domain.GuildConfig{World: "Antica", IgnoredPlayers: []string{"Alt"}, Quota: domain.Quota{IgnoredPlayers: 1}}, nil
+	}}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+	handler.IgnorePlayer(session, makeCommandInteraction("guild-1", "name", "Other Alt"))
+
+	resp := session.lastInteractionResponse
+	if resp.Data.Content != formatting.MsgIgnoredPlayerLimit(1) || resp.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Errorf("expected a private limit message, got %+v", resp.Data)
+	}
+}
+
+func TestWatchPlayer_Limit(t *testing.T) {
+	storage := &mockStorage{getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+		return &domain.GuildConfig{World: "Antica", WatchedPlayers: []string{"Alt"}, Quota: domain.Quota{WatchedPlayers: 1}}, nil
+	}}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).WatchPlayer(session, makeCommandInteraction("guild-1", "name", "Other Alt"))
+
+	if want := formatting.MsgWatchedPlayerLimit(1); session.editedContent() != want {
+		t.Errorf("expected '%s', got '%s'", want, session.editedContent())
 	}
+}
 
+func TestIgnorePlayer_MissingName(t *testing.T) {
+	session := &mockDiscordSession{}
 	handler := newTestHandler(&mockStorage{})
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+	handler.IgnorePlayer(session, makeCommandInteraction("guild-1", "name", "  "))
 
-	expected := formatting.MsgChannelError("death-tracker")
-	if session.lastInteractionResponse.Data.Content != expected {
-		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	if session.lastInteractionResponse.Data.Content != formatting.MsgPlayerNameRequired {
+		t.Errorf("expected '%s'", formatting.MsgPlayerNameRequired)
 	}
 }
 
-func TestTrackWorld_StorageError(t *testing.T) {
+func TestUnignorePlayer_Error(t *testing.T) {
 	storage := &mockStorage{
-		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+		removeIgnoredPlayerFunc: func(ctx context.Context, guildID, name string) error {
 			return errors.New("db error")
 		},
 	}
 
-	session := sessionWithChannels(
-		&discordgo.Channel{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
-		&discordgo.Channel{Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
-	)
-
+	session := &mockDiscordSession{}
 	handler := newTestHandler(storage)
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
+	handler.UnignorePlayer(session, makeCommandInteraction("guild-1", "name", "Bubble Bot"))
 
 	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
-		t.Errorf("expected '%s', got '%s'", formatting.MsgSaveError, session.lastInteractionResponse.Data.Content)
+		t.Errorf("expected '%s'", formatting.MsgSaveError)
 	}
 }
 
-func TestTrackWorld_WorldNameFormatting(t *testing.T) {
-	tests := []struct{ input, expected string }{
-		{"antica", "Antica"},
-		{"SECURA", "Secura"},
-		{"beLaBonA", "Belabona"},
+func TestUnignorePlayer_Autocomplete(t *testing.T) {
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{IgnoredPlayers: []string{"Bubble Bot", "Market Maker"}}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	handler := newTestHandler(storage)
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommandAutocomplete,
+			GuildID: "guild-1",
+			Data: discordgo.ApplicationCommandInteractionData{
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "bot", Focused: true},
+				},
+			},
+		},
+	}
+
+	handler.UnignorePlayer(session, interaction)
+
+	choices := session.lastInteractionResponse.Data.Choices
+	if len(choices) != 1 || choices[0].Value != "Bubble Bot" {
+		t.Errorf("expected only 'Bubble Bot', got %v", choices)
+	}
+}
+
+func TestSetLowLevelDeaths(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		saved := !enabled
+		storage := &mockStorage{
+			setGuildLowLevelDeathsFunc: func(ctx context.Context, guildID string, value bool) error {
+				saved = value
+				return nil
+			},
+		}
+
+		session := &mockDiscordSession{}
+		handler := newTestHandler(storage)
+		handler.SetLowLevelDeaths(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: enabled},
+					},
+				},
+			},
+		})
+
+		if saved != enabled {
+			t.Errorf("expected %v to be saved, got %v", enabled, saved)
+		}
+		expected := formatting.MsgLowLevelDeathsSet(enabled, handler.Config.MinLevelTrack)
+		if session.lastInteractionResponse.Data.Content != expected {
+			t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+		}
+	}
+}
+
+func TestSetMinLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		saved    bool
+		expected string
+	}{
+		{"Raises the minimum", 300, true, formatting.MsgMinLevelSet(300, 100)},
+		{"Falls back to the global minimum", 0, true, formatting.MsgMinLevelSet(0, 100)},
+		{"Rejects too high levels", 9000, false, formatting.MsgGuildMinLevelInvalid},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			var saved string
+		t.Run(tt.name, func(t *testing.T) {
+			saved := -1
 			storage := &mockStorage{
-				saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
-					saved = world
+				setGuildMinLevelFunc: func(ctx context.Context, guildID string, level int) error {
+					saved = level
 					return nil
 				},
 			}
 
-			session := sessionWithChannels(
-				&discordgo.Channel{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
-				&discordgo.Channel{Name: "level-tracker", Type: discordgo.ChannelTypeGuildText},
-			)
-
+			session := &mockDiscordSession{}
 			handler := newTestHandler(storage)
-			handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", tt.input))
+			handler.Config.MinLevelTrack = 100
+			handler.SetMinLevel(session, &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					Type:    discordgo.InteractionApplicationCommand,
+					GuildID: "guild-1",
+					Data: discordgo.ApplicationCommandInteractionData{
+						Options: []*discordgo.ApplicationCommandInteractionDataOption{
+							{Name: "level", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(tt.level)},
+						},
+					},
+				},
+			})
 
-			if saved != tt.expected {
-				t.Errorf("expected '%s', got '%s'", tt.expected, saved)
+			if tt.saved && saved != tt.level {
+				t.Errorf("expected %d to be saved, got %d", tt.level, saved)
+			}
+			if !tt.saved && saved != -1 {
+				t.Errorf("expected nothing to be saved, got %d", saved)
+			}
+			if session.lastInteractionResponse.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, session.lastInteractionResponse.Data.Content)
 			}
 		})
 	}
 }
 
-func TestStopTracking_Success(t *testing.T) {
-	var deleted string
-	storage := &mockStorage{
-		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
-			deleted = guildID
-			return nil
-		},
-	}
+func TestSetShareRange(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		saved := !enabled
+		storage := &mockStorage{
+			setGuildShareRangeFunc: func(ctx context.Context, guildID string, value bool) error {
+				saved = value
+				return nil
+			},
+		}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.StopTracking(session, makeCommandInteraction("guild-123", "", ""))
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetShareRange(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: enabled},
+					},
+				},
+			},
+		})
 
-	if deleted != "guild-123" {
-		t.Errorf("expected 'guild-123', got '%s'", deleted)
-	}
-	if session.lastInteractionResponse.Data.Content != formatting.MsgStopSuccess {
-		t.Errorf("expected '%s', got '%s'", formatting.MsgStopSuccess, session.lastInteractionResponse.Data.Content)
+		if saved != enabled {
+			t.Errorf("expected %v to be saved, got %v", enabled, saved)
+		}
+		if expected := formatting.MsgShareRangeSet(enabled); session.lastInteractionResponse.Data.Content != expected {
+			t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+		}
 	}
 }
 
-func TestStopTracking_Error(t *testing.T) {
-	storage := &mockStorage{
-		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
-			return errors.New("db error")
-		},
-	}
+func TestSetLevelDowns(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		saved := !enabled
+		storage := &mockStorage{
+			setGuildLevelDownsFunc: func(ctx context.Context, guildID string, value bool) error {
+				saved = value
+				return nil
+			},
+		}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.StopTracking(session, makeCommandInteraction("guild-1", "", ""))
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetLevelDowns(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: enabled},
+					},
+				},
+			},
+		})
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgStopError {
-		t.Errorf("expected '%s', got '%s'", formatting.MsgStopError, session.lastInteractionResponse.Data.Content)
+		if saved != enabled {
+			t.Errorf("expected %v to be saved, got %v", enabled, saved)
+		}
+		if expected := formatting.MsgLevelDownsSet(enabled); session.lastInteractionResponse.Data.Content != expected {
+			t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+		}
 	}
 }
 
-func TestAddGuild_Success(t *testing.T) {
-	var added string
-	storage := &mockStorage{
-		addGuildToConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
-			added = tibiaGuild
-			return nil
-		},
+func TestSetResponseVisibility(t *testing.T) {
+	tests := []struct {
+		choice  string
+		want    domain.ResponseVisibility
+		message string
+	}{
+		{"ephemeral", domain.VisibilityEphemeral, formatting.MsgResponseVisibilitySet(domain.VisibilityEphemeral)},
+		{"public", domain.VisibilityPublic, formatting.MsgResponseVisibilitySet(domain.VisibilityPublic)},
+		{"default", domain.VisibilityDefault, formatting.MsgResponseVisibilitySet(domain.VisibilityDefault)},
+		{"hidden", "unsaved", formatting.MsgVisibilityInvalid},
 	}
+	for _, tt := range tests {
+		saved := domain.ResponseVisibility("unsaved")
+		storage := &mockStorage{
+			setGuildResponseVisibilityFunc: func(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+				saved = visibility
+				return nil
+			},
+		}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.AddGuild(session, makeCommandInteraction("guild-1", "name", "Red Rose"))
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetResponseVisibility(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "visibility", Type: discordgo.ApplicationCommandOptionString, Value: tt.choice},
+					},
+				},
+			},
+		})
 
-	if added != "Red Rose" {
-		t.Errorf("expected 'Red Rose', got '%s'", added)
+		if saved != tt.want {
+			t.Errorf("%s: expected %q to be saved, got %q", tt.choice, tt.want, saved)
+		}
+		if session.lastInteractionResponse.Data.Content != tt.message {
+			t.Errorf("%s: expected '%s', got '%s'", tt.choice, tt.message, session.lastInteractionResponse.Data.Content)
+		}
 	}
+}
 
-	expected := formatting.MsgGuildAdded("Red Rose")
-	if session.lastInteractionResponse.Data.Content != expected {
-		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+func TestSetDeathVerbosity(t *testing.T) {
+	tests := []struct {
+		choice  string
+		want    domain.DeathVerbosity
+		message string
+	}{
+		{"compact", domain.VerbosityCompact, formatting.MsgDeathVerbositySet(domain.VerbosityCompact)},
+		{"detailed", domain.VerbosityDetailed, formatting.MsgDeathVerbositySet(domain.VerbosityDetailed)},
+		{"normal", domain.VerbosityNormal, formatting.MsgDeathVerbositySet(domain.VerbosityNormal)},
+		{"chatty", "unsaved", formatting.MsgVerbosityInvalid},
+	}
+	for _, tt := range tests {
+		saved := domain.DeathVerbosity("unsaved")
+		storage := &mockStorage{
+			setGuildDeathVerbosityFunc: func(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+				saved = verbosity
+				return nil
+			},
+		}
+
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetDeathVerbosity(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "verbosity", Type: discordgo.ApplicationCommandOptionString, Value: tt.choice},
+					},
+				},
+			},
+		})
+
+		if saved != tt.want {
+			t.Errorf("%s: expected %q to be saved, got %q", tt.choice, tt.want, saved)
+		}
+		if session.lastInteractionResponse.Data.Content != tt.message {
+			t.Errorf("%s: expected '%s', got '%s'", tt.choice, tt.message, session.lastInteractionResponse.Data.Content)
+		}
 	}
 }
 
-func TestAddGuild_MissingName(t *testing.T) {
-	session := &mockDiscordSession{}
-	handler := newTestHandler(&mockStorage{})
-	handler.AddGuild(session, makeCommandInteraction("guild-1", "", ""))
+func TestSetDeathLocation(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		saved := !enabled
+		storage := &mockStorage{
+			setGuildDeathLocationFunc: func(ctx context.Context, guildID string, value bool) error {
+				saved = value
+				return nil
+			},
+		}
+
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetDeathLocation(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: enabled},
+					},
+				},
+			},
+		})
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgGuildNameRequired {
-		t.Errorf("expected '%s'", formatting.MsgGuildNameRequired)
+		if saved != enabled {
+			t.Errorf("expected %v to be saved, got %v", enabled, saved)
+		}
+		if expected := formatting.MsgDeathLocationSet(enabled); session.lastInteractionResponse.Data.Content != expected {
+			t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+		}
 	}
 }
 
-func TestAddGuild_Error(t *testing.T) {
-	storage := &mockStorage{
-		addGuildToConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
-			return errors.New("db error")
-		},
-	}
+func TestSetAnnouncements(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		saved := !enabled
+		storage := &mockStorage{
+			setGuildBroadcastOptOutFunc: func(ctx context.Context, guildID string, optOut bool) error {
+				saved = !optOut
+				return nil
+			},
+		}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.AddGuild(session, makeCommandInteraction("guild-1", "name", "Test"))
+		session := &mockDiscordSession{}
+		newTestHandler(storage).SetAnnouncements(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: enabled},
+					},
+				},
+			},
+		})
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
-		t.Errorf("expected '%s'", formatting.MsgSaveError)
+		if saved != enabled {
+			t.Errorf("expected %v to be saved, got %v", enabled, saved)
+		}
+		if expected := formatting.MsgAnnouncementsSet(enabled); session.lastInteractionResponse.Data.Content != expected {
+			t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+		}
 	}
 }
 
-func TestUnsetGuild_Success(t *testing.T) {
-	var removed string
-	storage := &mockStorage{
-		removeGuildFromConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
-			removed = tibiaGuild
-			return nil
+func TestTrackHouses(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    string
+	}{
+		{
+			name: "explicit channel",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+				{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "chan-houses"},
+			},
+			want: "chan-houses",
+		},
+		{
+			name: "defaults to current channel",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+			},
+			want: "chan-current",
+		},
+		{
+			name: "disabled",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: false},
+				{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "chan-houses"},
+			},
+			want: "",
 		},
 	}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "name", "Red Rose"))
-
-	if removed != "Red Rose" {
-		t.Errorf("expected 'Red Rose', got '%s'", removed)
-	}
-
-	expected := formatting.MsgGuildRemoved("Red Rose")
-	if session.lastInteractionResponse.Data.Content != expected {
-		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
-	}
-}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedKind domain.NotificationChannel
+			savedID := "unchanged"
+			storage := &mockStorage{
+				setGuildChannelFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+					savedKind = kind
+					savedID = channelID
+					return nil
+				},
+			}
 
-func TestUnsetGuild_MissingName(t *testing.T) {
-	session := &mockDiscordSession{}
-	handler := newTestHandler(&mockStorage{})
-	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "", ""))
+			session := &mockDiscordSession{}
+			handler := newTestHandler(storage)
+			handler.TrackHouses(session, &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					Type:      discordgo.InteractionApplicationCommand,
+					GuildID:   "guild-1",
+					ChannelID: "chan-current",
+					Data:      discordgo.ApplicationCommandInteractionData{Options: tt.options},
+				},
+			})
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgGuildNameRequired {
-		t.Errorf("expected '%s'", formatting.MsgGuildNameRequired)
+			if savedKind != domain.ChannelHouses || savedID != tt.want {
+				t.Errorf("unexpected args: kind=%s id=%q", savedKind, savedID)
+			}
+			expected := formatting.MsgTrackHouses(tt.want)
+			if session.lastInteractionResponse.Data.Content != expected {
+				t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
 	}
 }
 
-func TestUnsetGuild_Autocomplete(t *testing.T) {
+func TestSetSkillThreshold(t *testing.T) {
+	var saved int
+	removed := false
 	storage := &mockStorage{
 		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army"}}, nil
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+		},
+		setSkillThresholdFunc: func(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+			if skill != domain.SkillMagic {
+				t.Errorf("unexpected skill %s", skill)
+			}
+			saved = minValue
+			return nil
+		},
+		deleteSkillThresholdFunc: func(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+			removed = true
+			return true, nil
 		},
 	}
-
-	session := &mockDiscordSession{}
 	handler := newTestHandler(storage)
-
-	interaction := &discordgo.InteractionCreate{
-		Interaction: &discordgo.Interaction{
-			Type:    discordgo.InteractionApplicationCommandAutocomplete,
-			GuildID: "guild-1",
-			Data: discordgo.ApplicationCommandInteractionData{
-				Options: []*discordgo.ApplicationCommandInteractionDataOption{
-					{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "red", Focused: true},
-				},
+	run := func(skill string, minValue int) *mockDiscordSession {
+		session := &mockDiscordSession{}
+		handler.SetSkillThreshold(session, &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:    discordgo.InteractionApplicationCommand,
+				GuildID: "guild-1",
+				Data: discordgo.ApplicationCommandInteractionData{Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "skill", Type: discordgo.ApplicationCommandOptionString, Value: skill},
+					{Name: "min-value", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(minValue)},
+				}},
 			},
-		},
+		})
+		return session
 	}
 
-	handler.UnsetGuild(session, interaction)
+	session := run("magic", 100)
+	if saved != 100 {
+		t.Errorf("expected threshold 100, got %d", saved)
+	}
+	if got, want := session.lastInteractionResponse.Data.Content, formatting.MsgSkillThresholdSet("magic level", 100); got != want {
+		t.Errorf("expected '%s', got '%s'", want, got)
+	}
 
-	if session.lastInteractionResponse.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
-		t.Error("expected autocomplete response type")
+	run("magic", 0)
+	if !removed {
+		t.Error("expected a min-value of 0 to remove the threshold")
 	}
-	if len(session.lastInteractionResponse.Data.Choices) != 1 {
-		t.Errorf("expected 1 choice matching 'red', got %d", len(session.lastInteractionResponse.Data.Choices))
+
+	session = run("cooking", 10)
+	if session.lastInteractionResponse.Data.Content != formatting.MsgSkillInvalid {
+		t.Errorf("expected invalid skill reply, got '%s'", session.lastInteractionResponse.Data.Content)
 	}
 }
 
-func TestListGuilds_WithGuilds(t *testing.T) {
-	storage := &mockStorage{
-		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army"}}, nil
+func TestSetMassDeathAlert(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    *domain.MassDeathAlert
+	}{
+		{
+			name: "default window",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "deaths", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(5)},
+			},
+			want: &domain.MassDeathAlert{Deaths: 5, Window: 10 * time.Minute},
+		},
+		{
+			name: "custom window",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "deaths", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(3)},
+				{Name: "minutes", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(30)},
+			},
+			want: &domain.MassDeathAlert{Deaths: 3, Window: 30 * time.Minute},
+		},
+		{
+			name: "off",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "deaths", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(0)},
+			},
+			want: &domain.MassDeathAlert{},
+		},
+		{
+			name: "single death refused",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "deaths", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(1)},
+			},
 		},
 	}
 
-	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *domain.MassDeathAlert
+			storage := &mockStorage{
+				setGuildMassDeathAlertFunc: func(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+					saved = &alert
+					return nil
+				},
+			}
 
-	expected := formatting.MsgGuildsList([]string{"Red Rose", "Blue Army"})
-	if session.lastInteractionResponse.Data.Content != expected {
-		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+			session := &mockDiscordSession{}
+			newTestHandler(storage).SetMassDeathAlert(session, &discordgo.InteractionCreate{
+				Interaction: &discordgo.Interaction{
+					Type:    discordgo.InteractionApplicationCommand,
+					GuildID: "guild-1",
+					Data:    discordgo.ApplicationCommandInteractionData{Options: tt.options},
+				},
+			})
+
+			expected := formatting.MsgMassDeathAlertInvalid
+			if tt.want != nil {
+				if saved == nil || *saved != *tt.want {
+					t.Fatalf("expected %+v saved, got %+v", tt.want, saved)
+				}
+				expected = formatting.MsgMassDeathAlertSet(*tt.want)
+			} else if saved != nil {
+				t.Fatalf("expected nothing saved, got %+v", saved)
+			}
+			if session.lastInteractionResponse.Data.Content != expected {
+				t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+			}
+		})
 	}
 }
 
-func TestListGuilds_NoGuilds(t *testing.T) {
+func TestSetChannel_Misc(t *testing.T) {
+	var savedKind domain.NotificationChannel
 	storage := &mockStorage{
-		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return &domain.GuildConfig{TibiaGuilds: []string{}}, nil
+		setGuildChannelFunc: func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+			savedKind = kind
+			return nil
 		},
 	}
 
 	session := &mockDiscordSession{}
 	handler := newTestHandler(storage)
-	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+	handler.SetChannel(session, makeSetChannelInteraction("guild-1", "misc", "chan-1"))
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgNoGuildsTracked {
-		t.Errorf("expected '%s'", formatting.MsgNoGuildsTracked)
+	if savedKind != domain.ChannelMisc {
+		t.Errorf("expected misc channel to be saved, got %q", savedKind)
 	}
 }
 
-func TestListGuilds_NilConfig(t *testing.T) {
-	storage := &mockStorage{
-		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return nil, nil
-		},
-	}
-
+func TestRashid(t *testing.T) {
 	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+	handler := newTestHandler(&mockStorage{})
+	handler.Rashid(session, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild-1",
+		},
+	})
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgNoGuildsTracked {
-		t.Errorf("expected '%s'", formatting.MsgNoGuildsTracked)
+	content := session.lastInteractionResponse.Data.Content
+	if !strings.Contains(content, domain.RashidCity(time.Now())) {
+		t.Errorf("expected today's city in '%s'", content)
 	}
 }
 
-func TestListGuilds_Error(t *testing.T) {
+func TestHelp(t *testing.T) {
 	storage := &mockStorage{
 		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return nil, errors.New("db error")
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
 		},
 	}
+	handler := newTestHandler(storage)
+	handler.Commands = func() []string { return []string{"track-world", "help"} }
 
 	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+	handler.Help(session, makeCommandInteraction("guild-1", "", ""))
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgConfigError {
-		t.Errorf("expected '%s'", formatting.MsgConfigError)
+	resp := session.lastInteractionResponse
+	if resp.Data.Flags != discordgo.MessageFlagsEphemeral || len(resp.Data.Embeds) != 1 {
+		t.Fatalf("expected an ephemeral embed, got %+v", resp.Data)
 	}
-}
-
-func TestBuildGuildChoices(t *testing.T) {
-	t.Run("filters by query", func(t *testing.T) {
-		cfg := &domain.GuildConfig{TibiaGuilds: []string{"Red Rose", "Blue Army", "Red Dragons"}}
-		choices := buildGuildChoices(cfg, "red")
-
-		if len(choices) != 2 {
-			t.Fatalf("expected 2 choices, got %d", len(choices))
-		}
-	})
-
-	t.Run("case insensitive", func(t *testing.T) {
-		cfg := &domain.GuildConfig{TibiaGuilds: []string{"Red Rose"}}
-		choices := buildGuildChoices(cfg, "RED")
-
-		if len(choices) != 1 {
-			t.Error("expected case-insensitive match")
-		}
-	})
-
-	t.Run("empty query returns all", func(t *testing.T) {
-		cfg := &domain.GuildConfig{TibiaGuilds: []string{"A", "B", "C"}}
-		choices := buildGuildChoices(cfg, "")
-
-		if len(choices) != 3 {
-			t.Errorf("expected 3, got %d", len(choices))
-		}
-	})
-
-	t.Run("limits to 25", func(t *testing.T) {
-		guilds := make([]string, 30)
-		for i := range guilds {
-			guilds[i] = "Guild"
-		}
-		cfg := &domain.GuildConfig{TibiaGuilds: guilds}
-		choices := buildGuildChoices(cfg, "")
-
-		if len(choices) != 25 {
-			t.Errorf("expected 25 max, got %d", len(choices))
-		}
-	})
-
-	t.Run("nil config returns nil", func(t *testing.T) {
-		choices := buildGuildChoices(nil, "test")
-		if choices != nil {
-			t.Error("expected nil")
-		}
-	})
-}
-
-func TestReadyHandler(t *testing.T) {
-	session := &discordgo.Session{State: discordgo.NewState()}
-	session.State.User = &discordgo.User{Username: "TestBot", Discriminator: "1234"}
-
-	ReadyHandler(session, &discordgo.Ready{})
-}
-
-func TestTrackWorld_LevelChannelError(t *testing.T) {
-	callCount := 0
-	session := &mockDiscordSession{
-		guildChannelsFunc: func(guildID string) ([]*discordgo.Channel, error) {
-			callCount++
-			if callCount == 1 {
-				return []*discordgo.Channel{
-					{Name: "death-tracker", Type: discordgo.ChannelTypeGuildText},
-				}, nil
-			}
-			return nil, errors.New("level channel error")
-		},
+	embed := resp.Data.Embeds[0]
+	if !strings.HasPrefix(embed.Description, "`/track-world`") || !strings.Contains(embed.Description, "`/help`") {
+		t.Errorf("expected the registered commands, got %q", embed.Description)
 	}
-
-	handler := newTestHandler(&mockStorage{})
-	handler.TrackWorld(session, makeCommandInteraction("guild-1", "name", "antica"))
-
-	expected := formatting.MsgChannelError("level-tracker")
-	if session.lastInteractionResponse.Data.Content != expected {
-		t.Errorf("expected '%s', got '%s'", expected, session.lastInteractionResponse.Data.Content)
+	if strings.Contains(embed.Description, "/add-guild") {
+		t.Errorf("expected unregistered commands left out, got %q", embed.Description)
+	}
+	if len(embed.Fields) == 0 || !strings.Contains(embed.Fields[0].Value, "Antica") {
+		t.Errorf("expected the guild setup, got %+v", embed.Fields)
 	}
 }
 
-func TestUnsetGuild_Error(t *testing.T) {
+func TestHelp_NotConfigured(t *testing.T) {
 	storage := &mockStorage{
-		removeGuildFromConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
-			return errors.New("db error")
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, nil
 		},
 	}
 
 	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
-	handler.UnsetGuild(session, makeCommandInteraction("guild-1", "name", "Test Guild"))
+	newTestHandler(storage).Help(session, makeCommandInteraction("guild-1", "", ""))
 
-	if session.lastInteractionResponse.Data.Content != formatting.MsgSaveError {
-		t.Errorf("expected '%s'", formatting.MsgSaveError)
+	embed := session.lastInteractionResponse.Data.Embeds[0]
+	if strings.Count(embed.Description, "\n") != len(GetApplicationCommands()) {
+		t.Errorf("expected every command listed, got %q", embed.Description)
+	}
+	if len(embed.Fields) != 1 || !strings.Contains(embed.Fields[0].Value, "/track-world") {
+		t.Errorf("expected a /track-world tip, got %+v", embed.Fields)
 	}
 }
 
-func TestUnsetGuild_AutocompleteError(t *testing.T) {
+func TestHelp_ConfigError(t *testing.T) {
 	storage := &mockStorage{
 		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
-			return nil, errors.New("db error")
+			return nil, errors.New("connection refused")
 		},
 	}
 
 	session := &mockDiscordSession{}
-	handler := newTestHandler(storage)
+	newTestHandler(storage).Help(session, makeCommandInteraction("guild-1", "", ""))
 
-	interaction := &discordgo.InteractionCreate{
-		Interaction: &discordgo.Interaction{
-			Type:    discordgo.InteractionApplicationCommandAutocomplete,
-			GuildID: "guild-1",
-			Data: discordgo.ApplicationCommandInteractionData{
-				Options: []*discordgo.ApplicationCommandInteractionDataOption{
-					{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "test", Focused: true},
-				},
-			},
-		},
+	resp := session.lastInteractionResponse
+	if resp.Data.Content != formatting.MsgConfigError || len(resp.Data.Embeds) != 0 {
+		t.Errorf("expected '%s' without the help embed, got %+v", formatting.MsgConfigError, resp.Data)
 	}
+}
 
-	handler.UnsetGuild(session, interaction)
+// mockStorageTx runs a transaction's calls against the mock itself and counts how
+// it ended on the mock. Rollbacks after a commit are not counted.
+type mockStorageTx struct {
+	*mockStorage
+	committed bool
+}
 
-	if session.lastInteractionResponse != nil {
-		t.Error("expected no response when GetGuildConfig fails in autocomplete")
+func (t *mockStorageTx) Commit(ctx context.Context) error {
+	t.committed = true
+	t.commits++
+	return nil
+}
+
+func (t *mockStorageTx) Rollback(ctx context.Context) error {
+	if !t.committed {
+		t.rollbacks++
 	}
+	return nil
 }
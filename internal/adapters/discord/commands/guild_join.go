@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/ports"
+	"death-level-tracker/internal/core/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildJoinHandler registers commands in servers the bot is added to when
+// they are scoped per server, and greets new servers with setup instructions.
+// It also keeps the configuration of servers that remove the bot out of
+// tracking, restoring it if the bot is added back within GUILD_REMOVAL_GRACE.
+type GuildJoinHandler struct {
+	config  *config.Config
+	service *services.ConfigurationService
+	leader  ports.LeaderElector
+
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+func NewGuildJoinHandler(cfg *config.Config, service *services.ConfigurationService, leader ports.LeaderElector) *GuildJoinHandler {
+	return &GuildJoinHandler{
+		config:  cfg,
+		service: service,
+		leader:  leader,
+		known:   make(map[string]bool),
+	}
+}
+
+// Ready remembers the servers the bot already belongs to. Discord follows
+// Ready with a GuildCreate for each of them, which is not a join.
+func (h *GuildJoinHandler) Ready(s *discordgo.Session, r *discordgo.Ready) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, g := range r.Guilds {
+		h.known[g.ID] = true
+	}
+}
+
+func (h *GuildJoinHandler) GuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	h.handleGuildCreate(s, s.State.User.ID, g.Guild)
+}
+
+func (h *GuildJoinHandler) handleGuildCreate(s GuildJoinSession, appID string, guild *discordgo.Guild) {
+	if guild.Unavailable {
+		return
+	}
+
+	h.mu.Lock()
+	joined := !h.known[guild.ID]
+	h.known[guild.ID] = true
+	h.mu.Unlock()
+
+	restored, err := h.service.GuildRejoined(context.Background(), guild.ID)
+	if err != nil {
+		slog.Error("Failed to restore guild config", "guild_id", guild.ID, "error", err)
+	} else if restored {
+		slog.Info("Restored config of rejoined guild", "guild_id", guild.ID)
+	}
+
+	// Servers joined while the bot was offline are caught up here as well;
+	// SyncCommands leaves them alone when nothing changed.
+	if h.config.DiscordGuildID != "" {
+		SyncCommands(s, GetApplicationCommands(), appID, guild.ID)
+	}
+
+	if !joined {
+		return
+	}
+	slog.Info("Joined guild", "guild_id", guild.ID, "name", guild.Name)
+	msg := formatting.MsgWelcome
+	if restored {
+		msg = formatting.MsgWelcomeBack
+	}
+	h.welcome(s, guild, msg)
+}
+
+// GuildDelete stops tracking a server that removed the bot. Discord also
+// sends GuildDelete when a server becomes unavailable during an outage, which
+// is ignored.
+func (h *GuildJoinHandler) GuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	h.handleGuildDelete(g.Guild)
+}
+
+func (h *GuildJoinHandler) handleGuildDelete(guild *discordgo.Guild) {
+	if guild.Unavailable {
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.known, guild.ID)
+	h.mu.Unlock()
+
+	if err := h.service.GuildRemoved(context.Background(), guild.ID); err != nil {
+		slog.Error("Failed to mark guild config removed", "guild_id", guild.ID, "error", err)
+		return
+	}
+	slog.Info("Removed from guild", "guild_id", guild.ID, "grace", h.config.GuildRemovalGrace)
+}
+
+// welcome posts msg to the server's system channel. Only the leader posts so
+// replicas do not greet a server twice.
+func (h *GuildJoinHandler) welcome(s GuildJoinSession, guild *discordgo.Guild, msg string) {
+	if !h.config.DiscordWelcomeMessage || guild.SystemChannelID == "" {
+		return
+	}
+	if h.leader != nil && !h.leader.IsLeader(context.Background()) {
+		return
+	}
+
+	if _, err := s.ChannelMessageSend(guild.SystemChannelID, msg); err != nil {
+		slog.Warn("Failed to send welcome message", "guild_id", guild.ID, "channel_id", guild.SystemChannelID, "error", err)
+	}
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type mockGuildJoinSession struct {
+	mockCommandSession
+	listedGuilds []string
+	sent         map[string]string
+}
+
+func (m *mockGuildJoinSession) ApplicationCommands(appID, guildID string, opts ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	m.listedGuilds = append(m.listedGuilds, guildID)
+	return nil, nil
+}
+
+func (m *mockGuildJoinSession) ChannelMessageSend(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if m.sent == nil {
+		m.sent = make(map[string]string)
+	}
+	m.sent[channelID] = content
+	return &discordgo.Message{}, nil
+}
+
+type stubLeader bool
+
+func (l stubLeader) IsLeader(ctx context.Context) bool { return bool(l) }
+func (l stubLeader) Release(ctx context.Context)       {}
+
+func TestGuildJoinHandler(t *testing.T) {
+	newGuild := &discordgo.Guild{ID: "new", Name: "New Server", SystemChannelID: "system-new"}
+	oldGuild := &discordgo.Guild{ID: "old", SystemChannelID: "system-old"}
+
+	t.Run("guild-scoped commands and welcome", func(t *testing.T) {
+		h := NewGuildJoinHandler(&config.Config{DiscordGuildID: "dev", DiscordWelcomeMessage: true}, services.NewConfigurationService(&mockStorage{}, nil, services.Limits{}, nil), nil)
+		h.Ready(nil, &discordgo.Ready{Guilds: []*discordgo.Guild{{ID: "old"}}})
+
+		session := &mockGuildJoinSession{}
+		h.handleGuildCreate(session, "bot-id", oldGuild)
+		h.handleGuildCreate(session, "bot-id", newGuild)
+
+		if len(session.listedGuilds) != 2 || session.listedGuilds[0] != "old" || session.listedGuilds[1] != "new" {
+			t.Errorf("expected commands synced in both guilds, got %v", session.listedGuilds)
+		}
+		if len(session.sent) != 1 || session.sent["system-new"] != formatting.MsgWelcome {
+			t.Errorf("expected welcome only in the new guild, got %v", session.sent)
+		}
+
+		session.sent = nil
+		h.handleGuildCreate(session, "bot-id", newGuild)
+		if len(session.sent) != 0 {
+			t.Errorf("expected no second welcome after reconnect, got %v", session.sent)
+		}
+	})
+
+	t.Run("global commands are not registered per guild", func(t *testing.T) {
+		h := NewGuildJoinHandler(&config.Config{DiscordWelcomeMessage: true}, services.NewConfigurationService(&mockStorage{}, nil, services.Limits{}, nil), nil)
+
+		session := &mockGuildJoinSession{}
+		h.handleGuildCreate(session, "bot-id", newGuild)
+
+		if len(session.listedGuilds) != 0 {
+			t.Errorf("expected no per-guild sync, got %v", session.listedGuilds)
+		}
+		if session.sent["system-new"] != formatting.MsgWelcome {
+			t.Error("expected welcome message")
+		}
+	})
+
+	t.Run("no welcome when disabled, on standby or without system channel", func(t *testing.T) {
+		cases := map[string]struct {
+			cfg    *config.Config
+			leader stubLeader
+			guild  *discordgo.Guild
+		}{
+			"disabled":          {&config.Config{}, true, newGuild},
+			"standby":           {&config.Config{DiscordWelcomeMessage: true}, false, newGuild},
+			"no system channel": {&config.Config{DiscordWelcomeMessage: true}, true, &discordgo.Guild{ID: "quiet"}},
+		}
+		for name, tc := range cases {
+			h := NewGuildJoinHandler(tc.cfg, services.NewConfigurationService(&mockStorage{}, nil, services.Limits{}, nil), tc.leader)
+			session := &mockGuildJoinSession{}
+			h.handleGuildCreate(session, "bot-id", tc.guild)
+			if len(session.sent) != 0 {
+				t.Errorf("%s: expected no welcome, got %v", name, session.sent)
+			}
+		}
+	})
+
+	t.Run("unavailable guild ignored", func(t *testing.T) {
+		h := NewGuildJoinHandler(&config.Config{DiscordGuildID: "dev", DiscordWelcomeMessage: true}, services.NewConfigurationService(&mockStorage{}, nil, services.Limits{}, nil), nil)
+		session := &mockGuildJoinSession{}
+		h.handleGuildCreate(session, "bot-id", &discordgo.Guild{ID: "down", Unavailable: true, SystemChannelID: "c"})
+		if len(session.listedGuilds) != 0 || len(session.sent) != 0 {
+			t.Error("expected unavailable guild to be ignored")
+		}
+	})
+	t.Run("rejoin restores config and welcomes back", func(t *testing.T) {
+		storage := &mockStorage{
+			restoreGuildConfigFunc: func(ctx context.Context, guildID string) (bool, error) {
+				return guildID == "new", nil
+			},
+		}
+		h := NewGuildJoinHandler(&config.Config{DiscordWelcomeMessage: true}, services.NewConfigurationService(storage, nil, services.Limits{}, nil), nil)
+
+		session := &mockGuildJoinSession{}
+		h.handleGuildCreate(session, "bot-id", newGuild)
+
+		if session.sent["system-new"] != formatting.MsgWelcomeBack {
+			t.Errorf("expected welcome back message, got %v", session.sent)
+		}
+	})
+}
+
+func TestGuildJoinHandler_GuildDelete(t *testing.T) {
+	var removed []string
+	storage := &mockStorage{
+		markGuildRemovedFunc: func(ctx context.Context, guildID string, at time.Time) error {
+			if time.Since(at) > time.Minute {
+				t.Errorf("expected removal time to be now, got %v", at)
+			}
+			removed = append(removed, guildID)
+			return nil
+		},
+	}
+	h := NewGuildJoinHandler(&config.Config{DiscordWelcomeMessage: true}, services.NewConfigurationService(storage, nil, services.Limits{}, nil), nil)
+	h.Ready(nil, &discordgo.Ready{Guilds: []*discordgo.Guild{{ID: "kicked"}, {ID: "outage"}}})
+
+	h.handleGuildDelete(&discordgo.Guild{ID: "outage", Unavailable: true})
+	h.handleGuildDelete(&discordgo.Guild{ID: "kicked"})
+
+	if len(removed) != 1 || removed[0] != "kicked" {
+		t.Errorf("expected only the kicked guild to be marked removed, got %v", removed)
+	}
+
+	session := &mockGuildJoinSession{}
+	h.handleGuildCreate(session, "bot-id", &discordgo.Guild{ID: "kicked", SystemChannelID: "system"})
+	if session.sent["system"] == "" {
+		t.Error("expected a re-invite to count as a join")
+	}
+}
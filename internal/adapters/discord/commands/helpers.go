@@ -1,6 +1,23 @@
 package commands
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// deferredTimeout bounds work behind a deferred response; Discord accepts
+// edits for 15 minutes after the interaction.
+const deferredTimeout = 14 * time.Minute
 
 func respond(s DiscordSession, i *discordgo.InteractionCreate, msg string, ephemeral bool) {
 	var flags discordgo.MessageFlags
@@ -17,6 +34,126 @@ func respond(s DiscordSession, i *discordgo.InteractionCreate, msg string, ephem
 	})
 }
 
+func respondEmbed(s DiscordSession, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, ephemeral bool) {
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  flags,
+		},
+	})
+}
+
+// deferResponse acknowledges i with a loading state so the handler can run
+// past Discord's 3 second deadline and answer later with editResponse.
+// Visibility is fixed here and cannot change in the edit.
+func deferResponse(s DiscordSession, i *discordgo.InteractionCreate, ephemeral bool) error {
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: flags,
+		},
+	})
+}
+
+// editResponse replaces the loading state left by deferResponse with msg.
+func editResponse(s DiscordSession, i *discordgo.InteractionCreate, msg string) {
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &msg}); err != nil {
+		slog.Warn("Failed to edit deferred response", "command", interactionName(i), "error", err)
+	}
+}
+
+// editResponseFile replaces the loading state left by deferResponse with msg
+// and attaches file.
+func editResponseFile(s DiscordSession, i *discordgo.InteractionCreate, msg string, file *discordgo.File) {
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &msg, Files: []*discordgo.File{file}}); err != nil {
+		slog.Warn("Failed to edit deferred response", "command", interactionName(i), "error", err)
+	}
+}
+
+// respondDeferred defers the response, runs work with a context bounded by
+// deferredTimeout and edits the response with the message it returns.
+func respondDeferred(s DiscordSession, i *discordgo.InteractionCreate, ephemeral bool, work func(ctx context.Context) string) {
+	if err := deferResponse(s, i, ephemeral); err != nil {
+		slog.Warn("Failed to defer response", "command", interactionName(i), "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deferredTimeout)
+	defer cancel()
+	editResponse(s, i, work(ctx))
+}
+
+// updateMessage replaces the message holding the clicked component with msg
+// and removes its components.
+func updateMessage(s DiscordSession, i *discordgo.InteractionCreate, msg string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    msg,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// autocompleteTimeout bounds lookups behind an autocomplete response, which
+// Discord drops after 3 seconds.
+const autocompleteTimeout = 2500 * time.Millisecond
+
+// confirmTimeout is how long a confirmation prompt can be accepted. The
+// deadline travels in the confirm button's custom ID, so any replica can
+// check it.
+const confirmTimeout = 30 * time.Second
+
+// respondConfirm replies with an ephemeral prompt holding a danger button
+// routed to confirmRoute and a cancel button routed to cancelRoute. prompt
+// renders the text for the prompt's deadline.
+func respondConfirm(s DiscordSession, i *discordgo.InteractionCreate, prompt func(expires time.Time) string, confirmLabel, confirmRoute, cancelRoute string) {
+	expires := time.Now().Add(confirmTimeout)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: prompt(expires),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    confirmLabel,
+						Style:    discordgo.DangerButton,
+						CustomID: componentID(confirmRoute, strconv.FormatInt(expires.Unix(), 10)),
+					},
+					discordgo.Button{
+						Label:    formatting.MsgCancelButton,
+						Style:    discordgo.SecondaryButton,
+						CustomID: componentID(cancelRoute),
+					},
+				}},
+			},
+		},
+	})
+}
+
+// confirmExpired reports whether the clicked confirm button of a
+// respondConfirm prompt is malformed or past its deadline.
+func confirmExpired(i *discordgo.InteractionCreate) bool {
+	args := componentArgs(i)
+	if len(args) != 1 {
+		return true
+	}
+	expires, err := strconv.ParseInt(args[0], 10, 64)
+	return err != nil || time.Now().After(time.Unix(expires, 0))
+}
+
 func respondAutocomplete(s DiscordSession, i *discordgo.InteractionCreate, choices []*discordgo.ApplicationCommandOptionChoice) error {
 	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
@@ -26,6 +163,19 @@ func respondAutocomplete(s DiscordSession, i *discordgo.InteractionCreate, choic
 	})
 }
 
+// channelError reports the notification channel ensureChannel could not
+// find or create.
+type channelError struct {
+	name string
+	err  error
+}
+
+func (e *channelError) Error() string {
+	return fmt.Sprintf("ensure channel %s: %v", e.name, e.err)
+}
+
+func (e *channelError) Unwrap() error { return e.err }
+
 func ensureChannel(s DiscordSession, guildID, name string) (string, error) {
 	channels, err := s.GuildChannels(guildID)
 	if err != nil {
@@ -62,3 +212,93 @@ func getFocusedOption(opts []*discordgo.ApplicationCommandInteractionDataOption)
 	}
 	return ""
 }
+
+// getFocusedOptionName names the option being autocompleted, for commands
+// that autocomplete more than one.
+func getFocusedOptionName(opts []*discordgo.ApplicationCommandInteractionDataOption) string {
+	for _, opt := range opts {
+		if opt.Focused {
+			return opt.Name
+		}
+	}
+	return ""
+}
+
+func getChannelOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range opts {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionChannel {
+			return opt.ChannelValue(nil).ID
+		}
+	}
+	return ""
+}
+
+// resolvedChannel returns the channel Discord resolved for a channel option,
+// or nil when the interaction does not carry it.
+func resolvedChannel(i *discordgo.InteractionCreate, channelID string) *discordgo.Channel {
+	data := i.ApplicationCommandData()
+	if data.Resolved == nil {
+		return nil
+	}
+	return data.Resolved.Channels[channelID]
+}
+
+func getRoleOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range opts {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionRole {
+			return opt.RoleValue(nil, "").ID
+		}
+	}
+	return ""
+}
+
+func getIntOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string, fallback int) int {
+	for _, opt := range opts {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionInteger {
+			return int(opt.IntValue())
+		}
+	}
+	return fallback
+}
+
+func getBoolOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string, fallback bool) bool {
+	for _, opt := range opts {
+		if opt.Name == name && opt.Type == discordgo.ApplicationCommandOptionBoolean {
+			return opt.BoolValue()
+		}
+	}
+	return fallback
+}
+
+// limitMessage explains which of the server's limits err hit.
+func limitMessage(err *services.LimitError) string {
+	switch err.Resource {
+	case services.LimitTibiaGuilds:
+		return formatting.MsgTibiaGuildLimit(err.Limit)
+	case services.LimitIgnoredPlayers:
+		return formatting.MsgIgnoredPlayerLimit(err.Limit)
+	case services.LimitWatchedPlayers:
+		return formatting.MsgWatchedPlayerLimit(err.Limit)
+	default:
+		return formatting.MsgWorldLimit(err.Limit)
+	}
+}
+
+// lookupFailed reports a TibiaData lookup that failed upstream, as opposed to
+// a name that does not exist. Trying again later may succeed.
+func lookupFailed(err error) bool {
+	return errors.Is(err, domain.ErrUpstreamDown) || errors.Is(err, domain.ErrRateLimited) || errors.Is(err, domain.ErrParse)
+}
+
+// capabilityConfig returns the guild's config for a capability check, or a
+// bare one before the guild has any so the premium allowlist still applies.
+func (h *BotHandler) capabilityConfig(ctx context.Context, guildID string) (domain.GuildConfig, error) {
+	cfg, err := h.Service.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return domain.GuildConfig{}, err
+	}
+	if cfg == nil {
+		return domain.GuildConfig{DiscordGuildID: guildID}, nil
+	}
+	return *cfg, nil
+}
@@ -1,12 +1,56 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+func TestRespondDeferred(t *testing.T) {
+	t.Run("defers then edits", func(t *testing.T) {
+		session := &mockDiscordSession{}
+		ran := false
+
+		respondDeferred(session, makeInteraction("slow", discordgo.InteractionApplicationCommand), false, func(ctx context.Context) string {
+			if session.lastInteractionResponse == nil {
+				t.Error("expected response to be deferred before work runs")
+			}
+			ran = true
+			return "done"
+		})
+
+		if !ran {
+			t.Fatal("expected work to run")
+		}
+		resp := session.lastInteractionResponse
+		if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || resp.Data.Flags != 0 {
+			t.Errorf("expected a public deferred response, got %+v", resp)
+		}
+		if session.editedContent() != "done" {
+			t.Errorf("expected edit 'done', got '%s'", session.editedContent())
+		}
+	})
+
+	t.Run("skips work when defer fails", func(t *testing.T) {
+		session := &mockDiscordSession{
+			interactionRespondFunc: func(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) error {
+				return errors.New("unknown interaction")
+			},
+		}
+
+		respondDeferred(session, makeInteraction("slow", discordgo.InteractionApplicationCommand), true, func(ctx context.Context) string {
+			t.Error("work should not run")
+			return ""
+		})
+
+		if session.lastResponseEdit != nil {
+			t.Error("expected no edit")
+		}
+	})
+}
+
 func TestRespond(t *testing.T) {
 	t.Run("ephemeral message", func(t *testing.T) {
 		session := &mockDiscordSession{}
@@ -1,15 +1,76 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
 	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/core/domain"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 type Middleware func(CommandHandler) CommandHandler
 
+// WithRecovery stops a panicking handler from taking down the gateway
+// goroutine, logging the stack and telling the user the command failed.
+func WithRecovery(next CommandHandler) CommandHandler {
+	return func(s DiscordSession, i *discordgo.InteractionCreate) {
+		defer func() {
+			if r := recover(); r != nil {
+				name := interactionName(i)
+				metrics.CommandPanics.WithLabelValues(name).Inc()
+				slog.Error("Command handler panicked", "command", name, "panic", r, "stack", string(debug.Stack()))
+				if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+					respond(s, i, formatting.MsgCommandError, true)
+				}
+			}
+		}()
+		next(s, i)
+	}
+}
+
+// WithLogging logs every interaction once its handler returns.
+func WithLogging(next CommandHandler) CommandHandler {
+	return func(s DiscordSession, i *discordgo.InteractionCreate) {
+		start := time.Now()
+		defer func() {
+			slog.Info("Handled interaction",
+				"command", interactionName(i),
+				"type", i.Type,
+				"guild_id", i.GuildID,
+				"user_id", invokingUserID(i),
+				"duration", time.Since(start))
+		}()
+		next(s, i)
+	}
+}
+
+// WithMetrics records handler latency per command.
+func WithMetrics(next CommandHandler) CommandHandler {
+	return func(s DiscordSession, i *discordgo.InteractionCreate) {
+		start := time.Now()
+		defer func() {
+			metrics.CommandDuration.WithLabelValues(interactionName(i)).Observe(time.Since(start).Seconds())
+		}()
+		next(s, i)
+	}
+}
+
+// WithAdmin answers members without the Administrator permission instead of
+// running the command. Page buttons are exempt, as anyone who sees a paginated
+// reply may page through it.
 func WithAdmin(next CommandHandler) CommandHandler {
 	return func(s DiscordSession, i *discordgo.InteractionCreate) {
+		if isComponentInteraction(i.Type) && interactionName(i) == PageRoute {
+			next(s, i)
+			return
+		}
 		if i.Member == nil || i.Member.Permissions&discordgo.PermissionAdministrator == 0 {
 			respond(s, i, formatting.MsgAdminRequired, true)
 			return
@@ -17,3 +78,224 @@ func WithAdmin(next CommandHandler) CommandHandler {
 		next(s, i)
 	}
 }
+
+// cooldownPruneSize is how many users a cooldown remembers before expired
+// entries are swept.
+const cooldownPruneSize = 256
+
+// WithCooldown lets each user run a wrapped command at most once per period
+// and answers earlier attempts ephemerally. Every command wrapped keeps its own
+// cooldowns, and autocomplete requests are not limited.
+func WithCooldown(period time.Duration) Middleware {
+	return withCooldown(period, time.Now)
+}
+
+func withCooldown(period time.Duration, now func() time.Time) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		var mu sync.Mutex
+		nextAllowed := make(map[string]time.Time)
+
+		return func(s DiscordSession, i *discordgo.InteractionCreate) {
+			if i.Type != discordgo.InteractionApplicationCommand {
+				next(s, i)
+				return
+			}
+
+			userID := invokingUserID(i)
+			t := now()
+
+			mu.Lock()
+			until, limited := nextAllowed[userID]
+			limited = limited && t.Before(until)
+			if !limited {
+				if len(nextAllowed) >= cooldownPruneSize {
+					for id, u := range nextAllowed {
+						if !t.Before(u) {
+							delete(nextAllowed, id)
+						}
+					}
+				}
+				nextAllowed[userID] = t.Add(period)
+			}
+			mu.Unlock()
+
+			if limited {
+				respond(s, i, formatting.MsgCooldown(until), true)
+				return
+			}
+			next(s, i)
+		}
+	}
+}
+
+// GuildConfigLookup loads a guild's configuration.
+type GuildConfigLookup interface {
+	GetGuildConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error)
+}
+
+// WithResponseVisibility applies the guild's domain.ResponseVisibility to the
+// messages handlers answer with, so respond and the other helpers follow it
+// whatever each handler asked for. The configuration is loaded on the first
+// answer, so autocomplete and modals cost no lookup. Answers carrying
+// components, such as confirmation prompts and the setup wizard, keep their
+// visibility: their buttons are for the admin who ran the command.
+func WithResponseVisibility(configs GuildConfigLookup) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(s DiscordSession, i *discordgo.InteractionCreate) {
+			if i.GuildID == "" {
+				next(s, i)
+				return
+			}
+			next(&visibilitySession{DiscordSession: s, guildID: i.GuildID, configs: configs}, i)
+		}
+	}
+}
+
+// visibilitySession rewrites the ephemeral flag of interaction responses
+// that create a message. Responses are copied rather than changed, so
+// WithAudit still sees what the handler sent.
+type visibilitySession struct {
+	DiscordSession
+	guildID    string
+	configs    GuildConfigLookup
+	visibility *domain.ResponseVisibility
+}
+
+func (v *visibilitySession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	if rewritable(resp) {
+		resp = withVisibility(resp, v.lookup())
+	}
+	return v.DiscordSession.InteractionRespond(interaction, resp, options...)
+}
+
+func (v *visibilitySession) lookup() domain.ResponseVisibility {
+	if v.visibility != nil {
+		return *v.visibility
+	}
+	visibility := domain.VisibilityDefault
+	cfg, err := v.configs.GetGuildConfig(context.Background(), v.guildID)
+	if err != nil {
+		slog.Debug("Response visibility not loaded", "guild_id", v.guildID, "error", err)
+	} else if cfg != nil {
+		visibility = cfg.ResponseVisibility
+	}
+	v.visibility = &visibility
+	return visibility
+}
+
+// rewritable reports whether resp creates a message without components.
+func rewritable(resp *discordgo.InteractionResponse) bool {
+	if resp.Data == nil || len(resp.Data.Components) > 0 {
+		return false
+	}
+	return resp.Type == discordgo.InteractionResponseChannelMessageWithSource || resp.Type == discordgo.InteractionResponseDeferredChannelMessageWithSource
+}
+
+// withVisibility returns resp with its ephemeral flag set as visibility asks,
+// or resp itself when it already matches.
+func withVisibility(resp *discordgo.InteractionResponse, visibility domain.ResponseVisibility) *discordgo.InteractionResponse {
+	flags := resp.Data.Flags
+	switch visibility {
+	case domain.VisibilityEphemeral:
+		flags |= discordgo.MessageFlagsEphemeral
+	case domain.VisibilityPublic:
+		flags &^= discordgo.MessageFlagsEphemeral
+	}
+	if flags == resp.Data.Flags {
+		return resp
+	}
+	data := *resp.Data
+	data.Flags = flags
+	return &discordgo.InteractionResponse{Type: resp.Type, Data: &data}
+}
+
+// AuditLogger posts audit entries to a channel looked up by name.
+type AuditLogger interface {
+	SendAuditLog(guildID, channelName, message string) error
+}
+
+// WithAudit records configuration changes in channelName together with the
+// invoking user. Servers opt in by creating the channel. Handlers answer
+// failures ephemerally, so only commands that got a public response are
+// logged. Confirmation buttons are logged under the command that showed them
+// when they update their message.
+func WithAudit(logger AuditLogger, channelName string) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(s DiscordSession, i *discordgo.InteractionCreate) {
+			if i.Type != discordgo.InteractionApplicationCommand && i.Type != discordgo.InteractionMessageComponent {
+				next(s, i)
+				return
+			}
+
+			rec := &responseRecorder{DiscordSession: s}
+			next(rec, i)
+			if rec.response == nil || rec.ephemeral() {
+				return
+			}
+
+			var msg string
+			if i.Type == discordgo.InteractionMessageComponent {
+				msg = formatting.MsgAuditEntry(invokingUserID(i), componentCommand(i), nil)
+			} else {
+				data := i.ApplicationCommandData()
+				msg = formatting.MsgAuditEntry(invokingUserID(i), data.Name, auditOptions(data.Options))
+			}
+			if err := logger.SendAuditLog(i.GuildID, channelName, msg); err != nil {
+				slog.Debug("Audit entry not posted", "guild_id", i.GuildID, "channel", channelName, "error", err)
+			}
+		}
+	}
+}
+
+// responseRecorder remembers the interaction response a handler sent.
+type responseRecorder struct {
+	DiscordSession
+	response *discordgo.InteractionResponse
+}
+
+func (r *responseRecorder) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	r.response = resp
+	return r.DiscordSession.InteractionRespond(interaction, resp, options...)
+}
+
+func (r *responseRecorder) ephemeral() bool {
+	return r.response.Data != nil && r.response.Data.Flags&discordgo.MessageFlagsEphemeral != 0
+}
+
+// componentCommand is the slash command whose response carried the clicked
+// component.
+func componentCommand(i *discordgo.InteractionCreate) string {
+	if i.Message != nil && i.Message.Interaction != nil {
+		return i.Message.Interaction.Name
+	}
+	return interactionName(i)
+}
+
+func invokingUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// auditOptions renders command options as "name: value", with channels and
+// roles as mentions.
+func auditOptions(opts []*discordgo.ApplicationCommandInteractionDataOption) []string {
+	rendered := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		var value string
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionChannel:
+			value = "<#" + opt.ChannelValue(nil).ID + ">"
+		case discordgo.ApplicationCommandOptionRole:
+			value = formatting.MsgRoleMention(opt.RoleValue(nil, "").ID)
+		default:
+			value = fmt.Sprint(opt.Value)
+		}
+		rendered = append(rendered, opt.Name+": "+value)
+	}
+	return rendered
+}
@@ -1,11 +1,16 @@
 package commands
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/core/domain"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestWithAdmin_AllowsAdminUser(t *testing.T) {
@@ -148,6 +153,292 @@ func TestMiddleware_TypeSignature(t *testing.T) {
 	var _ Middleware = WithAdmin
 }
 
+func TestWithRecovery(t *testing.T) {
+	session := &mockDiscordSession{}
+	before := testutil.ToFloat64(metrics.CommandPanics.WithLabelValues("panics"))
+
+	handler := WithRecovery(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		panic("boom")
+	})
+	handler(session, makeInteraction("panics", discordgo.InteractionApplicationCommand))
+
+	if session.lastInteractionResponse == nil || session.lastInteractionResponse.Data.Content != formatting.MsgCommandError {
+		t.Errorf("expected error reply, got %+v", session.lastInteractionResponse)
+	}
+	if session.lastInteractionResponse != nil && session.lastInteractionResponse.Data.Flags != discordgo.MessageFlagsEphemeral {
+		t.Error("error reply should be ephemeral")
+	}
+	if got := testutil.ToFloat64(metrics.CommandPanics.WithLabelValues("panics")) - before; got != 1 {
+		t.Errorf("expected panic to be counted once, got %v", got)
+	}
+}
+
+func TestWithRecovery_NoReplyForAutocomplete(t *testing.T) {
+	session := &mockDiscordSession{}
+
+	handler := WithRecovery(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		panic("boom")
+	})
+	handler(session, makeInteraction("panics", discordgo.InteractionApplicationCommandAutocomplete))
+
+	if session.lastInteractionResponse != nil {
+		t.Error("autocomplete should not get a message reply")
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	before := testutil.CollectAndCount(metrics.CommandDuration)
+	called := false
+
+	handler := WithMetrics(func(s DiscordSession, i *discordgo.InteractionCreate) { called = true })
+	handler(&mockDiscordSession{}, makeInteraction("timed-command", discordgo.InteractionApplicationCommand))
+
+	if !called {
+		t.Error("handler should be called")
+	}
+	if got := testutil.CollectAndCount(metrics.CommandDuration); got != before+1 {
+		t.Errorf("expected a new duration series, got %d series (was %d)", got, before)
+	}
+}
+
+func TestWithLogging_CallsHandler(t *testing.T) {
+	called := false
+
+	handler := WithLogging(func(s DiscordSession, i *discordgo.InteractionCreate) { called = true })
+	handler(&mockDiscordSession{}, auditedInteraction("track-world"))
+
+	if !called {
+		t.Error("handler should be called")
+	}
+}
+
+func TestWithCooldown(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cooldown := withCooldown(10*time.Second, func() time.Time { return now })
+
+	calls := map[string]int{}
+	stats := cooldown(func(s DiscordSession, i *discordgo.InteractionCreate) { calls["stats"]++ })
+	status := cooldown(func(s DiscordSession, i *discordgo.InteractionCreate) { calls["status"]++ })
+
+	interaction := func(userID string) *discordgo.InteractionCreate {
+		i := auditedInteraction("stats")
+		i.Member.User.ID = userID
+		return i
+	}
+
+	stats(&mockDiscordSession{}, interaction("user-1"))
+
+	session := &mockDiscordSession{}
+	stats(session, interaction("user-1"))
+	if calls["stats"] != 1 {
+		t.Errorf("expected repeat within cooldown to be blocked, got %d calls", calls["stats"])
+	}
+	if session.lastInteractionResponse == nil || session.lastInteractionResponse.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("expected an ephemeral cooldown reply")
+	} else if want := formatting.MsgCooldown(now.Add(10 * time.Second)); session.lastInteractionResponse.Data.Content != want {
+		t.Errorf("expected %q, got %q", want, session.lastInteractionResponse.Data.Content)
+	}
+
+	stats(&mockDiscordSession{}, interaction("user-2"))
+	status(&mockDiscordSession{}, interaction("user-1"))
+	if calls["stats"] != 2 || calls["status"] != 1 {
+		t.Errorf("expected cooldowns per user and per command, got %v", calls)
+	}
+
+	now = now.Add(10 * time.Second)
+	stats(&mockDiscordSession{}, interaction("user-1"))
+	if calls["stats"] != 3 {
+		t.Errorf("expected command to run after cooldown, got %d calls", calls["stats"])
+	}
+}
+
+func TestWithCooldown_IgnoresAutocomplete(t *testing.T) {
+	calls := 0
+	handler := WithCooldown(time.Hour)(func(s DiscordSession, i *discordgo.InteractionCreate) { calls++ })
+
+	for range 3 {
+		i := auditedInteraction("sync-guild")
+		i.Type = discordgo.InteractionApplicationCommandAutocomplete
+		handler(&mockDiscordSession{}, i)
+	}
+	if calls != 3 {
+		t.Errorf("expected autocomplete to bypass cooldown, got %d calls", calls)
+	}
+}
+
+type mockAuditLogger struct {
+	entries []string
+	err     error
+}
+
+func (m *mockAuditLogger) SendAuditLog(guildID, channelName, message string) error {
+	m.entries = append(m.entries, guildID+"|"+channelName+"|"+message)
+	return m.err
+}
+
+func auditedInteraction(name string, opts ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type:    discordgo.InteractionApplicationCommand,
+			GuildID: "guild-1",
+			Member:  &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:    name,
+				Options: opts,
+			},
+		},
+	}
+}
+
+func TestWithAudit_LogsSuccessfulChange(t *testing.T) {
+	logger := &mockAuditLogger{}
+	handler := WithAudit(logger, "tracker-audit")(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		respond(s, i, "Added guild.", false)
+	})
+
+	session := &mockDiscordSession{}
+	handler(session, auditedInteraction("set-channel",
+		&discordgo.ApplicationCommandInteractionDataOption{Name: "type", Type: discordgo.ApplicationCommandOptionString, Value: "deaths"},
+		&discordgo.ApplicationCommandInteractionDataOption{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "123"},
+	))
+
+	if session.lastInteractionResponse == nil {
+		t.Fatal("expected the handler's response to reach the session")
+	}
+	want := "guild-1|tracker-audit|" + formatting.MsgAuditEntry("user-1", "set-channel", []string{"type: deaths", "channel: <#123>"})
+	if len(logger.entries) != 1 || logger.entries[0] != want {
+		t.Errorf("expected %q, got %v", want, logger.entries)
+	}
+}
+
+func TestWithAudit_SkipsFailures(t *testing.T) {
+	logger := &mockAuditLogger{}
+	handler := WithAudit(logger, "tracker-audit")(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		respond(s, i, formatting.MsgSaveError, true)
+	})
+
+	handler(&mockDiscordSession{}, auditedInteraction("add-guild"))
+
+	if len(logger.entries) != 0 {
+		t.Errorf("expected ephemeral failure not to be audited, got %v", logger.entries)
+	}
+}
+
+func TestWithAudit_SkipsAutocomplete(t *testing.T) {
+	logger := &mockAuditLogger{}
+	called := false
+	handler := WithAudit(logger, "tracker-audit")(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		called = true
+		respondAutocomplete(s, i, nil)
+	})
+
+	i := auditedInteraction("unset-guild")
+	i.Type = discordgo.InteractionApplicationCommandAutocomplete
+	handler(&mockDiscordSession{}, i)
+
+	if !called || len(logger.entries) != 0 {
+		t.Errorf("expected handler to run without audit, called=%v entries=%v", called, logger.entries)
+	}
+}
+
+func TestWithAudit_LogsConfirmedComponent(t *testing.T) {
+	logger := &mockAuditLogger{}
+	handler := WithAudit(logger, "tracker-audit")(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		updateMessage(s, i, formatting.MsgStopSuccess)
+	})
+
+	i := makeComponentInteraction("guild-1", componentID(StopTrackingConfirmRoute, "1"))
+	i.Member = &discordgo.Member{User: &discordgo.User{ID: "user-1"}}
+	i.Message = &discordgo.Message{Interaction: &discordgo.MessageInteraction{Name: "stop-tracking"}}
+	handler(&mockDiscordSession{}, i)
+
+	want := "guild-1|tracker-audit|" + formatting.MsgAuditEntry("user-1", "stop-tracking", nil)
+	if len(logger.entries) != 1 || logger.entries[0] != want {
+		t.Errorf("expected %q, got %v", want, logger.entries)
+	}
+}
+
+func visibilityConfigs(visibility domain.ResponseVisibility, lookups *int) *mockStorage {
+	return &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			*lookups++
+			return &domain.GuildConfig{DiscordGuildID: guildID, ResponseVisibility: visibility}, nil
+		},
+	}
+}
+
+func TestWithResponseVisibility(t *testing.T) {
+	tests := []struct {
+		visibility domain.ResponseVisibility
+		ephemeral  bool
+		want       bool
+	}{
+		{domain.VisibilityDefault, false, false},
+		{domain.VisibilityDefault, true, true},
+		{domain.VisibilityEphemeral, false, true},
+		{domain.VisibilityPublic, true, false},
+	}
+	for _, tt := range tests {
+		var lookups int
+		handler := WithResponseVisibility(visibilityConfigs(tt.visibility, &lookups))(func(s DiscordSession, i *discordgo.InteractionCreate) {
+			respond(s, i, "Done.", tt.ephemeral)
+		})
+
+		session := &mockDiscordSession{}
+		handler(session, auditedInteraction("set-language"))
+
+		got := session.lastInteractionResponse.Data.Flags&discordgo.MessageFlagsEphemeral != 0
+		if got != tt.want || lookups != 1 {
+			t.Errorf("visibility %q, ephemeral %v: expected ephemeral %v after one lookup, got %v after %d", tt.visibility, tt.ephemeral, tt.want, got, lookups)
+		}
+	}
+}
+
+func TestWithResponseVisibility_KeepsPromptsAndSkipsAutocomplete(t *testing.T) {
+	var lookups int
+	configs := visibilityConfigs(domain.VisibilityPublic, &lookups)
+
+	confirm := WithResponseVisibility(configs)(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		respondConfirm(s, i, formatting.MsgStopConfirm, formatting.MsgStopConfirmButton, StopTrackingConfirmRoute, StopTrackingCancelRoute)
+	})
+	session := &mockDiscordSession{}
+	confirm(session, auditedInteraction("stop-tracking"))
+	if session.lastInteractionResponse.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("expected the confirmation prompt to stay ephemeral")
+	}
+
+	lookups = 0
+	autocomplete := WithResponseVisibility(configs)(func(s DiscordSession, i *discordgo.InteractionCreate) {
+		respondAutocomplete(s, i, nil)
+	})
+	i := auditedInteraction("unset-guild")
+	i.Type = discordgo.InteractionApplicationCommandAutocomplete
+	autocomplete(&mockDiscordSession{}, i)
+	if lookups != 0 {
+		t.Errorf("expected no lookup for autocomplete, got %d", lookups)
+	}
+}
+
+func TestWithResponseVisibility_AuditSeesHandlerChoice(t *testing.T) {
+	var lookups int
+	logger := &mockAuditLogger{}
+	handler := WithResponseVisibility(visibilityConfigs(domain.VisibilityEphemeral, &lookups))(
+		WithAudit(logger, "tracker-audit")(func(s DiscordSession, i *discordgo.InteractionCreate) {
+			respond(s, i, "Added guild.", false)
+		}),
+	)
+
+	session := &mockDiscordSession{}
+	handler(session, auditedInteraction("add-guild"))
+
+	if session.lastInteractionResponse.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("expected the reply to be ephemeral")
+	}
+	if len(logger.entries) != 1 {
+		t.Errorf("expected the change to be audited, got %v", logger.entries)
+	}
+}
+
 func interactionWithPermissions(perms int64) *discordgo.InteractionCreate {
 	return &discordgo.InteractionCreate{
 		Interaction: &discordgo.Interaction{
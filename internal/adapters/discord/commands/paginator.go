@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// PageRoute routes the ◀ and ▶ buttons of paginated replies.
+const PageRoute = "page"
+
+// pageTTL is how long the pages of a reply are kept for its buttons. Unlike
+// the setup wizard, pages are too large for custom IDs and live in memory on
+// the replica that sent the reply; a click after they expired, or on another
+// replica, removes the buttons and leaves the page shown.
+const pageTTL = 15 * time.Minute
+
+// Paginator answers list commands whose output does not fit one message with
+// a page and buttons to move between pages, keeping the pages by the ID of
+// the reply.
+type Paginator struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	lists map[string]pagedList
+}
+
+type pagedList struct {
+	pages   []string
+	expires time.Time
+}
+
+func NewPaginator() *Paginator {
+	return &Paginator{
+		now:   time.Now,
+		lists: make(map[string]pagedList),
+	}
+}
+
+// Respond answers i with the first of pages, with buttons when there are
+// more. A nil Paginator only sends the first page.
+func (p *Paginator) Respond(s DiscordSession, i *discordgo.InteractionCreate, pages []string, ephemeral bool) {
+	if len(pages) == 0 {
+		return
+	}
+	if p == nil || len(pages) == 1 {
+		respond(s, i, pages[0], ephemeral)
+		return
+	}
+
+	// The reply is deferred and then edited because only the edit returns
+	// the message, whose ID keys the pages.
+	if err := deferResponse(s, i, ephemeral); err != nil {
+		slog.Warn("Failed to defer response", "command", interactionName(i), "error", err)
+		return
+	}
+	components := pageButtons(0, len(pages))
+	msg, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &pages[0], Components: &components})
+	if err != nil {
+		slog.Warn("Failed to edit deferred response", "command", interactionName(i), "error", err)
+		return
+	}
+	p.store(msg.ID, pages)
+}
+
+// HandleButton shows the page a ◀ or ▶ button points to.
+func (p *Paginator) HandleButton(s DiscordSession, i *discordgo.InteractionCreate) {
+	var page int
+	var err error
+	if args := componentArgs(i); len(args) == 1 {
+		page, err = strconv.Atoi(args[0])
+	} else {
+		err = fmt.Errorf("unexpected page button arguments %q", args)
+	}
+	if err != nil {
+		slog.Warn("Invalid page button", "custom_id", customID(i), "error", err)
+		return
+	}
+
+	var messageID, content string
+	if i.Message != nil {
+		messageID, content = i.Message.ID, i.Message.Content
+	}
+	pages, ok := p.load(messageID)
+	if !ok || page < 0 || page >= len(pages) {
+		updateMessage(s, i, content)
+		return
+	}
+
+	components := pageButtons(page, len(pages))
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    pages[page],
+			Components: components,
+		},
+	})
+}
+
+func (p *Paginator) store(messageID string, pages []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for id, list := range p.lists {
+		if now.After(list.expires) {
+			delete(p.lists, id)
+		}
+	}
+	p.lists[messageID] = pagedList{pages: pages, expires: now.Add(pageTTL)}
+}
+
+func (p *Paginator) load(messageID string) ([]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list, ok := p.lists[messageID]
+	if !ok || p.now().After(list.expires) {
+		delete(p.lists, messageID)
+		return nil, false
+	}
+	return list.pages, true
+}
+
+// pageButtons are ◀ and ▶ around a disabled button showing which of total
+// pages is shown.
+func pageButtons(page, total int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "◀",
+				Style:    discordgo.SecondaryButton,
+				CustomID: componentID(PageRoute, strconv.Itoa(page-1)),
+				Disabled: page == 0,
+			},
+			discordgo.Button{
+				Label:    fmt.Sprintf("%d/%d", page+1, total),
+				Style:    discordgo.SecondaryButton,
+				CustomID: componentID(PageRoute, "current"),
+				Disabled: true,
+			},
+			discordgo.Button{
+				Label:    "▶",
+				Style:    discordgo.SecondaryButton,
+				CustomID: componentID(PageRoute, strconv.Itoa(page+1)),
+				Disabled: page == total-1,
+			},
+		}},
+	}
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func clickPage(messageID, content, customID string) *discordgo.InteractionCreate {
+	i := makeComponentInteraction("guild-1", customID)
+	i.Message = &discordgo.Message{ID: messageID, Content: content}
+	return i
+}
+
+func pageButtonsOf(t *testing.T, components []discordgo.MessageComponent) []discordgo.Button {
+	t.Helper()
+	if len(components) != 1 {
+		t.Fatalf("expected one row of page buttons, got %+v", components)
+	}
+	var buttons []discordgo.Button
+	for _, c := range components[0].(discordgo.ActionsRow).Components {
+		buttons = append(buttons, c.(discordgo.Button))
+	}
+	return buttons
+}
+
+func TestPaginator(t *testing.T) {
+	pages := []string{"page 1", "page 2", "page 3"}
+	session := &mockDiscordSession{}
+	p := NewPaginator()
+	p.Respond(session, makeCommandInteraction("guild-1", "", ""), pages, false)
+
+	if session.lastInteractionResponse.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("expected a deferred response, got %v", session.lastInteractionResponse.Type)
+	}
+	if edit := session.lastResponseEdit; edit == nil || *edit.Content != "page 1" {
+		t.Fatalf("expected the first page, got %+v", edit)
+	}
+	buttons := pageButtonsOf(t, *session.lastResponseEdit.Components)
+	if !buttons[0].Disabled || buttons[1].Label != "1/3" || buttons[2].Disabled {
+		t.Errorf("unexpected buttons on the first page: %+v", buttons)
+	}
+
+	p.HandleButton(session, clickPage("response-message", "page 1", buttons[2].CustomID))
+	if got := session.lastInteractionResponse; got.Type != discordgo.InteractionResponseUpdateMessage || got.Data.Content != "page 2" {
+		t.Fatalf("expected page 2, got %+v", got)
+	}
+	buttons = pageButtonsOf(t, session.lastInteractionResponse.Data.Components)
+	p.HandleButton(session, clickPage("response-message", "page 2", buttons[2].CustomID))
+	buttons = pageButtonsOf(t, session.lastInteractionResponse.Data.Components)
+	if session.lastInteractionResponse.Data.Content != "page 3" || !buttons[2].Disabled || buttons[1].Label != "3/3" {
+		t.Errorf("expected the last page with ▶ disabled, got %+v", session.lastInteractionResponse.Data)
+	}
+}
+
+func TestPaginator_SinglePage(t *testing.T) {
+	session := &mockDiscordSession{}
+	NewPaginator().Respond(session, makeCommandInteraction("guild-1", "", ""), []string{"only page"}, false)
+
+	if got := session.lastInteractionResponse; got.Data.Content != "only page" || len(got.Data.Components) != 0 {
+		t.Errorf("expected a plain reply, got %+v", got.Data)
+	}
+	if session.lastResponseEdit != nil {
+		t.Error("expected no deferred edit for a single page")
+	}
+}
+
+func TestPaginator_Expired(t *testing.T) {
+	now := time.Now()
+	p := NewPaginator()
+	p.now = func() time.Time { return now }
+	session := &mockDiscordSession{}
+	p.Respond(session, makeCommandInteraction("guild-1", "", ""), []string{"page 1", "page 2"}, false)
+
+	now = now.Add(pageTTL + time.Minute)
+	p.HandleButton(session, clickPage("response-message", "page 1", componentID(PageRoute, "1")))
+	if got := session.lastInteractionResponse.Data; got.Content != "page 1" || len(got.Components) != 0 {
+		t.Errorf("expected the buttons removed from the shown page, got %+v", got)
+	}
+
+	p.HandleButton(session, clickPage("other-replica", "page 1", componentID(PageRoute, "1")))
+	if got := session.lastInteractionResponse.Data; got.Content != "page 1" || len(got.Components) != 0 {
+		t.Errorf("expected unknown messages to lose their buttons, got %+v", got)
+	}
+}
+
+func TestListGuilds_Paginated(t *testing.T) {
+	guilds := make([]string, 45)
+	for i := range guilds {
+		guilds[i] = fmt.Sprintf("Guild %d", i+1)
+	}
+	storage := &mockStorage{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{TibiaGuilds: guilds}, nil
+		},
+	}
+
+	session := &mockDiscordSession{}
+	newTestHandler(storage).ListGuilds(session, makeCommandInteraction("guild-1", "", ""))
+
+	if session.lastResponseEdit == nil || session.lastResponseEdit.Components == nil {
+		t.Fatal("expected a paginated reply")
+	}
+	if buttons := pageButtonsOf(t, *session.lastResponseEdit.Components); buttons[1].Label != "1/3" {
+		t.Errorf("expected 3 pages, got %q", buttons[1].Label)
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"log/slog"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type permission struct {
+	bit  int64
+	name string
+}
+
+// guildPermissions are needed to create the notification channels on
+// /track-world and post to them.
+var guildPermissions = []permission{
+	{discordgo.PermissionViewChannel, "View Channels"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+}
+
+// channelPermissions are needed in every channel notifications are posted to.
+var channelPermissions = []permission{
+	{discordgo.PermissionViewChannel, "View Channel"},
+	{discordgo.PermissionSendMessages, "Send Messages"},
+}
+
+func missingPermissions(granted int64, required []permission) []string {
+	if granted&discordgo.PermissionAdministrator != 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, p := range required {
+		if granted&p.bit == 0 {
+			missing = append(missing, p.name)
+		}
+	}
+	return missing
+}
+
+// auditChannel reports the permissions the bot lacks in a notification
+// channel, prefixed with the channel mention.
+func auditChannel(s DiscordSession, botID, channelID string) []string {
+	granted, err := s.UserChannelPermissions(botID, channelID)
+	if err != nil {
+		slog.Error("Failed to read channel permissions", "channel_id", channelID, "error", err)
+		return []string{formatting.MsgChannelPermissionsUnknown(channelID)}
+	}
+
+	var missing []string
+	for _, name := range missingPermissions(granted, channelPermissions) {
+		missing = append(missing, formatting.MsgChannelPermission(channelID, name))
+	}
+	return missing
+}
+
+// notificationChannelIDs returns the channels notifications are posted to:
+// the configured channel ID or, failing that, the default-named channel.
+func notificationChannelIDs(s DiscordSession, cfg *domain.GuildConfig, guildID, deathName, levelName string) []string {
+	var deathID, levelID string
+	if cfg != nil {
+		deathID, levelID = cfg.DeathChannelID, cfg.LevelChannelID
+	}
+
+	if deathID == "" || levelID == "" {
+		channels, err := s.GuildChannels(guildID)
+		if err != nil {
+			slog.Error("Failed to fetch guild channels", "guild_id", guildID, "error", err)
+		}
+		for _, ch := range channels {
+			if ch.Type != discordgo.ChannelTypeGuildText {
+				continue
+			}
+			if deathID == "" && ch.Name == deathName {
+				deathID = ch.ID
+			}
+			if levelID == "" && ch.Name == levelName {
+				levelID = ch.ID
+			}
+		}
+	}
+
+	var ids []string
+	if deathID != "" {
+		ids = append(ids, deathID)
+	}
+	if levelID != "" && levelID != deathID {
+		ids = append(ids, levelID)
+	}
+	return ids
+}
@@ -1,12 +1,43 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"log/slog"
 
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/services"
+
 	"github.com/bwmarrin/discordgo"
 )
 
-var adminPerms = int64(discordgo.PermissionAdministrator)
+var (
+	adminPerms   = int64(discordgo.PermissionAdministrator)
+	minPingLevel = float64(0)
+
+	minPollMinutes = float64(0)
+	maxPollMinutes = float64(24 * 60)
+	minMuteHours   = float64(0)
+	maxMuteHours   = float64(7 * 24)
+	minSkillValue  = float64(1)
+
+	minMassDeaths           = float64(0)
+	maxMassDeaths           = float64(50)
+	minMassDeathMinutes     = float64(1)
+	maxMassDeathMinutes     = float64(60)
+	defaultMassDeathMinutes = 10
+
+	minGuildLevel = float64(0)
+	maxGuildLevel = float64(services.MaxMinLevel)
+)
+
+// visibilityDefaultChoice stands for domain.VisibilityDefault, as choice
+// values cannot be empty.
+const visibilityDefaultChoice = "default"
+
+// verbosityNormalChoice stands for domain.VerbosityNormal for the same reason.
+const verbosityNormalChoice = "normal"
 
 func GetApplicationCommands() []*discordgo.ApplicationCommand {
 	return []*discordgo.ApplicationCommand{
@@ -28,7 +59,7 @@ func GetApplicationCommands() []*discordgo.ApplicationCommand {
 			Description:              "Add a Tibia guild to the tracking whitelist",
 			DefaultMemberPermissions: &adminPerms,
 			Options: []*discordgo.ApplicationCommandOption{
-				stringOption("name", "Name of the Tibia guild", true, false),
+				stringOption("name", "Name of the Tibia guild", true, true),
 			},
 		},
 		{
@@ -39,12 +70,534 @@ func GetApplicationCommands() []*discordgo.ApplicationCommand {
 				stringOption("name", "Name of the Tibia guild", true, true),
 			},
 		},
+		{
+			Name:                     "ignore-player",
+			Description:              "Never announce deaths or level ups of a character",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("name", "Name of the character", true, true),
+			},
+		},
+		{
+			Name:                     "unignore-player",
+			Description:              "Resume notifications for an ignored character",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("name", "Name of the character", true, true),
+			},
+		},
 		{
 			Name:                     "list-guilds",
 			Description:              "List all tracked Tibia guilds",
 			DefaultMemberPermissions: &adminPerms,
 		},
+		{
+			Name:                     "sync-guild",
+			Description:              "Import current levels of all members of a tracked Tibia guild",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("name", "Name of the Tibia guild", true, true),
+			},
+		},
+		{
+			Name:                     "set-language",
+			Description:              "Set the language used for notifications",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("language", "Notification language", true, false), languageChoices()),
+			},
+		},
+		{
+			Name:                     "set-channel",
+			Description:              "Set the channel used for death, level or daily info notifications",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("type", "Notification type", true, false), []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "deaths", Value: string(domain.ChannelDeaths)},
+					{Name: "levels", Value: string(domain.ChannelLevels)},
+					{Name: "misc", Value: string(domain.ChannelMisc)},
+				}),
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Text channel or thread for notifications",
+					Required:     true,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread},
+				},
+			},
+		},
+		{
+			Name:                     "set-ping-role",
+			Description:              "Mention a role when a high-level player dies",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Name:        "role",
+					Description: "Role to mention",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "min-level",
+					Description: "Minimum level at death to mention the role",
+					MinValue:    &minPingLevel,
+				},
+			},
+		},
+		{
+			Name:                     "set-poll-interval",
+			Description:              "Set how often the tracked world is checked",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "minutes",
+					Description: "Polling interval in minutes (0 restores the default)",
+					Required:    true,
+					MinValue:    &minPollMinutes,
+					MaxValue:    maxPollMinutes,
+				},
+			},
+		},
+		{
+			Name:                     "mute-tracker",
+			Description:              "Pause all notifications for a number of hours",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "hours",
+					Description: "How long to stay muted (0 unmutes)",
+					Required:    true,
+					MinValue:    &minMuteHours,
+					MaxValue:    maxMuteHours,
+				},
+			},
+		},
+		{
+			Name:                     "set-low-level-deaths",
+			Description:              "Announce deaths of tracked guild members below the minimum level",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Announce low-level member deaths",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "set-min-level",
+			Description:              "Only announce deaths and level ups from this level up",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "level",
+					Description: "Lowest announced level (0 uses the bot's minimum)",
+					Required:    true,
+					MinValue:    &minGuildLevel,
+					MaxValue:    maxGuildLevel,
+				},
+			},
+		},
+		{
+			Name:                     "deaths-today",
+			Description:              "List today's deaths on the tracked world",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "retry-failed",
+			Description:              "Retry notifications that failed to send",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "check-permissions",
+			Description:              "Check the bot has the permissions it needs",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "help",
+			Description:              "List the commands and what is set up on this server",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "track-status",
+			Description:              "Show this server's tracking configuration",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "purge-data",
+			Description:              "Delete all data stored for this server",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "top-killers",
+			Description:              "Rank the characters that killed the most tracked players",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("window", "Time range (defaults to the last 7 days)", false, false), windowChoices(topKillersWindows)),
+			},
+		},
+		{
+			Name:                     "compare",
+			Description:              "Compare the levels and leveling pace of two characters",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("player1", "Name of the first character", true, true),
+				stringOption("player2", "Name of the second character", true, true),
+			},
+		},
+		{
+			Name:                     "track-houses",
+			Description:              "Announce house auctions on the tracked world",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Announce auctions that start and end",
+					Required:    true,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Text channel for auctions (defaults to this channel)",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+				},
+			},
+		},
+		{
+			Name:                     "rashid",
+			Description:              "Show which city Rashid is in today",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "pace",
+			Description:              "Estimate a character's levels per day and its level in 30 days",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("player", "Name of the character", true, true),
+			},
+		},
+		{
+			Name:                     "set-timezone",
+			Description:              "Set the timezone death times are shown in",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("timezone", "IANA timezone, e.g. Europe/Warsaw", true, false),
+			},
+		},
+		{
+			Name:                     "set-template",
+			Description:              "Customize death, level up or level down messages with {placeholders}",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("type", "Notification type", true, false), []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "deaths", Value: string(domain.ChannelDeaths)},
+					{Name: "levels", Value: string(domain.ChannelLevels)},
+					{Name: "level downs", Value: string(domain.ChannelLevelDowns)},
+				}),
+				stringOption("template", "e.g. {player} died at {level} to {reason}; leave empty for the default", false, false),
+			},
+		},
+		{
+			Name:                     "set-emoji",
+			Description:              "Prepend an emoji to death or level up messages and let the bot react to them",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("type", "Notification type", true, false), []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "deaths", Value: string(domain.ChannelDeaths)},
+					{Name: "levels", Value: string(domain.ChannelLevels)},
+				}),
+				stringOption("emoji", "Emoji or custom server emoji; leave empty for none", false, false),
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "react",
+					Description: "React to each message with the emoji (⚰️/🎉 without one)",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:                     "route-deaths",
+			Description:              "Post deaths at or above a level to their own channel",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "min-level",
+					Description: "Lowest level at death for this route",
+					Required:    true,
+					MinValue:    &minPingLevel,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel or thread for these deaths; leave empty to remove the route",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread},
+				},
+			},
+		},
+		{
+			Name:                     "set-quiet-hours",
+			Description:              "Post no notifications during a daily window in the server's timezone",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("window", "Window such as 02:00-08:00, or off", true, false),
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "catch-up",
+					Description: "Post missed deaths and level ups as one message when the window ends (default: true)",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:                     "export",
+			Description:              "Download this server's recorded deaths or level ups as a file",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("type", "What to export", true, false), []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "deaths", Value: string(domain.ChannelDeaths)},
+					{Name: "levels", Value: string(domain.ChannelLevels)},
+				}),
+				withChoices(stringOption("window", "Time range (defaults to the last 7 days)", false, false), windowChoices(exportWindows)),
+				withChoices(stringOption("format", "File format (defaults to CSV)", false, false), []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "CSV", Value: string(services.ExportCSV)},
+					{Name: "JSON", Value: string(services.ExportJSON)},
+				}),
+			},
+		},
+		{
+			Name:                     "set-share-range",
+			Description:              "Show the party experience share range in level up notifications",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Show the share range",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "set-death-location",
+			Description:              "Show where characters likely died, guessed from their killers",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Show the likely death location",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "set-level-downs",
+			Description:              "Announce characters losing two or more levels",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Announce level downs",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "set-response-visibility",
+			Description:              "Choose who sees the bot's replies to commands",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "visibility",
+					Description: "Who sees the replies",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "default (each command decides)", Value: visibilityDefaultChoice},
+						{Name: "ephemeral (only whoever ran the command)", Value: string(domain.VisibilityEphemeral)},
+						{Name: "public (everyone in the channel)", Value: string(domain.VisibilityPublic)},
+					},
+				},
+			},
+		},
+		{
+			Name:                     "set-death-verbosity",
+			Description:              "Choose how much death notifications tell",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "verbosity",
+					Description: "How much to tell",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "compact (name and reason)", Value: string(domain.VerbosityCompact)},
+						{Name: "normal (with vocation, guild and experience loss)", Value: verbosityNormalChoice},
+						{Name: "detailed (with killers, assists and links)", Value: string(domain.VerbosityDetailed)},
+					},
+				},
+			},
+		},
+		{
+			Name:                     "track-skills",
+			Description:              "Announce skill advances of watched characters",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Announce skill advances",
+					Required:    true,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel or thread for skill advances (defaults to this channel)",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread},
+				},
+			},
+		},
+		{
+			Name:                     "watch-player",
+			Description:              "Announce a character's skill advances with /track-skills",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("name", "Name of the character", true, true),
+			},
+		},
+		{
+			Name:                     "unwatch-player",
+			Description:              "Stop announcing a character's skill advances",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("name", "Name of the character", true, true),
+			},
+		},
+		{
+			Name:                     "set-skill-threshold",
+			Description:              "Only announce advances of a skill from a value on",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				withChoices(stringOption("skill", "Skill", true, false), skillChoices()),
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "min-value",
+					Description: "Lowest skill value to announce; leave empty to announce every advance",
+					MinValue:    &minSkillValue,
+				},
+			},
+		},
+		{
+			Name:                     "set-mass-death-alert",
+			Description:              "Warn of a possible war or raid when many tracked characters die close together",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "deaths",
+					Description: "How many characters must die (at least 2, 0 turns the alert off)",
+					Required:    true,
+					MinValue:    &minMassDeaths,
+					MaxValue:    maxMassDeaths,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "minutes",
+					Description: "Within how many minutes (default 10)",
+					MinValue:    &minMassDeathMinutes,
+					MaxValue:    maxMassDeathMinutes,
+				},
+			},
+		},
+		{
+			Name:                     "set-announcements",
+			Description:              "Receive announcements from the bot operator, such as maintenance notices",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Post operator announcements in the death channel",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:                     "resume-tracking",
+			Description:              "Restore the configuration removed by /stop-tracking within 30 days",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "activity",
+			Description:              "Chart when the tracked characters were online over the last two weeks",
+			DefaultMemberPermissions: &adminPerms,
+		},
+		{
+			Name:                     "frags",
+			Description:              "Rank the tracked characters by frags since server save, or show one character's",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("player", "Name of the character", false, true),
+			},
+		},
+		{
+			Name:                     "war-score",
+			Description:              "Count the kills between a tracked guild and its opponent during its last war",
+			DefaultMemberPermissions: &adminPerms,
+			Options: []*discordgo.ApplicationCommandOption{
+				stringOption("opponent", "Name of the Tibia guild at war with yours", true, true),
+				stringOption("guild", "Tracked Tibia guild (defaults to the only one tracked)", false, true),
+			},
+		},
+	}
+}
+
+func withChoices(opt *discordgo.ApplicationCommandOption, choices []*discordgo.ApplicationCommandOptionChoice) *discordgo.ApplicationCommandOption {
+	opt.Choices = choices
+	return opt
+}
+
+func windowChoices(windows []statsWindow) []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(windows))
+	for i, w := range windows {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  w.label,
+			Value: w.value,
+		}
 	}
+	return choices
+}
+
+func skillChoices() []*discordgo.ApplicationCommandOptionChoice {
+	catalog := formatting.CatalogFor(formatting.DefaultLanguage)
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(domain.Skills))
+	for i, skill := range domain.Skills {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  catalog.SkillName(skill),
+			Value: string(skill),
+		}
+	}
+	return choices
+}
+
+func languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	langs := formatting.SupportedLanguages()
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(langs))
+	for i, lang := range langs {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  formatting.CatalogFor(lang).Name,
+			Value: lang,
+		}
+	}
+	return choices
 }
 
 func stringOption(name, description string, required, autocomplete bool) *discordgo.ApplicationCommandOption {
@@ -57,6 +610,70 @@ func stringOption(name, description string, required, autocomplete bool) *discor
 	}
 }
 
+// SyncCommands brings the commands registered in guildID, or globally when
+// guildID is empty, in line with commands. New and changed commands are
+// created, unchanged ones are left alone so a restart does not re-create
+// them, and commands no longer defined are deleted. If the registered
+// commands cannot be listed, every command is created.
+func SyncCommands(session CommandSession, commands []*discordgo.ApplicationCommand, appID, guildID string) {
+	scope := guildID
+	if scope == "" {
+		scope = "global"
+	}
+
+	existing, err := session.ApplicationCommands(appID, guildID)
+	if err != nil {
+		slog.Error("Cannot list registered commands, registering all", "scope", scope, "error", err)
+		RegisterCommands(session, commands, appID, guildID)
+		return
+	}
+
+	registered := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		registered[cmd.Name] = cmd
+	}
+
+	var changed []*discordgo.ApplicationCommand
+	for _, cmd := range commands {
+		if have, ok := registered[cmd.Name]; !ok || commandChanged(cmd, have) {
+			changed = append(changed, cmd)
+		}
+		delete(registered, cmd.Name)
+	}
+
+	stale := make([]*discordgo.ApplicationCommand, 0, len(registered))
+	for _, cmd := range registered {
+		stale = append(stale, cmd)
+	}
+
+	CleanupCommands(session, stale, appID, guildID)
+	RegisterCommands(session, changed, appID, guildID)
+	slog.Info("Synced commands", "scope", scope, "unchanged", len(commands)-len(changed), "updated", len(changed), "removed", len(stale))
+}
+
+// commandChanged reports whether have, as registered with Discord, differs
+// from the definition want in anything users see. Fields Discord fills in
+// itself, such as the command type, are ignored.
+func commandChanged(want, have *discordgo.ApplicationCommand) bool {
+	if want.Description != have.Description {
+		return true
+	}
+	if (want.DefaultMemberPermissions == nil) != (have.DefaultMemberPermissions == nil) ||
+		want.DefaultMemberPermissions != nil && *want.DefaultMemberPermissions != *have.DefaultMemberPermissions {
+		return true
+	}
+
+	wantOpts, err := json.Marshal(want.Options)
+	if err != nil {
+		return true
+	}
+	haveOpts, err := json.Marshal(have.Options)
+	if err != nil {
+		return true
+	}
+	return !bytes.Equal(wantOpts, haveOpts)
+}
+
 func RegisterCommands(session CommandSession, commands []*discordgo.ApplicationCommand, userID, guildID string) []*discordgo.ApplicationCommand {
 	registered := make([]*discordgo.ApplicationCommand, len(commands))
 
@@ -4,14 +4,24 @@ import (
 	"errors"
 	"testing"
 
+	"death-level-tracker/internal/adapters/discord/formatting"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 type mockCommandSession struct {
+	listFunc   func(appID, guildID string) ([]*discordgo.ApplicationCommand, error)
 	createFunc func(appID, guildID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error)
 	deleteFunc func(appID, guildID, cmdID string) error
 }
 
+func (m *mockCommandSession) ApplicationCommands(appID, guildID string, opts ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	if m.listFunc != nil {
+		return m.listFunc(appID, guildID)
+	}
+	return nil, nil
+}
+
 func (m *mockCommandSession) ApplicationCommandCreate(appID, guildID string, cmd *discordgo.ApplicationCommand, opts ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
 	if m.createFunc != nil {
 		return m.createFunc(appID, guildID, cmd)
@@ -29,11 +39,11 @@ func (m *mockCommandSession) ApplicationCommandDelete(appID, guildID, cmdID stri
 func TestGetApplicationCommands(t *testing.T) {
 	commands := GetApplicationCommands()
 
-	if len(commands) != 5 {
-		t.Fatalf("expected 5 commands, got %d", len(commands))
+	expectedNames := []string{"track-world", "stop-tracking", "add-guild", "unset-guild", "ignore-player", "unignore-player", "list-guilds", "sync-guild", "set-language", "set-channel", "set-ping-role", "set-poll-interval", "mute-tracker", "set-low-level-deaths", "set-min-level", "deaths-today", "retry-failed", "check-permissions", "help", "track-status", "purge-data", "top-killers", "compare", "track-houses", "rashid", "pace", "set-timezone", "set-template", "set-emoji", "route-deaths", "set-quiet-hours", "export", "set-share-range", "set-death-location", "set-level-downs", "set-response-visibility", "set-death-verbosity", "track-skills", "watch-player", "unwatch-player", "set-skill-threshold", "set-mass-death-alert", "set-announcements", "resume-tracking", "activity", "frags", "war-score"}
+	if len(commands) != len(expectedNames) {
+		t.Fatalf("expected %d commands, got %d", len(expectedNames), len(commands))
 	}
 
-	expectedNames := []string{"track-world", "stop-tracking", "add-guild", "unset-guild", "list-guilds"}
 	for i, cmd := range commands {
 		if cmd.Name != expectedNames[i] {
 			t.Errorf("command %d: expected name %q, got %q", i, expectedNames[i], cmd.Name)
@@ -70,9 +80,11 @@ func TestGetApplicationCommands_OptionsConfiguration(t *testing.T) {
 	}{
 		{"track-world has required name option", 0, 1, false},
 		{"stop-tracking has no options", 1, 0, false},
-		{"add-guild has required name option", 2, 1, false},
+		{"add-guild has autocomplete option", 2, 1, true},
 		{"unset-guild has autocomplete option", 3, 1, true},
-		{"list-guilds has no options", 4, 0, false},
+		{"ignore-player has autocomplete option", 4, 1, true},
+		{"unignore-player has autocomplete option", 5, 1, true},
+		{"list-guilds has no options", 6, 0, false},
 	}
 
 	commands := GetApplicationCommands()
@@ -102,6 +114,35 @@ func TestGetApplicationCommands_OptionsConfiguration(t *testing.T) {
 	}
 }
 
+func TestGetApplicationCommands_SetLanguageChoices(t *testing.T) {
+	var cmd *discordgo.ApplicationCommand
+	for _, c := range GetApplicationCommands() {
+		if c.Name == "set-language" {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		t.Fatal("set-language command not found")
+	}
+
+	if len(cmd.Options) != 1 {
+		t.Fatalf("expected 1 option, got %d", len(cmd.Options))
+	}
+
+	opt := cmd.Options[0]
+	if !opt.Required {
+		t.Error("expected language option to be required")
+	}
+	if len(opt.Choices) != len(formatting.SupportedLanguages()) {
+		t.Fatalf("expected %d choices, got %d", len(formatting.SupportedLanguages()), len(opt.Choices))
+	}
+	for _, choice := range opt.Choices {
+		if !formatting.IsSupportedLanguage(choice.Value.(string)) {
+			t.Errorf("unexpected choice value %v", choice.Value)
+		}
+	}
+}
+
 func TestGetApplicationCommands_IsIdempotent(t *testing.T) {
 	first := GetApplicationCommands()
 	second := GetApplicationCommands()
@@ -225,6 +266,98 @@ func TestRegisterCommands_PassesCorrectParameters(t *testing.T) {
 	}
 }
 
+func TestSyncCommands(t *testing.T) {
+	perms := int64(discordgo.PermissionAdministrator)
+	defined := []*discordgo.ApplicationCommand{
+		{Name: "same", Description: "Same", DefaultMemberPermissions: &perms, Options: []*discordgo.ApplicationCommandOption{stringOption("name", "Name", true, false)}},
+		{Name: "edited", Description: "New description", DefaultMemberPermissions: &perms},
+		{Name: "new", Description: "New", DefaultMemberPermissions: &perms},
+	}
+
+	var created, deleted []string
+	var gotGuildID string
+	session := &mockCommandSession{
+		listFunc: func(appID, guildID string) ([]*discordgo.ApplicationCommand, error) {
+			gotGuildID = guildID
+			return []*discordgo.ApplicationCommand{
+				{ID: "1", Type: discordgo.ChatApplicationCommand, Name: "same", Description: "Same", DefaultMemberPermissions: &perms, Options: []*discordgo.ApplicationCommandOption{stringOption("name", "Name", true, false)}},
+				{ID: "2", Name: "edited", Description: "Old description", DefaultMemberPermissions: &perms},
+				{ID: "3", Name: "removed", Description: "Removed", DefaultMemberPermissions: &perms},
+			}, nil
+		},
+		createFunc: func(appID, guildID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error) {
+			created = append(created, cmd.Name)
+			return cmd, nil
+		},
+		deleteFunc: func(appID, guildID, cmdID string) error {
+			deleted = append(deleted, cmdID)
+			return nil
+		},
+	}
+
+	SyncCommands(session, defined, "bot-id", "")
+
+	if gotGuildID != "" {
+		t.Errorf("expected global commands to be listed, got guild %q", gotGuildID)
+	}
+	if len(created) != 2 || created[0] != "edited" || created[1] != "new" {
+		t.Errorf("expected only edited and new commands to be created, got %v", created)
+	}
+	if len(deleted) != 1 || deleted[0] != "3" {
+		t.Errorf("expected stale command to be deleted, got %v", deleted)
+	}
+}
+
+func TestSyncCommands_ListFailureRegistersAll(t *testing.T) {
+	var created []string
+	session := &mockCommandSession{
+		listFunc: func(appID, guildID string) ([]*discordgo.ApplicationCommand, error) {
+			return nil, errors.New("api unavailable")
+		},
+		createFunc: func(appID, guildID string, cmd *discordgo.ApplicationCommand) (*discordgo.ApplicationCommand, error) {
+			created = append(created, cmd.Name)
+			return cmd, nil
+		},
+		deleteFunc: func(appID, guildID, cmdID string) error {
+			t.Error("nothing should be deleted without a listing")
+			return nil
+		},
+	}
+
+	SyncCommands(session, []*discordgo.ApplicationCommand{{Name: "cmd-1"}, {Name: "cmd-2"}}, "bot-id", "guild-id")
+
+	if len(created) != 2 {
+		t.Errorf("expected every command to be created, got %v", created)
+	}
+}
+
+func TestCommandChanged(t *testing.T) {
+	perms := int64(discordgo.PermissionAdministrator)
+	other := int64(discordgo.PermissionManageServer)
+	base := func() *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{
+			Name: "cmd", Description: "Desc", DefaultMemberPermissions: &perms,
+			Options: []*discordgo.ApplicationCommandOption{stringOption("name", "Name", true, true)},
+		}
+	}
+
+	if commandChanged(base(), base()) {
+		t.Error("identical commands should not differ")
+	}
+
+	perm := base()
+	perm.DefaultMemberPermissions = &other
+	noPerm := base()
+	noPerm.DefaultMemberPermissions = nil
+	option := base()
+	option.Options[0].Required = false
+	for name, have := range map[string]*discordgo.ApplicationCommand{"permissions": perm, "no permissions": noPerm, "options": option} {
+		if !commandChanged(base(), have) {
+			t.Errorf("%s: expected a difference", name)
+		}
+	}
+}
+
 func TestCleanupCommands_AllSucceed(t *testing.T) {
 	var deleted []string
 	session := &mockCommandSession{
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -9,34 +11,72 @@ import (
 type CommandHandler func(s DiscordSession, i *discordgo.InteractionCreate)
 
 type Router struct {
-	routes map[string]CommandHandler
+	routes      map[string]CommandHandler
+	components  map[string]CommandHandler
+	middlewares []Middleware
 }
 
 func NewRouter() *Router {
 	slog.Info("Router initialized")
 	return &Router{
-		routes: make(map[string]CommandHandler),
+		routes:     make(map[string]CommandHandler),
+		components: make(map[string]CommandHandler),
 	}
 }
 
-func (r *Router) Register(name string, handler CommandHandler) {
+// Use adds middlewares that wrap every command, outside any registered with
+// the command itself.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Register routes name to handler wrapped in middlewares, the first being
+// the outermost.
+func (r *Router) Register(name string, handler CommandHandler, middlewares ...Middleware) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
 	r.routes[name] = handler
 }
 
-func (r *Router) Handle(s DiscordSession, i *discordgo.InteractionCreate) {
-	if !isCommandInteraction(i.Type) {
-		return
+// RegisterComponent routes message components and modal submits whose custom
+// ID was built by componentID with route to handler, wrapped like Register.
+func (r *Router) RegisterComponent(route string, handler CommandHandler, middlewares ...Middleware) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
 	}
+	r.components[route] = handler
+}
 
-	name := i.ApplicationCommandData().Name
-	slog.Info("Router received interaction", "type", i.Type, "name", name)
+// Commands returns the names of the registered slash commands, sorted.
+func (r *Router) Commands() []string {
+	names := make([]string, 0, len(r.routes))
+	for name := range r.routes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
 
-	handler, ok := r.routes[name]
+func (r *Router) Handle(s DiscordSession, i *discordgo.InteractionCreate) {
+	var handler CommandHandler
+	var ok bool
+	switch {
+	case isCommandInteraction(i.Type):
+		handler, ok = r.routes[interactionName(i)]
+	case isComponentInteraction(i.Type):
+		handler, ok = r.components[interactionName(i)]
+	default:
+		return
+	}
 	if !ok {
-		slog.Warn("No handler found for command", "name", name)
+		slog.Warn("No handler found for interaction", "type", i.Type, "name", interactionName(i))
 		return
 	}
 
+	for j := len(r.middlewares) - 1; j >= 0; j-- {
+		handler = r.middlewares[j](handler)
+	}
 	handler(s, i)
 }
 
@@ -46,7 +86,43 @@ func (r *Router) HandleFunc() func(*discordgo.Session, *discordgo.InteractionCre
 	}
 }
 
+// componentID builds a message component custom ID that the router sends to
+// the handler registered for route, carrying args for it to read back with
+// componentArgs.
+func componentID(route string, args ...string) string {
+	return strings.Join(append([]string{route}, args...), ":")
+}
+
+func componentArgs(i *discordgo.InteractionCreate) []string {
+	parts := strings.Split(customID(i), ":")
+	return parts[1:]
+}
+
+// customID is the custom ID of a clicked message component or a submitted
+// modal.
+func customID(i *discordgo.InteractionCreate) string {
+	if i.Type == discordgo.InteractionModalSubmit {
+		return i.ModalSubmitData().CustomID
+	}
+	return i.MessageComponentData().CustomID
+}
+
+// interactionName is the command name of a slash command or autocomplete
+// request, or the route of a message component or modal.
+func interactionName(i *discordgo.InteractionCreate) string {
+	if isComponentInteraction(i.Type) {
+		route, _, _ := strings.Cut(customID(i), ":")
+		return route
+	}
+	return i.ApplicationCommandData().Name
+}
+
 func isCommandInteraction(t discordgo.InteractionType) bool {
 	return t == discordgo.InteractionApplicationCommand ||
 		t == discordgo.InteractionApplicationCommandAutocomplete
 }
+
+func isComponentInteraction(t discordgo.InteractionType) bool {
+	return t == discordgo.InteractionMessageComponent ||
+		t == discordgo.InteractionModalSubmit
+}
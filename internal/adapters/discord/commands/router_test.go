@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
@@ -20,6 +22,18 @@ func (m *mockSession) InteractionRespond(i *discordgo.Interaction, resp *discord
 	return nil
 }
 
+func (m *mockSession) InteractionResponseEdit(i *discordgo.Interaction, edit *discordgo.WebhookEdit, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return nil, nil
+}
+
+func (m *mockSession) FollowupMessageCreate(i *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return nil, nil
+}
+
+func (m *mockSession) UserChannelPermissions(userID, channelID string, opts ...discordgo.RequestOption) (int64, error) {
+	return 0, nil
+}
+
 func TestNewRouter(t *testing.T) {
 	router := NewRouter()
 
@@ -75,6 +89,59 @@ func TestRouter_Register_OverwritesPrevious(t *testing.T) {
 	}
 }
 
+func TestRouter_Register_AppliesMiddlewaresInOrder(t *testing.T) {
+	router := NewRouter()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next CommandHandler) CommandHandler {
+			return func(s DiscordSession, i *discordgo.InteractionCreate) {
+				order = append(order, name)
+				next(s, i)
+			}
+		}
+	}
+	router.Register("cmd", func(s DiscordSession, i *discordgo.InteractionCreate) {
+		order = append(order, "handler")
+	}, tag("outer"), tag("inner"))
+
+	router.routes["cmd"](nil, nil)
+
+	if len(order) != 3 || order[0] != "outer" || order[1] != "inner" || order[2] != "handler" {
+		t.Errorf("unexpected call order: %v", order)
+	}
+}
+
+func TestRouter_Use_WrapsEveryCommand(t *testing.T) {
+	router := NewRouter()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next CommandHandler) CommandHandler {
+			return func(s DiscordSession, i *discordgo.InteractionCreate) {
+				order = append(order, name)
+				next(s, i)
+			}
+		}
+	}
+	router.Use(tag("global-outer"), tag("global-inner"))
+	router.Register("cmd", func(s DiscordSession, i *discordgo.InteractionCreate) {
+		order = append(order, "handler")
+	}, tag("route"))
+
+	router.Handle(&mockSession{}, makeInteraction("cmd", discordgo.InteractionApplicationCommand))
+
+	want := []string{"global-outer", "global-inner", "route", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("unexpected call order: %v", order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("unexpected call order: %v", order)
+		}
+	}
+}
+
 func TestRouter_Handle_DispatchesToCorrectHandler(t *testing.T) {
 	router := NewRouter()
 	session := &mockSession{}
@@ -113,8 +180,6 @@ func TestRouter_Handle_SupportsAutocomplete(t *testing.T) {
 func TestRouter_Handle_IgnoresOtherInteractionTypes(t *testing.T) {
 	ignoredTypes := []discordgo.InteractionType{
 		discordgo.InteractionPing,
-		discordgo.InteractionMessageComponent,
-		discordgo.InteractionModalSubmit,
 	}
 
 	for _, iType := range ignoredTypes {
@@ -136,6 +201,93 @@ func TestRouter_Handle_IgnoresOtherInteractionTypes(t *testing.T) {
 	}
 }
 
+func TestRouter_Handle_DispatchesComponents(t *testing.T) {
+	router := NewRouter()
+	session := &mockSession{}
+
+	var called, routed string
+	router.Register("confirm", func(s DiscordSession, i *discordgo.InteractionCreate) { called = "command" })
+	router.RegisterComponent("confirm", func(s DiscordSession, i *discordgo.InteractionCreate) {
+		called = "component"
+		routed = strings.Join(componentArgs(i), ",")
+	})
+
+	router.Handle(session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: componentID("confirm", "a", "b")},
+	}})
+
+	if called != "component" {
+		t.Errorf("expected component handler, got %q", called)
+	}
+	if routed != "a,b" {
+		t.Errorf("expected args 'a,b', got %q", routed)
+	}
+
+	called = ""
+	router.Handle(session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: "unknown:1"},
+	}})
+	if called != "" {
+		t.Errorf("expected unknown component to be ignored, got %q", called)
+	}
+}
+
+func TestRouter_Handle_PageButtonsSkipAdminCheck(t *testing.T) {
+	router := NewRouter()
+	router.Use(WithAdmin)
+
+	var called []string
+	router.RegisterComponent(PageRoute, func(s DiscordSession, i *discordgo.InteractionCreate) { called = append(called, "page") })
+	router.RegisterComponent("confirm", func(s DiscordSession, i *discordgo.InteractionCreate) { called = append(called, "confirm") })
+
+	click := func(customID string) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type:   discordgo.InteractionMessageComponent,
+			Member: &discordgo.Member{User: &discordgo.User{ID: "member-1"}, Permissions: discordgo.PermissionSendMessages},
+			Data:   discordgo.MessageComponentInteractionData{CustomID: customID},
+		}}
+	}
+	router.Handle(&mockSession{}, click(componentID(PageRoute, "1")))
+	router.Handle(&mockSession{}, click(componentID("confirm")))
+
+	if !slices.Equal(called, []string{"page"}) {
+		t.Errorf("expected only the page button to run for a non-admin, got %v", called)
+	}
+}
+
+func TestRouter_Handle_DispatchesModalSubmits(t *testing.T) {
+	router := NewRouter()
+	session := &mockSession{}
+
+	var routed string
+	router.RegisterComponent("form", func(s DiscordSession, i *discordgo.InteractionCreate) {
+		routed = strings.Join(componentArgs(i), ",")
+	})
+
+	router.Handle(session, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionModalSubmit,
+		Data: discordgo.ModalSubmitInteractionData{CustomID: componentID("form", "x")},
+	}})
+
+	if routed != "x" {
+		t.Errorf("expected modal submit routed with args 'x', got %q", routed)
+	}
+}
+
+func TestRouter_Commands(t *testing.T) {
+	router := NewRouter()
+	handler := func(s DiscordSession, i *discordgo.InteractionCreate) {}
+	router.Register("track-world", handler)
+	router.Register("add-guild", handler)
+	router.RegisterComponent("confirm", handler)
+
+	if got := router.Commands(); !slices.Equal(got, []string{"add-guild", "track-world"}) {
+		t.Errorf("expected sorted command names without components, got %v", got)
+	}
+}
+
 func TestRouter_Handle_UnregisteredCommand(t *testing.T) {
 	router := NewRouter()
 	session := &mockSession{}
@@ -0,0 +1,264 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/core/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetupRoute routes every button, select menu and modal of the setup wizard.
+const SetupRoute = "setup"
+
+// setupTimeout is how long the setup wizard accepts input. Like a
+// confirmation deadline it travels in the custom IDs, so any replica can
+// continue a wizard another one started.
+const setupTimeout = 15 * time.Minute
+
+// Actions of the setup wizard, the first custom ID argument.
+const (
+	setupActionGuild     = "guild"
+	setupActionGuildName = "guild-name"
+	setupActionLanguage  = "language"
+	setupActionMinLevel  = "min-level"
+	setupActionLevel     = "level"
+	setupActionFinish    = "finish"
+)
+
+// Text inputs of the setup modals.
+const (
+	setupGuildInput = "name"
+	setupLevelInput = "level"
+)
+
+// setupStep is a bit set of the wizard steps completed so far.
+type setupStep uint8
+
+const (
+	setupGuildAdded setupStep = 1 << iota
+	setupLanguageSet
+	setupMinLevelSet
+)
+
+// setupState is everything the wizard knows. It is keyed by the /track-world
+// interaction that started it and carried in every component's custom ID,
+// so no replica has to remember it.
+type setupState struct {
+	origin  string
+	done    setupStep
+	expires time.Time
+}
+
+func (st setupState) customID(action string) string {
+	return componentID(SetupRoute, action, st.origin, strconv.Itoa(int(st.done)), strconv.FormatInt(st.expires.Unix(), 10))
+}
+
+// parseSetupState reads the action and state back from a wizard custom ID.
+func parseSetupState(i *discordgo.InteractionCreate) (string, setupState, bool) {
+	args := componentArgs(i)
+	if len(args) != 4 {
+		return "", setupState{}, false
+	}
+	done, err := strconv.ParseUint(args[2], 10, 8)
+	if err != nil {
+		return "", setupState{}, false
+	}
+	expires, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil {
+		return "", setupState{}, false
+	}
+	return args[0], setupState{origin: args[1], done: setupStep(done), expires: time.Unix(expires, 0)}, true
+}
+
+// sendSetupWizard follows up the /track-world reply with the wizard, visible
+// only to the administrator who ran it.
+func sendSetupWizard(s DiscordSession, i *discordgo.InteractionCreate) {
+	st := setupState{origin: i.ID, expires: time.Now().Add(setupTimeout)}
+	_, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Content:    formatting.MsgSetupMenu(""),
+		Flags:      discordgo.MessageFlagsEphemeral,
+		Components: setupComponents(st),
+	})
+	if err != nil {
+		slog.Warn("Failed to send setup wizard", "guild_id", i.GuildID, "error", err)
+	}
+}
+
+// setupComponents renders the wizard's menu, marking completed steps green.
+func setupComponents(st setupState) []discordgo.MessageComponent {
+	style := func(step setupStep) discordgo.ButtonStyle {
+		if st.done&step != 0 {
+			return discordgo.SuccessButton
+		}
+		return discordgo.PrimaryButton
+	}
+
+	languages := formatting.SupportedLanguages()
+	options := make([]discordgo.SelectMenuOption, 0, len(languages))
+	for _, lang := range languages {
+		options = append(options, discordgo.SelectMenuOption{Label: formatting.CatalogFor(lang).Name, Value: lang})
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: formatting.MsgSetupGuildButton, Style: style(setupGuildAdded), CustomID: st.customID(setupActionGuild)},
+			discordgo.Button{Label: formatting.MsgSetupMinLevelButton, Style: style(setupMinLevelSet), CustomID: st.customID(setupActionMinLevel)},
+			discordgo.Button{Label: formatting.MsgSetupFinishButton, Style: discordgo.SecondaryButton, CustomID: st.customID(setupActionFinish)},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    st.customID(setupActionLanguage),
+				Placeholder: formatting.MsgSetupLanguagePlaceholder,
+				Options:     options,
+			},
+		}},
+	}
+}
+
+// Setup moves the setup wizard one step on. Buttons open a modal or finish
+// the wizard; submitted modals and the language menu save the setting and
+// redraw the menu with the outcome.
+func (h *BotHandler) Setup(s DiscordSession, i *discordgo.InteractionCreate) {
+	action, st, ok := parseSetupState(i)
+	if !ok || time.Now().After(st.expires) {
+		updateMessage(s, i, formatting.MsgSetupExpired)
+		return
+	}
+
+	switch action {
+	case setupActionGuild:
+		respondModal(s, i, st.customID(setupActionGuildName), formatting.MsgSetupGuildTitle, setupGuildInput, formatting.MsgSetupGuildInput)
+	case setupActionMinLevel:
+		respondModal(s, i, st.customID(setupActionLevel), formatting.MsgSetupMinLevelTitle, setupLevelInput, formatting.MsgSetupMinLevelInput)
+	case setupActionGuildName:
+		h.setupGuild(s, i, st)
+	case setupActionLevel:
+		h.setupMinLevel(s, i, st)
+	case setupActionLanguage:
+		h.setupLanguage(s, i, st)
+	case setupActionFinish:
+		updateMessage(s, i, formatting.MsgSetupDone)
+	default:
+		updateMessage(s, i, formatting.MsgSetupExpired)
+	}
+}
+
+// setupGuild adds the Tibia guild named in the submitted modal. The guild is
+// looked up on TibiaData, so the menu is redrawn after a deferred update.
+func (h *BotHandler) setupGuild(s DiscordSession, i *discordgo.InteractionCreate, st setupState) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	if err != nil {
+		slog.Warn("Failed to defer setup step", "guild_id", i.GuildID, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deferredTimeout)
+	defer cancel()
+
+	guildName := modalValue(i, setupGuildInput)
+	msg := formatting.MsgGuildNameRequired
+	if guildName != "" {
+		var added bool
+		if msg, added = h.addGuild(ctx, i.GuildID, guildName); added {
+			st.done |= setupGuildAdded
+		}
+	}
+
+	content := formatting.MsgSetupMenu(msg)
+	components := setupComponents(st)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content, Components: &components}); err != nil {
+		slog.Warn("Failed to update setup wizard", "guild_id", i.GuildID, "error", err)
+	}
+}
+
+func (h *BotHandler) setupMinLevel(s DiscordSession, i *discordgo.InteractionCreate, st setupState) {
+	level, err := strconv.Atoi(modalValue(i, setupLevelInput))
+	if err != nil {
+		updateSetup(s, i, st, formatting.MsgGuildMinLevelInvalid)
+		return
+	}
+
+	err = h.Service.SetMinLevel(context.Background(), i.GuildID, level)
+	switch {
+	case errors.Is(err, services.ErrInvalidMinLevel):
+		updateSetup(s, i, st, formatting.MsgGuildMinLevelInvalid)
+	case err != nil:
+		slog.Error("Failed to set minimum level", "guild_id", i.GuildID, "error", err)
+		updateSetup(s, i, st, formatting.MsgSaveError)
+	default:
+		st.done |= setupMinLevelSet
+		updateSetup(s, i, st, formatting.MsgMinLevelSet(level, h.Config.MinLevelTrack))
+	}
+}
+
+func (h *BotHandler) setupLanguage(s DiscordSession, i *discordgo.InteractionCreate, st setupState) {
+	var language string
+	if values := i.MessageComponentData().Values; len(values) == 1 {
+		language = values[0]
+	}
+	if !formatting.IsSupportedLanguage(language) {
+		updateSetup(s, i, st, formatting.MsgLanguageInvalid)
+		return
+	}
+
+	if err := h.Service.SetLanguage(context.Background(), i.GuildID, language); err != nil {
+		slog.Error("Failed to set language", "guild_id", i.GuildID, "error", err)
+		updateSetup(s, i, st, formatting.MsgSaveError)
+		return
+	}
+
+	st.done |= setupLanguageSet
+	updateSetup(s, i, st, formatting.MsgLanguageSet(formatting.CatalogFor(language).Name))
+}
+
+// updateSetup redraws the wizard with status and the menu for st.
+func updateSetup(s DiscordSession, i *discordgo.InteractionCreate, st setupState, status string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    formatting.MsgSetupMenu(status),
+			Components: setupComponents(st),
+		},
+	})
+}
+
+// respondModal opens a modal with a single short text input whose submit is
+// routed by customID.
+func respondModal(s DiscordSession, i *discordgo.InteractionCreate, customID, title, inputID, label string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: customID,
+			Title:    title,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{CustomID: inputID, Label: label, Style: discordgo.TextInputShort, Required: true, MaxLength: 100},
+				}},
+			},
+		},
+	})
+}
+
+// modalValue returns the trimmed value of the submitted modal's text input
+// id.
+func modalValue(i *discordgo.InteractionCreate, id string) string {
+	for _, c := range i.ModalSubmitData().Components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rc := range row.Components {
+			if input, ok := rc.(*discordgo.TextInput); ok && input.CustomID == id {
+				return strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	return ""
+}
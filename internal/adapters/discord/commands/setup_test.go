@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/discord/formatting"
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func testSetupState() setupState {
+	return setupState{origin: "origin-1", expires: time.Now().Add(time.Minute)}
+}
+
+func makeSetupClick(customID string, values ...string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionMessageComponent,
+		GuildID: "guild-1",
+		Data:    discordgo.MessageComponentInteractionData{CustomID: customID, Values: values},
+	}}
+}
+
+func makeSetupSubmit(customID, inputID, value string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionModalSubmit,
+		GuildID: "guild-1",
+		Data: discordgo.ModalSubmitInteractionData{
+			CustomID: customID,
+			Components: []discordgo.MessageComponent{
+				&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					&discordgo.TextInput{CustomID: inputID, Value: value},
+				}},
+			},
+		},
+	}}
+}
+
+// doneSteps reads the completed steps back from a redrawn menu.
+func doneSteps(t *testing.T, components []discordgo.MessageComponent) setupStep {
+	t.Helper()
+	button := components[0].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+	_, st, ok := parseSetupState(makeSetupClick(button.CustomID))
+	if !ok {
+		t.Fatalf("malformed custom ID %q", button.CustomID)
+	}
+	return st.done
+}
+
+func TestTrackWorld_SetupWizard(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *domain.GuildConfig
+		wizard bool
+	}{
+		{"First world", nil, true},
+		{"Configured before", &domain.GuildConfig{DiscordGuildID: "guild-1"}, true},
+		{"World already tracked", &domain.GuildConfig{DiscordGuildID: "guild-1", World: "Antica"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.config, nil
+				},
+			}
+			session := &mockDiscordSession{}
+			interaction := makeCommandInteraction("guild-1", "name", "secura")
+			interaction.ID = "origin-1"
+
+			newTestHandler(storage).TrackWorld(session, interaction)
+
+			if !tt.wizard {
+				if session.lastFollowup != nil {
+					t.Errorf("expected no wizard, got %+v", session.lastFollowup)
+				}
+				return
+			}
+			if session.lastFollowup == nil {
+				t.Fatal("expected the setup wizard")
+			}
+			if session.lastFollowup.Flags != discordgo.MessageFlagsEphemeral {
+				t.Error("expected an ephemeral wizard")
+			}
+			button := session.lastFollowup.Components[0].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+			action, st, ok := parseSetupState(makeSetupClick(button.CustomID))
+			if !ok || action != setupActionGuild || st.origin != "origin-1" || st.done != 0 {
+				t.Errorf("unexpected first button %q", button.CustomID)
+			}
+		})
+	}
+}
+
+func TestSetup_OpensModals(t *testing.T) {
+	st := testSetupState()
+	for action, submit := range map[string]string{setupActionGuild: setupActionGuildName, setupActionMinLevel: setupActionLevel} {
+		session := &mockDiscordSession{}
+		newTestHandler(&mockStorage{}).Setup(session, makeSetupClick(st.customID(action)))
+
+		resp := session.lastInteractionResponse
+		if resp.Type != discordgo.InteractionResponseModal {
+			t.Fatalf("%s: expected a modal, got %+v", action, resp)
+		}
+		if resp.Data.CustomID != st.customID(submit) {
+			t.Errorf("%s: expected modal routed to %q, got %q", action, st.customID(submit), resp.Data.CustomID)
+		}
+	}
+}
+
+func TestSetup_AddGuild(t *testing.T) {
+	var added string
+	storage := &mockStorage{
+		addGuildToConfigFunc: func(ctx context.Context, guildID, tibiaGuild string) error {
+			added = tibiaGuild
+			return nil
+		},
+	}
+	session := &mockDiscordSession{}
+	handler := guildHandler(storage, &domain.Guild{Name: "Red Rose", World: "Antica"}, nil)
+
+	handler.Setup(session, makeSetupSubmit(testSetupState().customID(setupActionGuildName), setupGuildInput, " red rose "))
+
+	if added != "Red Rose" {
+		t.Errorf("expected 'Red Rose' added, got %q", added)
+	}
+	if session.lastInteractionResponse.Type != discordgo.InteractionResponseDeferredMessageUpdate {
+		t.Errorf("expected a deferred update, got %+v", session.lastInteractionResponse)
+	}
+	if expected := formatting.MsgSetupMenu(formatting.MsgGuildAdded("Red Rose")); session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+	if done := doneSteps(t, *session.lastResponseEdit.Components); done != setupGuildAdded {
+		t.Errorf("expected the guild step done, got %b", done)
+	}
+}
+
+func TestSetup_AddGuildNotFound(t *testing.T) {
+	session := &mockDiscordSession{}
+	handler := guildHandler(&mockStorage{}, nil, domain.ErrNotFound)
+
+	handler.Setup(session, makeSetupSubmit(testSetupState().customID(setupActionGuildName), setupGuildInput, "Nowhere"))
+
+	if expected := formatting.MsgSetupMenu(formatting.MsgGuildNotFound("Nowhere")); session.editedContent() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, session.editedContent())
+	}
+	if done := doneSteps(t, *session.lastResponseEdit.Components); done != 0 {
+		t.Errorf("expected no step done, got %b", done)
+	}
+}
+
+func TestSetup_MinLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		saved    int
+		expected string
+	}{
+		{"Valid", "250", 250, formatting.MsgMinLevelSet(250, 0)},
+		{"Not a number", "high", -1, formatting.MsgGuildMinLevelInvalid},
+		{"Out of range", "9000", -1, formatting.MsgGuildMinLevelInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := -1
+			storage := &mockStorage{
+				setGuildMinLevelFunc: func(ctx context.Context, guildID string, level int) error {
+					saved = level
+					return nil
+				},
+			}
+			session := &mockDiscordSession{}
+
+			newTestHandler(storage).Setup(session, makeSetupSubmit(testSetupState().customID(setupActionLevel), setupLevelInput, tt.value))
+
+			if saved != tt.saved {
+				t.Errorf("expected %d saved, got %d", tt.saved, saved)
+			}
+			resp := session.lastInteractionResponse
+			if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != formatting.MsgSetupMenu(tt.expected) {
+				t.Errorf("unexpected response %+v", resp)
+			}
+			wantDone := setupStep(0)
+			if tt.saved >= 0 {
+				wantDone = setupMinLevelSet
+			}
+			if done := doneSteps(t, resp.Data.Components); done != wantDone {
+				t.Errorf("expected steps %b, got %b", wantDone, done)
+			}
+		})
+	}
+}
+
+func TestSetup_Language(t *testing.T) {
+	var saved string
+	storage := &mockStorage{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			saved = language
+			return nil
+		},
+	}
+	session := &mockDiscordSession{}
+	st := testSetupState()
+	st.done = setupGuildAdded
+
+	newTestHandler(storage).Setup(session, makeSetupClick(st.customID(setupActionLanguage), formatting.LangPolish))
+
+	if saved != formatting.LangPolish {
+		t.Errorf("expected %q saved, got %q", formatting.LangPolish, saved)
+	}
+	resp := session.lastInteractionResponse
+	if expected := formatting.MsgSetupMenu(formatting.MsgLanguageSet(formatting.CatalogFor(formatting.LangPolish).Name)); resp.Data.Content != expected {
+		t.Errorf("expected '%s', got '%s'", expected, resp.Data.Content)
+	}
+	if done := doneSteps(t, resp.Data.Components); done != setupGuildAdded|setupLanguageSet {
+		t.Errorf("expected guild and language steps done, got %b", done)
+	}
+}
+
+func TestSetup_FinishAndExpiry(t *testing.T) {
+	expired := testSetupState()
+	expired.expires = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name     string
+		customID string
+		expected string
+	}{
+		{"Finish", testSetupState().customID(setupActionFinish), formatting.MsgSetupDone},
+		{"Expired", expired.customID(setupActionGuild), formatting.MsgSetupExpired},
+		{"Malformed", componentID(SetupRoute, setupActionGuild), formatting.MsgSetupExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &mockDiscordSession{}
+			newTestHandler(&mockStorage{}).Setup(session, makeSetupClick(tt.customID))
+
+			resp := session.lastInteractionResponse
+			if resp.Type != discordgo.InteractionResponseUpdateMessage || resp.Data.Content != tt.expected {
+				t.Errorf("expected '%s', got %+v", tt.expected, resp)
+			}
+			if len(resp.Data.Components) != 0 {
+				t.Error("expected the menu removed")
+			}
+		})
+	}
+}
+
+func TestSetupState_FitsCustomID(t *testing.T) {
+	st := setupState{origin: strings.Repeat("9", 20), done: setupGuildAdded | setupLanguageSet | setupMinLevelSet, expires: time.Now().Add(setupTimeout)}
+	for _, action := range []string{setupActionGuild, setupActionGuildName, setupActionLanguage, setupActionMinLevel, setupActionLevel, setupActionFinish} {
+		if id := st.customID(action); len(id) > 100 {
+			t.Errorf("custom ID %q is longer than Discord's 100 characters", id)
+		}
+	}
+}
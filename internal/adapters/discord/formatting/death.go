@@ -0,0 +1,109 @@
+package formatting
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// characterURL is the tibia.com page of a character, followed by its name.
+const characterURL = "https://www.tibia.com/community/?subtopic=characters&name="
+
+// DeathMessage is what a death notification is built from.
+type DeathMessage struct {
+	Player domain.Player
+	Kill   domain.Kill
+	// Time is the time of death as shown, see EventTime.
+	Time string
+	// Template replaces the headline of the message when set.
+	Template string
+	// Location appends the likely death location when one is known.
+	Location bool
+}
+
+// DeathFormatter turns a death into the content of its notification.
+type DeathFormatter interface {
+	Format(c Catalog, d DeathMessage) string
+}
+
+// DeathFormatterFor returns the formatter for a guild's death verbosity,
+// falling back to the normal one.
+func DeathFormatterFor(verbosity domain.DeathVerbosity) DeathFormatter {
+	switch verbosity {
+	case domain.VerbosityCompact:
+		return compactDeath{}
+	case domain.VerbosityDetailed:
+		return detailedDeath{}
+	}
+	return normalDeath{}
+}
+
+// compactDeath is a single line with the bare name and the death reason.
+type compactDeath struct{}
+
+func (compactDeath) Format(c Catalog, d DeathMessage) string {
+	content := fmt.Sprintf("%s - %s", d.Player.Name, d.Kill.Reason)
+	if d.Template != "" {
+		content = RenderTemplate(d.Template, DeathTemplateValues(d.Player.Name, d.Time, d.Kill.Reason, d.Kill.Level))
+	}
+	return content + locationSuffix(c, d)
+}
+
+// normalDeath is the labelled name, time and reason with the estimated
+// experience loss.
+type normalDeath struct{}
+
+func (normalDeath) Format(c Catalog, d DeathMessage) string {
+	name := c.PlayerLabel(d.Player.Name, d.Player.Vocation, d.Player.GuildName, d.Player.GuildRank, d.Player.WorldPvPType)
+	content := c.Death(name, d.Time, d.Kill.Reason, d.Kill.Level)
+	if d.Template != "" {
+		content = RenderTemplate(d.Template, DeathTemplateValues(name, d.Time, d.Kill.Reason, d.Kill.Level))
+	}
+	return content + locationSuffix(c, d)
+}
+
+// detailedDeath follows the normal message with the killers and assists,
+// linking the characters among them, and a link to the victim.
+type detailedDeath struct{}
+
+func (detailedDeath) Format(c Catalog, d DeathMessage) string {
+	lines := []string{normalDeath{}.Format(c, d)}
+	if len(d.Kill.Killers) > 0 {
+		lines = append(lines, c.Killers(killerLinks(d.Kill.Killers)))
+	}
+	if len(d.Kill.Assists) > 0 {
+		lines = append(lines, c.Assists(killerLinks(d.Kill.Assists)))
+	}
+	lines = append(lines, "🔗 <"+CharacterURL(d.Player.Name)+">")
+	return strings.Join(lines, "\n")
+}
+
+func locationSuffix(c Catalog, d DeathMessage) string {
+	if !d.Location {
+		return ""
+	}
+	if location, ok := domain.LikelyDeathLocation(d.Kill); ok {
+		return " " + c.DeathLocation(location)
+	}
+	return ""
+}
+
+// killerLinks names killers, linking characters to their tibia.com page.
+// The angle brackets keep Discord from previewing every link.
+func killerLinks(killers []domain.Killer) []string {
+	names := make([]string, len(killers))
+	for i, k := range killers {
+		names[i] = k.Name
+		if k.IsPlayer {
+			names[i] = fmt.Sprintf("[%s](<%s>)", k.Name, CharacterURL(k.Name))
+		}
+	}
+	return names
+}
+
+// CharacterURL is the tibia.com page of the named character.
+func CharacterURL(name string) string {
+	return characterURL + url.QueryEscape(name)
+}
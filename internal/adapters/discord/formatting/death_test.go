@@ -0,0 +1,59 @@
+package formatting
+
+import (
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestDeathFormatterFor(t *testing.T) {
+	d := DeathMessage{
+		Player: domain.Player{Name: "Sir Hero", Vocation: "Elite Knight", GuildName: "Red Rose", GuildRank: "Leader"},
+		Kill: domain.Kill{
+			Level:   8,
+			Reason:  "Killed at Level 8 by Bubble and a frazzlemaw. Assisted by a guzzlemaw.",
+			Killers: []domain.Killer{{Name: "Bubble", IsPlayer: true}, {Name: "frazzlemaw"}},
+			Assists: []domain.Killer{{Name: "guzzlemaw"}},
+		},
+		Time:     "12:30",
+		Location: true,
+	}
+	normal := "Sir Hero (Elite Knight, Leader of Red Rose) - 12:30 - Killed at Level 8 by Bubble and a frazzlemaw. Assisted by a guzzlemaw. (est. loss: 126-420 XP, 1 lvl) (likely at Roshamuul)"
+
+	tests := []struct {
+		verbosity domain.DeathVerbosity
+		want      string
+	}{
+		{domain.VerbosityCompact, "Sir Hero - Killed at Level 8 by Bubble and a frazzlemaw. Assisted by a guzzlemaw. (likely at Roshamuul)"},
+		{domain.VerbosityNormal, normal},
+		{"unknown", normal},
+		{domain.VerbosityDetailed, normal + "\n" +
+			"Killers: [Bubble](<https://www.tibia.com/community/?subtopic=characters&name=Bubble>), frazzlemaw\n" +
+			"Assists: guzzlemaw\n" +
+			"🔗 <https://www.tibia.com/community/?subtopic=characters&name=Sir+Hero>"},
+	}
+	c := CatalogFor(LangEnglish)
+	for _, tt := range tests {
+		if got := DeathFormatterFor(tt.verbosity).Format(c, d); got != tt.want {
+			t.Errorf("%q: expected\n%q\ngot\n%q", tt.verbosity, tt.want, got)
+		}
+	}
+}
+
+func TestDeathFormatter_Template(t *testing.T) {
+	d := DeathMessage{
+		Player:   domain.Player{Name: "Hero", Vocation: "Druid"},
+		Kill:     domain.Kill{Level: 100, Reason: "Killed by a dragon"},
+		Time:     "12:30",
+		Template: "☠️ {player} ({level})",
+	}
+	for verbosity, want := range map[domain.DeathVerbosity]string{
+		domain.VerbosityCompact:  "☠️ Hero (100)",
+		domain.VerbosityNormal:   "☠️ Hero (Druid) (100)",
+		domain.VerbosityDetailed: "☠️ Hero (Druid) (100)\n🔗 <https://www.tibia.com/community/?subtopic=characters&name=Hero>",
+	} {
+		if got := DeathFormatterFor(verbosity).Format(CatalogFor(LangEnglish), d); got != want {
+			t.Errorf("%q: expected %q, got %q", verbosity, want, got)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package formatting
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// EmbedColorDeaths is the accent color of death-related embeds.
+const EmbedColorDeaths = 0x992D22
+
+// EmbedColorInfo is the accent color of informational embeds.
+const EmbedColorInfo = 0x3498DB
+
+// maxEmbedDescription is Discord's limit on an embed description.
+const maxEmbedDescription = 4096
+
+// TopKillersEmbed ranks the characters that killed the most tracked players
+// on world during window, e.g. "Last 7 days".
+func TopKillersEmbed(world, window string, killers []domain.KillerCount) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("☠️ Top killers on %s", world),
+		Color:  EmbedColorDeaths,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Deaths of tracked players · " + window},
+	}
+
+	if len(killers) == 0 {
+		embed.Description = "No tracked player was killed by another character."
+		return embed
+	}
+
+	var sb strings.Builder
+	for i, k := range killers {
+		fmt.Fprintf(&sb, "%d. **%s** - %d %s\n", i+1, k.Name, k.Kills, plural(k.Kills, "kill", "kills"))
+	}
+	embed.Description = sb.String()
+	return embed
+}
+
+// activityShades draw a heatmap cell, from nobody online to the peak.
+var activityShades = []rune("·░▒▓█")
+
+// activityDays are the heatmap rows, starting on Monday.
+var activityDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// ActivityEmbed charts when characters on world are online, one row per
+// weekday and one column per hour in timezone. guildMembers tells whether
+// only members of the tracked Tibia guilds are counted.
+func ActivityEmbed(world string, guildMembers bool, heatmap domain.ActivityHeatmap, timezone string) *discordgo.MessageEmbed {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	who := "All characters on " + world
+	if guildMembers {
+		who = "Members of the tracked guilds"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("📊 Activity on %s", world),
+		Color:  EmbedColorInfo,
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%s · Last %d days · %s", who, int(heatmap.Window.Hours()/24), timezone)},
+	}
+
+	day, hour, peak := heatmap.Peak()
+	if peak == 0 {
+		embed.Description = "Nobody was seen online yet. The chart fills in as the tracker runs."
+		return embed
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Most online on **%s around %02d:00**, %.1f on average.\n```\n", day, hour, peak)
+	sb.WriteString("    00    06    12    18\n")
+	for _, d := range activityDays {
+		sb.WriteString(d.String()[:3] + " ")
+		for _, online := range heatmap.Online[d] {
+			shade := 0
+			if online > 0 {
+				shade = max(int(math.Ceil(online/peak*float64(len(activityShades)-1))), 1)
+			}
+			sb.WriteRune(activityShades[shade])
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("```")
+	if day, hour, online := heatmap.WorldPeak(); guildMembers && online > 0 {
+		fmt.Fprintf(&sb, "\nAcross %s, most are online on **%s around %02d:00**, %.0f on average.", world, day, hour, online)
+	}
+	embed.Description = sb.String()
+	return embed
+}
+
+// HelpCommand is a slash command listed by /help.
+type HelpCommand struct {
+	Name        string
+	Description string
+}
+
+// helpFeature is an optional feature /help reports as on or off, with the
+// command that changes it.
+type helpFeature struct {
+	name    string
+	enabled bool
+	command string
+}
+
+// HelpEmbed describes the commands and, when cfg is not nil, the guild's
+// setup, which optional features are on and what to configure next. Tips
+// only mention commands in commands. Unset channels fall back to the default
+// channel names, and minLevel is the global minimum.
+func HelpEmbed(commands []HelpCommand, cfg *domain.GuildConfig, minLevel int, deathChannel, levelChannel string) *discordgo.MessageEmbed {
+	registered := make(map[string]bool, len(commands))
+	var sb strings.Builder
+	for n, cmd := range commands {
+		registered[cmd.Name] = true
+		line := fmt.Sprintf("`/%s` %s\n", cmd.Name, cmd.Description)
+		if sb.Len()+len(line) > maxEmbedDescription-20 {
+			fmt.Fprintf(&sb, "…and %d more", len(commands)-n)
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📖 Death Level Tracker help",
+		Description: sb.String(),
+		Color:       EmbedColorInfo,
+	}
+
+	var tips []string
+	tip := func(command, text string) {
+		if registered[command] {
+			tips = append(tips, fmt.Sprintf("`/%s` %s", command, text))
+		}
+	}
+
+	if cfg == nil || cfg.World == "" {
+		tip("track-world", "picks the Tibia world to track. Everything else builds on it.")
+	} else {
+		embed.Fields = append(embed.Fields, helpSetupField(*cfg, minLevel, deathChannel, levelChannel), helpFeaturesField(*cfg, registered))
+		if len(cfg.TibiaGuilds) == 0 {
+			tip("add-guild", "follows specific Tibia guilds; until then every player on the world is tracked.")
+		}
+	}
+	tip("check-permissions", "lists anything the bot is still missing.")
+	if len(tips) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Next steps", Value: strings.Join(tips, "\n")})
+	}
+	return embed
+}
+
+func helpSetupField(cfg domain.GuildConfig, minLevel int, deathChannel, levelChannel string) *discordgo.MessageEmbedField {
+	guilds := "all players"
+	if len(cfg.TibiaGuilds) > 0 {
+		guilds = fmt.Sprintf("%d %s", len(cfg.TibiaGuilds), plural(len(cfg.TibiaGuilds), "guild", "guilds"))
+	}
+
+	value := fmt.Sprintf("World: **%s**\n", cfg.World)
+	value += fmt.Sprintf("Tracking: %s\n", guilds)
+	value += fmt.Sprintf("Deaths: %s\n", channelRef(cfg.DeathChannelID, deathChannel))
+	value += fmt.Sprintf("Level ups: %s\n", channelRef(cfg.LevelChannelID, levelChannel))
+	if cfg.MiscChannelID != "" {
+		value += fmt.Sprintf("Misc: <#%s>\n", cfg.MiscChannelID)
+	}
+	value += fmt.Sprintf("Minimum level: %d\n", max(minLevel, cfg.MinLevel))
+	value += fmt.Sprintf("Language: %s", CatalogFor(cfg.Language).Name)
+	return &discordgo.MessageEmbedField{Name: "Setup", Value: value, Inline: true}
+}
+
+func helpFeaturesField(cfg domain.GuildConfig, registered map[string]bool) *discordgo.MessageEmbedField {
+	features := []helpFeature{
+		{"Low-level member deaths", cfg.LowLevelDeaths, "set-low-level-deaths"},
+		{"Level up pings", cfg.PingRoleID != "", "set-ping-role"},
+		{"Party share range", cfg.ShareRange, "set-share-range"},
+		{"Death location", cfg.DeathLocation, "set-death-location"},
+		{"Level downs", cfg.LevelDowns, "set-level-downs"},
+		{"Mass death alerts", cfg.MassDeathAlert.Enabled(), "set-mass-death-alert"},
+		{"House auctions", cfg.HouseChannelID != "", "track-houses"},
+		{"Skill advances", cfg.SkillChannelID != "", "track-skills"},
+		{"Quiet hours", cfg.QuietHours.Enabled(), "set-quiet-hours"},
+		{"Operator announcements", !cfg.BroadcastOptOut, "set-announcements"},
+	}
+
+	lines := make([]string, 0, len(features))
+	for _, f := range features {
+		switch {
+		case f.enabled:
+			lines = append(lines, "✅ "+f.name)
+		case registered[f.command]:
+			lines = append(lines, fmt.Sprintf("➖ %s · `/%s`", f.name, f.command))
+		default:
+			lines = append(lines, "➖ "+f.name)
+		}
+	}
+	return &discordgo.MessageEmbedField{Name: "Features", Value: strings.Join(lines, "\n"), Inline: true}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
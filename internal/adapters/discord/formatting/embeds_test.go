@@ -0,0 +1,135 @@
+package formatting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestTopKillersEmbed(t *testing.T) {
+	embed := TopKillersEmbed("Antica", "Last 7 days", []domain.KillerCount{
+		{Name: "Enemy Knight", Kills: 5},
+		{Name: "Enemy Druid", Kills: 1},
+	})
+
+	if !strings.Contains(embed.Title, "Antica") {
+		t.Errorf("expected world in title, got %q", embed.Title)
+	}
+	want := "1. **Enemy Knight** - 5 kills\n2. **Enemy Druid** - 1 kill\n"
+	if embed.Description != want {
+		t.Errorf("expected %q, got %q", want, embed.Description)
+	}
+	if embed.Footer == nil || !strings.Contains(embed.Footer.Text, "Last 7 days") {
+		t.Errorf("expected window in footer, got %+v", embed.Footer)
+	}
+}
+
+func TestTopKillersEmbed_Empty(t *testing.T) {
+	embed := TopKillersEmbed("Antica", "Last 24 hours", nil)
+	if embed.Description == "" {
+		t.Error("expected a message when nobody was killed")
+	}
+}
+
+func TestActivityEmbed(t *testing.T) {
+	var heatmap domain.ActivityHeatmap
+	heatmap.Window = domain.ActivityWindow
+	heatmap.Online[time.Saturday][20] = 8
+	heatmap.Online[time.Saturday][21] = 3
+	heatmap.Online[time.Monday][0] = 0.5
+	heatmap.World[time.Sunday][19] = 812.4
+
+	embed := ActivityEmbed("Antica", true, heatmap, "Europe/Warsaw")
+
+	if !strings.Contains(embed.Description, "Most online on **Saturday around 20:00**, 8.0 on average.") {
+		t.Errorf("expected the peak, got %q", embed.Description)
+	}
+	rows := strings.Split(embed.Description, "\n")
+	if want := "Mon ░·······················"; rows[3] != want {
+		t.Errorf("expected Monday row %q, got %q", want, rows[3])
+	}
+	if want := "Sat ····················█▒··"; rows[8] != want {
+		t.Errorf("expected Saturday row %q, got %q", want, rows[8])
+	}
+	if !strings.Contains(embed.Description, "Across Antica, most are online on **Sunday around 19:00**, 812 on average.") {
+		t.Errorf("expected the world peak, got %q", embed.Description)
+	}
+	if all := ActivityEmbed("Antica", false, heatmap, ""); strings.Contains(all.Description, "Across Antica") {
+		t.Errorf("expected no separate world peak when every character is charted, got %q", all.Description)
+	}
+	if !strings.Contains(embed.Footer.Text, "Members of the tracked guilds") || !strings.Contains(embed.Footer.Text, "14 days") || !strings.Contains(embed.Footer.Text, "Europe/Warsaw") {
+		t.Errorf("unexpected footer %q", embed.Footer.Text)
+	}
+}
+
+func TestActivityEmbed_Empty(t *testing.T) {
+	embed := ActivityEmbed("Antica", false, domain.ActivityHeatmap{Window: domain.ActivityWindow}, "")
+	if strings.Contains(embed.Description, "```") {
+		t.Errorf("expected no chart without presence, got %q", embed.Description)
+	}
+	if !strings.Contains(embed.Footer.Text, "All characters on Antica") || !strings.Contains(embed.Footer.Text, "UTC") {
+		t.Errorf("unexpected footer %q", embed.Footer.Text)
+	}
+}
+
+func TestHelpEmbed_Unconfigured(t *testing.T) {
+	commands := []HelpCommand{{Name: "track-world", Description: "Set the world"}, {Name: "help", Description: "Show help"}}
+	embed := HelpEmbed(commands, nil, 100, "death-tracker", "level-tracker")
+
+	if want := "`/track-world` Set the world\n`/help` Show help\n"; embed.Description != want {
+		t.Errorf("expected %q, got %q", want, embed.Description)
+	}
+	if len(embed.Fields) != 1 || embed.Fields[0].Name != "Next steps" {
+		t.Fatalf("expected only next steps, got %+v", embed.Fields)
+	}
+	if tips := embed.Fields[0].Value; !strings.Contains(tips, "/track-world") || strings.Contains(tips, "/check-permissions") {
+		t.Errorf("expected a tip for the registered /track-world only, got %q", tips)
+	}
+}
+
+func TestHelpEmbed_Configured(t *testing.T) {
+	commands := []HelpCommand{{Name: "set-share-range"}, {Name: "add-guild"}}
+	cfg := &domain.GuildConfig{
+		World:          "Antica",
+		DeathChannelID: "123",
+		LowLevelDeaths: true,
+		MinLevel:       300,
+		MassDeathAlert: domain.MassDeathAlert{Deaths: 5, Window: 10 * time.Minute},
+	}
+	embed := HelpEmbed(commands, cfg, 100, "death-tracker", "level-tracker")
+
+	if len(embed.Fields) != 3 {
+		t.Fatalf("expected setup, features and next steps, got %+v", embed.Fields)
+	}
+	setup, features, tips := embed.Fields[0].Value, embed.Fields[1].Value, embed.Fields[2].Value
+	for _, want := range []string{"**Antica**", "Tracking: all players", "Deaths: <#123>", "Level ups: #level-tracker", "Minimum level: 300"} {
+		if !strings.Contains(setup, want) {
+			t.Errorf("expected %q in setup %q", want, setup)
+		}
+	}
+	for _, want := range []string{"✅ Low-level member deaths", "✅ Mass death alerts", "➖ Party share range · `/set-share-range`", "➖ Skill advances\n", "✅ Operator announcements"} {
+		if !strings.Contains(features, want) {
+			t.Errorf("expected %q in features %q", want, features)
+		}
+	}
+	if !strings.Contains(tips, "/add-guild") {
+		t.Errorf("expected an /add-guild tip, got %q", tips)
+	}
+}
+
+func TestHelpEmbed_TruncatesCommands(t *testing.T) {
+	var commands []HelpCommand
+	for range 100 {
+		commands = append(commands, HelpCommand{Name: "command", Description: strings.Repeat("x", 80)})
+	}
+	embed := HelpEmbed(commands, nil, 100, "death-tracker", "level-tracker")
+
+	if len(embed.Description) > maxEmbedDescription {
+		t.Errorf("description is %d characters long", len(embed.Description))
+	}
+	if !strings.Contains(embed.Description, "more") {
+		t.Error("expected the cut commands to be counted")
+	}
+}
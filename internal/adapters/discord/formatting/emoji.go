@@ -0,0 +1,61 @@
+package formatting
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Reactions the bot adds to its own messages when a guild enables them
+// without choosing an emoji.
+const (
+	DefaultDeathReaction = "⚰️"
+	DefaultLevelReaction = "🎉"
+)
+
+// maxEmojiRunes fits the longest standard emoji sequences, such as flags and
+// families joined with zero-width joiners.
+const maxEmojiRunes = 16
+
+var ErrInvalidEmoji = errors.New("not a single emoji")
+
+var customEmojiPattern = regexp.MustCompile(`^<(a?):(\w{2,32}):(\d+)>$`)
+
+// ValidateEmoji accepts a standard emoji or a custom guild emote as Discord
+// renders it in messages, e.g. <:skull:123>. An empty emoji is valid.
+func ValidateEmoji(emoji string) error {
+	if emoji == "" || customEmojiPattern.MatchString(emoji) {
+		return nil
+	}
+	if len([]rune(emoji)) > maxEmojiRunes {
+		return ErrInvalidEmoji
+	}
+	symbol := false
+	for _, r := range emoji {
+		switch {
+		case r == '#' || r == '*' || unicode.IsDigit(r) && r < unicode.MaxASCII:
+			// keycap bases such as 1️⃣
+		case unicode.In(r, unicode.So, unicode.Me):
+			// pictographs and the keycap mark
+			symbol = true
+		case unicode.In(r, unicode.Sk, unicode.Mn, unicode.Cf):
+			// skin tones, variation selectors and joiners
+		default:
+			return ErrInvalidEmoji
+		}
+	}
+	if !symbol {
+		return ErrInvalidEmoji
+	}
+	return nil
+}
+
+// ReactionEmoji returns emoji in the form the reaction API expects: custom
+// emotes as name:id, standard emojis unchanged.
+func ReactionEmoji(emoji string) string {
+	if m := customEmojiPattern.FindStringSubmatch(emoji); m != nil {
+		return m[2] + ":" + m[3]
+	}
+	return strings.TrimSpace(emoji)
+}
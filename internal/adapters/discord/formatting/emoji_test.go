@@ -0,0 +1,32 @@
+package formatting
+
+import "testing"
+
+func TestValidateEmoji(t *testing.T) {
+	valid := []string{"", "💀", "⚰️", "🎉", "1️⃣", "👍🏽", "🇵🇱", "👨‍👩‍👧", "<:skull:123456789>", "<a:party_blob:987654321>"}
+	for _, emoji := range valid {
+		if err := ValidateEmoji(emoji); err != nil {
+			t.Errorf("ValidateEmoji(%q): unexpected error %v", emoji, err)
+		}
+	}
+
+	invalid := []string{"skull", "1", ":skull:", "💀 💀", "<:x:123>", "<@123>", "💀💀💀💀💀💀💀💀💀💀💀💀💀💀💀💀💀", "é"}
+	for _, emoji := range invalid {
+		if err := ValidateEmoji(emoji); err == nil {
+			t.Errorf("ValidateEmoji(%q): expected an error", emoji)
+		}
+	}
+}
+
+func TestReactionEmoji(t *testing.T) {
+	tests := map[string]string{
+		"💀":                      "💀",
+		"<:skull:123456789>":     "skull:123456789",
+		"<a:party_blob:9876543>": "party_blob:9876543",
+	}
+	for emoji, want := range tests {
+		if got := ReactionEmoji(emoji); got != want {
+			t.Errorf("ReactionEmoji(%q): expected %q, got %q", emoji, want, got)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package formatting
+
+import (
+	"fmt"
+	"strings"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+const (
+	LangEnglish    = "en"
+	LangPortuguese = "pt-BR"
+	LangPolish     = "pl"
+	LangSpanish    = "es"
+)
+
+const DefaultLanguage = LangEnglish
+
+// maxMessageLength is Discord's limit on message content.
+const maxMessageLength = 2000
+
+// Catalog holds the notification message formats for a single language.
+type Catalog struct {
+	Name        string
+	death       string
+	levelUp     string
+	levelDown   string
+	deathStreak string
+	massDeath   string
+	penalty     string
+	guildRank   string
+	guildJoin   string
+	guildLeave  string
+	warStart    string
+	warEnd      string
+	auction     string
+	auctionEnd  string
+	rashid      string
+	boostedMob  string
+	boostedBoss string
+	renamed     string
+	transferred string
+	catchUp     string
+	catchUpMore string
+	shareRange  string
+	deathPlace  string
+	killers     string
+	assists     string
+	skillUp     string
+	skills      map[domain.Skill]string
+}
+
+var catalogs = map[string]Catalog{
+	LangEnglish: {
+		Name:        "English",
+		death:       "%s - %s - %s",
+		levelUp:     "%s advanced from level %d to %d",
+		levelDown:   "📉 %s dropped from level %d to %d",
+		deathStreak: "%s is on a death streak: %d deaths in the last hour!",
+		massDeath:   "⚔️ Possible war or raid on %s: %d characters died within %d minutes:",
+		penalty:     "(est. loss: %s-%s XP, %s lvl)",
+		guildRank:   "%s of %s",
+		guildJoin:   "%s joined %s",
+		guildLeave:  "%s left %s",
+		warStart:    "⚔️ %s is now at war",
+		warEnd:      "🕊️ %s is no longer at war",
+		auction:     "🏠 %s in %s is up for auction: bid %s gp, %s left",
+		auctionEnd:  "🏠 Auction for %s in %s ended at %s gp",
+		rashid:      "🧳 Rashid is in **%s** today",
+		boostedMob:  "🐾 Boosted creature",
+		boostedBoss: "👑 Boosted boss",
+		renamed:     "✏️ %s is now known as %s",
+		transferred: "✈️ %s moved from %s to %s",
+		catchUp:     "🌙 While quiet hours were on:",
+		catchUpMore: "…and %d more",
+		shareRange:  "(shares XP with %d-%d)",
+		deathPlace:  "(likely at %s)",
+		killers:     "Killers: %s",
+		assists:     "Assists: %s",
+		skillUp:     "📈 %s advanced in %s from %d to %d",
+		skills: map[domain.Skill]string{
+			domain.SkillMagic:     "magic level",
+			domain.SkillFist:      "fist fighting",
+			domain.SkillClub:      "club fighting",
+			domain.SkillSword:     "sword fighting",
+			domain.SkillAxe:       "axe fighting",
+			domain.SkillDistance:  "distance fighting",
+			domain.SkillShielding: "shielding",
+			domain.SkillFishing:   "fishing",
+		},
+	},
+	LangPortuguese: {
+		Name:        "Português (Brasil)",
+		death:       "%s - %s - %s",
+		levelUp:     "%s avançou do nível %d para o %d",
+		levelDown:   "📉 %s caiu do nível %d para o %d",
+		deathStreak: "%s está numa sequência de mortes: %d mortes na última hora!",
+		massDeath:   "⚔️ Possível guerra ou raid em %s: %d personagens morreram em %d minutos:",
+		penalty:     "(perda estimada: %s-%s XP, %s nív.)",
+		guildRank:   "%s de %s",
+		guildJoin:   "%s entrou na guilda %s",
+		guildLeave:  "%s saiu da guilda %s",
+		warStart:    "⚔️ %s entrou em guerra",
+		warEnd:      "🕊️ %s não está mais em guerra",
+		auction:     "🏠 %s em %s está em leilão: lance %s gp, faltam %s",
+		auctionEnd:  "🏠 Leilão de %s em %s terminou em %s gp",
+		rashid:      "🧳 Rashid está em **%s** hoje",
+		boostedMob:  "🐾 Criatura em destaque",
+		boostedBoss: "👑 Boss em destaque",
+		renamed:     "✏️ %s agora se chama %s",
+		transferred: "✈️ %s foi transferido de %s para %s",
+		catchUp:     "🌙 Durante o horário de silêncio:",
+		catchUpMore: "…e mais %d",
+		shareRange:  "(divide XP com %d-%d)",
+		deathPlace:  "(provavelmente em %s)",
+		killers:     "Assassinos: %s",
+		assists:     "Assistências: %s",
+		skillUp:     "📈 %s avançou em %s de %d para %d",
+		skills: map[domain.Skill]string{
+			domain.SkillMagic:     "magic level",
+			domain.SkillFist:      "luta com punhos",
+			domain.SkillClub:      "luta com clava",
+			domain.SkillSword:     "luta com espada",
+			domain.SkillAxe:       "luta com machado",
+			domain.SkillDistance:  "luta à distância",
+			domain.SkillShielding: "defesa",
+			domain.SkillFishing:   "pesca",
+		},
+	},
+	LangPolish: {
+		Name:        "Polski",
+		death:       "%s - %s - %s",
+		levelUp:     "%s awansował z poziomu %d na %d",
+		levelDown:   "📉 %s spadł z poziomu %d na %d",
+		deathStreak: "%s ma serię zgonów: %d śmierci w ciągu ostatniej godziny!",
+		massDeath:   "⚔️ Możliwa wojna lub raid na %s: %d postaci zginęło w ciągu %d minut:",
+		penalty:     "(szacowana strata: %s-%s XP, %s poz.)",
+		guildRank:   "%s gildii %s",
+		guildJoin:   "%s dołączył do %s",
+		guildLeave:  "%s opuścił %s",
+		warStart:    "⚔️ %s jest teraz w stanie wojny",
+		warEnd:      "🕊️ %s nie jest już w stanie wojny",
+		auction:     "🏠 %s w %s wystawiony na aukcję: oferta %s gp, zostało %s",
+		auctionEnd:  "🏠 Aukcja %s w %s zakończona na %s gp",
+		rashid:      "🧳 Rashid jest dziś w **%s**",
+		boostedMob:  "🐾 Wzmocniony potwór",
+		boostedBoss: "👑 Wzmocniony boss",
+		renamed:     "✏️ %s zmienił nazwę na %s",
+		transferred: "✈️ %s przeniósł się z %s na %s",
+		catchUp:     "🌙 W czasie ciszy nocnej:",
+		catchUpMore: "…i %d więcej",
+		shareRange:  "(dzieli XP z %d-%d)",
+		deathPlace:  "(prawdopodobnie: %s)",
+		killers:     "Zabójcy: %s",
+		assists:     "Asysty: %s",
+		skillUp:     "📈 %s awansował w %s z %d na %d",
+		skills: map[domain.Skill]string{
+			domain.SkillMagic:     "magic level",
+			domain.SkillFist:      "walce pięściami",
+			domain.SkillClub:      "walce maczugą",
+			domain.SkillSword:     "walce mieczem",
+			domain.SkillAxe:       "walce toporem",
+			domain.SkillDistance:  "walce na odległość",
+			domain.SkillShielding: "obronie tarczą",
+			domain.SkillFishing:   "wędkarstwie",
+		},
+	},
+	LangSpanish: {
+		Name:        "Español",
+		death:       "%s - %s - %s",
+		levelUp:     "%s subió del nivel %d al %d",
+		levelDown:   "📉 %s bajó del nivel %d al %d",
+		deathStreak: "%s está en una racha de muertes: %d muertes en la última hora!",
+		massDeath:   "⚔️ Posible guerra o raid en %s: %d personajes murieron en %d minutos:",
+		penalty:     "(pérdida estimada: %s-%s XP, %s niv.)",
+		guildRank:   "%s de %s",
+		guildJoin:   "%s se unió a %s",
+		guildLeave:  "%s dejó %s",
+		warStart:    "⚔️ %s está ahora en guerra",
+		warEnd:      "🕊️ %s ya no está en guerra",
+		auction:     "🏠 %s en %s está en subasta: oferta %s gp, quedan %s",
+		auctionEnd:  "🏠 La subasta de %s en %s terminó en %s gp",
+		rashid:      "🧳 Rashid está hoy en **%s**",
+		boostedMob:  "🐾 Criatura potenciada",
+		boostedBoss: "👑 Jefe potenciado",
+		renamed:     "✏️ %s ahora se llama %s",
+		transferred: "✈️ %s se transfirió de %s a %s",
+		catchUp:     "🌙 Durante las horas de silencio:",
+		catchUpMore: "…y %d más",
+		shareRange:  "(comparte XP con %d-%d)",
+		deathPlace:  "(probablemente en %s)",
+		killers:     "Asesinos: %s",
+		assists:     "Asistencias: %s",
+		skillUp:     "📈 %s subió %s de %d a %d",
+		skills: map[domain.Skill]string{
+			domain.SkillMagic:     "magic level",
+			domain.SkillFist:      "lucha con puños",
+			domain.SkillClub:      "lucha con maza",
+			domain.SkillSword:     "lucha con espada",
+			domain.SkillAxe:       "lucha con hacha",
+			domain.SkillDistance:  "lucha a distancia",
+			domain.SkillShielding: "escudo",
+			domain.SkillFishing:   "pesca",
+		},
+	},
+}
+
+// SupportedLanguages returns the language codes in a stable display order.
+func SupportedLanguages() []string {
+	return []string{LangEnglish, LangPortuguese, LangPolish, LangSpanish}
+}
+
+func IsSupportedLanguage(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// CatalogFor returns the catalog for lang, falling back to English.
+func CatalogFor(lang string) Catalog {
+	if c, ok := catalogs[lang]; ok {
+		return c
+	}
+	return catalogs[DefaultLanguage]
+}
+
+// Death formats a death message, appending the estimated experience loss
+// when the level at death is known.
+func (c Catalog) Death(name, timeStr, reason string, level int) string {
+	msg := fmt.Sprintf(c.death, name, timeStr, reason)
+	if level <= 0 {
+		return msg
+	}
+	return msg + " " + c.Penalty(domain.EstimateDeathPenalty(level))
+}
+
+func (c Catalog) Penalty(p domain.DeathPenalty) string {
+	levels := fmt.Sprintf("%d", p.MinLevels)
+	if p.MinLevels != p.MaxLevels {
+		levels = fmt.Sprintf("%d-%d", p.MinLevels, p.MaxLevels)
+	}
+	return fmt.Sprintf(c.penalty, formatThousands(p.MinExp), formatThousands(p.MaxExp), levels)
+}
+
+func (c Catalog) LevelUp(name string, oldLevel, newLevel int) string {
+	return fmt.Sprintf(c.levelUp, name, oldLevel, newLevel)
+}
+
+func (c Catalog) LevelDown(name string, oldLevel, newLevel int) string {
+	return fmt.Sprintf(c.levelDown, name, oldLevel, newLevel)
+}
+
+// ShareRange formats the levels a character can share party experience with.
+func (c Catalog) ShareRange(r domain.ShareRange) string {
+	return fmt.Sprintf(c.shareRange, r.Min, r.Max)
+}
+
+// DeathLocation formats where a character likely died.
+func (c Catalog) DeathLocation(location string) string {
+	return fmt.Sprintf(c.deathPlace, location)
+}
+
+// Killers and Assists list the characters and creatures behind a death.
+func (c Catalog) Killers(names []string) string {
+	return fmt.Sprintf(c.killers, strings.Join(names, ", "))
+}
+
+func (c Catalog) Assists(names []string) string {
+	return fmt.Sprintf(c.assists, strings.Join(names, ", "))
+}
+
+// SkillAdvance formats a skill advance with the skill's localized name.
+func (c Catalog) SkillAdvance(a domain.SkillAdvance) string {
+	return fmt.Sprintf(c.skillUp, a.PlayerName, c.SkillName(a.Skill), a.OldValue, a.NewValue)
+}
+
+// SkillName localizes skill, falling back to its identifier.
+func (c Catalog) SkillName(skill domain.Skill) string {
+	if name, ok := c.skills[skill]; ok {
+		return name
+	}
+	return string(skill)
+}
+
+func (c Catalog) DeathStreak(name string, deaths int) string {
+	return fmt.Sprintf(c.deathStreak, name, deaths)
+}
+
+// MassDeath lists the victims of a death cluster one per line after the
+// alert, cut short like CatchUp to fit one message.
+func (c Catalog) MassDeath(m domain.MassDeath) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, c.massDeath, m.World, len(m.Victims), int(m.Window.Minutes()))
+	for i, v := range m.Victims {
+		line := fmt.Sprintf("• %s (%d)", v.Name, v.Level)
+		more := fmt.Sprintf(c.catchUpMore, len(m.Victims)-i)
+		if b.Len()+len(line)+len(more)+2 > maxMessageLength {
+			b.WriteString("\n" + more)
+			break
+		}
+		b.WriteString("\n" + line)
+	}
+	return b.String()
+}
+
+func (c Catalog) GuildJoined(name, guildName string) string {
+	return fmt.Sprintf(c.guildJoin, name, guildName)
+}
+
+func (c Catalog) GuildLeft(name, guildName string) string {
+	return fmt.Sprintf(c.guildLeave, name, guildName)
+}
+
+// GuildWar announces that a guild entered a war, or left it once the war
+// has ended.
+func (c Catalog) GuildWar(war domain.GuildWar) string {
+	if war.Ongoing() {
+		return fmt.Sprintf(c.warStart, war.GuildName)
+	}
+	return fmt.Sprintf(c.warEnd, war.GuildName)
+}
+
+func (c Catalog) HouseAuction(a domain.HouseAuction) string {
+	return fmt.Sprintf(c.auction, a.Name, a.Town, formatThousands(int64(a.CurrentBid)), a.TimeLeft)
+}
+
+func (c Catalog) HouseAuctionEnded(a domain.HouseAuction) string {
+	return fmt.Sprintf(c.auctionEnd, a.Name, a.Town, formatThousands(int64(a.CurrentBid)))
+}
+
+func (c Catalog) Rashid(city string) string {
+	return fmt.Sprintf(c.rashid, city)
+}
+
+// BoostedCreature and BoostedBoss title the embeds of the daily boosted post.
+func (c Catalog) BoostedCreature() string { return c.boostedMob }
+
+func (c Catalog) BoostedBoss() string { return c.boostedBoss }
+
+// CharacterChange describes a rename and a world transfer on one line each.
+func (c Catalog) CharacterChange(change domain.CharacterChange) string {
+	var lines []string
+	if change.OldName != "" {
+		lines = append(lines, fmt.Sprintf(c.renamed, change.OldName, change.Name))
+	}
+	if change.OldWorld != "" {
+		lines = append(lines, fmt.Sprintf(c.transferred, change.Name, change.OldWorld, change.World))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CatchUp lists the events held back during quiet hours under a header,
+// cutting the list short to stay within Discord's message limit.
+func (c Catalog) CatchUp(lines []string) string {
+	var b strings.Builder
+	b.WriteString(c.catchUp)
+	for i, line := range lines {
+		more := fmt.Sprintf(c.catchUpMore, len(lines)-i)
+		if b.Len()+len(line)+len(more)+2 > maxMessageLength {
+			b.WriteString("\n" + more)
+			break
+		}
+		b.WriteString("\n" + line)
+	}
+	return b.String()
+}
+
+// PlayerLabel decorates a character name with its vocation, guild rank and
+// world PvP type, e.g. "Hero (Elite Knight, Leader of Red Rose, Open PvP)".
+// Unknown parts are omitted.
+func (c Catalog) PlayerLabel(name, vocation, guildName, guildRank, pvpType string) string {
+	var parts []string
+	if vocation != "" && vocation != "None" {
+		parts = append(parts, vocation)
+	}
+	switch {
+	case guildName != "" && guildRank != "":
+		parts = append(parts, fmt.Sprintf(c.guildRank, guildRank, guildName))
+	case guildName != "":
+		parts = append(parts, guildName)
+	}
+	if pvpType != "" {
+		parts = append(parts, pvpType)
+	}
+
+	if len(parts) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(parts, ", "))
+}
@@ -0,0 +1,208 @@
+package formatting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestCatalogFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		expected string
+	}{
+		{"english", LangEnglish, "Knight Bob advanced from level 100 to 101"},
+		{"portuguese", LangPortuguese, "Knight Bob avançou do nível 100 para o 101"},
+		{"polish", LangPolish, "Knight Bob awansował z poziomu 100 na 101"},
+		{"spanish", LangSpanish, "Knight Bob subió del nivel 100 al 101"},
+		{"unknown falls back to english", "xx", "Knight Bob advanced from level 100 to 101"},
+		{"empty falls back to english", "", "Knight Bob advanced from level 100 to 101"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CatalogFor(tt.lang).LevelUp("Knight Bob", 100, 101)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCatalog_Death(t *testing.T) {
+	for _, lang := range SupportedLanguages() {
+		t.Run(lang, func(t *testing.T) {
+			result := CatalogFor(lang).Death("Hero", "2024-12-13 10:30", "Killed by a dragon", 0)
+			expected := "Hero - 2024-12-13 10:30 - Killed by a dragon"
+			if result != expected {
+				t.Errorf("Expected '%s', got '%s'", expected, result)
+			}
+		})
+	}
+}
+
+func TestSupportedLanguages(t *testing.T) {
+	langs := SupportedLanguages()
+	if len(langs) != 4 {
+		t.Fatalf("Expected 4 languages, got %d", len(langs))
+	}
+
+	for _, lang := range langs {
+		if !IsSupportedLanguage(lang) {
+			t.Errorf("Expected %q to be supported", lang)
+		}
+		if CatalogFor(lang).Name == "" {
+			t.Errorf("Expected %q to have a display name", lang)
+		}
+	}
+
+	if IsSupportedLanguage("xx") {
+		t.Error("Expected 'xx' to be unsupported")
+	}
+}
+
+func TestCatalog_DeathStreak(t *testing.T) {
+	result := CatalogFor(LangEnglish).DeathStreak("Hero", 3)
+	expected := "Hero is on a death streak: 3 deaths in the last hour!"
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestCatalog_HouseAuction(t *testing.T) {
+	auction := domain.HouseAuction{Name: "Elm Lane 1", Town: "Thais", CurrentBid: 1250000, TimeLeft: "2 days"}
+	catalog := CatalogFor(LangEnglish)
+
+	if got, want := catalog.HouseAuction(auction), "🏠 Elm Lane 1 in Thais is up for auction: bid 1,250,000 gp, 2 days left"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+	if got, want := catalog.HouseAuctionEnded(auction), "🏠 Auction for Elm Lane 1 in Thais ended at 1,250,000 gp"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+}
+
+func TestCatalog_GuildWar(t *testing.T) {
+	war := domain.GuildWar{GuildName: "Red Rose", StartedAt: time.Now().Add(-time.Hour)}
+	catalog := CatalogFor(LangEnglish)
+
+	if got, want := catalog.GuildWar(war), "⚔️ Red Rose is now at war"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+	war.EndedAt = time.Now()
+	if got, want := catalog.GuildWar(war), "🕊️ Red Rose is no longer at war"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+}
+
+func TestCatalog_Rashid(t *testing.T) {
+	if got, want := CatalogFor(LangPolish).Rashid("Edron"), "🧳 Rashid jest dziś w **Edron**"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+}
+
+func TestCatalog_CharacterChange(t *testing.T) {
+	catalog := CatalogFor(LangEnglish)
+
+	renamed := catalog.CharacterChange(domain.CharacterChange{Name: "New Hero", OldName: "Old Hero", World: "Antica"})
+	if want := "✏️ Old Hero is now known as New Hero"; renamed != want {
+		t.Errorf("Expected '%s', got '%s'", want, renamed)
+	}
+
+	both := catalog.CharacterChange(domain.CharacterChange{Name: "New Hero", OldName: "Old Hero", World: "Secura", OldWorld: "Antica"})
+	if want := "✏️ Old Hero is now known as New Hero\n✈️ New Hero moved from Antica to Secura"; both != want {
+		t.Errorf("Expected '%s', got '%s'", want, both)
+	}
+}
+
+func TestCatalog_DeathWithPenalty(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		expected string
+	}{
+		{"english", LangEnglish, "Hero - 2024-12-13 10:30 - Killed by a dragon (est. loss: 213,930-713,100 XP, 1-2 lvl)"},
+		{"spanish", LangSpanish, "Hero - 2024-12-13 10:30 - Killed by a dragon (pérdida estimada: 213,930-713,100 XP, 1-2 niv.)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CatalogFor(tt.lang).Death("Hero", "2024-12-13 10:30", "Killed by a dragon", 100)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCatalog_PenaltySingleLevel(t *testing.T) {
+	p := domain.DeathPenalty{MinExp: 126, MaxExp: 420, MinLevels: 1, MaxLevels: 1}
+	expected := "(est. loss: 126-420 XP, 1 lvl)"
+	if result := CatalogFor(LangEnglish).Penalty(p); result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestCatalog_PlayerLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		lang      string
+		vocation  string
+		guildName string
+		guildRank string
+		pvpType   string
+		expected  string
+	}{
+		{"name only", LangEnglish, "", "", "", "", "Hero"},
+		{"no vocation", LangEnglish, "None", "", "", "", "Hero"},
+		{"vocation only", LangEnglish, "Elite Knight", "", "", "", "Hero (Elite Knight)"},
+		{"vocation and rank", LangEnglish, "Elite Knight", "Red Rose", "Leader", "", "Hero (Elite Knight, Leader of Red Rose)"},
+		{"guild without rank", LangEnglish, "", "Red Rose", "", "", "Hero (Red Rose)"},
+		{"localized rank", LangPortuguese, "Elder Druid", "Red Rose", "Leader", "", "Hero (Elder Druid, Leader de Red Rose)"},
+		{"pvp type only", LangEnglish, "", "", "", "Open PvP", "Hero (Open PvP)"},
+		{"all parts", LangEnglish, "Elite Knight", "Red Rose", "Leader", "Retro Hardcore PvP", "Hero (Elite Knight, Leader of Red Rose, Retro Hardcore PvP)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CatalogFor(tt.lang).PlayerLabel("Hero", tt.vocation, tt.guildName, tt.guildRank, tt.pvpType)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCatalog_CatchUp(t *testing.T) {
+	catalog := CatalogFor(LangEnglish)
+
+	if got, want := catalog.CatchUp([]string{"a", "b"}), "🌙 While quiet hours were on:\na\nb"; got != want {
+		t.Errorf("Expected '%s', got '%s'", want, got)
+	}
+
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 50)
+	}
+	got := catalog.CatchUp(lines)
+	if len(got) > maxMessageLength || !strings.HasSuffix(got, " more") {
+		t.Errorf("expected a cut list within %d bytes, got %d bytes ending %q", maxMessageLength, len(got), got[len(got)-20:])
+	}
+}
+
+func TestCatalog_MassDeath(t *testing.T) {
+	massDeath := domain.MassDeath{
+		World:  "Antica",
+		Window: 10 * time.Minute,
+		Victims: []domain.MassDeathVictim{
+			{Name: "Hero", Level: 300},
+			{Name: "Mage", Level: 412},
+		},
+	}
+	want := "⚔️ Possible war or raid on Antica: 2 characters died within 10 minutes:\n• Hero (300)\n• Mage (412)"
+	if got := CatalogFor(LangEnglish).MassDeath(massDeath); got != want {
+		t.Errorf("MassDeath() = %q, want %q", got, want)
+	}
+}
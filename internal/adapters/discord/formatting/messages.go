@@ -1,24 +1,77 @@
 package formatting
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
 
 const (
-	MsgAdminRequired     = "You need Administrator permissions to use this command."
-	MsgWorldRequired     = "World name is required."
-	MsgGuildNameRequired = "Guild name is required."
-	MsgSaveError         = "Failed to save configuration."
-	MsgStopError         = "Failed to stop tracking."
-	MsgStopSuccess       = "Tracking stopped. Configuration removed."
-	MsgConfigError       = "Failed to retrieve configuration."
-	MsgNoGuildsTracked   = "No guilds are currently being tracked (all players will be tracked)."
+	MsgAdminRequired         = "You need Administrator permissions to use this command."
+	MsgWorldRequired         = "World name is required."
+	MsgGuildNameRequired     = "Guild name is required."
+	MsgPlayerNameRequired    = "Character name is required."
+	MsgSaveError             = "Failed to save configuration."
+	MsgStopError             = "Failed to stop tracking."
+	MsgStopSuccess           = "Tracking stopped. Configuration removed; /resume-tracking restores it within 30 days."
+	MsgResumeError           = "Failed to resume tracking."
+	MsgNothingToResume       = "There is no configuration stopped within the last 30 days to restore."
+	MsgResumeWorldTracked    = "This server tracks a world again, so its previous configuration was not restored."
+	MsgStopCancelled         = "Tracking was not stopped."
+	MsgStopConfirmButton     = "Stop tracking"
+	MsgCancelButton          = "Cancel"
+	MsgConfirmExpired        = "This confirmation expired. Run the command again."
+	MsgPurgeError            = "Failed to delete this server's data."
+	MsgPurgeCancelled        = "No data was deleted."
+	MsgPurgeConfirmButton    = "Delete all data"
+	MsgCompareNamesInvalid   = "Two different character names are required."
+	MsgCompareError          = "Failed to look up both characters. Check the names and try again."
+	MsgPaceError             = "Failed to look up the character. Check the name and try again."
+	MsgWarScoreError         = "Failed to look up both guilds. Check the names and try again."
+	MsgWarGuildRequired      = "This server tracks several Tibia guilds. Pick one with the guild option."
+	MsgConfigError           = "Failed to retrieve configuration."
+	MsgNoGuildsTracked       = "No guilds are currently being tracked (all players will be tracked)."
+	MsgLanguageInvalid       = "Unsupported language."
+	MsgTimezoneInvalid       = "Unknown timezone. Use an IANA name such as Europe/Warsaw or America/Sao_Paulo."
+	MsgQuietHoursInvalid     = "Use two different times such as 02:00-08:00, or off."
+	MsgChannelInvalid        = "A valid notification type and text channel are required."
+	MsgEmojiInvalid          = "Use a single emoji or one of this server's custom emojis."
+	MsgThreadLocked          = "That thread is locked. Unlock it or pick another channel so notifications can reopen it when it archives."
+	MsgRoleRequired          = "A role is required."
+	MsgTooManyDeathRoutes    = "This server already routes deaths by 5 level brackets. Remove one with /route-deaths first."
+	MsgMinLevelInvalid       = "Minimum level cannot be negative."
+	MsgSkillInvalid          = "Unknown skill."
+	MsgWorldNotTracked       = "No world is tracked yet. Use /track-world first."
+	MsgGuildLookupError      = "Failed to look up the guild on TibiaData. Try again later."
+	MsgCharacterLookupError  = "Failed to look up the character on TibiaData. Try again later."
+	MsgStatsError            = "Failed to retrieve statistics."
+	MsgExportError           = "Failed to export. Try again later."
+	MsgPollIntervalInvalid   = "Polling interval must be between 0 and 1440 minutes."
+	MsgTemplatesPremium      = "Custom templates are a premium feature. Run /set-template without a template to reset one."
+	MsgRetryError            = "Failed to retry notifications."
+	MsgGuildSyncError        = "Failed to sync guild members."
+	MsgPermissionsOK         = "The bot has all the permissions it needs."
+	MsgMuteInvalid           = "Mute duration must be between 0 and 168 hours."
+	MsgMassDeathAlertInvalid = "The alert needs 2 to 50 deaths within 1 to 60 minutes, or 0 deaths to turn it off."
+	MsgGuildMinLevelInvalid  = "The minimum level must be between 0 and 5000."
+	MsgVisibilityInvalid     = "Visibility must be default, ephemeral or public."
+	MsgVerbosityInvalid      = "Verbosity must be compact, normal or detailed."
+	MsgCommandError          = "Something went wrong while running this command."
+	MsgWelcome               = "👋 Thanks for adding Death Level Tracker! An administrator can start with `/track-world` to pick the Tibia world, then `/add-guild` to follow specific Tibia guilds. `/check-permissions` lists anything the bot is still missing."
+	MsgWelcomeBack           = "👋 Welcome back! This server's previous Death Level Tracker configuration was restored, and tracking resumes with the next cycle."
+	MsgTestNotification      = "🔔 Test notification from Death Level Tracker. Notifications can reach this channel."
 )
 
-func MsgDeath(name, timeStr, reason string) string {
-	return fmt.Sprintf("%s - %s - %s", name, timeStr, reason)
+func MsgDeath(name, timeStr, reason string, level int) string {
+	return CatalogFor(DefaultLanguage).Death(name, timeStr, reason, level)
 }
 
 func MsgLevelUp(name string, oldLevel, newLevel int) string {
-	return fmt.Sprintf("%s advanced from level %d to %d", name, oldLevel, newLevel)
+	return CatalogFor(DefaultLanguage).LevelUp(name, oldLevel, newLevel)
 }
 
 func MsgChannelError(channelName string) string {
@@ -29,18 +82,603 @@ func MsgTrackSuccess(world, deathChan, levelChan string) string {
 	return fmt.Sprintf("Tracking world **%s** configured! Notifications will appear in #%s and #%s.", world, deathChan, levelChan)
 }
 
+// Texts of the setup wizard sent after the first /track-world.
+const (
+	MsgSetupGuildButton         = "Add Tibia guild"
+	MsgSetupMinLevelButton      = "Set minimum level"
+	MsgSetupFinishButton        = "Finish"
+	MsgSetupLanguagePlaceholder = "Notification language"
+	MsgSetupGuildTitle          = "Add a Tibia guild"
+	MsgSetupGuildInput          = "Tibia guild name"
+	MsgSetupMinLevelTitle       = "Minimum level"
+	MsgSetupMinLevelInput       = "Lowest level to announce (0 for the default)"
+	MsgSetupDone                = "✅ Setup finished. /track-status shows the configuration, and every setting can be changed with its command."
+	MsgSetupExpired             = "This setup menu has expired. Every setting can still be changed with its command."
+)
+
+// MsgSetupMenu is the setup wizard's text, followed by the outcome of the
+// last step when there is one.
+func MsgSetupMenu(status string) string {
+	msg := "🧭 **Quick setup**\nFollow Tibia guilds, pick the notification language and the lowest level worth announcing. Skip anything that is fine as it is."
+	if status != "" {
+		msg += "\n\n" + status
+	}
+	return msg
+}
+
 func MsgGuildAdded(name string) string {
 	return fmt.Sprintf("Added guild '%s' to tracking list.", name)
 }
 
+func MsgGuildNotFound(name string) string {
+	return fmt.Sprintf("Guild '%s' does not exist on TibiaData.", name)
+}
+
+func MsgCharacterNotFound(name string) string {
+	return fmt.Sprintf("Character '%s' does not exist on TibiaData.", name)
+}
+
+func MsgPlayerOtherWorld(name, world, trackedWorld string) string {
+	return fmt.Sprintf("Character '%s' plays on **%s**, but this server tracks **%s**.", name, world, trackedWorld)
+}
+
+func MsgGuildOtherWorld(name, world, trackedWorld string) string {
+	return fmt.Sprintf("Guild '%s' plays on **%s**, but this server tracks **%s**.", name, world, trackedWorld)
+}
+
+func MsgTibiaGuildLimit(limit int) string {
+	return fmt.Sprintf("This server already tracks %d Tibia guilds, the most it can. Remove one with /unset-guild first.", limit)
+}
+
+func MsgWatchedPlayerLimit(limit int) string {
+	return fmt.Sprintf("This server already watches %d characters, the most it can. Remove one with /unwatch-player first.", limit)
+}
+
+func MsgIgnoredPlayerLimit(limit int) string {
+	return fmt.Sprintf("This server already ignores %d characters, the most it can. Remove one with /unignore-player first.", limit)
+}
+
+func MsgWorldLimit(limit int) string {
+	return fmt.Sprintf("This bot already tracks %d worlds, the most it can. Pick a world another server tracks, or ask the bot's operator.", limit)
+}
+
+func MsgPollIntervalPremium(floor time.Duration) string {
+	return fmt.Sprintf("Polling more often than every %s is a premium feature.", floor)
+}
+
+func MsgStopConfirm(expires time.Time) string {
+	return fmt.Sprintf("⚠️ This removes the tracked world, Tibia guilds and every setting for this server. /resume-tracking can restore them within 30 days. The buttons expire <t:%d:R>.", expires.Unix())
+}
+
+func MsgResumeSuccess(world string) string {
+	return fmt.Sprintf("Tracking of world **%s** resumed with this server's previous configuration.", world)
+}
+
+func MsgPurgeConfirm(expires time.Time) string {
+	return fmt.Sprintf("⚠️ This permanently deletes this server's configuration, queued notifications and any tracked deaths, level history and guild members no other server uses. The buttons expire <t:%d:R>.", expires.Unix())
+}
+
+func MsgPurgeComplete(result domain.PurgeResult) string {
+	return fmt.Sprintf("🧹 All data for this server was deleted, including %d queued notifications, %d guild members, %d deaths, %d level ups and %d player levels.",
+		result.Notifications, result.GuildMembers, result.Deaths, result.LevelUps, result.Players)
+}
+
+func MsgGuildSynced(name string, seeded int) string {
+	return fmt.Sprintf("Synced %d members of guild '%s'. Their levels will be tracked from the next cycle.", seeded, name)
+}
+
 func MsgGuildRemoved(name string) string {
 	return fmt.Sprintf("Removed guild '%s' from tracking list.", name)
 }
 
-func MsgGuildsList(guilds []string) string {
-	msg := "Tracking specific guilds:\n"
-	for _, g := range guilds {
-		msg += "- " + g + "\n"
+func MsgPlayerIgnored(name string) string {
+	return fmt.Sprintf("Ignoring character '%s'. Its deaths and level ups will not be announced.", name)
+}
+
+func MsgPlayerUnignored(name string) string {
+	return fmt.Sprintf("Character '%s' is no longer ignored.", name)
+}
+
+// MsgGuildsList lists the tracked Tibia guilds, paginated.
+func MsgGuildsList(guilds []string) []string {
+	lines := make([]string, len(guilds))
+	for i, g := range guilds {
+		lines[i] = "- " + g
+	}
+	return Paginate("Tracking specific guilds:\n", lines)
+}
+
+func MsgLanguageSet(language string) string {
+	return fmt.Sprintf("Notification language set to **%s**.", language)
+}
+
+func MsgChannelSet(kind, channelID string) string {
+	return fmt.Sprintf("Notifications for %s will be posted in <#%s>.", kind, channelID)
+}
+
+func MsgTemplateSet(kind, preview string) string {
+	return fmt.Sprintf("Notifications for %s will look like:\n> %s", kind, preview)
+}
+
+func MsgTemplateReset(kind string) string {
+	return fmt.Sprintf("Notifications for %s use the default message again.", kind)
+}
+
+// MsgTemplateInvalid explains why a template was rejected, listing the
+// placeholders available when it used an unknown one.
+func MsgTemplateInvalid(err error) string {
+	var unknown *UnknownPlaceholderError
+	if errors.As(err, &unknown) {
+		available := make([]string, len(unknown.Allowed))
+		for i, name := range unknown.Allowed {
+			available[i] = "`{" + name + "}`"
+		}
+		return fmt.Sprintf("Unknown placeholder `{%s}`. Available: %s.", unknown.Placeholder, strings.Join(available, ", "))
+	}
+	return fmt.Sprintf("Invalid template: %s.", err)
+}
+
+func MsgEmojiSet(kind, emoji string, react bool) string {
+	msg := fmt.Sprintf("Notifications for %s will have no emoji", kind)
+	if emoji != "" {
+		msg = fmt.Sprintf("Notifications for %s will start with %s", kind, emoji)
+	}
+	if react {
+		return msg + " and get a reaction from the bot."
+	}
+	return msg + "."
+}
+
+func MsgRoleMention(roleID string) string {
+	return fmt.Sprintf("<@&%s>", roleID)
+}
+
+func MsgPingRoleSet(roleID string, minLevel int) string {
+	return fmt.Sprintf("%s will be mentioned on deaths at level %d or higher.", MsgRoleMention(roleID), minLevel)
+}
+
+func MsgPollIntervalSet(interval time.Duration) string {
+	if interval == 0 {
+		return "Polling interval reset to the default."
+	}
+	return fmt.Sprintf("The tracked world will be checked every %s.", interval)
+}
+
+func MsgRetryFailed(sent, failed, dropped int) string {
+	if sent+failed+dropped == 0 {
+		return "No failed notifications are waiting."
+	}
+	return fmt.Sprintf("Retried notifications: %d sent, %d still failing, %d dropped.", sent, failed, dropped)
+}
+
+func MsgMissingPermissions(missing []string) string {
+	msg := "The bot is missing permissions:\n"
+	for _, m := range missing {
+		msg += "- " + m + "\n"
+	}
+	return msg + "Grant them in Server Settings → Roles or the channel's permission overrides, then try again."
+}
+
+func MsgChannelPermission(channelID, permission string) string {
+	return fmt.Sprintf("%s in <#%s>", permission, channelID)
+}
+
+func MsgChannelPermissionsUnknown(channelID string) string {
+	return fmt.Sprintf("Could not read permissions in <#%s>", channelID)
+}
+
+func MsgMuted(until time.Time) string {
+	if until.IsZero() {
+		return "Notifications resumed."
+	}
+	return fmt.Sprintf("Notifications muted until <t:%d:f>.", until.Unix())
+}
+
+func MsgDeathRouteSet(minLevel int, channelID string) string {
+	return fmt.Sprintf("Deaths at level %d+ will be posted to <#%s>.", minLevel, channelID)
+}
+
+func MsgDeathRouteRemoved(minLevel int, removed bool) string {
+	if !removed {
+		return fmt.Sprintf("No death route exists for level %d.", minLevel)
+	}
+	return fmt.Sprintf("Deaths at level %d+ are no longer routed separately.", minLevel)
+}
+
+func MsgQuietHoursSet(quiet domain.QuietHours, timezone string) string {
+	if !quiet.Enabled() {
+		return "Quiet hours turned off."
+	}
+	if timezone == "" {
+		timezone = "the bot's timezone"
+	}
+	msg := fmt.Sprintf("No notifications will be posted between **%s** (%s).", quiet, timezone)
+	if quiet.CatchUp {
+		return msg + " Deaths and level ups from that time will be posted as one catch-up message when it ends."
+	}
+	return msg + " Deaths and level ups from that time will not be posted."
+}
+
+func MsgTimezoneSet(timezone string) string {
+	return fmt.Sprintf("Death times will be shown in **%s**.", timezone)
+}
+
+func MsgMassDeathAlertSet(alert domain.MassDeathAlert) string {
+	if !alert.Enabled() {
+		return "Mass death alerts turned off."
+	}
+	return fmt.Sprintf("A possible war or raid will be announced when %d tracked characters die within %d minutes.", alert.Deaths, int(alert.Window.Minutes()))
+}
+
+func MsgShareRangeSet(enabled bool) string {
+	if enabled {
+		return "Level ups will show the levels the character can share experience with."
+	}
+	return "Level ups will no longer show the experience share range."
+}
+
+func MsgLevelDownsSet(enabled bool) string {
+	if enabled {
+		return fmt.Sprintf("Drops of %d or more levels will be announced in the level channel once seen twice in a row.", domain.MinLevelDrop)
+	}
+	return "Level downs will no longer be announced."
+}
+
+func MsgResponseVisibilitySet(visibility domain.ResponseVisibility) string {
+	switch visibility {
+	case domain.VisibilityEphemeral:
+		return "Replies to commands will only be visible to whoever ran them."
+	case domain.VisibilityPublic:
+		return "Replies to commands will be visible to everyone in the channel. Confirmation prompts stay private."
+	}
+	return "Each command will pick who sees its replies again."
+}
+
+func MsgDeathVerbositySet(verbosity domain.DeathVerbosity) string {
+	switch verbosity {
+	case domain.VerbosityCompact:
+		return "Deaths will be announced in one short line with the character name and death reason."
+	case domain.VerbosityDetailed:
+		return "Deaths will also list the killers and assists, with links to the characters on tibia.com."
+	}
+	return "Deaths will be announced with the vocation, guild and estimated experience loss."
+}
+
+func MsgDeathLocationSet(enabled bool) string {
+	if enabled {
+		return "Deaths will show where the character likely died, guessed from its killers."
+	}
+	return "Deaths will no longer show the likely death location."
+}
+
+func MsgAnnouncementsSet(enabled bool) string {
+	if enabled {
+		return "Announcements from the bot operator, such as maintenance notices, will be posted in the death channel."
+	}
+	return "Announcements from the bot operator will no longer be posted here."
+}
+
+// MaxBroadcastLength leaves room for MsgBroadcast's header within Discord's
+// message limit.
+const MaxBroadcastLength = maxMessageLength - 100
+
+// MsgBroadcast formats an announcement sent with the broadcast admin command.
+func MsgBroadcast(message string) string {
+	return "📢 **Announcement from the bot operator**\n" + message + "\n-# Administrators can turn these off with `/set-announcements enabled:False`."
+}
+
+func MsgLowLevelDeathsSet(enabled bool, minLevel int) string {
+	if enabled {
+		return fmt.Sprintf("Deaths of tracked guild members below level %d will be announced.", minLevel)
+	}
+	return fmt.Sprintf("Only deaths at level %d or above will be announced.", minLevel)
+}
+
+// MsgMinLevelSet confirms a guild minimum level; levels at or below the
+// global minimum leave the global one in effect.
+func MsgMinLevelSet(level, globalMin int) string {
+	if level <= globalMin {
+		return fmt.Sprintf("Deaths and level ups from level %d up will be announced.", globalMin)
+	}
+	return fmt.Sprintf("Only deaths and level ups from level %d up will be announced.", level)
+}
+
+func MsgTrackHouses(channelID string) string {
+	if channelID == "" {
+		return "House auctions will no longer be announced."
+	}
+	return fmt.Sprintf("House auctions on the tracked world will be announced in <#%s>.", channelID)
+}
+
+func MsgTrackSkills(channelID string) string {
+	if channelID == "" {
+		return "Skill advances will no longer be announced."
+	}
+	return fmt.Sprintf("Skill advances of watched characters will be announced in <#%s>. Add characters with /watch-player.", channelID)
+}
+
+func MsgPlayerWatched(name string) string {
+	return fmt.Sprintf("Watching character '%s'. Its skill advances will be announced once it ranks on the world highscores.", name)
+}
+
+func MsgPlayerUnwatched(name string) string {
+	return fmt.Sprintf("Character '%s' is no longer watched.", name)
+}
+
+func MsgSkillThresholdSet(skill string, minValue int) string {
+	return fmt.Sprintf("Only %s advances to %d or higher will be announced.", skill, minValue)
+}
+
+func MsgSkillThresholdRemoved(skill string, removed bool) string {
+	if !removed {
+		return fmt.Sprintf("No threshold is set for %s.", skill)
+	}
+	return fmt.Sprintf("Every %s advance will be announced again.", skill)
+}
+
+func MsgRashid(today, tomorrow string, next time.Time) string {
+	return fmt.Sprintf("Rashid is in **%s** until server save <t:%d:R>, then moves to **%s**.", today, next.Unix(), tomorrow)
+}
+
+func MsgExportEmpty(kind, window string) string {
+	return fmt.Sprintf("No %s recorded for this server (%s).", exportNoun(kind), strings.ToLower(window))
+}
+
+func MsgExport(kind, window string, rows int, truncated bool) string {
+	msg := fmt.Sprintf("Exported %s %s (%s).", formatThousands(int64(rows)), exportNoun(kind), strings.ToLower(window))
+	if truncated {
+		msg += " The export stopped at this limit; pick a shorter window for the rest."
+	}
+	return msg
+}
+
+func exportNoun(kind string) string {
+	if kind == string(domain.ChannelLevels) {
+		return "level ups"
+	}
+	return "deaths"
+}
+
+// MsgDeathsToday ranks the characters that died today, paginated.
+func MsgDeathsToday(world string, counts []domain.DeathCount) []string {
+	if len(counts) == 0 {
+		return []string{fmt.Sprintf("No deaths on **%s** today.", world)}
+	}
+
+	lines := make([]string, len(counts))
+	for i, c := range counts {
+		lines[i] = fmt.Sprintf("%d. %s - %d", i+1, c.Name, c.Count)
+	}
+	return Paginate(fmt.Sprintf("Deaths on **%s** today:\n", world), lines)
+}
+
+// MsgFrags ranks characters by their frags since server save, paginated.
+func MsgFrags(world string, counts []domain.FragCount) []string {
+	if len(counts) == 0 {
+		return []string{fmt.Sprintf("No frags on **%s** since server save.", world)}
+	}
+
+	lines := make([]string, len(counts))
+	for i, c := range counts {
+		lines[i] = fmt.Sprintf("%d. %s - %s", i+1, c.Name, fragLine(c))
+	}
+	return Paginate(fmt.Sprintf("🗡️ Frags on **%s** since server save:\n", world), lines)
+}
+
+// MsgPlayerFrags renders one character's frags since server save.
+func MsgPlayerFrags(c domain.FragCount) string {
+	return fmt.Sprintf("🗡️ **%s** since server save: %s", c.Name, fragLine(c))
+}
+
+func fragLine(c domain.FragCount) string {
+	return fmt.Sprintf("%d %s, %d %s (K/D %.2f)", c.Frags, plural(c.Frags, "frag", "frags"), c.Deaths, plural(c.Deaths, "death", "deaths"), c.Ratio())
+}
+
+// MsgCompare renders a level race between two characters and, when the lower
+// one is catching up, the projected day it takes the lead.
+func MsgCompare(a, b domain.LevelPace, crossover time.Time, catchingUp bool) string {
+	msg := fmt.Sprintf("⚔️ **%s** vs **%s**\n%s\n%s\n", a.Name, b.Name, levelPaceLine(a), levelPaceLine(b))
+
+	leader, trailer := a, b
+	if b.Level > a.Level {
+		leader, trailer = b, a
+	}
+	switch {
+	case leader.Level == trailer.Level:
+		msg += fmt.Sprintf("Both are level %d right now.", leader.Level)
+	case catchingUp:
+		msg += fmt.Sprintf("At this pace **%s** overtakes **%s** around <t:%d:D> (<t:%d:R>).", trailer.Name, leader.Name, crossover.Unix(), crossover.Unix())
+	default:
+		msg += fmt.Sprintf("At this pace **%s** stays ahead.", leader.Name)
+	}
+	return msg
+}
+
+// MsgPace renders a character's recent levels per day, the level it reaches
+// after days more at that pace and, when it was seen, its time online.
+func MsgPace(f domain.LevelForecast, days int) string {
+	icon := "📈"
+	if f.PerDay < 0 {
+		icon = "📉"
+	}
+	msg := fmt.Sprintf("%s **%s**: level %d, %.1f levels/day over the last %d days (recent days weigh most).\nAt this pace: level %d in %d days.",
+		icon, f.Name, f.Level, f.PerDay, f.Days, f.ProjectedLevel(days), days)
+	if f.Online >= time.Minute {
+		msg += fmt.Sprintf("\nOnline for %s in the last %d days.", hoursMinutes(f.Online), f.Days)
 	}
 	return msg
 }
+
+// MsgWarGuildNotTracked tells that /war-score only scores tracked guilds.
+func MsgWarGuildNotTracked(name string) string {
+	return fmt.Sprintf("'%s' is not a tracked guild. Add it with /add-guild first.", name)
+}
+
+func MsgNoGuildWar(name string) string {
+	return fmt.Sprintf("**%s** has not been seen at war since it was tracked.", name)
+}
+
+// MsgWarScore renders the kills between a guild and its opponent during the
+// guild's last war.
+func MsgWarScore(guildName string, score domain.WarScore) string {
+	period := fmt.Sprintf("at war since %s", RelativeTime(score.War.StartedAt))
+	if !score.War.Ongoing() {
+		period = fmt.Sprintf("war from %s to %s", RelativeTime(score.War.StartedAt), RelativeTime(score.War.EndedAt))
+	}
+	return fmt.Sprintf("⚔️ **%s** vs **%s** (%s)\nKills: **%d** · Deaths: **%d**\n_Characters count for the guild they are in now._",
+		guildName, score.Opponent, period, score.Kills, score.Deaths)
+}
+
+// hoursMinutes renders d as "12h 05m", or "45m" under an hour.
+func hoursMinutes(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+func levelPaceLine(p domain.LevelPace) string {
+	return fmt.Sprintf("**%s**: level %d, %+d in the last %d days (%.1f/day)", p.Name, p.Level, p.Gained, int(p.Window.Hours()/24), p.PerDay())
+}
+
+func MsgCooldown(until time.Time) string {
+	return fmt.Sprintf("This command is cooling down. Try again <t:%d:R>.", until.Unix())
+}
+
+// MsgAuditEntry records who ran a configuration command and with which
+// options, e.g. "📝 <@1> used /add-guild name: Red Rose".
+func MsgAuditEntry(userID, command string, options []string) string {
+	msg := fmt.Sprintf("📝 <@%s> used /%s", userID, command)
+	if len(options) > 0 {
+		msg += " " + strings.Join(options, ", ")
+	}
+	return msg
+}
+
+// MsgTrackStatus summarises a guild's tracking setup. Unset channels fall
+// back to the default channel names, and minLevel is the global minimum.
+func MsgTrackStatus(status domain.TrackStatus, minLevel int, deathChannel, levelChannel string, now time.Time) string {
+	cfg := status.Config
+
+	world := "not set"
+	if cfg.World != "" {
+		world = "**" + cfg.World + "**"
+	}
+	if status.WorldPvPType != "" {
+		world += " (" + status.WorldPvPType + ")"
+	}
+
+	msg := "**Tracking status**\n"
+	msg += fmt.Sprintf("World: %s\n", world)
+	if len(cfg.TibiaGuilds) == 0 {
+		msg += "Tibia guilds: all players\n"
+	} else {
+		msg += fmt.Sprintf("Tibia guilds: %s\n", strings.Join(cfg.TibiaGuilds, ", "))
+	}
+	msg += fmt.Sprintf("Death channel: %s\n", channelRef(cfg.DeathChannelID, deathChannel))
+	for _, route := range cfg.DeathRoutes {
+		msg += fmt.Sprintf("Deaths at level %d+: <#%s>\n", route.MinLevel, route.ChannelID)
+	}
+	msg += fmt.Sprintf("Level channel: %s\n", channelRef(cfg.LevelChannelID, levelChannel))
+	msg += fmt.Sprintf("Minimum level: %d\n", max(minLevel, cfg.MinLevel))
+	msg += fmt.Sprintf("Low-level member deaths: %s\n", onOff(cfg.LowLevelDeaths))
+	msg += fmt.Sprintf("Party share range: %s\n", onOff(cfg.ShareRange))
+	msg += fmt.Sprintf("Death location: %s\n", onOff(cfg.DeathLocation))
+	msg += fmt.Sprintf("Death messages: %s\n", verbosityName(cfg.DeathVerbosity))
+	msg += fmt.Sprintf("Level downs: %s\n", onOff(cfg.LevelDowns))
+	msg += fmt.Sprintf("Operator announcements: %s\n", onOff(!cfg.BroadcastOptOut))
+	msg += fmt.Sprintf("Command replies: %s\n", visibilityName(cfg.ResponseVisibility))
+	if alert := cfg.MassDeathAlert; alert.Enabled() {
+		msg += fmt.Sprintf("Mass death alert: %d deaths within %d minutes\n", alert.Deaths, int(alert.Window.Minutes()))
+	}
+	if cfg.MiscChannelID != "" {
+		msg += fmt.Sprintf("Misc channel: <#%s>\n", cfg.MiscChannelID)
+	}
+	if cfg.HouseChannelID != "" {
+		msg += fmt.Sprintf("House auctions: <#%s>\n", cfg.HouseChannelID)
+	}
+	if cfg.SkillChannelID != "" {
+		msg += fmt.Sprintf("Skill advances: <#%s>\n", cfg.SkillChannelID)
+		if len(cfg.WatchedPlayers) > 0 {
+			msg += fmt.Sprintf("Watched characters: %s\n", strings.Join(cfg.WatchedPlayers, ", "))
+		}
+		for _, skill := range domain.Skills {
+			if minValue, ok := cfg.SkillThresholds[skill]; ok {
+				msg += fmt.Sprintf("Minimum %s: %d\n", CatalogFor(DefaultLanguage).SkillName(skill), minValue)
+			}
+		}
+	}
+	msg += fmt.Sprintf("Language: %s\n", CatalogFor(cfg.Language).Name)
+	if cfg.Timezone != "" {
+		msg += fmt.Sprintf("Timezone: %s\n", cfg.Timezone)
+	}
+	if cfg.QuietHours.Enabled() {
+		catchUp := ""
+		if cfg.QuietHours.CatchUp {
+			catchUp = " with catch-up"
+		}
+		msg += fmt.Sprintf("Quiet hours: %s%s\n", cfg.QuietHours, catchUp)
+	}
+	if cfg.PingRoleID != "" {
+		msg += fmt.Sprintf("Ping role: %s at level %d+\n", MsgRoleMention(cfg.PingRoleID), cfg.PingMinLevel)
+	}
+	if cfg.PollInterval > 0 {
+		msg += fmt.Sprintf("Poll interval: %s\n", cfg.PollInterval)
+	}
+	if len(cfg.IgnoredPlayers) > 0 {
+		msg += fmt.Sprintf("Ignored characters: %d\n", len(cfg.IgnoredPlayers))
+	}
+	if cfg.IsMuted(now) {
+		msg += fmt.Sprintf("Muted until <t:%d:f>\n", cfg.MutedUntil.Unix())
+	}
+	if status.PendingNotifications > 0 {
+		msg += fmt.Sprintf("Notifications awaiting retry: %d\n", status.PendingNotifications)
+	}
+	if cfg.LastNotifiedAt.IsZero() {
+		msg += "Last notification: never\n"
+	} else {
+		msg += fmt.Sprintf("Last notification: <t:%d:R>\n", cfg.LastNotifiedAt.Unix())
+	}
+	return msg
+}
+
+func channelRef(channelID, defaultName string) string {
+	if channelID != "" {
+		return fmt.Sprintf("<#%s>", channelID)
+	}
+	return "#" + defaultName
+}
+
+func visibilityName(visibility domain.ResponseVisibility) string {
+	if visibility == domain.VisibilityDefault {
+		return "default"
+	}
+	return string(visibility)
+}
+
+func verbosityName(verbosity domain.DeathVerbosity) string {
+	if verbosity == domain.VerbosityNormal {
+		return "normal"
+	}
+	return string(verbosity)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// formatThousands renders n with comma thousands separators, e.g. 1,234,567.
+func formatThousands(n int64) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
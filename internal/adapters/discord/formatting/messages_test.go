@@ -1,6 +1,14 @@
 package formatting
 
-import "testing"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
 
 func TestConstants(t *testing.T) {
 	tests := []struct {
@@ -36,7 +44,7 @@ func TestConstants(t *testing.T) {
 		{
 			name:     "MsgStopSuccess",
 			constant: MsgStopSuccess,
-			expected: "Tracking stopped. Configuration removed.",
+			expected: "Tracking stopped. Configuration removed; /resume-tracking restores it within 30 days.",
 		},
 		{
 			name:     "MsgConfigError",
@@ -99,7 +107,7 @@ func TestMsgDeath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := MsgDeath(tt.charName, tt.timeStr, tt.reason)
+			result := MsgDeath(tt.charName, tt.timeStr, tt.reason, 0)
 			if result != tt.expected {
 				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
 			}
@@ -291,30 +299,203 @@ func TestMsgGuildsList(t *testing.T) {
 	tests := []struct {
 		name     string
 		guilds   []string
-		expected string
+		expected []string
 	}{
 		{
 			name:     "single guild",
 			guilds:   []string{"Red Rose"},
-			expected: "Tracking specific guilds:\n- Red Rose\n",
+			expected: []string{"Tracking specific guilds:\n- Red Rose\n"},
 		},
 		{
 			name:     "multiple guilds",
 			guilds:   []string{"Red Rose", "Eternal Flames", "Dark Side"},
-			expected: "Tracking specific guilds:\n- Red Rose\n- Eternal Flames\n- Dark Side\n",
+			expected: []string{"Tracking specific guilds:\n- Red Rose\n- Eternal Flames\n- Dark Side\n"},
 		},
 		{
 			name:     "empty list",
 			guilds:   []string{},
-			expected: "Tracking specific guilds:\n",
+			expected: []string{"Tracking specific guilds:\n"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if result := MsgGuildsList(tt.guilds); result != tt.expected {
-				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			if result := MsgGuildsList(tt.guilds); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMsgDeathsToday(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   []domain.DeathCount
+		expected []string
+	}{
+		{
+			name:     "no deaths",
+			counts:   nil,
+			expected: []string{"No deaths on **Antica** today."},
+		},
+		{
+			name:     "ranked deaths",
+			counts:   []domain.DeathCount{{Name: "Hero", Count: 3}, {Name: "Villain", Count: 1}},
+			expected: []string{"Deaths on **Antica** today:\n1. Hero - 3\n2. Villain - 1\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MsgDeathsToday("Antica", tt.counts); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
 			}
 		})
 	}
 }
+
+func TestMsgAuditEntry(t *testing.T) {
+	if got := MsgAuditEntry("1", "stop-tracking", nil); got != "📝 <@1> used /stop-tracking" {
+		t.Errorf("unexpected entry: %q", got)
+	}
+	if got := MsgAuditEntry("1", "add-guild", []string{"name: Red Rose"}); got != "📝 <@1> used /add-guild name: Red Rose" {
+		t.Errorf("unexpected entry: %q", got)
+	}
+}
+
+func TestMsgTrackStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("defaults", func(t *testing.T) {
+		result := MsgTrackStatus(domain.TrackStatus{Config: domain.GuildConfig{Language: "en"}}, 500, "death-tracker", "level-tracker", now)
+		for _, want := range []string{"World: not set", "Tibia guilds: all players", "Death channel: #death-tracker", "Level channel: #level-tracker", "Minimum level: 500", "Last notification: never"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("expected %q in:\n%s", want, result)
+			}
+		}
+		for _, unwanted := range []string{"Ping role", "Muted", "awaiting retry", "Ignored"} {
+			if strings.Contains(result, unwanted) {
+				t.Errorf("did not expect %q in:\n%s", unwanted, result)
+			}
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		status := domain.TrackStatus{
+			Config: domain.GuildConfig{
+				World:          "Antica",
+				TibiaGuilds:    []string{"Red Rose", "Blue Moon"},
+				DeathChannelID: "111",
+				PingRoleID:     "222",
+				PingMinLevel:   800,
+				PollInterval:   2 * time.Minute,
+				IgnoredPlayers: []string{"Bubble"},
+				MutedUntil:     now.Add(time.Hour),
+				LowLevelDeaths: true,
+				LastNotifiedAt: now.Add(-time.Minute),
+			},
+			PendingNotifications: 3,
+			WorldPvPType:         "Open PvP",
+		}
+		result := MsgTrackStatus(status, 500, "death-tracker", "level-tracker", now)
+		for _, want := range []string{
+			"World: **Antica** (Open PvP)",
+			"Tibia guilds: Red Rose, Blue Moon",
+			"Death channel: <#111>",
+			"Low-level member deaths: on",
+			"Ping role: <@&222> at level 800+",
+			"Poll interval: 2m0s",
+			"Ignored characters: 1",
+			fmt.Sprintf("Muted until <t:%d:f>", now.Add(time.Hour).Unix()),
+			"Notifications awaiting retry: 3",
+			fmt.Sprintf("Last notification: <t:%d:R>", now.Add(-time.Minute).Unix()),
+		} {
+			if !strings.Contains(result, want) {
+				t.Errorf("expected %q in:\n%s", want, result)
+			}
+		}
+	})
+}
+
+func TestMsgDeath_WithLevel(t *testing.T) {
+	result := MsgDeath("Hero", "2024-12-13 10:30", "Killed by a demon", 500)
+	expected := "Hero - 2024-12-13 10:30 - Killed by a demon (est. loss: 20,419,410-68,064,700 XP, 2-6 lvl)"
+	if result != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{68064700, "68,064,700"},
+		{-1234, "-1,234"},
+	}
+
+	for _, tt := range tests {
+		if result := formatThousands(tt.n); result != tt.expected {
+			t.Errorf("formatThousands(%d): expected '%s', got '%s'", tt.n, tt.expected, result)
+		}
+	}
+}
+
+func TestMsgCompare(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	hero := domain.LevelPace{Name: "Hero", Level: 300, Gained: 7, Window: week}
+	villain := domain.LevelPace{Name: "Villain", Level: 290, Gained: 21, Window: week}
+	crossover := time.Unix(1700000000, 0)
+
+	msg := MsgCompare(hero, villain, crossover, true)
+	for _, want := range []string{
+		"**Hero**: level 300, +7 in the last 7 days (1.0/day)",
+		"**Villain**: level 290, +21 in the last 7 days (3.0/day)",
+		"**Villain** overtakes **Hero** around <t:1700000000:D>",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in %q", want, msg)
+		}
+	}
+
+	if msg := MsgCompare(hero, villain, time.Time{}, false); !strings.Contains(msg, "**Hero** stays ahead") {
+		t.Errorf("expected leader to stay ahead, got %q", msg)
+	}
+	if msg := MsgCompare(hero, domain.LevelPace{Name: "Twin", Level: 300, Window: week}, time.Time{}, false); !strings.Contains(msg, "Both are level 300") {
+		t.Errorf("expected a tie, got %q", msg)
+	}
+}
+
+func TestMsgPace(t *testing.T) {
+	forecast := domain.LevelForecast{Name: "Hero", Level: 300, PerDay: 2.4, Days: 7}
+	msg := MsgPace(forecast, 30)
+	for _, want := range []string{
+		"📈 **Hero**: level 300, 2.4 levels/day over the last 7 days",
+		"level 372 in 30 days",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in %q", want, msg)
+		}
+	}
+
+	if strings.Contains(msg, "Online for") {
+		t.Errorf("expected no online time for a character never seen, got %q", msg)
+	}
+
+	losing := domain.LevelForecast{Name: "Villain", Level: 20, PerDay: -1, Days: 7}
+	if msg := MsgPace(losing, 30); !strings.HasPrefix(msg, "📉") || !strings.Contains(msg, "level 1 in 30 days") {
+		t.Errorf("expected a falling pace floored at level 1, got %q", msg)
+	}
+
+	forecast.Online = 12*time.Hour + 5*time.Minute + 30*time.Second
+	if msg := MsgPace(forecast, 30); !strings.Contains(msg, "Online for 12h 05m in the last 7 days.") {
+		t.Errorf("expected the time online, got %q", msg)
+	}
+	forecast.Online = 45 * time.Minute
+	if msg := MsgPace(forecast, 30); !strings.Contains(msg, "Online for 45m") {
+		t.Errorf("expected minutes under an hour, got %q", msg)
+	}
+}
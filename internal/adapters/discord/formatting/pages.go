@@ -0,0 +1,27 @@
+package formatting
+
+import "strings"
+
+// PageLines is how many entries a page of a list reply shows.
+const PageLines = 20
+
+// Paginate splits lines into pages of at most PageLines lines that each
+// start with header and fit in one message. Without lines it returns a
+// single page holding the header.
+func Paginate(header string, lines []string) []string {
+	var pages []string
+	var page strings.Builder
+	page.WriteString(header)
+	count := 0
+	for _, line := range lines {
+		if count == PageLines || page.Len()+len(line)+1 > maxMessageLength {
+			pages = append(pages, page.String())
+			page.Reset()
+			page.WriteString(header)
+			count = 0
+		}
+		page.WriteString(line + "\n")
+		count++
+	}
+	return append(pages, page.String())
+}
@@ -0,0 +1,30 @@
+package formatting
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	if got := Paginate("Header:\n", nil); len(got) != 1 || got[0] != "Header:\n" {
+		t.Errorf("expected one page with the header, got %q", got)
+	}
+
+	lines := make([]string, PageLines*2+1)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	pages := Paginate("Header:\n", lines)
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(pages))
+	}
+	if !strings.HasPrefix(pages[1], "Header:\nline 21\n") || pages[2] != "Header:\nline 41\n" {
+		t.Errorf("unexpected pages: %q", pages)
+	}
+
+	long := []string{strings.Repeat("a", 1500), strings.Repeat("b", 1500)}
+	if pages := Paginate("Header:\n", long); len(pages) != 2 {
+		t.Errorf("expected long lines split to fit a message, got %d pages", len(pages))
+	}
+}
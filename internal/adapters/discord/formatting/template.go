@@ -0,0 +1,115 @@
+package formatting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// MaxTemplateLength keeps rendered messages well below Discord's 2000
+// character limit once placeholders are filled in.
+const MaxTemplateLength = 300
+
+var (
+	ErrTemplateTooLong = fmt.Errorf("template is longer than %d characters", MaxTemplateLength)
+	ErrTemplateMention = errors.New("template must not mention @everyone, @here, users or roles")
+)
+
+// UnknownPlaceholderError reports a {placeholder} that is not available for
+// the template's notification type.
+type UnknownPlaceholderError struct {
+	Placeholder string
+	Allowed     []string
+}
+
+func (e *UnknownPlaceholderError) Error() string {
+	return fmt.Sprintf("unknown placeholder {%s}", e.Placeholder)
+}
+
+// templatePlaceholders lists the placeholders each notification type fills
+// in, in the order they are documented.
+var templatePlaceholders = map[domain.NotificationChannel][]string{
+	domain.ChannelDeaths: {"player", "level", "reason", "time"},
+	domain.ChannelLevels: {"player", "level", "old_level", "time"},
+	// Level downs fill in the same values as level ups.
+	domain.ChannelLevelDowns: {"player", "level", "old_level", "time"},
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{([^{}\s]*)\}`)
+	mentionPattern     = regexp.MustCompile(`@everyone|@here|<@[!&]?\d+>`)
+)
+
+// TemplatePlaceholders returns the placeholders a template for kind may use;
+// kinds without templates have none.
+func TemplatePlaceholders(kind domain.NotificationChannel) []string {
+	return templatePlaceholders[kind]
+}
+
+// ValidateTemplate checks that tmpl only uses placeholders available for kind
+// and cannot ping anyone. An empty template is valid and restores the default
+// message.
+func ValidateTemplate(kind domain.NotificationChannel, tmpl string) error {
+	allowed, ok := templatePlaceholders[kind]
+	if !ok {
+		return fmt.Errorf("notification type %s has no template", kind)
+	}
+	if len([]rune(tmpl)) > MaxTemplateLength {
+		return ErrTemplateTooLong
+	}
+	if mentionPattern.MatchString(tmpl) {
+		return ErrTemplateMention
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+		if !slices.Contains(allowed, m[1]) {
+			return &UnknownPlaceholderError{Placeholder: m[1], Allowed: allowed}
+		}
+	}
+	return nil
+}
+
+// RenderTemplate fills in the placeholders of tmpl from values in a single
+// pass, so placeholders inside values such as a death reason are left as is.
+func RenderTemplate(tmpl string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// TemplatePreview renders tmpl for kind with sample values, so admins can see
+// the result before a real notification uses it.
+func TemplatePreview(kind domain.NotificationChannel, tmpl string) string {
+	switch kind {
+	case domain.ChannelLevels:
+		return RenderTemplate(tmpl, LevelUpTemplateValues("Knight Hero", "2026-01-15 12:30", 499, 500))
+	case domain.ChannelLevelDowns:
+		return RenderTemplate(tmpl, LevelUpTemplateValues("Knight Hero", "2026-01-15 12:30", 500, 497))
+	}
+	return RenderTemplate(tmpl, DeathTemplateValues("Knight Hero", "2026-01-15 12:30", "Killed at Level 500 by a dragon lord", 500))
+}
+
+// DeathTemplateValues returns the placeholder values of a death message.
+func DeathTemplateValues(name, timeStr, reason string, level int) map[string]string {
+	return map[string]string{
+		"player": name,
+		"level":  fmt.Sprintf("%d", level),
+		"reason": reason,
+		"time":   timeStr,
+	}
+}
+
+// LevelUpTemplateValues returns the placeholder values of a level up message.
+func LevelUpTemplateValues(name, timeStr string, oldLevel, newLevel int) map[string]string {
+	return map[string]string{
+		"player":    name,
+		"level":     fmt.Sprintf("%d", newLevel),
+		"old_level": fmt.Sprintf("%d", oldLevel),
+		"time":      timeStr,
+	}
+}
@@ -0,0 +1,70 @@
+package formatting
+
+import (
+	"errors"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     domain.NotificationChannel
+		template string
+		wantErr  error
+	}{
+		{"empty restores default", domain.ChannelDeaths, "", nil},
+		{"death placeholders", domain.ChannelDeaths, "☠️ {player} ({level}) - {reason} - {time}", nil},
+		{"level placeholders", domain.ChannelLevels, "{player}: {old_level} → {level} {time}", nil},
+		{"level down placeholders", domain.ChannelLevelDowns, "📉 {player}: {old_level} → {level}", nil},
+		{"plain braces", domain.ChannelDeaths, "{player} died { sadly }", nil},
+		{"old level on deaths", domain.ChannelDeaths, "{player} {old_level}", &UnknownPlaceholderError{}},
+		{"typo", domain.ChannelLevels, "{playr} advanced", &UnknownPlaceholderError{}},
+		{"everyone ping", domain.ChannelDeaths, "@everyone {player} died", ErrTemplateMention},
+		{"role ping", domain.ChannelDeaths, "<@&123> {player} died", ErrTemplateMention},
+		{"too long", domain.ChannelDeaths, string(make([]rune, MaxTemplateLength+1)), ErrTemplateTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(tt.kind, tt.template)
+			var unknown *UnknownPlaceholderError
+			switch {
+			case tt.wantErr == nil:
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			case errors.As(tt.wantErr, &unknown):
+				if !errors.As(err, &unknown) {
+					t.Errorf("expected unknown placeholder, got %v", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := ValidateTemplate(domain.ChannelMisc, "{player}"); err == nil {
+		t.Error("expected an error for a notification type without templates")
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	values := DeathTemplateValues("Hero", "12:30", "Killed by a {player} mimic", 500)
+	got := RenderTemplate("{player} ({level}) - {reason} at {time}", values)
+
+	want := "Hero (500) - Killed by a {player} mimic at 12:30"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMsgTemplateInvalid(t *testing.T) {
+	err := ValidateTemplate(domain.ChannelLevels, "{reason}")
+
+	want := "Unknown placeholder `{reason}`. Available: `{player}`, `{level}`, `{old_level}`, `{time}`."
+	if got := MsgTemplateInvalid(err); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,21 @@
+package formatting
+
+import (
+	"fmt"
+	"time"
+)
+
+// RelativeTime renders t with Discord timestamp markup, which every reader
+// sees localized and kept up to date, e.g. "5 minutes ago".
+func RelativeTime(t time.Time) string {
+	return fmt.Sprintf("<t:%d:R>", t.Unix())
+}
+
+// EventTime renders when a death or level up happened. With markup it is a
+// RelativeTime; otherwise it is plain text in DcLongTimeFormat in loc.
+func EventTime(t time.Time, loc *time.Location, markup bool) string {
+	if markup {
+		return RelativeTime(t)
+	}
+	return t.In(loc).Format(DcLongTimeFormat)
+}
@@ -0,0 +1,21 @@
+package formatting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventTime(t *testing.T) {
+	at := time.Date(2026, 1, 15, 12, 30, 0, 0, time.UTC)
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+
+	if got, want := EventTime(at, warsaw, true), "<t:1768480200:R>"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := EventTime(at, warsaw, false), "2026-01-15 13:30"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ErrThreadLocked means a notification thread was archived and locked by a
+// moderator, so the bot cannot reopen it.
+var ErrThreadLocked = errors.New("thread is locked")
+
+// postToThreadOrChannel runs post and, when channelID is an archived thread,
+// unarchives the thread and runs post once more. Plain channels never hit the
+// retry.
+func (a *Adapter) postToThreadOrChannel(channelID string, post func() error) error {
+	err := post()
+	if !isRESTError(err, discordgo.ErrCodePerformedOperationOnArchivedThread) {
+		return err
+	}
+
+	slog.Info("Unarchiving notification thread", "channel_id", channelID)
+	archived := false
+	if _, uerr := a.session.ChannelEditComplex(channelID, &discordgo.ChannelEdit{Archived: &archived}); uerr != nil {
+		if isRESTError(uerr, discordgo.ErrCodeThreadIsLocked) {
+			return fmt.Errorf("thread %s: %w", channelID, ErrThreadLocked)
+		}
+		return fmt.Errorf("unarchive thread %s: %w", channelID, uerr)
+	}
+	return post()
+}
+
+func isRESTError(err error, code int) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == code
+}
@@ -0,0 +1,87 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func restError(code int) error {
+	return &discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"},
+		Message:  &discordgo.APIErrorMessage{Code: code},
+	}
+}
+
+func TestAdapter_UnarchivesThreadBeforePosting(t *testing.T) {
+	archived := true
+	var sent int
+
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			if archived {
+				return nil, restError(discordgo.ErrCodePerformedOperationOnArchivedThread)
+			}
+			sent++
+			return &discordgo.Message{ID: "msg-123"}, nil
+		},
+		channelEditComplexFunc: func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			if channelID != "thread-1" || data.Archived == nil || *data.Archived {
+				t.Errorf("unexpected edit of %s: %+v", channelID, data)
+			}
+			archived = false
+			return &discordgo.Channel{ID: channelID}, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", LevelChannelID: "thread-1"}
+	if err := adapter.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 100, NewLevel: 101}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sent != 1 {
+		t.Errorf("Expected 1 message after unarchiving, got %d", sent)
+	}
+}
+
+func TestAdapter_LockedThread(t *testing.T) {
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return nil, restError(discordgo.ErrCodePerformedOperationOnArchivedThread)
+		},
+		channelEditComplexFunc: func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			return nil, restError(discordgo.ErrCodeThreadIsLocked)
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "thread-1"}
+	err := adapter.SendDeathStreakNotification(guild, "Hero", 3)
+	if !errors.Is(err, ErrThreadLocked) {
+		t.Errorf("Expected ErrThreadLocked, got %v", err)
+	}
+}
+
+func TestAdapter_ChannelErrorsSkipUnarchive(t *testing.T) {
+	sendErr := restError(discordgo.ErrCodeMissingAccess)
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return nil, sendErr
+		},
+		channelEditComplexFunc: func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			t.Error("expected no unarchive for a non-thread error")
+			return nil, nil
+		},
+	}
+
+	adapter := NewAdapter(session, testConfig)
+	guild := domain.GuildConfig{DiscordGuildID: "guild-1", DeathChannelID: "channel-1"}
+	if err := adapter.SendDeathStreakNotification(guild, "Hero", 3); !errors.Is(err, sendErr) {
+		t.Errorf("Expected the send error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// playerThrottle remembers when a player was last announced in a guild so
+// level ups reported twice in quick succession are only posted once.
+type playerThrottle struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	sent     map[string]time.Time
+	now      func() time.Time
+}
+
+func newPlayerThrottle(cooldown time.Duration) *playerThrottle {
+	return &playerThrottle{
+		cooldown: cooldown,
+		sent:     make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Throttled reports whether player was announced in guildID less than the
+// cooldown ago. A zero cooldown never throttles.
+func (t *playerThrottle) Throttled(guildID, player string) bool {
+	if t.cooldown <= 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last, ok := t.sent[t.key(guildID, player)]
+	return ok && t.now().Sub(last) < t.cooldown
+}
+
+// Record starts the cooldown of player in guildID and drops expired entries.
+func (t *playerThrottle) Record(guildID, player string) {
+	if t.cooldown <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for key, last := range t.sent {
+		if now.Sub(last) >= t.cooldown {
+			delete(t.sent, key)
+		}
+	}
+	t.sent[t.key(guildID, player)] = now
+}
+
+func (t *playerThrottle) key(guildID, player string) string {
+	return guildID + ":" + strings.ToLower(player)
+}
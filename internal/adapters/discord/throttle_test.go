@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPlayerThrottle(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttle := newPlayerThrottle(5 * time.Minute)
+	throttle.now = func() time.Time { return now }
+
+	if throttle.Throttled("g1", "Hero") {
+		t.Fatal("expected first message to pass")
+	}
+	throttle.Record("g1", "Hero")
+
+	if !throttle.Throttled("g1", "hero") {
+		t.Error("expected player to be throttled regardless of case")
+	}
+	if throttle.Throttled("g2", "Hero") {
+		t.Error("expected other guilds to be independent")
+	}
+
+	now = now.Add(5 * time.Minute)
+	if throttle.Throttled("g1", "Hero") {
+		t.Error("expected cooldown to expire")
+	}
+	throttle.Record("g1", "Other")
+	if len(throttle.sent) != 1 {
+		t.Errorf("expected expired entries to be pruned, got %d", len(throttle.sent))
+	}
+}
+
+func TestPlayerThrottle_Disabled(t *testing.T) {
+	throttle := newPlayerThrottle(0)
+	throttle.Record("g1", "Hero")
+	if throttle.Throttled("g1", "Hero") {
+		t.Error("expected zero cooldown to never throttle")
+	}
+}
+
+func TestAdapter_SendLevelUpNotification_Throttled(t *testing.T) {
+	var sent int
+	session := &mockDiscordSession{
+		channelMessageSendFunc: func(channelID, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent++
+			return &discordgo.Message{}, nil
+		},
+	}
+	cfg := &config.Config{DiscordChannelLevel: "level-tracker", LevelUpCooldown: 5 * time.Minute}
+	adapter := NewAdapter(session, cfg)
+	guild := domain.GuildConfig{DiscordGuildID: "g1", LevelChannelID: "level-1"}
+
+	for level := 101; level <= 103; level++ {
+		levelUp := domain.LevelUp{PlayerName: "Hero", OldLevel: level - 1, NewLevel: level}
+		if err := adapter.SendLevelUpNotification(guild, levelUp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 message within the cooldown, got %d", sent)
+	}
+
+	if err := adapter.SendDeathNotification(domain.GuildConfig{DiscordGuildID: "g1", DeathChannelID: "death-1"}, domain.Player{Name: "Hero"}, domain.Kill{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("expected deaths to bypass the cooldown, got %d messages", sent)
+	}
+}
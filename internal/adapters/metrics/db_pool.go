@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBPoolStats is a point-in-time snapshot of the database connection pool.
+type DBPoolStats struct {
+	AcquiredConns        int32
+	IdleConns            int32
+	TotalConns           int32
+	MaxConns             int32
+	AcquireCount         int64
+	EmptyAcquireCount    int64
+	AcquireDuration      time.Duration
+	EmptyAcquireWaitTime time.Duration
+}
+
+var (
+	dbPoolAcquiredDesc = prometheus.NewDesc("db_pool_acquired_conns", "Connections currently checked out of the pool", nil, nil)
+	dbPoolIdleDesc     = prometheus.NewDesc("db_pool_idle_conns", "Idle connections in the pool", nil, nil)
+	dbPoolTotalDesc    = prometheus.NewDesc("db_pool_total_conns", "Total connections in the pool", nil, nil)
+	dbPoolMaxDesc      = prometheus.NewDesc("db_pool_max_conns", "Configured maximum pool size", nil, nil)
+	dbPoolAcquiresDesc = prometheus.NewDesc("db_pool_acquires_total", "Successful connection acquires", nil, nil)
+	dbPoolWaitsDesc    = prometheus.NewDesc("db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty", nil, nil)
+	dbPoolAcquireDesc  = prometheus.NewDesc("db_pool_acquire_seconds_total", "Cumulative time spent acquiring connections", nil, nil)
+	dbPoolWaitDesc     = prometheus.NewDesc("db_pool_wait_seconds_total", "Cumulative time spent waiting on an empty pool", nil, nil)
+)
+
+type dbPoolCollector struct {
+	stats func() DBPoolStats
+}
+
+// RegisterDBPool exposes pool stats on /metrics, read from stats on every
+// scrape.
+func RegisterDBPool(stats func() DBPoolStats) error {
+	return prometheus.Register(&dbPoolCollector{stats: stats})
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbPoolAcquiredDesc
+	ch <- dbPoolIdleDesc
+	ch <- dbPoolTotalDesc
+	ch <- dbPoolMaxDesc
+	ch <- dbPoolAcquiresDesc
+	ch <- dbPoolWaitsDesc
+	ch <- dbPoolAcquireDesc
+	ch <- dbPoolWaitDesc
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns))
+	ch <- prometheus.MustNewConstMetric(dbPoolIdleDesc, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(dbPoolTotalDesc, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(dbPoolMaxDesc, prometheus.GaugeValue, float64(s.MaxConns))
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquiresDesc, prometheus.CounterValue, float64(s.AcquireCount))
+	ch <- prometheus.MustNewConstMetric(dbPoolWaitsDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount))
+	ch <- prometheus.MustNewConstMetric(dbPoolAcquireDesc, prometheus.CounterValue, s.AcquireDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(dbPoolWaitDesc, prometheus.CounterValue, s.EmptyAcquireWaitTime.Seconds())
+}
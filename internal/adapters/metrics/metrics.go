@@ -16,6 +16,32 @@ var (
 		Help: "The total number of tracked level ups",
 	})
 
+	TrackerLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "death_tracker_leader",
+		Help: "1 if this instance holds the tracker leader lock, 0 if it is on standby",
+	})
+
+	TrackerCycleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tracker_cycle_duration_seconds",
+		Help:    "Duration of a tracking cycle for one world",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200},
+	}, []string{"world"})
+
+	TrackerCyclesSkipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tracker_cycles_skipped_total",
+		Help: "Tracking cycles skipped because the world's previous cycle was still running",
+	}, []string{"world"})
+
+	TrackerWorldPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tracker_world_panics_total",
+		Help: "World processing cycles that panicked and were recovered",
+	}, []string{"world"})
+
+	TrackerWorldTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tracker_world_timeouts_total",
+		Help: "World processing cycles cut short by WORLD_CYCLE_TIMEOUT",
+	}, []string{"world"})
+
 	TibiaDataRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "tibiadata_request_duration_seconds",
 		Help:    "Duration of TibiaData API requests",
@@ -27,6 +53,11 @@ var (
 		Help: "Total number of TibiaData API requests",
 	}, []string{"endpoint", "status"})
 
+	CharacterCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tibiadata_character_cache_total",
+		Help: "Character detail cache lookups by result",
+	}, []string{"result"})
+
 	TibiaComRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "tibiacom_request_duration_seconds",
 		Help:    "Duration of Tibia.com HTML scraping requests",
@@ -38,8 +69,35 @@ var (
 		Help: "Total number of Tibia.com HTML scraping requests",
 	}, []string{"status"})
 
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database queries by sqlc query name",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query", "status"})
+
+	DBSlowQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "Database queries slower than DB_SLOW_QUERY_THRESHOLD",
+	}, []string{"query"})
+
 	DiscordMessagesSent = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "discord_messages_sent_total",
 		Help: "Total number of Discord messages sent",
 	}, []string{"channel_type", "status"})
+
+	NotificationRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "discord_notification_retries_total",
+		Help: "Failed Discord notifications by retry outcome",
+	}, []string{"status"})
+
+	CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "discord_command_duration_seconds",
+		Help:    "Duration of slash command and autocomplete handlers",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command"})
+
+	CommandPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "discord_command_panics_total",
+		Help: "Slash command handlers that panicked",
+	}, []string{"command"})
 )
@@ -0,0 +1,1433 @@
+// Package memory keeps everything the bot stores in process memory, so it can
+// run without a database for demos and tests. Nothing survives a restart and
+// several replicas cannot share it.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// Store implements ports.Repository with the same semantics as the Postgres
+// store. It is safe for concurrent use.
+type Store struct {
+	mu  sync.RWMutex
+	now func() time.Time
+
+	guilds   map[string]*guildRecord
+	stopped  map[string]domain.StoppedGuildConfig
+	players  map[string]playerRecord
+	deaths   []deathRecord
+	levelUps []domain.LevelUpRecord
+	// presence holds the characters seen online by world and hour.
+	presence      map[string]map[time.Time]map[string]bool
+	onlineCounts  []onlineCountRecord
+	hourlyCounts  []hourlyCountRecord
+	sessions      []sessionRecord
+	houseAuctions map[string]map[int]auctionRecord
+	// playerSkills holds skill values by world, skill and character.
+	playerSkills map[string]map[domain.Skill]map[string]int
+	guildMembers map[string]map[string]bool
+	memberCache  map[string]domain.GuildMemberList
+	// wars holds the wars of each Tibia guild, oldest first.
+	wars          map[string][]domain.GuildWar
+	notifications []domain.FailedNotification
+	nextID        int64
+	// nextEventID numbers deaths and level ups for paging.
+	nextEventID int64
+}
+
+type guildRecord struct {
+	config    domain.GuildConfig
+	removedAt time.Time
+}
+
+type playerRecord struct {
+	level     int
+	world     string
+	updatedAt time.Time
+}
+
+type deathRecord struct {
+	id      int64
+	name    string
+	world   string
+	level   int
+	reason  string
+	diedAt  time.Time
+	killers []string
+}
+
+type onlineCountRecord struct {
+	world     string
+	sampledAt time.Time
+	players   int
+}
+
+type hourlyCountRecord struct {
+	world      string
+	hour       time.Time
+	avgPlayers float64
+	maxPlayers int
+}
+
+type sessionRecord struct {
+	world      string
+	name       string
+	startedAt  time.Time
+	lastSeenAt time.Time
+}
+
+type auctionRecord struct {
+	auction domain.HouseAuction
+	seenAt  time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		now:           time.Now,
+		guilds:        make(map[string]*guildRecord),
+		stopped:       make(map[string]domain.StoppedGuildConfig),
+		players:       make(map[string]playerRecord),
+		presence:      make(map[string]map[time.Time]map[string]bool),
+		houseAuctions: make(map[string]map[int]auctionRecord),
+		playerSkills:  make(map[string]map[domain.Skill]map[string]int),
+		guildMembers:  make(map[string]map[string]bool),
+		memberCache:   make(map[string]domain.GuildMemberList),
+		wars:          make(map[string][]domain.GuildWar),
+	}
+}
+
+func (s *Store) Close() {}
+
+// -- Guild Configuration Methods --
+
+// guild returns the guild's record, creating one with the schema defaults
+// when it does not exist. Callers hold the write lock.
+func (s *Store) guild(guildID string) *guildRecord {
+	g, ok := s.guilds[guildID]
+	if !ok {
+		g = &guildRecord{config: domain.GuildConfig{
+			DiscordGuildID: guildID,
+			Language:       "en",
+			LowLevelDeaths: true,
+		}}
+		s.guilds[guildID] = g
+	}
+	return g
+}
+
+// update applies fn to the guild, creating it first like an upsert.
+func (s *Store) update(guildID string, fn func(cfg *domain.GuildConfig)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.guild(guildID).config)
+	return nil
+}
+
+// updateExisting applies fn only when the guild exists, like an UPDATE.
+func (s *Store) updateExisting(guildID string, fn func(cfg *domain.GuildConfig)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guilds[guildID]; ok {
+		fn(&g.config)
+	}
+	return nil
+}
+
+func (s *Store) SaveGuildWorld(ctx context.Context, guildID, world string) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.World = world })
+}
+
+// GetGuildConfig returns nil when the guild has never been configured.
+// Removed guilds are still returned, as with Postgres.
+func (s *Store) GetGuildConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return nil, nil
+	}
+	cfg := cloneConfig(g.config)
+	return &cfg, nil
+}
+
+func (s *Store) GetAllGuildConfigs(ctx context.Context) ([]domain.GuildConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]domain.GuildConfig, 0, len(s.guilds))
+	for _, g := range s.guilds {
+		if g.removedAt.IsZero() {
+			result = append(result, cloneConfig(g.config))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DiscordGuildID < result[j].DiscordGuildID })
+	return result, nil
+}
+
+func (s *Store) DeleteGuildConfig(ctx context.Context, guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.guilds, guildID)
+	return nil
+}
+
+func (s *Store) AddGuildToConfig(ctx context.Context, guildID, tibiaGuild string) error {
+	tibiaGuild = domain.NormalizeName(tibiaGuild)
+	return s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		if !slices.Contains(cfg.TibiaGuilds, tibiaGuild) {
+			cfg.TibiaGuilds = append(cfg.TibiaGuilds, tibiaGuild)
+		}
+	})
+}
+
+func (s *Store) RemoveGuildFromConfig(ctx context.Context, guildID, tibiaGuild string) error {
+	tibiaGuild = domain.NormalizeName(tibiaGuild)
+	return s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		cfg.TibiaGuilds = slices.DeleteFunc(cfg.TibiaGuilds, func(g string) bool { return g == tibiaGuild })
+	})
+}
+
+func (s *Store) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.Language = language })
+}
+
+func (s *Store) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	var field func(cfg *domain.GuildConfig) *string
+	switch kind {
+	case domain.ChannelDeaths:
+		field = func(cfg *domain.GuildConfig) *string { return &cfg.DeathChannelID }
+	case domain.ChannelLevels:
+		field = func(cfg *domain.GuildConfig) *string { return &cfg.LevelChannelID }
+	case domain.ChannelHouses:
+		field = func(cfg *domain.GuildConfig) *string { return &cfg.HouseChannelID }
+	case domain.ChannelMisc:
+		field = func(cfg *domain.GuildConfig) *string { return &cfg.MiscChannelID }
+	case domain.ChannelSkills:
+		field = func(cfg *domain.GuildConfig) *string { return &cfg.SkillChannelID }
+	default:
+		return fmt.Errorf("unknown notification channel: %s", kind)
+	}
+	return s.update(guildID, func(cfg *domain.GuildConfig) { *field(cfg) = channelID })
+}
+
+func (s *Store) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) {
+		cfg.PingRoleID = roleID
+		cfg.PingMinLevel = minLevel
+	})
+}
+
+func (s *Store) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.PollInterval = interval.Truncate(time.Second) })
+}
+
+func (s *Store) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.MutedUntil = until })
+}
+
+func (s *Store) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.LowLevelDeaths = enabled })
+}
+
+func (s *Store) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.ShareRange = enabled })
+}
+
+func (s *Store) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.DeathLocation = enabled })
+}
+
+func (s *Store) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.LevelDowns = enabled })
+}
+
+func (s *Store) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.DeathVerbosity = verbosity })
+}
+
+func (s *Store) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.ResponseVisibility = visibility })
+}
+
+func (s *Store) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.MassDeathAlert = alert })
+}
+
+func (s *Store) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.BroadcastOptOut = optOut })
+}
+
+func (s *Store) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.MinLevel = level })
+}
+
+func (s *Store) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.Timezone = timezone })
+}
+
+func (s *Store) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	switch kind {
+	case domain.ChannelDeaths:
+		return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.DeathTemplate = template })
+	case domain.ChannelLevels:
+		return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.LevelTemplate = template })
+	case domain.ChannelLevelDowns:
+		return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.LevelDownTemplate = template })
+	default:
+		return fmt.Errorf("no template for notification channel: %s", kind)
+	}
+}
+
+func (s *Store) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	switch kind {
+	case domain.ChannelDeaths:
+		return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.DeathEmoji, cfg.DeathReaction = emoji, react })
+	case domain.ChannelLevels:
+		return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.LevelEmoji, cfg.LevelReaction = emoji, react })
+	default:
+		return fmt.Errorf("no emoji for notification channel: %s", kind)
+	}
+}
+
+func (s *Store) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) {
+		cfg.DeathRoutes = slices.DeleteFunc(cfg.DeathRoutes, func(r domain.DeathRoute) bool { return r.MinLevel == minLevel })
+		cfg.DeathRoutes = append(cfg.DeathRoutes, domain.DeathRoute{MinLevel: minLevel, ChannelID: channelID})
+		slices.SortFunc(cfg.DeathRoutes, func(a, b domain.DeathRoute) int { return a.MinLevel - b.MinLevel })
+	})
+}
+
+func (s *Store) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	deleted := false
+	err := s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		before := len(cfg.DeathRoutes)
+		cfg.DeathRoutes = slices.DeleteFunc(cfg.DeathRoutes, func(r domain.DeathRoute) bool { return r.MinLevel == minLevel })
+		deleted = len(cfg.DeathRoutes) < before
+	})
+	return deleted, err
+}
+
+func (s *Store) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) {
+		if cfg.SkillThresholds == nil {
+			cfg.SkillThresholds = make(map[domain.Skill]int)
+		}
+		cfg.SkillThresholds[skill] = minValue
+	})
+}
+
+func (s *Store) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	deleted := false
+	err := s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		_, deleted = cfg.SkillThresholds[skill]
+		delete(cfg.SkillThresholds, skill)
+	})
+	return deleted, err
+}
+
+func (s *Store) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.QuietHours = quiet })
+}
+
+func (s *Store) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.Quota = quota })
+}
+
+func (s *Store) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	return s.update(guildID, func(cfg *domain.GuildConfig) { cfg.Premium = premium })
+}
+
+func (s *Store) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	return s.updateExisting(guildID, func(cfg *domain.GuildConfig) { cfg.LastNotifiedAt = at })
+}
+
+func (s *Store) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.guilds[guildID]; ok && g.removedAt.IsZero() {
+		g.removedAt = at
+	}
+	return nil
+}
+
+func (s *Store) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.guilds[guildID]
+	if !ok || g.removedAt.IsZero() {
+		return false, nil
+	}
+	g.removedAt = time.Time{}
+	return true, nil
+}
+
+func (s *Store) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for id, g := range s.guilds {
+		if !g.removedAt.IsZero() && g.removedAt.Before(removedBefore) {
+			delete(s.guilds, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped[stopped.DiscordGuildID] = stopped
+	return nil
+}
+
+func (s *Store) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stopped, ok := s.stopped[guildID]
+	if !ok {
+		return nil, nil
+	}
+	return &stopped, nil
+}
+
+func (s *Store) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.stopped, guildID)
+	return nil
+}
+
+func (s *Store) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for id, stopped := range s.stopped {
+		if stopped.StoppedAt.Before(stoppedBefore) {
+			delete(s.stopped, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// PurgeGuildData deletes everything stored for a Discord guild. Members,
+// deaths, players and level ups are only deleted when no other guild still
+// tracks the same Tibia guilds or world.
+func (s *Store) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result domain.PurgeResult
+	before := len(s.notifications)
+	s.notifications = slices.DeleteFunc(s.notifications, func(n domain.FailedNotification) bool {
+		return n.DiscordGuildID == guildID
+	})
+	result.Notifications = int64(before - len(s.notifications))
+
+	if _, ok := s.stopped[guildID]; ok {
+		delete(s.stopped, guildID)
+		result.Configs++
+	}
+
+	g, ok := s.guilds[guildID]
+	if !ok {
+		return result, nil
+	}
+	delete(s.guilds, guildID)
+	result.Configs++
+
+	for _, tibiaGuild := range g.config.TibiaGuilds {
+		if s.tibiaGuildTracked(tibiaGuild) {
+			continue
+		}
+		result.GuildMembers += int64(len(s.guildMembers[tibiaGuild]))
+		delete(s.guildMembers, tibiaGuild)
+	}
+
+	world := g.config.World
+	if s.worldTracked(world) {
+		return result, nil
+	}
+	before = len(s.deaths)
+	s.deaths = slices.DeleteFunc(s.deaths, func(d deathRecord) bool { return d.world == world })
+	result.Deaths = int64(before - len(s.deaths))
+	for name, p := range s.players {
+		if p.world == world {
+			delete(s.players, name)
+			result.Players++
+		}
+	}
+	before = len(s.levelUps)
+	s.levelUps = slices.DeleteFunc(s.levelUps, func(l domain.LevelUpRecord) bool { return l.World == world })
+	result.LevelUps = int64(before - len(s.levelUps))
+	delete(s.playerSkills, world)
+	return result, nil
+}
+
+func (s *Store) tibiaGuildTracked(tibiaGuild string) bool {
+	for _, g := range s.guilds {
+		if slices.Contains(g.config.TibiaGuilds, tibiaGuild) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) worldTracked(world string) bool {
+	for _, g := range s.guilds {
+		if g.config.World == world {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	name = domain.NormalizeName(name)
+	return s.update(guildID, func(cfg *domain.GuildConfig) {
+		if !slices.ContainsFunc(cfg.IgnoredPlayers, func(p string) bool { return domain.SameName(p, name) }) {
+			cfg.IgnoredPlayers = append(cfg.IgnoredPlayers, name)
+		}
+	})
+}
+
+func (s *Store) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	name = domain.NormalizeName(name)
+	return s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		cfg.IgnoredPlayers = slices.DeleteFunc(cfg.IgnoredPlayers, func(p string) bool { return domain.SameName(p, name) })
+	})
+}
+
+func (s *Store) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	name = domain.NormalizeName(name)
+	return s.update(guildID, func(cfg *domain.GuildConfig) {
+		if !slices.ContainsFunc(cfg.WatchedPlayers, func(p string) bool { return domain.SameName(p, name) }) {
+			cfg.WatchedPlayers = append(cfg.WatchedPlayers, name)
+		}
+	})
+}
+
+func (s *Store) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	name = domain.NormalizeName(name)
+	return s.updateExisting(guildID, func(cfg *domain.GuildConfig) {
+		cfg.WatchedPlayers = slices.DeleteFunc(cfg.WatchedPlayers, func(p string) bool { return domain.SameName(p, name) })
+	})
+}
+
+// -- Player & Level Management Methods --
+
+func (s *Store) UpsertPlayerLevel(ctx context.Context, name string, level int, world string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players[domain.NormalizeName(name)] = playerRecord{level: level, world: world, updatedAt: s.now()}
+	return nil
+}
+
+// RenamePlayer moves everything stored under oldName to newName. Records
+// already stored under newName win over their oldName duplicates.
+func (s *Store) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	oldName, newName = domain.NormalizeName(oldName), domain.NormalizeName(newName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.players[oldName]; ok {
+		if _, exists := s.players[newName]; !exists {
+			s.players[newName] = playerRecord{level: p.level, world: p.world, updatedAt: s.now()}
+		}
+		delete(s.players, oldName)
+	}
+
+	recorded := make(map[time.Time]bool)
+	for _, d := range s.deaths {
+		if d.name == newName {
+			recorded[d.diedAt.UTC()] = true
+		}
+	}
+	s.deaths = slices.DeleteFunc(s.deaths, func(d deathRecord) bool {
+		return d.name == oldName && recorded[d.diedAt.UTC()]
+	})
+	for i := range s.deaths {
+		if s.deaths[i].name == oldName {
+			s.deaths[i].name = newName
+		}
+	}
+	for i := range s.levelUps {
+		if s.levelUps[i].Name == oldName {
+			s.levelUps[i].Name = newName
+		}
+	}
+	for _, members := range s.guildMembers {
+		if members[oldName] {
+			delete(members, oldName)
+			members[newName] = true
+		}
+	}
+	for _, g := range s.guilds {
+		if i := slices.Index(g.config.IgnoredPlayers, oldName); i >= 0 {
+			g.config.IgnoredPlayers[i] = newName
+		}
+		if i := slices.Index(g.config.WatchedPlayers, oldName); i >= 0 {
+			g.config.WatchedPlayers[i] = newName
+		}
+	}
+	return nil
+}
+
+// BatchUpsertPlayerLevels writes all levels at once. Duplicate names keep the
+// last entry.
+func (s *Store) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, l := range levels {
+		s.players[domain.NormalizeName(l.Name)] = playerRecord{level: l.Level, world: l.World, updatedAt: now}
+	}
+	return nil
+}
+
+func (s *Store) GetPlayersLevels(ctx context.Context, world string) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]int)
+	for name, p := range s.players {
+		if p.world == world {
+			result[name] = p.level
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	prefix = strings.ToLower(domain.NormalizeName(prefix))
+	s.mu.RLock()
+	var names []string
+	for name, p := range s.players {
+		if p.world == world && strings.HasPrefix(strings.ToLower(name), prefix) {
+			names = append(names, name)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	if len(names) > domain.MaxNameSuggestions {
+		names = names[:domain.MaxNameSuggestions]
+	}
+	return names, nil
+}
+
+func (s *Store) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.playerSkills[world][skill]), nil
+}
+
+func (s *Store) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.playerSkills[world] == nil {
+		s.playerSkills[world] = make(map[domain.Skill]map[string]int)
+	}
+	if s.playerSkills[world][skill] == nil {
+		s.playerSkills[world][skill] = make(map[string]int)
+	}
+	maps.Copy(s.playerSkills[world][skill], values)
+	return nil
+}
+
+func (s *Store) BatchTouchPlayers(ctx context.Context, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, name := range names {
+		if p, ok := s.players[name]; ok {
+			p.updatedAt = now
+			s.players[name] = p
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteOldPlayers(ctx context.Context, world string, threshold time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := s.now().Add(-threshold)
+	var deleted int64
+	for name, p := range s.players {
+		if p.world == world && p.updatedAt.Before(cutoff) {
+			delete(s.players, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) GetOfflinePlayers(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	online := make(map[string]bool, len(onlineNames))
+	for _, name := range onlineNames {
+		online[name] = true
+	}
+
+	var result []domain.Player
+	for name, p := range s.players {
+		if p.world == world && !online[name] {
+			result = append(result, domain.Player{Name: name, Level: p.level, World: world})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// -- Death Log Methods --
+
+// RecordDeath ignores a death already recorded for the same character and
+// time.
+func (s *Store) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	name = domain.NormalizeName(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deaths {
+		if d.name == name && d.diedAt.Equal(kill.Time) {
+			return nil
+		}
+	}
+	s.nextEventID++
+	s.deaths = append(s.deaths, deathRecord{
+		id:      s.nextEventID,
+		name:    name,
+		world:   world,
+		level:   kill.Level,
+		reason:  kill.Reason,
+		diedAt:  kill.Time,
+		killers: kill.PlayerKillers(),
+	})
+	return nil
+}
+
+func (s *Store) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, d := range s.deaths {
+		if d.world != world || d.diedAt.Before(since) || !d.diedAt.Before(until) || !slices.Contains(victims, d.name) {
+			continue
+		}
+		if slices.ContainsFunc(d.killers, func(k string) bool { return slices.Contains(killers, k) }) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Store) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	name = domain.NormalizeName(name)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, d := range s.deaths {
+		if d.name == name && !d.diedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetDeathCountsSince ranks the 25 characters of world that died most often.
+func (s *Store) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, d := range s.deaths {
+		if d.world == world && !d.diedAt.Before(since) {
+			counts[d.name]++
+		}
+	}
+
+	result := make([]domain.DeathCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, domain.DeathCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return truncate(result, 25), nil
+}
+
+// GetTopKillersSince ranks the 10 player killers of world. With guildNames,
+// only deaths of their members count and members killing each other are
+// left out.
+func (s *Store) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := s.membersOf(guildNames)
+
+	kills := make(map[string]int)
+	for _, d := range s.deaths {
+		if d.world != world || d.diedAt.Before(since) {
+			continue
+		}
+		if len(guildNames) > 0 && !members[d.name] {
+			continue
+		}
+		for _, killer := range d.killers {
+			if !members[killer] {
+				kills[killer]++
+			}
+		}
+	}
+
+	result := make([]domain.KillerCount, 0, len(kills))
+	for name, count := range kills {
+		result = append(result, domain.KillerCount{Name: name, Kills: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kills != result[j].Kills {
+			return result[i].Kills > result[j].Kills
+		}
+		return result[i].Name < result[j].Name
+	})
+	return truncate(result, 10), nil
+}
+
+func (s *Store) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := s.membersOf(guildNames)
+	counts := make(map[string]*domain.FragCount)
+	count := func(name string) *domain.FragCount {
+		c, ok := counts[name]
+		if !ok {
+			c = &domain.FragCount{Name: name}
+			counts[name] = c
+		}
+		return c
+	}
+	for _, d := range s.deaths {
+		if d.world != world || d.diedAt.Before(since) {
+			continue
+		}
+		if len(d.killers) > 0 {
+			count(d.name).Deaths++
+		}
+		if members[d.name] {
+			continue
+		}
+		for _, killer := range d.killers {
+			count(killer).Frags++
+		}
+	}
+
+	var result []domain.FragCount
+	for _, c := range counts {
+		if name != "" && !strings.EqualFold(c.Name, domain.NormalizeName(name)) {
+			continue
+		}
+		if members[c.Name] || len(guildNames) == 0 && (c.Frags > 0 || name != "") {
+			result = append(result, *c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Frags != result[j].Frags {
+			return result[i].Frags > result[j].Frags
+		}
+		if result[i].Deaths != result[j].Deaths {
+			return result[i].Deaths < result[j].Deaths
+		}
+		return result[i].Name < result[j].Name
+	})
+	return truncate(result, 25), nil
+}
+
+// GetDeathsPage relies on deaths being appended in ID order.
+func (s *Store) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := s.membersOf(guildNames)
+	var result []domain.DeathRecord
+	for _, d := range s.deaths {
+		if len(result) == limit {
+			break
+		}
+		if d.world != world || d.diedAt.Before(since) || d.id <= afterID {
+			continue
+		}
+		if len(guildNames) > 0 && !members[d.name] {
+			continue
+		}
+		result = append(result, domain.DeathRecord{
+			ID:      d.id,
+			Name:    d.name,
+			World:   d.world,
+			Level:   d.level,
+			Reason:  d.reason,
+			Killers: slices.Clone(d.killers),
+			DiedAt:  d.diedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.deaths)
+	s.deaths = slices.DeleteFunc(s.deaths, func(d deathRecord) bool { return d.diedAt.Before(diedBefore) })
+	return int64(before - len(s.deaths)), nil
+}
+
+func (s *Store) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hours := s.presence[world]
+	if hours == nil {
+		hours = make(map[time.Time]map[string]bool)
+		s.presence[world] = hours
+	}
+	hour := at.Truncate(time.Hour).UTC()
+	if hours[hour] == nil {
+		hours[hour] = make(map[string]bool)
+	}
+	for _, name := range domain.NormalizeNames(names) {
+		hours[hour][name] = true
+	}
+	return nil
+}
+
+func (s *Store) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := s.membersOf(guildNames)
+	var result []domain.OnlineCount
+	for hour, names := range s.presence[world] {
+		if hour.Before(since) {
+			continue
+		}
+		count := 0
+		for name := range names {
+			if len(guildNames) == 0 || members[name] {
+				count++
+			}
+		}
+		if count > 0 {
+			result = append(result, domain.OnlineCount{Hour: hour, Players: count})
+		}
+	}
+	slices.SortFunc(result, func(a, b domain.OnlineCount) int { return a.Hour.Compare(b.Hour) })
+	return result, nil
+}
+
+func (s *Store) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for _, hours := range s.presence {
+		for hour, names := range hours {
+			if hour.Before(seenBefore) {
+				deleted += int64(len(names))
+				delete(hours, hour)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.onlineCounts {
+		if c.world == world && c.sampledAt.Equal(at) {
+			s.onlineCounts[i].players = players
+			return nil
+		}
+	}
+	s.onlineCounts = append(s.onlineCounts, onlineCountRecord{world: world, sampledAt: at, players: players})
+	return nil
+}
+
+func (s *Store) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []domain.OnlineCount
+	for _, h := range s.hourlyCounts {
+		if h.world == world && !h.hour.Before(since) {
+			result = append(result, domain.OnlineCount{Hour: h.hour, Players: int(math.Round(h.avgPlayers))})
+		}
+	}
+	sums := make(map[time.Time][2]int)
+	for _, c := range s.onlineCounts {
+		if c.world == world && !c.sampledAt.Before(since) {
+			hour := c.sampledAt.UTC().Truncate(time.Hour)
+			sums[hour] = [2]int{sums[hour][0] + c.players, sums[hour][1] + 1}
+		}
+	}
+	for hour, sum := range sums {
+		result = append(result, domain.OnlineCount{Hour: hour, Players: int(math.Round(float64(sum[0]) / float64(sum[1])))})
+	}
+	slices.SortFunc(result, func(a, b domain.OnlineCount) int { return a.Hour.Compare(b.Hour) })
+	return result, nil
+}
+
+func (s *Store) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type bucket struct {
+		world string
+		hour  time.Time
+	}
+	folded := make(map[bucket][]int)
+	s.onlineCounts = slices.DeleteFunc(s.onlineCounts, func(c onlineCountRecord) bool {
+		if !c.sampledAt.Before(sampledBefore) {
+			return false
+		}
+		b := bucket{c.world, c.sampledAt.UTC().Truncate(time.Hour)}
+		folded[b] = append(folded[b], c.players)
+		return true
+	})
+
+	var written int64
+	for b, players := range folded {
+		if slices.ContainsFunc(s.hourlyCounts, func(h hourlyCountRecord) bool { return h.world == b.world && h.hour.Equal(b.hour) }) {
+			continue
+		}
+		sum := 0
+		for _, p := range players {
+			sum += p
+		}
+		s.hourlyCounts = append(s.hourlyCounts, hourlyCountRecord{
+			world:      b.world,
+			hour:       b.hour,
+			avgPlayers: float64(sum) / float64(len(players)),
+			maxPlayers: slices.Max(players),
+		})
+		written++
+	}
+	return written, nil
+}
+
+func (s *Store) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.hourlyCounts)
+	s.hourlyCounts = slices.DeleteFunc(s.hourlyCounts, func(h hourlyCountRecord) bool { return h.hour.Before(hourBefore) })
+	return int64(before - len(s.hourlyCounts)), nil
+}
+
+func (s *Store) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := make(map[string]int)
+	for i, session := range s.sessions {
+		if session.world == world && !session.lastSeenAt.Before(openSince) {
+			open[session.name] = i
+		}
+	}
+	for _, name := range domain.NormalizeNames(names) {
+		if i, ok := open[name]; ok {
+			s.sessions[i].lastSeenAt = at
+			continue
+		}
+		s.sessions = append(s.sessions, sessionRecord{world: world, name: name, startedAt: at, lastSeenAt: at})
+	}
+	return nil
+}
+
+func (s *Store) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name = domain.NormalizeName(name)
+	var online time.Duration
+	for _, session := range s.sessions {
+		if session.name != name || session.lastSeenAt.Before(since) {
+			continue
+		}
+		start := session.startedAt
+		if start.Before(since) {
+			start = since
+		}
+		online += session.lastSeenAt.Sub(start)
+	}
+	return online.Truncate(time.Second), nil
+}
+
+func (s *Store) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.sessions)
+	s.sessions = slices.DeleteFunc(s.sessions, func(session sessionRecord) bool { return session.lastSeenAt.Before(lastSeenBefore) })
+	return int64(before - len(s.sessions)), nil
+}
+
+func (s *Store) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reachedAt := levelUp.ReachedAt
+	if reachedAt.IsZero() {
+		reachedAt = s.now()
+	}
+	s.nextEventID++
+	s.levelUps = append(s.levelUps, domain.LevelUpRecord{
+		ID:        s.nextEventID,
+		Name:      domain.NormalizeName(levelUp.PlayerName),
+		World:     levelUp.World,
+		OldLevel:  levelUp.OldLevel,
+		NewLevel:  levelUp.NewLevel,
+		ReachedAt: reachedAt,
+	})
+	return nil
+}
+
+// GetLevelUpsSince returns the character's level ups, oldest first.
+func (s *Store) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	name = domain.NormalizeName(name)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []domain.LevelUp
+	for _, l := range s.levelUps {
+		if l.Name == name && !l.ReachedAt.Before(since) {
+			result = append(result, domain.LevelUp{
+				PlayerName: l.Name,
+				World:      l.World,
+				OldLevel:   l.OldLevel,
+				NewLevel:   l.NewLevel,
+				ReachedAt:  l.ReachedAt,
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ReachedAt.Before(result[j].ReachedAt) })
+	return result, nil
+}
+
+// GetDailyLevelGains sums the character's net levels per UTC day, oldest
+// first.
+func (s *Store) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	levelUps, _ := s.GetLevelUpsSince(ctx, name, since)
+
+	var result []domain.DailyLevelGain
+	for _, l := range levelUps {
+		day := l.ReachedAt.UTC().Truncate(24 * time.Hour)
+		if n := len(result); n > 0 && result[n-1].Day.Equal(day) {
+			result[n-1].Levels += l.NewLevel - l.OldLevel
+			continue
+		}
+		result = append(result, domain.DailyLevelGain{Day: day, Levels: l.NewLevel - l.OldLevel})
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.levelUps)
+	s.levelUps = slices.DeleteFunc(s.levelUps, func(l domain.LevelUpRecord) bool { return l.ReachedAt.Before(reachedBefore) })
+	return int64(before - len(s.levelUps)), nil
+}
+
+func (s *Store) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := s.membersOf(guildNames)
+	var result []domain.LevelUpRecord
+	for _, l := range s.levelUps {
+		if len(result) == limit {
+			break
+		}
+		if l.World != world || l.ReachedAt.Before(since) || l.ID <= afterID {
+			continue
+		}
+		if len(guildNames) > 0 && !members[l.Name] {
+			continue
+		}
+		result = append(result, l)
+	}
+	return result, nil
+}
+
+// -- House Auction Methods --
+
+func (s *Store) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []domain.HouseAuction
+	for _, a := range s.houseAuctions[world] {
+		if !a.seenAt.Before(seenSince) {
+			result = append(result, a.auction)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].HouseID < result[j].HouseID })
+	return result, nil
+}
+
+// ReplaceHouseAuctions stores auctions as the ones running on world and
+// forgets the rest.
+func (s *Store) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	stored := make(map[int]auctionRecord, len(auctions))
+	for _, a := range auctions {
+		stored[a.HouseID] = auctionRecord{
+			auction: domain.HouseAuction{
+				HouseID:    a.HouseID,
+				Name:       a.Name,
+				Town:       a.Town,
+				World:      world,
+				CurrentBid: a.CurrentBid,
+			},
+			seenAt: now,
+		}
+	}
+	s.houseAuctions[world] = stored
+	return nil
+}
+
+// -- Guild Member Methods --
+
+func (s *Store) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.guildMembers[guildName]))
+	for name := range s.guildMembers[guildName] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Store) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	members, ok := s.guildMembers[guildName]
+	if !ok {
+		members = make(map[string]bool, len(names))
+		s.guildMembers[guildName] = members
+	}
+	for _, name := range names {
+		members[name] = true
+	}
+	return nil
+}
+
+func (s *Store) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		delete(s.guildMembers[guildName], name)
+	}
+	return nil
+}
+
+func (s *Store) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]domain.GuildMemberList, 0, len(s.memberCache))
+	for _, list := range s.memberCache {
+		list.Members = slices.Clone(list.Members)
+		result = append(result, list)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GuildName < result[j].GuildName })
+	return result, nil
+}
+
+func (s *Store) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list.Members = slices.Clone(list.Members)
+	s.memberCache[list.GuildName] = list
+	return nil
+}
+
+func (s *Store) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for name := range s.memberCache {
+		if !slices.Contains(keep, name) {
+			delete(s.memberCache, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// -- Guild War Methods --
+
+func (s *Store) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	guildName = domain.NormalizeName(guildName)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	wars := s.wars[guildName]
+	if len(wars) == 0 {
+		return nil, nil
+	}
+	war := wars[len(wars)-1]
+	return &war, nil
+}
+
+func (s *Store) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	guildName = domain.NormalizeName(guildName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, war := range s.wars[guildName] {
+		if war.StartedAt.Equal(startedAt) {
+			return nil
+		}
+	}
+	s.wars[guildName] = append(s.wars[guildName], domain.GuildWar{GuildName: guildName, StartedAt: startedAt})
+	slices.SortFunc(s.wars[guildName], func(a, b domain.GuildWar) int { return a.StartedAt.Compare(b.StartedAt) })
+	return nil
+}
+
+func (s *Store) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	guildName = domain.NormalizeName(guildName)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wars := s.wars[guildName]
+	for i := range wars {
+		if wars[i].Ongoing() {
+			wars[i].EndedAt = endedAt
+		}
+	}
+	return nil
+}
+
+// -- Failed Notification Methods --
+
+func (s *Store) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.notifications = append(s.notifications, domain.FailedNotification{
+		ID:             s.nextID,
+		DiscordGuildID: n.DiscordGuildID,
+		Kind:           n.Kind,
+		Payload:        slices.Clone(n.Payload),
+		LastError:      n.LastError,
+		NextAttemptAt:  n.NextAttemptAt,
+		CreatedAt:      s.now(),
+	})
+	return nil
+}
+
+func (s *Store) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []domain.FailedNotification
+	for _, n := range s.notifications {
+		if len(result) == limit {
+			break
+		}
+		if !n.NextAttemptAt.After(due) {
+			result = append(result, cloneNotification(n))
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) GetGuildFailedNotifications(ctx context.Context, discordGuildID string) ([]domain.FailedNotification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []domain.FailedNotification
+	for _, n := range s.notifications {
+		if n.DiscordGuildID == discordGuildID {
+			result = append(result, cloneNotification(n))
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.notifications {
+		if s.notifications[i].ID == id {
+			s.notifications[i].Attempts++
+			s.notifications[i].LastError = lastErr
+			s.notifications[i].NextAttemptAt = nextAttempt
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteFailedNotification(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifications = slices.DeleteFunc(s.notifications, func(n domain.FailedNotification) bool { return n.ID == id })
+	return nil
+}
+
+func (s *Store) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.notifications)
+	s.notifications = slices.DeleteFunc(s.notifications, func(n domain.FailedNotification) bool {
+		return n.CreatedAt.Before(createdBefore)
+	})
+	return int64(before - len(s.notifications)), nil
+}
+
+// cloneConfig copies the slices and maps of cfg so callers cannot change stored data.
+func cloneConfig(cfg domain.GuildConfig) domain.GuildConfig {
+	cfg.TibiaGuilds = slices.Clone(cfg.TibiaGuilds)
+	cfg.IgnoredPlayers = slices.Clone(cfg.IgnoredPlayers)
+	cfg.DeathRoutes = slices.Clone(cfg.DeathRoutes)
+	cfg.WatchedPlayers = slices.Clone(cfg.WatchedPlayers)
+	cfg.SkillThresholds = maps.Clone(cfg.SkillThresholds)
+	return cfg
+}
+
+func cloneNotification(n domain.FailedNotification) domain.FailedNotification {
+	n.Payload = slices.Clone(n.Payload)
+	return n
+}
+
+func truncate[T any](items []T, limit int) []T {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+var _ ports.Repository = (*Store)(nil)
+
+// membersOf collects the members of the Tibia guilds. Callers hold the lock.
+func (s *Store) membersOf(guildNames []string) map[string]bool {
+	members := make(map[string]bool)
+	for _, guildName := range guildNames {
+		for name := range s.guildMembers[guildName] {
+			members[name] = true
+		}
+	}
+	return members
+}
@@ -0,0 +1,737 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+var ctx = context.Background()
+
+// newTestStore returns a store whose clock the test moves by hand.
+func newTestStore() (*Store, *time.Time) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewStore()
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestGuildConfig_Defaults(t *testing.T) {
+	s, _ := newTestStore()
+
+	if cfg, err := s.GetGuildConfig(ctx, "g1"); err != nil || cfg != nil {
+		t.Fatalf("expected no config for an unknown guild, got %+v, %v", cfg, err)
+	}
+
+	s.SetGuildLanguage(ctx, "g1", "pl")
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if cfg.Language != "pl" || !cfg.LowLevelDeaths || cfg.World != "" {
+		t.Errorf("expected an upserted config with schema defaults, got %+v", cfg)
+	}
+
+	// Plain updates do not create a config.
+	s.AddGuildToConfig(ctx, "g2", "Red Rose")
+	s.SetGuildLastNotified(ctx, "g2", time.Now())
+	if cfg, _ := s.GetGuildConfig(ctx, "g2"); cfg != nil {
+		t.Errorf("expected no config to be created, got %+v", cfg)
+	}
+}
+
+func TestGuildConfig_Lists(t *testing.T) {
+	s, _ := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+
+	s.AddGuildToConfig(ctx, "g1", "Red Rose")
+	s.AddGuildToConfig(ctx, "g1", "Red Rose")
+	s.AddGuildToConfig(ctx, "g1", "Blue Moon")
+	s.RemoveGuildFromConfig(ctx, "g1", "Red Rose")
+	s.AddIgnoredPlayer(ctx, "g1", "Bubble")
+	s.AddIgnoredPlayer(ctx, "g1", "bubble")
+	s.AddIgnoredPlayer(ctx, "g1", "Eternal Oblivion")
+	s.RemoveIgnoredPlayer(ctx, "g1", "ETERNAL OBLIVION")
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if len(cfg.TibiaGuilds) != 1 || cfg.TibiaGuilds[0] != "Blue Moon" {
+		t.Errorf("unexpected Tibia guilds: %v", cfg.TibiaGuilds)
+	}
+	if len(cfg.IgnoredPlayers) != 1 || cfg.IgnoredPlayers[0] != "Bubble" {
+		t.Errorf("unexpected ignored players: %v", cfg.IgnoredPlayers)
+	}
+
+	// Returned configs are copies.
+	cfg.TibiaGuilds[0] = "Changed"
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); cfg.TibiaGuilds[0] != "Blue Moon" {
+		t.Error("expected the stored config to be unaffected by callers")
+	}
+}
+
+func TestGuildConfig_RemoveAndRestore(t *testing.T) {
+	s, now := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+	s.SaveGuildWorld(ctx, "g2", "Secura")
+
+	s.MarkGuildRemoved(ctx, "g1", *now)
+	configs, _ := s.GetAllGuildConfigs(ctx)
+	if len(configs) != 1 || configs[0].DiscordGuildID != "g2" {
+		t.Fatalf("expected removed guilds to be hidden, got %+v", configs)
+	}
+
+	if restored, _ := s.RestoreGuildConfig(ctx, "g1"); !restored {
+		t.Error("expected the removed guild to be restored")
+	}
+	if restored, _ := s.RestoreGuildConfig(ctx, "g1"); restored {
+		t.Error("expected a second restore to do nothing")
+	}
+
+	s.MarkGuildRemoved(ctx, "g1", now.Add(-time.Hour))
+	if deleted, _ := s.DeleteRemovedGuildConfigs(ctx, *now); deleted != 1 {
+		t.Errorf("expected 1 removed guild to be deleted, got %d", deleted)
+	}
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); cfg != nil {
+		t.Errorf("expected the guild to be gone, got %+v", cfg)
+	}
+}
+
+func TestStoppedGuildConfigs(t *testing.T) {
+	s, now := newTestStore()
+	s.SaveStoppedGuildConfig(ctx, domain.StoppedGuildConfig{DiscordGuildID: "g1", Snapshot: []byte(`{"world":"Antica"}`), StoppedAt: now.Add(-time.Hour)})
+	s.SaveStoppedGuildConfig(ctx, domain.StoppedGuildConfig{DiscordGuildID: "g2", Snapshot: []byte(`{"world":"Secura"}`), StoppedAt: now.Add(-time.Minute)})
+
+	stopped, err := s.GetStoppedGuildConfig(ctx, "g1")
+	if err != nil || stopped == nil || string(stopped.Snapshot) != `{"world":"Antica"}` {
+		t.Fatalf("expected the snapshot of g1, got %+v, %v", stopped, err)
+	}
+	if stopped, _ := s.GetStoppedGuildConfig(ctx, "g3"); stopped != nil {
+		t.Errorf("expected no snapshot of g3, got %+v", stopped)
+	}
+
+	if deleted, _ := s.DeleteStoppedGuildConfigs(ctx, now.Add(-30*time.Minute)); deleted != 1 {
+		t.Errorf("expected 1 expired snapshot to be deleted, got %d", deleted)
+	}
+	if stopped, _ := s.GetStoppedGuildConfig(ctx, "g1"); stopped != nil {
+		t.Errorf("expected the expired snapshot to be gone, got %+v", stopped)
+	}
+
+	s.DeleteStoppedGuildConfig(ctx, "g2")
+	if stopped, _ := s.GetStoppedGuildConfig(ctx, "g2"); stopped != nil {
+		t.Errorf("expected the resumed snapshot to be gone, got %+v", stopped)
+	}
+}
+
+func TestSetGuildChannel(t *testing.T) {
+	s, _ := newTestStore()
+	for _, kind := range []domain.NotificationChannel{domain.ChannelDeaths, domain.ChannelLevels, domain.ChannelHouses, domain.ChannelMisc} {
+		if err := s.SetGuildChannel(ctx, "g1", kind, "c-"+string(kind)); err != nil {
+			t.Fatalf("SetGuildChannel(%s): %v", kind, err)
+		}
+	}
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if cfg.DeathChannelID != "c-deaths" || cfg.LevelChannelID != "c-levels" || cfg.HouseChannelID != "c-houses" || cfg.MiscChannelID != "c-misc" {
+		t.Errorf("unexpected channels: %+v", cfg)
+	}
+	if err := s.SetGuildChannel(ctx, "g1", "bogus", "c"); err == nil {
+		t.Error("expected an error for an unknown channel")
+	}
+}
+
+func TestSetGuildTemplate(t *testing.T) {
+	s, _ := newTestStore()
+	if err := s.SetGuildTemplate(ctx, "g1", domain.ChannelDeaths, "{player} died"); err != nil {
+		t.Fatalf("SetGuildTemplate(deaths): %v", err)
+	}
+	if err := s.SetGuildTemplate(ctx, "g1", domain.ChannelLevels, "{player} leveled"); err != nil {
+		t.Fatalf("SetGuildTemplate(levels): %v", err)
+	}
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if cfg.DeathTemplate != "{player} died" || cfg.LevelTemplate != "{player} leveled" {
+		t.Errorf("unexpected templates: %+v", cfg)
+	}
+	if err := s.SetGuildTemplate(ctx, "g1", domain.ChannelMisc, "x"); err == nil {
+		t.Error("expected an error for a channel without templates")
+	}
+}
+
+func TestSetGuildEmoji(t *testing.T) {
+	s, _ := newTestStore()
+	if err := s.SetGuildEmoji(ctx, "g1", domain.ChannelDeaths, "💀", true); err != nil {
+		t.Fatalf("SetGuildEmoji(deaths): %v", err)
+	}
+	if err := s.SetGuildEmoji(ctx, "g1", domain.ChannelLevels, "⭐", false); err != nil {
+		t.Fatalf("SetGuildEmoji(levels): %v", err)
+	}
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if cfg.DeathEmoji != "💀" || !cfg.DeathReaction || cfg.LevelEmoji != "⭐" || cfg.LevelReaction {
+		t.Errorf("unexpected emojis: %+v", cfg)
+	}
+	if err := s.SetGuildEmoji(ctx, "g1", domain.ChannelMisc, "x", false); err == nil {
+		t.Error("expected an error for a channel without emojis")
+	}
+}
+
+func TestSetGuildQuietHours(t *testing.T) {
+	s, _ := newTestStore()
+	quiet := domain.QuietHours{Start: 22 * 60, End: 6 * 60, CatchUp: true}
+	if err := s.SetGuildQuietHours(ctx, "g1", quiet); err != nil {
+		t.Fatalf("SetGuildQuietHours: %v", err)
+	}
+
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); cfg.QuietHours != quiet {
+		t.Errorf("expected %+v, got %+v", quiet, cfg.QuietHours)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	s, now := newTestStore()
+	s.BatchUpsertPlayerLevels(ctx, []domain.PlayerLevel{
+		{Name: "Alice", Level: 500, World: "Antica"},
+		{Name: "Bob", Level: 600, World: "Antica"},
+		{Name: "Alice", Level: 501, World: "Antica"},
+	})
+	s.UpsertPlayerLevel(ctx, "Carol", 700, "Secura")
+
+	levels, _ := s.GetPlayersLevels(ctx, "Antica")
+	if len(levels) != 2 || levels["Alice"] != 501 {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+
+	offline, _ := s.GetOfflinePlayers(ctx, "Antica", []string{"Alice"})
+	if len(offline) != 1 || offline[0].Name != "Bob" {
+		t.Errorf("unexpected offline players: %+v", offline)
+	}
+
+	*now = now.Add(time.Hour)
+	s.BatchTouchPlayers(ctx, []string{"Alice"})
+	deleted, _ := s.DeleteOldPlayers(ctx, "Antica", 30*time.Minute)
+	if deleted != 1 {
+		t.Errorf("expected only the untouched player to be pruned, got %d", deleted)
+	}
+}
+
+func TestNormalizesNames(t *testing.T) {
+	s, _ := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+
+	s.AddGuildToConfig(ctx, "g1", "Red+Rose")
+	s.AddGuildToConfig(ctx, "g1", "Red Rose")
+	s.AddIgnoredPlayer(ctx, "g1", "Sir%27Lance")
+	s.AddIgnoredPlayer(ctx, "g1", "sír’lance")
+	s.BatchUpsertPlayerLevels(ctx, []domain.PlayerLevel{
+		{Name: "Sir%27Lance", Level: 500, World: "Antica"},
+		{Name: "Sir'Lance", Level: 501, World: "Antica"},
+	})
+	s.RecordDeath(ctx, "Sir\u00a0Lance", "Antica", domain.Kill{Time: time.Now()})
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	if !reflect.DeepEqual(cfg.TibiaGuilds, []string{"Red Rose"}) || !reflect.DeepEqual(cfg.IgnoredPlayers, []string{"Sir'Lance"}) {
+		t.Errorf("expected one normalized entry per name, got %v and %v", cfg.TibiaGuilds, cfg.IgnoredPlayers)
+	}
+	if levels, _ := s.GetPlayersLevels(ctx, "Antica"); !reflect.DeepEqual(levels, map[string]int{"Sir'Lance": 501}) {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+	if count, _ := s.CountDeathsSince(ctx, "Sir+Lance", time.Time{}); count != 1 {
+		t.Errorf("expected the death under the normalized name, got %d", count)
+	}
+}
+
+func TestGetPlayersByPrefix(t *testing.T) {
+	s, _ := newTestStore()
+	s.BatchUpsertPlayerLevels(ctx, []domain.PlayerLevel{
+		{Name: "bubblegum", Level: 100, World: "Antica"},
+		{Name: "Bubble", Level: 100, World: "Antica"},
+		{Name: "Bubba", Level: 100, World: "Secura"},
+		{Name: "Sir Bubble", Level: 100, World: "Antica"},
+	})
+	for i := range domain.MaxNameSuggestions {
+		s.UpsertPlayerLevel(ctx, fmt.Sprintf("Zed %02d", i), 100, "Antica")
+	}
+
+	if names, _ := s.GetPlayersByPrefix(ctx, "Antica", "BUB"); !reflect.DeepEqual(names, []string{"Bubble", "bubblegum"}) {
+		t.Errorf("expected case-insensitive prefix matches on the world in order, got %v", names)
+	}
+	if names, _ := s.GetPlayersByPrefix(ctx, "Antica", "zed"); len(names) != domain.MaxNameSuggestions {
+		t.Errorf("expected %d suggestions at most, got %d", domain.MaxNameSuggestions, len(names))
+	}
+}
+
+func TestRenamePlayer(t *testing.T) {
+	s, now := newTestStore()
+	s.UpsertPlayerLevel(ctx, "Old Hero", 300, "Antica")
+	s.RecordDeath(ctx, "Old Hero", "Antica", domain.Kill{Time: now.Add(-time.Hour)})
+	s.RecordDeath(ctx, "Old Hero", "Antica", domain.Kill{Time: *now})
+	s.RecordDeath(ctx, "New Hero", "Antica", domain.Kill{Time: *now})
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Old Hero", World: "Antica", OldLevel: 299, NewLevel: 300})
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Old Hero"})
+	s.AddIgnoredPlayer(ctx, "guild-1", "Old Hero")
+
+	if err := s.RenamePlayer(ctx, "Old Hero", "New Hero"); err != nil {
+		t.Fatalf("RenamePlayer: %v", err)
+	}
+
+	if levels, _ := s.GetPlayersLevels(ctx, "Antica"); !reflect.DeepEqual(levels, map[string]int{"New Hero": 300}) {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+	if count, _ := s.CountDeathsSince(ctx, "New Hero", now.Add(-2*time.Hour)); count != 2 {
+		t.Errorf("expected the deaths merged without duplicates, got %d", count)
+	}
+	if levelUps, _ := s.GetLevelUpsSince(ctx, "New Hero", now.Add(-time.Hour)); len(levelUps) != 1 {
+		t.Errorf("expected the level history moved, got %+v", levelUps)
+	}
+	if members, _ := s.GetGuildMemberNames(ctx, "Red Rose"); !reflect.DeepEqual(members, []string{"New Hero"}) {
+		t.Errorf("unexpected guild members: %v", members)
+	}
+	if cfg, _ := s.GetGuildConfig(ctx, "guild-1"); !reflect.DeepEqual(cfg.IgnoredPlayers, []string{"New Hero"}) {
+		t.Errorf("unexpected ignored players: %v", cfg.IgnoredPlayers)
+	}
+}
+
+func TestDeaths(t *testing.T) {
+	s, now := newTestStore()
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Alice", "Bob"})
+
+	kill := func(minutesAgo int, killers ...string) domain.Kill {
+		k := domain.Kill{Time: now.Add(-time.Duration(minutesAgo) * time.Minute)}
+		for _, name := range killers {
+			k.Killers = append(k.Killers, domain.Killer{Name: name, IsPlayer: true})
+		}
+		k.Killers = append(k.Killers, domain.Killer{Name: "a dragon"})
+		return k
+	}
+	s.RecordDeath(ctx, "Alice", "Antica", kill(10, "Pk"))
+	s.RecordDeath(ctx, "Alice", "Antica", kill(10, "Pk"))
+	s.RecordDeath(ctx, "Alice", "Antica", kill(20, "Bob"))
+	s.RecordDeath(ctx, "Carol", "Antica", kill(30, "Pk"))
+	s.RecordDeath(ctx, "Dave", "Secura", kill(30, "Pk"))
+
+	if count, _ := s.CountDeathsSince(ctx, "Alice", now.Add(-time.Hour)); count != 2 {
+		t.Errorf("expected duplicate deaths to be ignored, got %d", count)
+	}
+
+	counts, _ := s.GetDeathCountsSince(ctx, "Antica", now.Add(-time.Hour))
+	if len(counts) != 2 || counts[0] != (domain.DeathCount{Name: "Alice", Count: 2}) {
+		t.Errorf("unexpected death counts: %+v", counts)
+	}
+
+	all, _ := s.GetTopKillersSince(ctx, "Antica", nil, now.Add(-time.Hour))
+	if len(all) != 2 || all[0] != (domain.KillerCount{Name: "Pk", Kills: 2}) {
+		t.Errorf("unexpected top killers: %+v", all)
+	}
+	guild, _ := s.GetTopKillersSince(ctx, "Antica", []string{"Red Rose"}, now.Add(-time.Hour))
+	if len(guild) != 1 || guild[0] != (domain.KillerCount{Name: "Pk", Kills: 1}) {
+		t.Errorf("expected only kills of guild members by outsiders, got %+v", guild)
+	}
+
+	if kills, _ := s.CountKillsBetween(ctx, "Antica", []string{"Alice", "Carol"}, []string{"Pk"}, now.Add(-25*time.Minute), *now); kills != 1 {
+		t.Errorf("expected 1 kill by Pk within the window, got %d", kills)
+	}
+
+	if deleted, _ := s.DeleteDeathsBefore(ctx, now.Add(-15*time.Minute)); deleted != 3 {
+		t.Errorf("expected 3 old deaths to be deleted, got %d", deleted)
+	}
+}
+
+func TestFragCounts(t *testing.T) {
+	s, now := newTestStore()
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Alice", "Bob"})
+
+	kill := func(killers ...string) domain.Kill {
+		k := domain.Kill{Time: now.Add(-time.Minute)}
+		for _, name := range killers {
+			k.Killers = append(k.Killers, domain.Killer{Name: name, IsPlayer: true})
+		}
+		return k
+	}
+	s.RecordDeath(ctx, "Enemy", "Antica", kill("Alice", "Bob"))
+	s.RecordDeath(ctx, "Other", "Antica", kill("Alice"))
+	s.RecordDeath(ctx, "Alice", "Antica", kill("Enemy"))
+	s.RecordDeath(ctx, "Bob", "Antica", kill("Alice", "Enemy", "Other"))
+	s.RecordDeath(ctx, "Bob", "Antica", domain.Kill{Time: now.Add(-2 * time.Minute), Killers: []domain.Killer{{Name: "a dragon"}}})
+
+	counts, _ := s.GetFragCountsSince(ctx, "Antica", []string{"Red Rose"}, "", now.Add(-time.Hour))
+	want := []domain.FragCount{{Name: "Alice", Frags: 2, Deaths: 1}, {Name: "Bob", Frags: 1, Deaths: 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected %+v, got %+v", want, counts)
+	}
+
+	all, _ := s.GetFragCountsSince(ctx, "Antica", nil, "", now.Add(-time.Hour))
+	if len(all) != 4 || all[0] != (domain.FragCount{Name: "Alice", Frags: 3, Deaths: 1}) {
+		t.Errorf("expected every character with a frag, got %+v", all)
+	}
+
+	one, _ := s.GetFragCountsSince(ctx, "Antica", nil, "bob", now.Add(-time.Hour))
+	if len(one) != 1 || one[0] != (domain.FragCount{Name: "Bob", Frags: 1, Deaths: 1}) {
+		t.Errorf("expected only Bob, got %+v", one)
+	}
+}
+
+func TestGuildWars(t *testing.T) {
+	s, now := newTestStore()
+	if war, _ := s.GetGuildWar(ctx, "Red Rose"); war != nil {
+		t.Fatalf("expected no war, got %+v", war)
+	}
+
+	start := *now
+	s.StartGuildWar(ctx, "Red Rose", start)
+	s.StartGuildWar(ctx, "Red Rose", start)
+	s.EndGuildWar(ctx, "Red Rose", start.Add(time.Hour))
+	s.StartGuildWar(ctx, "Red Rose", start.Add(2*time.Hour))
+
+	war, _ := s.GetGuildWar(ctx, "Red Rose")
+	if war == nil || !war.StartedAt.Equal(start.Add(2*time.Hour)) || !war.Ongoing() {
+		t.Errorf("expected the latest war to be ongoing, got %+v", war)
+	}
+}
+
+func TestLevelUps(t *testing.T) {
+	s, now := newTestStore()
+	start := *now
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", World: "Antica", OldLevel: 500, NewLevel: 501})
+	*now = now.Add(time.Hour)
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", World: "Antica", OldLevel: 501, NewLevel: 502})
+
+	levelUps, _ := s.GetLevelUpsSince(ctx, "Alice", start)
+	if len(levelUps) != 2 || levelUps[0].NewLevel != 501 || !levelUps[1].ReachedAt.Equal(*now) {
+		t.Errorf("unexpected level ups: %+v", levelUps)
+	}
+	if deleted, _ := s.DeleteLevelUpsBefore(ctx, *now); deleted != 1 {
+		t.Errorf("expected 1 old level up to be deleted, got %d", deleted)
+	}
+}
+
+func TestEventPages(t *testing.T) {
+	s, now := newTestStore()
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Alice"})
+	for i := 0; i < 3; i++ {
+		s.RecordDeath(ctx, "Alice", "Antica", domain.Kill{Time: now.Add(time.Duration(i) * time.Minute), Level: 100 + i})
+		s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", World: "Antica", OldLevel: 100 + i, NewLevel: 101 + i})
+	}
+	s.RecordDeath(ctx, "Carol", "Antica", domain.Kill{Time: *now})
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Carol", World: "Antica", OldLevel: 1, NewLevel: 2})
+
+	first, _ := s.GetDeathsPage(ctx, "Antica", []string{"Red Rose"}, time.Time{}, 0, 2)
+	if len(first) != 2 || first[0].Level != 100 || first[1].Level != 101 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	rest, _ := s.GetDeathsPage(ctx, "Antica", []string{"Red Rose"}, time.Time{}, first[1].ID, 2)
+	if len(rest) != 1 || rest[0].Level != 102 {
+		t.Errorf("expected the last guild member death after the first page, got %+v", rest)
+	}
+	if all, _ := s.GetDeathsPage(ctx, "Antica", nil, time.Time{}, 0, 10); len(all) != 4 {
+		t.Errorf("expected every death on the world without a guild filter, got %d", len(all))
+	}
+
+	levelUps, _ := s.GetLevelUpsPage(ctx, "Antica", []string{"Red Rose"}, time.Time{}, 0, 10)
+	if len(levelUps) != 3 || levelUps[2].NewLevel != 103 {
+		t.Errorf("unexpected level ups page: %+v", levelUps)
+	}
+	if secura, _ := s.GetLevelUpsPage(ctx, "Secura", nil, time.Time{}, 0, 10); len(secura) != 0 {
+		t.Errorf("expected no level ups on another world, got %+v", secura)
+	}
+}
+
+func TestDailyLevelGains(t *testing.T) {
+	s, now := newTestStore()
+	start := *now
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", OldLevel: 500, NewLevel: 501})
+	*now = now.Add(6 * time.Hour)
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", OldLevel: 501, NewLevel: 503})
+	*now = now.Add(24 * time.Hour)
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", OldLevel: 503, NewLevel: 502})
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Bob", OldLevel: 100, NewLevel: 101})
+
+	gains, _ := s.GetDailyLevelGains(ctx, "Alice", start)
+	want := []domain.DailyLevelGain{
+		{Day: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Levels: 3},
+		{Day: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Levels: -1},
+	}
+	if !reflect.DeepEqual(gains, want) {
+		t.Errorf("expected %+v, got %+v", want, gains)
+	}
+}
+
+func TestOnlinePresence(t *testing.T) {
+	s, now := newTestStore()
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Alice"})
+	hour := now.Truncate(time.Hour)
+
+	s.RecordOnlinePresence(ctx, "Antica", []string{"Alice", "Bob"}, hour.Add(10*time.Minute))
+	s.RecordOnlinePresence(ctx, "Antica", []string{"Alice", "Sir%27Lance"}, hour.Add(40*time.Minute))
+	s.RecordOnlinePresence(ctx, "Antica", []string{"Alice"}, hour.Add(-time.Hour))
+	s.RecordOnlinePresence(ctx, "Secura", []string{"Carol"}, hour)
+
+	counts, _ := s.GetOnlineCountsSince(ctx, "Antica", nil, hour.Add(-2*time.Hour))
+	want := []domain.OnlineCount{{Hour: hour.Add(-time.Hour), Players: 1}, {Hour: hour, Players: 3}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected %+v, got %+v", want, counts)
+	}
+	counts, _ = s.GetOnlineCountsSince(ctx, "Antica", []string{"Red Rose"}, hour)
+	if want := []domain.OnlineCount{{Hour: hour, Players: 1}}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected only guild members counted, got %+v", counts)
+	}
+
+	if deleted, _ := s.DeleteOnlinePresenceBefore(ctx, hour); deleted != 1 {
+		t.Errorf("expected 1 old sighting deleted, got %d", deleted)
+	}
+	if counts, _ := s.GetOnlineCountsSince(ctx, "Antica", nil, time.Time{}); len(counts) != 1 {
+		t.Errorf("expected only the current hour left, got %+v", counts)
+	}
+}
+
+func TestOnlineCounts(t *testing.T) {
+	s, now := newTestStore()
+	hour := now.Truncate(time.Hour)
+
+	s.RecordOnlineCount(ctx, "Antica", 100, hour.Add(-25*time.Hour))
+	s.RecordOnlineCount(ctx, "Antica", 200, hour.Add(-25*time.Hour+30*time.Minute))
+	s.RecordOnlineCount(ctx, "Antica", 40, hour.Add(5*time.Minute))
+	s.RecordOnlineCount(ctx, "Antica", 50, hour.Add(5*time.Minute))
+	s.RecordOnlineCount(ctx, "Secura", 7, hour)
+
+	if written, _ := s.DownsampleOnlineCounts(ctx, hour.Add(-24*time.Hour)); written != 1 {
+		t.Errorf("expected one hour downsampled, got %d", written)
+	}
+	if written, _ := s.DownsampleOnlineCounts(ctx, hour.Add(-24*time.Hour)); written != 0 {
+		t.Errorf("expected nothing left to downsample, got %d", written)
+	}
+
+	counts, _ := s.GetWorldOnlineCountsSince(ctx, "Antica", hour.Add(-48*time.Hour))
+	want := []domain.OnlineCount{{Hour: hour.Add(-25 * time.Hour), Players: 150}, {Hour: hour, Players: 50}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected %+v, got %+v", want, counts)
+	}
+
+	if deleted, _ := s.DeleteHourlyOnlineCountsBefore(ctx, hour); deleted != 1 {
+		t.Errorf("expected the hourly count deleted, got %d", deleted)
+	}
+	if counts, _ := s.GetWorldOnlineCountsSince(ctx, "Antica", time.Time{}); len(counts) != 1 {
+		t.Errorf("expected only the current hour left, got %+v", counts)
+	}
+}
+
+func TestOnlineSessions(t *testing.T) {
+	s, now := newTestStore()
+	gap := 15 * time.Minute
+	seen := func(at time.Time, names ...string) {
+		s.RecordOnlineSessions(ctx, "Antica", names, at, at.Add(-gap))
+	}
+
+	seen(*now, "Alice", "Bob")
+	seen(now.Add(5*time.Minute), "Alice", "Sir%27Lance")
+	seen(now.Add(10*time.Minute), "Alice")
+	// Bob comes back after the gap, which starts a new session.
+	seen(now.Add(30*time.Minute), "Bob")
+	seen(now.Add(35*time.Minute), "Bob")
+
+	for name, want := range map[string]time.Duration{
+		"Alice":      10 * time.Minute,
+		"Bob":        5 * time.Minute,
+		"Sir'Lance":  0,
+		"Nobody Yet": 0,
+	} {
+		if got, _ := s.GetOnlineTimeSince(ctx, name, *now); got != want {
+			t.Errorf("expected %s online for %v, got %v", name, want, got)
+		}
+	}
+	if got, _ := s.GetOnlineTimeSince(ctx, "Alice", now.Add(5*time.Minute)); got != 5*time.Minute {
+		t.Errorf("expected only the time since the cutoff, got %v", got)
+	}
+
+	if deleted, _ := s.DeleteOnlineSessionsBefore(ctx, now.Add(20*time.Minute)); deleted != 3 {
+		t.Errorf("expected the ended sessions deleted, got %d", deleted)
+	}
+	if got, _ := s.GetOnlineTimeSince(ctx, "Bob", time.Time{}); got != 5*time.Minute {
+		t.Errorf("expected Bob's latest session kept, got %v", got)
+	}
+}
+
+func TestHouseAuctions(t *testing.T) {
+	s, now := newTestStore()
+	s.ReplaceHouseAuctions(ctx, "Antica", []domain.HouseAuction{{HouseID: 2, Name: "B"}, {HouseID: 1, Name: "A"}})
+	*now = now.Add(time.Hour)
+	s.ReplaceHouseAuctions(ctx, "Antica", []domain.HouseAuction{{HouseID: 2, Name: "B", CurrentBid: 100}, {HouseID: 3, Name: "C"}})
+
+	auctions, _ := s.GetHouseAuctions(ctx, "Antica", now.Add(-time.Minute))
+	if len(auctions) != 2 || auctions[0].HouseID != 2 || auctions[0].CurrentBid != 100 || auctions[1].World != "Antica" {
+		t.Errorf("unexpected auctions: %+v", auctions)
+	}
+	if stale, _ := s.GetHouseAuctions(ctx, "Antica", now.Add(time.Minute)); len(stale) != 0 {
+		t.Errorf("expected no auctions seen after seenSince, got %+v", stale)
+	}
+}
+
+func TestFailedNotifications(t *testing.T) {
+	s, now := newTestStore()
+	s.EnqueueFailedNotification(ctx, domain.FailedNotification{DiscordGuildID: "g1", Kind: "death", NextAttemptAt: *now})
+	s.EnqueueFailedNotification(ctx, domain.FailedNotification{DiscordGuildID: "g2", Kind: "death", NextAttemptAt: now.Add(time.Hour)})
+	s.EnqueueFailedNotification(ctx, domain.FailedNotification{DiscordGuildID: "g1", Kind: "level", NextAttemptAt: *now})
+
+	due, _ := s.GetDueFailedNotifications(ctx, *now, 1)
+	if len(due) != 1 || due[0].ID != 1 {
+		t.Fatalf("expected the oldest due notification, got %+v", due)
+	}
+
+	s.RescheduleFailedNotification(ctx, 1, now.Add(time.Minute), "boom")
+	pending, _ := s.GetGuildFailedNotifications(ctx, "g1")
+	if len(pending) != 2 || pending[0].Attempts != 1 || pending[0].LastError != "boom" {
+		t.Errorf("unexpected pending notifications: %+v", pending)
+	}
+
+	s.DeleteFailedNotification(ctx, 3)
+	*now = now.Add(time.Hour)
+	s.EnqueueFailedNotification(ctx, domain.FailedNotification{DiscordGuildID: "g1", NextAttemptAt: *now})
+	if deleted, _ := s.DeleteExpiredFailedNotifications(ctx, *now); deleted != 2 {
+		t.Errorf("expected 2 expired notifications to be deleted, got %d", deleted)
+	}
+}
+
+func TestPurgeGuildData(t *testing.T) {
+	s, now := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+	s.AddGuildToConfig(ctx, "g1", "Red Rose")
+	s.AddGuildToConfig(ctx, "g1", "Shared")
+	s.SaveGuildWorld(ctx, "g2", "Secura")
+	s.AddGuildToConfig(ctx, "g2", "Shared")
+	s.AddGuildMembers(ctx, "Red Rose", []string{"Alice", "Bob"})
+	s.AddGuildMembers(ctx, "Shared", []string{"Carol"})
+	s.UpsertPlayerLevel(ctx, "Alice", 500, "Antica")
+	s.UpsertPlayerLevel(ctx, "Dave", 500, "Secura")
+	s.RecordDeath(ctx, "Alice", "Antica", domain.Kill{Time: *now})
+	s.RecordLevelUp(ctx, domain.LevelUp{PlayerName: "Alice", World: "Antica"})
+	s.EnqueueFailedNotification(ctx, domain.FailedNotification{DiscordGuildID: "g1"})
+	s.SaveStoppedGuildConfig(ctx, domain.StoppedGuildConfig{DiscordGuildID: "g1", Snapshot: []byte(`{}`), StoppedAt: *now})
+
+	result, err := s.PurgeGuildData(ctx, "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := domain.PurgeResult{Configs: 2, Notifications: 1, GuildMembers: 2, Deaths: 1, Players: 1, LevelUps: 1}
+	if result != want {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+	if members, _ := s.GetGuildMemberNames(ctx, "Shared"); len(members) != 1 {
+		t.Errorf("expected members of a guild still tracked elsewhere to be kept, got %v", members)
+	}
+	if levels, _ := s.GetPlayersLevels(ctx, "Secura"); len(levels) != 1 {
+		t.Errorf("expected players of other worlds to be kept, got %v", levels)
+	}
+}
+
+func TestDeathRoutes(t *testing.T) {
+	s, _ := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+	s.SetDeathRoute(ctx, "g1", 1000, "high")
+	s.SetDeathRoute(ctx, "g1", 300, "mid")
+	s.SetDeathRoute(ctx, "g1", 1000, "elite")
+
+	cfg, _ := s.GetGuildConfig(ctx, "g1")
+	want := []domain.DeathRoute{{MinLevel: 300, ChannelID: "mid"}, {MinLevel: 1000, ChannelID: "elite"}}
+	if !reflect.DeepEqual(cfg.DeathRoutes, want) {
+		t.Errorf("expected %+v, got %+v", want, cfg.DeathRoutes)
+	}
+
+	if removed, _ := s.DeleteDeathRoute(ctx, "g1", 300); !removed {
+		t.Error("expected the route at level 300 to be removed")
+	}
+	if removed, _ := s.DeleteDeathRoute(ctx, "g1", 300); removed {
+		t.Error("expected a second removal to report nothing removed")
+	}
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); len(cfg.DeathRoutes) != 1 {
+		t.Errorf("expected one route left, got %+v", cfg.DeathRoutes)
+	}
+}
+
+func TestStore_ConcurrentUse(t *testing.T) {
+	s := NewStore()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			guildID := fmt.Sprintf("g%d", i)
+			for j := 0; j < 50; j++ {
+				name := fmt.Sprintf("p%d-%d", i, j)
+				s.SaveGuildWorld(ctx, guildID, "Antica")
+				s.AddIgnoredPlayer(ctx, guildID, name)
+				s.UpsertPlayerLevel(ctx, name, j, "Antica")
+				s.AddGuildMembers(ctx, guildID, []string{name})
+				s.GetAllGuildConfigs(ctx)
+				s.GetPlayersLevels(ctx, "Antica")
+				s.GetTopKillersSince(ctx, "Antica", []string{guildID}, time.Time{})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if levels, _ := s.GetPlayersLevels(ctx, "Antica"); len(levels) != 8*50 {
+		t.Errorf("expected %d players, got %d", 8*50, len(levels))
+	}
+}
+
+func TestGuildMemberCache(t *testing.T) {
+	s, clock := newTestStore()
+	now := *clock
+	s.SaveGuildMemberCache(ctx, domain.GuildMemberList{GuildName: "Red Rose", Members: []string{"Alice"}, FetchedAt: now.Add(-time.Hour)})
+	s.SaveGuildMemberCache(ctx, domain.GuildMemberList{GuildName: "Red Rose", Members: []string{"Alice", "Bob"}, FetchedAt: now})
+	s.SaveGuildMemberCache(ctx, domain.GuildMemberList{GuildName: "Blue Moon", Members: []string{"Carol"}, FetchedAt: now})
+
+	lists, _ := s.GetGuildMemberCache(ctx)
+	want := []domain.GuildMemberList{
+		{GuildName: "Blue Moon", Members: []string{"Carol"}, FetchedAt: now},
+		{GuildName: "Red Rose", Members: []string{"Alice", "Bob"}, FetchedAt: now},
+	}
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("unexpected cache: %+v", lists)
+	}
+
+	if deleted, _ := s.DeleteGuildMemberCacheExcept(ctx, []string{"Red Rose"}); deleted != 1 {
+		t.Errorf("expected 1 list deleted, got %d", deleted)
+	}
+	if lists, _ := s.GetGuildMemberCache(ctx); len(lists) != 1 || lists[0].GuildName != "Red Rose" {
+		t.Errorf("expected only Red Rose kept, got %+v", lists)
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	s, _ := newTestStore()
+	s.SaveGuildWorld(ctx, "g1", "Antica")
+
+	tx, _ := s.BeginTx(ctx)
+	tx.SaveGuildWorld(ctx, "g1", "Secura")
+	tx.AddGuildToConfig(ctx, "g1", "Red Rose")
+	tx.SaveGuildWorld(ctx, "g2", "Refugia")
+	tx.UpsertPlayerLevel(ctx, "Bubble", 100, "Secura")
+	tx.Rollback(ctx)
+
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); cfg.World != "Antica" || len(cfg.TibiaGuilds) != 0 {
+		t.Errorf("expected the rolled back writes undone, got %+v", cfg)
+	}
+	if cfg, _ := s.GetGuildConfig(ctx, "g2"); cfg != nil {
+		t.Errorf("expected the rolled back insert undone, got %+v", cfg)
+	}
+	if levels, _ := s.GetPlayersLevels(ctx, "Secura"); len(levels) != 0 {
+		t.Errorf("expected no levels, got %v", levels)
+	}
+
+	tx, _ = s.BeginTx(ctx)
+	tx.SaveGuildWorld(ctx, "g1", "Secura")
+	nested, _ := tx.BeginTx(ctx)
+	nested.SaveGuildWorld(ctx, "g2", "Refugia")
+	nested.Rollback(ctx)
+	tx.Commit(ctx)
+	tx.Rollback(ctx)
+
+	if cfg, _ := s.GetGuildConfig(ctx, "g1"); cfg.World != "Secura" {
+		t.Errorf("expected the committed world, got %+v", cfg)
+	}
+	if cfg, _ := s.GetGuildConfig(ctx, "g2"); cfg != nil {
+		t.Errorf("expected the nested rollback to undo its insert only, got %+v", cfg)
+	}
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// memoryTx writes straight to the store and restores the state it began
+// with on Rollback. Transactions are not isolated, so a rollback also undoes
+// writes other callers made meanwhile; the bot keeps them short enough for a
+// single-process demo store.
+type memoryTx struct {
+	*Store
+	snapshot *Store
+	done     bool
+}
+
+func (s *Store) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &memoryTx{Store: s, snapshot: s.clone()}, nil
+}
+
+func (t *memoryTx) Commit(ctx context.Context) error {
+	t.done = true
+	return nil
+}
+
+func (t *memoryTx) Rollback(ctx context.Context) error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.restore(t.snapshot)
+	return nil
+}
+
+// clone deep-copies the stored data. Callers hold the lock.
+func (s *Store) clone() *Store {
+	c := &Store{
+		guilds:        make(map[string]*guildRecord, len(s.guilds)),
+		stopped:       maps.Clone(s.stopped),
+		players:       maps.Clone(s.players),
+		deaths:        slices.Clone(s.deaths),
+		levelUps:      slices.Clone(s.levelUps),
+		presence:      make(map[string]map[time.Time]map[string]bool, len(s.presence)),
+		onlineCounts:  slices.Clone(s.onlineCounts),
+		hourlyCounts:  slices.Clone(s.hourlyCounts),
+		sessions:      slices.Clone(s.sessions),
+		houseAuctions: make(map[string]map[int]auctionRecord, len(s.houseAuctions)),
+		playerSkills:  make(map[string]map[domain.Skill]map[string]int, len(s.playerSkills)),
+		guildMembers:  make(map[string]map[string]bool, len(s.guildMembers)),
+		memberCache:   maps.Clone(s.memberCache),
+		notifications: slices.Clone(s.notifications),
+		nextID:        s.nextID,
+		nextEventID:   s.nextEventID,
+	}
+	for id, g := range s.guilds {
+		c.guilds[id] = &guildRecord{config: cloneConfig(g.config), removedAt: g.removedAt}
+	}
+	for world, hours := range s.presence {
+		c.presence[world] = make(map[time.Time]map[string]bool, len(hours))
+		for hour, names := range hours {
+			c.presence[world][hour] = maps.Clone(names)
+		}
+	}
+	for world, auctions := range s.houseAuctions {
+		c.houseAuctions[world] = maps.Clone(auctions)
+	}
+	for world, skills := range s.playerSkills {
+		c.playerSkills[world] = make(map[domain.Skill]map[string]int, len(skills))
+		for skill, values := range skills {
+			c.playerSkills[world][skill] = maps.Clone(values)
+		}
+	}
+	for guild, members := range s.guildMembers {
+		c.guildMembers[guild] = maps.Clone(members)
+	}
+	return c
+}
+
+// restore replaces the stored data with a clone. Callers hold the lock.
+func (s *Store) restore(c *Store) {
+	s.guilds = c.guilds
+	s.stopped = c.stopped
+	s.players = c.players
+	s.deaths = c.deaths
+	s.levelUps = c.levelUps
+	s.presence = c.presence
+	s.onlineCounts = c.onlineCounts
+	s.hourlyCounts = c.hourlyCounts
+	s.sessions = c.sessions
+	s.houseAuctions = c.houseAuctions
+	s.playerSkills = c.playerSkills
+	s.guildMembers = c.guildMembers
+	s.memberCache = c.memberCache
+	s.notifications = c.notifications
+	s.nextID = c.nextID
+	s.nextEventID = c.nextEventID
+}
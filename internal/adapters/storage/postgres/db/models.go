@@ -8,11 +8,140 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Death struct {
+	ID        int64
+	Name      string
+	World     string
+	Level     int32
+	Reason    string
+	DiedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamp
+	Killers   []string
+}
+
+type DeathRoute struct {
+	GuildID   string
+	MinLevel  int32
+	ChannelID string
+}
+
+type FailedNotification struct {
+	ID            int64
+	GuildID       string
+	Kind          string
+	Payload       []byte
+	Attempts      int32
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+}
+
 type GuildConfig struct {
-	GuildID     string
-	World       string
-	TibiaGuilds []string
-	UpdatedAt   pgtype.Timestamp
+	GuildID                string
+	World                  string
+	TibiaGuilds            []string
+	UpdatedAt              pgtype.Timestamp
+	Language               string
+	DeathChannelID         string
+	LevelChannelID         string
+	PingRoleID             string
+	PingMinLevel           int32
+	PollIntervalSeconds    int32
+	MutedUntil             pgtype.Timestamptz
+	IgnoredPlayers         []string
+	LowLevelDeaths         bool
+	LastNotifiedAt         pgtype.Timestamptz
+	RemovedAt              pgtype.Timestamptz
+	HouseChannelID         string
+	MiscChannelID          string
+	Timezone               string
+	DeathTemplate          string
+	LevelTemplate          string
+	DeathEmoji             string
+	LevelEmoji             string
+	DeathReaction          bool
+	LevelReaction          bool
+	QuietStart             int32
+	QuietEnd               int32
+	QuietCatchUp           bool
+	QuotaTibiaGuilds       int32
+	QuotaIgnoredPlayers    int32
+	Premium                bool
+	ShareRange             bool
+	SkillChannelID         string
+	WatchedPlayers         []string
+	MassDeathCount         int32
+	MassDeathWindowMinutes int32
+	BroadcastOptOut        bool
+	MinLevel               int32
+	DeathLocation          bool
+	LevelDowns             bool
+	LevelDownTemplate      string
+	ResponseVisibility     string
+	DeathVerbosity         string
+	QuotaWatchedPlayers    int32
+}
+
+type GuildMember struct {
+	GuildName string
+	Name      string
+	JoinedAt  pgtype.Timestamptz
+}
+
+type GuildMemberCache struct {
+	GuildName string
+	Members   []string
+	FetchedAt pgtype.Timestamptz
+}
+
+type GuildWar struct {
+	GuildName string
+	StartedAt pgtype.Timestamptz
+	EndedAt   pgtype.Timestamptz
+}
+
+type HouseAuction struct {
+	World      string
+	HouseID    int32
+	Name       string
+	Town       string
+	CurrentBid int64
+	SeenAt     pgtype.Timestamptz
+}
+
+type LevelUp struct {
+	ID        int64
+	Name      string
+	World     string
+	OldLevel  int32
+	NewLevel  int32
+	ReachedAt pgtype.Timestamptz
+}
+
+type OnlineCount struct {
+	World     string
+	SampledAt pgtype.Timestamptz
+	Players   int32
+}
+
+type OnlineCountsHourly struct {
+	World      string
+	Hour       pgtype.Timestamptz
+	AvgPlayers float32
+	MaxPlayers int32
+}
+
+type OnlinePresence struct {
+	World    string
+	SeenHour pgtype.Timestamptz
+	Name     string
+}
+
+type OnlineSession struct {
+	World      string
+	Name       string
+	StartedAt  pgtype.Timestamptz
+	LastSeenAt pgtype.Timestamptz
 }
 
 type Player struct {
@@ -21,3 +150,23 @@ type Player struct {
 	World     string
 	UpdatedAt pgtype.Timestamp
 }
+
+type PlayerSkill struct {
+	World     string
+	Name      string
+	Skill     string
+	Value     int32
+	UpdatedAt pgtype.Timestamptz
+}
+
+type SkillThreshold struct {
+	GuildID  string
+	Skill    string
+	MinValue int32
+}
+
+type StoppedGuildConfig struct {
+	GuildID   string
+	Config    []byte
+	StoppedAt pgtype.Timestamptz
+}
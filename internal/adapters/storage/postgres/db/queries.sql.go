@@ -12,6 +12,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addGuildMembers = `-- name: AddGuildMembers :exec
+INSERT INTO guild_members (guild_name, name)
+SELECT $1, unnest($2::text[])
+ON CONFLICT (guild_name, name) DO NOTHING
+`
+
+type AddGuildMembersParams struct {
+	GuildName string
+	Names     []string
+}
+
+func (q *Queries) AddGuildMembers(ctx context.Context, arg AddGuildMembersParams) error {
+	_, err := q.db.Exec(ctx, addGuildMembers, arg.GuildName, arg.Names)
+	return err
+}
+
 const addGuildToConfig = `-- name: AddGuildToConfig :exec
 UPDATE guild_configs
 SET tibia_guilds = array_append(tibia_guilds, $2::text), updated_at = NOW()
@@ -28,6 +44,42 @@ func (q *Queries) AddGuildToConfig(ctx context.Context, arg AddGuildToConfigPara
 	return err
 }
 
+const addIgnoredPlayer = `-- name: AddIgnoredPlayer :exec
+INSERT INTO guild_configs (guild_id, world, ignored_players, updated_at)
+VALUES ($1, '', ARRAY[$2::text], NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET ignored_players = array_append(COALESCE(guild_configs.ignored_players, '{}'), $2::text), updated_at = NOW()
+WHERE NOT EXISTS (SELECT 1 FROM unnest(guild_configs.ignored_players) AS p WHERE lower(p) = lower($2::text))
+`
+
+type AddIgnoredPlayerParams struct {
+	GuildID string
+	Name    string
+}
+
+func (q *Queries) AddIgnoredPlayer(ctx context.Context, arg AddIgnoredPlayerParams) error {
+	_, err := q.db.Exec(ctx, addIgnoredPlayer, arg.GuildID, arg.Name)
+	return err
+}
+
+const addWatchedPlayer = `-- name: AddWatchedPlayer :exec
+INSERT INTO guild_configs (guild_id, world, watched_players, updated_at)
+VALUES ($1, '', ARRAY[$2::text], NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET watched_players = array_append(COALESCE(guild_configs.watched_players, '{}'), $2::text), updated_at = NOW()
+WHERE NOT EXISTS (SELECT 1 FROM unnest(guild_configs.watched_players) AS p WHERE lower(p) = lower($2::text))
+`
+
+type AddWatchedPlayerParams struct {
+	GuildID string
+	Name    string
+}
+
+func (q *Queries) AddWatchedPlayer(ctx context.Context, arg AddWatchedPlayerParams) error {
+	_, err := q.db.Exec(ctx, addWatchedPlayer, arg.GuildID, arg.Name)
+	return err
+}
+
 const batchTouchPlayers = `-- name: BatchTouchPlayers :exec
 UPDATE players SET updated_at = NOW() WHERE name = ANY($1::text[])
 `
@@ -37,6 +89,131 @@ func (q *Queries) BatchTouchPlayers(ctx context.Context, names []string) error {
 	return err
 }
 
+const batchUpsertPlayerLevels = `-- name: BatchUpsertPlayerLevels :exec
+INSERT INTO players (name, level, world, updated_at)
+SELECT unnest($1::text[]), unnest($2::int[]), unnest($3::text[]), NOW()
+ON CONFLICT (name) DO UPDATE
+SET level = EXCLUDED.level, world = EXCLUDED.world, updated_at = NOW()
+`
+
+type BatchUpsertPlayerLevelsParams struct {
+	Names  []string
+	Levels []int32
+	Worlds []string
+}
+
+func (q *Queries) BatchUpsertPlayerLevels(ctx context.Context, arg BatchUpsertPlayerLevelsParams) error {
+	_, err := q.db.Exec(ctx, batchUpsertPlayerLevels, arg.Names, arg.Levels, arg.Worlds)
+	return err
+}
+
+const batchUpsertPlayerSkills = `-- name: BatchUpsertPlayerSkills :exec
+INSERT INTO player_skills (world, name, skill, value, updated_at)
+SELECT $1::text, unnest($2::text[]), $3::text, unnest($4::int[]), NOW()
+ON CONFLICT (world, name, skill) DO UPDATE
+SET value = EXCLUDED.value, updated_at = NOW()
+`
+
+type BatchUpsertPlayerSkillsParams struct {
+	World       string
+	Names       []string
+	Skill       string
+	SkillValues []int32
+}
+
+func (q *Queries) BatchUpsertPlayerSkills(ctx context.Context, arg BatchUpsertPlayerSkillsParams) error {
+	_, err := q.db.Exec(ctx, batchUpsertPlayerSkills,
+		arg.World,
+		arg.Names,
+		arg.Skill,
+		arg.SkillValues,
+	)
+	return err
+}
+
+const countDeathsSince = `-- name: CountDeathsSince :one
+SELECT COUNT(*) FROM deaths WHERE name = $1 AND died_at >= $2
+`
+
+type CountDeathsSinceParams struct {
+	Name  string
+	Since pgtype.Timestamptz
+}
+
+func (q *Queries) CountDeathsSince(ctx context.Context, arg CountDeathsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countDeathsSince, arg.Name, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countKillsBetween = `-- name: CountKillsBetween :one
+SELECT COUNT(*) FROM deaths
+WHERE world = $1 AND died_at >= $2 AND died_at < $3
+  AND name = ANY($4::text[]) AND killers && $5::text[]
+`
+
+type CountKillsBetweenParams struct {
+	World   string
+	Since   pgtype.Timestamptz
+	Until   pgtype.Timestamptz
+	Victims []string
+	Killers []string
+}
+
+// Counts deaths on world from since until until of the victims with one of
+// killers among their player killers.
+func (q *Queries) CountKillsBetween(ctx context.Context, arg CountKillsBetweenParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countKillsBetween,
+		arg.World,
+		arg.Since,
+		arg.Until,
+		arg.Victims,
+		arg.Killers,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteDeathRoute = `-- name: DeleteDeathRoute :execresult
+DELETE FROM death_routes WHERE guild_id = $1 AND min_level = $2
+`
+
+type DeleteDeathRouteParams struct {
+	GuildID  string
+	MinLevel int32
+}
+
+func (q *Queries) DeleteDeathRoute(ctx context.Context, arg DeleteDeathRouteParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteDeathRoute, arg.GuildID, arg.MinLevel)
+}
+
+const deleteDeathsBefore = `-- name: DeleteDeathsBefore :execresult
+DELETE FROM deaths WHERE died_at < $1
+`
+
+func (q *Queries) DeleteDeathsBefore(ctx context.Context, diedBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteDeathsBefore, diedBefore)
+}
+
+const deleteExpiredFailedNotifications = `-- name: DeleteExpiredFailedNotifications :execresult
+DELETE FROM failed_notifications WHERE created_at < $1
+`
+
+func (q *Queries) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteExpiredFailedNotifications, createdBefore)
+}
+
+const deleteFailedNotification = `-- name: DeleteFailedNotification :exec
+DELETE FROM failed_notifications WHERE id = $1
+`
+
+func (q *Queries) DeleteFailedNotification(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteFailedNotification, id)
+	return err
+}
+
 const deleteGuildConfig = `-- name: DeleteGuildConfig :exec
 DELETE FROM guild_configs WHERE guild_id = $1
 `
@@ -46,6 +223,30 @@ func (q *Queries) DeleteGuildConfig(ctx context.Context, guildID string) error {
 	return err
 }
 
+const deleteGuildMemberCacheExcept = `-- name: DeleteGuildMemberCacheExcept :execresult
+DELETE FROM guild_member_cache WHERE NOT (guild_name = ANY($1::text[]))
+`
+
+func (q *Queries) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteGuildMemberCacheExcept, keep)
+}
+
+const deleteHourlyOnlineCountsBefore = `-- name: DeleteHourlyOnlineCountsBefore :execresult
+DELETE FROM online_counts_hourly WHERE hour < $1
+`
+
+func (q *Queries) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteHourlyOnlineCountsBefore, hourBefore)
+}
+
+const deleteLevelUpsBefore = `-- name: DeleteLevelUpsBefore :execresult
+DELETE FROM level_ups WHERE reached_at < $1
+`
+
+func (q *Queries) DeleteLevelUpsBefore(ctx context.Context, reachedBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteLevelUpsBefore, reachedBefore)
+}
+
 const deleteOldPlayers = `-- name: DeleteOldPlayers :execresult
 DELETE FROM players WHERE world = $1 AND updated_at < NOW() - $2::interval
 `
@@ -59,46 +260,144 @@ func (q *Queries) DeleteOldPlayers(ctx context.Context, arg DeleteOldPlayersPara
 	return q.db.Exec(ctx, deleteOldPlayers, arg.World, arg.Threshold)
 }
 
-const getGuildConfig = `-- name: GetGuildConfig :one
-SELECT guild_id, world, tibia_guilds, updated_at FROM guild_configs WHERE guild_id = $1
+const deleteOnlinePresenceBefore = `-- name: DeleteOnlinePresenceBefore :execresult
+DELETE FROM online_presence WHERE seen_hour < $1
 `
 
-func (q *Queries) GetGuildConfig(ctx context.Context, guildID string) (GuildConfig, error) {
-	row := q.db.QueryRow(ctx, getGuildConfig, guildID)
-	var i GuildConfig
-	err := row.Scan(
-		&i.GuildID,
-		&i.World,
-		&i.TibiaGuilds,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteOnlinePresenceBefore, seenBefore)
 }
 
-const getOfflinePlayers = `-- name: GetOfflinePlayers :many
-SELECT name, level FROM players WHERE world = $1 AND name != ALL($2::text[])
+const deleteOnlineSessionsBefore = `-- name: DeleteOnlineSessionsBefore :execresult
+DELETE FROM online_sessions WHERE last_seen_at < $1
 `
 
-type GetOfflinePlayersParams struct {
-	World       string
-	OnlineNames []string
+func (q *Queries) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteOnlineSessionsBefore, lastSeenBefore)
 }
 
-type GetOfflinePlayersRow struct {
+const deleteRemovedGuildConfigs = `-- name: DeleteRemovedGuildConfigs :execresult
+DELETE FROM guild_configs WHERE removed_at < $1
+`
+
+func (q *Queries) DeleteRemovedGuildConfigs(ctx context.Context, removedAt pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteRemovedGuildConfigs, removedAt)
+}
+
+const deleteSkillThreshold = `-- name: DeleteSkillThreshold :execresult
+DELETE FROM skill_thresholds WHERE guild_id = $1 AND skill = $2
+`
+
+type DeleteSkillThresholdParams struct {
+	GuildID string
+	Skill   string
+}
+
+func (q *Queries) DeleteSkillThreshold(ctx context.Context, arg DeleteSkillThresholdParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteSkillThreshold, arg.GuildID, arg.Skill)
+}
+
+const deleteStoppedGuildConfig = `-- name: DeleteStoppedGuildConfig :exec
+DELETE FROM stopped_guild_configs WHERE guild_id = $1
+`
+
+func (q *Queries) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	_, err := q.db.Exec(ctx, deleteStoppedGuildConfig, guildID)
+	return err
+}
+
+const deleteStoppedGuildConfigs = `-- name: DeleteStoppedGuildConfigs :execresult
+DELETE FROM stopped_guild_configs WHERE stopped_at < $1
+`
+
+func (q *Queries) DeleteStoppedGuildConfigs(ctx context.Context, stoppedAt pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, deleteStoppedGuildConfigs, stoppedAt)
+}
+
+const downsampleOnlineCounts = `-- name: DownsampleOnlineCounts :execresult
+WITH folded AS (
+    DELETE FROM online_counts WHERE sampled_at < $1
+    RETURNING world, sampled_at, players
+)
+INSERT INTO online_counts_hourly (world, hour, avg_players, max_players)
+SELECT world, date_trunc('hour', sampled_at, 'UTC'), AVG(players), MAX(players)
+FROM folded
+GROUP BY 1, 2
+ON CONFLICT (world, hour) DO NOTHING
+`
+
+// Folds cycle counts taken before sampled_before into hourly averages.
+// sampled_before must fall on the hour so no hour is folded twice.
+func (q *Queries) DownsampleOnlineCounts(ctx context.Context, sampledBefore pgtype.Timestamptz) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, downsampleOnlineCounts, sampledBefore)
+}
+
+const endGuildWar = `-- name: EndGuildWar :exec
+UPDATE guild_wars SET ended_at = $2 WHERE guild_name = $1 AND ended_at IS NULL
+`
+
+type EndGuildWarParams struct {
+	GuildName string
+	EndedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) EndGuildWar(ctx context.Context, arg EndGuildWarParams) error {
+	_, err := q.db.Exec(ctx, endGuildWar, arg.GuildName, arg.EndedAt)
+	return err
+}
+
+const enqueueFailedNotification = `-- name: EnqueueFailedNotification :exec
+INSERT INTO failed_notifications (guild_id, kind, payload, last_error, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type EnqueueFailedNotificationParams struct {
+	GuildID       string
+	Kind          string
+	Payload       []byte
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) EnqueueFailedNotification(ctx context.Context, arg EnqueueFailedNotificationParams) error {
+	_, err := q.db.Exec(ctx, enqueueFailedNotification,
+		arg.GuildID,
+		arg.Kind,
+		arg.Payload,
+		arg.LastError,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const getDailyLevelGains = `-- name: GetDailyLevelGains :many
+SELECT date_trunc('day', reached_at AT TIME ZONE 'UTC')::timestamp AS day, SUM(new_level - old_level)::int AS levels
+FROM level_ups
+WHERE name = $1 AND reached_at >= $2
+GROUP BY day
+ORDER BY day
+`
+
+type GetDailyLevelGainsParams struct {
 	Name  string
-	Level int32
+	Since pgtype.Timestamptz
 }
 
-func (q *Queries) GetOfflinePlayers(ctx context.Context, arg GetOfflinePlayersParams) ([]GetOfflinePlayersRow, error) {
-	rows, err := q.db.Query(ctx, getOfflinePlayers, arg.World, arg.OnlineNames)
+type GetDailyLevelGainsRow struct {
+	Day    pgtype.Timestamp
+	Levels int32
+}
+
+func (q *Queries) GetDailyLevelGains(ctx context.Context, arg GetDailyLevelGainsParams) ([]GetDailyLevelGainsRow, error) {
+	rows, err := q.db.Query(ctx, getDailyLevelGains, arg.Name, arg.Since)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetOfflinePlayersRow
+	var items []GetDailyLevelGainsRow
 	for rows.Next() {
-		var i GetOfflinePlayersRow
-		if err := rows.Scan(&i.Name, &i.Level); err != nil {
+		var i GetDailyLevelGainsRow
+		if err := rows.Scan(&i.Day, &i.Levels); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -109,25 +408,34 @@ func (q *Queries) GetOfflinePlayers(ctx context.Context, arg GetOfflinePlayersPa
 	return items, nil
 }
 
-const getPlayersLevels = `-- name: GetPlayersLevels :many
-SELECT name, level FROM players WHERE world = $1
+const getDeathCountsSince = `-- name: GetDeathCountsSince :many
+SELECT name, COUNT(*) AS deaths FROM deaths
+WHERE world = $1 AND died_at >= $2
+GROUP BY name
+ORDER BY deaths DESC, name
+LIMIT 25
 `
 
-type GetPlayersLevelsRow struct {
-	Name  string
-	Level int32
+type GetDeathCountsSinceParams struct {
+	World string
+	Since pgtype.Timestamptz
 }
 
-func (q *Queries) GetPlayersLevels(ctx context.Context, world string) ([]GetPlayersLevelsRow, error) {
-	rows, err := q.db.Query(ctx, getPlayersLevels, world)
+type GetDeathCountsSinceRow struct {
+	Name   string
+	Deaths int64
+}
+
+func (q *Queries) GetDeathCountsSince(ctx context.Context, arg GetDeathCountsSinceParams) ([]GetDeathCountsSinceRow, error) {
+	rows, err := q.db.Query(ctx, getDeathCountsSince, arg.World, arg.Since)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPlayersLevelsRow
+	var items []GetDeathCountsSinceRow
 	for rows.Next() {
-		var i GetPlayersLevelsRow
-		if err := rows.Scan(&i.Name, &i.Level); err != nil {
+		var i GetDeathCountsSinceRow
+		if err := rows.Scan(&i.Name, &i.Deaths); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -138,26 +446,84 @@ func (q *Queries) GetPlayersLevels(ctx context.Context, world string) ([]GetPlay
 	return items, nil
 }
 
-const getWorldsMap = `-- name: GetWorldsMap :many
-SELECT guild_id, world, tibia_guilds FROM guild_configs
+const getDeathRoutes = `-- name: GetDeathRoutes :many
+SELECT guild_id, min_level, channel_id FROM death_routes ORDER BY guild_id, min_level
 `
 
-type GetWorldsMapRow struct {
-	GuildID     string
-	World       string
-	TibiaGuilds []string
+func (q *Queries) GetDeathRoutes(ctx context.Context) ([]DeathRoute, error) {
+	rows, err := q.db.Query(ctx, getDeathRoutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeathRoute
+	for rows.Next() {
+		var i DeathRoute
+		if err := rows.Scan(&i.GuildID, &i.MinLevel, &i.ChannelID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetWorldsMap(ctx context.Context) ([]GetWorldsMapRow, error) {
-	rows, err := q.db.Query(ctx, getWorldsMap)
+const getDeathsPage = `-- name: GetDeathsPage :many
+SELECT id, name, world, level, reason, died_at, killers FROM deaths
+WHERE world = $1 AND died_at >= $2 AND id > $3
+  AND (cardinality($4::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($4::text[])
+  ))
+ORDER BY id
+LIMIT $5
+`
+
+type GetDeathsPageParams struct {
+	World      string
+	Since      pgtype.Timestamptz
+	AfterID    int64
+	GuildNames []string
+	PageSize   int32
+}
+
+type GetDeathsPageRow struct {
+	ID      int64
+	Name    string
+	World   string
+	Level   int32
+	Reason  string
+	DiedAt  pgtype.Timestamptz
+	Killers []string
+}
+
+// Pages through deaths by ID for exports. With guild_names, only deaths of
+// their members are returned.
+func (q *Queries) GetDeathsPage(ctx context.Context, arg GetDeathsPageParams) ([]GetDeathsPageRow, error) {
+	rows, err := q.db.Query(ctx, getDeathsPage,
+		arg.World,
+		arg.Since,
+		arg.AfterID,
+		arg.GuildNames,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorldsMapRow
+	var items []GetDeathsPageRow
 	for rows.Next() {
-		var i GetWorldsMapRow
-		if err := rows.Scan(&i.GuildID, &i.World, &i.TibiaGuilds); err != nil {
+		var i GetDeathsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.World,
+			&i.Level,
+			&i.Reason,
+			&i.DiedAt,
+			&i.Killers,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -168,36 +534,1850 @@ func (q *Queries) GetWorldsMap(ctx context.Context) ([]GetWorldsMapRow, error) {
 	return items, nil
 }
 
-const removeGuildFromConfig = `-- name: RemoveGuildFromConfig :exec
-UPDATE guild_configs
-SET tibia_guilds = array_remove(tibia_guilds, $2::text), updated_at = NOW()
-WHERE guild_id = $1
+const getDueFailedNotifications = `-- name: GetDueFailedNotifications :many
+SELECT id, guild_id, kind, payload, attempts, last_error, next_attempt_at, created_at FROM failed_notifications
+WHERE next_attempt_at <= $1
+ORDER BY id
+LIMIT $2
 `
 
-type RemoveGuildFromConfigParams struct {
-	GuildID    string
-	TibiaGuild string
+type GetDueFailedNotificationsParams struct {
+	Due      pgtype.Timestamptz
+	MaxItems int32
 }
 
-func (q *Queries) RemoveGuildFromConfig(ctx context.Context, arg RemoveGuildFromConfigParams) error {
-	_, err := q.db.Exec(ctx, removeGuildFromConfig, arg.GuildID, arg.TibiaGuild)
-	return err
+func (q *Queries) GetDueFailedNotifications(ctx context.Context, arg GetDueFailedNotificationsParams) ([]FailedNotification, error) {
+	rows, err := q.db.Query(ctx, getDueFailedNotifications, arg.Due, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FailedNotification
+	for rows.Next() {
+		var i FailedNotification
+		if err := rows.Scan(
+			&i.ID,
+			&i.GuildID,
+			&i.Kind,
+			&i.Payload,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const saveGuildWorld = `-- name: SaveGuildWorld :exec
-INSERT INTO guild_configs (guild_id, world, updated_at)
-VALUES ($1, $2, NOW())
-ON CONFLICT (guild_id) DO UPDATE
-SET world = EXCLUDED.world, updated_at = NOW()
+const getFragCountsSince = `-- name: GetFragCountsSince :many
+WITH members AS (
+    SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($3::text[])
+), frags AS (
+    SELECT killer::text AS name, COUNT(*) AS frags
+    FROM deaths, unnest(deaths.killers) AS killer
+    WHERE deaths.world = $1 AND deaths.died_at >= $2
+      AND deaths.name NOT IN (SELECT name FROM members)
+    GROUP BY killer
+), losses AS (
+    SELECT deaths.name, COUNT(*) AS deaths
+    FROM deaths
+    WHERE deaths.world = $1 AND deaths.died_at >= $2 AND cardinality(deaths.killers) > 0
+    GROUP BY deaths.name
+)
+SELECT COALESCE(f.name, l.name)::text AS name, COALESCE(f.frags, 0)::bigint AS frags, COALESCE(l.deaths, 0)::bigint AS deaths
+FROM frags f FULL JOIN losses l ON l.name = f.name
+WHERE ($4::text = '' OR lower(COALESCE(f.name, l.name)) = lower($4::text))
+  AND (COALESCE(f.name, l.name) IN (SELECT name FROM members)
+       OR (cardinality($3::text[]) = 0 AND (f.name IS NOT NULL OR $4::text <> '')))
+ORDER BY frags DESC, deaths, name
+LIMIT 25
 `
 
-type SaveGuildWorldParams struct {
-	GuildID string
-	World   string
+type GetFragCountsSinceParams struct {
+	World      string
+	Since      pgtype.Timestamptz
+	GuildNames []string
+	Name       string
 }
 
-func (q *Queries) SaveGuildWorld(ctx context.Context, arg SaveGuildWorldParams) error {
-	_, err := q.db.Exec(ctx, saveGuildWorld, arg.GuildID, arg.World)
+type GetFragCountsSinceRow struct {
+	Name   string
+	Frags  int64
+	Deaths int64
+}
+
+// Counts per character of world the deaths of others it took part in as a
+// player killer (frags) and its own deaths with a player among the killers.
+// With guild_names, only their members are counted and killing a fellow
+// member is no frag; without, only characters with a frag are. With name,
+// only that character is counted, whatever the case.
+func (q *Queries) GetFragCountsSince(ctx context.Context, arg GetFragCountsSinceParams) ([]GetFragCountsSinceRow, error) {
+	rows, err := q.db.Query(ctx, getFragCountsSince,
+		arg.World,
+		arg.Since,
+		arg.GuildNames,
+		arg.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFragCountsSinceRow
+	for rows.Next() {
+		var i GetFragCountsSinceRow
+		if err := rows.Scan(&i.Name, &i.Frags, &i.Deaths); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGuildConfig = `-- name: GetGuildConfig :one
+SELECT guild_id, world, tibia_guilds, updated_at, language, death_channel_id, level_channel_id, ping_role_id, ping_min_level, poll_interval_seconds, muted_until, ignored_players, low_level_deaths, last_notified_at, removed_at, house_channel_id, misc_channel_id, timezone, death_template, level_template, death_emoji, level_emoji, death_reaction, level_reaction, quiet_start, quiet_end, quiet_catch_up, quota_tibia_guilds, quota_ignored_players, premium, share_range, skill_channel_id, watched_players, mass_death_count, mass_death_window_minutes, broadcast_opt_out, min_level, death_location, level_downs, level_down_template, response_visibility, death_verbosity, quota_watched_players FROM guild_configs WHERE guild_id = $1
+`
+
+func (q *Queries) GetGuildConfig(ctx context.Context, guildID string) (GuildConfig, error) {
+	row := q.db.QueryRow(ctx, getGuildConfig, guildID)
+	var i GuildConfig
+	err := row.Scan(
+		&i.GuildID,
+		&i.World,
+		&i.TibiaGuilds,
+		&i.UpdatedAt,
+		&i.Language,
+		&i.DeathChannelID,
+		&i.LevelChannelID,
+		&i.PingRoleID,
+		&i.PingMinLevel,
+		&i.PollIntervalSeconds,
+		&i.MutedUntil,
+		&i.IgnoredPlayers,
+		&i.LowLevelDeaths,
+		&i.LastNotifiedAt,
+		&i.RemovedAt,
+		&i.HouseChannelID,
+		&i.MiscChannelID,
+		&i.Timezone,
+		&i.DeathTemplate,
+		&i.LevelTemplate,
+		&i.DeathEmoji,
+		&i.LevelEmoji,
+		&i.DeathReaction,
+		&i.LevelReaction,
+		&i.QuietStart,
+		&i.QuietEnd,
+		&i.QuietCatchUp,
+		&i.QuotaTibiaGuilds,
+		&i.QuotaIgnoredPlayers,
+		&i.Premium,
+		&i.ShareRange,
+		&i.SkillChannelID,
+		&i.WatchedPlayers,
+		&i.MassDeathCount,
+		&i.MassDeathWindowMinutes,
+		&i.BroadcastOptOut,
+		&i.MinLevel,
+		&i.DeathLocation,
+		&i.LevelDowns,
+		&i.LevelDownTemplate,
+		&i.ResponseVisibility,
+		&i.DeathVerbosity,
+		&i.QuotaWatchedPlayers,
+	)
+	return i, err
+}
+
+const getGuildDeathRoutes = `-- name: GetGuildDeathRoutes :many
+SELECT min_level, channel_id FROM death_routes WHERE guild_id = $1 ORDER BY min_level
+`
+
+type GetGuildDeathRoutesRow struct {
+	MinLevel  int32
+	ChannelID string
+}
+
+func (q *Queries) GetGuildDeathRoutes(ctx context.Context, guildID string) ([]GetGuildDeathRoutesRow, error) {
+	rows, err := q.db.Query(ctx, getGuildDeathRoutes, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGuildDeathRoutesRow
+	for rows.Next() {
+		var i GetGuildDeathRoutesRow
+		if err := rows.Scan(&i.MinLevel, &i.ChannelID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGuildFailedNotifications = `-- name: GetGuildFailedNotifications :many
+SELECT id, guild_id, kind, payload, attempts, last_error, next_attempt_at, created_at FROM failed_notifications
+WHERE guild_id = $1
+ORDER BY id
+`
+
+func (q *Queries) GetGuildFailedNotifications(ctx context.Context, guildID string) ([]FailedNotification, error) {
+	rows, err := q.db.Query(ctx, getGuildFailedNotifications, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FailedNotification
+	for rows.Next() {
+		var i FailedNotification
+		if err := rows.Scan(
+			&i.ID,
+			&i.GuildID,
+			&i.Kind,
+			&i.Payload,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGuildMemberCache = `-- name: GetGuildMemberCache :many
+SELECT guild_name, members, fetched_at FROM guild_member_cache ORDER BY guild_name
+`
+
+func (q *Queries) GetGuildMemberCache(ctx context.Context) ([]GuildMemberCache, error) {
+	rows, err := q.db.Query(ctx, getGuildMemberCache)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GuildMemberCache
+	for rows.Next() {
+		var i GuildMemberCache
+		if err := rows.Scan(&i.GuildName, &i.Members, &i.FetchedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGuildMemberNames = `-- name: GetGuildMemberNames :many
+SELECT name FROM guild_members WHERE guild_name = $1 ORDER BY name
+`
+
+func (q *Queries) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getGuildMemberNames, guildName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGuildSkillThresholds = `-- name: GetGuildSkillThresholds :many
+SELECT skill, min_value FROM skill_thresholds WHERE guild_id = $1 ORDER BY skill
+`
+
+type GetGuildSkillThresholdsRow struct {
+	Skill    string
+	MinValue int32
+}
+
+func (q *Queries) GetGuildSkillThresholds(ctx context.Context, guildID string) ([]GetGuildSkillThresholdsRow, error) {
+	rows, err := q.db.Query(ctx, getGuildSkillThresholds, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGuildSkillThresholdsRow
+	for rows.Next() {
+		var i GetGuildSkillThresholdsRow
+		if err := rows.Scan(&i.Skill, &i.MinValue); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHouseAuctions = `-- name: GetHouseAuctions :many
+SELECT world, house_id, name, town, current_bid, seen_at FROM house_auctions
+WHERE world = $1 AND seen_at >= $2
+ORDER BY house_id
+`
+
+type GetHouseAuctionsParams struct {
+	World     string
+	SeenSince pgtype.Timestamptz
+}
+
+func (q *Queries) GetHouseAuctions(ctx context.Context, arg GetHouseAuctionsParams) ([]HouseAuction, error) {
+	rows, err := q.db.Query(ctx, getHouseAuctions, arg.World, arg.SeenSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HouseAuction
+	for rows.Next() {
+		var i HouseAuction
+		if err := rows.Scan(
+			&i.World,
+			&i.HouseID,
+			&i.Name,
+			&i.Town,
+			&i.CurrentBid,
+			&i.SeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestGuildWar = `-- name: GetLatestGuildWar :one
+SELECT guild_name, started_at, ended_at FROM guild_wars
+WHERE guild_name = $1
+ORDER BY started_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestGuildWar(ctx context.Context, guildName string) (GuildWar, error) {
+	row := q.db.QueryRow(ctx, getLatestGuildWar, guildName)
+	var i GuildWar
+	err := row.Scan(&i.GuildName, &i.StartedAt, &i.EndedAt)
+	return i, err
+}
+
+const getLevelUpsPage = `-- name: GetLevelUpsPage :many
+SELECT id, name, world, old_level, new_level, reached_at FROM level_ups
+WHERE world = $1 AND reached_at >= $2 AND id > $3
+  AND (cardinality($4::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($4::text[])
+  ))
+ORDER BY id
+LIMIT $5
+`
+
+type GetLevelUpsPageParams struct {
+	World      string
+	Since      pgtype.Timestamptz
+	AfterID    int64
+	GuildNames []string
+	PageSize   int32
+}
+
+// Pages through level ups by ID for exports. With guild_names, only level
+// ups of their members are returned.
+func (q *Queries) GetLevelUpsPage(ctx context.Context, arg GetLevelUpsPageParams) ([]LevelUp, error) {
+	rows, err := q.db.Query(ctx, getLevelUpsPage,
+		arg.World,
+		arg.Since,
+		arg.AfterID,
+		arg.GuildNames,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LevelUp
+	for rows.Next() {
+		var i LevelUp
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.World,
+			&i.OldLevel,
+			&i.NewLevel,
+			&i.ReachedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLevelUpsSince = `-- name: GetLevelUpsSince :many
+SELECT name, world, old_level, new_level, reached_at FROM level_ups
+WHERE name = $1 AND reached_at >= $2
+ORDER BY reached_at
+`
+
+type GetLevelUpsSinceParams struct {
+	Name  string
+	Since pgtype.Timestamptz
+}
+
+type GetLevelUpsSinceRow struct {
+	Name      string
+	World     string
+	OldLevel  int32
+	NewLevel  int32
+	ReachedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetLevelUpsSince(ctx context.Context, arg GetLevelUpsSinceParams) ([]GetLevelUpsSinceRow, error) {
+	rows, err := q.db.Query(ctx, getLevelUpsSince, arg.Name, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLevelUpsSinceRow
+	for rows.Next() {
+		var i GetLevelUpsSinceRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.World,
+			&i.OldLevel,
+			&i.NewLevel,
+			&i.ReachedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOfflinePlayers = `-- name: GetOfflinePlayers :many
+SELECT name, level FROM players WHERE world = $1 AND name != ALL($2::text[])
+`
+
+type GetOfflinePlayersParams struct {
+	World       string
+	OnlineNames []string
+}
+
+type GetOfflinePlayersRow struct {
+	Name  string
+	Level int32
+}
+
+func (q *Queries) GetOfflinePlayers(ctx context.Context, arg GetOfflinePlayersParams) ([]GetOfflinePlayersRow, error) {
+	rows, err := q.db.Query(ctx, getOfflinePlayers, arg.World, arg.OnlineNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOfflinePlayersRow
+	for rows.Next() {
+		var i GetOfflinePlayersRow
+		if err := rows.Scan(&i.Name, &i.Level); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOnlineCountsSince = `-- name: GetOnlineCountsSince :many
+SELECT seen_hour, COUNT(*) AS players
+FROM online_presence
+WHERE world = $1 AND seen_hour >= $2
+  AND (cardinality($3::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($3::text[])
+  ))
+GROUP BY seen_hour
+ORDER BY seen_hour
+`
+
+type GetOnlineCountsSinceParams struct {
+	World      string
+	Since      pgtype.Timestamptz
+	GuildNames []string
+}
+
+type GetOnlineCountsSinceRow struct {
+	SeenHour pgtype.Timestamptz
+	Players  int64
+}
+
+// Counts characters online on world per hour. With guild_names, only their
+// members count.
+func (q *Queries) GetOnlineCountsSince(ctx context.Context, arg GetOnlineCountsSinceParams) ([]GetOnlineCountsSinceRow, error) {
+	rows, err := q.db.Query(ctx, getOnlineCountsSince, arg.World, arg.Since, arg.GuildNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOnlineCountsSinceRow
+	for rows.Next() {
+		var i GetOnlineCountsSinceRow
+		if err := rows.Scan(&i.SeenHour, &i.Players); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOnlineTimeSince = `-- name: GetOnlineTimeSince :one
+SELECT COALESCE(SUM(EXTRACT(EPOCH FROM last_seen_at - GREATEST(started_at, $2))), 0)::bigint AS seconds
+FROM online_sessions
+WHERE name = $1 AND last_seen_at >= $2
+`
+
+type GetOnlineTimeSinceParams struct {
+	Name  string
+	Since pgtype.Timestamptz
+}
+
+// Sums how long the character was online since the given time, from the
+// first to the last sighting of each session.
+func (q *Queries) GetOnlineTimeSince(ctx context.Context, arg GetOnlineTimeSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getOnlineTimeSince, arg.Name, arg.Since)
+	var seconds int64
+	err := row.Scan(&seconds)
+	return seconds, err
+}
+
+const getPlayerSkills = `-- name: GetPlayerSkills :many
+SELECT name, value FROM player_skills WHERE world = $1 AND skill = $2
+`
+
+type GetPlayerSkillsParams struct {
+	World string
+	Skill string
+}
+
+type GetPlayerSkillsRow struct {
+	Name  string
+	Value int32
+}
+
+func (q *Queries) GetPlayerSkills(ctx context.Context, arg GetPlayerSkillsParams) ([]GetPlayerSkillsRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerSkills, arg.World, arg.Skill)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerSkillsRow
+	for rows.Next() {
+		var i GetPlayerSkillsRow
+		if err := rows.Scan(&i.Name, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayersByPrefix = `-- name: GetPlayersByPrefix :many
+SELECT name FROM players
+WHERE world = $1 AND lower(name) LIKE lower($2::text) || '%' ESCAPE '\'
+ORDER BY lower(name)
+LIMIT $3
+`
+
+type GetPlayersByPrefixParams struct {
+	World      string
+	Prefix     string
+	MaxResults int32
+}
+
+// Case-insensitive prefix search backed by idx_players_name_trgm. The prefix
+// must have \, % and _ escaped with a backslash.
+func (q *Queries) GetPlayersByPrefix(ctx context.Context, arg GetPlayersByPrefixParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getPlayersByPrefix, arg.World, arg.Prefix, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayersLevels = `-- name: GetPlayersLevels :many
+SELECT name, level FROM players WHERE world = $1
+`
+
+type GetPlayersLevelsRow struct {
+	Name  string
+	Level int32
+}
+
+func (q *Queries) GetPlayersLevels(ctx context.Context, world string) ([]GetPlayersLevelsRow, error) {
+	rows, err := q.db.Query(ctx, getPlayersLevels, world)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayersLevelsRow
+	for rows.Next() {
+		var i GetPlayersLevelsRow
+		if err := rows.Scan(&i.Name, &i.Level); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSkillThresholds = `-- name: GetSkillThresholds :many
+SELECT guild_id, skill, min_value FROM skill_thresholds ORDER BY guild_id, skill
+`
+
+func (q *Queries) GetSkillThresholds(ctx context.Context) ([]SkillThreshold, error) {
+	rows, err := q.db.Query(ctx, getSkillThresholds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SkillThreshold
+	for rows.Next() {
+		var i SkillThreshold
+		if err := rows.Scan(&i.GuildID, &i.Skill, &i.MinValue); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStoppedGuildConfig = `-- name: GetStoppedGuildConfig :one
+SELECT guild_id, config, stopped_at FROM stopped_guild_configs WHERE guild_id = $1
+`
+
+func (q *Queries) GetStoppedGuildConfig(ctx context.Context, guildID string) (StoppedGuildConfig, error) {
+	row := q.db.QueryRow(ctx, getStoppedGuildConfig, guildID)
+	var i StoppedGuildConfig
+	err := row.Scan(&i.GuildID, &i.Config, &i.StoppedAt)
+	return i, err
+}
+
+const getTopKillersSince = `-- name: GetTopKillersSince :many
+SELECT killer::text AS killer, COUNT(*) AS kills
+FROM deaths, unnest(deaths.killers) AS killer
+WHERE deaths.world = $1 AND deaths.died_at >= $2
+  AND (cardinality($3::text[]) = 0 OR deaths.name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($3::text[])
+  ))
+  AND killer NOT IN (SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY($3::text[]))
+GROUP BY killer
+ORDER BY kills DESC, killer
+LIMIT 10
+`
+
+type GetTopKillersSinceParams struct {
+	World      string
+	Since      pgtype.Timestamptz
+	GuildNames []string
+}
+
+type GetTopKillersSinceRow struct {
+	Killer string
+	Kills  int64
+}
+
+// Counts deaths per player killer. With guild_names, only deaths of their
+// members count and members killing each other are left out.
+func (q *Queries) GetTopKillersSince(ctx context.Context, arg GetTopKillersSinceParams) ([]GetTopKillersSinceRow, error) {
+	rows, err := q.db.Query(ctx, getTopKillersSince, arg.World, arg.Since, arg.GuildNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTopKillersSinceRow
+	for rows.Next() {
+		var i GetTopKillersSinceRow
+		if err := rows.Scan(&i.Killer, &i.Kills); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorldOnlineCountsSince = `-- name: GetWorldOnlineCountsSince :many
+SELECT hour, ROUND(players)::int AS players FROM (
+    SELECT h.hour, h.avg_players::float8 AS players
+    FROM online_counts_hourly h
+    WHERE h.world = $1 AND h.hour >= $2
+    UNION ALL
+    SELECT date_trunc('hour', c.sampled_at, 'UTC') AS hour, AVG(c.players)::float8 AS players
+    FROM online_counts c
+    WHERE c.world = $1 AND c.sampled_at >= $2
+    GROUP BY 1
+) counts
+ORDER BY hour
+`
+
+type GetWorldOnlineCountsSinceParams struct {
+	World string
+	Since pgtype.Timestamptz
+}
+
+type GetWorldOnlineCountsSinceRow struct {
+	Hour    pgtype.Timestamptz
+	Players int32
+}
+
+// Averages the characters online on world per hour, from the hourly counts
+// and the cycle counts not yet downsampled.
+func (q *Queries) GetWorldOnlineCountsSince(ctx context.Context, arg GetWorldOnlineCountsSinceParams) ([]GetWorldOnlineCountsSinceRow, error) {
+	rows, err := q.db.Query(ctx, getWorldOnlineCountsSince, arg.World, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorldOnlineCountsSinceRow
+	for rows.Next() {
+		var i GetWorldOnlineCountsSinceRow
+		if err := rows.Scan(&i.Hour, &i.Players); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorldsMap = `-- name: GetWorldsMap :many
+SELECT guild_id, world, tibia_guilds, language, death_channel_id, level_channel_id, ping_role_id, ping_min_level, poll_interval_seconds, muted_until, ignored_players, low_level_deaths, last_notified_at, house_channel_id, misc_channel_id, timezone, death_template, level_template, death_emoji, level_emoji, death_reaction, level_reaction, quiet_start, quiet_end, quiet_catch_up, quota_tibia_guilds, quota_ignored_players, premium, share_range, skill_channel_id, watched_players, mass_death_count, mass_death_window_minutes, broadcast_opt_out, min_level, death_location, level_downs, level_down_template, response_visibility, death_verbosity, quota_watched_players FROM guild_configs
+WHERE removed_at IS NULL
+`
+
+type GetWorldsMapRow struct {
+	GuildID                string
+	World                  string
+	TibiaGuilds            []string
+	Language               string
+	DeathChannelID         string
+	LevelChannelID         string
+	PingRoleID             string
+	PingMinLevel           int32
+	PollIntervalSeconds    int32
+	MutedUntil             pgtype.Timestamptz
+	IgnoredPlayers         []string
+	LowLevelDeaths         bool
+	LastNotifiedAt         pgtype.Timestamptz
+	HouseChannelID         string
+	MiscChannelID          string
+	Timezone               string
+	DeathTemplate          string
+	LevelTemplate          string
+	DeathEmoji             string
+	LevelEmoji             string
+	DeathReaction          bool
+	LevelReaction          bool
+	QuietStart             int32
+	QuietEnd               int32
+	QuietCatchUp           bool
+	QuotaTibiaGuilds       int32
+	QuotaIgnoredPlayers    int32
+	Premium                bool
+	ShareRange             bool
+	SkillChannelID         string
+	WatchedPlayers         []string
+	MassDeathCount         int32
+	MassDeathWindowMinutes int32
+	BroadcastOptOut        bool
+	MinLevel               int32
+	DeathLocation          bool
+	LevelDowns             bool
+	LevelDownTemplate      string
+	ResponseVisibility     string
+	DeathVerbosity         string
+	QuotaWatchedPlayers    int32
+}
+
+func (q *Queries) GetWorldsMap(ctx context.Context) ([]GetWorldsMapRow, error) {
+	rows, err := q.db.Query(ctx, getWorldsMap)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorldsMapRow
+	for rows.Next() {
+		var i GetWorldsMapRow
+		if err := rows.Scan(
+			&i.GuildID,
+			&i.World,
+			&i.TibiaGuilds,
+			&i.Language,
+			&i.DeathChannelID,
+			&i.LevelChannelID,
+			&i.PingRoleID,
+			&i.PingMinLevel,
+			&i.PollIntervalSeconds,
+			&i.MutedUntil,
+			&i.IgnoredPlayers,
+			&i.LowLevelDeaths,
+			&i.LastNotifiedAt,
+			&i.HouseChannelID,
+			&i.MiscChannelID,
+			&i.Timezone,
+			&i.DeathTemplate,
+			&i.LevelTemplate,
+			&i.DeathEmoji,
+			&i.LevelEmoji,
+			&i.DeathReaction,
+			&i.LevelReaction,
+			&i.QuietStart,
+			&i.QuietEnd,
+			&i.QuietCatchUp,
+			&i.QuotaTibiaGuilds,
+			&i.QuotaIgnoredPlayers,
+			&i.Premium,
+			&i.ShareRange,
+			&i.SkillChannelID,
+			&i.WatchedPlayers,
+			&i.MassDeathCount,
+			&i.MassDeathWindowMinutes,
+			&i.BroadcastOptOut,
+			&i.MinLevel,
+			&i.DeathLocation,
+			&i.LevelDowns,
+			&i.LevelDownTemplate,
+			&i.ResponseVisibility,
+			&i.DeathVerbosity,
+			&i.QuotaWatchedPlayers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markGuildRemoved = `-- name: MarkGuildRemoved :exec
+UPDATE guild_configs SET removed_at = $2 WHERE guild_id = $1 AND removed_at IS NULL
+`
+
+type MarkGuildRemovedParams struct {
+	GuildID   string
+	RemovedAt pgtype.Timestamptz
+}
+
+func (q *Queries) MarkGuildRemoved(ctx context.Context, arg MarkGuildRemovedParams) error {
+	_, err := q.db.Exec(ctx, markGuildRemoved, arg.GuildID, arg.RemovedAt)
+	return err
+}
+
+const purgeGuildData = `-- name: PurgeGuildData :one
+WITH config AS (
+    DELETE FROM guild_configs WHERE guild_configs.guild_id = $1
+    RETURNING world, tibia_guilds
+), stopped AS (
+    DELETE FROM stopped_guild_configs WHERE stopped_guild_configs.guild_id = $1
+    RETURNING stopped_guild_configs.guild_id
+), notifications AS (
+    DELETE FROM failed_notifications WHERE failed_notifications.guild_id = $1
+    RETURNING failed_notifications.id
+), members AS (
+    DELETE FROM guild_members USING config
+    WHERE guild_members.guild_name = ANY(config.tibia_guilds)
+      AND NOT EXISTS (
+          SELECT 1 FROM guild_configs other
+          WHERE other.guild_id <> $1 AND guild_members.guild_name = ANY(other.tibia_guilds)
+      )
+    RETURNING guild_members.name
+), world_deaths AS (
+    DELETE FROM deaths USING config
+    WHERE deaths.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> $1 AND other.world = config.world)
+    RETURNING deaths.id
+), world_players AS (
+    DELETE FROM players USING config
+    WHERE players.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> $1 AND other.world = config.world)
+    RETURNING players.name
+), world_level_ups AS (
+    DELETE FROM level_ups USING config
+    WHERE level_ups.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> $1 AND other.world = config.world)
+    RETURNING level_ups.id
+), world_skills AS (
+    DELETE FROM player_skills USING config
+    WHERE player_skills.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> $1 AND other.world = config.world)
+    RETURNING player_skills.name
+)
+SELECT
+    (SELECT COUNT(*) FROM config) + (SELECT COUNT(*) FROM stopped) AS configs,
+    (SELECT COUNT(*) FROM notifications) AS notifications,
+    (SELECT COUNT(*) FROM members) AS guild_members,
+    (SELECT COUNT(*) FROM world_deaths) AS deaths,
+    (SELECT COUNT(*) FROM world_players) AS players,
+    (SELECT COUNT(*) FROM world_level_ups) AS level_ups
+`
+
+type PurgeGuildDataRow struct {
+	Configs       int64
+	Notifications int64
+	GuildMembers  int64
+	Deaths        int64
+	Players       int64
+	LevelUps      int64
+}
+
+func (q *Queries) PurgeGuildData(ctx context.Context, guildID string) (PurgeGuildDataRow, error) {
+	row := q.db.QueryRow(ctx, purgeGuildData, guildID)
+	var i PurgeGuildDataRow
+	err := row.Scan(
+		&i.Configs,
+		&i.Notifications,
+		&i.GuildMembers,
+		&i.Deaths,
+		&i.Players,
+		&i.LevelUps,
+	)
+	return i, err
+}
+
+const recordDeath = `-- name: RecordDeath :exec
+INSERT INTO deaths (name, world, level, reason, died_at, killers)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (name, died_at) DO NOTHING
+`
+
+type RecordDeathParams struct {
+	Name    string
+	World   string
+	Level   int32
+	Reason  string
+	DiedAt  pgtype.Timestamptz
+	Killers []string
+}
+
+func (q *Queries) RecordDeath(ctx context.Context, arg RecordDeathParams) error {
+	_, err := q.db.Exec(ctx, recordDeath,
+		arg.Name,
+		arg.World,
+		arg.Level,
+		arg.Reason,
+		arg.DiedAt,
+		arg.Killers,
+	)
+	return err
+}
+
+const recordLevelUp = `-- name: RecordLevelUp :exec
+INSERT INTO level_ups (name, world, old_level, new_level, reached_at)
+VALUES ($1, $2, $3, $4, COALESCE($5, NOW()))
+`
+
+type RecordLevelUpParams struct {
+	Name      string
+	World     string
+	OldLevel  int32
+	NewLevel  int32
+	ReachedAt pgtype.Timestamptz
+}
+
+func (q *Queries) RecordLevelUp(ctx context.Context, arg RecordLevelUpParams) error {
+	_, err := q.db.Exec(ctx, recordLevelUp,
+		arg.Name,
+		arg.World,
+		arg.OldLevel,
+		arg.NewLevel,
+		arg.ReachedAt,
+	)
+	return err
+}
+
+const recordOnlineCount = `-- name: RecordOnlineCount :exec
+INSERT INTO online_counts (world, sampled_at, players)
+VALUES ($1, $2, $3)
+ON CONFLICT (world, sampled_at) DO UPDATE SET players = EXCLUDED.players
+`
+
+type RecordOnlineCountParams struct {
+	World     string
+	SampledAt pgtype.Timestamptz
+	Players   int32
+}
+
+func (q *Queries) RecordOnlineCount(ctx context.Context, arg RecordOnlineCountParams) error {
+	_, err := q.db.Exec(ctx, recordOnlineCount, arg.World, arg.SampledAt, arg.Players)
+	return err
+}
+
+const recordOnlinePresence = `-- name: RecordOnlinePresence :exec
+INSERT INTO online_presence (world, seen_hour, name)
+SELECT $1::text, $2::timestamptz, unnest($3::text[])
+ON CONFLICT DO NOTHING
+`
+
+type RecordOnlinePresenceParams struct {
+	World    string
+	SeenHour pgtype.Timestamptz
+	Names    []string
+}
+
+func (q *Queries) RecordOnlinePresence(ctx context.Context, arg RecordOnlinePresenceParams) error {
+	_, err := q.db.Exec(ctx, recordOnlinePresence, arg.World, arg.SeenHour, arg.Names)
+	return err
+}
+
+const recordOnlineSessions = `-- name: RecordOnlineSessions :exec
+WITH seen AS (
+    SELECT unnest($1::text[]) AS name
+), extended AS (
+    UPDATE online_sessions s SET last_seen_at = $2
+    FROM seen
+    WHERE s.world = $3 AND s.name = seen.name AND s.last_seen_at >= $4
+    RETURNING s.name
+)
+INSERT INTO online_sessions (world, name, started_at, last_seen_at)
+SELECT $3, seen.name, $2, $2
+FROM seen
+WHERE seen.name NOT IN (SELECT name FROM extended)
+ON CONFLICT DO NOTHING
+`
+
+type RecordOnlineSessionsParams struct {
+	Names     []string
+	SeenAt    pgtype.Timestamptz
+	World     string
+	OpenSince pgtype.Timestamptz
+}
+
+// Extends the sessions of characters last seen at or after open_since to
+// seen_at and starts a session for every other character in names.
+func (q *Queries) RecordOnlineSessions(ctx context.Context, arg RecordOnlineSessionsParams) error {
+	_, err := q.db.Exec(ctx, recordOnlineSessions,
+		arg.Names,
+		arg.SeenAt,
+		arg.World,
+		arg.OpenSince,
+	)
+	return err
+}
+
+const removeGuildFromConfig = `-- name: RemoveGuildFromConfig :exec
+UPDATE guild_configs
+SET tibia_guilds = array_remove(tibia_guilds, $2::text), updated_at = NOW()
+WHERE guild_id = $1
+`
+
+type RemoveGuildFromConfigParams struct {
+	GuildID    string
+	TibiaGuild string
+}
+
+func (q *Queries) RemoveGuildFromConfig(ctx context.Context, arg RemoveGuildFromConfigParams) error {
+	_, err := q.db.Exec(ctx, removeGuildFromConfig, arg.GuildID, arg.TibiaGuild)
+	return err
+}
+
+const removeGuildMembers = `-- name: RemoveGuildMembers :exec
+DELETE FROM guild_members WHERE guild_name = $1 AND name = ANY($2::text[])
+`
+
+type RemoveGuildMembersParams struct {
+	GuildName string
+	Names     []string
+}
+
+func (q *Queries) RemoveGuildMembers(ctx context.Context, arg RemoveGuildMembersParams) error {
+	_, err := q.db.Exec(ctx, removeGuildMembers, arg.GuildName, arg.Names)
+	return err
+}
+
+const removeIgnoredPlayer = `-- name: RemoveIgnoredPlayer :exec
+UPDATE guild_configs
+SET ignored_players = ARRAY(SELECT p FROM unnest(ignored_players) AS p WHERE lower(p) <> lower($2::text)), updated_at = NOW()
+WHERE guild_id = $1
+`
+
+type RemoveIgnoredPlayerParams struct {
+	GuildID string
+	Name    string
+}
+
+func (q *Queries) RemoveIgnoredPlayer(ctx context.Context, arg RemoveIgnoredPlayerParams) error {
+	_, err := q.db.Exec(ctx, removeIgnoredPlayer, arg.GuildID, arg.Name)
+	return err
+}
+
+const removeWatchedPlayer = `-- name: RemoveWatchedPlayer :exec
+UPDATE guild_configs
+SET watched_players = ARRAY(SELECT p FROM unnest(watched_players) AS p WHERE lower(p) <> lower($2::text)), updated_at = NOW()
+WHERE guild_id = $1
+`
+
+type RemoveWatchedPlayerParams struct {
+	GuildID string
+	Name    string
+}
+
+func (q *Queries) RemoveWatchedPlayer(ctx context.Context, arg RemoveWatchedPlayerParams) error {
+	_, err := q.db.Exec(ctx, removeWatchedPlayer, arg.GuildID, arg.Name)
+	return err
+}
+
+const renamePlayer = `-- name: RenamePlayer :exec
+WITH moved_player AS (
+    UPDATE players SET name = $1::text, updated_at = NOW()
+    WHERE players.name = $2::text
+      AND NOT EXISTS (SELECT 1 FROM players p WHERE p.name = $1::text)
+    RETURNING players.name
+), stale_player AS (
+    DELETE FROM players
+    WHERE players.name = $2::text
+      AND EXISTS (SELECT 1 FROM players p WHERE p.name = $1::text)
+    RETURNING players.name
+), moved_deaths AS (
+    UPDATE deaths SET name = $1::text
+    WHERE deaths.name = $2::text
+      AND NOT EXISTS (SELECT 1 FROM deaths d WHERE d.name = $1::text AND d.died_at = deaths.died_at)
+    RETURNING deaths.id
+), moved_level_ups AS (
+    UPDATE level_ups SET name = $1::text
+    WHERE level_ups.name = $2::text
+    RETURNING level_ups.id
+), moved_members AS (
+    UPDATE guild_members SET name = $1::text
+    WHERE guild_members.name = $2::text
+      AND NOT EXISTS (SELECT 1 FROM guild_members m WHERE m.guild_name = guild_members.guild_name AND m.name = $1::text)
+    RETURNING guild_members.name
+), stale_members AS (
+    DELETE FROM guild_members
+    WHERE guild_members.name = $2::text
+      AND EXISTS (SELECT 1 FROM guild_members m WHERE m.guild_name = guild_members.guild_name AND m.name = $1::text)
+    RETURNING guild_members.name
+)
+UPDATE guild_configs
+SET ignored_players = array_replace(ignored_players, $2::text, $1::text),
+    watched_players = array_replace(watched_players, $2::text, $1::text),
+    updated_at = NOW()
+WHERE $2::text = ANY(ignored_players) OR $2::text = ANY(watched_players)
+`
+
+type RenamePlayerParams struct {
+	NewName string
+	OldName string
+}
+
+// Moves everything stored under a character's old name to its new one. Rows
+// already stored under the new name win over their old-name duplicates.
+func (q *Queries) RenamePlayer(ctx context.Context, arg RenamePlayerParams) error {
+	_, err := q.db.Exec(ctx, renamePlayer, arg.NewName, arg.OldName)
+	return err
+}
+
+const replaceHouseAuctions = `-- name: ReplaceHouseAuctions :exec
+WITH ended AS (
+    DELETE FROM house_auctions
+    WHERE house_auctions.world = $1 AND NOT (house_auctions.house_id = ANY($2::int[]))
+)
+INSERT INTO house_auctions (world, house_id, name, town, current_bid, seen_at)
+SELECT $1::text, unnest($2::int[]), unnest($3::text[]), unnest($4::text[]), unnest($5::bigint[]), NOW()
+ON CONFLICT (world, house_id) DO UPDATE
+SET name = EXCLUDED.name, town = EXCLUDED.town, current_bid = EXCLUDED.current_bid, seen_at = NOW()
+`
+
+type ReplaceHouseAuctionsParams struct {
+	World       string
+	HouseIds    []int32
+	Names       []string
+	Towns       []string
+	CurrentBids []int64
+}
+
+// Stores the auctions currently running on a world and forgets the rest.
+func (q *Queries) ReplaceHouseAuctions(ctx context.Context, arg ReplaceHouseAuctionsParams) error {
+	_, err := q.db.Exec(ctx, replaceHouseAuctions,
+		arg.World,
+		arg.HouseIds,
+		arg.Names,
+		arg.Towns,
+		arg.CurrentBids,
+	)
+	return err
+}
+
+const rescheduleFailedNotification = `-- name: RescheduleFailedNotification :exec
+UPDATE failed_notifications
+SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+WHERE id = $1
+`
+
+type RescheduleFailedNotificationParams struct {
+	ID            int64
+	LastError     string
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) RescheduleFailedNotification(ctx context.Context, arg RescheduleFailedNotificationParams) error {
+	_, err := q.db.Exec(ctx, rescheduleFailedNotification, arg.ID, arg.LastError, arg.NextAttemptAt)
+	return err
+}
+
+const restoreGuildConfig = `-- name: RestoreGuildConfig :execresult
+UPDATE guild_configs SET removed_at = NULL WHERE guild_id = $1 AND removed_at IS NOT NULL
+`
+
+func (q *Queries) RestoreGuildConfig(ctx context.Context, guildID string) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, restoreGuildConfig, guildID)
+}
+
+const saveGuildMemberCache = `-- name: SaveGuildMemberCache :exec
+INSERT INTO guild_member_cache (guild_name, members, fetched_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_name) DO UPDATE
+SET members = EXCLUDED.members, fetched_at = EXCLUDED.fetched_at
+`
+
+type SaveGuildMemberCacheParams struct {
+	GuildName string
+	Members   []string
+	FetchedAt pgtype.Timestamptz
+}
+
+func (q *Queries) SaveGuildMemberCache(ctx context.Context, arg SaveGuildMemberCacheParams) error {
+	_, err := q.db.Exec(ctx, saveGuildMemberCache, arg.GuildName, arg.Members, arg.FetchedAt)
+	return err
+}
+
+const saveGuildWorld = `-- name: SaveGuildWorld :exec
+INSERT INTO guild_configs (guild_id, world, updated_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET world = EXCLUDED.world, updated_at = NOW()
+`
+
+type SaveGuildWorldParams struct {
+	GuildID string
+	World   string
+}
+
+func (q *Queries) SaveGuildWorld(ctx context.Context, arg SaveGuildWorldParams) error {
+	_, err := q.db.Exec(ctx, saveGuildWorld, arg.GuildID, arg.World)
+	return err
+}
+
+const saveStoppedGuildConfig = `-- name: SaveStoppedGuildConfig :exec
+INSERT INTO stopped_guild_configs (guild_id, config, stopped_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id) DO UPDATE
+SET config = EXCLUDED.config, stopped_at = EXCLUDED.stopped_at
+`
+
+type SaveStoppedGuildConfigParams struct {
+	GuildID   string
+	Config    []byte
+	StoppedAt pgtype.Timestamptz
+}
+
+func (q *Queries) SaveStoppedGuildConfig(ctx context.Context, arg SaveStoppedGuildConfigParams) error {
+	_, err := q.db.Exec(ctx, saveStoppedGuildConfig, arg.GuildID, arg.Config, arg.StoppedAt)
+	return err
+}
+
+const setDeathRoute = `-- name: SetDeathRoute :exec
+INSERT INTO death_routes (guild_id, min_level, channel_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id, min_level) DO UPDATE
+SET channel_id = EXCLUDED.channel_id
+`
+
+type SetDeathRouteParams struct {
+	GuildID   string
+	MinLevel  int32
+	ChannelID string
+}
+
+func (q *Queries) SetDeathRoute(ctx context.Context, arg SetDeathRouteParams) error {
+	_, err := q.db.Exec(ctx, setDeathRoute, arg.GuildID, arg.MinLevel, arg.ChannelID)
+	return err
+}
+
+const setGuildBroadcastOptOut = `-- name: SetGuildBroadcastOptOut :exec
+INSERT INTO guild_configs (guild_id, world, broadcast_opt_out, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET broadcast_opt_out = EXCLUDED.broadcast_opt_out, updated_at = NOW()
+`
+
+type SetGuildBroadcastOptOutParams struct {
+	GuildID         string
+	BroadcastOptOut bool
+}
+
+func (q *Queries) SetGuildBroadcastOptOut(ctx context.Context, arg SetGuildBroadcastOptOutParams) error {
+	_, err := q.db.Exec(ctx, setGuildBroadcastOptOut, arg.GuildID, arg.BroadcastOptOut)
+	return err
+}
+
+const setGuildDeathChannel = `-- name: SetGuildDeathChannel :exec
+INSERT INTO guild_configs (guild_id, world, death_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_channel_id = EXCLUDED.death_channel_id, updated_at = NOW()
+`
+
+type SetGuildDeathChannelParams struct {
+	GuildID        string
+	DeathChannelID string
+}
+
+func (q *Queries) SetGuildDeathChannel(ctx context.Context, arg SetGuildDeathChannelParams) error {
+	_, err := q.db.Exec(ctx, setGuildDeathChannel, arg.GuildID, arg.DeathChannelID)
+	return err
+}
+
+const setGuildDeathEmoji = `-- name: SetGuildDeathEmoji :exec
+INSERT INTO guild_configs (guild_id, world, death_emoji, death_reaction, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_emoji = EXCLUDED.death_emoji, death_reaction = EXCLUDED.death_reaction, updated_at = NOW()
+`
+
+type SetGuildDeathEmojiParams struct {
+	GuildID       string
+	DeathEmoji    string
+	DeathReaction bool
+}
+
+func (q *Queries) SetGuildDeathEmoji(ctx context.Context, arg SetGuildDeathEmojiParams) error {
+	_, err := q.db.Exec(ctx, setGuildDeathEmoji, arg.GuildID, arg.DeathEmoji, arg.DeathReaction)
+	return err
+}
+
+const setGuildDeathLocation = `-- name: SetGuildDeathLocation :exec
+INSERT INTO guild_configs (guild_id, world, death_location, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_location = EXCLUDED.death_location, updated_at = NOW()
+`
+
+type SetGuildDeathLocationParams struct {
+	GuildID       string
+	DeathLocation bool
+}
+
+func (q *Queries) SetGuildDeathLocation(ctx context.Context, arg SetGuildDeathLocationParams) error {
+	_, err := q.db.Exec(ctx, setGuildDeathLocation, arg.GuildID, arg.DeathLocation)
+	return err
+}
+
+const setGuildDeathTemplate = `-- name: SetGuildDeathTemplate :exec
+INSERT INTO guild_configs (guild_id, world, death_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_template = EXCLUDED.death_template, updated_at = NOW()
+`
+
+type SetGuildDeathTemplateParams struct {
+	GuildID       string
+	DeathTemplate string
+}
+
+func (q *Queries) SetGuildDeathTemplate(ctx context.Context, arg SetGuildDeathTemplateParams) error {
+	_, err := q.db.Exec(ctx, setGuildDeathTemplate, arg.GuildID, arg.DeathTemplate)
+	return err
+}
+
+const setGuildDeathVerbosity = `-- name: SetGuildDeathVerbosity :exec
+INSERT INTO guild_configs (guild_id, world, death_verbosity, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_verbosity = EXCLUDED.death_verbosity, updated_at = NOW()
+`
+
+type SetGuildDeathVerbosityParams struct {
+	GuildID        string
+	DeathVerbosity string
+}
+
+func (q *Queries) SetGuildDeathVerbosity(ctx context.Context, arg SetGuildDeathVerbosityParams) error {
+	_, err := q.db.Exec(ctx, setGuildDeathVerbosity, arg.GuildID, arg.DeathVerbosity)
+	return err
+}
+
+const setGuildHouseChannel = `-- name: SetGuildHouseChannel :exec
+INSERT INTO guild_configs (guild_id, world, house_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET house_channel_id = EXCLUDED.house_channel_id, updated_at = NOW()
+`
+
+type SetGuildHouseChannelParams struct {
+	GuildID        string
+	HouseChannelID string
+}
+
+func (q *Queries) SetGuildHouseChannel(ctx context.Context, arg SetGuildHouseChannelParams) error {
+	_, err := q.db.Exec(ctx, setGuildHouseChannel, arg.GuildID, arg.HouseChannelID)
+	return err
+}
+
+const setGuildLanguage = `-- name: SetGuildLanguage :exec
+INSERT INTO guild_configs (guild_id, world, language, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET language = EXCLUDED.language, updated_at = NOW()
+`
+
+type SetGuildLanguageParams struct {
+	GuildID  string
+	Language string
+}
+
+func (q *Queries) SetGuildLanguage(ctx context.Context, arg SetGuildLanguageParams) error {
+	_, err := q.db.Exec(ctx, setGuildLanguage, arg.GuildID, arg.Language)
+	return err
+}
+
+const setGuildLevelChannel = `-- name: SetGuildLevelChannel :exec
+INSERT INTO guild_configs (guild_id, world, level_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_channel_id = EXCLUDED.level_channel_id, updated_at = NOW()
+`
+
+type SetGuildLevelChannelParams struct {
+	GuildID        string
+	LevelChannelID string
+}
+
+func (q *Queries) SetGuildLevelChannel(ctx context.Context, arg SetGuildLevelChannelParams) error {
+	_, err := q.db.Exec(ctx, setGuildLevelChannel, arg.GuildID, arg.LevelChannelID)
+	return err
+}
+
+const setGuildLastNotified = `-- name: SetGuildLastNotified :exec
+UPDATE guild_configs SET last_notified_at = $2 WHERE guild_id = $1
+`
+
+type SetGuildLastNotifiedParams struct {
+	GuildID        string
+	LastNotifiedAt pgtype.Timestamptz
+}
+
+func (q *Queries) SetGuildLastNotified(ctx context.Context, arg SetGuildLastNotifiedParams) error {
+	_, err := q.db.Exec(ctx, setGuildLastNotified, arg.GuildID, arg.LastNotifiedAt)
+	return err
+}
+
+const setGuildLevelDownTemplate = `-- name: SetGuildLevelDownTemplate :exec
+INSERT INTO guild_configs (guild_id, world, level_down_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_down_template = EXCLUDED.level_down_template, updated_at = NOW()
+`
+
+type SetGuildLevelDownTemplateParams struct {
+	GuildID           string
+	LevelDownTemplate string
+}
+
+func (q *Queries) SetGuildLevelDownTemplate(ctx context.Context, arg SetGuildLevelDownTemplateParams) error {
+	_, err := q.db.Exec(ctx, setGuildLevelDownTemplate, arg.GuildID, arg.LevelDownTemplate)
+	return err
+}
+
+const setGuildLevelDowns = `-- name: SetGuildLevelDowns :exec
+INSERT INTO guild_configs (guild_id, world, level_downs, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_downs = EXCLUDED.level_downs, updated_at = NOW()
+`
+
+type SetGuildLevelDownsParams struct {
+	GuildID    string
+	LevelDowns bool
+}
+
+func (q *Queries) SetGuildLevelDowns(ctx context.Context, arg SetGuildLevelDownsParams) error {
+	_, err := q.db.Exec(ctx, setGuildLevelDowns, arg.GuildID, arg.LevelDowns)
+	return err
+}
+
+const setGuildLevelEmoji = `-- name: SetGuildLevelEmoji :exec
+INSERT INTO guild_configs (guild_id, world, level_emoji, level_reaction, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_emoji = EXCLUDED.level_emoji, level_reaction = EXCLUDED.level_reaction, updated_at = NOW()
+`
+
+type SetGuildLevelEmojiParams struct {
+	GuildID       string
+	LevelEmoji    string
+	LevelReaction bool
+}
+
+func (q *Queries) SetGuildLevelEmoji(ctx context.Context, arg SetGuildLevelEmojiParams) error {
+	_, err := q.db.Exec(ctx, setGuildLevelEmoji, arg.GuildID, arg.LevelEmoji, arg.LevelReaction)
+	return err
+}
+
+const setGuildLevelTemplate = `-- name: SetGuildLevelTemplate :exec
+INSERT INTO guild_configs (guild_id, world, level_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_template = EXCLUDED.level_template, updated_at = NOW()
+`
+
+type SetGuildLevelTemplateParams struct {
+	GuildID       string
+	LevelTemplate string
+}
+
+func (q *Queries) SetGuildLevelTemplate(ctx context.Context, arg SetGuildLevelTemplateParams) error {
+	_, err := q.db.Exec(ctx, setGuildLevelTemplate, arg.GuildID, arg.LevelTemplate)
+	return err
+}
+
+const setGuildLowLevelDeaths = `-- name: SetGuildLowLevelDeaths :exec
+INSERT INTO guild_configs (guild_id, world, low_level_deaths, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET low_level_deaths = EXCLUDED.low_level_deaths, updated_at = NOW()
+`
+
+type SetGuildLowLevelDeathsParams struct {
+	GuildID        string
+	LowLevelDeaths bool
+}
+
+func (q *Queries) SetGuildLowLevelDeaths(ctx context.Context, arg SetGuildLowLevelDeathsParams) error {
+	_, err := q.db.Exec(ctx, setGuildLowLevelDeaths, arg.GuildID, arg.LowLevelDeaths)
+	return err
+}
+
+const setGuildMassDeathAlert = `-- name: SetGuildMassDeathAlert :exec
+INSERT INTO guild_configs (guild_id, world, mass_death_count, mass_death_window_minutes, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET mass_death_count = EXCLUDED.mass_death_count, mass_death_window_minutes = EXCLUDED.mass_death_window_minutes, updated_at = NOW()
+`
+
+type SetGuildMassDeathAlertParams struct {
+	GuildID                string
+	MassDeathCount         int32
+	MassDeathWindowMinutes int32
+}
+
+func (q *Queries) SetGuildMassDeathAlert(ctx context.Context, arg SetGuildMassDeathAlertParams) error {
+	_, err := q.db.Exec(ctx, setGuildMassDeathAlert, arg.GuildID, arg.MassDeathCount, arg.MassDeathWindowMinutes)
+	return err
+}
+
+const setGuildMinLevel = `-- name: SetGuildMinLevel :exec
+INSERT INTO guild_configs (guild_id, world, min_level, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET min_level = EXCLUDED.min_level, updated_at = NOW()
+`
+
+type SetGuildMinLevelParams struct {
+	GuildID  string
+	MinLevel int32
+}
+
+func (q *Queries) SetGuildMinLevel(ctx context.Context, arg SetGuildMinLevelParams) error {
+	_, err := q.db.Exec(ctx, setGuildMinLevel, arg.GuildID, arg.MinLevel)
+	return err
+}
+
+const setGuildMiscChannel = `-- name: SetGuildMiscChannel :exec
+INSERT INTO guild_configs (guild_id, world, misc_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET misc_channel_id = EXCLUDED.misc_channel_id, updated_at = NOW()
+`
+
+type SetGuildMiscChannelParams struct {
+	GuildID       string
+	MiscChannelID string
+}
+
+func (q *Queries) SetGuildMiscChannel(ctx context.Context, arg SetGuildMiscChannelParams) error {
+	_, err := q.db.Exec(ctx, setGuildMiscChannel, arg.GuildID, arg.MiscChannelID)
+	return err
+}
+
+const setGuildMutedUntil = `-- name: SetGuildMutedUntil :exec
+INSERT INTO guild_configs (guild_id, world, muted_until, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET muted_until = EXCLUDED.muted_until, updated_at = NOW()
+`
+
+type SetGuildMutedUntilParams struct {
+	GuildID    string
+	MutedUntil pgtype.Timestamptz
+}
+
+func (q *Queries) SetGuildMutedUntil(ctx context.Context, arg SetGuildMutedUntilParams) error {
+	_, err := q.db.Exec(ctx, setGuildMutedUntil, arg.GuildID, arg.MutedUntil)
+	return err
+}
+
+const setGuildPingRole = `-- name: SetGuildPingRole :exec
+INSERT INTO guild_configs (guild_id, world, ping_role_id, ping_min_level, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET ping_role_id = EXCLUDED.ping_role_id, ping_min_level = EXCLUDED.ping_min_level, updated_at = NOW()
+`
+
+type SetGuildPingRoleParams struct {
+	GuildID      string
+	PingRoleID   string
+	PingMinLevel int32
+}
+
+func (q *Queries) SetGuildPingRole(ctx context.Context, arg SetGuildPingRoleParams) error {
+	_, err := q.db.Exec(ctx, setGuildPingRole, arg.GuildID, arg.PingRoleID, arg.PingMinLevel)
+	return err
+}
+
+const setGuildPollInterval = `-- name: SetGuildPollInterval :exec
+INSERT INTO guild_configs (guild_id, world, poll_interval_seconds, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET poll_interval_seconds = EXCLUDED.poll_interval_seconds, updated_at = NOW()
+`
+
+type SetGuildPollIntervalParams struct {
+	GuildID             string
+	PollIntervalSeconds int32
+}
+
+func (q *Queries) SetGuildPollInterval(ctx context.Context, arg SetGuildPollIntervalParams) error {
+	_, err := q.db.Exec(ctx, setGuildPollInterval, arg.GuildID, arg.PollIntervalSeconds)
+	return err
+}
+
+const setGuildQuietHours = `-- name: SetGuildQuietHours :exec
+INSERT INTO guild_configs (guild_id, world, quiet_start, quiet_end, quiet_catch_up, updated_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET quiet_start = EXCLUDED.quiet_start, quiet_end = EXCLUDED.quiet_end, quiet_catch_up = EXCLUDED.quiet_catch_up, updated_at = NOW()
+`
+
+type SetGuildQuietHoursParams struct {
+	GuildID      string
+	QuietStart   int32
+	QuietEnd     int32
+	QuietCatchUp bool
+}
+
+func (q *Queries) SetGuildQuietHours(ctx context.Context, arg SetGuildQuietHoursParams) error {
+	_, err := q.db.Exec(ctx, setGuildQuietHours, arg.GuildID, arg.QuietStart, arg.QuietEnd, arg.QuietCatchUp)
+	return err
+}
+
+const setGuildPremium = `-- name: SetGuildPremium :exec
+INSERT INTO guild_configs (guild_id, world, premium, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET premium = EXCLUDED.premium, updated_at = NOW()
+`
+
+type SetGuildPremiumParams struct {
+	GuildID string
+	Premium bool
+}
+
+func (q *Queries) SetGuildPremium(ctx context.Context, arg SetGuildPremiumParams) error {
+	_, err := q.db.Exec(ctx, setGuildPremium, arg.GuildID, arg.Premium)
+	return err
+}
+
+const setGuildQuota = `-- name: SetGuildQuota :exec
+INSERT INTO guild_configs (guild_id, world, quota_tibia_guilds, quota_ignored_players, quota_watched_players, updated_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET quota_tibia_guilds = EXCLUDED.quota_tibia_guilds, quota_ignored_players = EXCLUDED.quota_ignored_players, quota_watched_players = EXCLUDED.quota_watched_players, updated_at = NOW()
+`
+
+type SetGuildQuotaParams struct {
+	GuildID             string
+	QuotaTibiaGuilds    int32
+	QuotaIgnoredPlayers int32
+	QuotaWatchedPlayers int32
+}
+
+func (q *Queries) SetGuildQuota(ctx context.Context, arg SetGuildQuotaParams) error {
+	_, err := q.db.Exec(ctx, setGuildQuota, arg.GuildID, arg.QuotaTibiaGuilds, arg.QuotaIgnoredPlayers, arg.QuotaWatchedPlayers)
+	return err
+}
+
+const setGuildResponseVisibility = `-- name: SetGuildResponseVisibility :exec
+INSERT INTO guild_configs (guild_id, world, response_visibility, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET response_visibility = EXCLUDED.response_visibility, updated_at = NOW()
+`
+
+type SetGuildResponseVisibilityParams struct {
+	GuildID            string
+	ResponseVisibility string
+}
+
+func (q *Queries) SetGuildResponseVisibility(ctx context.Context, arg SetGuildResponseVisibilityParams) error {
+	_, err := q.db.Exec(ctx, setGuildResponseVisibility, arg.GuildID, arg.ResponseVisibility)
+	return err
+}
+
+const setGuildShareRange = `-- name: SetGuildShareRange :exec
+INSERT INTO guild_configs (guild_id, world, share_range, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET share_range = EXCLUDED.share_range, updated_at = NOW()
+`
+
+type SetGuildShareRangeParams struct {
+	GuildID    string
+	ShareRange bool
+}
+
+func (q *Queries) SetGuildShareRange(ctx context.Context, arg SetGuildShareRangeParams) error {
+	_, err := q.db.Exec(ctx, setGuildShareRange, arg.GuildID, arg.ShareRange)
+	return err
+}
+
+const setGuildSkillChannel = `-- name: SetGuildSkillChannel :exec
+INSERT INTO guild_configs (guild_id, world, skill_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET skill_channel_id = EXCLUDED.skill_channel_id, updated_at = NOW()
+`
+
+type SetGuildSkillChannelParams struct {
+	GuildID        string
+	SkillChannelID string
+}
+
+func (q *Queries) SetGuildSkillChannel(ctx context.Context, arg SetGuildSkillChannelParams) error {
+	_, err := q.db.Exec(ctx, setGuildSkillChannel, arg.GuildID, arg.SkillChannelID)
+	return err
+}
+
+const setGuildTimezone = `-- name: SetGuildTimezone :exec
+INSERT INTO guild_configs (guild_id, world, timezone, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET timezone = EXCLUDED.timezone, updated_at = NOW()
+`
+
+type SetGuildTimezoneParams struct {
+	GuildID  string
+	Timezone string
+}
+
+func (q *Queries) SetGuildTimezone(ctx context.Context, arg SetGuildTimezoneParams) error {
+	_, err := q.db.Exec(ctx, setGuildTimezone, arg.GuildID, arg.Timezone)
+	return err
+}
+
+const setSkillThreshold = `-- name: SetSkillThreshold :exec
+INSERT INTO skill_thresholds (guild_id, skill, min_value)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id, skill) DO UPDATE
+SET min_value = EXCLUDED.min_value
+`
+
+type SetSkillThresholdParams struct {
+	GuildID  string
+	Skill    string
+	MinValue int32
+}
+
+func (q *Queries) SetSkillThreshold(ctx context.Context, arg SetSkillThresholdParams) error {
+	_, err := q.db.Exec(ctx, setSkillThreshold, arg.GuildID, arg.Skill, arg.MinValue)
+	return err
+}
+
+const startGuildWar = `-- name: StartGuildWar :exec
+INSERT INTO guild_wars (guild_name, started_at)
+VALUES ($1, $2)
+ON CONFLICT (guild_name, started_at) DO NOTHING
+`
+
+type StartGuildWarParams struct {
+	GuildName string
+	StartedAt pgtype.Timestamptz
+}
+
+func (q *Queries) StartGuildWar(ctx context.Context, arg StartGuildWarParams) error {
+	_, err := q.db.Exec(ctx, startGuildWar, arg.GuildName, arg.StartedAt)
 	return err
 }
 
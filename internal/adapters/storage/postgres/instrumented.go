@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/adapters/storage/postgres/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// instrumentedDB times every query the sqlc Queries run. Each one is logged
+// at debug level with its row count and observed in db_query_duration_seconds;
+// queries slower than slow are logged as warnings too. A zero slow never
+// flags a query.
+type instrumentedDB struct {
+	db   db.DBTX
+	slow time.Duration
+}
+
+func newInstrumentedDB(dbtx db.DBTX, slow time.Duration) *instrumentedDB {
+	return &instrumentedDB{db: dbtx, slow: slow}
+}
+
+func (d *instrumentedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	start := time.Now()
+	tag, err := d.db.Exec(ctx, sql, args...)
+	d.observe(ctx, queryName(sql), start, tag.RowsAffected(), err)
+	return tag, err
+}
+
+// Query is observed when the rows are closed, so its duration includes
+// reading them.
+func (d *instrumentedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	start := time.Now()
+	rows, err := d.db.Query(ctx, sql, args...)
+	if err != nil {
+		d.observe(ctx, queryName(sql), start, 0, err)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, done: func(n int64, err error) {
+		d.observe(ctx, queryName(sql), start, n, err)
+	}}, nil
+}
+
+// QueryRow is observed when the row is scanned.
+func (d *instrumentedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	start := time.Now()
+	row := d.db.QueryRow(ctx, sql, args...)
+	return instrumentedRow{row: row, done: func(err error) {
+		var n int64 = 1
+		if errors.Is(err, pgx.ErrNoRows) {
+			n, err = 0, nil
+		}
+		d.observe(ctx, queryName(sql), start, n, err)
+	}}
+}
+
+func (d *instrumentedDB) observe(ctx context.Context, query string, start time.Time, rows int64, err error) {
+	elapsed := time.Since(start)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	metrics.DBQueryDuration.WithLabelValues(query, status).Observe(elapsed.Seconds())
+
+	if d.slow > 0 && elapsed >= d.slow {
+		metrics.DBSlowQueries.WithLabelValues(query).Inc()
+		slog.WarnContext(ctx, "Slow database query", "query", query, "duration", elapsed, "rows", rows, "threshold", d.slow)
+		return
+	}
+	slog.DebugContext(ctx, "Database query", "query", query, "duration", elapsed, "rows", rows, "error", err)
+}
+
+// queryName reads the name sqlc puts in the "-- name: GetPlayersLevels :many"
+// header of every query. Other statements are reported as "unknown".
+func queryName(sql string) string {
+	header, _, _ := strings.Cut(sql, "\n")
+	rest, ok := strings.CutPrefix(strings.TrimSpace(header), "-- name: ")
+	if !ok {
+		return "unknown"
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
+
+// instrumentedRows counts the rows read and reports them once, on Close.
+type instrumentedRows struct {
+	pgx.Rows
+	n    int64
+	once sync.Once
+	done func(n int64, err error)
+}
+
+func (r *instrumentedRows) Next() bool {
+	if r.Rows.Next() {
+		r.n++
+		return true
+	}
+	// pgx closes the rows once they are exhausted, without calling Close.
+	r.report()
+	return false
+}
+
+func (r *instrumentedRows) Close() {
+	r.Rows.Close()
+	r.report()
+}
+
+func (r *instrumentedRows) report() {
+	r.once.Do(func() { r.done(r.n, r.Rows.Err()) })
+}
+
+type instrumentedRow struct {
+	row  pgx.Row
+	done func(err error)
+}
+
+func (r instrumentedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.done(err)
+	return err
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/adapters/storage/postgres/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// captureLogs sends slog output to the returned buffer until the test ends.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"-- name: GetPlayersLevels :many\nSELECT name, level FROM players WHERE world = $1\n", "GetPlayersLevels"},
+		{"-- name: DeleteOldPlayers :execrows\nDELETE FROM players", "DeleteOldPlayers"},
+		{"SELECT 1", "unknown"},
+		{"", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := queryName(tt.sql); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentedDB_LogsQueries(t *testing.T) {
+	logs := captureLogs(t)
+	count := 0
+	mockDB := &MockDB{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			return &MockRows{
+				NextFunc: func() bool {
+					count++
+					return count <= 3
+				},
+				ScanFunc: func(dest ...any) error {
+					*dest[0].(*string) = "Player"
+					*dest[1].(*int32) = 100
+					return nil
+				},
+			}, nil
+		},
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			return pgconn.NewCommandTag("DELETE 7"), nil
+		},
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			return &MockRow{ScanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+		},
+	}
+	q := db.New(newInstrumentedDB(mockDB, time.Hour))
+
+	if _, err := q.GetPlayersLevels(context.Background(), "Antica"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := q.DeleteOldPlayers(context.Background(), db.DeleteOldPlayersParams{World: "Antica"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := q.GetGuildConfig(context.Background(), "guild-1"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected no rows, got %v", err)
+	}
+
+	out := logs.String()
+	for _, want := range []string{"query=GetPlayersLevels duration=", "rows=3", "query=DeleteOldPlayers", "rows=7", "query=GetGuildConfig", "rows=0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in logs:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "level=WARN") {
+		t.Errorf("expected no slow queries:\n%s", out)
+	}
+	if strings.Count(out, "query=GetPlayersLevels") != 1 {
+		t.Errorf("expected the query logged once:\n%s", out)
+	}
+}
+
+func TestInstrumentedDB_FlagsSlowQueries(t *testing.T) {
+	logs := captureLogs(t)
+	before := testutil.ToFloat64(metrics.DBSlowQueries.WithLabelValues("SetGuildPremium"))
+	mockDB := &MockDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			time.Sleep(5 * time.Millisecond)
+			return pgconn.NewCommandTag("UPDATE 1"), nil
+		},
+	}
+	q := db.New(newInstrumentedDB(mockDB, time.Millisecond))
+
+	if err := q.SetGuildPremium(context.Background(), db.SetGuildPremiumParams{GuildID: "guild-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.DBSlowQueries.WithLabelValues("SetGuildPremium")) - before; got != 1 {
+		t.Errorf("expected 1 slow query counted, got %v", got)
+	}
+	if out := logs.String(); !strings.Contains(out, `level=WARN msg="Slow database query" query=SetGuildPremium`) {
+		t.Errorf("expected a slow query warning:\n%s", out)
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TrackerLockKey is the advisory lock key shared by every bot replica.
+const TrackerLockKey int64 = 0x646c74 // "dlt"
+
+const unlockTimeout = 5 * time.Second
+
+// lockConn is the subset of *pgxpool.Conn the leader needs. Advisory locks
+// belong to a session, so the lock must be taken and held on one connection.
+type lockConn interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Release()
+}
+
+// AdvisoryLeader elects a single tracker leader using a session-level
+// Postgres advisory lock. The leader keeps a dedicated connection open; if the
+// process dies or the connection drops, Postgres frees the lock and a standby
+// picks it up on its next attempt.
+type AdvisoryLeader struct {
+	acquire func(ctx context.Context) (lockConn, error)
+	key     int64
+
+	mu   sync.Mutex
+	conn lockConn
+}
+
+// NewLeader returns an elector that competes for key using connections from
+// the store's pool.
+func (s *PostgresStore) NewLeader(key int64) *AdvisoryLeader {
+	return &AdvisoryLeader{
+		acquire: func(ctx context.Context) (lockConn, error) {
+			return s.pool.Acquire(ctx)
+		},
+		key: key,
+	}
+}
+
+// IsLeader verifies a held lock is still alive, or tries to take it if this
+// instance is on standby. It never blocks waiting for another leader.
+func (l *AdvisoryLeader) IsLeader(ctx context.Context) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		var one int
+		err := l.conn.QueryRow(ctx, "SELECT 1").Scan(&one)
+		if err == nil {
+			return true
+		}
+		slog.WarnContext(ctx, "Lost tracker leadership", "error", err)
+		l.stepDown()
+	}
+
+	conn, err := l.acquire(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to acquire connection for leader lock", "error", err)
+		return false
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil || !locked {
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to try leader lock", "error", err)
+		}
+		conn.Release()
+		return false
+	}
+
+	l.conn = conn
+	metrics.TrackerLeader.Set(1)
+	slog.InfoContext(ctx, "Acquired tracker leadership")
+	return true
+}
+
+// Release gives up leadership so a standby can take over without waiting for
+// this connection to close.
+func (l *AdvisoryLeader) Release(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+	l.stepDown()
+	slog.InfoContext(ctx, "Released tracker leadership")
+}
+
+// stepDown unlocks and returns the held connection. A broken connection is
+// discarded by the pool, which also ends its session and frees the lock.
+func (l *AdvisoryLeader) stepDown() {
+	ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+	defer cancel()
+
+	var unlocked bool
+	if err := l.conn.QueryRow(ctx, "SELECT pg_advisory_unlock($1)", l.key).Scan(&unlocked); err != nil {
+		slog.Warn("Failed to unlock leader lock", "error", err)
+	}
+	l.conn.Release()
+	l.conn = nil
+	metrics.TrackerLeader.Set(0)
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type mockLockConn struct {
+	locked   bool
+	alive    bool
+	released bool
+	queries  []string
+}
+
+func (c *mockLockConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	c.queries = append(c.queries, sql)
+	return &MockRow{ScanFunc: func(dest ...any) error {
+		switch {
+		case strings.Contains(sql, "pg_try_advisory_lock"):
+			*dest[0].(*bool) = c.locked
+		case strings.Contains(sql, "pg_advisory_unlock"):
+			*dest[0].(*bool) = true
+		default:
+			if !c.alive {
+				return errors.New("conn closed")
+			}
+			*dest[0].(*int) = 1
+		}
+		return nil
+	}}
+}
+
+func (c *mockLockConn) Release() { c.released = true }
+
+func newTestLeader(conns ...*mockLockConn) *AdvisoryLeader {
+	return &AdvisoryLeader{
+		key: TrackerLockKey,
+		acquire: func(ctx context.Context) (lockConn, error) {
+			if len(conns) == 0 {
+				return nil, errors.New("pool exhausted")
+			}
+			c := conns[0]
+			conns = conns[1:]
+			return c, nil
+		},
+	}
+}
+
+func TestAdvisoryLeader_IsLeader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Acquires free lock and keeps connection", func(t *testing.T) {
+		conn := &mockLockConn{locked: true, alive: true}
+		l := newTestLeader(conn)
+
+		if !l.IsLeader(ctx) {
+			t.Fatal("expected to become leader")
+		}
+		if !l.IsLeader(ctx) {
+			t.Fatal("expected to stay leader")
+		}
+		if conn.released {
+			t.Error("leader connection should stay checked out")
+		}
+		if len(conn.queries) != 2 || conn.queries[1] != "SELECT 1" {
+			t.Errorf("expected lock then liveness check, got %v", conn.queries)
+		}
+	})
+
+	t.Run("Stands by when lock is held elsewhere", func(t *testing.T) {
+		conn := &mockLockConn{locked: false}
+		l := newTestLeader(conn)
+
+		if l.IsLeader(ctx) {
+			t.Fatal("expected standby")
+		}
+		if !conn.released {
+			t.Error("expected connection to be returned to the pool")
+		}
+	})
+
+	t.Run("Stands by when no connection is available", func(t *testing.T) {
+		l := newTestLeader()
+		if l.IsLeader(ctx) {
+			t.Fatal("expected standby")
+		}
+	})
+
+	t.Run("Reacquires after losing connection", func(t *testing.T) {
+		dead := &mockLockConn{locked: true, alive: true}
+		fresh := &mockLockConn{locked: true, alive: true}
+		l := newTestLeader(dead, fresh)
+
+		if !l.IsLeader(ctx) {
+			t.Fatal("expected to become leader")
+		}
+		dead.alive = false
+
+		if !l.IsLeader(ctx) {
+			t.Fatal("expected to win the lock again on a fresh connection")
+		}
+		if !dead.released {
+			t.Error("expected dead connection to be released")
+		}
+		if l.conn != fresh {
+			t.Error("expected leader to hold the fresh connection")
+		}
+	})
+}
+
+func TestAdvisoryLeader_Release(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockLockConn{locked: true, alive: true}
+	l := newTestLeader(conn)
+
+	l.Release(ctx) // no-op before leading
+	if !l.IsLeader(ctx) {
+		t.Fatal("expected to become leader")
+	}
+	l.Release(ctx)
+
+	if !conn.released {
+		t.Error("expected connection to be released")
+	}
+	if last := conn.queries[len(conn.queries)-1]; !strings.Contains(last, "pg_advisory_unlock") {
+		t.Errorf("expected unlock query, got %q", last)
+	}
+	if l.conn != nil {
+		t.Error("expected leader to drop its connection")
+	}
+}
@@ -0,0 +1,305 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MigrationLockKey serialises migrations across replicas starting together.
+const MigrationLockKey int64 = 0x646c746d // "dltm"
+
+// Migration is one versioned schema change loaded from <version>_<name>.sql.
+type Migration struct {
+	Version  string
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus reports whether a migration has been applied and whether
+// its file changed afterwards.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// LoadMigrations reads migrations from the root of fsys, with optional
+// rollback scripts under down/, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(files))
+	seen := make(map[string]string)
+	for _, file := range files {
+		version, name, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), "_")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>.sql", file)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migration %s: version %s already used by %s", file, version, other)
+		}
+		seen[version] = file
+
+		up, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		down, err := fs.ReadFile(fsys, path.Join("down", file))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(up)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			Up:       string(up),
+			Down:     string(down),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies and rolls back migrations on a dedicated connection.
+type Migrator struct {
+	conn       *pgx.Conn
+	migrations []Migration
+}
+
+func NewMigrator(ctx context.Context, databaseURL string, migrations []Migration) (*Migrator, error) {
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect for migrations: %w", err)
+	}
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+func (m *Migrator) Close(ctx context.Context) {
+	m.conn.Close(ctx)
+}
+
+// Status lists every known migration in version order.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.locked(ctx, func(applied map[string]appliedMigration) error {
+		for _, mig := range m.migrations {
+			a, ok := applied[mig.Version]
+			statuses = append(statuses, MigrationStatus{
+				Migration: mig,
+				Applied:   ok,
+				AppliedAt: a.appliedAt,
+				Modified:  ok && a.checksum != "" && a.checksum != mig.Checksum,
+			})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up applies all pending migrations, each in its own transaction, and returns
+// those it applied. With dryRun it returns what would run instead.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(applied map[string]appliedMigration) error {
+		for _, mig := range m.migrations {
+			if a, ok := applied[mig.Version]; ok && a.checksum != "" && a.checksum != mig.Checksum {
+				slog.Warn("Applied migration was modified", "version", mig.Version, "name", mig.Name)
+			}
+		}
+
+		pending := pendingMigrations(m.migrations, applied)
+		if dryRun {
+			done = pending
+			return nil
+		}
+		for _, mig := range pending {
+			start := time.Now()
+			if err := m.apply(ctx, mig.Up, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`, mig.Version, mig.Name, mig.Checksum)
+				return err
+			}); err != nil {
+				return fmt.Errorf("apply %s_%s: %w", mig.Version, mig.Name, err)
+			}
+			slog.Info("Applied migration", "version", mig.Version, "name", mig.Name, "duration", time.Since(start))
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and
+// returns those it rolled back. With dryRun it returns the plan instead.
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(applied map[string]appliedMigration) error {
+		plan, err := rollbackPlan(m.migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			done = plan
+			return nil
+		}
+		for _, mig := range plan {
+			if err := m.apply(ctx, mig.Down, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("roll back %s_%s: %w", mig.Version, mig.Name, err)
+			}
+			slog.Info("Rolled back migration", "version", mig.Version, "name", mig.Name)
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// locked holds the migration advisory lock while fn runs, so replicas
+// starting together do not apply the same migration twice.
+func (m *Migrator) locked(ctx context.Context, fn func(applied map[string]appliedMigration) error) error {
+	if _, err := m.conn.Exec(ctx, "SELECT pg_advisory_lock($1)", MigrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := m.conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", MigrationLockKey); err != nil {
+			slog.Warn("Failed to release migration lock", "error", err)
+		}
+	}()
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+func (m *Migrator) apply(ctx context.Context, script string, record func(pgx.Tx) error) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// applied loads the migration history, adopting versions already applied by
+// Atlas the first time the table is created.
+func (m *Migrator) applied(ctx context.Context) (map[string]appliedMigration, error) {
+	var exists bool
+	if err := m.conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check schema_migrations: %w", err)
+	}
+
+	if !exists {
+		_, err := m.conn.Exec(ctx, `CREATE TABLE schema_migrations (
+    version VARCHAR(32) PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum VARCHAR(64) NOT NULL DEFAULT '',
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+		if err != nil {
+			return nil, fmt.Errorf("create schema_migrations: %w", err)
+		}
+		if err := m.adoptAtlasHistory(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := m.conn.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]appliedMigration)
+	for rows.Next() {
+		var version string
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) adoptAtlasHistory(ctx context.Context) error {
+	var exists bool
+	err := m.conn.QueryRow(ctx, `SELECT to_regclass('atlas_schema_revisions.atlas_schema_revisions') IS NOT NULL`).Scan(&exists)
+	if err != nil || !exists {
+		return err
+	}
+
+	tag, err := m.conn.Exec(ctx, `INSERT INTO schema_migrations (version, name, applied_at)
+SELECT version, description, executed_at FROM atlas_schema_revisions.atlas_schema_revisions
+WHERE applied = total
+ON CONFLICT (version) DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("import atlas history: %w", err)
+	}
+	slog.Info("Imported migration history from Atlas", "count", tag.RowsAffected())
+	return nil
+}
+
+func pendingMigrations(migrations []Migration, applied map[string]appliedMigration) []Migration {
+	var pending []Migration
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending
+}
+
+func rollbackPlan(migrations []Migration, applied map[string]appliedMigration, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+
+	var plan []Migration
+	for i := len(migrations) - 1; i >= 0 && len(plan) < steps; i-- {
+		mig := migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return nil, fmt.Errorf("migration %s_%s has no down script", mig.Version, mig.Name)
+		}
+		plan = append(plan, mig)
+	}
+	return plan, nil
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"death-level-tracker/sql/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_deaths.sql":      {Data: []byte("CREATE TABLE deaths ();")},
+		"001_baseline.sql":        {Data: []byte("CREATE TABLE players ();")},
+		"down/002_add_deaths.sql": {Data: []byte("DROP TABLE deaths;")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+
+	got, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(got))
+	}
+	if got[0].Version != "001" || got[0].Name != "baseline" || got[0].Down != "" {
+		t.Errorf("unexpected first migration: %+v", got[0])
+	}
+	if got[1].Version != "002" || got[1].Down != "DROP TABLE deaths;" {
+		t.Errorf("unexpected second migration: %+v", got[1])
+	}
+	if got[0].Checksum == "" || got[0].Checksum == got[1].Checksum {
+		t.Error("expected distinct checksums")
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing name":      {"001.sql": {Data: []byte("SELECT 1;")}},
+		"duplicate version": {"001_a.sql": {}, "001_b.sql": {}},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadMigrations(fsys); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for _, m := range all {
+		if strings.TrimSpace(m.Down) == "" {
+			t.Errorf("migration %s_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	all := []Migration{{Version: "001"}, {Version: "002"}, {Version: "003"}}
+	applied := map[string]appliedMigration{"001": {}, "003": {}}
+
+	pending := pendingMigrations(all, applied)
+	if len(pending) != 1 || pending[0].Version != "002" {
+		t.Errorf("expected only 002 pending, got %+v", pending)
+	}
+}
+
+func TestRollbackPlan(t *testing.T) {
+	all := []Migration{
+		{Version: "001", Down: "DROP 1"},
+		{Version: "002", Down: "DROP 2"},
+		{Version: "003", Down: "DROP 3"},
+		{Version: "004"},
+	}
+	applied := map[string]appliedMigration{"001": {}, "002": {}, "003": {}}
+
+	t.Run("newest first", func(t *testing.T) {
+		plan, err := rollbackPlan(all, applied, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(plan) != 2 || plan[0].Version != "003" || plan[1].Version != "002" {
+			t.Errorf("unexpected plan: %+v", plan)
+		}
+	})
+
+	t.Run("more steps than applied", func(t *testing.T) {
+		plan, err := rollbackPlan(all, applied, 10)
+		if err != nil || len(plan) != 3 {
+			t.Errorf("expected all 3 applied, got %+v, %v", plan, err)
+		}
+	})
+
+	t.Run("missing down script", func(t *testing.T) {
+		withLatest := map[string]appliedMigration{"003": {}, "004": {}}
+		if _, err := rollbackPlan(all, withLatest, 1); err == nil {
+			t.Error("expected error for migration without down script")
+		}
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		if _, err := rollbackPlan(all, applied, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
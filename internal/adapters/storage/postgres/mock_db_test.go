@@ -89,3 +89,57 @@ func (m *MockRows) Values() ([]any, error) { return nil, nil }
 func (m *MockRows) RawValues() [][]byte    { return nil }
 
 func (m *MockRows) Conn() *pgx.Conn { return nil }
+
+// MockTx implements pgx.Tx on top of MockDB, recording how it ended.
+// Methods the store does not use panic through the embedded nil pgx.Tx.
+type MockTx struct {
+	pgx.Tx
+	*MockDB
+	committed  bool
+	rolledBack bool
+}
+
+func (m *MockTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &MockTx{MockDB: m.MockDB}, nil
+}
+
+func (m *MockTx) Commit(ctx context.Context) error {
+	if m.committed || m.rolledBack {
+		return pgx.ErrTxClosed
+	}
+	m.committed = true
+	return nil
+}
+
+func (m *MockTx) Rollback(ctx context.Context) error {
+	if m.committed || m.rolledBack {
+		return pgx.ErrTxClosed
+	}
+	m.rolledBack = true
+	return nil
+}
+
+func (m *MockTx) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return m.MockDB.Exec(ctx, sql, arguments...)
+}
+
+func (m *MockTx) Query(ctx context.Context, sql string, arguments ...interface{}) (pgx.Rows, error) {
+	return m.MockDB.Query(ctx, sql, arguments...)
+}
+
+func (m *MockTx) QueryRow(ctx context.Context, sql string, arguments ...interface{}) pgx.Row {
+	return m.MockDB.QueryRow(ctx, sql, arguments...)
+}
+
+// MockBeginner hands out tx from Begin.
+type MockBeginner struct {
+	tx  *MockTx
+	err error
+}
+
+func (m *MockBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.tx, nil
+}
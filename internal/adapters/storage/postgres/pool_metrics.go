@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"log/slog"
+
+	"death-level-tracker/internal/adapters/metrics"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func registerPoolMetrics(pool *pgxpool.Pool) {
+	err := metrics.RegisterDBPool(func() metrics.DBPoolStats {
+		return poolStats(pool.Stat())
+	})
+	if err != nil {
+		slog.Warn("Failed to register database pool metrics", "error", err)
+	}
+}
+
+func poolStats(s *pgxpool.Stat) metrics.DBPoolStats {
+	return metrics.DBPoolStats{
+		AcquiredConns:        s.AcquiredConns(),
+		IdleConns:            s.IdleConns(),
+		TotalConns:           s.TotalConns(),
+		MaxConns:             s.MaxConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		AcquireDuration:      s.AcquireDuration(),
+		EmptyAcquireWaitTime: s.EmptyAcquireWaitTime(),
+	}
+}
@@ -2,12 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"death-level-tracker/internal/adapters/storage/postgres/db"
+	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/domain"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -15,10 +19,22 @@ import (
 type PostgresStore struct {
 	pool *pgxpool.Pool
 	q    *db.Queries
+	// begin starts transactions: the pool, or the open transaction of a
+	// store returned by BeginTx.
+	begin         txBeginner
+	slowThreshold time.Duration
 }
 
-func NewPostgresStore(ctx context.Context, connString string) (*PostgresStore, error) {
-	pool, err := pgxpool.New(ctx, connString)
+func NewPostgresStore(ctx context.Context, cfg *config.Config) (*PostgresStore, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	poolCfg.MaxConns = int32(cfg.DBMaxConns)
+	poolCfg.MinConns = int32(cfg.DBMinConns)
+	poolCfg.MaxConnLifetime = cfg.DBMaxConnLifetime
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("create connection pool: %w", err)
 	}
@@ -27,9 +43,13 @@ func NewPostgresStore(ctx context.Context, connString string) (*PostgresStore, e
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
+	registerPoolMetrics(pool)
+
 	return &PostgresStore{
-		pool: pool,
-		q:    db.New(pool),
+		pool:          pool,
+		q:             db.New(newInstrumentedDB(pool, cfg.DBSlowQueryThreshold)),
+		begin:         pool,
+		slowThreshold: cfg.DBSlowQueryThreshold,
 	}, nil
 }
 
@@ -48,14 +68,71 @@ func (s *PostgresStore) SaveGuildWorld(ctx context.Context, guildID, world strin
 
 func (s *PostgresStore) GetGuildConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
 	row, err := s.q.GetGuildConfig(ctx, guildID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("get guild config: %w", err)
 	}
+	routes, err := s.q.GetGuildDeathRoutes(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("get death routes: %w", err)
+	}
+	deathRoutes := make([]domain.DeathRoute, 0, len(routes))
+	for _, route := range routes {
+		deathRoutes = append(deathRoutes, domain.DeathRoute{MinLevel: int(route.MinLevel), ChannelID: route.ChannelID})
+	}
+	thresholds, err := s.q.GetGuildSkillThresholds(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("get skill thresholds: %w", err)
+	}
+	var skillThresholds map[domain.Skill]int
+	if len(thresholds) > 0 {
+		skillThresholds = make(map[domain.Skill]int, len(thresholds))
+		for _, t := range thresholds {
+			skillThresholds[domain.Skill(t.Skill)] = int(t.MinValue)
+		}
+	}
 
 	return &domain.GuildConfig{
-		DiscordGuildID: row.GuildID,
-		World:          row.World,
-		TibiaGuilds:    row.TibiaGuilds,
+		DiscordGuildID:     row.GuildID,
+		World:              row.World,
+		TibiaGuilds:        row.TibiaGuilds,
+		Language:           row.Language,
+		DeathChannelID:     row.DeathChannelID,
+		LevelChannelID:     row.LevelChannelID,
+		PingRoleID:         row.PingRoleID,
+		PingMinLevel:       int(row.PingMinLevel),
+		PollInterval:       time.Duration(row.PollIntervalSeconds) * time.Second,
+		MutedUntil:         row.MutedUntil.Time,
+		IgnoredPlayers:     row.IgnoredPlayers,
+		LowLevelDeaths:     row.LowLevelDeaths,
+		LastNotifiedAt:     row.LastNotifiedAt.Time,
+		HouseChannelID:     row.HouseChannelID,
+		MiscChannelID:      row.MiscChannelID,
+		Timezone:           row.Timezone,
+		DeathTemplate:      row.DeathTemplate,
+		LevelTemplate:      row.LevelTemplate,
+		LevelDownTemplate:  row.LevelDownTemplate,
+		DeathEmoji:         row.DeathEmoji,
+		LevelEmoji:         row.LevelEmoji,
+		DeathReaction:      row.DeathReaction,
+		LevelReaction:      row.LevelReaction,
+		QuietHours:         quietHours(row.QuietStart, row.QuietEnd, row.QuietCatchUp),
+		Quota:              domain.Quota{TibiaGuilds: int(row.QuotaTibiaGuilds), IgnoredPlayers: int(row.QuotaIgnoredPlayers), WatchedPlayers: int(row.QuotaWatchedPlayers)},
+		Premium:            row.Premium,
+		ShareRange:         row.ShareRange,
+		DeathLocation:      row.DeathLocation,
+		LevelDowns:         row.LevelDowns,
+		ResponseVisibility: domain.ResponseVisibility(row.ResponseVisibility),
+		DeathVerbosity:     domain.DeathVerbosity(row.DeathVerbosity),
+		DeathRoutes:        deathRoutes,
+		SkillChannelID:     row.SkillChannelID,
+		WatchedPlayers:     row.WatchedPlayers,
+		SkillThresholds:    skillThresholds,
+		MassDeathAlert:     massDeathAlert(row.MassDeathCount, row.MassDeathWindowMinutes),
+		BroadcastOptOut:    row.BroadcastOptOut,
+		MinLevel:           int(row.MinLevel),
 	}, nil
 }
 
@@ -64,13 +141,67 @@ func (s *PostgresStore) GetAllGuildConfigs(ctx context.Context) ([]domain.GuildC
 	if err != nil {
 		return nil, fmt.Errorf("get all guild configs: %w", err)
 	}
+	routes, err := s.q.GetDeathRoutes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get death routes: %w", err)
+	}
+	routesByGuild := make(map[string][]domain.DeathRoute)
+	for _, route := range routes {
+		routesByGuild[route.GuildID] = append(routesByGuild[route.GuildID], domain.DeathRoute{MinLevel: int(route.MinLevel), ChannelID: route.ChannelID})
+	}
+	thresholds, err := s.q.GetSkillThresholds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get skill thresholds: %w", err)
+	}
+	thresholdsByGuild := make(map[string]map[domain.Skill]int)
+	for _, t := range thresholds {
+		if thresholdsByGuild[t.GuildID] == nil {
+			thresholdsByGuild[t.GuildID] = make(map[domain.Skill]int)
+		}
+		thresholdsByGuild[t.GuildID][domain.Skill(t.Skill)] = int(t.MinValue)
+	}
 
 	result := make([]domain.GuildConfig, 0, len(rows))
 	for _, row := range rows {
 		result = append(result, domain.GuildConfig{
-			DiscordGuildID: row.GuildID,
-			World:          row.World,
-			TibiaGuilds:    row.TibiaGuilds,
+			DiscordGuildID:     row.GuildID,
+			World:              row.World,
+			TibiaGuilds:        row.TibiaGuilds,
+			Language:           row.Language,
+			DeathChannelID:     row.DeathChannelID,
+			LevelChannelID:     row.LevelChannelID,
+			PingRoleID:         row.PingRoleID,
+			PingMinLevel:       int(row.PingMinLevel),
+			PollInterval:       time.Duration(row.PollIntervalSeconds) * time.Second,
+			MutedUntil:         row.MutedUntil.Time,
+			IgnoredPlayers:     row.IgnoredPlayers,
+			LowLevelDeaths:     row.LowLevelDeaths,
+			LastNotifiedAt:     row.LastNotifiedAt.Time,
+			HouseChannelID:     row.HouseChannelID,
+			MiscChannelID:      row.MiscChannelID,
+			Timezone:           row.Timezone,
+			DeathTemplate:      row.DeathTemplate,
+			LevelTemplate:      row.LevelTemplate,
+			LevelDownTemplate:  row.LevelDownTemplate,
+			DeathEmoji:         row.DeathEmoji,
+			LevelEmoji:         row.LevelEmoji,
+			DeathReaction:      row.DeathReaction,
+			LevelReaction:      row.LevelReaction,
+			QuietHours:         quietHours(row.QuietStart, row.QuietEnd, row.QuietCatchUp),
+			Quota:              domain.Quota{TibiaGuilds: int(row.QuotaTibiaGuilds), IgnoredPlayers: int(row.QuotaIgnoredPlayers), WatchedPlayers: int(row.QuotaWatchedPlayers)},
+			Premium:            row.Premium,
+			ShareRange:         row.ShareRange,
+			DeathLocation:      row.DeathLocation,
+			LevelDowns:         row.LevelDowns,
+			ResponseVisibility: domain.ResponseVisibility(row.ResponseVisibility),
+			DeathVerbosity:     domain.DeathVerbosity(row.DeathVerbosity),
+			DeathRoutes:        routesByGuild[row.GuildID],
+			SkillChannelID:     row.SkillChannelID,
+			WatchedPlayers:     row.WatchedPlayers,
+			SkillThresholds:    thresholdsByGuild[row.GuildID],
+			MassDeathAlert:     massDeathAlert(row.MassDeathCount, row.MassDeathWindowMinutes),
+			BroadcastOptOut:    row.BroadcastOptOut,
+			MinLevel:           int(row.MinLevel),
 		})
 	}
 	return result, nil
@@ -83,14 +214,345 @@ func (s *PostgresStore) DeleteGuildConfig(ctx context.Context, guildID string) e
 func (s *PostgresStore) AddGuildToConfig(ctx context.Context, guildID, tibiaGuild string) error {
 	return s.q.AddGuildToConfig(ctx, db.AddGuildToConfigParams{
 		GuildID:    guildID,
-		TibiaGuild: tibiaGuild,
+		TibiaGuild: domain.NormalizeName(tibiaGuild),
 	})
 }
 
 func (s *PostgresStore) RemoveGuildFromConfig(ctx context.Context, guildID, tibiaGuild string) error {
 	return s.q.RemoveGuildFromConfig(ctx, db.RemoveGuildFromConfigParams{
 		GuildID:    guildID,
-		TibiaGuild: tibiaGuild,
+		TibiaGuild: domain.NormalizeName(tibiaGuild),
+	})
+}
+
+func (s *PostgresStore) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	return s.q.SetGuildLanguage(ctx, db.SetGuildLanguageParams{
+		GuildID:  guildID,
+		Language: language,
+	})
+}
+
+func (s *PostgresStore) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	switch kind {
+	case domain.ChannelDeaths:
+		return s.q.SetGuildDeathChannel(ctx, db.SetGuildDeathChannelParams{
+			GuildID:        guildID,
+			DeathChannelID: channelID,
+		})
+	case domain.ChannelLevels:
+		return s.q.SetGuildLevelChannel(ctx, db.SetGuildLevelChannelParams{
+			GuildID:        guildID,
+			LevelChannelID: channelID,
+		})
+	case domain.ChannelHouses:
+		return s.q.SetGuildHouseChannel(ctx, db.SetGuildHouseChannelParams{
+			GuildID:        guildID,
+			HouseChannelID: channelID,
+		})
+	case domain.ChannelMisc:
+		return s.q.SetGuildMiscChannel(ctx, db.SetGuildMiscChannelParams{
+			GuildID:       guildID,
+			MiscChannelID: channelID,
+		})
+	case domain.ChannelSkills:
+		return s.q.SetGuildSkillChannel(ctx, db.SetGuildSkillChannelParams{
+			GuildID:        guildID,
+			SkillChannelID: channelID,
+		})
+	default:
+		return fmt.Errorf("unknown notification channel: %s", kind)
+	}
+}
+
+func (s *PostgresStore) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	return s.q.SetGuildPingRole(ctx, db.SetGuildPingRoleParams{
+		GuildID:      guildID,
+		PingRoleID:   roleID,
+		PingMinLevel: int32(minLevel),
+	})
+}
+
+func (s *PostgresStore) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	return s.q.SetGuildPollInterval(ctx, db.SetGuildPollIntervalParams{
+		GuildID:             guildID,
+		PollIntervalSeconds: int32(interval / time.Second),
+	})
+}
+
+func (s *PostgresStore) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	return s.q.SetGuildShareRange(ctx, db.SetGuildShareRangeParams{GuildID: guildID, ShareRange: enabled})
+}
+
+func (s *PostgresStore) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	return s.q.SetGuildDeathLocation(ctx, db.SetGuildDeathLocationParams{GuildID: guildID, DeathLocation: enabled})
+}
+
+func (s *PostgresStore) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	return s.q.SetGuildLevelDowns(ctx, db.SetGuildLevelDownsParams{GuildID: guildID, LevelDowns: enabled})
+}
+
+func (s *PostgresStore) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	return s.q.SetGuildDeathVerbosity(ctx, db.SetGuildDeathVerbosityParams{GuildID: guildID, DeathVerbosity: string(verbosity)})
+}
+
+func (s *PostgresStore) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	return s.q.SetGuildResponseVisibility(ctx, db.SetGuildResponseVisibilityParams{GuildID: guildID, ResponseVisibility: string(visibility)})
+}
+
+func (s *PostgresStore) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	return s.q.SetGuildMassDeathAlert(ctx, db.SetGuildMassDeathAlertParams{
+		GuildID:                guildID,
+		MassDeathCount:         int32(alert.Deaths),
+		MassDeathWindowMinutes: int32(alert.Window / time.Minute),
+	})
+}
+
+func (s *PostgresStore) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	return s.q.SetGuildBroadcastOptOut(ctx, db.SetGuildBroadcastOptOutParams{GuildID: guildID, BroadcastOptOut: optOut})
+}
+
+func (s *PostgresStore) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	return s.q.SetGuildMinLevel(ctx, db.SetGuildMinLevelParams{GuildID: guildID, MinLevel: int32(level)})
+}
+
+func (s *PostgresStore) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	return s.q.SetGuildMutedUntil(ctx, db.SetGuildMutedUntilParams{
+		GuildID:    guildID,
+		MutedUntil: pgtype.Timestamptz{Time: until, Valid: !until.IsZero()},
+	})
+}
+
+func (s *PostgresStore) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	return s.q.SetGuildLowLevelDeaths(ctx, db.SetGuildLowLevelDeathsParams{
+		GuildID:        guildID,
+		LowLevelDeaths: enabled,
+	})
+}
+
+func (s *PostgresStore) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	return s.q.SetGuildTimezone(ctx, db.SetGuildTimezoneParams{
+		GuildID:  guildID,
+		Timezone: timezone,
+	})
+}
+
+func (s *PostgresStore) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	switch kind {
+	case domain.ChannelDeaths:
+		return s.q.SetGuildDeathTemplate(ctx, db.SetGuildDeathTemplateParams{
+			GuildID:       guildID,
+			DeathTemplate: template,
+		})
+	case domain.ChannelLevels:
+		return s.q.SetGuildLevelTemplate(ctx, db.SetGuildLevelTemplateParams{
+			GuildID:       guildID,
+			LevelTemplate: template,
+		})
+	case domain.ChannelLevelDowns:
+		return s.q.SetGuildLevelDownTemplate(ctx, db.SetGuildLevelDownTemplateParams{
+			GuildID:           guildID,
+			LevelDownTemplate: template,
+		})
+	default:
+		return fmt.Errorf("no template for notification channel: %s", kind)
+	}
+}
+
+func (s *PostgresStore) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	switch kind {
+	case domain.ChannelDeaths:
+		return s.q.SetGuildDeathEmoji(ctx, db.SetGuildDeathEmojiParams{
+			GuildID:       guildID,
+			DeathEmoji:    emoji,
+			DeathReaction: react,
+		})
+	case domain.ChannelLevels:
+		return s.q.SetGuildLevelEmoji(ctx, db.SetGuildLevelEmojiParams{
+			GuildID:       guildID,
+			LevelEmoji:    emoji,
+			LevelReaction: react,
+		})
+	default:
+		return fmt.Errorf("no emoji for notification channel: %s", kind)
+	}
+}
+
+func (s *PostgresStore) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	err := s.q.SetDeathRoute(ctx, db.SetDeathRouteParams{
+		GuildID:   guildID,
+		MinLevel:  int32(minLevel),
+		ChannelID: channelID,
+	})
+	if err != nil {
+		return fmt.Errorf("set death route: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	tag, err := s.q.DeleteDeathRoute(ctx, db.DeleteDeathRouteParams{
+		GuildID:  guildID,
+		MinLevel: int32(minLevel),
+	})
+	if err != nil {
+		return false, fmt.Errorf("delete death route: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStore) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	err := s.q.SetSkillThreshold(ctx, db.SetSkillThresholdParams{
+		GuildID:  guildID,
+		Skill:    string(skill),
+		MinValue: int32(minValue),
+	})
+	if err != nil {
+		return fmt.Errorf("set skill threshold: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	tag, err := s.q.DeleteSkillThreshold(ctx, db.DeleteSkillThresholdParams{
+		GuildID: guildID,
+		Skill:   string(skill),
+	})
+	if err != nil {
+		return false, fmt.Errorf("delete skill threshold: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStore) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	return s.q.SetGuildQuietHours(ctx, db.SetGuildQuietHoursParams{
+		GuildID:      guildID,
+		QuietStart:   int32(quiet.Start),
+		QuietEnd:     int32(quiet.End),
+		QuietCatchUp: quiet.CatchUp,
+	})
+}
+
+func (s *PostgresStore) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	return s.q.SetGuildQuota(ctx, db.SetGuildQuotaParams{
+		GuildID:             guildID,
+		QuotaTibiaGuilds:    int32(quota.TibiaGuilds),
+		QuotaIgnoredPlayers: int32(quota.IgnoredPlayers),
+		QuotaWatchedPlayers: int32(quota.WatchedPlayers),
+	})
+}
+
+func (s *PostgresStore) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	return s.q.SetGuildPremium(ctx, db.SetGuildPremiumParams{GuildID: guildID, Premium: premium})
+}
+
+func (s *PostgresStore) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	return s.q.SetGuildLastNotified(ctx, db.SetGuildLastNotifiedParams{
+		GuildID:        guildID,
+		LastNotifiedAt: pgtype.Timestamptz{Time: at, Valid: !at.IsZero()},
+	})
+}
+
+func (s *PostgresStore) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	return s.q.MarkGuildRemoved(ctx, db.MarkGuildRemovedParams{
+		GuildID:   guildID,
+		RemovedAt: pgtype.Timestamptz{Time: at, Valid: true},
+	})
+}
+
+func (s *PostgresStore) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	tag, err := s.q.RestoreGuildConfig(ctx, guildID)
+	if err != nil {
+		return false, fmt.Errorf("restore guild config: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStore) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteRemovedGuildConfigs(ctx, pgtype.Timestamptz{Time: removedBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete removed guild configs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	return s.q.SaveStoppedGuildConfig(ctx, db.SaveStoppedGuildConfigParams{
+		GuildID:   stopped.DiscordGuildID,
+		Config:    stopped.Snapshot,
+		StoppedAt: pgtype.Timestamptz{Time: stopped.StoppedAt, Valid: true},
+	})
+}
+
+func (s *PostgresStore) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	row, err := s.q.GetStoppedGuildConfig(ctx, guildID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get stopped guild config: %w", err)
+	}
+	return &domain.StoppedGuildConfig{
+		DiscordGuildID: row.GuildID,
+		Snapshot:       row.Config,
+		StoppedAt:      row.StoppedAt.Time,
+	}, nil
+}
+
+func (s *PostgresStore) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	return s.q.DeleteStoppedGuildConfig(ctx, guildID)
+}
+
+func (s *PostgresStore) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteStoppedGuildConfigs(ctx, pgtype.Timestamptz{Time: stoppedBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete stopped guild configs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgeGuildData deletes everything stored for a Discord guild in one
+// statement. Members, deaths and players are only deleted when no other guild
+// still tracks the same Tibia guilds or world.
+func (s *PostgresStore) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	row, err := s.q.PurgeGuildData(ctx, guildID)
+	if err != nil {
+		return domain.PurgeResult{}, fmt.Errorf("purge guild data: %w", err)
+	}
+	return domain.PurgeResult{
+		Configs:       row.Configs,
+		Notifications: row.Notifications,
+		GuildMembers:  row.GuildMembers,
+		Deaths:        row.Deaths,
+		Players:       row.Players,
+		LevelUps:      row.LevelUps,
+	}, nil
+}
+
+func (s *PostgresStore) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return s.q.AddIgnoredPlayer(ctx, db.AddIgnoredPlayerParams{
+		GuildID: guildID,
+		Name:    domain.NormalizeName(name),
+	})
+}
+
+func (s *PostgresStore) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return s.q.RemoveIgnoredPlayer(ctx, db.RemoveIgnoredPlayerParams{
+		GuildID: guildID,
+		Name:    domain.NormalizeName(name),
+	})
+}
+
+func (s *PostgresStore) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return s.q.AddWatchedPlayer(ctx, db.AddWatchedPlayerParams{
+		GuildID: guildID,
+		Name:    domain.NormalizeName(name),
+	})
+}
+
+func (s *PostgresStore) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return s.q.RemoveWatchedPlayer(ctx, db.RemoveWatchedPlayerParams{
+		GuildID: guildID,
+		Name:    domain.NormalizeName(name),
 	})
 }
 
@@ -98,12 +560,47 @@ func (s *PostgresStore) RemoveGuildFromConfig(ctx context.Context, guildID, tibi
 
 func (s *PostgresStore) UpsertPlayerLevel(ctx context.Context, name string, level int, world string) error {
 	return s.q.UpsertPlayerLevel(ctx, db.UpsertPlayerLevelParams{
-		Name:  name,
+		Name:  domain.NormalizeName(name),
 		Level: int32(level),
 		World: world,
 	})
 }
 
+// RenamePlayer moves the character's level, deaths, level history, guild
+// memberships, ignore and watch entries from oldName to newName in one statement.
+func (s *PostgresStore) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	params := db.RenamePlayerParams{NewName: domain.NormalizeName(newName), OldName: domain.NormalizeName(oldName)}
+	if err := s.q.RenamePlayer(ctx, params); err != nil {
+		return fmt.Errorf("rename player %s to %s: %w", oldName, newName, err)
+	}
+	return nil
+}
+
+// BatchUpsertPlayerLevels writes all levels in one statement. Names that are
+// the same once normalized keep the last entry, since ON CONFLICT cannot
+// touch a row twice.
+func (s *PostgresStore) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	if len(levels) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(levels))
+	params := db.BatchUpsertPlayerLevelsParams{}
+	for _, l := range levels {
+		name := domain.NormalizeName(l.Name)
+		if i, ok := index[name]; ok {
+			params.Levels[i] = int32(l.Level)
+			params.Worlds[i] = l.World
+			continue
+		}
+		index[name] = len(params.Names)
+		params.Names = append(params.Names, name)
+		params.Levels = append(params.Levels, int32(l.Level))
+		params.Worlds = append(params.Worlds, l.World)
+	}
+	return s.q.BatchUpsertPlayerLevels(ctx, params)
+}
+
 func (s *PostgresStore) GetPlayersLevels(ctx context.Context, world string) (map[string]int, error) {
 	rows, err := s.q.GetPlayersLevels(ctx, world)
 	if err != nil {
@@ -117,6 +614,50 @@ func (s *PostgresStore) GetPlayersLevels(ctx context.Context, world string) (map
 	return result, nil
 }
 
+// likeEscaper escapes LIKE wildcards so a prefix matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *PostgresStore) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	names, err := s.q.GetPlayersByPrefix(ctx, db.GetPlayersByPrefixParams{
+		World:      world,
+		Prefix:     likeEscaper.Replace(domain.NormalizeName(prefix)),
+		MaxResults: domain.MaxNameSuggestions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get players by prefix: %w", err)
+	}
+	return names, nil
+}
+
+func (s *PostgresStore) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	rows, err := s.q.GetPlayerSkills(ctx, db.GetPlayerSkillsParams{World: world, Skill: string(skill)})
+	if err != nil {
+		return nil, fmt.Errorf("get player skills: %w", err)
+	}
+
+	result := make(map[string]int, len(rows))
+	for _, row := range rows {
+		result[row.Name] = int(row.Value)
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	params := db.BatchUpsertPlayerSkillsParams{World: world, Skill: string(skill)}
+	for name, value := range values {
+		params.Names = append(params.Names, name)
+		params.SkillValues = append(params.SkillValues, int32(value))
+	}
+	if err := s.q.BatchUpsertPlayerSkills(ctx, params); err != nil {
+		return fmt.Errorf("save player skills: %w", err)
+	}
+	return nil
+}
+
 func (s *PostgresStore) BatchTouchPlayers(ctx context.Context, names []string) error {
 	if len(names) == 0 {
 		return nil
@@ -157,3 +698,564 @@ func (s *PostgresStore) GetOfflinePlayers(ctx context.Context, world string, onl
 	}
 	return result, nil
 }
+
+// -- Death Log Methods --
+
+func (s *PostgresStore) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	return s.q.RecordDeath(ctx, db.RecordDeathParams{
+		Name:    domain.NormalizeName(name),
+		World:   world,
+		Level:   int32(kill.Level),
+		Reason:  kill.Reason,
+		DiedAt:  pgtype.Timestamptz{Time: kill.Time, Valid: true},
+		Killers: kill.PlayerKillers(),
+	})
+}
+
+func (s *PostgresStore) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	if len(victims) == 0 || len(killers) == 0 {
+		return 0, nil
+	}
+	count, err := s.q.CountKillsBetween(ctx, db.CountKillsBetweenParams{
+		World:   world,
+		Since:   pgtype.Timestamptz{Time: since, Valid: true},
+		Until:   pgtype.Timestamptz{Time: until, Valid: true},
+		Victims: victims,
+		Killers: killers,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count kills: %w", err)
+	}
+	return int(count), nil
+}
+
+func (s *PostgresStore) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	count, err := s.q.CountDeathsSince(ctx, db.CountDeathsSinceParams{
+		Name:  domain.NormalizeName(name),
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count deaths: %w", err)
+	}
+	return int(count), nil
+}
+
+func (s *PostgresStore) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	rows, err := s.q.GetDeathCountsSince(ctx, db.GetDeathCountsSinceParams{
+		World: world,
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get death counts: %w", err)
+	}
+
+	result := make([]domain.DeathCount, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.DeathCount{
+			Name:  row.Name,
+			Count: int(row.Deaths),
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	if guildNames == nil {
+		guildNames = []string{}
+	}
+	rows, err := s.q.GetTopKillersSince(ctx, db.GetTopKillersSinceParams{
+		World:      world,
+		Since:      pgtype.Timestamptz{Time: since, Valid: true},
+		GuildNames: guildNames,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get top killers: %w", err)
+	}
+
+	result := make([]domain.KillerCount, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.KillerCount{
+			Name:  row.Killer,
+			Kills: int(row.Kills),
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	if guildNames == nil {
+		guildNames = []string{}
+	}
+	rows, err := s.q.GetFragCountsSince(ctx, db.GetFragCountsSinceParams{
+		World:      world,
+		Since:      pgtype.Timestamptz{Time: since, Valid: true},
+		GuildNames: guildNames,
+		Name:       domain.NormalizeName(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get frag counts: %w", err)
+	}
+
+	result := make([]domain.FragCount, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.FragCount{
+			Name:   row.Name,
+			Frags:  int(row.Frags),
+			Deaths: int(row.Deaths),
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	if guildNames == nil {
+		guildNames = []string{}
+	}
+	rows, err := s.q.GetDeathsPage(ctx, db.GetDeathsPageParams{
+		World:      world,
+		Since:      pgtype.Timestamptz{Time: since, Valid: true},
+		AfterID:    afterID,
+		GuildNames: guildNames,
+		PageSize:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get deaths page: %w", err)
+	}
+
+	result := make([]domain.DeathRecord, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.DeathRecord{
+			ID:      row.ID,
+			Name:    row.Name,
+			World:   row.World,
+			Level:   int(row.Level),
+			Reason:  row.Reason,
+			Killers: row.Killers,
+			DiedAt:  row.DiedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteDeathsBefore(ctx, pgtype.Timestamptz{Time: diedBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete old deaths: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return s.q.RecordOnlinePresence(ctx, db.RecordOnlinePresenceParams{
+		World:    world,
+		SeenHour: pgtype.Timestamptz{Time: at.Truncate(time.Hour), Valid: true},
+		Names:    domain.NormalizeNames(names),
+	})
+}
+
+func (s *PostgresStore) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	if guildNames == nil {
+		guildNames = []string{}
+	}
+	rows, err := s.q.GetOnlineCountsSince(ctx, db.GetOnlineCountsSinceParams{
+		World:      world,
+		Since:      pgtype.Timestamptz{Time: since, Valid: true},
+		GuildNames: guildNames,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get online counts: %w", err)
+	}
+
+	result := make([]domain.OnlineCount, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.OnlineCount{Hour: row.SeenHour.Time, Players: int(row.Players)})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteOnlinePresenceBefore(ctx, pgtype.Timestamptz{Time: seenBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete old online presence: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	return s.q.RecordOnlineCount(ctx, db.RecordOnlineCountParams{
+		World:     world,
+		SampledAt: pgtype.Timestamptz{Time: at, Valid: true},
+		Players:   int32(players),
+	})
+}
+
+func (s *PostgresStore) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	rows, err := s.q.GetWorldOnlineCountsSince(ctx, db.GetWorldOnlineCountsSinceParams{
+		World: world,
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get world online counts: %w", err)
+	}
+
+	result := make([]domain.OnlineCount, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.OnlineCount{Hour: row.Hour.Time, Players: int(row.Players)})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	tag, err := s.q.DownsampleOnlineCounts(ctx, pgtype.Timestamptz{Time: sampledBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("downsample online counts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteHourlyOnlineCountsBefore(ctx, pgtype.Timestamptz{Time: hourBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete old online counts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return s.q.RecordOnlineSessions(ctx, db.RecordOnlineSessionsParams{
+		Names:     domain.NormalizeNames(names),
+		SeenAt:    pgtype.Timestamptz{Time: at, Valid: true},
+		World:     world,
+		OpenSince: pgtype.Timestamptz{Time: openSince, Valid: true},
+	})
+}
+
+func (s *PostgresStore) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	seconds, err := s.q.GetOnlineTimeSince(ctx, db.GetOnlineTimeSinceParams{
+		Name:  domain.NormalizeName(name),
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get online time for %s: %w", name, err)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func (s *PostgresStore) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteOnlineSessionsBefore(ctx, pgtype.Timestamptz{Time: lastSeenBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete old online sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RecordLevelUp stores the level up at its ReachedAt, or now when it is not
+// set.
+func (s *PostgresStore) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	return s.q.RecordLevelUp(ctx, db.RecordLevelUpParams{
+		Name:      domain.NormalizeName(levelUp.PlayerName),
+		World:     levelUp.World,
+		OldLevel:  int32(levelUp.OldLevel),
+		NewLevel:  int32(levelUp.NewLevel),
+		ReachedAt: pgtype.Timestamptz{Time: levelUp.ReachedAt, Valid: !levelUp.ReachedAt.IsZero()},
+	})
+}
+
+func (s *PostgresStore) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	rows, err := s.q.GetLevelUpsSince(ctx, db.GetLevelUpsSinceParams{
+		Name:  domain.NormalizeName(name),
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get level ups: %w", err)
+	}
+
+	result := make([]domain.LevelUp, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.LevelUp{
+			PlayerName: row.Name,
+			World:      row.World,
+			OldLevel:   int(row.OldLevel),
+			NewLevel:   int(row.NewLevel),
+			ReachedAt:  row.ReachedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	rows, err := s.q.GetDailyLevelGains(ctx, db.GetDailyLevelGainsParams{
+		Name:  domain.NormalizeName(name),
+		Since: pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get daily level gains: %w", err)
+	}
+
+	result := make([]domain.DailyLevelGain, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.DailyLevelGain{
+			Day:    row.Day.Time,
+			Levels: int(row.Levels),
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteLevelUpsBefore(ctx, pgtype.Timestamptz{Time: reachedBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete old level ups: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *PostgresStore) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	if guildNames == nil {
+		guildNames = []string{}
+	}
+	rows, err := s.q.GetLevelUpsPage(ctx, db.GetLevelUpsPageParams{
+		World:      world,
+		Since:      pgtype.Timestamptz{Time: since, Valid: true},
+		AfterID:    afterID,
+		GuildNames: guildNames,
+		PageSize:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get level ups page: %w", err)
+	}
+
+	result := make([]domain.LevelUpRecord, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.LevelUpRecord{
+			ID:        row.ID,
+			Name:      row.Name,
+			World:     row.World,
+			OldLevel:  int(row.OldLevel),
+			NewLevel:  int(row.NewLevel),
+			ReachedAt: row.ReachedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+// -- House Auction Methods --
+
+func (s *PostgresStore) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	rows, err := s.q.GetHouseAuctions(ctx, db.GetHouseAuctionsParams{
+		World:     world,
+		SeenSince: pgtype.Timestamptz{Time: seenSince, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get house auctions: %w", err)
+	}
+
+	result := make([]domain.HouseAuction, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.HouseAuction{
+			HouseID:    int(row.HouseID),
+			Name:       row.Name,
+			Town:       row.Town,
+			World:      row.World,
+			CurrentBid: int(row.CurrentBid),
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	params := db.ReplaceHouseAuctionsParams{
+		World:       world,
+		HouseIds:    make([]int32, 0, len(auctions)),
+		Names:       make([]string, 0, len(auctions)),
+		Towns:       make([]string, 0, len(auctions)),
+		CurrentBids: make([]int64, 0, len(auctions)),
+	}
+	for _, a := range auctions {
+		params.HouseIds = append(params.HouseIds, int32(a.HouseID))
+		params.Names = append(params.Names, a.Name)
+		params.Towns = append(params.Towns, a.Town)
+		params.CurrentBids = append(params.CurrentBids, int64(a.CurrentBid))
+	}
+	if err := s.q.ReplaceHouseAuctions(ctx, params); err != nil {
+		return fmt.Errorf("replace house auctions: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	names, err := s.q.GetGuildMemberNames(ctx, guildName)
+	if err != nil {
+		return nil, fmt.Errorf("get guild members: %w", err)
+	}
+	return names, nil
+}
+
+func (s *PostgresStore) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return s.q.AddGuildMembers(ctx, db.AddGuildMembersParams{
+		GuildName: guildName,
+		Names:     names,
+	})
+}
+
+func (s *PostgresStore) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return s.q.RemoveGuildMembers(ctx, db.RemoveGuildMembersParams{
+		GuildName: guildName,
+		Names:     names,
+	})
+}
+
+func (s *PostgresStore) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	rows, err := s.q.GetGuildMemberCache(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get guild member cache: %w", err)
+	}
+
+	result := make([]domain.GuildMemberList, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.GuildMemberList{
+			GuildName: row.GuildName,
+			Members:   row.Members,
+			FetchedAt: row.FetchedAt.Time,
+		})
+	}
+	return result, nil
+}
+
+func (s *PostgresStore) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	members := list.Members
+	if members == nil {
+		members = []string{}
+	}
+	err := s.q.SaveGuildMemberCache(ctx, db.SaveGuildMemberCacheParams{
+		GuildName: list.GuildName,
+		Members:   members,
+		FetchedAt: pgtype.Timestamptz{Time: list.FetchedAt, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("save guild member cache: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	if keep == nil {
+		keep = []string{}
+	}
+	tag, err := s.q.DeleteGuildMemberCacheExcept(ctx, keep)
+	if err != nil {
+		return 0, fmt.Errorf("delete guild member cache: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// -- Guild War Methods --
+
+func (s *PostgresStore) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	row, err := s.q.GetLatestGuildWar(ctx, domain.NormalizeName(guildName))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get guild war: %w", err)
+	}
+	return &domain.GuildWar{
+		GuildName: row.GuildName,
+		StartedAt: row.StartedAt.Time,
+		EndedAt:   row.EndedAt.Time,
+	}, nil
+}
+
+func (s *PostgresStore) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	return s.q.StartGuildWar(ctx, db.StartGuildWarParams{
+		GuildName: domain.NormalizeName(guildName),
+		StartedAt: pgtype.Timestamptz{Time: startedAt, Valid: true},
+	})
+}
+
+func (s *PostgresStore) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	return s.q.EndGuildWar(ctx, db.EndGuildWarParams{
+		GuildName: domain.NormalizeName(guildName),
+		EndedAt:   pgtype.Timestamptz{Time: endedAt, Valid: true},
+	})
+}
+
+func (s *PostgresStore) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	return s.q.EnqueueFailedNotification(ctx, db.EnqueueFailedNotificationParams{
+		GuildID:       n.DiscordGuildID,
+		Kind:          string(n.Kind),
+		Payload:       n.Payload,
+		LastError:     n.LastError,
+		NextAttemptAt: pgtype.Timestamptz{Time: n.NextAttemptAt, Valid: true},
+	})
+}
+
+func (s *PostgresStore) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	rows, err := s.q.GetDueFailedNotifications(ctx, db.GetDueFailedNotificationsParams{
+		Due:      pgtype.Timestamptz{Time: due, Valid: true},
+		MaxItems: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get due failed notifications: %w", err)
+	}
+	return mapFailedNotifications(rows), nil
+}
+
+func (s *PostgresStore) GetGuildFailedNotifications(ctx context.Context, discordGuildID string) ([]domain.FailedNotification, error) {
+	rows, err := s.q.GetGuildFailedNotifications(ctx, discordGuildID)
+	if err != nil {
+		return nil, fmt.Errorf("get guild failed notifications: %w", err)
+	}
+	return mapFailedNotifications(rows), nil
+}
+
+func (s *PostgresStore) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	return s.q.RescheduleFailedNotification(ctx, db.RescheduleFailedNotificationParams{
+		ID:            id,
+		LastError:     lastErr,
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttempt, Valid: true},
+	})
+}
+
+func (s *PostgresStore) DeleteFailedNotification(ctx context.Context, id int64) error {
+	return s.q.DeleteFailedNotification(ctx, id)
+}
+
+func (s *PostgresStore) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	tag, err := s.q.DeleteExpiredFailedNotifications(ctx, pgtype.Timestamptz{Time: createdBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete expired failed notifications: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func mapFailedNotifications(rows []db.FailedNotification) []domain.FailedNotification {
+	result := make([]domain.FailedNotification, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, domain.FailedNotification{
+			ID:             row.ID,
+			DiscordGuildID: row.GuildID,
+			Kind:           domain.NotificationKind(row.Kind),
+			Payload:        row.Payload,
+			Attempts:       int(row.Attempts),
+			LastError:      row.LastError,
+			NextAttemptAt:  row.NextAttemptAt.Time,
+			CreatedAt:      row.CreatedAt.Time,
+		})
+	}
+	return result
+}
+
+func quietHours(start, end int32, catchUp bool) domain.QuietHours {
+	return domain.QuietHours{Start: int(start), End: int(end), CatchUp: catchUp}
+}
+
+func massDeathAlert(deaths, windowMinutes int32) domain.MassDeathAlert {
+	return domain.MassDeathAlert{Deaths: int(deaths), Window: time.Duration(windowMinutes) * time.Minute}
+}
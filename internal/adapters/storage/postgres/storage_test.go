@@ -4,13 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+	"unicode"
 
 	"death-level-tracker/internal/adapters/storage/postgres/db"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/services"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func TestPostgresStore_SaveGuildWorld(t *testing.T) {
@@ -92,20 +100,151 @@ func TestPostgresStore_GetGuildConfig(t *testing.T) {
 			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 				return &MockRow{
 					ScanFunc: func(dest ...any) error {
-						return errors.New("no rows in result set") // mimicking pgx.ErrNoRows behavior conceptually
+						return pgx.ErrNoRows
 					},
 				}
 			},
 		}
 
 		store := &PostgresStore{q: db.New(mockDB)}
-		_, err := store.GetGuildConfig(ctx, "unknown")
-		if err == nil {
+		cfg, err := store.GetGuildConfig(ctx, "unknown")
+		if err != nil || cfg != nil {
+			t.Fatalf("Expected no config and no error, got %+v, %v", cfg, err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockDB := &MockDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+				return &MockRow{
+					ScanFunc: func(dest ...any) error {
+						return errors.New("connection refused")
+					},
+				}
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		if _, err := store.GetGuildConfig(ctx, "guild123"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+// TestPostgresStore_AddGuildWithoutWorld runs the service over the store, as
+// the not-found contract of GetGuildConfig is what the service relies on.
+func TestPostgresStore_AddGuildWithoutWorld(t *testing.T) {
+	mockDB := &MockDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			return &MockRow{
+				ScanFunc: func(dest ...any) error {
+					return pgx.ErrNoRows
+				},
+			}
+		},
+	}
+	svc := services.NewConfigurationService(&PostgresStore{q: db.New(mockDB)}, nil, services.Limits{}, nil)
+
+	if err := svc.CheckAddGuild(context.Background(), "unknown", "Red Rose"); !errors.Is(err, services.ErrNoWorldTracked) {
+		t.Errorf("Expected ErrNoWorldTracked from CheckAddGuild, got %v", err)
+	}
+	if _, err := svc.AddGuildToTrack(context.Background(), "unknown", "Red Rose"); !errors.Is(err, services.ErrNoWorldTracked) {
+		t.Errorf("Expected ErrNoWorldTracked from AddGuildToTrack, got %v", err)
+	}
+}
+
+func TestPostgresStore_SetGuildLanguage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				if len(args) != 2 || args[0] != "guild123" || args[1] != "pl" {
+					return pgconn.CommandTag{}, fmt.Errorf("unexpected args: %v", args)
+				}
+				return pgconn.NewCommandTag("INSERT 1"), nil
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		if err := store.SetGuildLanguage(ctx, "guild123", "pl"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.CommandTag{}, errors.New("db error")
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		if err := store.SetGuildLanguage(ctx, "guild123", "pl"); err == nil {
 			t.Fatal("Expected error, got nil")
 		}
 	})
 }
 
+func TestPostgresStore_SetGuildChannel(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		kind      domain.NotificationChannel
+		wantQuery string
+	}{
+		{"Deaths", domain.ChannelDeaths, "death_channel_id"},
+		{"Levels", domain.ChannelLevels, "level_channel_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &MockDB{
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+					if !strings.Contains(sql, tt.wantQuery) {
+						return pgconn.CommandTag{}, fmt.Errorf("unexpected query: %s", sql)
+					}
+					if args[0] != "guild123" || args[1] != "chan-1" {
+						return pgconn.CommandTag{}, fmt.Errorf("unexpected args: %v", args)
+					}
+					return pgconn.NewCommandTag("INSERT 1"), nil
+				},
+			}
+
+			store := &PostgresStore{q: db.New(mockDB)}
+			if err := store.SetGuildChannel(ctx, "guild123", tt.kind, "chan-1"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("Unknown Kind", func(t *testing.T) {
+		store := &PostgresStore{q: db.New(&MockDB{})}
+		if err := store.SetGuildChannel(ctx, "guild123", "other", "chan-1"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestPostgresStore_SetGuildPollInterval(t *testing.T) {
+	ctx := context.Background()
+
+	mockDB := &MockDB{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			if len(args) != 2 || args[0] != "guild123" || args[1] != int32(120) {
+				return pgconn.CommandTag{}, fmt.Errorf("unexpected args: %v", args)
+			}
+			return pgconn.NewCommandTag("INSERT 1"), nil
+		},
+	}
+
+	store := &PostgresStore{q: db.New(mockDB)}
+	if err := store.SetGuildPollInterval(ctx, "guild123", 2*time.Minute); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
 func TestPostgresStore_UpsertPlayerLevel(t *testing.T) {
 	ctx := context.Background()
 
@@ -131,6 +270,34 @@ func TestPostgresStore_GetAllGuildConfigs(t *testing.T) {
 		mockDB := &MockDB{
 			QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
 				count := 0
+				if strings.Contains(sql, "death_routes") {
+					return &MockRows{
+						NextFunc: func() bool {
+							count++
+							return count <= 1
+						},
+						ScanFunc: func(dest ...any) error {
+							*dest[0].(*string) = "guild1"
+							*dest[1].(*int32) = 500
+							*dest[2].(*string) = "high-deaths"
+							return nil
+						},
+					}, nil
+				}
+				if strings.Contains(sql, "skill_thresholds") {
+					return &MockRows{
+						NextFunc: func() bool {
+							count++
+							return count <= 1
+						},
+						ScanFunc: func(dest ...any) error {
+							*dest[0].(*string) = "guild2"
+							*dest[1].(*string) = string(domain.SkillMagic)
+							*dest[2].(*int32) = 100
+							return nil
+						},
+					}, nil
+				}
 				return &MockRows{
 					NextFunc: func() bool {
 						count++
@@ -154,7 +321,16 @@ func TestPostgresStore_GetAllGuildConfigs(t *testing.T) {
 		}
 
 		if len(configs) != 2 {
-			t.Errorf("Expected 2 configs, got %d", len(configs))
+			t.Fatalf("Expected 2 configs, got %d", len(configs))
+		}
+		if got := configs[0].DeathRoutes; len(got) != 1 || got[0] != (domain.DeathRoute{MinLevel: 500, ChannelID: "high-deaths"}) {
+			t.Errorf("Unexpected death routes for guild1: %v", got)
+		}
+		if len(configs[1].DeathRoutes) != 0 {
+			t.Errorf("Expected no death routes for guild2, got %v", configs[1].DeathRoutes)
+		}
+		if got := configs[1].SkillThresholds; got[domain.SkillMagic] != 100 || configs[0].SkillThresholds != nil {
+			t.Errorf("Expected a magic level threshold for guild2 only, got %v and %v", configs[0].SkillThresholds, got)
 		}
 	})
 
@@ -172,6 +348,189 @@ func TestPostgresStore_GetAllGuildConfigs(t *testing.T) {
 	})
 }
 
+// guildConfigColumns lists the columns a guild_configs query selects.
+func guildConfigColumns(query string) []string {
+	_, list, _ := strings.Cut(query, "SELECT ")
+	list, _, _ = strings.Cut(list, " FROM guild_configs")
+	return strings.Split(list, ", ")
+}
+
+// columnName turns a sqlc field name such as DeathChannelID back into its
+// column name.
+func columnName(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(field[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// guildConfigRow gives every guild_configs column a distinct non-zero value
+// of the type sqlc maps it to.
+func guildConfigRow(t *testing.T) map[string]any {
+	t.Helper()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	row := make(map[string]any)
+	fields := reflect.TypeOf(db.GuildConfig{})
+	for i := range fields.NumField() {
+		field := fields.Field(i)
+		column := columnName(field.Name)
+		switch field.Type {
+		case reflect.TypeOf(""):
+			row[column] = column
+		case reflect.TypeOf(int32(0)):
+			row[column] = int32(i + 1)
+		case reflect.TypeOf(false):
+			row[column] = true
+		case reflect.TypeOf([]string{}):
+			row[column] = []string{column}
+		case reflect.TypeOf(pgtype.Timestamptz{}):
+			row[column] = pgtype.Timestamptz{Time: base.Add(time.Duration(i) * time.Minute), Valid: true}
+		case reflect.TypeOf(pgtype.Timestamp{}):
+			row[column] = pgtype.Timestamp{Time: base.Add(time.Duration(i) * time.Minute), Valid: true}
+		default:
+			t.Fatalf("no test value for %s of type %s", field.Name, field.Type)
+		}
+	}
+	return row
+}
+
+// scanColumns scans row into dest by the columns query selects, failing like
+// pgx when the number or types of the targets do not match.
+func scanColumns(row map[string]any, query string, dest []any) error {
+	columns := guildConfigColumns(query)
+	if len(dest) != len(columns) {
+		return fmt.Errorf("%d scan targets for %d columns", len(dest), len(columns))
+	}
+	for i, column := range columns {
+		value, ok := row[column]
+		if !ok {
+			return fmt.Errorf("unknown column %q", column)
+		}
+		target := reflect.ValueOf(dest[i]).Elem()
+		if !reflect.TypeOf(value).AssignableTo(target.Type()) {
+			return fmt.Errorf("cannot scan %s into %s", column, target.Type())
+		}
+		target.Set(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+// assertRowColumns checks that every field of a sqlc row holds the value of
+// its own column.
+func assertRowColumns(t *testing.T, row map[string]any, got any) {
+	t.Helper()
+	v := reflect.ValueOf(got)
+	for i := range v.NumField() {
+		name := v.Type().Field(i).Name
+		if want := row[columnName(name)]; !reflect.DeepEqual(v.Field(i).Interface(), want) {
+			t.Errorf("%T.%s = %v, want %v", got, name, v.Field(i).Interface(), want)
+		}
+	}
+}
+
+// sqlFile reads a file of the repository's sql directory.
+func sqlFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "sql", name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+// TestPostgresStore_GuildConfigRoundTrip catches drift between the
+// hand-edited sqlc code, sql/queries.sql and sql/schema.sql: a guild config
+// is read through GetGuildConfig and GetAllGuildConfigs from a database that
+// scans by the selected columns.
+func TestPostgresStore_GuildConfigRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	row := guildConfigRow(t)
+	queries := make(map[string]string)
+	mockDB := &MockDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			queries["GetGuildConfig"] = sql
+			return &MockRow{ScanFunc: func(dest ...any) error { return scanColumns(row, sql, dest) }}
+		},
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			if !strings.Contains(sql, "FROM guild_configs") {
+				return &MockRows{}, nil
+			}
+			queries["GetWorldsMap"] = sql
+			done := false
+			return &MockRows{
+				NextFunc: func() bool {
+					defer func() { done = true }()
+					return !done
+				},
+				ScanFunc: func(dest ...any) error { return scanColumns(row, sql, dest) },
+			}, nil
+		},
+	}
+	q := db.New(mockDB)
+
+	worldsRows, err := q.GetWorldsMap(ctx)
+	if err != nil {
+		t.Fatalf("GetWorldsMap: %v", err)
+	}
+	assertRowColumns(t, row, worldsRows[0])
+	configRow, err := q.GetGuildConfig(ctx, "guild_id")
+	if err != nil {
+		t.Fatalf("GetGuildConfig: %v", err)
+	}
+	assertRowColumns(t, row, configRow)
+
+	t.Run("matches the sql files", func(t *testing.T) {
+		schema := sqlFile(t, "schema.sql")
+		_, table, _ := strings.Cut(schema, "CREATE TABLE IF NOT EXISTS guild_configs (\n")
+		table, _, _ = strings.Cut(table, "\n);")
+		var schemaColumns []string
+		for _, line := range strings.Split(table, "\n") {
+			schemaColumns = append(schemaColumns, strings.Fields(line)[0])
+		}
+		if got := guildConfigColumns(queries["GetGuildConfig"]); !reflect.DeepEqual(got, schemaColumns) {
+			t.Errorf("GetGuildConfig selects %v, schema.sql has %v", got, schemaColumns)
+		}
+
+		_, want, _ := strings.Cut(sqlFile(t, "queries.sql"), "-- name: GetWorldsMap :many\n")
+		want, _, _ = strings.Cut(want, ";")
+		_, got, _ := strings.Cut(queries["GetWorldsMap"], "\n")
+		if strings.TrimSpace(got) != strings.TrimSpace(want) {
+			t.Errorf("GetWorldsMap differs from queries.sql:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	store := &PostgresStore{q: q}
+	cfg, err := store.GetGuildConfig(ctx, "guild_id")
+	if err != nil {
+		t.Fatalf("GetGuildConfig: %v", err)
+	}
+	configs, err := store.GetAllGuildConfigs(ctx)
+	if err != nil {
+		t.Fatalf("GetAllGuildConfigs: %v", err)
+	}
+	if len(configs) != 1 {
+		t.Fatalf("expected 1 config, got %d", len(configs))
+	}
+	// Only the death routes and skill thresholds come from other tables.
+	got, want := configs[0], *cfg
+	got.DeathRoutes, got.SkillThresholds = nil, nil
+	want.DeathRoutes, want.SkillThresholds = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllGuildConfigs and GetGuildConfig disagree:\n%+v\n%+v", got, want)
+	}
+	fields := reflect.ValueOf(want)
+	for i := range fields.NumField() {
+		name := fields.Type().Field(i).Name
+		if name != "DeathRoutes" && name != "SkillThresholds" && fields.Field(i).IsZero() {
+			t.Errorf("GuildConfig.%s is not mapped from its column", name)
+		}
+	}
+}
+
 func TestPostgresStore_GetPlayersLevels(t *testing.T) {
 	ctx := context.Background()
 
@@ -221,6 +580,163 @@ func TestPostgresStore_GetPlayersLevels(t *testing.T) {
 	})
 }
 
+func TestPostgresStore_GetPlayersByPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	var query string
+	var args []any
+	mockDB := &MockDB{
+		QueryFunc: func(ctx context.Context, sql string, a ...any) (pgx.Rows, error) {
+			query, args = sql, a
+			count := 0
+			return &MockRows{
+				NextFunc: func() bool {
+					count++
+					return count <= 1
+				},
+				ScanFunc: func(dest ...any) error {
+					*dest[0].(*string) = "Bubble_50%"
+					return nil
+				},
+			}, nil
+		},
+	}
+
+	store := &PostgresStore{q: db.New(mockDB)}
+	names, err := store.GetPlayersByPrefix(ctx, "Antica", ` Bubble_50%\ `)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Bubble_50%" {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if args[0] != "Antica" || args[1] != `Bubble\_50\%\\` || args[2] != int32(domain.MaxNameSuggestions) {
+		t.Errorf("expected the world, an escaped prefix and the suggestion limit, got %v", args)
+	}
+	if !strings.Contains(query, `ESCAPE '\'`) {
+		t.Errorf("expected the query to name its escape character, got %q", query)
+	}
+}
+
+func TestPostgresStore_GetStoppedGuildConfig(t *testing.T) {
+	ctx := context.Background()
+	stoppedAt := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Found", func(t *testing.T) {
+		mockDB := &MockDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+				return &MockRow{ScanFunc: func(dest ...any) error {
+					*dest[0].(*string) = "guild123"
+					*dest[1].(*[]byte) = []byte(`{"world":"Antica"}`)
+					*dest[2].(*pgtype.Timestamptz) = pgtype.Timestamptz{Time: stoppedAt, Valid: true}
+					return nil
+				}}
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		stopped, err := store.GetStoppedGuildConfig(ctx, "guild123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stopped == nil || string(stopped.Snapshot) != `{"world":"Antica"}` || !stopped.StoppedAt.Equal(stoppedAt) {
+			t.Errorf("unexpected snapshot %+v", stopped)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockDB := &MockDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+				return &MockRow{ScanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		stopped, err := store.GetStoppedGuildConfig(ctx, "unknown")
+		if err != nil || stopped != nil {
+			t.Errorf("expected no snapshot and no error, got %+v, %v", stopped, err)
+		}
+	})
+}
+
+func TestPostgresStore_RecordOnlinePresence(t *testing.T) {
+	ctx := context.Background()
+
+	var args []any
+	mockDB := &MockDB{
+		ExecFunc: func(ctx context.Context, sql string, a ...any) (pgconn.CommandTag, error) {
+			args = a
+			return pgconn.NewCommandTag("INSERT 0 2"), nil
+		},
+	}
+
+	store := &PostgresStore{q: db.New(mockDB)}
+	at := time.Date(2026, 6, 1, 20, 41, 5, 0, time.UTC)
+	if err := store.RecordOnlinePresence(ctx, "Antica", []string{"Sir%27Lance", "Bob"}, at); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hour := args[1].(pgtype.Timestamptz)
+	names := args[2].([]string)
+	if args[0] != "Antica" || !hour.Time.Equal(time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)) || len(names) != 2 || names[0] != "Sir'Lance" {
+		t.Errorf("expected the world, the hour and normalized names, got %v", args)
+	}
+
+	args = nil
+	if err := store.RecordOnlinePresence(ctx, "Antica", nil, at); err != nil || args != nil {
+		t.Errorf("expected nobody online to skip the query, got %v, %v", args, err)
+	}
+}
+
+func TestPostgresStore_RecordOnlineSessions(t *testing.T) {
+	ctx := context.Background()
+
+	var args []any
+	mockDB := &MockDB{
+		ExecFunc: func(ctx context.Context, sql string, a ...any) (pgconn.CommandTag, error) {
+			args = a
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+	}
+
+	store := &PostgresStore{q: db.New(mockDB)}
+	at := time.Date(2026, 6, 1, 20, 41, 5, 0, time.UTC)
+	if err := store.RecordOnlineSessions(ctx, "Antica", []string{"Sir%27Lance"}, at, at.Add(-15*time.Minute)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := args[0].([]string)
+	openSince := args[3].(pgtype.Timestamptz)
+	if names[0] != "Sir'Lance" || args[2] != "Antica" || !openSince.Time.Equal(at.Add(-15*time.Minute)) {
+		t.Errorf("expected normalized names, the world and the open cutoff, got %v", args)
+	}
+
+	args = nil
+	if err := store.RecordOnlineSessions(ctx, "Antica", nil, at, at); err != nil || args != nil {
+		t.Errorf("expected nobody online to skip the query, got %v, %v", args, err)
+	}
+}
+
+func TestPostgresStore_GetOnlineTimeSince(t *testing.T) {
+	mockDB := &MockDB{
+		QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+			return &MockRow{
+				ScanFunc: func(dest ...any) error {
+					*dest[0].(*int64) = 5400
+					return nil
+				},
+			}
+		},
+	}
+
+	store := &PostgresStore{q: db.New(mockDB)}
+	online, err := store.GetOnlineTimeSince(context.Background(), "Hero", time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if online != 90*time.Minute {
+		t.Errorf("expected 90 minutes online, got %v", online)
+	}
+}
+
 func TestPostgresStore_DeleteOldPlayers(t *testing.T) {
 	ctx := context.Background()
 
@@ -256,6 +772,56 @@ func TestPostgresStore_DeleteOldPlayers(t *testing.T) {
 	})
 }
 
+func TestPostgresStore_PurgeGuildData(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockDB := &MockDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+				if len(args) != 1 || args[0] != "guild123" {
+					t.Errorf("unexpected args: %v", args)
+				}
+				return &MockRow{
+					ScanFunc: func(dest ...any) error {
+						for i, d := range dest {
+							*d.(*int64) = int64(i + 1)
+						}
+						return nil
+					},
+				}
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		result, err := store.PurgeGuildData(ctx, "guild123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		want := domain.PurgeResult{Configs: 1, Notifications: 2, GuildMembers: 3, Deaths: 4, Players: 5, LevelUps: 6}
+		if result != want {
+			t.Errorf("Expected %+v, got %+v", want, result)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockDB := &MockDB{
+			QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
+				return &MockRow{
+					ScanFunc: func(dest ...any) error {
+						return errors.New("db error")
+					},
+				}
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		if _, err := store.PurgeGuildData(ctx, "guild123"); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
 func TestPostgresStore_BatchTouchPlayers(t *testing.T) {
 	ctx := context.Background()
 
@@ -282,6 +848,53 @@ func TestPostgresStore_BatchTouchPlayers(t *testing.T) {
 	})
 }
 
+func TestPostgresStore_BatchUpsertPlayerLevels(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				t.Error("expected no query for empty batch")
+				return pgconn.CommandTag{}, nil
+			},
+		}
+		store := &PostgresStore{q: db.New(mockDB)}
+		if err := store.BatchUpsertPlayerLevels(ctx, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Single query with deduplicated normalized names", func(t *testing.T) {
+		var calls int
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				calls++
+				names, levels, worlds := args[0].([]string), args[1].([]int32), args[2].([]string)
+				if len(names) != 2 || names[0] != "A" || names[1] != "B" {
+					return pgconn.CommandTag{}, fmt.Errorf("unexpected names: %v", names)
+				}
+				if levels[0] != 150 || levels[1] != 200 || worlds[0] != "Secura" {
+					return pgconn.CommandTag{}, fmt.Errorf("unexpected rows: %v %v", levels, worlds)
+				}
+				return pgconn.NewCommandTag("INSERT 0 2"), nil
+			},
+		}
+
+		store := &PostgresStore{q: db.New(mockDB)}
+		err := store.BatchUpsertPlayerLevels(ctx, []domain.PlayerLevel{
+			{Name: "A", Level: 100, World: "Antica"},
+			{Name: "B", Level: 200, World: "Antica"},
+			{Name: " A\u00a0", Level: 150, World: "Secura"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 query, got %d", calls)
+		}
+	})
+}
+
 func TestPostgresStore_ManageGuildConfig(t *testing.T) {
 	ctx := context.Background()
 
@@ -326,6 +939,33 @@ func TestPostgresStore_ManageGuildConfig(t *testing.T) {
 			t.Errorf("Unexpected error: %v", err)
 		}
 	})
+
+	t.Run("AddIgnoredPlayer", func(t *testing.T) {
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				if len(args) != 2 || args[0] != "guild1" || args[1] != "Bubble Bot" {
+					return pgconn.CommandTag{}, fmt.Errorf("unexpected args: %v", args)
+				}
+				return pgconn.NewCommandTag("INSERT 0 1"), nil
+			},
+		}
+		store := &PostgresStore{q: db.New(mockDB)}
+		if err := store.AddIgnoredPlayer(ctx, "guild1", "Bubble Bot"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("RemoveIgnoredPlayer", func(t *testing.T) {
+		mockDB := &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag("UPDATE 1"), nil
+			},
+		}
+		store := &PostgresStore{q: db.New(mockDB)}
+		if err := store.RemoveIgnoredPlayer(ctx, "guild1", "Bubble Bot"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
 }
 
 func TestPostgresStore_GetOfflinePlayers(t *testing.T) {
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"death-level-tracker/internal/adapters/storage/postgres/db"
+	"death-level-tracker/internal/core/ports"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// txBeginner is implemented by both pgxpool.Pool and pgx.Tx; beginning on a
+// pgx.Tx creates a savepoint.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// postgresTx is a PostgresStore whose queries all run in one transaction.
+type postgresTx struct {
+	*PostgresStore
+	tx pgx.Tx
+}
+
+func (s *PostgresStore) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	tx, err := s.begin.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	return &postgresTx{
+		PostgresStore: &PostgresStore{
+			pool:          s.pool,
+			q:             db.New(newInstrumentedDB(tx, s.slowThreshold)),
+			begin:         tx,
+			slowThreshold: s.slowThreshold,
+		},
+		tx: tx,
+	}, nil
+}
+
+func (t *postgresTx) Commit(ctx context.Context) error {
+	if err := t.tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (t *postgresTx) Rollback(ctx context.Context) error {
+	if err := t.tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return fmt.Errorf("roll back transaction: %w", err)
+	}
+	return nil
+}
+
+// Close leaves the pool to the store that began the transaction.
+func (t *postgresTx) Close() {}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"death-level-tracker/internal/adapters/storage/postgres/db"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPostgresStore_BeginTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Queries run in the transaction", func(t *testing.T) {
+		var inTx bool
+		tx := &MockTx{MockDB: &MockDB{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				inTx = true
+				return pgconn.NewCommandTag("INSERT 0 1"), nil
+			},
+		}}
+		store := &PostgresStore{
+			q: db.New(&MockDB{ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				t.Error("expected no query outside the transaction")
+				return pgconn.CommandTag{}, nil
+			}}),
+			begin: &MockBeginner{tx: tx},
+		}
+
+		rtx, err := store.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer rtx.Rollback(ctx)
+		if err := rtx.SaveGuildWorld(ctx, "guild-1", "Antica"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := rtx.Commit(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := rtx.Rollback(ctx); err != nil {
+			t.Errorf("expected a rollback after commit to do nothing, got %v", err)
+		}
+
+		if !inTx || !tx.committed || tx.rolledBack {
+			t.Errorf("expected the write committed in the transaction, got inTx=%v committed=%v rolledBack=%v", inTx, tx.committed, tx.rolledBack)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		tx := &MockTx{MockDB: &MockDB{}}
+		store := &PostgresStore{begin: &MockBeginner{tx: tx}}
+
+		rtx, _ := store.BeginTx(ctx)
+		if err := rtx.Rollback(ctx); err != nil || !tx.rolledBack {
+			t.Errorf("expected the transaction rolled back, got %v", err)
+		}
+	})
+
+	t.Run("Begin error", func(t *testing.T) {
+		store := &PostgresStore{begin: &MockBeginner{err: errors.New("pool closed")}}
+		if _, err := store.BeginTx(ctx); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -2,24 +2,60 @@ package tibiadata
 
 import (
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"death-level-tracker/internal/adapters/tibiadata/api"
 	"death-level-tracker/internal/config"
 )
 
+// DefaultTibiaComBaseURL is the public tibia.com site scraped for online lists.
+const DefaultTibiaComBaseURL = "https://www.tibia.com"
+
+// DefaultTibiaComTimeout applies when TIBIACOM_TIMEOUT is not set.
+const DefaultTibiaComTimeout = 30 * time.Second
+
 type Adapter struct {
-	client         *api.Client
-	tibiaComClient *http.Client
-	config         *config.Config
+	client          *api.Client
+	tibiaComClient  *http.Client
+	tibiaComBaseURL string
+	config          *config.Config
+	characters      *characterCache
+
+	// pvpTypes holds each world's PvP type by world name. It never changes,
+	// so it is kept for the life of the process.
+	pvpMu    sync.Mutex
+	pvpTypes map[string]string
 }
 
 func NewAdapter(client *api.Client, cfg *config.Config) *Adapter {
+	baseURL := strings.TrimSuffix(cfg.TibiaComBaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultTibiaComBaseURL
+	}
+
+	timeout := cfg.TibiaComTimeout
+	if timeout <= 0 {
+		timeout = DefaultTibiaComTimeout
+	}
+
+	// The auth header is only meant for a self-hosted proxy; never leak the
+	// token to the public site.
+	var transport http.RoundTripper = api.NewTransport(cfg, cfg.TibiaComProxyURL)
+	if baseURL != DefaultTibiaComBaseURL {
+		transport = api.NewAuthRoundTripper(cfg.TibiaDataAuthHeader, cfg.TibiaDataAuthToken, transport)
+	}
+
 	return &Adapter{
-		client: client,
-		config: cfg,
+		client:          client,
+		config:          cfg,
+		characters:      newCharacterCache(cfg.CharacterCacheSize, cfg.CharacterCacheTTL),
+		pvpTypes:        make(map[string]string),
+		tibiaComBaseURL: baseURL,
 		tibiaComClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   timeout,
+			Transport: transport,
 		},
 	}
 }
@@ -0,0 +1,35 @@
+package tibiadata
+
+import (
+	"context"
+	"log/slog"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// FetchBoosted gets today's boosted creature and boss. They come from two
+// endpoints and both must succeed.
+func (a *Adapter) FetchBoosted(ctx context.Context) (*domain.Boosted, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	creatures, err := a.client.GetCreatures(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch boosted creature", "error", err)
+		return nil, classify(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	bosses, err := a.client.GetBoostableBosses(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch boosted boss", "error", err)
+		return nil, classify(err)
+	}
+
+	creature, boss := creatures.Creatures.Boosted, bosses.BoostableBosses.Boosted
+	return &domain.Boosted{
+		Creature: domain.BoostedCreature{Name: creature.Name, ImageURL: creature.ImageURL},
+		Boss:     domain.BoostedCreature{Name: boss.Name, ImageURL: boss.ImageURL},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package tibiadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestAdapter_FetchBoosted(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/creatures":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"creatures": {"boosted": {"name": "Dragon", "race": "dragon", "image_url": "https://static.tibia.com/images/library/dragon.gif", "featured": true}}}`))
+		case "/boostablebosses":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"boostable_bosses": {"boosted": {"name": "Ferumbras", "image_url": "https://static.tibia.com/images/library/ferumbras.gif", "featured": true}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	boosted, err := adapter.FetchBoosted(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := domain.Boosted{
+		Creature: domain.BoostedCreature{Name: "Dragon", ImageURL: "https://static.tibia.com/images/library/dragon.gif"},
+		Boss:     domain.BoostedCreature{Name: "Ferumbras", ImageURL: "https://static.tibia.com/images/library/ferumbras.gif"},
+	}
+	if *boosted != want {
+		t.Errorf("Expected %+v, got %+v", want, *boosted)
+	}
+}
+
+func TestAdapter_FetchBoosted_Error(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/boostablebosses" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"creatures": {"boosted": {"name": "Dragon"}}}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	if _, err := adapter.FetchBoosted(context.Background()); err == nil {
+		t.Error("Expected error when the boss cannot be fetched, got nil")
+	}
+}
@@ -2,64 +2,72 @@ package tibiadata
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"sync"
 
 	"death-level-tracker/internal/core/domain"
 )
 
+var errCachedNotFound = fmt.Errorf("character %w (cached)", domain.ErrNotFound)
+
 // FetchCharacter gets a single character's details.
 func (a *Adapter) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
-	char, err := a.client.GetCharacter(name)
-	if err != nil {
-		return nil, err
-	}
-	return a.mapCharacter(char), nil
+	return a.getCharacter(ctx, name)
 }
 
-// FetchCharacterDetails concurrently fetches details for a list of character names.
-func (a *Adapter) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
-	results := make(chan *domain.Player, len(names))
-	jobs := make(chan string, len(names))
-	workerCount := a.config.WorkerPoolSize
-
-	var wg sync.WaitGroup
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go a.worker(ctx, jobs, results, &wg)
+// getCharacter serves from the character cache when possible. Successful
+// lookups and 404s are cached; other errors are not. A lookup that misses
+// the cache gives up after CHARACTER_FETCH_TIMEOUT, so one hung character
+// page cannot hold a worker for the rest of the cycle.
+func (a *Adapter) getCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	name = domain.NormalizeName(name)
+	if player, ok := a.characters.get(name); ok {
+		if player == nil {
+			return nil, errCachedNotFound
+		}
+		return player, nil
 	}
 
-	go func() {
-		defer close(results)
-		wg.Wait()
-	}()
-
-	go func() {
-		defer close(jobs)
-		for _, name := range names {
-			jobs <- name
+	if timeout := a.config.CharacterFetchTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	char, err := a.client.GetCharacter(ctx, name)
+	if err != nil {
+		err = classify(err)
+		if errors.Is(err, domain.ErrNotFound) {
+			a.characters.put(name, nil)
 		}
-	}()
+		return nil, err
+	}
+
+	player := a.mapCharacter(char)
+	if player != nil {
+		a.characters.put(name, player)
+	}
+	return player, nil
+}
 
+// FetchCharacterDetails concurrently fetches details for a list of character
+// names, WORKER_POOL_SIZE at a time, streaming them as they arrive.
+// Characters that cannot be fetched are logged and left out; once ctx ends
+// the names still queued are dropped and the channel is closed.
+func (a *Adapter) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
+	results, _ := runPool(ctx, names, poolOptions{workers: a.config.WorkerPoolSize}, a.fetchCharacterDetail)
 	return results, nil
 }
 
-func (a *Adapter) worker(ctx context.Context, jobs <-chan string, results chan<- *domain.Player, wg *sync.WaitGroup) {
-	defer wg.Done()
-	for name := range jobs {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			char, err := a.client.GetCharacter(name)
-			if err != nil {
-				slog.Warn("Failed to fetch character", "name", name, "error", err)
-				continue
-			}
-			result := a.mapCharacter(char)
-			if result != nil {
-				results <- result
-			}
-		}
+func (a *Adapter) fetchCharacterDetail(ctx context.Context, name string) (*domain.Player, error) {
+	player, err := a.getCharacter(ctx, name)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		slog.DebugContext(ctx, "Skipping character that no longer exists", "name", name)
+	case err != nil:
+		slog.WarnContext(ctx, "Failed to fetch character", "name", name, "error", err)
+	case player == nil:
+		err = fmt.Errorf("character %q has no details", name)
 	}
+	return player, err
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -52,6 +53,53 @@ func TestAdapter_FetchCharacter(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "Success - Renamed Character",
+			charName:   "Old Bubble",
+			mockStatus: http.StatusOK,
+			mockResponse: `{
+				"character": {
+					"character": {
+						"name": "Bubble",
+						"former_names": ["Old Bubble", "Older Bubble"],
+						"level": 100,
+						"world": "Antica"
+					},
+					"deaths": []
+				}
+			}`,
+			validate: func(t *testing.T, p *domain.Player) {
+				if p.Name != "Bubble" || len(p.FormerNames) != 2 || p.FormerNames[0] != "Old Bubble" {
+					t.Errorf("Expected Bubble with its former names, got %+v", p)
+				}
+			},
+		},
+		{
+			name:       "Success - Character with Guild",
+			charName:   "Leader",
+			mockStatus: http.StatusOK,
+			mockResponse: `{
+				"character": {
+					"character": {
+						"name": "Leader",
+						"level": 400,
+						"world": "Antica",
+						"vocation": "Elite Knight",
+						"guild": {"name": "Red Rose", "rank": "Leader"}
+					},
+					"deaths": []
+				}
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, p *domain.Player) {
+				if p.Vocation != "Elite Knight" {
+					t.Errorf("Expected Vocation Elite Knight, got %s", p.Vocation)
+				}
+				if p.GuildName != "Red Rose" || p.GuildRank != "Leader" {
+					t.Errorf("Expected Leader of Red Rose, got %s of %s", p.GuildRank, p.GuildName)
+				}
+			},
+		},
 		{
 			name:       "Success - Character with Deaths",
 			charName:   "Dead Player",
@@ -79,6 +127,46 @@ func TestAdapter_FetchCharacter(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "Success - Death with Killers and Assists",
+			charName:   "Pk Victim",
+			mockStatus: http.StatusOK,
+			mockResponse: `{
+				"character": {
+					"character": {"name": "Pk Victim", "level": 300, "world": "Antica"},
+					"deaths": [
+						{
+							"time": "2023-01-01T12:00:00Z", "level": 300,
+							"reason": "Killed at Level 300 by Enemy Knight and a fire elemental of Enemy Druid.",
+							"killers": [
+								{"name": "Enemy Knight", "player": true, "traded": false, "summon": ""},
+								{"name": "Enemy Druid", "player": true, "traded": false, "summon": "fire elemental"}
+							],
+							"assists": [
+								{"name": "Enemy Sorcerer", "player": true, "traded": false, "summon": ""}
+							]
+						}
+					]
+				}
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, p *domain.Player) {
+				if len(p.Deaths) != 1 {
+					t.Fatalf("Expected 1 death, got %d", len(p.Deaths))
+				}
+				kill := p.Deaths[0]
+				want := []domain.Killer{
+					{Name: "Enemy Knight", IsPlayer: true},
+					{Name: "Enemy Druid", IsPlayer: true, IsSummon: true},
+				}
+				if !reflect.DeepEqual(kill.Killers, want) {
+					t.Errorf("Expected killers %+v, got %+v", want, kill.Killers)
+				}
+				if len(kill.Assists) != 1 || kill.Assists[0].Name != "Enemy Sorcerer" {
+					t.Errorf("Unexpected assists: %+v", kill.Assists)
+				}
+			},
+		},
 		{
 			name:        "Error - 404 Not Found",
 			charName:    "Unknown",
@@ -97,7 +185,7 @@ func TestAdapter_FetchCharacter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.mockStatus)
 				if tt.mockResponse != "" {
 					w.Write([]byte(tt.mockResponse))
@@ -137,7 +225,7 @@ func TestAdapter_FetchCharacterDetails_Batch(t *testing.T) {
 		"Encoded Name": `{"character": {"character": {"name": "Encoded Name", "level": 40}, "deaths": []}}`,
 	}
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 		parts := strings.Split(r.URL.Path, "/")
 		if len(parts) < 3 {
 			w.WriteHeader(http.StatusBadRequest)
@@ -193,7 +281,7 @@ func TestAdapter_FetchCharacterDetails_Batch(t *testing.T) {
 }
 
 func TestAdapter_FetchCharacterDetails_PartialErrors(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(r.URL.Path, "Fail") {
 			w.WriteHeader(http.StatusNotFound)
 		} else {
@@ -221,7 +309,7 @@ func TestAdapter_FetchCharacterDetails_PartialErrors(t *testing.T) {
 }
 
 func TestAdapter_FetchCharacterDetails_ContextCancellation(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(50 * time.Millisecond)
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"character": {"character": {"name": "Player", "level": 10}, "deaths": []}}`))
@@ -251,3 +339,29 @@ func TestAdapter_FetchCharacterDetails_ContextCancellation(t *testing.T) {
 		t.Errorf("Expected cancellation to stop processing, but got %d results", count)
 	}
 }
+
+func TestAdapter_FetchCharacter_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{CharacterFetchTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	_, err := adapter.FetchCharacter(context.Background(), "Hung")
+	if err == nil {
+		t.Fatal("expected the hung lookup to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the lookup to give up after its timeout, took %v", elapsed)
+	}
+	if _, ok := adapter.characters.get("Hung"); ok {
+		t.Error("expected a timed out lookup not to be cached")
+	}
+}
@@ -2,18 +2,63 @@ package tibiadata
 
 import (
 	"context"
+
+	"death-level-tracker/internal/core/domain"
 )
 
 // FetchGuildMembers gets all members of a guild.
 func (a *Adapter) FetchGuildMembers(ctx context.Context, name string) ([]string, error) {
-	guild, err := a.client.GetGuild(name)
+	guild, err := a.client.GetGuild(ctx, domain.NormalizeName(name))
 	if err != nil {
-		return nil, err
+		return nil, classify(err)
 	}
 
 	members := make([]string, len(guild.Guild.Members))
 	for i, m := range guild.Guild.Members {
-		members[i] = m.Name
+		members[i] = domain.NormalizeName(m.Name)
 	}
 	return members, nil
 }
+
+// FetchWorldGuilds lists the active guilds of a world followed by those in
+// formation.
+func (a *Adapter) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	guilds, err := a.client.GetGuilds(ctx, world)
+	if err != nil {
+		return nil, classify(err)
+	}
+
+	names := make([]string, 0, len(guilds.Guilds.Active)+len(guilds.Guilds.Formation))
+	for _, g := range guilds.Guilds.Active {
+		names = append(names, domain.NormalizeName(g.Name))
+	}
+	for _, g := range guilds.Guilds.Formation {
+		names = append(names, domain.NormalizeName(g.Name))
+	}
+	return names, nil
+}
+
+// FetchGuild gets a guild with its world and the level of every member.
+func (a *Adapter) FetchGuild(ctx context.Context, name string) (*domain.Guild, error) {
+	guild, err := a.client.GetGuild(ctx, domain.NormalizeName(name))
+	if err != nil {
+		return nil, classify(err)
+	}
+
+	members := make([]domain.Player, len(guild.Guild.Members))
+	for i, m := range guild.Guild.Members {
+		members[i] = domain.Player{
+			Name:     domain.NormalizeName(m.Name),
+			Level:    m.Level,
+			Vocation: m.Vocation,
+			World:    guild.Guild.World,
+		}
+	}
+
+	return &domain.Guild{
+		Name:    domain.NormalizeName(guild.Guild.Name),
+		World:   guild.Guild.World,
+		Members: members,
+		InWar:   guild.Guild.InWar,
+	}, nil
+}
@@ -106,7 +106,7 @@ func TestAdapter_FetchGuildMembers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 				// Verify URL path contains the guild name (encoded or safe version)
 				// For Hell's Angels, client sends Hell's%20Angels (with raw ') or similar.
 				// We don't need strict URL verification here as that is covered in api/client_test.go
@@ -142,3 +142,75 @@ func TestAdapter_FetchGuildMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestAdapter_FetchGuild(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"guild": {
+				"name": "Red Rose",
+				"world": "Antica",
+				"in_war": true,
+				"members": [
+					{"name": "Player One", "level": 650, "vocation": "Elite Knight", "rank": "Leader"},
+					{"name": "Player Two", "level": 320, "vocation": "Druid", "rank": "Member"}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	guild, err := adapter.FetchGuild(context.Background(), "Red Rose")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if guild.Name != "Red Rose" || guild.World != "Antica" || !guild.InWar {
+		t.Errorf("Unexpected guild: %+v", guild)
+	}
+	if len(guild.Members) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(guild.Members))
+	}
+	if guild.Members[0].Level != 650 || guild.Members[0].World != "Antica" {
+		t.Errorf("Unexpected member: %+v", guild.Members[0])
+	}
+}
+
+func TestAdapter_FetchGuild_Error(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	if _, err := adapter.FetchGuild(context.Background(), "Unknown"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestAdapter_FetchWorldGuilds(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"guilds": {
+				"world": "Antica",
+				"active": [{"name": "Red Rose"}, {"name": "Hell's Angels"}],
+				"formation": [{"name": "Blue Army"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	names, err := adapter.FetchWorldGuilds(context.Background(), "Antica")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "Red Rose,Hell's Angels,Blue Army" {
+		t.Errorf("Unexpected guilds: %v", names)
+	}
+}
@@ -0,0 +1,47 @@
+package tibiadata
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// highscoreCategories maps each skill to its TibiaData highscore category.
+var highscoreCategories = map[domain.Skill]string{
+	domain.SkillMagic:     "magiclevel",
+	domain.SkillFist:      "fistfighting",
+	domain.SkillClub:      "clubfighting",
+	domain.SkillSword:     "swordfighting",
+	domain.SkillAxe:       "axefighting",
+	domain.SkillDistance:  "distancefighting",
+	domain.SkillShielding: "shielding",
+	domain.SkillFishing:   "fishing",
+}
+
+// FetchSkillHighscores gets one page of world's highscores for skill across
+// all vocations.
+func (a *Adapter) FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+	category, ok := highscoreCategories[skill]
+	if !ok {
+		return nil, fmt.Errorf("unknown skill: %s", skill)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := a.client.GetHighscores(ctx, world, category, page)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch highscores", "world", world, "skill", skill, "page", page, "error", err)
+		return nil, classify(err)
+	}
+
+	result := &domain.HighscorePage{
+		Entries:    make([]domain.HighscoreEntry, 0, len(resp.Highscores.HighscoreList)),
+		TotalPages: resp.Highscores.HighscorePage.TotalPages,
+	}
+	for _, entry := range resp.Highscores.HighscoreList {
+		result.Entries = append(result.Entries, domain.HighscoreEntry{Name: domain.NormalizeName(entry.Name), Value: entry.Value})
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package tibiadata
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/core/domain"
+)
+
+// houseTowns are the towns TibiaData lists houses for. The houses endpoint is
+// per town, so a world takes one request each.
+var houseTowns = []string{
+	"Ab'Dendriel", "Ankrahmun", "Carlin", "Darashia", "Edron", "Farmine",
+	"Gray Beach", "Issavi", "Kazordoon", "Liberty Bay", "Moonfall", "Port Hope",
+	"Rathleon", "Silvertides", "Svargrond", "Thais", "Venore", "Yalahar",
+}
+
+// FetchHouseAuctions gets the running house and guildhall auctions of every
+// town on world, WORKER_POOL_SIZE towns at a time, listed in houseTowns
+// order. It fails as a whole when any town cannot be fetched, so an auction
+// is never mistaken for ended.
+func (a *Adapter) FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+	opts := poolOptions{workers: a.config.WorkerPoolSize, failFast: true, ordered: true}
+	results, wait := runPool(ctx, houseTowns, opts, func(ctx context.Context, town string) ([]domain.HouseAuction, error) {
+		resp, err := a.client.GetHouses(ctx, world, town)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to fetch houses", "world", world, "town", town, "error", err)
+			return nil, fmt.Errorf("fetch houses of %s: %w", town, classify(err))
+		}
+		auctions := appendAuctions(nil, world, town, resp.Houses.HouseList)
+		return appendAuctions(auctions, world, town, resp.Houses.Guildhalls), nil
+	})
+
+	var auctions []domain.HouseAuction
+	for town := range results {
+		auctions = append(auctions, town...)
+	}
+	if err := wait(); err != nil {
+		return nil, err
+	}
+	return auctions, nil
+}
+
+func appendAuctions(auctions []domain.HouseAuction, world, town string, houses []api.House) []domain.HouseAuction {
+	for _, h := range houses {
+		if !h.Auctioned || h.Auction.Finished {
+			continue
+		}
+		auctions = append(auctions, domain.HouseAuction{
+			HouseID:    h.HouseID,
+			Name:       h.Name,
+			Town:       town,
+			World:      world,
+			Size:       h.Size,
+			Rent:       h.Rent,
+			CurrentBid: h.Auction.CurrentBid,
+			TimeLeft:   h.Auction.TimeLeft,
+		})
+	}
+	return auctions
+}
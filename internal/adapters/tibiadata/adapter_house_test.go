@@ -0,0 +1,69 @@
+package tibiadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/config"
+)
+
+func TestAdapter_FetchHouseAuctions(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if !strings.HasSuffix(r.URL.Path, "/Thais") {
+			w.Write([]byte(`{"houses": {}}`))
+			return
+		}
+		w.Write([]byte(`{
+			"houses": {
+				"house_list": [
+					{"name": "Market Street 1", "house_id": 10, "auctioned": true, "auction": {"current_bid": 50000, "time_left": "1 day"}},
+					{"name": "Market Street 2", "house_id": 11, "auctioned": true, "auction": {"current_bid": 90000, "finished": true}},
+					{"name": "Market Street 3", "house_id": 12, "rented": true}
+				],
+				"guildhall_list": [
+					{"name": "Castle of the Winds", "house_id": 20, "auctioned": true, "auction": {"current_bid": 0, "time_left": "6 days"}}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	auctions, err := adapter.FetchHouseAuctions(context.Background(), "Antica")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(auctions) != 2 {
+		t.Fatalf("Expected 2 running auctions, got %+v", auctions)
+	}
+	if a := auctions[0]; a.HouseID != 10 || a.Town != "Thais" || a.World != "Antica" || a.CurrentBid != 50000 || a.TimeLeft != "1 day" {
+		t.Errorf("Unexpected auction: %+v", a)
+	}
+	if auctions[1].HouseID != 20 {
+		t.Errorf("Expected guildhall auction, got %+v", auctions[1])
+	}
+}
+
+func TestAdapter_FetchHouseAuctions_Error(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Carlin") {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"houses": {}}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	if _, err := adapter.FetchHouseAuctions(context.Background(), "Antica"); err == nil {
+		t.Error("Expected error when a town fails, got nil")
+	}
+}
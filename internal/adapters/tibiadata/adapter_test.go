@@ -1,6 +1,7 @@
 package tibiadata
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func TestNewAdapter(t *testing.T) {
-	client := api.NewClient()
+	client := api.NewClient(&config.Config{})
 	cfg := &config.Config{
 		WorkerPoolSize: 10,
 	}
@@ -37,3 +38,11 @@ func TestNewAdapter(t *testing.T) {
 		}
 	}
 }
+
+// jsonHandler serves h's responses as JSON, like TibiaData does.
+func jsonHandler(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		h(w, r)
+	})
+}
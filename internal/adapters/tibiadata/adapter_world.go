@@ -2,9 +2,11 @@ package tibiadata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 
 	"death-level-tracker/internal/adapters/metrics"
@@ -12,32 +14,146 @@ import (
 	"death-level-tracker/internal/core/domain"
 )
 
-// FetchWorld gets online players from TibiaData API.
+// FetchWorld gets online players from TibiaData API. The world page also
+// names the PvP type, which is kept for FetchWorldPvPType.
 func (a *Adapter) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
-	onlinePlayers, err := a.client.GetWorld(world)
+	data, err := a.client.GetWorldInfo(ctx, world)
 	if err != nil {
-		slog.Error("Failed to fetch world players", "world", world, "error", err)
-		return nil, err
+		slog.ErrorContext(ctx, "Failed to fetch world players", "world", world, "error", err)
+		return nil, classify(err)
 	}
-	slog.Info("Fetched online players", "world", world, "count", len(onlinePlayers))
+	a.storePvPType(world, data.World.PvPType)
+	onlinePlayers := data.World.OnlinePlayers
+	slog.InfoContext(ctx, "Fetched online players", "world", world, "count", len(onlinePlayers))
 
 	players := make([]domain.Player, len(onlinePlayers))
+	levels := make(map[string]int, len(onlinePlayers))
 	for i, p := range onlinePlayers {
+		name := domain.NormalizeName(p.Name)
 		players[i] = domain.Player{
-			Name:     p.Name,
+			Name:     name,
 			Level:    p.Level,
 			Vocation: p.Vocation,
 			World:    world,
 		}
+		levels[name] = p.Level
 	}
+	a.characters.invalidateChanged(levels)
 
 	return players, nil
 }
 
+// FetchWorldPvPType gets the PvP type of world, such as "Open PvP", from
+// TibiaData. A world's PvP type never changes, so it is fetched only until
+// one lookup succeeds.
+func (a *Adapter) FetchWorldPvPType(ctx context.Context, world string) (string, error) {
+	a.pvpMu.Lock()
+	pvpType, ok := a.pvpTypes[world]
+	a.pvpMu.Unlock()
+	if ok {
+		return pvpType, nil
+	}
+
+	data, err := a.client.GetWorldInfo(ctx, world)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch world", "world", world, "error", err)
+		return "", classify(err)
+	}
+	a.storePvPType(world, data.World.PvPType)
+	return data.World.PvPType, nil
+}
+
+// storePvPType remembers world's PvP type. Pages without one are not kept,
+// so a later lookup asks again.
+func (a *Adapter) storePvPType(world, pvpType string) {
+	if pvpType == "" {
+		return
+	}
+	a.pvpMu.Lock()
+	a.pvpTypes[world] = pvpType
+	a.pvpMu.Unlock()
+}
+
+const (
+	// maxTibiaComPages caps the pages read for one world, so a page
+	// navigation that never ends cannot keep a cycle busy.
+	maxTibiaComPages = 20
+	// tibiaComPageConcurrency bounds the page requests in flight per world.
+	tibiaComPageConcurrency = 4
+)
+
 // FetchWorldFromTibiaCom scrapes Tibia.com as a fallback/alternative source.
+// Worlds listed over several pages have the remaining pages fetched
+// concurrently once the first one tells how many there are; the list fails
+// as a whole if any page does, since a partial list would mark players as
+// logged out.
 func (a *Adapter) FetchWorldFromTibiaCom(ctx context.Context, world string) (map[string]int, error) {
+	first, err := a.fetchTibiaComPage(ctx, world, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	players := first.Players
+	if first.Pages > 1 {
+		last := min(first.Pages, maxTibiaComPages)
+		if last < first.Pages {
+			slog.WarnContext(ctx, "Tibia.com world list has too many pages, reading the first ones only", "world", world, "pages", first.Pages, "max", maxTibiaComPages)
+		}
+		rest, err := a.fetchTibiaComPages(ctx, world, last)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range rest {
+			mergeTibiaComPlayers(players, page.Players)
+		}
+	}
+
+	a.characters.invalidateChanged(players)
+
+	slog.InfoContext(ctx, "Fetched online players from tibia.com", "world", world, "count", len(players), "pages", first.Pages)
+	return players, nil
+}
+
+// fetchTibiaComPages fetches pages 2 to last of world's online list. The
+// first failure cancels the pages still queued and is returned.
+func (a *Adapter) fetchTibiaComPages(ctx context.Context, world string, last int) ([]*scraper.WorldPage, error) {
+	numbers := make([]int, 0, last-1)
+	for n := 2; n <= last; n++ {
+		numbers = append(numbers, n)
+	}
+
+	results, wait := runPool(ctx, numbers, poolOptions{workers: tibiaComPageConcurrency, failFast: true}, func(ctx context.Context, n int) (*scraper.WorldPage, error) {
+		return a.fetchTibiaComPage(ctx, world, n)
+	})
+	pages := make([]*scraper.WorldPage, 0, len(numbers))
+	for page := range results {
+		pages = append(pages, page)
+	}
+	if err := wait(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// mergeTibiaComPlayers adds page to players. A character listed on two pages
+// because the list shifted while it was paged keeps the higher level, so the
+// result does not depend on the order pages arrive in.
+func mergeTibiaComPlayers(players, page map[string]int) {
+	for name, level := range page {
+		if level > players[name] {
+			players[name] = level
+		}
+	}
+}
+
+// fetchTibiaComPage fetches and parses one page, starting at 1, of world's
+// online list.
+func (a *Adapter) fetchTibiaComPage(ctx context.Context, world string, page int) (*scraper.WorldPage, error) {
 	start := time.Now()
-	targetURL := fmt.Sprintf("https://www.tibia.com/community/?subtopic=worlds&world=%s", world)
+	targetURL := fmt.Sprintf("%s/community/?subtopic=worlds&world=%s", a.tibiaComBaseURL, url.QueryEscape(world))
+	if page > 1 {
+		targetURL += fmt.Sprintf("&currentpage=%d", page)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
 	if err != nil {
@@ -58,24 +174,35 @@ func (a *Adapter) FetchWorldFromTibiaCom(ctx context.Context, world string) (map
 	metrics.TibiaComRequests.WithLabelValues(status).Inc()
 
 	if err != nil {
-		slog.Error("Failed to fetch tibia.com world page", "world", world, "error", err)
-		return nil, fmt.Errorf("do request: %w", err)
+		slog.ErrorContext(ctx, "Failed to fetch tibia.com world page", "world", world, "page", page, "error", err)
+		return nil, &fetchError{kind: domain.ErrUpstreamDown, err: fmt.Errorf("do request: %w", err)}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		slog.InfoContext(ctx, "Tibia.com is under maintenance", "world", world)
+		return nil, domain.ErrWorldMaintenance
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		slog.Error("Unexpected status from tibia.com", "world", world, "status", resp.StatusCode)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		slog.ErrorContext(ctx, "Unexpected status from tibia.com", "world", world, "page", page, "status", resp.StatusCode)
+		err := fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		if kind := statusKind(resp.StatusCode); kind != nil {
+			return nil, &fetchError{kind: kind, err: err}
+		}
+		return nil, err
 	}
 
-	players, err := scraper.ParseTibiaComWorld(resp.Body)
+	parsed, err := scraper.ParseTibiaComWorldPage(resp.Body)
+	if errors.Is(err, domain.ErrWorldMaintenance) {
+		slog.InfoContext(ctx, "World is under maintenance on tibia.com", "world", world)
+		return nil, err
+	}
 	if err != nil {
-		slog.Error("Failed to parse tibia.com HTML", "world", world, "error", err)
-		return nil, fmt.Errorf("parse HTML: %w", err)
+		slog.ErrorContext(ctx, "Failed to parse tibia.com HTML", "world", world, "page", page, "error", err)
+		return nil, &fetchError{kind: domain.ErrParse, err: fmt.Errorf("parse HTML: %w", err)}
 	}
-
-	slog.Info("Fetched online players from tibia.com", "world", world, "count", len(players))
-	return players, nil
+	return parsed, nil
 }
 
 func (a *Adapter) addBrowserHeaders(req *http.Request) {
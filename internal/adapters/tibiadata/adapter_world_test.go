@@ -2,10 +2,15 @@ package tibiadata
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -24,6 +29,24 @@ func TestAdapter_FetchWorld(t *testing.T) {
 		errContains  string
 		validate     func(t *testing.T, players []domain.Player)
 	}{
+		{
+			name:       "Success - Encoded Names",
+			worldName:  "Antica",
+			mockStatus: http.StatusOK,
+			mockResponse: `{
+				"world": {
+					"online_players": [
+						{"name": "Sir%27Lance", "level": 100, "vocation": "Knight"},
+						{"name": "Lady\u00a0Rose", "level": 200, "vocation": "Druid"}
+					]
+				}
+			}`,
+			validate: func(t *testing.T, players []domain.Player) {
+				if len(players) != 2 || players[0].Name != "Sir'Lance" || players[1].Name != "Lady Rose" {
+					t.Errorf("expected normalized names, got %v", players)
+				}
+			},
+		},
 		{
 			name:       "Success - Standard World",
 			worldName:  "Antica",
@@ -79,7 +102,7 @@ func TestAdapter_FetchWorld(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.mockStatus)
 				if tt.mockResponse != "" {
 					w.Write([]byte(tt.mockResponse))
@@ -111,6 +134,57 @@ func TestAdapter_FetchWorld(t *testing.T) {
 	}
 }
 
+func TestAdapter_FetchWorldPvPType(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/world/Antica" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"world": {"name": "Antica", "pvp_type": "Open PvP", "online_players": []}}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+	for range 2 {
+		pvpType, err := adapter.FetchWorldPvPType(context.Background(), "Antica")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if pvpType != "Open PvP" {
+			t.Errorf("Expected Open PvP, got %q", pvpType)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected the PvP type to be fetched once, got %d requests", got)
+	}
+}
+
+func TestAdapter_FetchWorldPvPType_FromWorld(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"world": {"name": "Antica", "pvp_type": "Optional PvP", "online_players": [{"name": "One", "level": 100}]}}`))
+	}))
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+	if _, err := adapter.FetchWorld(context.Background(), "Antica"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pvpType, err := adapter.FetchWorldPvPType(context.Background(), "Antica")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pvpType != "Optional PvP" {
+		t.Errorf("Expected Optional PvP, got %q", pvpType)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected the PvP type to come from the online list, got %d requests", got)
+	}
+}
+
 func TestAdapter_FetchWorldFromTibiaCom(t *testing.T) {
 	htmlWithPlayers := `
 		<html><body><table>
@@ -157,7 +231,15 @@ func TestAdapter_FetchWorldFromTibiaCom(t *testing.T) {
 			worldName:   "Maintenance",
 			mockStatus:  http.StatusServiceUnavailable,
 			wantErr:     true,
-			errContains: "unexpected status code: 503",
+			errContains: domain.ErrWorldMaintenance.Error(),
+		},
+		{
+			name:        "Error - Maintenance Page",
+			worldName:   "Antica",
+			mockStatus:  http.StatusOK,
+			mockHTML:    `<html><body>Tibia is currently undergoing maintenance.</body></html>`,
+			wantErr:     true,
+			errContains: domain.ErrWorldMaintenance.Error(),
 		},
 	}
 
@@ -172,7 +254,7 @@ func TestAdapter_FetchWorldFromTibiaCom(t *testing.T) {
 			}))
 			defer server.Close()
 
-			client := api.NewClient()
+			client := api.NewClient(&config.Config{})
 			adapter := NewAdapter(client, &config.Config{})
 
 			// Inject custom transport to hijack requests to tibia.com and redirect to mock server
@@ -202,6 +284,120 @@ func TestAdapter_FetchWorldFromTibiaCom(t *testing.T) {
 	}
 }
 
+// tibiaComPages builds a world list of players spread over pages of perPage
+// characters, each linking to every page like tibia.com's page navigation.
+func tibiaComPages(players, perPage int) map[int]string {
+	pageCount := (players + perPage - 1) / perPage
+	pages := make(map[int]string, pageCount)
+	for n := 1; n <= pageCount; n++ {
+		var b strings.Builder
+		b.WriteString("<html><body><table>")
+		for i := (n - 1) * perPage; i < min(n*perPage, players); i++ {
+			fmt.Fprintf(&b, `<tr class="Odd"><td><a href="?subtopic=characters&name=Player+%d">Player %d</a></td><td>%d</td></tr>`, i, i, 100+i)
+		}
+		b.WriteString(`</table><div class="PageNavigation">`)
+		for link := 1; link <= pageCount; link++ {
+			fmt.Fprintf(&b, `<a href="?subtopic=worlds&world=Antica&currentpage=%d">%d</a>`, link, link)
+		}
+		b.WriteString("</div></body></html>")
+		pages[n] = b.String()
+	}
+	return pages
+}
+
+func TestAdapter_FetchWorldFromTibiaCom_Pages(t *testing.T) {
+	newAdapter := func(t *testing.T, handler http.HandlerFunc) *Adapter {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		adapter := NewAdapter(api.NewClient(&config.Config{}), &config.Config{})
+		adapter.tibiaComClient = &http.Client{Timeout: time.Second, Transport: &hijackTransport{target: server.URL}}
+		return adapter
+	}
+	pageOf := func(r *http.Request) int {
+		n, err := strconv.Atoi(r.URL.Query().Get("currentpage"))
+		if err != nil {
+			return 1
+		}
+		return n
+	}
+
+	t.Run("merges every page", func(t *testing.T) {
+		pages := tibiaComPages(1600, 300)
+		var (
+			mu        sync.Mutex
+			requested []int
+			inFlight  atomic.Int32
+			peak      atomic.Int32
+		)
+		adapter := newAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			page := pageOf(r)
+			mu.Lock()
+			requested = append(requested, page)
+			mu.Unlock()
+			w.Write([]byte(pages[page]))
+		})
+
+		players, err := adapter.FetchWorldFromTibiaCom(context.Background(), "Antica")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(players) != 1600 {
+			t.Errorf("expected 1600 players, got %d", len(players))
+		}
+		if players["Player 0"] != 100 || players["Player 1599"] != 1699 {
+			t.Errorf("expected players from the first and last page, got %d and %d", players["Player 0"], players["Player 1599"])
+		}
+		if len(requested) != len(pages) || requested[0] != 1 {
+			t.Errorf("expected each of %d pages once starting with the first, got %v", len(pages), requested)
+		}
+		if got := peak.Load(); got > tibiaComPageConcurrency {
+			t.Errorf("expected at most %d pages in flight, got %d", tibiaComPageConcurrency, got)
+		}
+	})
+
+	t.Run("character on two pages keeps the higher level", func(t *testing.T) {
+		pages := tibiaComPages(4, 2)
+		pages[2] = strings.Replace(pages[2], "name=Player+2", "name=Player+1", 1)
+		pages[2] = strings.Replace(pages[2], "<td>102</td>", "<td>150</td>", 1)
+		adapter := newAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(pages[pageOf(r)]))
+		})
+
+		players, err := adapter.FetchWorldFromTibiaCom(context.Background(), "Antica")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if players["Player 1"] != 150 || len(players) != 3 {
+			t.Errorf("expected Player 1 at 150 among 3 players, got %v", players)
+		}
+	})
+
+	t.Run("failed page fails the list", func(t *testing.T) {
+		pages := tibiaComPages(900, 300)
+		adapter := newAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+			if pageOf(r) == 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(pages[pageOf(r)]))
+		})
+
+		players, err := adapter.FetchWorldFromTibiaCom(context.Background(), "Antica")
+		if !errors.Is(err, domain.ErrUpstreamDown) {
+			t.Errorf("expected ErrUpstreamDown, got %v", err)
+		}
+		if players != nil {
+			t.Errorf("expected no partial list, got %d players", len(players))
+		}
+	})
+}
+
 type hijackTransport struct {
 	target string
 }
@@ -1,30 +1,97 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/config"
 )
 
 const DefaultBaseURL = "https://api.tibiadata.com/v4"
 
+// DefaultTimeout applies when TIBIADATA_TIMEOUT is not set.
+const DefaultTimeout = 10 * time.Second
+
+// maxResponseSize caps how much of a response body is read. The largest
+// TibiaData responses, big guilds and busy worlds, stay well below it.
+const maxResponseSize = 5 << 20
+
+// ErrNotFound is wrapped into errors for 404 responses, e.g. deleted or
+// renamed characters.
+var ErrNotFound = errors.New("not found")
+
+// ErrResponseTooLarge is wrapped into a DecodeError for bodies over
+// maxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// NetworkError reports a request that got no complete response.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string { return "request failed: " + e.Err.Error() }
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// StatusError reports a response with a status other than 200. It matches
+// ErrNotFound for 404 responses.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusNotFound {
+		return fmt.Sprintf("%v: unexpected status code: %d", ErrNotFound, e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DecodeError reports a successful response whose body is not the expected
+// JSON: the wrong content type, too large, or malformed.
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string { return "decode response: " + e.Err.Error() }
+func (e *DecodeError) Unwrap() error { return e.Err }
+
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
 }
 
-func NewClient() *Client {
+// NewClient creates a client for the configured TibiaData instance, falling
+// back to the public API and DefaultTimeout when they are not set.
+func NewClient(cfg *config.Config) *Client {
+	baseURL := strings.TrimSuffix(cfg.TibiaDataBaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	timeout := cfg.TibiaDataTimeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
 	return &Client{
 		httpClient: &http.Client{
-			Timeout:   10 * time.Second,
-			Transport: NewMetricsRoundTripper(http.DefaultTransport),
+			Timeout:   timeout,
+			Transport: NewAuthRoundTripper(cfg.TibiaDataAuthHeader, cfg.TibiaDataAuthToken, NewMetricsRoundTripper(NewTransport(cfg, cfg.TibiaDataProxyURL))),
 		},
-		baseURL: DefaultBaseURL,
+		baseURL: baseURL,
 	}
 }
 
@@ -32,17 +99,27 @@ func NewClient() *Client {
 func NewTestClient(baseURL string) *Client {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: DefaultTimeout,
 		},
 		baseURL: baseURL,
 	}
 }
 
-func (c *Client) GetWorld(worldName string) ([]OnlinePlayer, error) {
+func (c *Client) GetWorld(ctx context.Context, worldName string) ([]OnlinePlayer, error) {
+	data, err := c.GetWorldInfo(ctx, worldName)
+	if err != nil {
+		return nil, err
+	}
+	return data.World.OnlinePlayers, nil
+}
+
+// GetWorldInfo returns the whole world page, including its PvP type and
+// online players.
+func (c *Client) GetWorldInfo(ctx context.Context, worldName string) (*WorldResponse, error) {
 	u := fmt.Sprintf("%s/world/%s", c.baseURL, url.PathEscape(worldName))
 
 	var data WorldResponse
-	if err := c.getAndDecode(u, &data); err != nil {
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
 		return nil, fmt.Errorf("fetch world: %w", err)
 	}
 
@@ -52,10 +129,10 @@ func (c *Client) GetWorld(worldName string) ([]OnlinePlayer, error) {
 		}
 	}
 
-	return data.World.OnlinePlayers, nil
+	return &data, nil
 }
 
-func (c *Client) GetCharacter(name string) (*CharacterResponse, error) {
+func (c *Client) GetCharacter(ctx context.Context, name string) (*CharacterResponse, error) {
 	// TibiaData requires single quotes to be encoded or handled specific way?
 	// The original code replaced encoded single quote with literal single quote.
 	// keeping it as is to avoid regression.
@@ -63,45 +140,158 @@ func (c *Client) GetCharacter(name string) (*CharacterResponse, error) {
 	u := fmt.Sprintf("%s/character/%s", c.baseURL, safeName)
 
 	var data CharacterResponse
-	if err := c.getAndDecode(u, &data); err != nil {
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
 		return nil, fmt.Errorf("fetch character: %w", err)
 	}
 
 	return &data, nil
 }
 
-func (c *Client) GetGuild(name string) (*GuildResponse, error) {
+func (c *Client) GetGuild(ctx context.Context, name string) (*GuildResponse, error) {
 	safeName := strings.ReplaceAll(url.PathEscape(name), "%27", "'")
 	u := fmt.Sprintf("%s/guild/%s", c.baseURL, safeName)
 
 	var data GuildResponse
-	if err := c.getAndDecode(u, &data); err != nil {
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
 		return nil, fmt.Errorf("fetch guild: %w", err)
 	}
 
 	return &data, nil
 }
 
-func (c *Client) getAndDecode(url string, dest interface{}) error {
-	resp, err := c.httpClient.Get(url)
+// GetGuilds lists the active guilds and those in formation on a world.
+func (c *Client) GetGuilds(ctx context.Context, worldName string) (*GuildsResponse, error) {
+	u := fmt.Sprintf("%s/guilds/%s", c.baseURL, url.PathEscape(worldName))
+
+	var data GuildsResponse
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
+		return nil, fmt.Errorf("fetch guilds: %w", err)
+	}
+
+	return &data, nil
+}
+
+// GetHouses lists the houses and guildhalls of a town on a world.
+func (c *Client) GetHouses(ctx context.Context, worldName, town string) (*HousesResponse, error) {
+	u := fmt.Sprintf("%s/houses/%s/%s", c.baseURL, url.PathEscape(worldName), strings.ReplaceAll(url.PathEscape(town), "%27", "'"))
+
+	var data HousesResponse
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
+		return nil, fmt.Errorf("fetch houses: %w", err)
+	}
+
+	return &data, nil
+}
+
+// GetHighscores gets one page, starting at 1, of a world's highscores for a
+// category such as "magiclevel", across all vocations.
+func (c *Client) GetHighscores(ctx context.Context, worldName, category string, page int) (*HighscoresResponse, error) {
+	u := fmt.Sprintf("%s/highscores/%s/%s/all/%d", c.baseURL, url.PathEscape(worldName), url.PathEscape(category), page)
+
+	var data HighscoresResponse
+	if err := c.getAndDecode(ctx, u, &data); err != nil {
+		return nil, fmt.Errorf("fetch highscores: %w", err)
+	}
+
+	return &data, nil
+}
+
+// GetCreatures lists the creatures of the library, along with today's
+// boosted one.
+func (c *Client) GetCreatures(ctx context.Context) (*CreaturesResponse, error) {
+	var data CreaturesResponse
+	if err := c.getAndDecode(ctx, c.baseURL+"/creatures", &data); err != nil {
+		return nil, fmt.Errorf("fetch creatures: %w", err)
+	}
+
+	return &data, nil
+}
+
+// GetBoostableBosses lists the bosses that can be boosted, along with
+// today's boosted one.
+func (c *Client) GetBoostableBosses(ctx context.Context) (*BoostableBossesResponse, error) {
+	var data BoostableBossesResponse
+	if err := c.getAndDecode(ctx, c.baseURL+"/boostablebosses", &data); err != nil {
+		return nil, fmt.Errorf("fetch boostable bosses: %w", err)
+	}
+
+	return &data, nil
+}
+
+// getAndDecode fetches url within ctx and decodes its JSON body into dest.
+// Failures, including an expired ctx, are a *NetworkError, *StatusError or
+// *DecodeError.
+func (c *Client) getAndDecode(ctx context.Context, url string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return err
+		return &NetworkError{Err: err}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &NetworkError{Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	if contentType := resp.Header.Get("Content-Type"); !isJSON(contentType) {
+		return &DecodeError{Err: fmt.Errorf("unexpected content type %q", contentType)}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	if len(body) > maxResponseSize {
+		return &DecodeError{Err: fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, maxResponseSize)}
+	}
+	if err := json.Unmarshal(body, dest); err != nil {
+		return &DecodeError{Err: err}
 	}
 
 	return nil
 }
 
+// isJSON accepts application/json and structured +json media types.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 // -- Middleware --
 
+// AuthRoundTripper sets an authentication header on every request, for
+// self-hosted TibiaData instances behind a gateway.
+type AuthRoundTripper struct {
+	Header  string
+	Token   string
+	Proxied http.RoundTripper
+}
+
+// NewAuthRoundTripper returns proxied unchanged when no token is configured.
+func NewAuthRoundTripper(header, token string, proxied http.RoundTripper) http.RoundTripper {
+	if proxied == nil {
+		proxied = http.DefaultTransport
+	}
+	if token == "" {
+		return proxied
+	}
+	if header == "" {
+		header = "Authorization"
+	}
+	return &AuthRoundTripper{Header: header, Token: token, Proxied: proxied}
+}
+
+func (art *AuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(art.Header, art.Token)
+	return art.Proxied.RoundTrip(req)
+}
+
 type MetricsRoundTripper struct {
 	Proxied http.RoundTripper
 }
@@ -132,6 +322,8 @@ func (mrt *MetricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 			endpoint = "character"
 		} else if strings.Contains(path, "/guild/") {
 			endpoint = "guild"
+		} else if strings.Contains(path, "/houses/") {
+			endpoint = "houses"
 		}
 	}
 
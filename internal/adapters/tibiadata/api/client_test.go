@@ -1,16 +1,20 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"death-level-tracker/internal/config"
 )
 
 func TestNewClient(t *testing.T) {
-	client := NewClient()
+	client := NewClient(&config.Config{})
 
 	if client == nil {
 		t.Fatal("Expected NewClient to return non-nil client")
@@ -26,6 +30,46 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_Timeout(t *testing.T) {
+	client := NewClient(&config.Config{TibiaDataTimeout: 25 * time.Second})
+
+	if client.httpClient.Timeout != 25*time.Second {
+		t.Errorf("Expected timeout 25s, got %v", client.httpClient.Timeout)
+	}
+}
+
+func TestNewClient_SelfHosted(t *testing.T) {
+	var gotHeader, gotPath string
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Api-Key")
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"world": {"online_players": []}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{
+		TibiaDataBaseURL:    server.URL + "/v4/",
+		TibiaDataAuthHeader: "X-Api-Key",
+		TibiaDataAuthToken:  "secret",
+	})
+	if _, err := client.GetWorld(context.Background(), "Antica"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/v4/world/Antica" {
+		t.Errorf("expected request to self-hosted base URL, got path %q", gotPath)
+	}
+	if gotHeader != "secret" {
+		t.Errorf("expected auth header to be sent, got %q", gotHeader)
+	}
+}
+
+func TestNewAuthRoundTripper_NoToken(t *testing.T) {
+	if rt := NewAuthRoundTripper("Authorization", "", http.DefaultTransport); rt != http.DefaultTransport {
+		t.Errorf("expected transport to be returned unchanged without a token, got %T", rt)
+	}
+}
+
 func TestClient_GetWorld(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -154,11 +198,11 @@ func TestClient_GetWorld(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(tt.mockHandler))
+			server := httptest.NewServer(jsonHandler(tt.mockHandler))
 			defer server.Close()
 
 			client := NewTestClient(server.URL)
-			players, err := client.GetWorld(tt.worldName)
+			players, err := client.GetWorld(context.Background(), tt.worldName)
 
 			if tt.expectError {
 				if err == nil {
@@ -280,11 +324,11 @@ func TestClient_GetCharacter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(tt.mockHandler))
+			server := httptest.NewServer(jsonHandler(tt.mockHandler))
 			defer server.Close()
 
 			client := NewTestClient(server.URL)
-			char, err := client.GetCharacter(tt.charName)
+			char, err := client.GetCharacter(context.Background(), tt.charName)
 
 			if tt.expectError {
 				if err == nil {
@@ -359,11 +403,11 @@ func TestClient_GetGuild(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(tt.mockHandler))
+			server := httptest.NewServer(jsonHandler(tt.mockHandler))
 			defer server.Close()
 
 			client := NewTestClient(server.URL)
-			guild, err := client.GetGuild(tt.guildName)
+			guild, err := client.GetGuild(context.Background(), tt.guildName)
 
 			if tt.expectError {
 				if err == nil {
@@ -382,3 +426,193 @@ func TestClient_GetGuild(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetHouses(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.RequestURI, "/houses/Antica/Ab'Dendriel") {
+			t.Errorf("Expected world and town in path, got %s", r.RequestURI)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"houses": {
+				"world": "Antica",
+				"town": "Ab'Dendriel",
+				"house_list": [
+					{"name": "Elm Lane 1", "house_id": 1, "size": 20, "rent": 5000, "rented": false, "auctioned": true,
+					 "auction": {"current_bid": 120000, "time_left": "2 days", "finished": false}}
+				],
+				"guildhall_list": [
+					{"name": "Elvenbane", "house_id": 2, "size": 300, "rent": 100000, "rented": true, "auctioned": false}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	houses, err := NewTestClient(server.URL).GetHouses(context.Background(), "Antica", "Ab'Dendriel")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(houses.Houses.HouseList) != 1 || len(houses.Houses.Guildhalls) != 1 {
+		t.Fatalf("Unexpected houses: %+v", houses.Houses)
+	}
+	house := houses.Houses.HouseList[0]
+	if !house.Auctioned || house.Auction.CurrentBid != 120000 || house.Auction.TimeLeft != "2 days" {
+		t.Errorf("Unexpected auction: %+v", house)
+	}
+}
+
+func TestClient_GetGuilds(t *testing.T) {
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.RequestURI, "/guilds/Antica") {
+			t.Errorf("Expected world in path, got %s", r.RequestURI)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"guilds": {
+				"world": "Antica",
+				"active": [{"name": "Red Rose", "logo_url": "", "description": ""}],
+				"formation": [{"name": "Blue Army"}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	guilds, err := NewTestClient(server.URL).GetGuilds(context.Background(), "Antica")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(guilds.Guilds.Active) != 1 || guilds.Guilds.Active[0].Name != "Red Rose" {
+		t.Errorf("Unexpected active guilds: %+v", guilds.Guilds.Active)
+	}
+	if len(guilds.Guilds.Formation) != 1 || guilds.Guilds.Formation[0].Name != "Blue Army" {
+		t.Errorf("Unexpected guilds in formation: %+v", guilds.Guilds.Formation)
+	}
+}
+
+func TestClient_ErrorTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			check: func(t *testing.T, err error) {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+					t.Errorf("expected a 404 StatusError, got %v", err)
+				}
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("expected ErrNotFound, got %v", err)
+				}
+			},
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			check: func(t *testing.T, err error) {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+					t.Errorf("expected a 502 StatusError, got %v", err)
+				}
+				if errors.Is(err, ErrNotFound) {
+					t.Errorf("expected no ErrNotFound for a 502, got %v", err)
+				}
+			},
+		},
+		{
+			name: "html body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte("<html>maintenance</html>"))
+			},
+			check: func(t *testing.T, err error) {
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), "text/html") {
+					t.Errorf("expected a content type DecodeError, got %v", err)
+				}
+			},
+		},
+		{
+			name: "oversized body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"padding": "`))
+				w.Write([]byte(strings.Repeat("x", maxResponseSize)))
+				w.Write([]byte(`"}`))
+			},
+			check: func(t *testing.T, err error) {
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) || !errors.Is(err, ErrResponseTooLarge) {
+					t.Errorf("expected ErrResponseTooLarge, got %v", err)
+				}
+			},
+		},
+		{
+			name: "malformed json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.Write([]byte(`{"world":`))
+			},
+			check: func(t *testing.T, err error) {
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) {
+					t.Errorf("expected a DecodeError, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := NewTestClient(server.URL).GetWorld(context.Background(), "Antica")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestClient_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	_, err := NewTestClient(url).GetWorld(context.Background(), "Antica")
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("expected a NetworkError, got %v", err)
+	}
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewTestClient(server.URL).GetCharacter(ctx, "Bubble")
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a NetworkError wrapping context.Canceled, got %v", err)
+	}
+}
+
+// jsonHandler serves h's responses as JSON, like TibiaData does.
+func jsonHandler(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		h(w, r)
+	})
+}
@@ -4,6 +4,7 @@ import "time"
 
 type WorldResponse struct {
 	World struct {
+		PvPType       string         `json:"pvp_type"`
 		OnlinePlayers []OnlinePlayer `json:"online_players"`
 	} `json:"world"`
 }
@@ -22,16 +23,34 @@ type CharacterResponse struct {
 }
 
 type CharacterInfo struct {
-	Name     string `json:"name"`
-	Level    int    `json:"level"`
-	Vocation string `json:"vocation"`
-	World    string `json:"world"`
+	Name        string         `json:"name"`
+	FormerNames []string       `json:"former_names"`
+	Level       int            `json:"level"`
+	Vocation    string         `json:"vocation"`
+	World       string         `json:"world"`
+	Guild       CharacterGuild `json:"guild"`
+}
+
+type CharacterGuild struct {
+	Name string `json:"name"`
+	Rank string `json:"rank"`
 }
 
 type Death struct {
-	Time   time.Time `json:"time"`
-	Level  int       `json:"level"`
-	Reason string    `json:"reason"`
+	Time    time.Time `json:"time"`
+	Level   int       `json:"level"`
+	Reason  string    `json:"reason"`
+	Killers []Killer  `json:"killers"`
+	Assists []Killer  `json:"assists"`
+}
+
+// Killer is a creature or character involved in a death. Summon names the
+// creature a player summoned, when the kill came from it.
+type Killer struct {
+	Name   string `json:"name"`
+	Player bool   `json:"player"`
+	Traded bool   `json:"traded"`
+	Summon string `json:"summon"`
 }
 
 type GuildResponse struct {
@@ -40,7 +59,9 @@ type GuildResponse struct {
 
 type GuildInfo struct {
 	Name    string        `json:"name"`
+	World   string        `json:"world"`
 	Members []GuildMember `json:"members"`
+	InWar   bool          `json:"in_war"`
 }
 
 type GuildMember struct {
@@ -50,3 +71,83 @@ type GuildMember struct {
 	Rank     string `json:"rank"`
 	Status   string `json:"status"`
 }
+
+type GuildsResponse struct {
+	Guilds struct {
+		World     string           `json:"world"`
+		Active    []GuildListEntry `json:"active"`
+		Formation []GuildListEntry `json:"formation"`
+	} `json:"guilds"`
+}
+
+type GuildListEntry struct {
+	Name string `json:"name"`
+}
+
+type HousesResponse struct {
+	Houses struct {
+		World      string  `json:"world"`
+		Town       string  `json:"town"`
+		HouseList  []House `json:"house_list"`
+		Guildhalls []House `json:"guildhall_list"`
+	} `json:"houses"`
+}
+
+type House struct {
+	Name      string       `json:"name"`
+	HouseID   int          `json:"house_id"`
+	Size      int          `json:"size"`
+	Rent      int          `json:"rent"`
+	Rented    bool         `json:"rented"`
+	Auctioned bool         `json:"auctioned"`
+	Auction   HouseAuction `json:"auction"`
+}
+
+type HouseAuction struct {
+	CurrentBid int    `json:"current_bid"`
+	TimeLeft   string `json:"time_left"`
+	Finished   bool   `json:"finished"`
+}
+
+type HighscoresResponse struct {
+	Highscores struct {
+		World         string           `json:"world"`
+		Category      string           `json:"category"`
+		HighscoreList []HighscoreEntry `json:"highscore_list"`
+		HighscorePage struct {
+			CurrentPage int `json:"current_page"`
+			TotalPages  int `json:"total_pages"`
+		} `json:"highscore_page"`
+	} `json:"highscores"`
+}
+
+type HighscoreEntry struct {
+	Rank  int    `json:"rank"`
+	Name  string `json:"name"`
+	World string `json:"world"`
+	Level int    `json:"level"`
+	Value int    `json:"value"`
+}
+
+type CreaturesResponse struct {
+	Creatures struct {
+		Boosted      BoostedEntry   `json:"boosted"`
+		CreatureList []BoostedEntry `json:"creature_list"`
+	} `json:"creatures"`
+}
+
+type BoostableBossesResponse struct {
+	BoostableBosses struct {
+		Boosted           BoostedEntry   `json:"boosted"`
+		BoostableBossList []BoostedEntry `json:"boostable_boss_list"`
+	} `json:"boostable_bosses"`
+}
+
+// BoostedEntry is a creature or boss as listed by the creatures and
+// boostablebosses endpoints.
+type BoostedEntry struct {
+	Name     string `json:"name"`
+	Race     string `json:"race"`
+	ImageURL string `json:"image_url"`
+	Featured bool   `json:"featured"`
+}
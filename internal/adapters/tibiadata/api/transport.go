@@ -0,0 +1,62 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+
+	"death-level-tracker/internal/config"
+)
+
+// NewTransport builds the transport for TibiaData or tibia.com requests. An
+// empty proxyURL honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY. Each client
+// talks to a single host, so HTTP_MAX_IDLE_CONNS also bounds the idle
+// connections per host, letting every worker keep its connection alive.
+func NewTransport(cfg *config.Config, proxyURL string) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err == nil {
+			t.Proxy = http.ProxyURL(u)
+		} else {
+			slog.Error("Ignoring invalid proxy URL", "error", err)
+		}
+	}
+
+	if cfg.HTTPMaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.HTTPMaxIdleConns
+		t.MaxIdleConnsPerHost = cfg.HTTPMaxIdleConns
+	}
+
+	if cfg.HTTPCAFile != "" {
+		pool, err := loadCertPool(cfg.HTTPCAFile)
+		if err != nil {
+			slog.Error("Failed to load CA file, using system roots only", "path", cfg.HTTPCAFile, "error", err)
+		} else {
+			t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	return t
+}
+
+// loadCertPool adds the PEM certificates in path to the system roots.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates in %s", path)
+	}
+	return pool, nil
+}
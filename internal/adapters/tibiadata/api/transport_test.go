@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"death-level-tracker/internal/config"
+)
+
+func TestNewTransport_Proxy(t *testing.T) {
+	transport := NewTransport(&config.Config{}, "http://proxy.internal:3128")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://www.tibia.com/community/", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("expected requests to go through the proxy, got %v", proxy)
+	}
+}
+
+func TestNewTransport_IdleConns(t *testing.T) {
+	transport := NewTransport(&config.Config{HTTPMaxIdleConns: 20}, "")
+
+	if transport.MaxIdleConns != 20 || transport.MaxIdleConnsPerHost != 20 {
+		t.Errorf("expected 20 idle connections in total and per host, got %d and %d", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.Proxy == nil {
+		t.Error("expected the environment proxy settings to be honored")
+	}
+}
+
+func TestNewTransport_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	untrusted := &http.Client{Transport: NewTransport(&config.Config{}, "")}
+	if _, err := untrusted.Get(server.URL); err == nil {
+		t.Error("expected the test certificate to be rejected without HTTP_CA_FILE")
+	}
+
+	trusted := &http.Client{Transport: NewTransport(&config.Config{HTTPCAFile: caFile}, "")}
+	resp, err := trusted.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the CA file to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+}
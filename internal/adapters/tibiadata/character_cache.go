@@ -0,0 +1,123 @@
+package tibiadata
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/core/domain"
+)
+
+type cacheEntry struct {
+	key       string         // domain.NameKey of the character name
+	player    *domain.Player // nil caches a 404
+	expiresAt time.Time
+}
+
+// characterCache is a size-bounded LRU of character lookups. Entries expire
+// after ttl; a nil cache is valid and never hits.
+type characterCache struct {
+	ttl      time.Duration
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newCharacterCache(capacity int, ttl time.Duration) *characterCache {
+	if capacity <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &characterCache{
+		ttl:      ttl,
+		capacity: capacity,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached player and whether the name was cached at all. A hit
+// with a nil player means the character was not found last time.
+func (c *characterCache) get(name string) (*domain.Player, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[domain.NameKey(name)]
+	if !ok {
+		metrics.CharacterCacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.remove(el)
+		metrics.CharacterCacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	if entry.player == nil {
+		metrics.CharacterCacheLookups.WithLabelValues("not_found").Inc()
+		return nil, true
+	}
+	metrics.CharacterCacheLookups.WithLabelValues("hit").Inc()
+	player := *entry.player
+	return &player, true
+}
+
+// put caches a copy of player, so the caller may go on changing its own.
+func (c *characterCache) put(name string, player *domain.Player) {
+	if c == nil {
+		return
+	}
+	if player != nil {
+		stored := *player
+		player = &stored
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := domain.NameKey(name)
+	entry := &cacheEntry{key: key, player: player, expiresAt: c.now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidateChanged drops entries whose cached level no longer matches the
+// level seen in an online list, so level changes are fetched immediately.
+func (c *characterCache) invalidateChanged(levels map[string]int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, level := range levels {
+		el, ok := c.entries[domain.NameKey(name)]
+		if !ok {
+			continue
+		}
+		if p := el.Value.(*cacheEntry).player; p == nil || p.Level != level {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *characterCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,134 @@
+package tibiadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestCharacterCache_Disabled(t *testing.T) {
+	if c := newCharacterCache(100, 0); c != nil {
+		t.Error("expected nil cache when TTL is zero")
+	}
+	if c := newCharacterCache(0, time.Minute); c != nil {
+		t.Error("expected nil cache when capacity is zero")
+	}
+
+	var c *characterCache
+	c.put("Bubble", &domain.Player{Name: "Bubble"})
+	if _, ok := c.get("Bubble"); ok {
+		t.Error("nil cache should never hit")
+	}
+}
+
+func TestCharacterCache_Expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newCharacterCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.put("Bubble", &domain.Player{Name: "Bubble", Level: 100})
+	if p, ok := c.get("Bubble"); !ok || p.Level != 100 {
+		t.Fatalf("expected hit, got %v %v", p, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.get("Bubble"); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestCharacterCache_SpellingsShareEntry(t *testing.T) {
+	c := newCharacterCache(10, time.Minute)
+	c.put("Sir'Lance", &domain.Player{Name: "Sir'Lance", Level: 100})
+
+	for _, spelling := range []string{"Sir%27Lance", "sir’lance", " SIR'LANCE "} {
+		if p, ok := c.get(spelling); !ok || p.Level != 100 {
+			t.Errorf("expected %q to hit, got %v %v", spelling, p, ok)
+		}
+	}
+
+	c.invalidateChanged(map[string]int{"sir'lance": 101})
+	if _, ok := c.get("Sir'Lance"); ok {
+		t.Error("expected a level change under another spelling to invalidate the entry")
+	}
+}
+
+func TestCharacterCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newCharacterCache(2, time.Minute)
+	c.put("A", &domain.Player{Name: "A"})
+	c.put("B", &domain.Player{Name: "B"})
+	c.get("A")
+	c.put("C", &domain.Player{Name: "C"})
+
+	if _, ok := c.get("B"); ok {
+		t.Error("expected B to be evicted")
+	}
+	if _, ok := c.get("A"); !ok {
+		t.Error("expected A to survive as recently used")
+	}
+	if _, ok := c.get("C"); !ok {
+		t.Error("expected C to be cached")
+	}
+}
+
+func TestCharacterCache_InvalidateChanged(t *testing.T) {
+	c := newCharacterCache(10, time.Minute)
+	c.put("Same", &domain.Player{Name: "Same", Level: 100})
+	c.put("Changed", &domain.Player{Name: "Changed", Level: 100})
+	c.put("Missing", nil)
+
+	c.invalidateChanged(map[string]int{"Same": 100, "Changed": 101, "Missing": 50})
+
+	if _, ok := c.get("Same"); !ok {
+		t.Error("expected unchanged level to stay cached")
+	}
+	if _, ok := c.get("Changed"); ok {
+		t.Error("expected changed level to be invalidated")
+	}
+	if _, ok := c.get("Missing"); ok {
+		t.Error("expected online character to clear a cached 404")
+	}
+}
+
+func TestAdapter_FetchCharacterDetails_Cached(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(jsonHandler(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/Ghost") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"character": {"character": {"name": "Bubble", "level": 100}, "deaths": []}}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{WorkerPoolSize: 1, CharacterCacheTTL: time.Minute, CharacterCacheSize: 10}
+	adapter := NewAdapter(api.NewTestClient(server.URL), cfg)
+
+	for range 2 {
+		results, err := adapter.FetchCharacterDetails(context.Background(), []string{"Bubble", "Ghost"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []*domain.Player
+		for p := range results {
+			got = append(got, p)
+		}
+		if len(got) != 1 || got[0].Level != 100 {
+			t.Fatalf("expected Bubble only, got %v", got)
+		}
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected 2 API calls with second pass cached, got %d", n)
+	}
+}
@@ -0,0 +1,56 @@
+package tibiadata
+
+import (
+	"errors"
+	"net/http"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/core/domain"
+)
+
+// fetchError tags an upstream error with the domain error it amounts to,
+// keeping the original message and chain.
+type fetchError struct {
+	kind error
+	err  error
+}
+
+func (e *fetchError) Error() string   { return e.err.Error() }
+func (e *fetchError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// classify maps a TibiaData client error onto the domain fetch errors.
+// Errors it cannot place, e.g. a cancelled context, are returned unchanged.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *api.StatusError
+	var networkErr *api.NetworkError
+	var decodeErr *api.DecodeError
+	switch {
+	case errors.As(err, &statusErr):
+		if kind := statusKind(statusErr.StatusCode); kind != nil {
+			return &fetchError{kind: kind, err: err}
+		}
+	case errors.As(err, &networkErr):
+		return &fetchError{kind: domain.ErrUpstreamDown, err: err}
+	case errors.As(err, &decodeErr):
+		return &fetchError{kind: domain.ErrParse, err: err}
+	}
+	return err
+}
+
+// statusKind maps an unexpected HTTP status onto a domain fetch error, or nil
+// when none fits.
+func statusKind(code int) error {
+	switch {
+	case code == http.StatusNotFound:
+		return domain.ErrNotFound
+	case code == http.StatusTooManyRequests:
+		return domain.ErrRateLimited
+	case code >= http.StatusInternalServerError:
+		return domain.ErrUpstreamDown
+	}
+	return nil
+}
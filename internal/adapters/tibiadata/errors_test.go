@@ -0,0 +1,93 @@
+package tibiadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "not found", err: &api.StatusError{StatusCode: http.StatusNotFound}, want: domain.ErrNotFound},
+		{name: "rate limited", err: &api.StatusError{StatusCode: http.StatusTooManyRequests}, want: domain.ErrRateLimited},
+		{name: "server error", err: &api.StatusError{StatusCode: http.StatusBadGateway}, want: domain.ErrUpstreamDown},
+		{name: "network", err: &api.NetworkError{Err: errors.New("connection refused")}, want: domain.ErrUpstreamDown},
+		{name: "decode", err: &api.DecodeError{Err: errors.New("unexpected EOF")}, want: domain.ErrParse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if !errors.Is(got, tt.err) || got.Error() != tt.err.Error() {
+				t.Errorf("expected the original error to be kept, got %v", got)
+			}
+		})
+	}
+
+	t.Run("unclassified", func(t *testing.T) {
+		for _, err := range []error{nil, context.Canceled, &api.StatusError{StatusCode: http.StatusBadRequest}} {
+			if got := classify(err); got != err {
+				t.Errorf("expected %v unchanged, got %v", err, got)
+			}
+		}
+	})
+}
+
+func TestAdapter_FetchWorldFromTibiaCom_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{status: http.StatusTooManyRequests, want: domain.ErrRateLimited},
+		{status: http.StatusInternalServerError, want: domain.ErrUpstreamDown},
+		{status: http.StatusServiceUnavailable, want: domain.ErrWorldMaintenance},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			adapter := NewAdapter(api.NewClient(&config.Config{}), &config.Config{})
+			adapter.tibiaComClient = &http.Client{
+				Timeout:   time.Second,
+				Transport: &hijackTransport{target: server.URL},
+			}
+
+			_, err := adapter.FetchWorldFromTibiaCom(context.Background(), "Antica")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAdapter_FetchCharacter_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	adapter := NewAdapter(api.NewTestClient(server.URL), &config.Config{})
+
+	// The second lookup is served from the character cache.
+	for i := 0; i < 2; i++ {
+		if _, err := adapter.FetchCharacter(context.Background(), "Deleted"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("lookup %d: expected domain.ErrNotFound, got %v", i+1, err)
+		}
+	}
+}
@@ -15,17 +15,37 @@ func (a *Adapter) mapCharacter(char *api.CharacterResponse) *domain.Player {
 	var deaths []domain.Kill
 	for _, d := range char.Character.Deaths {
 		deaths = append(deaths, domain.Kill{
-			Time:   d.Time,
-			Level:  d.Level,
-			Reason: d.Reason,
+			Time:    d.Time,
+			Level:   d.Level,
+			Reason:  d.Reason,
+			Killers: mapKillers(d.Killers),
+			Assists: mapKillers(d.Assists),
 		})
 	}
 
 	return &domain.Player{
-		Name:     c.Name,
-		Level:    c.Level,
-		World:    c.World,
-		Vocation: c.Vocation,
-		Deaths:   deaths,
+		Name:        domain.NormalizeName(c.Name),
+		Level:       c.Level,
+		World:       c.World,
+		Vocation:    c.Vocation,
+		GuildName:   domain.NormalizeName(c.Guild.Name),
+		GuildRank:   c.Guild.Rank,
+		Deaths:      deaths,
+		FormerNames: domain.NormalizeNames(c.FormerNames),
 	}
 }
+
+func mapKillers(killers []api.Killer) []domain.Killer {
+	if len(killers) == 0 {
+		return nil
+	}
+	result := make([]domain.Killer, 0, len(killers))
+	for _, k := range killers {
+		result = append(result, domain.Killer{
+			Name:     domain.NormalizeName(k.Name),
+			IsPlayer: k.Player,
+			IsSummon: k.Summon != "",
+		})
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package tibiadata
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// poolOptions tune runPool.
+type poolOptions struct {
+	// workers bounds the fetches in flight; values below 1 mean 1.
+	workers int
+	// failFast stops the pool at the first failed fetch: inputs still
+	// queued are dropped and wait reports the error. Otherwise a failed
+	// input is only left out of the results.
+	failFast bool
+	// ordered delivers results in the order of the inputs rather than as
+	// they finish. A slow input then holds back the ones after it.
+	ordered bool
+}
+
+// runPool fetches every input with at most opts.workers fetches in flight
+// and streams the results. The channel is closed once the pool is done;
+// wait blocks until then and returns the first error when opts.failFast is
+// set, or ctx's error if it ended before every input was fetched. Inputs not
+// yet started when ctx ends are dropped. The channel is only for receiving;
+// it is typed bidirectional to match ports.TibiaFetcher.
+func runPool[In, Out any](ctx context.Context, inputs []In, opts poolOptions, fetch func(context.Context, In) (Out, error)) (results chan Out, wait func() error) {
+	out := make(chan Out, len(inputs))
+	deliver := newDelivery(out, len(inputs), opts.ordered)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(1, opts.workers))
+
+	done := make(chan error, 1)
+	go func() {
+		for i, in := range inputs {
+			if gctx.Err() != nil {
+				break
+			}
+			g.Go(func() error {
+				if gctx.Err() != nil {
+					deliver.skip(i)
+					return nil
+				}
+				v, err := fetch(gctx, in)
+				if err != nil {
+					deliver.skip(i)
+					if opts.failFast {
+						return err
+					}
+					return nil
+				}
+				deliver.send(i, v)
+				return nil
+			})
+		}
+		err := g.Wait()
+		if err == nil {
+			err = ctx.Err()
+		}
+		deliver.flush()
+		close(out)
+		done <- err
+	}()
+
+	return out, sync.OnceValue(func() error { return <-done })
+}
+
+// delivery hands results to the pool's channel, holding back those that
+// finish ahead of an earlier input in ordered mode. The channel has room for
+// every input, so sending never blocks a worker.
+type delivery[Out any] struct {
+	out     chan<- Out
+	ordered bool
+
+	mu       sync.Mutex
+	next     int
+	finished []bool
+	has      []bool
+	values   []Out
+}
+
+func newDelivery[Out any](out chan<- Out, n int, ordered bool) *delivery[Out] {
+	d := &delivery[Out]{out: out, ordered: ordered}
+	if ordered {
+		d.finished = make([]bool, n)
+		d.has = make([]bool, n)
+		d.values = make([]Out, n)
+	}
+	return d
+}
+
+func (d *delivery[Out]) send(i int, v Out) {
+	if !d.ordered {
+		d.out <- v
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.finished[i], d.has[i], d.values[i] = true, true, v
+	d.advance()
+}
+
+// skip marks input i as producing no result, releasing those behind it.
+func (d *delivery[Out]) skip(i int) {
+	if !d.ordered {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.finished[i] = true
+	d.advance()
+}
+
+// flush delivers what is still held back once no more inputs will finish,
+// e.g. results queued behind an input that was never started.
+func (d *delivery[Out]) flush() {
+	if !d.ordered {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for ; d.next < len(d.finished); d.next++ {
+		if d.has[d.next] {
+			d.out <- d.values[d.next]
+		}
+	}
+}
+
+func (d *delivery[Out]) advance() {
+	for ; d.next < len(d.finished) && d.finished[d.next]; d.next++ {
+		if d.has[d.next] {
+			d.out <- d.values[d.next]
+			var zero Out
+			d.values[d.next] = zero
+		}
+	}
+}
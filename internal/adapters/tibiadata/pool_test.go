@@ -0,0 +1,138 @@
+package tibiadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func collect[Out any](results chan Out) []Out {
+	var got []Out
+	for v := range results {
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestRunPool_BoundsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	inputs := make([]int, 20)
+	for i := range inputs {
+		inputs[i] = i
+	}
+
+	results, wait := runPool(context.Background(), inputs, poolOptions{workers: 3}, func(ctx context.Context, n int) (int, error) {
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); cur > p && !peak.CompareAndSwap(p, cur); p = peak.Load() {
+		}
+		time.Sleep(2 * time.Millisecond)
+		return n * 2, nil
+	})
+
+	got := collect(results)
+	if err := wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(inputs) {
+		t.Errorf("expected %d results, got %d", len(inputs), len(got))
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("expected at most 3 fetches in flight, got %d", p)
+	}
+}
+
+func TestRunPool_SkipsFailures(t *testing.T) {
+	results, wait := runPool(context.Background(), []int{1, 2, 3, 4}, poolOptions{workers: 2}, func(ctx context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return 0, fmt.Errorf("even %d", n)
+		}
+		return n, nil
+	})
+
+	got := collect(results)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("expected the odd inputs only, got %v", got)
+	}
+	if err := wait(); err != nil {
+		t.Errorf("expected failures to be left out without an error, got %v", err)
+	}
+}
+
+func TestRunPool_FailFast(t *testing.T) {
+	boom := errors.New("boom")
+	var started atomic.Int32
+	inputs := make([]int, 50)
+	for i := range inputs {
+		inputs[i] = i
+	}
+
+	results, wait := runPool(context.Background(), inputs, poolOptions{workers: 1, failFast: true}, func(ctx context.Context, n int) (int, error) {
+		started.Add(1)
+		if n == 2 {
+			return 0, boom
+		}
+		return n, nil
+	})
+
+	got := collect(results)
+	if err := wait(); !errors.Is(err, boom) {
+		t.Errorf("expected the first failure, got %v", err)
+	}
+	if err := wait(); !errors.Is(err, boom) {
+		t.Errorf("expected wait to keep reporting the failure, got %v", err)
+	}
+	if n := started.Load(); n >= int32(len(inputs)) {
+		t.Errorf("expected the queued inputs to be dropped, %d were started", n)
+	}
+	if len(got) > 3 {
+		t.Errorf("expected no results past the failure, got %v", got)
+	}
+}
+
+func TestRunPool_Ordered(t *testing.T) {
+	inputs := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	results, wait := runPool(context.Background(), inputs, poolOptions{workers: 4, ordered: true}, func(ctx context.Context, n int) (int, error) {
+		// Earlier inputs finish last.
+		time.Sleep(time.Duration(len(inputs)-n) * time.Millisecond)
+		if n == 3 {
+			return 0, errors.New("skipped")
+		}
+		return n, nil
+	})
+
+	got := collect(results)
+	if err := wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{0, 1, 2, 4, 5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("expected %v in input order, got %v", want, got)
+	}
+}
+
+func TestRunPool_CancelMidQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var started atomic.Int32
+	inputs := make([]int, 100)
+	results, wait := runPool(ctx, inputs, poolOptions{workers: 2, ordered: true}, func(ctx context.Context, n int) (int, error) {
+		if started.Add(1) == 4 {
+			cancel()
+		}
+		return n, nil
+	})
+
+	collect(results)
+	if err := wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := started.Load(); n >= int32(len(inputs)) {
+		t.Errorf("expected the queued inputs to be dropped, %d were started", n)
+	}
+}
@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"death-level-tracker/internal/adapters/tibiadata"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// Recorder passes every call to the wrapped fetcher and records what it
+// returned. Failing to record is logged and never fails the call.
+type Recorder struct {
+	next  ports.TibiaFetcher
+	store *store
+}
+
+var _ ports.TibiaFetcher = (*Recorder)(nil)
+
+func NewRecorder(next ports.TibiaFetcher, dir string) *Recorder {
+	return &Recorder{next: next, store: newStore(dir)}
+}
+
+func (r *Recorder) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
+	players, err := r.next.FetchWorld(ctx, world)
+	r.record(kindWorld, world, players, err)
+	return players, err
+}
+
+func (r *Recorder) FetchWorldFromTibiaCom(ctx context.Context, world string) (map[string]int, error) {
+	levels, err := r.next.FetchWorldFromTibiaCom(ctx, world)
+	r.record(kindTibiaComWorld, world, levels, err)
+	return levels, err
+}
+
+// FetchOnlinePlayers goes through this type's own world calls, so the
+// underlying responses are recorded.
+func (r *Recorder) FetchOnlinePlayers(ctx context.Context, source, world string) (*domain.OnlineList, error) {
+	return tibiadata.FetchOnlinePlayers(ctx, r, source, world)
+}
+
+func (r *Recorder) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	player, err := r.next.FetchCharacter(ctx, name)
+	r.record(kindCharacter, name, player, err)
+	return player, err
+}
+
+// FetchCharacterDetails records each character as it is delivered. Characters
+// the fetcher skips are not recorded and replay as not found.
+func (r *Recorder) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
+	in, err := r.next.FetchCharacterDetails(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *domain.Player, len(names))
+	go func() {
+		defer close(out)
+		for player := range in {
+			r.record(kindCharacter, player.Name, player, nil)
+			out <- player
+		}
+	}()
+	return out, nil
+}
+
+func (r *Recorder) FetchGuild(ctx context.Context, guildName string) (*domain.Guild, error) {
+	guild, err := r.next.FetchGuild(ctx, guildName)
+	r.record(kindGuild, guildName, guild, err)
+	return guild, err
+}
+
+func (r *Recorder) FetchGuildMembers(ctx context.Context, guildName string) ([]string, error) {
+	members, err := r.next.FetchGuildMembers(ctx, guildName)
+	r.record(kindGuildMembers, guildName, members, err)
+	return members, err
+}
+
+func (r *Recorder) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	names, err := r.next.FetchWorldGuilds(ctx, world)
+	r.record(kindWorldGuilds, world, names, err)
+	return names, err
+}
+
+func (r *Recorder) FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+	auctions, err := r.next.FetchHouseAuctions(ctx, world)
+	r.record(kindHouses, world, auctions, err)
+	return auctions, err
+}
+
+func (r *Recorder) FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+	result, err := r.next.FetchSkillHighscores(ctx, world, skill, page)
+	r.record(kindHighscores, highscoreKey(world, skill, page), result, err)
+	return result, err
+}
+
+func (r *Recorder) FetchBoosted(ctx context.Context) (*domain.Boosted, error) {
+	boosted, err := r.next.FetchBoosted(ctx)
+	r.record(kindBoosted, boostedKey, boosted, err)
+	return boosted, err
+}
+
+func (r *Recorder) FetchWorldPvPType(ctx context.Context, world string) (string, error) {
+	pvpType, err := r.next.FetchWorldPvPType(ctx, world)
+	r.record(kindWorldPvPType, world, pvpType, err)
+	return pvpType, err
+}
+
+// record skips calls cut short by shutdown; they say nothing about upstream.
+func (r *Recorder) record(kind, key string, result any, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if err := r.store.save(kind, key, result, err); err != nil {
+		slog.Warn("Failed to record fetcher response", "kind", kind, "key", key, "error", err)
+	}
+}
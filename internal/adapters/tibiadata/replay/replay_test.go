@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+type fakeFetcher struct {
+	ports.TibiaFetcher
+	worlds     map[string][]domain.Player
+	characters map[string]*domain.Player
+	err        error
+}
+
+func (f *fakeFetcher) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.worlds[world], nil
+}
+
+func (f *fakeFetcher) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	if player, ok := f.characters[name]; ok {
+		return player, nil
+	}
+	return nil, fmt.Errorf("character %s: %w", name, domain.ErrNotFound)
+}
+
+func (f *fakeFetcher) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
+	results := make(chan *domain.Player, len(names))
+	for _, name := range names {
+		if player, ok := f.characters[name]; ok {
+			results <- player
+		}
+	}
+	close(results)
+	return results, nil
+}
+
+func details(t *testing.T, f ports.TibiaFetcher, names ...string) map[string]*domain.Player {
+	t.Helper()
+	ch, err := f.FetchCharacterDetails(context.Background(), names)
+	if err != nil {
+		t.Fatalf("FetchCharacterDetails: %v", err)
+	}
+	got := make(map[string]*domain.Player)
+	for p := range ch {
+		got[p.Name] = p
+	}
+	return got
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	alice := &domain.Player{
+		Name:  "Alice Two",
+		Level: 250,
+		World: "Antica",
+		Deaths: []domain.Kill{{
+			Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Level:   251,
+			Reason:  "Killed at Level 251 by a dragon lord.",
+			Killers: []domain.Killer{{Name: "dragon lord"}},
+		}},
+	}
+	live := &fakeFetcher{
+		worlds:     map[string][]domain.Player{"Antica": {{Name: "Alice Two", Level: 250}}},
+		characters: map[string]*domain.Player{"Alice Two": alice},
+	}
+
+	recorder := NewRecorder(live, dir)
+	if _, err := recorder.FetchWorld(ctx, "Antica"); err != nil {
+		t.Fatalf("FetchWorld: %v", err)
+	}
+	details(t, recorder, "Alice Two", "Ghost")
+	if _, err := recorder.FetchCharacter(ctx, "Gone"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the live not found error, got %v", err)
+	}
+
+	replayer := NewReplayer(dir)
+
+	players, err := replayer.FetchWorld(ctx, "Antica")
+	if err != nil || !reflect.DeepEqual(players, live.worlds["Antica"]) {
+		t.Errorf("FetchWorld = %+v, %v", players, err)
+	}
+
+	got := details(t, replayer, "Alice Two", "Ghost")
+	if len(got) != 1 || !reflect.DeepEqual(got["Alice Two"], alice) {
+		t.Errorf("FetchCharacterDetails = %+v, want only Alice Two", got)
+	}
+
+	_, err = replayer.FetchCharacter(ctx, "Gone")
+	if !errors.Is(err, domain.ErrNotFound) || err.Error() != "character Gone: not found" {
+		t.Errorf("expected the recorded not found error, got %v", err)
+	}
+}
+
+func TestRecorder_KeepsErrorKinds(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	recorder := NewRecorder(&fakeFetcher{err: fmt.Errorf("fetch world: %w", domain.ErrRateLimited)}, dir)
+	recorder.FetchWorld(ctx, "Antica")
+
+	_, err := NewReplayer(dir).FetchWorld(ctx, "Antica")
+	if !errors.Is(err, domain.ErrRateLimited) {
+		t.Errorf("expected replayed error to be rate limited, got %v", err)
+	}
+}
+
+func TestRecorder_SkipsCancelledCalls(t *testing.T) {
+	dir := t.TempDir()
+
+	recorder := NewRecorder(&fakeFetcher{err: context.Canceled}, dir)
+	recorder.FetchWorld(context.Background(), "Antica")
+
+	if _, err := os.Stat(filepath.Join(dir, kindWorld)); !os.IsNotExist(err) {
+		t.Errorf("expected no recording for a cancelled call, got %v", err)
+	}
+}
+
+func TestReplayer_MissingRecording(t *testing.T) {
+	_, err := NewReplayer(t.TempDir()).FetchGuild(context.Background(), "Red Rose")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for a call never recorded, got %v", err)
+	}
+}
+
+func TestStore_EscapesKeys(t *testing.T) {
+	s := newStore(t.TempDir())
+
+	if err := s.save(kindGuild, "../Red/Rose", []string{"Alice"}, nil); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, kindGuild, "..%2FRed%2FRose.json")); err != nil {
+		t.Errorf("expected the key to be escaped into one file: %v", err)
+	}
+
+	var members []string
+	if err := s.load(kindGuild, "../Red/Rose", &members); err != nil || !reflect.DeepEqual(members, []string{"Alice"}) {
+		t.Errorf("load = %v, %v", members, err)
+	}
+}
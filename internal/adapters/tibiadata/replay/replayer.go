@@ -0,0 +1,126 @@
+package replay
+
+import (
+	"context"
+	"log/slog"
+
+	"death-level-tracker/internal/adapters/tibiadata"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// Replayer answers every call from the recordings in its directory and never
+// touches the network. Calls that were not recorded fail with
+// domain.ErrNotFound.
+type Replayer struct {
+	store *store
+}
+
+var _ ports.TibiaFetcher = (*Replayer)(nil)
+
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{store: newStore(dir)}
+}
+
+func (r *Replayer) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
+	var players []domain.Player
+	if err := r.store.load(kindWorld, world, &players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+func (r *Replayer) FetchWorldFromTibiaCom(ctx context.Context, world string) (map[string]int, error) {
+	var levels map[string]int
+	if err := r.store.load(kindTibiaComWorld, world, &levels); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// FetchOnlinePlayers goes through this type's own world calls, so the
+// underlying responses are served from the recording.
+func (r *Replayer) FetchOnlinePlayers(ctx context.Context, source, world string) (*domain.OnlineList, error) {
+	return tibiadata.FetchOnlinePlayers(ctx, r, source, world)
+}
+
+func (r *Replayer) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	var player *domain.Player
+	if err := r.store.load(kindCharacter, name, &player); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// FetchCharacterDetails delivers the recorded characters and, like the live
+// fetcher, skips those that cannot be loaded.
+func (r *Replayer) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
+	results := make(chan *domain.Player, len(names))
+	defer close(results)
+
+	for _, name := range names {
+		player, err := r.FetchCharacter(ctx, name)
+		if err != nil || player == nil {
+			slog.Debug("Skipping character without a usable recording", "name", name, "error", err)
+			continue
+		}
+		results <- player
+	}
+	return results, nil
+}
+
+func (r *Replayer) FetchGuild(ctx context.Context, guildName string) (*domain.Guild, error) {
+	var guild *domain.Guild
+	if err := r.store.load(kindGuild, guildName, &guild); err != nil {
+		return nil, err
+	}
+	return guild, nil
+}
+
+func (r *Replayer) FetchGuildMembers(ctx context.Context, guildName string) ([]string, error) {
+	var members []string
+	if err := r.store.load(kindGuildMembers, guildName, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *Replayer) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	var names []string
+	if err := r.store.load(kindWorldGuilds, world, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (r *Replayer) FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+	var auctions []domain.HouseAuction
+	if err := r.store.load(kindHouses, world, &auctions); err != nil {
+		return nil, err
+	}
+	return auctions, nil
+}
+
+func (r *Replayer) FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+	var result domain.HighscorePage
+	if err := r.store.load(kindHighscores, highscoreKey(world, skill, page), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *Replayer) FetchWorldPvPType(ctx context.Context, world string) (string, error) {
+	var pvpType string
+	if err := r.store.load(kindWorldPvPType, world, &pvpType); err != nil {
+		return "", err
+	}
+	return pvpType, nil
+}
+
+func (r *Replayer) FetchBoosted(ctx context.Context) (*domain.Boosted, error) {
+	var boosted domain.Boosted
+	if err := r.store.load(kindBoosted, boostedKey, &boosted); err != nil {
+		return nil, err
+	}
+	return &boosted, nil
+}
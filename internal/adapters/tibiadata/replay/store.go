@@ -0,0 +1,162 @@
+// Package replay records what the Tibia fetcher returns to disk and serves it
+// back later, so a bug seen with specific worlds or characters can be
+// reproduced offline and deterministically.
+//
+// Each call is stored as one JSON file, <dir>/<kind>/<key>.json, holding the
+// result or the error it returned. Files are plain JSON and may be edited by
+// hand to build a scenario.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// Kinds of recorded calls, each stored in its own subdirectory.
+const (
+	kindWorld         = "world"
+	kindTibiaComWorld = "tibiacom_world"
+	kindCharacter     = "character"
+	kindGuild         = "guild"
+	kindGuildMembers  = "guild_members"
+	kindWorldGuilds   = "world_guilds"
+	kindHouses        = "houses"
+	kindHighscores    = "highscores"
+	kindBoosted       = "boosted"
+	kindWorldPvPType  = "world_pvp_type"
+)
+
+// boostedKey is the only key of kindBoosted; a recording keeps the last
+// boosted creature and boss seen.
+const boostedKey = "today"
+
+// highscoreKey identifies one recorded page of a world's skill highscores.
+func highscoreKey(world string, skill domain.Skill, page int) string {
+	return fmt.Sprintf("%s/%s/%d", world, skill, page)
+}
+
+// errorKinds names the domain errors a recording keeps, so that replayed
+// errors still match with errors.Is.
+var errorKinds = map[string]error{
+	"maintenance":   domain.ErrWorldMaintenance,
+	"not_found":     domain.ErrNotFound,
+	"rate_limited":  domain.ErrRateLimited,
+	"upstream_down": domain.ErrUpstreamDown,
+	"parse":         domain.ErrParse,
+}
+
+// recording is the file stored for one call.
+type recording struct {
+	RecordedAt time.Time       `json:"recorded_at"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      *recordedError  `json:"error,omitempty"`
+}
+
+type recordedError struct {
+	Kind    string `json:"kind,omitempty"`
+	Message string `json:"message"`
+}
+
+// replayedError is a recorded error. It matches the domain error it was
+// recorded with.
+type replayedError struct {
+	kind    error
+	message string
+}
+
+func (e *replayedError) Error() string { return e.message }
+func (e *replayedError) Unwrap() error { return e.kind }
+
+// store reads and writes recordings below dir.
+type store struct {
+	dir string
+	now func() time.Time
+}
+
+func newStore(dir string) *store {
+	return &store{dir: dir, now: time.Now}
+}
+
+// path escapes key so names with spaces or slashes stay a single file.
+func (s *store) path(kind, key string) string {
+	return filepath.Join(s.dir, kind, url.PathEscape(key)+".json")
+}
+
+// save records result, or err when the call failed. The file is replaced
+// atomically so concurrent workers never leave a torn recording behind.
+func (s *store) save(kind, key string, result any, err error) error {
+	rec := recording{RecordedAt: s.now().UTC()}
+	if err != nil {
+		rec.Error = &recordedError{Kind: errorKind(err), Message: err.Error()}
+	} else {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("encode %s %q: %w", kind, key, err)
+		}
+		rec.Result = raw
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s %q: %w", kind, key, err)
+	}
+
+	path := s.path(kind, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".recording-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// load decodes the recording of kind and key into result, or returns the
+// error that was recorded. A call that was never recorded is reported as
+// domain.ErrNotFound, the way a missing character would be.
+func (s *store) load(kind, key string, result any) error {
+	data, err := os.ReadFile(s.path(kind, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no recording of %s %q: %w", kind, key, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return fmt.Errorf("read recording of %s %q: %w", kind, key, err)
+	}
+	if rec.Error != nil {
+		return &replayedError{kind: errorKinds[rec.Error.Kind], message: rec.Error.Message}
+	}
+	if err := json.Unmarshal(rec.Result, result); err != nil {
+		return fmt.Errorf("read recording of %s %q: %w", kind, key, err)
+	}
+	return nil
+}
+
+func errorKind(err error) string {
+	for name, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata from the parser's output")
+
+// worldGolden is what a saved world page is expected to parse into.
+type worldGolden struct {
+	Maintenance bool           `json:"maintenance,omitempty"`
+	Pages       int            `json:"pages,omitempty"`
+	Players     map[string]int `json:"players,omitempty"`
+}
+
+// TestParseTibiaComWorldPage_Snapshots parses every saved page in testdata
+// and compares the result with the .golden.json next to it. Add a page by
+// saving it as testdata/<name>.html and running the test with -update, then
+// check the written golden file by hand before committing it.
+func TestParseTibiaComWorldPage_Snapshots(t *testing.T) {
+	snapshots, err := filepath.Glob(filepath.Join("testdata", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) == 0 {
+		t.Fatal("no snapshots in testdata")
+	}
+
+	for _, snapshot := range snapshots {
+		name := strings.TrimSuffix(filepath.Base(snapshot), ".html")
+		t.Run(name, func(t *testing.T) {
+			got := parseSnapshot(t, snapshot)
+			goldenPath := strings.TrimSuffix(snapshot, ".html") + ".golden.json"
+
+			if *update {
+				data, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenPath, append(data, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			data, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create it): %v", err)
+			}
+			var want worldGolden
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatalf("decode golden file: %v", err)
+			}
+			compareGolden(t, want, got)
+		})
+	}
+}
+
+func parseSnapshot(t *testing.T, path string) worldGolden {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	page, err := ParseTibiaComWorldPage(f)
+	if errors.Is(err, domain.ErrWorldMaintenance) {
+		return worldGolden{Maintenance: true}
+	}
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return worldGolden{Pages: page.Pages, Players: page.Players}
+}
+
+// compareGolden reports the first differing players by name rather than
+// dumping lists that can run into thousands of entries.
+func compareGolden(t *testing.T, want, got worldGolden) {
+	t.Helper()
+	if want.Maintenance != got.Maintenance {
+		t.Fatalf("maintenance = %v, want %v", got.Maintenance, want.Maintenance)
+	}
+	if want.Pages != got.Pages {
+		t.Errorf("pages = %d, want %d", got.Pages, want.Pages)
+	}
+	if len(want.Players) == 0 && len(got.Players) == 0 || reflect.DeepEqual(want.Players, got.Players) {
+		return
+	}
+
+	t.Errorf("got %d players, want %d", len(got.Players), len(want.Players))
+	var diffs []string
+	for name, level := range want.Players {
+		if gotLevel, ok := got.Players[name]; !ok {
+			diffs = append(diffs, "missing "+name)
+		} else if gotLevel != level {
+			diffs = append(diffs, "wrong level for "+name)
+		}
+	}
+	for name := range got.Players {
+		if _, ok := want.Players[name]; !ok {
+			diffs = append(diffs, "unexpected "+name)
+		}
+	}
+	sort.Strings(diffs)
+	for _, diff := range diffs[:min(len(diffs), 10)] {
+		t.Error(diff)
+	}
+}
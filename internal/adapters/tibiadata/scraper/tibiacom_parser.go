@@ -5,28 +5,81 @@ import (
 	"io"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+
+	"death-level-tracker/internal/core/domain"
 
 	"golang.org/x/net/html"
 )
 
+var maintenancePhrases = []string{
+	"undergoing maintenance",
+	"under maintenance",
+}
+
+// playerColumns holds the cell indexes of the name and level columns.
+type playerColumns struct {
+	name  int
+	level int
+}
+
+// classicColumns is the fixed layout of Odd/Even player rows.
+var classicColumns = playerColumns{name: 0, level: 1}
+
+// WorldPage is one page of a Tibia.com world's online list.
+type WorldPage struct {
+	Players map[string]int
+	// Pages is how many pages the list is split over, at least 1.
+	Pages int
+}
+
+// ParseTibiaComWorld extracts online characters and their levels from a
+// Tibia.com world page. It reads the classic layout of Odd/Even rows as well
+// as the community layout, whose rows are unclassed and whose columns follow a
+// Name/Level header. It returns domain.ErrWorldMaintenance when no players
+// are listed and the page reports maintenance or the world is offline.
 func ParseTibiaComWorld(r io.Reader) (map[string]int, error) {
+	page, err := ParseTibiaComWorldPage(r)
+	if err != nil {
+		return nil, err
+	}
+	return page.Players, nil
+}
+
+// ParseTibiaComWorldPage is ParseTibiaComWorld for one page of a paged list.
+// The page count is read from the page navigation, whose current page is
+// marked rather than linked; a page without navigation is the only one.
+func ParseTibiaComWorldPage(r io.Reader) (*WorldPage, error) {
 	doc, err := html.Parse(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	players := make(map[string]int)
-	var traverse func(*html.Node)
+	page := &WorldPage{Players: make(map[string]int), Pages: 1}
+	headers := make(map[*html.Node]playerColumns)
 
+	var traverse func(*html.Node)
 	traverse = func(n *html.Node) {
 		if n.Type == html.ElementNode && n.Data == "tr" {
-			if isPlayerRow(n) {
-				name, level := extractPlayerData(n)
-				if name != "" && level > 0 {
-					players[name] = level
-				}
+			cells := rowCells(n)
+			table := enclosingTable(n)
+			if cols, ok := headerColumns(cells); ok {
+				headers[table] = cols
+			} else if cols, ok := headers[table]; ok {
+				addPlayer(page.Players, cells, cols)
+			} else if isPlayerRow(n) {
+				addPlayer(page.Players, cells, classicColumns)
+			}
+		}
+		if n.Type == html.ElementNode && n.Data == "a" {
+			page.Pages = max(page.Pages, linkedPage(n))
+		}
+		if n.Type == html.ElementNode && hasClass(n, "CurrentPageLink") {
+			if current, err := strconv.Atoi(strings.TrimSpace(getTextContent(n))); err == nil {
+				page.Pages = max(page.Pages, current)
 			}
 		}
 
@@ -36,7 +89,64 @@ func ParseTibiaComWorld(r io.Reader) (map[string]int, error) {
 	}
 
 	traverse(doc)
-	return players, nil
+
+	// An empty page is checked for maintenance only, so that a stray mention
+	// of maintenance elsewhere never hides online players.
+	if len(page.Players) == 0 && isMaintenancePage(doc) {
+		return nil, domain.ErrWorldMaintenance
+	}
+	return page, nil
+}
+
+// linkedPage returns the page a navigation link points to, or 0 when the
+// link is not one.
+func linkedPage(a *html.Node) int {
+	for _, attr := range a.Attr {
+		if attr.Key != "href" {
+			continue
+		}
+		u, err := url.Parse(attr.Val)
+		if err != nil {
+			return 0
+		}
+		page, err := strconv.Atoi(u.Query().Get("currentpage"))
+		if err != nil {
+			return 0
+		}
+		return page
+	}
+	return 0
+}
+
+// isMaintenancePage detects the maintenance notice and the world information
+// table reporting the world as offline.
+func isMaintenancePage(doc *html.Node) bool {
+	text := strings.ToLower(visibleText(doc))
+	for _, phrase := range maintenancePhrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+
+	offline := false
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if offline {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "tr" {
+			cells := rowCells(n)
+			if len(cells) >= 2 && cellText(cells[0]) == "status:" && strings.Contains(cellText(cells[1]), "offline") {
+				offline = true
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+	return offline
 }
 
 func isPlayerRow(n *html.Node) bool {
@@ -48,53 +158,81 @@ func isPlayerRow(n *html.Node) bool {
 	return false
 }
 
-func extractPlayerData(tr *html.Node) (string, int) {
-	var cells []*html.Node
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" && slices.Contains(strings.Fields(attr.Val), class) {
+			return true
+		}
+	}
+	return false
+}
 
+func rowCells(tr *html.Node) []*html.Node {
+	var cells []*html.Node
 	for c := tr.FirstChild; c != nil; c = c.NextSibling {
-		if c.Type == html.ElementNode && c.Data == "td" {
+		if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
 			cells = append(cells, c)
 		}
 	}
+	return cells
+}
 
-	if len(cells) < 2 {
-		return "", 0
+func enclosingTable(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "table" {
+			return p
+		}
 	}
+	return nil
+}
 
-	name := extractPlayerName(cells[0])
-	level := extractLevel(cells[1])
+// headerColumns recognises a header row by its Name and Level cells.
+func headerColumns(cells []*html.Node) (playerColumns, bool) {
+	cols := playerColumns{name: -1, level: -1}
+	for i, cell := range cells {
+		switch cellText(cell) {
+		case "name":
+			cols.name = i
+		case "level":
+			cols.level = i
+		}
+	}
+	return cols, cols.name >= 0 && cols.level >= 0
+}
+
+func addPlayer(players map[string]int, cells []*html.Node, cols playerColumns) {
+	if cols.name >= len(cells) || cols.level >= len(cells) {
+		return
+	}
 
-	return name, level
+	name := extractPlayerName(cells[cols.name])
+	level := extractLevel(cells[cols.level])
+	if name != "" && level > 0 {
+		players[name] = level
+	}
 }
 
 func extractPlayerName(td *html.Node) string {
-	var link *html.Node
-
-	for c := td.FirstChild; c != nil; c = c.NextSibling {
-		if c.Type == html.ElementNode && c.Data == "a" {
-			for _, attr := range c.Attr {
+	var name string
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if name != "" {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "a" {
+			for _, attr := range n.Attr {
 				if attr.Key == "href" && strings.Contains(attr.Val, "name=") {
-					link = c
-					break
+					name = extractNameFromURL(attr.Val)
+					return
 				}
 			}
-			if link != nil {
-				break
-			}
 		}
-	}
-
-	if link == nil {
-		return ""
-	}
-
-	for _, attr := range link.Attr {
-		if attr.Key == "href" && strings.Contains(attr.Val, "name=") {
-			return extractNameFromURL(attr.Val)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
 		}
 	}
-
-	return ""
+	find(td)
+	return name
 }
 
 func extractNameFromURL(link string) string {
@@ -108,21 +246,30 @@ func extractNameFromURL(link string) string {
 	if err != nil {
 		return ""
 	}
-	return decoded
+	return domain.NormalizeName(decoded)
 }
 
+// extractLevel parses a level cell, ignoring thousand separators such as
+// "1,756", "1.756" or "1 756".
 func extractLevel(td *html.Node) int {
-	text := getTextContent(td)
-	text = strings.TrimSpace(text)
+	text := strings.Map(func(r rune) rune {
+		if r == ',' || r == '.' || r == '\'' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, getTextContent(td))
 
 	level, err := strconv.Atoi(text)
 	if err != nil {
 		return 0
 	}
-
 	return level
 }
 
+func cellText(td *html.Node) string {
+	return strings.ToLower(strings.TrimFunc(getTextContent(td), unicode.IsSpace))
+}
+
 func getTextContent(n *html.Node) string {
 	if n.Type == html.TextNode {
 		return n.Data
@@ -132,6 +279,21 @@ func getTextContent(n *html.Node) string {
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		text.WriteString(getTextContent(c))
 	}
+	return text.String()
+}
+
+// visibleText is like getTextContent but skips scripts and styles.
+func visibleText(n *html.Node) string {
+	if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+		return ""
+	}
+	if n.Type == html.TextNode {
+		return n.Data
+	}
 
+	var text strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		text.WriteString(visibleText(c))
+	}
 	return text.String()
 }
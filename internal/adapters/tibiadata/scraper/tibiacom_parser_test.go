@@ -1,8 +1,11 @@
 package scraper
 
 import (
+	"errors"
 	"strings"
 	"testing"
+
+	"death-level-tracker/internal/core/domain"
 )
 
 func TestParseTibiaComWorld(t *testing.T) {
@@ -122,6 +125,52 @@ func TestParseTibiaComWorld(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Community Layout - Header Defines Columns",
+			htmlInput: `
+				<html><body><table class="TableContent">
+					<tr><td class="LabelV">Name</td><td class="LabelV">Vocation</td><td class="LabelV">Level</td></tr>
+					<tr bgcolor="#F1E0C6">
+						<td><a href="https://www.tibia.com/community/?subtopic=characters&name=Bubble">Bubble</a></td>
+						<td>Elite Knight</td>
+						<td>100</td>
+					</tr>
+					<tr bgcolor="#D4C0A1">
+						<td><span><a href="https://www.tibia.com/community/?subtopic=characters&name=Saayo">Saayo</a></span></td>
+						<td>Master Sorcerer</td>
+						<td>1756</td>
+					</tr>
+				</table></body></html>`,
+			want: map[string]int{
+				"Bubble": 100,
+				"Saayo":  1756,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Edge Case - Thousand Separators",
+			htmlInput: `
+				<html><body><table>
+					<tr class="Odd">
+						<td><a href="...&name=Comma">Comma</a></td>
+						<td>1,756</td>
+					</tr>
+					<tr class="Even">
+						<td><a href="...&name=Dot">Dot</a></td>
+						<td>2.104</td>
+					</tr>
+					<tr class="Odd">
+						<td><a href="...&name=Space">Space</a></td>
+						<td>1&#160;001</td>
+					</tr>
+				</table></body></html>`,
+			want: map[string]int{
+				"Comma": 1756,
+				"Dot":   2104,
+				"Space": 1001,
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -164,6 +213,8 @@ func TestExtractNameFromURL_Internal(t *testing.T) {
 		{"?name=Quote%27s", "Quote's"},
 		{"?other=1&name=MiddleParam&foo=bar", "MiddleParam"},
 		{"https://tibia.com?name=Trailing", "Trailing"},
+		{"?name=Non%C2%A0Breaking", "Non Breaking"},
+		{"?name=Double%20%20Space", "Double Space"},
 	}
 
 	for _, tt := range tests {
@@ -172,3 +223,93 @@ func TestExtractNameFromURL_Internal(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTibiaComWorld_Maintenance(t *testing.T) {
+	tests := []struct {
+		name      string
+		htmlInput string
+	}{
+		{
+			name:      "Maintenance Notice",
+			htmlInput: `<html><body><div>Tibia is currently undergoing maintenance. Please try again later.</div></body></html>`,
+		},
+		{
+			name: "World Offline",
+			htmlInput: `
+				<html><body><table>
+					<tr><td class="LabelV200">Status:</td><td>Offline</td></tr>
+					<tr><td class="LabelV200">Players Online:</td><td>0</td></tr>
+				</table></body></html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTibiaComWorld(strings.NewReader(tt.htmlInput))
+			if !errors.Is(err, domain.ErrWorldMaintenance) {
+				t.Errorf("expected ErrWorldMaintenance, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseTibiaComWorld_MaintenanceMentionWithPlayers(t *testing.T) {
+	htmlInput := `
+		<html><body>
+		<p>The server will be under maintenance tomorrow.</p>
+		<table>
+			<tr class="Odd"><td><a href="...&name=Bubble">Bubble</a></td><td>100</td></tr>
+		</table>
+		</body></html>`
+
+	got, err := ParseTibiaComWorld(strings.NewReader(htmlInput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["Bubble"] != 100 {
+		t.Errorf("expected Bubble at level 100, got %v", got)
+	}
+}
+
+func TestParseTibiaComWorldPage_Pages(t *testing.T) {
+	tests := []struct {
+		name      string
+		htmlInput string
+		wantPages int
+	}{
+		{
+			name:      "No Navigation",
+			htmlInput: `<html><body><table><tr class="Odd"><td><a href="?name=Bubble">Bubble</a></td><td>100</td></tr></table></body></html>`,
+			wantPages: 1,
+		},
+		{
+			name: "Page Navigation",
+			htmlInput: `
+				<html><body>
+				<table><tr class="Odd"><td><a href="?name=Bubble">Bubble</a></td><td>100</td></tr></table>
+				<div class="PageNavigation">
+					<span class="PageLink"><b>1</b></span>
+					<span class="PageLink"><a href="https://www.tibia.com/community/?subtopic=worlds&world=Antica&currentpage=2">2</a></span>
+					<span class="PageLink"><a href="https://www.tibia.com/community/?subtopic=worlds&world=Antica&currentpage=3">3</a></span>
+					<span class="PageLink"><a href="https://www.tibia.com/community/?subtopic=worlds&world=Antica&currentpage=2">&gt;</a></span>
+				</div>
+				</body></html>`,
+			wantPages: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ParseTibiaComWorldPage(strings.NewReader(tt.htmlInput))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page.Pages != tt.wantPages {
+				t.Errorf("expected %d pages, got %d", tt.wantPages, page.Pages)
+			}
+			if page.Players["Bubble"] != 100 {
+				t.Errorf("expected Bubble at level 100, got %v", page.Players)
+			}
+		})
+	}
+}
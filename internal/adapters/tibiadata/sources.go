@@ -0,0 +1,86 @@
+package tibiadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// levelSource lists a world's online players from one upstream. A new
+// upstream only needs an entry in levelSources and a name accepted by
+// LEVEL_SOURCES; the tracker walks whatever order is configured.
+type levelSource struct {
+	fetch func(ctx context.Context, f ports.TibiaFetcher, world string) (*domain.OnlineList, error)
+	// fallThrough reports whether err leaves the next source worth trying.
+	fallThrough func(err error) bool
+}
+
+var levelSources = map[string]levelSource{
+	// tibia.com lists every online character with a live level, but its
+	// page changes without notice, so any failure short of maintenance
+	// hands over to the next source.
+	config.LevelSourceTibiaCom: {
+		fetch: func(ctx context.Context, f ports.TibiaFetcher, world string) (*domain.OnlineList, error) {
+			levels, err := f.FetchWorldFromTibiaCom(ctx, world)
+			if err != nil {
+				return nil, err
+			}
+			return &domain.OnlineList{Players: levelsToPlayers(levels), Live: true}, nil
+		},
+		fallThrough: func(err error) bool {
+			return !errors.Is(err, domain.ErrWorldMaintenance)
+		},
+	},
+	// TibiaData caches its world pages, so levels are checked against each
+	// character page. Only an outage or an unreadable answer hands over;
+	// a rate limit is the caller's cue to back off.
+	config.LevelSourceTibiaData: {
+		fetch: func(ctx context.Context, f ports.TibiaFetcher, world string) (*domain.OnlineList, error) {
+			players, err := f.FetchWorld(ctx, world)
+			if err != nil {
+				return nil, err
+			}
+			return &domain.OnlineList{Players: players}, nil
+		},
+		fallThrough: func(err error) bool {
+			return errors.Is(err, domain.ErrUpstreamDown) || errors.Is(err, domain.ErrParse)
+		},
+	},
+}
+
+// FetchOnlinePlayers lists world's online players from the named source
+// through f, so wrappers like the replay recorder see the underlying calls.
+// Failures the next source may not share also wrap
+// domain.ErrSourceUnavailable.
+func FetchOnlinePlayers(ctx context.Context, f ports.TibiaFetcher, source, world string) (*domain.OnlineList, error) {
+	ls, ok := levelSources[source]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown level source %q", domain.ErrSourceUnavailable, source)
+	}
+
+	list, err := ls.fetch(ctx, f, world)
+	if err != nil {
+		if ls.fallThrough(err) {
+			return nil, fmt.Errorf("%s: %w: %w", source, domain.ErrSourceUnavailable, err)
+		}
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	list.Source = source
+	return list, nil
+}
+
+func (a *Adapter) FetchOnlinePlayers(ctx context.Context, source, world string) (*domain.OnlineList, error) {
+	return FetchOnlinePlayers(ctx, a, source, world)
+}
+
+func levelsToPlayers(levels map[string]int) []domain.Player {
+	players := make([]domain.Player, 0, len(levels))
+	for name, level := range levels {
+		players = append(players, domain.Player{Name: name, Level: level})
+	}
+	return players
+}
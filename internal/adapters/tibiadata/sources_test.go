@@ -0,0 +1,96 @@
+package tibiadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+type worldFetcher struct {
+	ports.TibiaFetcher
+	tibiaDataErr error
+	tibiaComErr  error
+}
+
+func (f *worldFetcher) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
+	if f.tibiaDataErr != nil {
+		return nil, f.tibiaDataErr
+	}
+	return []domain.Player{{Name: "Alice", Level: 150, Vocation: "Knight"}}, nil
+}
+
+func (f *worldFetcher) FetchWorldFromTibiaCom(ctx context.Context, world string) (map[string]int, error) {
+	if f.tibiaComErr != nil {
+		return nil, f.tibiaComErr
+	}
+	return map[string]int{"Alice": 151}, nil
+}
+
+func TestFetchOnlinePlayers(t *testing.T) {
+	t.Run("tibiacom lists live levels", func(t *testing.T) {
+		list, err := FetchOnlinePlayers(context.Background(), &worldFetcher{}, "tibiacom", "Antica")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list.Source != "tibiacom" || !list.Live || len(list.Players) != 1 || list.Players[0].Level != 151 {
+			t.Errorf("unexpected list: %+v", list)
+		}
+	})
+
+	t.Run("tibiadata needs character pages", func(t *testing.T) {
+		list, err := FetchOnlinePlayers(context.Background(), &worldFetcher{}, "tibiadata", "Antica")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list.Source != "tibiadata" || list.Live || len(list.Players) != 1 {
+			t.Errorf("unexpected list: %+v", list)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		_, err := FetchOnlinePlayers(context.Background(), &worldFetcher{}, "guildstats", "Antica")
+		if !errors.Is(err, domain.ErrSourceUnavailable) {
+			t.Errorf("expected ErrSourceUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestFetchOnlinePlayers_FallThrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		fetcher *worldFetcher
+		want    bool
+	}{
+		{"tibiacom rate limited", "tibiacom", &worldFetcher{tibiaComErr: domain.ErrRateLimited}, true},
+		{"tibiacom unclassified", "tibiacom", &worldFetcher{tibiaComErr: errors.New("boom")}, true},
+		{"tibiacom maintenance", "tibiacom", &worldFetcher{tibiaComErr: domain.ErrWorldMaintenance}, false},
+		{"tibiadata down", "tibiadata", &worldFetcher{tibiaDataErr: fmt.Errorf("request failed: %w", domain.ErrUpstreamDown)}, true},
+		{"tibiadata unreadable", "tibiadata", &worldFetcher{tibiaDataErr: domain.ErrParse}, true},
+		{"tibiadata rate limited", "tibiadata", &worldFetcher{tibiaDataErr: domain.ErrRateLimited}, false},
+		{"tibiadata not found", "tibiadata", &worldFetcher{tibiaDataErr: domain.ErrNotFound}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FetchOnlinePlayers(context.Background(), tt.fetcher, tt.source, "Antica")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := errors.Is(err, domain.ErrSourceUnavailable); got != tt.want {
+				t.Errorf("expected fall through %v, got %v (%v)", tt.want, got, err)
+			}
+			cause := tt.fetcher.tibiaComErr
+			if tt.source == "tibiadata" {
+				cause = tt.fetcher.tibiaDataErr
+			}
+			if !errors.Is(err, cause) {
+				t.Errorf("expected the cause %v to be kept, got %v", cause, err)
+			}
+		})
+	}
+}
@@ -11,17 +11,86 @@ import (
 )
 
 type Config struct {
-	Token                string
-	TrackerInterval      time.Duration
-	MinLevelTrack        int
-	DiscordChannelDeath  string
-	DiscordChannelLevel  string
-	WorkerPoolSize       int
-	UseTibiaComForLevels bool
-	DiscordGuildID       string
-	DatabaseURL          string
+	Token                  string
+	TrackerInterval        time.Duration
+	WorldPollIntervals     map[string]time.Duration
+	ServerSaveQuietWindow  time.Duration
+	AdaptiveInterval       bool
+	QuietFirstCycle        bool
+	LevelUpCooldown        time.Duration
+	DiscordTimestamps      bool
+	HousePollInterval      time.Duration
+	SkillPollInterval      time.Duration
+	RashidDailyPost        bool
+	BoostedDailyPost       bool
+	MinLevelTrack          int
+	DiscordChannelDeath    string
+	DiscordChannelLevel    string
+	DiscordChannelAudit    string
+	WorkerPoolSize         int
+	LevelSources           []string
+	DiscordGuildID         string
+	DiscordWelcomeMessage  bool
+	StorageDriver          string
+	DatabaseURL            string
+	DebugAddr              string
+	DebugDumpDir           string
+	NotificationMaxAge     time.Duration
+	LeaderElection         bool
+	CharacterCacheTTL      time.Duration
+	CharacterCacheSize     int
+	DBMaxConns             int
+	DBMinConns             int
+	DBMaxConnLifetime      time.Duration
+	DBSlowQueryThreshold   time.Duration
+	MigrateOnStart         bool
+	GuildCacheTTL          time.Duration
+	GuildRemovalGrace      time.Duration
+	PlayerHistoryRetention time.Duration
+	TibiaDataBaseURL       string
+	TibiaComBaseURL        string
+	TibiaDataAuthHeader    string
+	TibiaDataAuthToken     string
+	TibiaDataTimeout       time.Duration
+	TibiaComTimeout        time.Duration
+	CharacterFetchTimeout  time.Duration
+	WorldCycleTimeout      time.Duration
+	TibiaDataProxyURL      string
+	TibiaComProxyURL       string
+	HTTPMaxIdleConns       int
+	HTTPCAFile             string
+	ReplayMode             string
+	ReplayDir              string
+	NotifyDryRun           bool
+	MaxTibiaGuilds         int
+	MaxIgnoredPlayers      int
+	MaxWatchedPlayers      int
+	MaxWorlds              int
+	PremiumGating          bool
+	PremiumGuilds          []string
+	FreeMinPollInterval    time.Duration
+	PremiumMaxTibiaGuilds  int
 }
 
+// Storage drivers selectable with STORAGE_DRIVER.
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverMemory   = "memory"
+)
+
+// Level sources selectable with LEVEL_SOURCES, tried in the listed order.
+const (
+	LevelSourceTibiaCom  = "tibiacom"
+	LevelSourceTibiaData = "tibiadata"
+)
+
+// Modes of REPLAY_MODE. Recording saves every fetcher response below
+// REPLAY_DIR; replaying serves them back without touching the network.
+const (
+	ReplayModeRecord = "record"
+	ReplayModeReplay = "replay"
+)
+
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
@@ -33,24 +102,78 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("DISCORD_TOKEN is not set (via secret or env var)")
 	}
 
-	dbURL := readSecret("database_url")
-	if dbURL == "" {
-		dbURL = os.Getenv("DATABASE_URL")
+	// The memory driver needs no database, so DATABASE_URL is optional there.
+	storageDriver := envString("STORAGE_DRIVER", StorageDriverPostgres)
+	dbURL, err := LoadDatabaseURL()
+	if err != nil && storageDriver != StorageDriverMemory {
+		return nil, err
 	}
-	if dbURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL is not set (via secret or env var)")
+
+	authToken := readSecret("tibiadata_auth_token")
+	if authToken == "" {
+		authToken = os.Getenv("TIBIADATA_AUTH_TOKEN")
 	}
 
 	cfg := &Config{
-		Token:                token,
-		TrackerInterval:      envDuration("TRACKER_INTERVAL", 5*time.Minute),
-		MinLevelTrack:        envInt("MIN_LEVEL_TRACK", 500),
-		DiscordChannelDeath:  envString("DISCORD_CHANNEL_DEATH", "death-tracker"),
-		DiscordChannelLevel:  envString("DISCORD_CHANNEL_LEVEL", "level-tracker"),
-		WorkerPoolSize:       envInt("WORKER_POOL_SIZE", 10),
-		UseTibiaComForLevels: envBool("USE_TIBIACOM_FOR_LEVELS", true),
-		DiscordGuildID:       envString("DISCORD_GUILD_ID", ""),
-		DatabaseURL:          dbURL,
+		Token:                  token,
+		TrackerInterval:        envDuration("TRACKER_INTERVAL", 5*time.Minute),
+		WorldPollIntervals:     envDurationMap("WORLD_POLL_INTERVALS"),
+		ServerSaveQuietWindow:  envDuration("SERVER_SAVE_QUIET_WINDOW", 10*time.Minute),
+		AdaptiveInterval:       envBool("ADAPTIVE_INTERVAL", true),
+		QuietFirstCycle:        envBool("QUIET_FIRST_CYCLE", true),
+		LevelUpCooldown:        envDuration("LEVEL_UP_COOLDOWN", 5*time.Minute),
+		DiscordTimestamps:      envBool("DISCORD_TIMESTAMPS", true),
+		HousePollInterval:      envDuration("HOUSE_POLL_INTERVAL", 30*time.Minute),
+		SkillPollInterval:      envDuration("SKILL_POLL_INTERVAL", time.Hour),
+		RashidDailyPost:        envBool("RASHID_DAILY_POST", true),
+		BoostedDailyPost:       envBool("BOOSTED_DAILY_POST", true),
+		MinLevelTrack:          envInt("MIN_LEVEL_TRACK", 500),
+		DiscordChannelDeath:    envString("DISCORD_CHANNEL_DEATH", "death-tracker"),
+		DiscordChannelLevel:    envString("DISCORD_CHANNEL_LEVEL", "level-tracker"),
+		DiscordChannelAudit:    envString("DISCORD_CHANNEL_AUDIT", "tracker-audit"),
+		WorkerPoolSize:         envInt("WORKER_POOL_SIZE", 10),
+		LevelSources:           levelSources(),
+		DiscordGuildID:         envString("DISCORD_GUILD_ID", ""),
+		DiscordWelcomeMessage:  envBool("DISCORD_WELCOME_MESSAGE", true),
+		StorageDriver:          storageDriver,
+		DatabaseURL:            dbURL,
+		DebugAddr:              envString("DEBUG_ADDR", ""),
+		DebugDumpDir:           envString("DEBUG_DUMP_DIR", os.TempDir()),
+		NotificationMaxAge:     envDuration("NOTIFICATION_MAX_AGE", 24*time.Hour),
+		LeaderElection:         envBool("LEADER_ELECTION", true),
+		CharacterCacheTTL:      envDuration("CHARACTER_CACHE_TTL", 10*time.Minute),
+		CharacterCacheSize:     envInt("CHARACTER_CACHE_SIZE", 5000),
+		DBMaxConns:             envInt("DB_MAX_CONNS", 10),
+		DBMinConns:             envInt("DB_MIN_CONNS", 0),
+		DBMaxConnLifetime:      envDuration("DB_MAX_CONN_LIFETIME", time.Hour),
+		DBSlowQueryThreshold:   envDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+		MigrateOnStart:         envBool("MIGRATE_ON_START", true),
+		GuildCacheTTL:          envDuration("GUILD_CACHE_TTL", 15*time.Minute),
+		GuildRemovalGrace:      envDuration("GUILD_REMOVAL_GRACE", 7*24*time.Hour),
+		PlayerHistoryRetention: envDuration("PLAYER_HISTORY_RETENTION", 90*24*time.Hour),
+		TibiaDataBaseURL:       envString("TIBIADATA_BASE_URL", "https://api.tibiadata.com/v4"),
+		TibiaComBaseURL:        envString("TIBIACOM_BASE_URL", "https://www.tibia.com"),
+		TibiaDataAuthHeader:    envString("TIBIADATA_AUTH_HEADER", "Authorization"),
+		TibiaDataAuthToken:     authToken,
+		TibiaDataTimeout:       envDuration("TIBIADATA_TIMEOUT", 10*time.Second),
+		CharacterFetchTimeout:  envDuration("CHARACTER_FETCH_TIMEOUT", 5*time.Second),
+		WorldCycleTimeout:      envDuration("WORLD_CYCLE_TIMEOUT", 2*time.Minute),
+		TibiaComTimeout:        envDuration("TIBIACOM_TIMEOUT", 30*time.Second),
+		TibiaDataProxyURL:      envString("TIBIADATA_PROXY_URL", ""),
+		TibiaComProxyURL:       envString("TIBIACOM_PROXY_URL", ""),
+		HTTPMaxIdleConns:       envInt("HTTP_MAX_IDLE_CONNS", 100),
+		HTTPCAFile:             envString("HTTP_CA_FILE", ""),
+		ReplayMode:             envString("REPLAY_MODE", ""),
+		ReplayDir:              envString("REPLAY_DIR", ""),
+		NotifyDryRun:           envBool("NOTIFY_DRY_RUN", false),
+		MaxTibiaGuilds:         envInt("MAX_TIBIA_GUILDS", 0),
+		MaxIgnoredPlayers:      envInt("MAX_IGNORED_PLAYERS", 0),
+		MaxWatchedPlayers:      envInt("MAX_WATCHED_PLAYERS", 25),
+		MaxWorlds:              envInt("MAX_WORLDS", 0),
+		PremiumGating:          envBool("PREMIUM_GATING", false),
+		PremiumGuilds:          envList("PREMIUM_GUILDS"),
+		FreeMinPollInterval:    envDuration("FREE_MIN_POLL_INTERVAL", 15*time.Minute),
+		PremiumMaxTibiaGuilds:  envInt("PREMIUM_MAX_TIBIA_GUILDS", 0),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -60,6 +183,21 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// LoadDatabaseURL reads only the database connection string, for commands
+// such as migrate that do not need a Discord token.
+func LoadDatabaseURL() (string, error) {
+	_ = godotenv.Load()
+
+	dbURL := readSecret("database_url")
+	if dbURL == "" {
+		dbURL = os.Getenv("DATABASE_URL")
+	}
+	if dbURL == "" {
+		return "", fmt.Errorf("DATABASE_URL is not set (via secret or env var)")
+	}
+	return dbURL, nil
+}
+
 var secretsDir = "/run/secrets/"
 
 func readSecret(name string) string {
@@ -86,8 +224,14 @@ func envInt(key string, fallback int) int {
 	return fallback
 }
 
+// envDuration accepts time.ParseDuration syntax plus whole days such as "90d".
 func envDuration(key string, fallback time.Duration) time.Duration {
 	if v := os.Getenv(key); v != "" {
+		if days, ok := strings.CutSuffix(v, "d"); ok {
+			if n, err := strconv.Atoi(days); err == nil {
+				return time.Duration(n) * 24 * time.Hour
+			}
+		}
 		if d, err := time.ParseDuration(v); err == nil {
 			return d
 		}
@@ -95,6 +239,50 @@ func envDuration(key string, fallback time.Duration) time.Duration {
 	return fallback
 }
 
+// envDurationMap parses "Key=duration" pairs separated by commas, e.g.
+// "Antica=2m,Secura=10m". Malformed entries are skipped.
+func envDurationMap(key string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		result[strings.TrimSpace(name)] = d
+	}
+	return result
+}
+
+// envList parses comma separated values, skipping empty ones.
+func envList(key string) []string {
+	var result []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// levelSources reads LEVEL_SOURCES, falling back to the order implied by the
+// older USE_TIBIACOM_FOR_LEVELS switch.
+func levelSources() []string {
+	if sources := envList("LEVEL_SOURCES"); len(sources) > 0 {
+		for i, source := range sources {
+			sources[i] = strings.ToLower(source)
+		}
+		return sources
+	}
+	if envBool("USE_TIBIACOM_FOR_LEVELS", true) {
+		return []string{LevelSourceTibiaCom, LevelSourceTibiaData}
+	}
+	return []string{LevelSourceTibiaData, LevelSourceTibiaCom}
+}
+
 func envBool(key string, fallback bool) bool {
 	if v := os.Getenv(key); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
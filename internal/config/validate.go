@@ -1,19 +1,43 @@
 package config
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 )
 
 const (
-	minTokenLength     = 50
-	minTrackerInterval = 1 * time.Minute
-	maxTrackerInterval = 24 * time.Hour
-	minLevelTrack      = 1
-	minWorkerPoolSize  = 1
-	maxWorkerPoolSize  = 100
-	maxChannelNameLen  = 100
+	minTokenLength      = 50
+	minTrackerInterval  = 1 * time.Minute
+	maxTrackerInterval  = 24 * time.Hour
+	minLevelTrack       = 1
+	minWorkerPoolSize   = 1
+	maxWorkerPoolSize   = 100
+	maxChannelNameLen   = 100
+	maxQuietWindow      = 2 * time.Hour
+	maxLevelUpCooldown  = time.Hour
+	minHousePoll        = 5 * time.Minute
+	maxHousePoll        = 24 * time.Hour
+	minSkillPoll        = 15 * time.Minute
+	maxSkillPoll        = 24 * time.Hour
+	minNotificationAge  = 10 * time.Minute
+	maxNotificationAge  = 7 * 24 * time.Hour
+	maxCharacterTTL     = time.Hour
+	minGuildCacheTTL    = time.Minute
+	maxGuildCacheTTL    = 24 * time.Hour
+	maxRemovalGrace     = 90 * 24 * time.Hour
+	minHistoryRetention = 24 * time.Hour
+	maxDBConns          = 200
+	minConnLifetime     = time.Minute
+	minHTTPTimeout      = time.Second
+	maxHTTPTimeout      = 5 * time.Minute
+	maxHTTPIdleConns    = 1000
+	minCycleTimeout     = 10 * time.Second
+	maxFreeMinPoll      = 24 * time.Hour
 )
 
 func (c *Config) Validate() error {
@@ -25,6 +49,57 @@ func (c *Config) Validate() error {
 	if err := c.validateTrackerInterval(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.validateWorldPollIntervals(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateServerSaveQuietWindow(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateLevelUpCooldown(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateHousePollInterval(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateSkillPollInterval(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateNotificationMaxAge(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateCharacterCache(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateGuildCacheTTL(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateGuildRemovalGrace(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validatePlayerHistoryRetention(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateLevelSources(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateStorageDriver(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateDBPool(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateTibiaEndpoints(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateHTTPClients(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateDeadlines(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validateReplay(); err != nil {
+		errs = append(errs, err)
+	}
 	if err := c.validateMinLevelTrack(); err != nil {
 		errs = append(errs, err)
 	}
@@ -34,6 +109,12 @@ func (c *Config) Validate() error {
 	if err := c.validateChannelNames(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.validateLimits(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.validatePremium(); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("configuration validation failed:\n  %w", errors.Join(errs...))
@@ -61,6 +142,221 @@ func (c *Config) validateTrackerInterval() error {
 	return nil
 }
 
+func (c *Config) validateWorldPollIntervals() error {
+	var errs []error
+	for world, interval := range c.WorldPollIntervals {
+		if interval < minTrackerInterval || interval > maxTrackerInterval {
+			errs = append(errs, fmt.Errorf("WORLD_POLL_INTERVALS for %s must be between %v and %v, got %v", world, minTrackerInterval, maxTrackerInterval, interval))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) validateServerSaveQuietWindow() error {
+	if c.ServerSaveQuietWindow < 0 || c.ServerSaveQuietWindow > maxQuietWindow {
+		return fmt.Errorf("SERVER_SAVE_QUIET_WINDOW must be between 0 and %v, got %v", maxQuietWindow, c.ServerSaveQuietWindow)
+	}
+	return nil
+}
+
+func (c *Config) validateLevelUpCooldown() error {
+	if c.LevelUpCooldown < 0 || c.LevelUpCooldown > maxLevelUpCooldown {
+		return fmt.Errorf("LEVEL_UP_COOLDOWN must be between 0 and %v, got %v", maxLevelUpCooldown, c.LevelUpCooldown)
+	}
+	return nil
+}
+
+func (c *Config) validateHousePollInterval() error {
+	if c.HousePollInterval < minHousePoll || c.HousePollInterval > maxHousePoll {
+		return fmt.Errorf("HOUSE_POLL_INTERVAL must be between %v and %v, got %v", minHousePoll, maxHousePoll, c.HousePollInterval)
+	}
+	return nil
+}
+
+func (c *Config) validateSkillPollInterval() error {
+	if c.SkillPollInterval < minSkillPoll || c.SkillPollInterval > maxSkillPoll {
+		return fmt.Errorf("SKILL_POLL_INTERVAL must be between %v and %v, got %v", minSkillPoll, maxSkillPoll, c.SkillPollInterval)
+	}
+	return nil
+}
+
+func (c *Config) validateNotificationMaxAge() error {
+	if c.NotificationMaxAge < minNotificationAge || c.NotificationMaxAge > maxNotificationAge {
+		return fmt.Errorf("NOTIFICATION_MAX_AGE must be between %v and %v, got %v", minNotificationAge, maxNotificationAge, c.NotificationMaxAge)
+	}
+	return nil
+}
+
+func (c *Config) validateCharacterCache() error {
+	if c.CharacterCacheTTL < 0 || c.CharacterCacheTTL > maxCharacterTTL {
+		return fmt.Errorf("CHARACTER_CACHE_TTL must be between 0 and %v, got %v", maxCharacterTTL, c.CharacterCacheTTL)
+	}
+	if c.CharacterCacheTTL > 0 && c.CharacterCacheSize < 1 {
+		return fmt.Errorf("CHARACTER_CACHE_SIZE must be at least 1 when caching is enabled, got %d", c.CharacterCacheSize)
+	}
+	return nil
+}
+
+func (c *Config) validateGuildCacheTTL() error {
+	if c.GuildCacheTTL < minGuildCacheTTL || c.GuildCacheTTL > maxGuildCacheTTL {
+		return fmt.Errorf("GUILD_CACHE_TTL must be between %v and %v, got %v", minGuildCacheTTL, maxGuildCacheTTL, c.GuildCacheTTL)
+	}
+	return nil
+}
+
+func (c *Config) validateGuildRemovalGrace() error {
+	if c.GuildRemovalGrace < 0 || c.GuildRemovalGrace > maxRemovalGrace {
+		return fmt.Errorf("GUILD_REMOVAL_GRACE must be between 0 and %v, got %v", maxRemovalGrace, c.GuildRemovalGrace)
+	}
+	return nil
+}
+
+// validatePlayerHistoryRetention allows 0 to keep history forever; otherwise
+// at least a day so streak and stats windows still have data.
+func (c *Config) validatePlayerHistoryRetention() error {
+	if c.PlayerHistoryRetention != 0 && c.PlayerHistoryRetention < minHistoryRetention {
+		return fmt.Errorf("PLAYER_HISTORY_RETENTION must be 0 or at least %v, got %v", minHistoryRetention, c.PlayerHistoryRetention)
+	}
+	return nil
+}
+
+func (c *Config) validateLevelSources() error {
+	if len(c.LevelSources) == 0 {
+		return fmt.Errorf("LEVEL_SOURCES must name at least one source")
+	}
+	seen := make(map[string]bool, len(c.LevelSources))
+	for _, source := range c.LevelSources {
+		switch source {
+		case LevelSourceTibiaCom, LevelSourceTibiaData:
+		default:
+			return fmt.Errorf("LEVEL_SOURCES entries must be %q or %q, got %q", LevelSourceTibiaCom, LevelSourceTibiaData, source)
+		}
+		if seen[source] {
+			return fmt.Errorf("LEVEL_SOURCES lists %q twice", source)
+		}
+		seen[source] = true
+	}
+	return nil
+}
+
+func (c *Config) validateStorageDriver() error {
+	switch c.StorageDriver {
+	case StorageDriverPostgres, StorageDriverMemory:
+		return nil
+	}
+	return fmt.Errorf("STORAGE_DRIVER must be %q or %q, got %q", StorageDriverPostgres, StorageDriverMemory, c.StorageDriver)
+}
+
+func (c *Config) validateDBPool() error {
+	var errs []error
+	if c.DBMaxConns < 1 || c.DBMaxConns > maxDBConns {
+		errs = append(errs, fmt.Errorf("DB_MAX_CONNS must be between 1 and %d, got %d", maxDBConns, c.DBMaxConns))
+	}
+	if c.DBMinConns < 0 || c.DBMinConns > c.DBMaxConns {
+		errs = append(errs, fmt.Errorf("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS (%d), got %d", c.DBMaxConns, c.DBMinConns))
+	}
+	if c.DBMaxConnLifetime < minConnLifetime {
+		errs = append(errs, fmt.Errorf("DB_MAX_CONN_LIFETIME must be at least %v, got %v", minConnLifetime, c.DBMaxConnLifetime))
+	}
+	if c.DBSlowQueryThreshold < 0 {
+		errs = append(errs, fmt.Errorf("DB_SLOW_QUERY_THRESHOLD cannot be negative, got %v", c.DBSlowQueryThreshold))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) validateTibiaEndpoints() error {
+	var errs []error
+	if err := validateBaseURL("TIBIADATA_BASE_URL", c.TibiaDataBaseURL); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateBaseURL("TIBIACOM_BASE_URL", c.TibiaComBaseURL); err != nil {
+		errs = append(errs, err)
+	}
+	if c.TibiaDataAuthToken != "" && (c.TibiaDataAuthHeader == "" || strings.ContainsAny(c.TibiaDataAuthHeader, ": \t")) {
+		errs = append(errs, fmt.Errorf("TIBIADATA_AUTH_HEADER must be a valid header name, got %q", c.TibiaDataAuthHeader))
+	}
+	return errors.Join(errs...)
+}
+
+// validateBaseURL accepts an empty value (use the public endpoint) or an
+// absolute http(s) URL.
+func validateBaseURL(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http(s) URL, got %q", name, value)
+	}
+	return nil
+}
+
+func (c *Config) validateDeadlines() error {
+	if c.CharacterFetchTimeout < minHTTPTimeout || c.CharacterFetchTimeout > maxHTTPTimeout {
+		return fmt.Errorf("CHARACTER_FETCH_TIMEOUT must be between %v and %v, got %v", minHTTPTimeout, maxHTTPTimeout, c.CharacterFetchTimeout)
+	}
+	if c.WorldCycleTimeout < max(minCycleTimeout, c.CharacterFetchTimeout) || c.WorldCycleTimeout > maxTrackerInterval {
+		return fmt.Errorf("WORLD_CYCLE_TIMEOUT must be between %v and %v, got %v", max(minCycleTimeout, c.CharacterFetchTimeout), maxTrackerInterval, c.WorldCycleTimeout)
+	}
+	return nil
+}
+
+func (c *Config) validateHTTPClients() error {
+	var errs []error
+	for name, timeout := range map[string]time.Duration{"TIBIADATA_TIMEOUT": c.TibiaDataTimeout, "TIBIACOM_TIMEOUT": c.TibiaComTimeout} {
+		if timeout < minHTTPTimeout || timeout > maxHTTPTimeout {
+			errs = append(errs, fmt.Errorf("%s must be between %v and %v, got %v", name, minHTTPTimeout, maxHTTPTimeout, timeout))
+		}
+	}
+	if err := validateProxyURL("TIBIADATA_PROXY_URL", c.TibiaDataProxyURL); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateProxyURL("TIBIACOM_PROXY_URL", c.TibiaComProxyURL); err != nil {
+		errs = append(errs, err)
+	}
+	if c.HTTPMaxIdleConns < 1 || c.HTTPMaxIdleConns > maxHTTPIdleConns {
+		errs = append(errs, fmt.Errorf("HTTP_MAX_IDLE_CONNS must be between 1 and %d, got %d", maxHTTPIdleConns, c.HTTPMaxIdleConns))
+	}
+	if c.HTTPCAFile != "" {
+		pem, err := os.ReadFile(c.HTTPCAFile)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("HTTP_CA_FILE: %w", err))
+		} else if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			errs = append(errs, fmt.Errorf("HTTP_CA_FILE %s contains no PEM certificates", c.HTTPCAFile))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateProxyURL accepts an empty value (honor HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY) or an http(s) or socks5 proxy URL.
+func (c *Config) validateReplay() error {
+	switch c.ReplayMode {
+	case "":
+		if c.ReplayDir != "" {
+			return fmt.Errorf("REPLAY_DIR requires REPLAY_MODE to be %q or %q", ReplayModeRecord, ReplayModeReplay)
+		}
+		return nil
+	case ReplayModeRecord, ReplayModeReplay:
+		if c.ReplayDir == "" {
+			return fmt.Errorf("REPLAY_MODE %q requires REPLAY_DIR", c.ReplayMode)
+		}
+		return nil
+	}
+	return fmt.Errorf("REPLAY_MODE must be %q or %q, got %q", ReplayModeRecord, ReplayModeReplay, c.ReplayMode)
+}
+
+func validateProxyURL(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+		return fmt.Errorf("%s must be an http(s) or socks5 URL, got %q", name, value)
+	}
+	return nil
+}
+
 func (c *Config) validateMinLevelTrack() error {
 	if c.MinLevelTrack < minLevelTrack {
 		return fmt.Errorf("MIN_LEVEL_TRACK must be at least %d, got %d", minLevelTrack, c.MinLevelTrack)
@@ -78,6 +374,36 @@ func (c *Config) validateWorkerPoolSize() error {
 	return nil
 }
 
+// validateLimits allows 0 for no limit.
+func (c *Config) validateLimits() error {
+	var errs []error
+	for name, limit := range map[string]int{
+		"MAX_TIBIA_GUILDS":         c.MaxTibiaGuilds,
+		"MAX_IGNORED_PLAYERS":      c.MaxIgnoredPlayers,
+		"MAX_WATCHED_PLAYERS":      c.MaxWatchedPlayers,
+		"MAX_WORLDS":               c.MaxWorlds,
+		"PREMIUM_MAX_TIBIA_GUILDS": c.PremiumMaxTibiaGuilds,
+	} {
+		if limit < 0 {
+			errs = append(errs, fmt.Errorf("%s cannot be negative, got %d", name, limit))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) validatePremium() error {
+	var errs []error
+	if c.FreeMinPollInterval < 0 || c.FreeMinPollInterval > maxFreeMinPoll {
+		errs = append(errs, fmt.Errorf("FREE_MIN_POLL_INTERVAL must be between 0 and %v, got %v", maxFreeMinPoll, c.FreeMinPollInterval))
+	}
+	for _, id := range c.PremiumGuilds {
+		if strings.Trim(id, "0123456789") != "" {
+			errs = append(errs, fmt.Errorf("PREMIUM_GUILDS must list Discord guild IDs, got %q", id))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *Config) validateChannelNames() error {
 	var errs []error
 
@@ -87,6 +413,9 @@ func (c *Config) validateChannelNames() error {
 	if err := validateChannel("DISCORD_CHANNEL_LEVEL", c.DiscordChannelLevel); err != nil {
 		errs = append(errs, err)
 	}
+	if err := validateChannel("DISCORD_CHANNEL_AUDIT", c.DiscordChannelAudit); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -8,12 +10,29 @@ import (
 
 func validConfig() *Config {
 	return &Config{
-		Token:               strings.Repeat("x", 50),
-		TrackerInterval:     5 * time.Minute,
-		MinLevelTrack:       500,
-		WorkerPoolSize:      10,
-		DiscordChannelDeath: "death-tracker",
-		DiscordChannelLevel: "level-tracker",
+		Token:                  strings.Repeat("x", 50),
+		TrackerInterval:        5 * time.Minute,
+		MinLevelTrack:          500,
+		WorkerPoolSize:         10,
+		DiscordChannelDeath:    "death-tracker",
+		DiscordChannelLevel:    "level-tracker",
+		DiscordChannelAudit:    "tracker-audit",
+		NotificationMaxAge:     24 * time.Hour,
+		LevelUpCooldown:        5 * time.Minute,
+		HousePollInterval:      30 * time.Minute,
+		SkillPollInterval:      time.Hour,
+		TibiaDataTimeout:       10 * time.Second,
+		TibiaComTimeout:        30 * time.Second,
+		CharacterFetchTimeout:  5 * time.Second,
+		WorldCycleTimeout:      2 * time.Minute,
+		HTTPMaxIdleConns:       100,
+		StorageDriver:          StorageDriverPostgres,
+		LevelSources:           []string{LevelSourceTibiaCom, LevelSourceTibiaData},
+		DBMaxConns:             10,
+		DBMaxConnLifetime:      time.Hour,
+		GuildCacheTTL:          15 * time.Minute,
+		GuildRemovalGrace:      7 * 24 * time.Hour,
+		PlayerHistoryRetention: 90 * 24 * time.Hour,
 	}
 }
 
@@ -72,6 +91,451 @@ func TestValidate_TrackerInterval(t *testing.T) {
 	}
 }
 
+func TestValidate_WorldPollIntervals(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"min valid", time.Minute, false},
+		{"below min", 30 * time.Second, true},
+		{"above max", 25 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.WorldPollIntervals = map[string]time.Duration{"Antica": tt.interval}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WorldPollIntervals=%v: error=%v, wantErr=%v", tt.interval, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ServerSaveQuietWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  time.Duration
+		wantErr bool
+	}{
+		{"disabled", 0, false},
+		{"normal", 15 * time.Minute, false},
+		{"negative", -time.Minute, true},
+		{"above max", 3 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.ServerSaveQuietWindow = tt.window
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ServerSaveQuietWindow=%v: error=%v, wantErr=%v", tt.window, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_LevelUpCooldown(t *testing.T) {
+	tests := []struct {
+		name     string
+		cooldown time.Duration
+		wantErr  bool
+	}{
+		{"disabled", 0, false},
+		{"normal", 5 * time.Minute, false},
+		{"max", time.Hour, false},
+		{"negative", -time.Minute, true},
+		{"above max", 2 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.LevelUpCooldown = tt.cooldown
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LevelUpCooldown=%v: error=%v, wantErr=%v", tt.cooldown, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_HousePollInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"min", 5 * time.Minute, false},
+		{"default", 30 * time.Minute, false},
+		{"max", 24 * time.Hour, false},
+		{"below min", time.Minute, true},
+		{"above max", 25 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.HousePollInterval = tt.interval
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HousePollInterval=%v: error=%v, wantErr=%v", tt.interval, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SkillPollInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"min", 15 * time.Minute, false},
+		{"default", time.Hour, false},
+		{"max", 24 * time.Hour, false},
+		{"below min", 5 * time.Minute, true},
+		{"above max", 25 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.SkillPollInterval = tt.interval
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SkillPollInterval=%v: error=%v, wantErr=%v", tt.interval, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_NotificationMaxAge(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		wantErr bool
+	}{
+		{"min valid", 10 * time.Minute, false},
+		{"normal", 24 * time.Hour, false},
+		{"max valid", 7 * 24 * time.Hour, false},
+		{"below min", time.Minute, true},
+		{"above max", 8 * 24 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.NotificationMaxAge = tt.maxAge
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NotificationMaxAge=%v: error=%v, wantErr=%v", tt.maxAge, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CharacterCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		size    int
+		wantErr bool
+	}{
+		{"disabled", 0, 0, false},
+		{"normal", 10 * time.Minute, 5000, false},
+		{"max ttl", time.Hour, 1, false},
+		{"negative ttl", -time.Minute, 5000, true},
+		{"ttl above max", 2 * time.Hour, 5000, true},
+		{"enabled without size", 10 * time.Minute, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.CharacterCacheTTL = tt.ttl
+			cfg.CharacterCacheSize = tt.size
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CharacterCacheTTL=%v, CharacterCacheSize=%d: error=%v, wantErr=%v", tt.ttl, tt.size, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_TibiaEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		header  string
+		token   string
+		wantErr bool
+	}{
+		{"defaults", "", "Authorization", "", false},
+		{"self-hosted", "http://tibiadata:8080/v4", "X-Api-Key", "secret", false},
+		{"relative url", "tibiadata/v4", "Authorization", "", true},
+		{"unsupported scheme", "ftp://tibiadata/v4", "Authorization", "", true},
+		{"empty header with token", "", "", "secret", true},
+		{"invalid header with token", "", "X Api: Key", "secret", true},
+		{"invalid header without token", "", "X Api: Key", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.TibiaDataBaseURL = tt.baseURL
+			cfg.TibiaDataAuthHeader = tt.header
+			cfg.TibiaDataAuthToken = tt.token
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TibiaDataBaseURL=%q, header=%q: error=%v, wantErr=%v", tt.baseURL, tt.header, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Deadlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{"defaults", func(c *Config) {}, false},
+		{"character timeout too short", func(c *Config) { c.CharacterFetchTimeout = 0 }, true},
+		{"character timeout too long", func(c *Config) { c.CharacterFetchTimeout = 10 * time.Minute }, true},
+		{"cycle timeout too short", func(c *Config) { c.WorldCycleTimeout = 5 * time.Second }, true},
+		{"cycle timeout below character timeout", func(c *Config) {
+			c.CharacterFetchTimeout = time.Minute
+			c.WorldCycleTimeout = 30 * time.Second
+		}, true},
+		{"cycle timeout too long", func(c *Config) { c.WorldCycleTimeout = 48 * time.Hour }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error=%v, wantErr=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_HTTPClients(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{"defaults", func(c *Config) {}, false},
+		{"http proxy", func(c *Config) { c.TibiaComProxyURL = "http://proxy:3128" }, false},
+		{"socks proxy", func(c *Config) { c.TibiaDataProxyURL = "socks5://proxy:1080" }, false},
+		{"proxy without host", func(c *Config) { c.TibiaComProxyURL = "proxy:3128" }, true},
+		{"timeout too short", func(c *Config) { c.TibiaDataTimeout = 100 * time.Millisecond }, true},
+		{"timeout too long", func(c *Config) { c.TibiaComTimeout = 10 * time.Minute }, true},
+		{"no idle conns", func(c *Config) { c.HTTPMaxIdleConns = 0 }, true},
+		{"missing CA file", func(c *Config) { c.HTTPCAFile = filepath.Join(dir, "missing.pem") }, true},
+		{"CA file without certificates", func(c *Config) { c.HTTPCAFile = notPEM }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error=%v, wantErr=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_GuildCacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{"default", 15 * time.Minute, false},
+		{"min", time.Minute, false},
+		{"max", 24 * time.Hour, false},
+		{"zero", 0, true},
+		{"too short", 30 * time.Second, true},
+		{"too long", 25 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.GuildCacheTTL = tt.ttl
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GuildCacheTTL=%v: error=%v, wantErr=%v", tt.ttl, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_GuildRemovalGrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		grace   time.Duration
+		wantErr bool
+	}{
+		{"default", 7 * 24 * time.Hour, false},
+		{"zero", 0, false},
+		{"max", 90 * 24 * time.Hour, false},
+		{"negative", -time.Hour, true},
+		{"too long", 91 * 24 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.GuildRemovalGrace = tt.grace
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GuildRemovalGrace=%v: error=%v, wantErr=%v", tt.grace, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_StorageDriver(t *testing.T) {
+	tests := []struct {
+		driver  string
+		wantErr bool
+	}{
+		{"postgres", false},
+		{"memory", false},
+		{"", true},
+		{"sqlite", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.StorageDriver = tt.driver
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StorageDriver=%q: error=%v, wantErr=%v", tt.driver, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_LevelSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		wantErr bool
+	}{
+		{"both", []string{"tibiacom", "tibiadata"}, false},
+		{"single", []string{"tibiadata"}, false},
+		{"empty", nil, true},
+		{"unknown", []string{"tibiacom", "guildstats"}, true},
+		{"duplicate", []string{"tibiadata", "tibiadata"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.LevelSources = tt.sources
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LevelSources=%v: error=%v, wantErr=%v", tt.sources, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Replay(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		dir     string
+		wantErr bool
+	}{
+		{"off", "", "", false},
+		{"record", "record", "/var/replay", false},
+		{"replay", "replay", "/var/replay", false},
+		{"mode without dir", "record", "", true},
+		{"dir without mode", "", "/var/replay", true},
+		{"unknown mode", "capture", "/var/replay", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.ReplayMode = tt.mode
+			cfg.ReplayDir = tt.dir
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReplayMode=%q ReplayDir=%q: error=%v, wantErr=%v", tt.mode, tt.dir, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DBPool(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxConns int
+		minConns int
+		lifetime time.Duration
+		wantErr  bool
+	}{
+		{"defaults", 10, 0, time.Hour, false},
+		{"min equals max", 20, 20, 30 * time.Minute, false},
+		{"zero max", 0, 0, time.Hour, true},
+		{"max above limit", 500, 0, time.Hour, true},
+		{"min above max", 5, 6, time.Hour, true},
+		{"negative min", 5, -1, time.Hour, true},
+		{"short lifetime", 10, 0, 10 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.DBMaxConns = tt.maxConns
+			cfg.DBMinConns = tt.minConns
+			cfg.DBMaxConnLifetime = tt.lifetime
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DBMaxConns=%d, DBMinConns=%d, DBMaxConnLifetime=%v: error=%v, wantErr=%v", tt.maxConns, tt.minConns, tt.lifetime, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_DBSlowQueryThreshold(t *testing.T) {
+	for _, tt := range []struct {
+		threshold time.Duration
+		wantErr   bool
+	}{
+		{0, false},
+		{500 * time.Millisecond, false},
+		{-time.Second, true},
+	} {
+		cfg := validConfig()
+		cfg.DBSlowQueryThreshold = tt.threshold
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("DBSlowQueryThreshold=%v: error=%v, wantErr=%v", tt.threshold, err, tt.wantErr)
+		}
+	}
+}
+
 func TestValidate_MinLevelTrack(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -152,6 +616,14 @@ func TestValidate_ChannelNames(t *testing.T) {
 	}
 }
 
+func TestValidate_AuditChannelName(t *testing.T) {
+	cfg := validConfig()
+	cfg.DiscordChannelAudit = strings.Repeat("x", 101)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for audit channel name over 100 characters")
+	}
+}
+
 func TestValidate_MultipleErrors(t *testing.T) {
 	cfg := &Config{
 		Token:               "",
@@ -183,3 +655,62 @@ func TestValidate_MultipleErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_PlayerHistoryRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		wantErr   bool
+	}{
+		{"default", 90 * 24 * time.Hour, false},
+		{"disabled", 0, false},
+		{"one day", 24 * time.Hour, false},
+		{"too short", time.Hour, true},
+		{"negative", -24 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.PlayerHistoryRetention = tt.retention
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlayerHistoryRetention=%v: error=%v, wantErr=%v", tt.retention, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Limits(t *testing.T) {
+	cfg := validConfig()
+	cfg.MaxTibiaGuilds, cfg.MaxIgnoredPlayers, cfg.MaxWorlds = 5, 0, 20
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.MaxIgnoredPlayers = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "MAX_IGNORED_PLAYERS") {
+		t.Errorf("expected a MAX_IGNORED_PLAYERS error, got %v", err)
+	}
+}
+
+func TestValidate_Premium(t *testing.T) {
+	cfg := validConfig()
+	cfg.PremiumGating = true
+	cfg.PremiumGuilds = []string{"123456789012345678"}
+	cfg.FreeMinPollInterval = 15 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.PremiumGuilds = []string{"my-server"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "PREMIUM_GUILDS") {
+		t.Errorf("expected a PREMIUM_GUILDS error, got %v", err)
+	}
+
+	cfg.PremiumGuilds = nil
+	cfg.FreeMinPollInterval = -time.Minute
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "FREE_MIN_POLL_INTERVAL") {
+		t.Errorf("expected a FREE_MIN_POLL_INTERVAL error, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// ActivityWindow is the trailing period /activity covers. Online presence
+// older than that is deleted.
+const ActivityWindow = 14 * 24 * time.Hour
+
+// OnlineSampleRetention is how long the online count of every cycle is kept
+// before it is averaged into an hourly count.
+const OnlineSampleRetention = 2 * 24 * time.Hour
+
+// OnlineCount is how many characters were seen online during the hour
+// starting at Hour.
+type OnlineCount struct {
+	Hour    time.Time
+	Players int
+}
+
+// ActivityHeatmap is the average number of characters online by weekday and
+// hour of day in a guild's timezone.
+type ActivityHeatmap struct {
+	// Online is indexed by time.Weekday, then hour of day.
+	Online [7][24]float64
+	// World is laid out like Online but counts every character on the world.
+	World  [7][24]float64
+	Window time.Duration
+}
+
+// NewActivityHeatmap averages hourly online counts of the guild's characters
+// and of the whole world over window by the weekday and hour they fall on in
+// loc.
+func NewActivityHeatmap(counts, world []OnlineCount, window time.Duration, loc *time.Location) ActivityHeatmap {
+	h := ActivityHeatmap{Window: window}
+	weeks := window.Hours() / (7 * 24)
+	if weeks <= 0 {
+		return h
+	}
+	addCounts(&h.Online, counts, weeks, loc)
+	addCounts(&h.World, world, weeks, loc)
+	return h
+}
+
+func addCounts(grid *[7][24]float64, counts []OnlineCount, weeks float64, loc *time.Location) {
+	for _, c := range counts {
+		t := c.Hour.In(loc)
+		grid[t.Weekday()][t.Hour()] += float64(c.Players) / weeks
+	}
+}
+
+// Peak returns the weekday and hour with the most characters online on
+// average, and that average. An empty heatmap peaks at zero.
+func (h ActivityHeatmap) Peak() (time.Weekday, int, float64) {
+	return peakOf(h.Online)
+}
+
+// WorldPeak is Peak for every character on the world.
+func (h ActivityHeatmap) WorldPeak() (time.Weekday, int, float64) {
+	return peakOf(h.World)
+}
+
+func peakOf(grid [7][24]float64) (time.Weekday, int, float64) {
+	var day time.Weekday
+	var hour int
+	var peak float64
+	for d := range grid {
+		for hr, online := range grid[d] {
+			if online > peak {
+				day, hour, peak = time.Weekday(d), hr, online
+			}
+		}
+	}
+	return day, hour, peak
+}
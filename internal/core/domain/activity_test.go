@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewActivityHeatmap(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	// Sunday 2026-03-01 19:00 UTC is 20:00 in Warsaw.
+	sunday := time.Date(2026, 3, 1, 19, 0, 0, 0, time.UTC)
+	counts := []OnlineCount{
+		{Hour: sunday, Players: 10},
+		{Hour: sunday.AddDate(0, 0, -7), Players: 6},
+		{Hour: sunday.Add(time.Hour), Players: 3},
+	}
+
+	world := []OnlineCount{
+		{Hour: sunday.Add(-time.Hour), Players: 400},
+		{Hour: sunday, Players: 300},
+	}
+
+	h := NewActivityHeatmap(counts, world, 14*24*time.Hour, warsaw)
+
+	if got := h.Online[time.Sunday][20]; got != 8 {
+		t.Errorf("expected Sunday 20:00 to average 8 over two weeks, got %v", got)
+	}
+	if got := h.Online[time.Sunday][21]; got != 1.5 {
+		t.Errorf("expected Sunday 21:00 to average 1.5, got %v", got)
+	}
+	if day, hour, peak := h.Peak(); day != time.Sunday || hour != 20 || peak != 8 {
+		t.Errorf("expected the peak on Sunday 20:00 at 8, got %v %d:00 at %v", day, hour, peak)
+	}
+	if day, hour, peak := h.WorldPeak(); day != time.Sunday || hour != 19 || peak != 200 {
+		t.Errorf("expected the world peak on Sunday 19:00 at 200, got %v %d:00 at %v", day, hour, peak)
+	}
+}
+
+func TestActivityHeatmap_EmptyPeak(t *testing.T) {
+	h := NewActivityHeatmap(nil, nil, ActivityWindow, time.UTC)
+	if _, _, peak := h.Peak(); peak != 0 {
+		t.Errorf("expected no peak, got %v", peak)
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+// BoostedCreature is a creature or boss with its picture on tibia.com.
+type BoostedCreature struct {
+	Name     string
+	ImageURL string
+}
+
+// Boosted is today's boosted creature and boss. Both give more experience or
+// loot until the next server save.
+type Boosted struct {
+	Creature BoostedCreature
+	Boss     BoostedCreature
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// deathLocations maps notable creatures and bosses to the hunting ground or
+// quest they are only met in, keyed by creatureKey. Creatures found all over
+// the map, such as dragons, are left out on purpose: a guess is only worth
+// showing when it is almost certainly right. Keep the entries grouped by
+// location and the locations in alphabetical order.
+var deathLocations = map[string]string{
+	// Cobra Bastion
+	"cobra assassin": "Cobra Bastion",
+	"cobra scout":    "Cobra Bastion",
+	"cobra vizier":   "Cobra Bastion",
+	"scarlett etzel": "Cobra Bastion",
+
+	// Falcon Bastion
+	"falcon knight":       "Falcon Bastion",
+	"falcon paladin":      "Falcon Bastion",
+	"grand master oberon": "Falcon Bastion",
+
+	// Feaster of Souls
+	"dread maiden": "Feaster of Souls",
+	"fear feaster": "Feaster of Souls",
+	"pale worm":    "Feaster of Souls",
+	"unwelcome":    "Feaster of Souls",
+
+	// Gnomprona
+	"gorerilla":         "Gnomprona",
+	"headpecker":        "Gnomprona",
+	"hulking prehemoth": "Gnomprona",
+	"mantosaurus":       "Gnomprona",
+	"nighthunter":       "Gnomprona",
+	"sulphider":         "Gnomprona",
+
+	// Grave Danger
+	"count vlarkorth": "Grave Danger",
+	"duke krule":      "Grave Danger",
+	"earl osam":       "Grave Danger",
+	"king zelos":      "Grave Danger",
+	"lord azaram":     "Grave Danger",
+	"sir baeloc":      "Grave Danger",
+
+	// Ingol
+	"boar man":      "Ingol",
+	"carnivostrich": "Ingol",
+	"crape man":     "Ingol",
+	"liodile":       "Ingol",
+
+	// Kilmaresh
+	"black sphinx acolyte":      "Kilmaresh",
+	"burning gladiator":         "Kilmaresh",
+	"priestess of the wild sun": "Kilmaresh",
+
+	// Roshamuul
+	"choking fear":       "Roshamuul",
+	"frazzlemaw":         "Roshamuul",
+	"guzzlemaw":          "Roshamuul",
+	"retching horror":    "Roshamuul",
+	"sight of surrender": "Roshamuul",
+	"silencer":           "Roshamuul",
+
+	// Rotten Blood
+	"bakragore":           "Rotten Blood",
+	"chagorz":             "Rotten Blood",
+	"darklight construct": "Rotten Blood",
+	"darklight emitter":   "Rotten Blood",
+	"darklight matter":    "Rotten Blood",
+	"darklight source":    "Rotten Blood",
+	"darklight striker":   "Rotten Blood",
+	"ichgahal":            "Rotten Blood",
+	"murcion":             "Rotten Blood",
+	"oozing carcass":      "Rotten Blood",
+	"oozing corpus":       "Rotten Blood",
+	"sopping carcass":     "Rotten Blood",
+	"sopping corpus":      "Rotten Blood",
+	"vemiath":             "Rotten Blood",
+
+	// Secret Library
+	"brain squid":       "the Secret Library",
+	"burning book":      "the Secret Library",
+	"cursed book":       "the Secret Library",
+	"energetic book":    "the Secret Library",
+	"flying book":       "the Secret Library",
+	"guardian of tales": "the Secret Library",
+	"icecold book":      "the Secret Library",
+
+	// Soul War
+	"branchy crawler":       "Soul War",
+	"capricious phantom":    "Soul War",
+	"cloak of terror":       "Soul War",
+	"courage leech":         "Soul War",
+	"goshnar's cruelty":     "Soul War",
+	"goshnar's greed":       "Soul War",
+	"goshnar's hatred":      "Soul War",
+	"goshnar's malice":      "Soul War",
+	"goshnar's megalomania": "Soul War",
+	"goshnar's spite":       "Soul War",
+	"hazardous phantom":     "Soul War",
+	"infernal phantom":      "Soul War",
+	"many faces":            "Soul War",
+	"mould phantom":         "Soul War",
+	"rotten golem":          "Soul War",
+	"turbulent elemental":   "Soul War",
+	"vibrant phantom":       "Soul War",
+
+	// Warzones
+	"abyssador":   "the Warzones",
+	"deathstrike": "the Warzones",
+	"gnomevil":    "the Warzones",
+}
+
+// creatureKey is the NameKey of a creature name without the article death
+// lists put in front of it, so "a Cloak of Terror" and "The Dread Maiden"
+// find their entries.
+func creatureKey(name string) string {
+	key := NameKey(name)
+	for _, article := range []string{"a ", "an ", "the "} {
+		if rest, ok := strings.CutPrefix(key, article); ok {
+			return rest
+		}
+	}
+	return key
+}
+
+// LikelyDeathLocation guesses where a character died from the creatures that
+// killed it: the location of the first killer, then assist, found in
+// deathLocations. Kills without a killer list, such as those read from
+// tibia.com, are guessed from the names in the death reason. It reports false
+// when no creature gives the location away.
+func LikelyDeathLocation(kill Kill) (string, bool) {
+	names := killerNames(kill)
+	if len(kill.Killers) == 0 && len(kill.Assists) == 0 {
+		names = reasonKillers(kill.Reason)
+	}
+	for _, name := range names {
+		if location, ok := deathLocations[creatureKey(name)]; ok {
+			return location, true
+		}
+	}
+	return "", false
+}
+
+// killerNames lists the creatures among the killers and assists; players and
+// their summons say nothing about the place.
+func killerNames(kill Kill) []string {
+	var names []string
+	for _, k := range append(slices.Clone(kill.Killers), kill.Assists...) {
+		if !k.IsPlayer && !k.IsSummon {
+			names = append(names, k.Name)
+		}
+	}
+	return names
+}
+
+// reasonKillers splits the killers, then the assists, out of a reason such
+// as "Killed at Level 500 by a dragon lord, a demon and Bubble. Assisted by a
+// frazzlemaw."
+func reasonKillers(reason string) []string {
+	_, killers, ok := strings.Cut(reason, " by ")
+	if !ok {
+		return nil
+	}
+	killers = strings.TrimSuffix(strings.TrimSpace(killers), ".")
+	killers, assists, _ := strings.Cut(killers, ". Assisted by ")
+	var names []string
+	for _, list := range []string{killers, assists} {
+		for _, part := range strings.Split(list, ", ") {
+			names = append(names, strings.Split(part, " and ")...)
+		}
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "testing"
+
+func TestLikelyDeathLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		kill     Kill
+		location string
+	}{
+		{
+			"Boss",
+			Kill{Killers: []Killer{{Name: "Goshnar's Megalomania"}}},
+			"Soul War",
+		},
+		{
+			"Creature with article",
+			Kill{Killers: []Killer{{Name: "a cloak of terror"}}},
+			"Soul War",
+		},
+		{
+			"Boss named with the",
+			Kill{Killers: []Killer{{Name: "The Dread Maiden"}}},
+			"Feaster of Souls",
+		},
+		{
+			"First known killer wins",
+			Kill{Killers: []Killer{{Name: "a dragon lord"}, {Name: "a guzzlemaw"}, {Name: "a falcon knight"}}},
+			"Roshamuul",
+		},
+		{
+			"Assist",
+			Kill{Killers: []Killer{{Name: "a dragon"}}, Assists: []Killer{{Name: "a brain squid"}}},
+			"the Secret Library",
+		},
+		{
+			"Players and summons are ignored",
+			Kill{
+				Reason:  "Killed at Level 300 by Silencer and a guzzlemaw of Enemy Druid.",
+				Killers: []Killer{{Name: "Silencer", IsPlayer: true}, {Name: "Enemy Druid", IsSummon: true}},
+			},
+			"",
+		},
+		{
+			"Creature found everywhere",
+			Kill{Killers: []Killer{{Name: "a dragon lord"}}},
+			"",
+		},
+		{
+			"Reason without killer list",
+			Kill{Reason: "Killed at Level 500 by Bubble, a demon and a vibrant phantom."},
+			"Soul War",
+		},
+		{
+			"Reason with assists",
+			Kill{Reason: "Died at Level 500 by a dragon. Assisted by a frazzlemaw."},
+			"Roshamuul",
+		},
+		{
+			"Reason without killers",
+			Kill{Reason: "Died at Level 20"},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, ok := LikelyDeathLocation(tt.kill)
+			if location != tt.location || ok != (tt.location != "") {
+				t.Errorf("expected %q, got %q (%v)", tt.location, location, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+const (
+	promotionReduction = 0.30
+	blessingReduction  = 0.08
+	maxBlessings       = 5
+)
+
+// DeathPenalty is an estimated experience loss for a death. Promotion and
+// blessings are not public, so the loss is given as a range between a fully
+// protected character (Min) and an unprotected one (Max).
+type DeathPenalty struct {
+	MinExp    int64
+	MaxExp    int64
+	MinLevels int
+	MaxLevels int
+}
+
+// ExperienceForLevel returns the total experience required to reach level.
+func ExperienceForLevel(level int) int64 {
+	if level < 1 {
+		return 0
+	}
+	l := int64(level)
+	return 50 * (l*l*l - 6*l*l + 17*l - 12) / 3
+}
+
+// EstimateDeathPenalty estimates the experience and levels lost by a character
+// dying at level, assuming it had just reached that level.
+func EstimateDeathPenalty(level int) DeathPenalty {
+	if level < 1 {
+		return DeathPenalty{}
+	}
+
+	maxExp := baseDeathLoss(level)
+	protection := promotionReduction + blessingReduction*maxBlessings
+	minExp := int64(float64(maxExp) * (1 - protection))
+
+	return DeathPenalty{
+		MinExp:    minExp,
+		MaxExp:    maxExp,
+		MinLevels: level - levelAfterLoss(level, minExp),
+		MaxLevels: level - levelAfterLoss(level, maxExp),
+	}
+}
+
+func baseDeathLoss(level int) int64 {
+	if level < 24 {
+		return ExperienceForLevel(level) / 10
+	}
+	l := float64(level)
+	return int64((l + 50) / 100 * 50 * (l*l - 5*l + 8))
+}
+
+func levelAfterLoss(level int, loss int64) int {
+	remaining := ExperienceForLevel(level) - loss
+	for level > 1 && ExperienceForLevel(level) > remaining {
+		level--
+	}
+	return level
+}
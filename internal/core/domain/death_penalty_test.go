@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestExperienceForLevel(t *testing.T) {
+	tests := []struct {
+		level    int
+		expected int64
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 100},
+		{8, 4200},
+		{100, 15694800},
+	}
+
+	for _, tt := range tests {
+		if got := ExperienceForLevel(tt.level); got != tt.expected {
+			t.Errorf("level %d: expected %d, got %d", tt.level, tt.expected, got)
+		}
+	}
+}
+
+func TestEstimateDeathPenalty(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		expected DeathPenalty
+	}{
+		{"unknown level", 0, DeathPenalty{}},
+		{"below level 24 loses 10%", 8, DeathPenalty{MinExp: 126, MaxExp: 420, MinLevels: 1, MaxLevels: 1}},
+		{"level 100", 100, DeathPenalty{MinExp: 213930, MaxExp: 713100, MinLevels: 1, MaxLevels: 2}},
+		{"level 500", 500, DeathPenalty{MinExp: 20419410, MaxExp: 68064700, MinLevels: 2, MaxLevels: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateDeathPenalty(tt.level); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEstimateDeathPenalty_ProtectedLosesLess(t *testing.T) {
+	for _, level := range []int{24, 250, 1000} {
+		p := EstimateDeathPenalty(level)
+		if p.MinExp >= p.MaxExp {
+			t.Errorf("level %d: expected MinExp < MaxExp, got %d >= %d", level, p.MinExp, p.MaxExp)
+		}
+		if p.MinLevels > p.MaxLevels {
+			t.Errorf("level %d: expected MinLevels <= MaxLevels, got %d > %d", level, p.MinLevels, p.MaxLevels)
+		}
+	}
+}
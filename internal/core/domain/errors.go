@@ -0,0 +1,27 @@
+package domain
+
+import "errors"
+
+// ErrWorldMaintenance is returned by fetchers when a world's players cannot be
+// read because Tibia is under maintenance or the world is offline.
+var ErrWorldMaintenance = errors.New("world is under maintenance")
+
+// Fetchers wrap their failures in one of these so callers can react without
+// knowing the upstream. Match them with errors.Is.
+var (
+	// ErrNotFound means the character, guild or world does not exist, e.g.
+	// after a rename or deletion. Retrying will not help.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited means the upstream asked us to slow down.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstreamDown means the upstream could not be reached or failed with
+	// a server error. Another source may still work.
+	ErrUpstreamDown = errors.New("upstream unavailable")
+	// ErrParse means the upstream answered with something that could not be
+	// read, e.g. a changed page layout or a non-JSON body.
+	ErrParse = errors.New("unreadable response")
+)
+
+// ErrSourceUnavailable is wrapped alongside the cause when a level source
+// failed in a way the next configured source is unlikely to share.
+var ErrSourceUnavailable = errors.New("level source unavailable")
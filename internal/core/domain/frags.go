@@ -0,0 +1,18 @@
+package domain
+
+// FragCount is how many deaths of others a character took part in as a
+// player killer during a Tibia day, and how often it died to players.
+type FragCount struct {
+	Name   string
+	Frags  int
+	Deaths int
+}
+
+// Ratio is frags per death, the kill/death ratio. Without deaths it is the
+// number of frags, as is usual on war servers.
+func (f FragCount) Ratio() float64 {
+	if f.Deaths == 0 {
+		return float64(f.Frags)
+	}
+	return float64(f.Frags) / float64(f.Deaths)
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "testing"
+
+func TestFragCount_Ratio(t *testing.T) {
+	tests := []struct {
+		count FragCount
+		want  float64
+	}{
+		{FragCount{Frags: 6, Deaths: 4}, 1.5},
+		{FragCount{Frags: 3}, 3},
+		{FragCount{Deaths: 2}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.count.Ratio(); got != tt.want {
+			t.Errorf("%+v: expected %v, got %v", tt.count, tt.want, got)
+		}
+	}
+}
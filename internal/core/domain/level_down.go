@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// MinLevelDrop is the smallest loss announced as a level down. A single
+// level is what most deaths cost and what a stale character page most often
+// looks like, so it is not worth a message.
+const MinLevelDrop = 2
+
+// LevelDown is a significant drop of a character's level, usually a death
+// with a heavy experience loss or a server rollback.
+type LevelDown struct {
+	PlayerName string
+	OldLevel   int
+	NewLevel   int
+	World      string
+	Vocation   string
+	GuildName  string
+	GuildRank  string
+	// WorldPvPType is the PvP type of World, or empty when it is unknown.
+	WorldPvPType string
+	// DetectedAt is when the drop was confirmed.
+	DetectedAt time.Time
+}
+
+// IsLevelDrop reports whether going from oldLevel to newLevel loses at least
+// MinLevelDrop levels.
+func IsLevelDrop(oldLevel, newLevel int) bool {
+	return oldLevel-newLevel >= MinLevelDrop
+}
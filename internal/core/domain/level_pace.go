@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// maxProjection bounds crossover projections; anything further out says more
+// about the pace estimate than about the race.
+const maxProjection = 5 * 365 * 24 * time.Hour
+
+// LevelPace is a character's current level and its net level change over the
+// trailing Window.
+type LevelPace struct {
+	Name   string
+	Level  int
+	Gained int
+	Window time.Duration
+}
+
+// NewLevelPace derives a pace from the character's level ups during window,
+// oldest first. Without level ups the level is assumed unchanged; deaths after
+// the last level up make Gained smaller or negative.
+func NewLevelPace(name string, level int, levelUps []LevelUp, window time.Duration) LevelPace {
+	start := level
+	if len(levelUps) > 0 {
+		start = levelUps[0].OldLevel
+	}
+	return LevelPace{Name: name, Level: level, Gained: level - start, Window: window}
+}
+
+// PerDay is the average net levels gained per day over the window.
+func (p LevelPace) PerDay() float64 {
+	if p.Window <= 0 {
+		return 0
+	}
+	return float64(p.Gained) / p.Window.Hours() * 24
+}
+
+// LevelForecast is a character's level and its recent pace: an exponential
+// moving average of the net levels it gained on each of the last Days days.
+type LevelForecast struct {
+	Name   string
+	Level  int
+	PerDay float64
+	Days   int
+	// Online is how long the character was seen online during the last
+	// Days days, zero when it was not seen.
+	Online time.Duration
+}
+
+// ProjectedLevel is the level reached after days more at PerDay, never below
+// level 1.
+func (f LevelForecast) ProjectedLevel(days int) int {
+	return max(f.Level+int(math.Round(f.PerDay*float64(days))), 1)
+}
+
+// ProjectCrossover estimates when the lower-level character overtakes the
+// other if both keep their pace. It reports false when both have the same
+// level or the gap is not closing within maxProjection.
+func ProjectCrossover(a, b LevelPace, now time.Time) (time.Time, bool) {
+	leader, trailer := a, b
+	if b.Level > a.Level {
+		leader, trailer = b, a
+	}
+
+	gap := float64(leader.Level - trailer.Level)
+	closing := trailer.PerDay() - leader.PerDay()
+	if gap == 0 || closing <= 0 {
+		return time.Time{}, false
+	}
+
+	days := gap / closing
+	if days > maxProjection.Hours()/24 {
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(days * float64(24*time.Hour))), true
+}
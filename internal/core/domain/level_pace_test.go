@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+func TestNewLevelPace(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		levelUps []LevelUp
+		expected int
+	}{
+		{"no level ups", 200, nil, 0},
+		{"gained since first level up", 210, []LevelUp{{OldLevel: 200, NewLevel: 205}, {OldLevel: 205, NewLevel: 211}}, 10},
+		{"died after leveling", 199, []LevelUp{{OldLevel: 200, NewLevel: 201}}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pace := NewLevelPace("Hero", tt.level, tt.levelUps, week)
+			if pace.Gained != tt.expected {
+				t.Errorf("expected %d levels gained, got %d", tt.expected, pace.Gained)
+			}
+		})
+	}
+}
+
+func TestLevelPace_PerDay(t *testing.T) {
+	if got := (LevelPace{Gained: 14, Window: week}).PerDay(); got != 2 {
+		t.Errorf("expected 2 levels per day, got %v", got)
+	}
+	if got := (LevelPace{Gained: 14}).PerDay(); got != 0 {
+		t.Errorf("expected 0 without a window, got %v", got)
+	}
+}
+
+func TestLevelForecast_ProjectedLevel(t *testing.T) {
+	if got := (LevelForecast{Level: 300, PerDay: 2.4}).ProjectedLevel(30); got != 372 {
+		t.Errorf("expected level 372, got %d", got)
+	}
+	if got := (LevelForecast{Level: 20, PerDay: -1}).ProjectedLevel(30); got != 1 {
+		t.Errorf("expected the projection to stop at level 1, got %d", got)
+	}
+}
+
+func TestProjectCrossover(t *testing.T) {
+	now := time.Date(2024, 12, 13, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		a, b     LevelPace
+		expected time.Time
+		ok       bool
+	}{
+		{
+			name:     "trailer catches up",
+			a:        LevelPace{Level: 300, Gained: 7, Window: week},
+			b:        LevelPace{Level: 290, Gained: 21, Window: week},
+			expected: now.Add(5 * 24 * time.Hour),
+			ok:       true,
+		},
+		{
+			name:     "order does not matter",
+			a:        LevelPace{Level: 290, Gained: 21, Window: week},
+			b:        LevelPace{Level: 300, Gained: 7, Window: week},
+			expected: now.Add(5 * 24 * time.Hour),
+			ok:       true,
+		},
+		{
+			name: "leader is faster",
+			a:    LevelPace{Level: 300, Gained: 21, Window: week},
+			b:    LevelPace{Level: 290, Gained: 7, Window: week},
+		},
+		{
+			name: "same level",
+			a:    LevelPace{Level: 300, Gained: 7, Window: week},
+			b:    LevelPace{Level: 300, Gained: 14, Window: week},
+		},
+		{
+			name: "too far out",
+			a:    LevelPace{Level: 1000, Gained: 0, Window: week},
+			b:    LevelPace{Level: 100, Gained: 1, Window: 365 * 24 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ProjectCrossover(tt.a, tt.b, now)
+			if ok != tt.ok || !got.Equal(tt.expected) {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+}
@@ -1,46 +1,512 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type World struct {
 	Name string
 }
 
 type Guild struct {
-	Name string
+	Name    string
+	World   string
+	Members []Player
+	// InWar is set while the guild is in a guild war.
+	InWar bool
 }
 
 type Player struct {
-	Name     string
-	Level    int
-	Vocation string
-	World    string
-	Deaths   []Kill
+	Name      string
+	Level     int
+	Vocation  string
+	World     string
+	GuildName string
+	GuildRank string
+	Deaths    []Kill
+	// FormerNames are names the character was known by before a rename.
+	FormerNames []string
+	// WorldPvPType is the PvP type of World, or empty when it is unknown.
+	WorldPvPType string
 }
 
 type Kill struct {
-	ID       string
-	Time     time.Time
-	Level    int
-	Reason   string
-	Involved []Killer
+	ID     string
+	Time   time.Time
+	Level  int
+	Reason string
+	// Killers dealt the killing damage; Assists only took part.
+	Killers []Killer
+	Assists []Killer
 }
 
 type Killer struct {
 	Name     string
 	IsPlayer bool
+	// IsSummon marks a creature summoned by the player named in Name.
 	IsSummon bool
 }
 
+// IsPvP reports whether any character took part in the death.
+func (k Kill) IsPvP() bool {
+	return len(k.PlayerKillers()) > 0 || len(playerNames(k.Assists)) > 0
+}
+
+// PlayerKillers returns the names of the characters that dealt the killing
+// damage, including through their summons, without duplicates.
+func (k Kill) PlayerKillers() []string {
+	return playerNames(k.Killers)
+}
+
+func playerNames(killers []Killer) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, killer := range killers {
+		if !killer.IsPlayer || seen[killer.Name] {
+			continue
+		}
+		seen[killer.Name] = true
+		names = append(names, killer.Name)
+	}
+	return names
+}
+
+// PlayerLevel is a single row for a batched level update.
+type PlayerLevel struct {
+	Name  string
+	Level int
+	World string
+}
+
+type DeathCount struct {
+	Name  string
+	Count int
+}
+
+// KillerCount is how many tracked deaths a character took part in as killer.
+type KillerCount struct {
+	Name  string
+	Kills int
+}
+
 type LevelUp struct {
 	PlayerName string
 	OldLevel   int
 	NewLevel   int
 	World      string
+	Vocation   string
+	GuildName  string
+	GuildRank  string
+	// WorldPvPType is the PvP type of World, or empty when it is unknown.
+	WorldPvPType string
+	// ReachedAt is when the level up was detected, or stored for level ups
+	// read back from storage.
+	ReachedAt time.Time
+}
+
+// DeathRecord is a death read back from the death log. ID orders records in
+// the order they were stored.
+type DeathRecord struct {
+	ID      int64
+	Name    string
+	World   string
+	Level   int
+	Reason  string
+	Killers []string
+	DiedAt  time.Time
+}
+
+// LevelUpRecord is a level up read back from storage. ID orders records in
+// the order they were stored.
+type LevelUpRecord struct {
+	ID        int64
+	Name      string
+	World     string
+	OldLevel  int
+	NewLevel  int
+	ReachedAt time.Time
+}
+
+// DailyLevelGain is a character's net levels gained on one UTC day.
+type DailyLevelGain struct {
+	Day    time.Time
+	Levels int
+}
+
+// MembershipChange lists characters that joined or left a Tibia guild since
+// its member list was last stored.
+type MembershipChange struct {
+	GuildName string
+	Joined    []string
+	Left      []string
+}
+
+// CharacterChange reports a tracked character that was renamed, moved to
+// another world, or both. OldName and OldWorld are empty for the part that
+// did not change.
+type CharacterChange struct {
+	Name     string
+	OldName  string
+	World    string
+	OldWorld string
+}
+
+// HouseAuction is a house or guildhall up for auction on a world.
+type HouseAuction struct {
+	HouseID    int
+	Name       string
+	Town       string
+	World      string
+	Size       int
+	Rent       int
+	CurrentBid int
+	// TimeLeft is TibiaData's remaining auction time, e.g. "2 days".
+	TimeLeft string
+}
+
+// GuildMemberList is a Tibia guild's member list as last fetched.
+type GuildMemberList struct {
+	GuildName string
+	Members   []string
+	FetchedAt time.Time
 }
 
 type GuildConfig struct {
 	DiscordGuildID string
 	World          string
 	TibiaGuilds    []string
+	Language       string
+	DeathChannelID string
+	LevelChannelID string
+	PingRoleID     string
+	PingMinLevel   int
+	PollInterval   time.Duration
+	MutedUntil     time.Time
+	IgnoredPlayers []string
+	// LowLevelDeaths announces deaths of tracked Tibia guild members even
+	// below the global minimum level.
+	LowLevelDeaths bool
+	// ShareRange appends the party experience share range to level ups.
+	ShareRange bool
+	// DeathLocation appends the hunting ground or boss area guessed from
+	// the killers to deaths.
+	DeathLocation bool
+	// LevelDowns announces drops of MinLevelDrop or more levels to the level
+	// channel.
+	LevelDowns bool
+	// BroadcastOptOut keeps operator announcements out of the guild.
+	BroadcastOptOut bool
+	// MinLevel raises MIN_LEVEL_TRACK for the guild: deaths and level ups of
+	// characters below it are not announced there. 0 keeps the bot-wide one.
+	MinLevel int
+	// LastNotifiedAt is when a notification was last delivered to the guild.
+	LastNotifiedAt time.Time
+	// HouseChannelID receives house auction announcements for the world;
+	// empty means the guild has not opted in.
+	HouseChannelID string
+	// MiscChannelID receives daily informational posts; empty falls back to
+	// the level channel.
+	MiscChannelID string
+	// Timezone is the IANA zone timestamps in notifications are shown in;
+	// empty uses the bot's local zone.
+	Timezone string
+	// DeathTemplate, LevelTemplate and LevelDownTemplate replace the default
+	// death, level up and level down messages with {placeholder} templates;
+	// empty uses the defaults.
+	DeathTemplate     string
+	LevelTemplate     string
+	LevelDownTemplate string
+	// DeathEmoji and LevelEmoji are prepended to death and level up messages;
+	// empty adds nothing.
+	DeathEmoji string
+	LevelEmoji string
+	// DeathReaction and LevelReaction make the bot react to its own death and
+	// level up messages.
+	DeathReaction bool
+	LevelReaction bool
+	// DeathRoutes send deaths to other channels by level, sorted by
+	// MinLevel.
+	DeathRoutes []DeathRoute
+	QuietHours  QuietHours
+	// Quota overrides the bot-wide limits on what the guild can track.
+	Quota Quota
+	// Premium unlocks the features gated by PREMIUM_GATING.
+	Premium bool
+	// SkillChannelID receives skill advances of WatchedPlayers; empty means
+	// the guild has not opted in.
+	SkillChannelID string
+	WatchedPlayers []string
+	// SkillThresholds hold the lowest value of each skill worth announcing;
+	// skills without one are announced at any value.
+	SkillThresholds map[Skill]int
+	// MassDeathAlert raises an extra alert when many tracked characters die
+	// close together.
+	MassDeathAlert MassDeathAlert
+	// ResponseVisibility overrides who sees the bot's replies to commands.
+	ResponseVisibility ResponseVisibility
+	// DeathVerbosity is how much death notifications tell.
+	DeathVerbosity DeathVerbosity
+}
+
+// MassDeathAlert warns of a possible war or raid when Deaths or more tracked
+// characters die within Window. Zero Deaths turns it off.
+type MassDeathAlert struct {
+	Deaths int
+	Window time.Duration
+}
+
+// Enabled reports whether the guild wants mass death alerts.
+func (a MassDeathAlert) Enabled() bool {
+	return a.Deaths > 0 && a.Window > 0
+}
+
+// MassDeath is a cluster of deaths on one world that reached a guild's
+// MassDeathAlert. Victims are in order of death.
+type MassDeath struct {
+	World   string
+	Window  time.Duration
+	Victims []MassDeathVictim
+}
+
+type MassDeathVictim struct {
+	Name  string
+	Level int
+	Time  time.Time
+}
+
+// Quota overrides the bot-wide limits for one guild. Zero keeps the bot-wide
+// limit and a negative value lifts it.
+type Quota struct {
+	TibiaGuilds    int
+	IgnoredPlayers int
+	WatchedPlayers int
+}
+
+// QuietHours is a daily window in the guild's timezone during which no
+// notifications are posted. Start and End are minutes after midnight; the
+// window wraps past midnight when End is before Start, and equal values turn
+// it off.
+type QuietHours struct {
+	Start int
+	End   int
+	// CatchUp posts the deaths and level ups held back during the window as
+	// one message when it ends; otherwise they are dropped.
+	CatchUp bool
+}
+
+// Enabled reports whether the guild has quiet hours.
+func (q QuietHours) Enabled() bool {
+	return q.Start != q.End
+}
+
+// Contains reports whether the wall clock time of t falls inside the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.Enabled() {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if q.Start < q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+// String formats the window as "HH:MM-HH:MM".
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
+
+// CatchUp holds the deaths and level ups a guild missed during its quiet
+// hours.
+type CatchUp struct {
+	Deaths   []CatchUpDeath
+	LevelUps []LevelUp
+}
+
+type CatchUpDeath struct {
+	Player Player
+	Kill   Kill
+}
+
+// Len counts the held back events.
+func (c CatchUp) Len() int {
+	return len(c.Deaths) + len(c.LevelUps)
+}
+
+// DeathRoute sends deaths at or above MinLevel to ChannelID instead of the
+// guild's death channel.
+type DeathRoute struct {
+	MinLevel  int
+	ChannelID string
+}
+
+// TrackStatus aggregates a Discord guild's tracking setup for /track-status.
+type TrackStatus struct {
+	Config GuildConfig
+	// PendingNotifications counts failed notifications awaiting retry.
+	PendingNotifications int
+	// WorldPvPType is the tracked world's PvP type, or empty when it could
+	// not be fetched.
+	WorldPvPType string
+}
+
+// PurgeResult counts the rows /purge-data deleted for a Discord guild. History
+// shared with other tracking guilds is kept and not counted.
+type PurgeResult struct {
+	Configs       int64
+	Notifications int64
+	GuildMembers  int64
+	Deaths        int64
+	Players       int64
+	LevelUps      int64
+}
+
+// IsMuted reports whether notifications for the guild are paused at now.
+func (g GuildConfig) IsMuted(now time.Time) bool {
+	return now.Before(g.MutedUntil)
+}
+
+// Announces reports whether deaths and level ups at level pass the guild's
+// own minimum level.
+func (g GuildConfig) Announces(level int) bool {
+	return level >= g.MinLevel
+}
+
+// InQuietHours reports whether now falls inside the guild's quiet hours.
+func (g GuildConfig) InQuietHours(now time.Time) bool {
+	return g.QuietHours.Contains(now.In(g.Location()))
+}
+
+// IsIgnored reports whether the guild never wants notifications about the
+// character, under any spelling SameName accepts.
+func (g GuildConfig) IsIgnored(name string) bool {
+	for _, ignored := range g.IgnoredPlayers {
+		if SameName(ignored, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the zone the guild's notifications show timestamps in,
+// falling back to the bot's local zone when none is set or it cannot be
+// loaded.
+func (g GuildConfig) Location() *time.Location {
+	if g.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// DeathChannelFor returns the channel a death at level is posted to: the
+// route with the highest MinLevel at or below level, or the death channel.
+func (g GuildConfig) DeathChannelFor(level int) string {
+	channelID := g.DeathChannelID
+	for _, route := range g.DeathRoutes {
+		if level >= route.MinLevel {
+			channelID = route.ChannelID
+		}
+	}
+	return channelID
+}
+
+type NotificationChannel string
+
+const (
+	ChannelDeaths NotificationChannel = "deaths"
+	ChannelLevels NotificationChannel = "levels"
+	ChannelHouses NotificationChannel = "houses"
+	ChannelMisc   NotificationChannel = "misc"
+	ChannelSkills NotificationChannel = "skills"
+	// ChannelLevelDowns only names the level down template; level downs are
+	// posted to the level channel.
+	ChannelLevelDowns NotificationChannel = "level_downs"
+)
+
+// ResponseVisibility is who sees the bot's replies to commands in a guild.
+type ResponseVisibility string
+
+const (
+	// VisibilityDefault keeps each command's own choice: confirmations are
+	// public, errors and personal lookups ephemeral.
+	VisibilityDefault   ResponseVisibility = ""
+	VisibilityEphemeral ResponseVisibility = "ephemeral"
+	VisibilityPublic    ResponseVisibility = "public"
+)
+
+// Valid reports whether v is one of the known visibilities.
+func (v ResponseVisibility) Valid() bool {
+	switch v {
+	case VisibilityDefault, VisibilityEphemeral, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// DeathVerbosity is how much a guild's death notifications tell.
+type DeathVerbosity string
+
+const (
+	// VerbosityNormal is the usual one-line message with the estimated
+	// experience loss.
+	VerbosityNormal DeathVerbosity = ""
+	// VerbosityCompact only names the character and the death reason.
+	VerbosityCompact DeathVerbosity = "compact"
+	// VerbosityDetailed adds the killers, assists and character links.
+	VerbosityDetailed DeathVerbosity = "detailed"
+)
+
+// Valid reports whether v is one of the known verbosities.
+func (v DeathVerbosity) Valid() bool {
+	switch v {
+	case VerbosityNormal, VerbosityCompact, VerbosityDetailed:
+		return true
+	}
+	return false
+}
+
+type NotificationKind string
+
+const (
+	NotificationLevelUp     NotificationKind = "level_up"
+	NotificationDeath       NotificationKind = "death"
+	NotificationDeathStreak NotificationKind = "death_streak"
+	NotificationMembership  NotificationKind = "membership"
+	NotificationHouse       NotificationKind = "house_auction"
+	NotificationCharacter   NotificationKind = "character_change"
+	NotificationCatchUp     NotificationKind = "catch_up"
+	NotificationSkill       NotificationKind = "skill_advance"
+	NotificationMassDeath   NotificationKind = "mass_death"
+	NotificationLevelDown   NotificationKind = "level_down"
+	NotificationGuildWar    NotificationKind = "guild_war"
+)
+
+// StoppedConfigRetention is how long the configuration of a guild that ran
+// /stop-tracking is kept for /resume-tracking.
+const StoppedConfigRetention = 30 * 24 * time.Hour
+
+// StoppedGuildConfig is the configuration a guild had when it stopped
+// tracking. Snapshot holds the JSON-encoded configuration.
+type StoppedGuildConfig struct {
+	DiscordGuildID string
+	Snapshot       []byte
+	StoppedAt      time.Time
+}
+
+// FailedNotification is a notification that could not be delivered and is
+// waiting to be retried. Payload holds the JSON-encoded event.
+type FailedNotification struct {
+	ID             int64
+	DiscordGuildID string
+	Kind           NotificationKind
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
 }
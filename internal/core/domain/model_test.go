@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKill_PlayerKillers(t *testing.T) {
+	kill := Kill{
+		Killers: []Killer{
+			{Name: "dragon lord"},
+			{Name: "Enemy Knight", IsPlayer: true},
+			{Name: "Enemy Druid", IsPlayer: true, IsSummon: true},
+			{Name: "Enemy Knight", IsPlayer: true},
+		},
+		Assists: []Killer{{Name: "Enemy Sorcerer", IsPlayer: true}},
+	}
+
+	want := []string{"Enemy Knight", "Enemy Druid"}
+	if got := kill.PlayerKillers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestKill_IsPvP(t *testing.T) {
+	tests := []struct {
+		name     string
+		kill     Kill
+		expected bool
+	}{
+		{"creatures only", Kill{Killers: []Killer{{Name: "dragon"}}}, false},
+		{"no killers", Kill{Reason: "Died by a rat"}, false},
+		{"player killer", Kill{Killers: []Killer{{Name: "Enemy", IsPlayer: true}}}, true},
+		{"player assist", Kill{Killers: []Killer{{Name: "dragon"}}, Assists: []Killer{{Name: "Enemy", IsPlayer: true}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kill.IsPvP(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGuildConfig_Location(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		expected string
+	}{
+		{"unset", "", time.Local.String()},
+		{"zone", "Europe/Warsaw", "Europe/Warsaw"},
+		{"unknown zone", "Mars/Olympus", time.Local.String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (GuildConfig{Timezone: tt.timezone}).Location().String(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGuildConfig_DeathChannelFor(t *testing.T) {
+	cfg := GuildConfig{
+		DeathChannelID: "deaths",
+		DeathRoutes:    []DeathRoute{{MinLevel: 300, ChannelID: "mid"}, {MinLevel: 1000, ChannelID: "high"}},
+	}
+
+	tests := []struct {
+		level    int
+		expected string
+	}{
+		{50, "deaths"},
+		{300, "mid"},
+		{999, "mid"},
+		{1500, "high"},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.DeathChannelFor(tt.level); got != tt.expected {
+			t.Errorf("level %d: expected %s, got %s", tt.level, tt.expected, got)
+		}
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	night := QuietHours{Start: 22 * 60, End: 6 * 60}
+	morning := QuietHours{Start: 2 * 60, End: 8 * 60}
+
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		clock    string
+		expected bool
+	}{
+		{"off", QuietHours{}, "03:00", false},
+		{"inside", morning, "02:00", true},
+		{"end is exclusive", morning, "08:00", false},
+		{"before", morning, "01:59", false},
+		{"wraps before midnight", night, "23:30", true},
+		{"wraps after midnight", night, "05:59", true},
+		{"outside wrapped", night, "12:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock, _ := time.Parse("15:04", tt.clock)
+			if got := tt.quiet.Contains(clock); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGuildConfig_InQuietHours(t *testing.T) {
+	cfg := GuildConfig{Timezone: "Europe/Warsaw", QuietHours: QuietHours{Start: 2 * 60, End: 8 * 60}}
+
+	// 01:30 UTC is 03:30 in Warsaw in summer.
+	if !cfg.InQuietHours(time.Date(2026, 7, 1, 1, 30, 0, 0, time.UTC)) {
+		t.Error("expected quiet hours to follow the guild's timezone")
+	}
+	if cfg.InQuietHours(time.Date(2026, 7, 1, 6, 30, 0, 0, time.UTC)) {
+		t.Error("expected 08:30 in Warsaw to be outside quiet hours")
+	}
+	if got := cfg.QuietHours.String(); got != "02:00-08:00" {
+		t.Errorf("expected 02:00-08:00, got %s", got)
+	}
+}
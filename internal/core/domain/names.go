@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Character and guild names reach the bot in several encodings: as typed,
+// URL-encoded by TibiaData and tibia.com links ("Sir%27Lance",
+// "Red+Rose"), with non-breaking spaces from tibia.com pages, or with
+// typographic apostrophes pasted from chat. NormalizeName gives them the one
+// form that is stored, cached and shown; NameKey compares names the way
+// Tibia does, ignoring case and accents.
+
+// nameApostrophes are characters pasted in place of the apostrophe in names
+// such as "Sir'Lance".
+const nameApostrophes = "’‘`´ʼ′"
+
+// NormalizeName decodes URL-encoded names, turns typographic apostrophes
+// into ' and every run of whitespace into a single space, and trims the
+// name. Case and accents are kept.
+func NormalizeName(name string) string {
+	if strings.ContainsAny(name, "%+") {
+		// Names never contain '+' or '%', so either means the name is
+		// encoded. Malformed escapes are kept as they are.
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(nameApostrophes, r):
+			return '\''
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeNames normalizes every name, dropping those left empty.
+func NormalizeNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		if n := NormalizeName(name); n != "" {
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized
+}
+
+// unfoldable maps letters without a decomposition to their ASCII spelling.
+var unfoldable = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "þ", "th", "ı", "i")
+
+// NameKey is the key two spellings of the same name share: the normalized
+// name in lower case with accents folded to ASCII, so "Sír Łance",
+// "sir lance" and "Sir%20Lance" have the same key.
+func NameKey(name string) string {
+	key := strings.ToLower(NormalizeName(name))
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), key)
+	if err == nil {
+		key = folded
+	}
+	return unfoldable.Replace(key)
+}
+
+// MaxNameSuggestions is how many names a prefix lookup suggests, the most
+// choices Discord shows for an autocompleted option.
+const MaxNameSuggestions = 25
+
+// SameName reports whether a and b name the same character or guild.
+func SameName(a, b string) bool {
+	return NameKey(a) == NameKey(b)
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"Plain", "Bubble", "Bubble"},
+		{"Keeps case", "sIR lANCE", "sIR lANCE"},
+		{"Keeps accents", "Sír Lánce", "Sír Lánce"},
+		{"Keeps hyphens", "Mary-Jane", "Mary-Jane"},
+		{"Trims", "  Bubble \t", "Bubble"},
+		{"Collapses spaces", "Sir   Lance", "Sir Lance"},
+		{"Non-breaking space", "Sir\u00a0Lance", "Sir Lance"},
+		{"Tab and newline", "Sir\t\nLance", "Sir Lance"},
+		{"Empty", "", ""},
+		{"Only spaces", " \u00a0 ", ""},
+
+		{"Apostrophe", "Sir'Lance", "Sir'Lance"},
+		{"Encoded apostrophe", "Sir%27Lance", "Sir'Lance"},
+		{"Lower case escape", "Sir%2flance", "Sir/lance"},
+		{"Right single quote", "Sir’Lance", "Sir'Lance"},
+		{"Left single quote", "Sir‘Lance", "Sir'Lance"},
+		{"Backtick", "Sir`Lance", "Sir'Lance"},
+		{"Acute accent", "Sir´Lance", "Sir'Lance"},
+		{"Modifier apostrophe", "Sirʼ Lance", "Sir' Lance"},
+
+		{"Encoded space", "Sir%20Lance", "Sir Lance"},
+		{"Plus space", "Red+Rose", "Red Rose"},
+		{"Encoded non-breaking space", "Sir%C2%A0Lance", "Sir Lance"},
+		{"Encoded accent", "S%C3%ADr", "Sír"},
+		{"Encoded apostrophe and spaces", "Sir%27s+Lance%20Knight", "Sir's Lance Knight"},
+		{"Malformed escape kept", "100%", "100%"},
+		{"Malformed escape with plus kept", "A+%zz", "A+%zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.in); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName_Idempotent(t *testing.T) {
+	for _, in := range []string{"Sir%27Lance", "Red+Rose", "Sír\u00a0 Lánce", "Sir’s Knight", "100%"} {
+		once := NormalizeName(in)
+		if twice := NormalizeName(once); twice != once {
+			t.Errorf("NormalizeName(%q) = %q, but normalizing again gives %q", in, once, twice)
+		}
+	}
+}
+
+func TestNormalizeNames(t *testing.T) {
+	got := NormalizeNames([]string{"Sir%27Lance", " ", "Red+Rose", ""})
+	if want := []string{"Sir'Lance", "Red Rose"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Bubble", "bubble"},
+		{"SIR LANCE", "sir lance"},
+		{"Sir%27Lance", "sir'lance"},
+		{"Sir’Lance", "sir'lance"},
+		{"Sír Lánce", "sir lance"},
+		{"Ñandú", "nandu"},
+		{"Çağrı", "cagri"},
+		{"Zoë", "zoe"},
+		{"Ångström", "angstrom"},
+		{"Łukasz", "lukasz"},
+		{"Strauß", "strauss"},
+		{"Ærø", "aero"},
+		{"Œuvre", "oeuvre"},
+		{"Đorđe", "dorde"},
+		{"Þór", "thor"},
+		{"Red+Rose", "red rose"},
+		{"  Sir\u00a0\u00a0Lance ", "sir lance"},
+	}
+
+	for _, tt := range tests {
+		if got := NameKey(tt.in); got != tt.want {
+			t.Errorf("NameKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSameName(t *testing.T) {
+	same := [][2]string{
+		{"Sir'Lance", "Sir%27Lance"},
+		{"Sir'Lance", "sir’lance"},
+		{"Sir Lance", "Sir+Lance"},
+		{"Sir Lance", "Sir\u00a0Lance"},
+		{"Sir Lance", " sir  lance "},
+		{"Sír Lánce", "Sir Lance"},
+		{"Red Rose", "red%20rose"},
+	}
+	for _, pair := range same {
+		if !SameName(pair[0], pair[1]) {
+			t.Errorf("expected %q and %q to be the same name", pair[0], pair[1])
+		}
+	}
+
+	different := [][2]string{
+		{"Sir Lance", "SirLance"},
+		{"Sir'Lance", "Sir Lance"},
+		{"Sir-Lance", "Sir Lance"},
+		{"Bubble", "Bubbles"},
+		{"", "Bubble"},
+	}
+	for _, pair := range different {
+		if SameName(pair[0], pair[1]) {
+			t.Errorf("expected %q and %q to be different names", pair[0], pair[1])
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package domain
+
+// OnlineList is a world's online players as listed by one level source.
+type OnlineList struct {
+	Source  string
+	Players []Player
+	// Live means the listed levels are current, so level ups can be told
+	// without fetching every character page.
+	Live bool
+}
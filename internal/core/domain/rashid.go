@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// rashidCities is where the travelling trader Rashid stays on each weekday.
+// He moves at server save, so the weekday is that of the Tibia day. He is the
+// only travelling NPC on a fixed schedule: others, like the pirate Yasir,
+// turn up at random and neither TibiaData nor tibia.com lists them.
+var rashidCities = map[time.Weekday]string{
+	time.Monday:    "Svargrond",
+	time.Tuesday:   "Liberty Bay",
+	time.Wednesday: "Port Hope",
+	time.Thursday:  "Ankrahmun",
+	time.Friday:    "Darashia",
+	time.Saturday:  "Edron",
+	time.Sunday:    "Carlin",
+}
+
+// RashidCity returns the city Rashid is in at now.
+func RashidCity(now time.Time) string {
+	return rashidCities[LastServerSave(now).Weekday()]
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRashidCity(t *testing.T) {
+	// 2024-12-13 is a Friday.
+	save := time.Date(2024, 12, 13, ServerSaveHour, 0, 0, 0, ServerSaveLocation)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{"at server save", save, "Darashia"},
+		{"evening", save.Add(10 * time.Hour), "Darashia"},
+		{"before next save", save.Add(24*time.Hour - time.Minute), "Darashia"},
+		{"next day", save.Add(24 * time.Hour), "Edron"},
+		{"before server save", save.Add(-time.Minute), "Ankrahmun"},
+		{"other timezone", save.Add(time.Minute).UTC(), "Darashia"},
+		{"sunday", save.Add(2 * 24 * time.Hour), "Carlin"},
+		{"monday", save.Add(3 * 24 * time.Hour), "Svargrond"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RashidCity(tt.now); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNextServerSave(t *testing.T) {
+	save := time.Date(2024, 12, 13, ServerSaveHour, 0, 0, 0, ServerSaveLocation)
+
+	if got := NextServerSave(save.Add(-time.Minute)); !got.Equal(save) {
+		t.Errorf("expected %v before server save, got %v", save, got)
+	}
+	if got := NextServerSave(save); !got.Equal(save.AddDate(0, 0, 1)) {
+		t.Errorf("expected the following day at server save, got %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ServerSaveHour is the local hour of Tibia's daily server save. A Tibia day
+// runs from one server save to the next.
+const ServerSaveHour = 10
+
+// ServerSaveLocation is the zone the daily server save follows.
+var ServerSaveLocation = loadServerSaveLocation()
+
+// loadServerSaveLocation returns the zone Tibia's daily server save follows,
+// falling back to a fixed CET offset when tzdata is unavailable.
+func loadServerSaveLocation() *time.Location {
+	if loc, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CET", 60*60)
+}
+
+// LastServerSave returns the server save that started the Tibia day now falls
+// in.
+func LastServerSave(now time.Time) time.Time {
+	local := now.In(ServerSaveLocation)
+	save := time.Date(local.Year(), local.Month(), local.Day(), ServerSaveHour, 0, 0, 0, ServerSaveLocation)
+	if local.Before(save) {
+		save = save.AddDate(0, 0, -1)
+	}
+	return save
+}
+
+// NextServerSave returns the first server save after now.
+func NextServerSave(now time.Time) time.Time {
+	return LastServerSave(now).AddDate(0, 0, 1)
+}
@@ -0,0 +1,21 @@
+package domain
+
+// ShareRange is the span of levels a character can share party experience
+// with.
+type ShareRange struct {
+	Min int
+	Max int
+}
+
+// PartyShareRange returns the levels that can share experience with a
+// character of level: from two thirds of it, rounded up, to three halves of
+// it, rounded down.
+func PartyShareRange(level int) ShareRange {
+	if level < 1 {
+		return ShareRange{}
+	}
+	return ShareRange{
+		Min: (2*level + 2) / 3,
+		Max: level * 3 / 2,
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "testing"
+
+func TestPartyShareRange(t *testing.T) {
+	tests := []struct {
+		level    int
+		expected ShareRange
+	}{
+		{0, ShareRange{}},
+		{1, ShareRange{Min: 1, Max: 1}},
+		{3, ShareRange{Min: 2, Max: 4}},
+		{100, ShareRange{Min: 67, Max: 150}},
+		{500, ShareRange{Min: 334, Max: 750}},
+	}
+
+	for _, tt := range tests {
+		if got := PartyShareRange(tt.level); got != tt.expected {
+			t.Errorf("level %d: expected %+v, got %+v", tt.level, tt.expected, got)
+		}
+	}
+}
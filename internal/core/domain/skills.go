@@ -0,0 +1,50 @@
+package domain
+
+// Skill is a character skill ranked on the world highscores.
+type Skill string
+
+const (
+	SkillMagic     Skill = "magic"
+	SkillFist      Skill = "fist"
+	SkillClub      Skill = "club"
+	SkillSword     Skill = "sword"
+	SkillAxe       Skill = "axe"
+	SkillDistance  Skill = "distance"
+	SkillShielding Skill = "shielding"
+	SkillFishing   Skill = "fishing"
+)
+
+// Skills lists every tracked skill in display order.
+var Skills = []Skill{SkillMagic, SkillFist, SkillClub, SkillSword, SkillAxe, SkillDistance, SkillShielding, SkillFishing}
+
+// IsSkill reports whether s names a tracked skill.
+func IsSkill(s string) bool {
+	for _, skill := range Skills {
+		if string(skill) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// HighscorePage is one page of a world's highscores for a skill, highest
+// value first.
+type HighscorePage struct {
+	Entries    []HighscoreEntry
+	TotalPages int
+}
+
+type HighscoreEntry struct {
+	Name  string
+	Value int
+}
+
+// SkillAdvance is a watched character raising a skill between two highscore
+// updates.
+type SkillAdvance struct {
+	PlayerName string
+	World      string
+	Skill      Skill
+	OldValue   int
+	NewValue   int
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// GuildWar is a war a Tibia guild took part in. TibiaData only tells whether
+// a guild is at war, not against whom or since when, so a war runs from the
+// check that first saw the guild at war to the one that saw it at peace.
+type GuildWar struct {
+	GuildName string
+	StartedAt time.Time
+	// EndedAt is zero while the war goes on.
+	EndedAt time.Time
+}
+
+// Ongoing reports whether the guild was still at war when last checked.
+func (w GuildWar) Ongoing() bool {
+	return w.EndedAt.IsZero()
+}
+
+// Until is when the war ended, or now while it goes on.
+func (w GuildWar) Until(now time.Time) time.Time {
+	if w.Ongoing() {
+		return now
+	}
+	return w.EndedAt
+}
+
+// WarScore tallies the kills between a tracked guild and its opponent during
+// a war, counting characters by the guild they are in now.
+type WarScore struct {
+	War      GuildWar
+	Opponent string
+	// Kills are deaths of opponent members with a guild member among the
+	// killers; Deaths are the other way around.
+	Kills  int
+	Deaths int
+}
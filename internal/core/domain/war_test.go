@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuildWar_Until(t *testing.T) {
+	start := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(48 * time.Hour)
+
+	war := GuildWar{GuildName: "Red Rose", StartedAt: start}
+	if !war.Ongoing() || !war.Until(now).Equal(now) {
+		t.Errorf("expected an ongoing war to run until now, got %v", war.Until(now))
+	}
+
+	war.EndedAt = start.Add(24 * time.Hour)
+	if war.Ongoing() || !war.Until(now).Equal(war.EndedAt) {
+		t.Errorf("expected an ended war to run until it ended, got %v", war.Until(now))
+	}
+}
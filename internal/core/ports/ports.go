@@ -8,31 +8,264 @@ import (
 
 type Repository interface {
 	SaveGuildWorld(ctx context.Context, discordGuildID, world string) error
+	// GetGuildConfig returns nil and no error for a guild that was never
+	// configured; errors always mean the lookup itself failed.
 	GetGuildConfig(ctx context.Context, discordGuildID string) (*domain.GuildConfig, error)
 	GetAllGuildConfigs(ctx context.Context) ([]domain.GuildConfig, error)
 	DeleteGuildConfig(ctx context.Context, discordGuildID string) error
 	AddGuildToConfig(ctx context.Context, discordGuildID, guildName string) error
 	RemoveGuildFromConfig(ctx context.Context, discordGuildID, guildName string) error
+	SetGuildLanguage(ctx context.Context, discordGuildID, language string) error
+	SetGuildChannel(ctx context.Context, discordGuildID string, kind domain.NotificationChannel, channelID string) error
+	SetGuildPingRole(ctx context.Context, discordGuildID, roleID string, minLevel int) error
+	SetGuildPollInterval(ctx context.Context, discordGuildID string, interval time.Duration) error
+	SetGuildMutedUntil(ctx context.Context, discordGuildID string, until time.Time) error
+	SetGuildLowLevelDeaths(ctx context.Context, discordGuildID string, enabled bool) error
+	SetGuildShareRange(ctx context.Context, discordGuildID string, enabled bool) error
+	SetGuildDeathLocation(ctx context.Context, discordGuildID string, enabled bool) error
+	SetGuildLevelDowns(ctx context.Context, discordGuildID string, enabled bool) error
+	SetGuildResponseVisibility(ctx context.Context, discordGuildID string, visibility domain.ResponseVisibility) error
+	SetGuildDeathVerbosity(ctx context.Context, discordGuildID string, verbosity domain.DeathVerbosity) error
+	SetGuildBroadcastOptOut(ctx context.Context, discordGuildID string, optOut bool) error
+	SetGuildMinLevel(ctx context.Context, discordGuildID string, level int) error
+	SetGuildMassDeathAlert(ctx context.Context, discordGuildID string, alert domain.MassDeathAlert) error
+	SetGuildTimezone(ctx context.Context, discordGuildID, timezone string) error
+	SetGuildTemplate(ctx context.Context, discordGuildID string, kind domain.NotificationChannel, template string) error
+	SetGuildEmoji(ctx context.Context, discordGuildID string, kind domain.NotificationChannel, emoji string, react bool) error
+	SetGuildQuietHours(ctx context.Context, discordGuildID string, quiet domain.QuietHours) error
+	SetGuildQuota(ctx context.Context, discordGuildID string, quota domain.Quota) error
+	SetGuildPremium(ctx context.Context, discordGuildID string, premium bool) error
+	// SetDeathRoute sends the guild's deaths at or above minLevel to
+	// channelID, replacing any route with the same minLevel.
+	SetDeathRoute(ctx context.Context, discordGuildID string, minLevel int, channelID string) error
+	DeleteDeathRoute(ctx context.Context, discordGuildID string, minLevel int) (bool, error)
+	// SetSkillThreshold only announces the guild's advances of skill at or
+	// above minValue.
+	SetSkillThreshold(ctx context.Context, discordGuildID string, skill domain.Skill, minValue int) error
+	DeleteSkillThreshold(ctx context.Context, discordGuildID string, skill domain.Skill) (bool, error)
+	SetGuildLastNotified(ctx context.Context, discordGuildID string, at time.Time) error
+	// MarkGuildRemoved hides the guild's configuration from GetAllGuildConfigs
+	// until RestoreGuildConfig or DeleteRemovedGuildConfigs.
+	MarkGuildRemoved(ctx context.Context, discordGuildID string, at time.Time) error
+	RestoreGuildConfig(ctx context.Context, discordGuildID string) (bool, error)
+	DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error)
+	// SaveStoppedGuildConfig keeps the snapshot of a guild that stopped
+	// tracking, replacing any older one, until DeleteStoppedGuildConfig or
+	// DeleteStoppedGuildConfigs.
+	SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error
+	// GetStoppedGuildConfig returns nil when the guild has no snapshot.
+	GetStoppedGuildConfig(ctx context.Context, discordGuildID string) (*domain.StoppedGuildConfig, error)
+	DeleteStoppedGuildConfig(ctx context.Context, discordGuildID string) error
+	DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error)
+	// PurgeGuildData deletes the guild's configuration, including a stopped
+	// one, queued notifications and any tracking history no other guild still
+	// uses.
+	PurgeGuildData(ctx context.Context, discordGuildID string) (domain.PurgeResult, error)
+	AddIgnoredPlayer(ctx context.Context, discordGuildID, name string) error
+	RemoveIgnoredPlayer(ctx context.Context, discordGuildID, name string) error
+	AddWatchedPlayer(ctx context.Context, discordGuildID, name string) error
+	RemoveWatchedPlayer(ctx context.Context, discordGuildID, name string) error
 
 	UpsertPlayerLevel(ctx context.Context, name string, level int, world string) error
+	BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error
 	GetPlayersLevels(ctx context.Context, world string) (map[string]int, error)
+	// GetPlayersByPrefix returns up to domain.MaxNameSuggestions names of
+	// characters stored on world starting with prefix, ignoring case, in
+	// alphabetical order.
+	GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error)
 	GetOfflinePlayers(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error)
+	// RenamePlayer moves everything stored under a character's old name,
+	// such as its level, deaths, level history, guild memberships, ignore
+	// and watch entries, to its new name.
+	RenamePlayer(ctx context.Context, oldName, newName string) error
+
+	RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error
+	CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error)
+	GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error)
+	// GetTopKillersSince ranks player killers of deaths on world. When
+	// guildNames is set, only deaths of members of those Tibia guilds count.
+	GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error)
+	// GetFragCountsSince ranks characters of world by their frags, the deaths
+	// of others outside guildNames they took part in as player killers, and
+	// counts their own deaths to players. When guildNames is not empty, only
+	// their members are ranked. When name is not empty, only that character
+	// is.
+	GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error)
+	DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error)
+	// GetDeathsPage returns up to limit deaths on world since the given time
+	// with an ID above afterID, in ID order, so callers page through them by
+	// passing the last ID they got. With guildNames, only deaths of their
+	// members are returned.
+	GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error)
+
+	// RecordOnlinePresence notes that the characters were online on world
+	// during the hour containing at.
+	RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error
+	// GetOnlineCountsSince counts the characters seen online on world in each
+	// hour since the given time, oldest first. With guildNames, only members
+	// of those Tibia guilds count.
+	GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error)
+	DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error)
+	// RecordOnlineCount stores how many characters were online on world in
+	// the cycle at the given time.
+	RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error
+	// GetWorldOnlineCountsSince averages the characters online on world in
+	// each hour since the given time, oldest first.
+	GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error)
+	// DownsampleOnlineCounts folds the cycle counts taken before
+	// sampledBefore, which must fall on the hour, into hourly averages and
+	// returns how many hourly counts it wrote.
+	DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error)
+	DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error)
+	// RecordOnlineSessions extends to at the online sessions of the
+	// characters last seen at or after openSince, and starts one for each
+	// other character in names. A session ends at the last cycle its
+	// character was seen in.
+	RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error
+	// GetOnlineTimeSince sums how long the character was seen online since
+	// the given time.
+	GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error)
+	DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error)
+
+	// RecordLevelUp stores the level up at its ReachedAt, or now when that
+	// is zero.
+	RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error
+	// GetLevelUpsSince returns the character's level ups, oldest first.
+	GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error)
+	// GetDailyLevelGains returns the character's net levels gained per UTC
+	// day, oldest first. Days without level ups are left out.
+	GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error)
+	DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error)
+	// GetLevelUpsPage pages through level ups on world like GetDeathsPage.
+	GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error)
+
+	// GetPlayerSkills returns the last value of skill seen for each character
+	// on world.
+	GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error)
+	SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error
+
+	// GetHouseAuctions returns the auctions stored for world that were still
+	// running at seenSince or later.
+	GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error)
+	// ReplaceHouseAuctions stores auctions as the ones running on world.
+	ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error
+
+	GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error)
+	AddGuildMembers(ctx context.Context, guildName string, names []string) error
+	RemoveGuildMembers(ctx context.Context, guildName string, names []string) error
+	// GetGuildMemberCache returns the last fetched member list of every
+	// cached Tibia guild.
+	GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error)
+	SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error
+	// DeleteGuildMemberCacheExcept forgets the cached lists of guilds not in
+	// keep.
+	DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error)
+
+	// GetGuildWar returns the last war the Tibia guild was seen in, or nil
+	// when it never was.
+	GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error)
+	StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error
+	// EndGuildWar ends the guild's ongoing war, if any.
+	EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error
+	// CountKillsBetween counts deaths on world in [since, until) of victims
+	// with one of killers among their player killers.
+	CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error)
+
+	EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error
+	GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error)
+	GetGuildFailedNotifications(ctx context.Context, discordGuildID string) ([]domain.FailedNotification, error)
+	RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error
+	DeleteFailedNotification(ctx context.Context, id int64) error
+	DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error)
 
 	BatchTouchPlayers(ctx context.Context, names []string) error
 	DeleteOldPlayers(ctx context.Context, world string, maxAge time.Duration) (int64, error)
+
+	// BeginTx starts a transaction. Calling it on a RepositoryTx starts a
+	// nested one that commits into its parent.
+	BeginTx(ctx context.Context) (RepositoryTx, error)
 	Close()
 }
 
+// RepositoryTx is a Repository whose writes are applied together on Commit
+// or not at all. Rollback after Commit does nothing, so it can be deferred
+// right after BeginTx. Close does not end the transaction.
+type RepositoryTx interface {
+	Repository
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
 type TibiaFetcher interface {
 	FetchWorld(ctx context.Context, world string) ([]domain.Player, error)
 	FetchGuildMembers(ctx context.Context, guildName string) ([]string, error)
+	FetchGuild(ctx context.Context, guildName string) (*domain.Guild, error)
+	// FetchWorldGuilds lists the names of every guild on world, including
+	// guilds still in formation.
+	FetchWorldGuilds(ctx context.Context, world string) ([]string, error)
 	FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error)
 	FetchCharacter(ctx context.Context, name string) (*domain.Player, error)
 	FetchWorldFromTibiaCom(ctx context.Context, world string) (map[string]int, error)
+	// FetchWorldPvPType returns world's PvP type, such as "Open PvP".
+	FetchWorldPvPType(ctx context.Context, world string) (string, error)
+	// FetchOnlinePlayers lists world's online players from the named level
+	// source. Failures wrapping domain.ErrSourceUnavailable leave the next
+	// configured source worth trying.
+	FetchOnlinePlayers(ctx context.Context, source, world string) (*domain.OnlineList, error)
+	// FetchHouseAuctions lists the houses and guildhalls of every town on
+	// world that are currently auctioned.
+	FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error)
+	// FetchSkillHighscores returns one page, starting at 1, of world's
+	// highscores for skill.
+	FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error)
+	// FetchBoosted returns today's boosted creature and boss.
+	FetchBoosted(ctx context.Context) (*domain.Boosted, error)
 }
 
 type NotificationService interface {
-	SendLevelUpNotification(guildID string, levelUp domain.LevelUp) error
-	SendDeathNotification(guildID string, playerName string, kill domain.Kill) error
+	SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error
+	// SendLevelDownNotification announces a significant level drop to the
+	// guild's level channel.
+	SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error
+	SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error
+	SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error
+	// SendMassDeathNotification warns of a possible war or raid after many
+	// tracked characters died close together.
+	SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error
+	SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error
+	// SendGuildWarNotification announces that a tracked Tibia guild entered
+	// a war, or left it when the war has ended, to the guild's death channel.
+	SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error
+	// SendHouseAuctionNotification announces a new auction, or its end when
+	// ended is set, to the guild's house channel.
+	SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error
+	// SendCharacterChangeNotification announces a rename or world transfer
+	// of a tracked character to the guild's misc channel.
+	SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error
+	// SendRashidNotification posts the city Rashid is in today to the guild's
+	// misc channel.
+	SendRashidNotification(guild domain.GuildConfig, city string) error
+	// SendBoostedNotification posts today's boosted creature and boss to the
+	// guild's misc channel.
+	SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error
+	// SendCatchUpNotification posts the deaths and level ups held back during
+	// the guild's quiet hours.
+	SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error
+	// SendSkillAdvanceNotification announces a watched character's skill
+	// advance to the guild's skill channel.
+	SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error
 	SendGenericMessage(guildID string, channelName string, message string) error
 }
+
+// LeaderElector decides which bot replica runs the tracker. IsLeader acquires
+// leadership if it is free and reports whether this instance currently holds it.
+type LeaderElector interface {
+	IsLeader(ctx context.Context) bool
+	Release(ctx context.Context)
+}
+
+// Capabilities decides which premium-gated features a guild gets. Restrict
+// returns the config without the settings the guild's tier does not allow.
+type Capabilities interface {
+	Restrict(cfg domain.GuildConfig) domain.GuildConfig
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"death-level-tracker/internal/core/ports"
+)
+
+// BackfillService seeds stored player levels from a Tibia guild's roster so
+// level-ups are detected before members are first seen online.
+type BackfillService struct {
+	repo     ports.Repository
+	fetcher  ports.TibiaFetcher
+	minLevel int
+}
+
+func NewBackfillService(repo ports.Repository, fetcher ports.TibiaFetcher, minLevel int) *BackfillService {
+	return &BackfillService{
+		repo:     repo,
+		fetcher:  fetcher,
+		minLevel: minLevel,
+	}
+}
+
+// SyncGuild stores the current level of every member at or above the minimum
+// tracked level and returns how many players were seeded.
+func (s *BackfillService) SyncGuild(ctx context.Context, guildName string) (int, error) {
+	guild, err := s.fetcher.FetchGuild(ctx, guildName)
+	if err != nil {
+		return 0, fmt.Errorf("fetch guild: %w", err)
+	}
+
+	seeded := 0
+	for _, member := range guild.Members {
+		if member.Level < s.minLevel {
+			continue
+		}
+		if err := s.repo.UpsertPlayerLevel(ctx, member.Name, member.Level, guild.World); err != nil {
+			slog.ErrorContext(ctx, "Failed to seed player level", "guild", guildName, "name", member.Name, "error", err)
+			continue
+		}
+		seeded++
+	}
+
+	slog.InfoContext(ctx, "Guild backfill finished", "guild", guildName, "world", guild.World, "members", len(guild.Members), "seeded", seeded)
+	return seeded, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+type mockFetcher struct {
+	ports.TibiaFetcher
+	fetchGuildFunc     func(ctx context.Context, name string) (*domain.Guild, error)
+	fetchCharacterFunc func(ctx context.Context, name string) (*domain.Player, error)
+	fetchHousesFunc    func(ctx context.Context, world string) ([]domain.HouseAuction, error)
+	fetchGuildsFunc    func(ctx context.Context, world string) ([]string, error)
+	fetchSkillsFunc    func(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error)
+	fetchBoostedFunc   func(ctx context.Context) (*domain.Boosted, error)
+	fetchPvPTypeFunc   func(ctx context.Context, world string) (string, error)
+}
+
+func (m *mockFetcher) FetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	return m.fetchCharacterFunc(ctx, name)
+}
+
+func (m *mockFetcher) FetchGuild(ctx context.Context, name string) (*domain.Guild, error) {
+	if m.fetchGuildFunc != nil {
+		return m.fetchGuildFunc(ctx, name)
+	}
+	return &domain.Guild{Name: name}, nil
+}
+
+func (m *mockFetcher) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	return m.fetchGuildsFunc(ctx, world)
+}
+
+func (m *mockFetcher) FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+	return m.fetchHousesFunc(ctx, world)
+}
+
+func (m *mockFetcher) FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+	return m.fetchSkillsFunc(ctx, world, skill, page)
+}
+
+func (m *mockFetcher) FetchBoosted(ctx context.Context) (*domain.Boosted, error) {
+	return m.fetchBoostedFunc(ctx)
+}
+
+func (m *mockFetcher) FetchWorldPvPType(ctx context.Context, world string) (string, error) {
+	return m.fetchPvPTypeFunc(ctx, world)
+}
+
+func TestSyncGuild_SeedsMembersAboveMinLevel(t *testing.T) {
+	seeded := make(map[string]int)
+	var seededWorld string
+	repo := &mockRepository{
+		upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+			seeded[name] = level
+			seededWorld = world
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{
+				Name:  name,
+				World: "Antica",
+				Members: []domain.Player{
+					{Name: "High", Level: 600},
+					{Name: "Exact", Level: 500},
+					{Name: "Low", Level: 120},
+				},
+			}, nil
+		},
+	}
+
+	svc := NewBackfillService(repo, fetcher, 500)
+	count, err := svc.SyncGuild(context.Background(), "Red Rose")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 seeded, got %d", count)
+	}
+	if seeded["High"] != 600 || seeded["Exact"] != 500 {
+		t.Errorf("unexpected seeded levels: %v", seeded)
+	}
+	if _, ok := seeded["Low"]; ok {
+		t.Error("expected low level member to be skipped")
+	}
+	if seededWorld != "Antica" {
+		t.Errorf("expected world 'Antica', got '%s'", seededWorld)
+	}
+}
+
+func TestSyncGuild_FetchError(t *testing.T) {
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return nil, errors.New("api error")
+		},
+	}
+
+	svc := NewBackfillService(&mockRepository{}, fetcher, 500)
+	if _, err := svc.SyncGuild(context.Background(), "Red Rose"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestSyncGuild_ContinuesOnUpsertError(t *testing.T) {
+	repo := &mockRepository{
+		upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+			if name == "Broken" {
+				return errors.New("db error")
+			}
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{
+				World:   "Antica",
+				Members: []domain.Player{{Name: "Broken", Level: 600}, {Name: "Fine", Level: 700}},
+			}, nil
+		},
+	}
+
+	svc := NewBackfillService(repo, fetcher, 500)
+	count, err := svc.SyncGuild(context.Background(), "Red Rose")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 seeded, got %d", count)
+	}
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// backupVersion is bumped whenever the backup file layout changes in a way
+// older builds cannot restore.
+const backupVersion = 1
+
+// ErrRestoreNotEmpty means the target storage already has guilds configured.
+// Restoring on top of them would duplicate level history.
+var ErrRestoreNotEmpty = errors.New("storage already has guilds configured")
+
+// BackupSummary counts what a backup wrote or a restore read.
+type BackupSummary struct {
+	Guilds   int
+	Players  int
+	Members  int
+	Deaths   int
+	LevelUps int
+}
+
+// backupFile is the JSON document written by Backup. It only uses the
+// storage port, so a backup taken from one storage backend restores into
+// another.
+type backupFile struct {
+	Version      int                 `json:"version"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Guilds       []backupGuild       `json:"guilds"`
+	GuildMembers map[string][]string `json:"guild_members"`
+	Players      []backupPlayer      `json:"players"`
+	Deaths       []exportedDeath     `json:"deaths"`
+	LevelUps     []exportedLevelUp   `json:"level_ups"`
+}
+
+type backupGuild struct {
+	DiscordGuildID string             `json:"discord_guild_id"`
+	World          string             `json:"world"`
+	TibiaGuilds    []string           `json:"tibia_guilds,omitempty"`
+	Language       string             `json:"language,omitempty"`
+	Channels       map[string]string  `json:"channels,omitempty"`
+	PingRoleID     string             `json:"ping_role_id,omitempty"`
+	PingMinLevel   int                `json:"ping_min_level,omitempty"`
+	MinLevel       int                `json:"min_level,omitempty"`
+	PollInterval   string             `json:"poll_interval,omitempty"`
+	MutedUntil     time.Time          `json:"muted_until,omitzero"`
+	IgnoredPlayers []string           `json:"ignored_players,omitempty"`
+	LowLevelDeaths bool               `json:"low_level_deaths"`
+	ShareRange     bool               `json:"share_range,omitempty"`
+	DeathLocation  bool               `json:"death_location,omitempty"`
+	LevelDowns     bool               `json:"level_downs,omitempty"`
+	NoBroadcasts   bool               `json:"broadcast_opt_out,omitempty"`
+	LastNotifiedAt time.Time          `json:"last_notified_at,omitzero"`
+	Timezone       string             `json:"timezone,omitempty"`
+	Templates      map[string]string  `json:"templates,omitempty"`
+	Emojis         map[string]string  `json:"emojis,omitempty"`
+	Reactions      map[string]bool    `json:"reactions,omitempty"`
+	DeathRoutes    []backupDeathRoute `json:"death_routes,omitempty"`
+	QuietHours     *backupQuietHours  `json:"quiet_hours,omitempty"`
+	Quota          *backupQuota       `json:"quota,omitempty"`
+	Premium        bool               `json:"premium,omitempty"`
+	WatchedPlayers []string           `json:"watched_players,omitempty"`
+	SkillMinimums  map[string]int     `json:"skill_thresholds,omitempty"`
+	MassDeath      *backupMassDeath   `json:"mass_death_alert,omitempty"`
+	Visibility     string             `json:"response_visibility,omitempty"`
+	Verbosity      string             `json:"death_verbosity,omitempty"`
+}
+
+type backupDeathRoute struct {
+	MinLevel  int    `json:"min_level"`
+	ChannelID string `json:"channel_id"`
+}
+
+type backupQuietHours struct {
+	Start   int  `json:"start"`
+	End     int  `json:"end"`
+	CatchUp bool `json:"catch_up"`
+}
+
+type backupMassDeath struct {
+	Deaths int    `json:"deaths"`
+	Window string `json:"window"`
+}
+
+type backupQuota struct {
+	TibiaGuilds    int `json:"tibia_guilds"`
+	IgnoredPlayers int `json:"ignored_players"`
+	WatchedPlayers int `json:"watched_players,omitempty"`
+}
+
+type backupPlayer struct {
+	Name  string `json:"name"`
+	World string `json:"world"`
+	Level int    `json:"level"`
+}
+
+// BackupService copies guild configs, player levels, Tibia guild rosters and
+// the death and level up history to and from a JSON file. Queued
+// notifications and house auctions are left out; the bot rebuilds both.
+type BackupService struct {
+	repo ports.Repository
+	now  func() time.Time
+}
+
+func NewBackupService(repo ports.Repository) *BackupService {
+	return &BackupService{repo: repo, now: time.Now}
+}
+
+// Backup writes everything stored for the configured guilds' worlds to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) (BackupSummary, error) {
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		return BackupSummary{}, fmt.Errorf("get guild configs: %w", err)
+	}
+
+	file := backupFile{
+		Version:      backupVersion,
+		CreatedAt:    s.now().UTC(),
+		Guilds:       make([]backupGuild, 0, len(configs)),
+		GuildMembers: make(map[string][]string),
+	}
+	var worlds []string
+	for _, cfg := range configs {
+		file.Guilds = append(file.Guilds, newBackupGuild(cfg))
+		if cfg.World != "" && !slices.Contains(worlds, cfg.World) {
+			worlds = append(worlds, cfg.World)
+		}
+		for _, guildName := range cfg.TibiaGuilds {
+			if _, ok := file.GuildMembers[guildName]; ok {
+				continue
+			}
+			members, err := s.repo.GetGuildMemberNames(ctx, guildName)
+			if err != nil {
+				return BackupSummary{}, fmt.Errorf("get %s members: %w", guildName, err)
+			}
+			file.GuildMembers[guildName] = members
+		}
+	}
+	slices.Sort(worlds)
+
+	for _, world := range worlds {
+		levels, err := s.repo.GetPlayersLevels(ctx, world)
+		if err != nil {
+			return BackupSummary{}, fmt.Errorf("get %s levels: %w", world, err)
+		}
+		players := make([]backupPlayer, 0, len(levels))
+		for name, level := range levels {
+			players = append(players, backupPlayer{Name: name, World: world, Level: level})
+		}
+		slices.SortFunc(players, func(a, b backupPlayer) int { return strings.Compare(a.Name, b.Name) })
+		file.Players = append(file.Players, players...)
+
+		if err := allPages(func(afterID int64) ([]domain.DeathRecord, error) {
+			return s.repo.GetDeathsPage(ctx, world, nil, time.Time{}, afterID, exportPageSize)
+		}, func(d domain.DeathRecord) int64 {
+			file.Deaths = append(file.Deaths, exportedDeath{ID: d.ID, Name: d.Name, World: d.World, Level: d.Level, Reason: d.Reason, Killers: d.Killers, DiedAt: d.DiedAt.UTC()})
+			return d.ID
+		}); err != nil {
+			return BackupSummary{}, fmt.Errorf("get %s deaths: %w", world, err)
+		}
+		if err := allPages(func(afterID int64) ([]domain.LevelUpRecord, error) {
+			return s.repo.GetLevelUpsPage(ctx, world, nil, time.Time{}, afterID, exportPageSize)
+		}, func(l domain.LevelUpRecord) int64 {
+			file.LevelUps = append(file.LevelUps, exportedLevelUp{ID: l.ID, Name: l.Name, World: l.World, OldLevel: l.OldLevel, NewLevel: l.NewLevel, ReachedAt: l.ReachedAt.UTC()})
+			return l.ID
+		}); err != nil {
+			return BackupSummary{}, fmt.Errorf("get %s level ups: %w", world, err)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return BackupSummary{}, fmt.Errorf("write backup: %w", err)
+	}
+	return file.summary(), nil
+}
+
+// Restore reads a file written by Backup into empty storage.
+func (s *BackupService) Restore(ctx context.Context, r io.Reader) (BackupSummary, error) {
+	var file backupFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return BackupSummary{}, fmt.Errorf("read backup: %w", err)
+	}
+	if file.Version != backupVersion {
+		return BackupSummary{}, fmt.Errorf("unsupported backup version %d", file.Version)
+	}
+
+	existing, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		return BackupSummary{}, fmt.Errorf("get guild configs: %w", err)
+	}
+	if len(existing) > 0 {
+		return BackupSummary{}, ErrRestoreNotEmpty
+	}
+
+	for _, guild := range file.Guilds {
+		if err := restoreGuild(ctx, s.repo, guild); err != nil {
+			return BackupSummary{}, fmt.Errorf("restore guild %s: %w", guild.DiscordGuildID, err)
+		}
+	}
+	for guildName, members := range file.GuildMembers {
+		if err := s.repo.AddGuildMembers(ctx, guildName, members); err != nil {
+			return BackupSummary{}, fmt.Errorf("restore %s members: %w", guildName, err)
+		}
+	}
+
+	levels := make([]domain.PlayerLevel, 0, len(file.Players))
+	for _, p := range file.Players {
+		levels = append(levels, domain.PlayerLevel{Name: p.Name, Level: p.Level, World: p.World})
+	}
+	for start := 0; start < len(levels); start += importBatchSize {
+		if err := s.repo.BatchUpsertPlayerLevels(ctx, levels[start:min(start+importBatchSize, len(levels))]); err != nil {
+			return BackupSummary{}, fmt.Errorf("restore players: %w", err)
+		}
+	}
+
+	for _, d := range file.Deaths {
+		kill := domain.Kill{Time: d.DiedAt, Level: d.Level, Reason: d.Reason}
+		for _, name := range d.Killers {
+			kill.Killers = append(kill.Killers, domain.Killer{Name: name, IsPlayer: true})
+		}
+		if err := s.repo.RecordDeath(ctx, d.Name, d.World, kill); err != nil {
+			return BackupSummary{}, fmt.Errorf("restore death of %s: %w", d.Name, err)
+		}
+	}
+	for _, l := range file.LevelUps {
+		levelUp := domain.LevelUp{PlayerName: l.Name, World: l.World, OldLevel: l.OldLevel, NewLevel: l.NewLevel, ReachedAt: l.ReachedAt}
+		if err := s.repo.RecordLevelUp(ctx, levelUp); err != nil {
+			return BackupSummary{}, fmt.Errorf("restore level up of %s: %w", l.Name, err)
+		}
+	}
+	return file.summary(), nil
+}
+
+// restoreGuild replays the guild's settings into repo through the same
+// setters the slash commands use, skipping the ones left at their defaults.
+func restoreGuild(ctx context.Context, repo ports.Repository, g backupGuild) error {
+	id := g.DiscordGuildID
+	if err := repo.SaveGuildWorld(ctx, id, g.World); err != nil {
+		return err
+	}
+	for _, guildName := range g.TibiaGuilds {
+		if err := repo.AddGuildToConfig(ctx, id, guildName); err != nil {
+			return err
+		}
+	}
+	for _, name := range g.IgnoredPlayers {
+		if err := repo.AddIgnoredPlayer(ctx, id, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range g.WatchedPlayers {
+		if err := repo.AddWatchedPlayer(ctx, id, name); err != nil {
+			return err
+		}
+	}
+	for skill, minValue := range g.SkillMinimums {
+		if err := repo.SetSkillThreshold(ctx, id, domain.Skill(skill), minValue); err != nil {
+			return err
+		}
+	}
+	for kind, channelID := range g.Channels {
+		if err := repo.SetGuildChannel(ctx, id, domain.NotificationChannel(kind), channelID); err != nil {
+			return err
+		}
+	}
+	for kind, template := range g.Templates {
+		if err := repo.SetGuildTemplate(ctx, id, domain.NotificationChannel(kind), template); err != nil {
+			return err
+		}
+	}
+	for _, kind := range []domain.NotificationChannel{domain.ChannelDeaths, domain.ChannelLevels} {
+		emoji, react := g.Emojis[string(kind)], g.Reactions[string(kind)]
+		if emoji == "" && !react {
+			continue
+		}
+		if err := repo.SetGuildEmoji(ctx, id, kind, emoji, react); err != nil {
+			return err
+		}
+	}
+	for _, route := range g.DeathRoutes {
+		if err := repo.SetDeathRoute(ctx, id, route.MinLevel, route.ChannelID); err != nil {
+			return err
+		}
+	}
+	if g.Language != "" {
+		if err := repo.SetGuildLanguage(ctx, id, g.Language); err != nil {
+			return err
+		}
+	}
+	if g.PingRoleID != "" {
+		if err := repo.SetGuildPingRole(ctx, id, g.PingRoleID, g.PingMinLevel); err != nil {
+			return err
+		}
+	}
+	if g.PollInterval != "" {
+		interval, err := time.ParseDuration(g.PollInterval)
+		if err != nil {
+			return fmt.Errorf("poll interval: %w", err)
+		}
+		if err := repo.SetGuildPollInterval(ctx, id, interval); err != nil {
+			return err
+		}
+	}
+	if !g.MutedUntil.IsZero() {
+		if err := repo.SetGuildMutedUntil(ctx, id, g.MutedUntil); err != nil {
+			return err
+		}
+	}
+	if err := repo.SetGuildLowLevelDeaths(ctx, id, g.LowLevelDeaths); err != nil {
+		return err
+	}
+	if g.MinLevel > 0 {
+		if err := repo.SetGuildMinLevel(ctx, id, g.MinLevel); err != nil {
+			return err
+		}
+	}
+	if g.ShareRange {
+		if err := repo.SetGuildShareRange(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	if g.DeathLocation {
+		if err := repo.SetGuildDeathLocation(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	if g.LevelDowns {
+		if err := repo.SetGuildLevelDowns(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	if g.Visibility != "" {
+		if err := repo.SetGuildResponseVisibility(ctx, id, domain.ResponseVisibility(g.Visibility)); err != nil {
+			return err
+		}
+	}
+	if g.Verbosity != "" {
+		if err := repo.SetGuildDeathVerbosity(ctx, id, domain.DeathVerbosity(g.Verbosity)); err != nil {
+			return err
+		}
+	}
+	if g.NoBroadcasts {
+		if err := repo.SetGuildBroadcastOptOut(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	if g.MassDeath != nil {
+		window, err := time.ParseDuration(g.MassDeath.Window)
+		if err != nil {
+			return fmt.Errorf("mass death window: %w", err)
+		}
+		if err := repo.SetGuildMassDeathAlert(ctx, id, domain.MassDeathAlert{Deaths: g.MassDeath.Deaths, Window: window}); err != nil {
+			return err
+		}
+	}
+	if g.Timezone != "" {
+		if err := repo.SetGuildTimezone(ctx, id, g.Timezone); err != nil {
+			return err
+		}
+	}
+	if g.QuietHours != nil {
+		quiet := domain.QuietHours{Start: g.QuietHours.Start, End: g.QuietHours.End, CatchUp: g.QuietHours.CatchUp}
+		if err := repo.SetGuildQuietHours(ctx, id, quiet); err != nil {
+			return err
+		}
+	}
+	if g.Quota != nil {
+		if err := repo.SetGuildQuota(ctx, id, domain.Quota{TibiaGuilds: g.Quota.TibiaGuilds, IgnoredPlayers: g.Quota.IgnoredPlayers, WatchedPlayers: g.Quota.WatchedPlayers}); err != nil {
+			return err
+		}
+	}
+	if g.Premium {
+		if err := repo.SetGuildPremium(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	if !g.LastNotifiedAt.IsZero() {
+		if err := repo.SetGuildLastNotified(ctx, id, g.LastNotifiedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBackupGuild(cfg domain.GuildConfig) backupGuild {
+	g := backupGuild{
+		DiscordGuildID: cfg.DiscordGuildID,
+		World:          cfg.World,
+		TibiaGuilds:    cfg.TibiaGuilds,
+		Language:       cfg.Language,
+		Channels:       make(map[string]string),
+		PingRoleID:     cfg.PingRoleID,
+		PingMinLevel:   cfg.PingMinLevel,
+		MinLevel:       cfg.MinLevel,
+		MutedUntil:     cfg.MutedUntil,
+		IgnoredPlayers: cfg.IgnoredPlayers,
+		LowLevelDeaths: cfg.LowLevelDeaths,
+		ShareRange:     cfg.ShareRange,
+		DeathLocation:  cfg.DeathLocation,
+		LevelDowns:     cfg.LevelDowns,
+		NoBroadcasts:   cfg.BroadcastOptOut,
+		LastNotifiedAt: cfg.LastNotifiedAt,
+		Timezone:       cfg.Timezone,
+		Templates:      make(map[string]string),
+		Emojis:         make(map[string]string),
+		Reactions:      make(map[string]bool),
+		Premium:        cfg.Premium,
+		WatchedPlayers: cfg.WatchedPlayers,
+		Visibility:     string(cfg.ResponseVisibility),
+		Verbosity:      string(cfg.DeathVerbosity),
+	}
+	if cfg.PollInterval > 0 {
+		g.PollInterval = cfg.PollInterval.String()
+	}
+	for _, route := range cfg.DeathRoutes {
+		g.DeathRoutes = append(g.DeathRoutes, backupDeathRoute{MinLevel: route.MinLevel, ChannelID: route.ChannelID})
+	}
+	for skill, minValue := range cfg.SkillThresholds {
+		if g.SkillMinimums == nil {
+			g.SkillMinimums = make(map[string]int)
+		}
+		g.SkillMinimums[string(skill)] = minValue
+	}
+	if q := cfg.Quota; q != (domain.Quota{}) {
+		g.Quota = &backupQuota{TibiaGuilds: q.TibiaGuilds, IgnoredPlayers: q.IgnoredPlayers, WatchedPlayers: q.WatchedPlayers}
+	}
+	if a := cfg.MassDeathAlert; a.Enabled() {
+		g.MassDeath = &backupMassDeath{Deaths: a.Deaths, Window: a.Window.String()}
+	}
+	if q := cfg.QuietHours; q.Enabled() {
+		g.QuietHours = &backupQuietHours{Start: q.Start, End: q.End, CatchUp: q.CatchUp}
+	}
+	setIf := func(m map[string]string, kind domain.NotificationChannel, value string) {
+		if value != "" {
+			m[string(kind)] = value
+		}
+	}
+	setIf(g.Channels, domain.ChannelDeaths, cfg.DeathChannelID)
+	setIf(g.Channels, domain.ChannelLevels, cfg.LevelChannelID)
+	setIf(g.Channels, domain.ChannelHouses, cfg.HouseChannelID)
+	setIf(g.Channels, domain.ChannelMisc, cfg.MiscChannelID)
+	setIf(g.Channels, domain.ChannelSkills, cfg.SkillChannelID)
+	setIf(g.Templates, domain.ChannelDeaths, cfg.DeathTemplate)
+	setIf(g.Templates, domain.ChannelLevels, cfg.LevelTemplate)
+	setIf(g.Templates, domain.ChannelLevelDowns, cfg.LevelDownTemplate)
+	setIf(g.Emojis, domain.ChannelDeaths, cfg.DeathEmoji)
+	setIf(g.Emojis, domain.ChannelLevels, cfg.LevelEmoji)
+	if cfg.DeathReaction {
+		g.Reactions[string(domain.ChannelDeaths)] = true
+	}
+	if cfg.LevelReaction {
+		g.Reactions[string(domain.ChannelLevels)] = true
+	}
+	return g
+}
+
+func (f backupFile) summary() BackupSummary {
+	members := 0
+	for _, names := range f.GuildMembers {
+		members += len(names)
+	}
+	return BackupSummary{
+		Guilds:   len(f.Guilds),
+		Players:  len(f.Players),
+		Members:  members,
+		Deaths:   len(f.Deaths),
+		LevelUps: len(f.LevelUps),
+	}
+}
+
+// allPages calls fetch with the last ID seen until a page comes back short.
+func allPages[T any](fetch func(afterID int64) ([]T, error), each func(T) int64) error {
+	var afterID int64
+	for {
+		page, err := fetch(afterID)
+		if err != nil {
+			return err
+		}
+		for _, item := range page {
+			afterID = each(item)
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
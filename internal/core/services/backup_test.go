@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestRestore_UnsupportedVersion(t *testing.T) {
+	_, err := NewBackupService(&mockRepository{}).Restore(context.Background(), strings.NewReader(`{"version": 99}`))
+	if err == nil || !strings.Contains(err.Error(), "unsupported backup version 99") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+}
+
+func TestRestore_NotEmpty(t *testing.T) {
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil
+		},
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			t.Error("expected nothing to be restored")
+			return nil
+		},
+	}
+
+	_, err := NewBackupService(repo).Restore(context.Background(), strings.NewReader(`{"version": 1, "guilds": [{"discord_guild_id": "g2", "world": "Antica"}]}`))
+	if !errors.Is(err, ErrRestoreNotEmpty) {
+		t.Errorf("expected ErrRestoreNotEmpty, got %v", err)
+	}
+}
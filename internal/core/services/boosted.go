@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// boostedPostDelay gives tibia.com and TibiaData's cache time to switch to
+// the new boosts after server save.
+const boostedPostDelay = 10 * time.Minute
+
+// BoostedService posts the boosted creature and boss of the day to every
+// tracking guild after each server save.
+type BoostedService struct {
+	repo     ports.Repository
+	fetcher  ports.TibiaFetcher
+	notifier ports.NotificationService
+	// leader gates posting when several replicas run; nil means always lead.
+	leader ports.LeaderElector
+	now    func() time.Time
+}
+
+func NewBoostedService(repo ports.Repository, fetcher ports.TibiaFetcher, notifier ports.NotificationService, leader ports.LeaderElector) *BoostedService {
+	return &BoostedService{
+		repo:     repo,
+		fetcher:  fetcher,
+		notifier: notifier,
+		leader:   leader,
+		now:      time.Now,
+	}
+}
+
+// Start waits for each server save and posts the day's boosts until ctx is
+// cancelled. Like Rashid's post, nothing is posted on startup.
+func (s *BoostedService) Start(ctx context.Context) {
+	for {
+		next := domain.NextServerSave(s.now()).Add(boostedPostDelay)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.PostDaily(ctx)
+		}
+	}
+}
+
+// PostDaily fetches today's boosted creature and boss once and sends them to
+// every guild tracking a world that is not muted.
+func (s *BoostedService) PostDaily(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs", "error", err)
+		return
+	}
+
+	now := s.now()
+	var guilds []domain.GuildConfig
+	for _, guild := range configs {
+		if guild.World != "" && !guild.IsMuted(now) {
+			guilds = append(guilds, guild)
+		}
+	}
+	if len(guilds) == 0 {
+		return
+	}
+
+	boosted, err := s.fetcher.FetchBoosted(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch boosted creature and boss", "error", err)
+		return
+	}
+
+	sent := 0
+	for _, guild := range guilds {
+		if err := s.notifier.SendBoostedNotification(guild, *boosted); err != nil {
+			slog.ErrorContext(ctx, "Failed to post boosted creature and boss", "guild_id", guild.DiscordGuildID, "error", err)
+			continue
+		}
+		sent++
+	}
+	slog.InfoContext(ctx, "Posted boosted creature and boss", "creature", boosted.Creature.Name, "boss", boosted.Boss.Name, "guilds", sent)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestBoostedService_PostDaily(t *testing.T) {
+	now := time.Date(2024, 12, 14, 12, 0, 0, 0, domain.ServerSaveLocation)
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{
+				{DiscordGuildID: "tracking", World: "Antica"},
+				{DiscordGuildID: "other", World: "Secura"},
+				{DiscordGuildID: "unconfigured"},
+				{DiscordGuildID: "muted", World: "Antica", MutedUntil: now.Add(time.Hour)},
+			}, nil
+		},
+	}
+	fetches := 0
+	fetcher := &mockFetcher{
+		fetchBoostedFunc: func(ctx context.Context) (*domain.Boosted, error) {
+			fetches++
+			return &domain.Boosted{Creature: domain.BoostedCreature{Name: "Dragon"}, Boss: domain.BoostedCreature{Name: "Ferumbras"}}, nil
+		},
+	}
+	posted := make(map[string]domain.Boosted)
+	notifier := &mockNotifier{
+		sendBoostedFunc: func(guild domain.GuildConfig, boosted domain.Boosted) error {
+			posted[guild.DiscordGuildID] = boosted
+			return nil
+		},
+	}
+
+	svc := NewBoostedService(repo, fetcher, notifier, nil)
+	svc.now = func() time.Time { return now }
+	svc.PostDaily(context.Background())
+
+	if fetches != 1 {
+		t.Errorf("expected the boosts to be fetched once, got %d", fetches)
+	}
+	if len(posted) != 2 || posted["tracking"].Creature.Name != "Dragon" || posted["other"].Boss.Name != "Ferumbras" {
+		t.Errorf("expected both tracking guilds to get the boosts, got %v", posted)
+	}
+}
+
+func TestBoostedService_PostDaily_FetchError(t *testing.T) {
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "tracking", World: "Antica"}}, nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchBoostedFunc: func(ctx context.Context) (*domain.Boosted, error) {
+			return nil, errors.New("upstream down")
+		},
+	}
+	notifier := &mockNotifier{
+		sendBoostedFunc: func(guild domain.GuildConfig, boosted domain.Boosted) error {
+			t.Error("expected nothing posted when the boosts cannot be fetched")
+			return nil
+		},
+	}
+
+	NewBoostedService(repo, fetcher, notifier, nil).PostDaily(context.Background())
+}
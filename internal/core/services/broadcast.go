@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// Broadcaster posts an operator announcement to a guild's death channel.
+type Broadcaster interface {
+	SendBroadcast(guild domain.GuildConfig, message string) error
+}
+
+// BroadcastResult counts where a broadcast went. Failed lists the Discord
+// guild IDs the message could not be delivered to.
+type BroadcastResult struct {
+	Sent     int
+	OptedOut int
+	Muted    int
+	Failed   []string
+}
+
+// BroadcastService sends operator announcements, such as maintenance notices,
+// to every configured guild.
+type BroadcastService struct {
+	repo   ports.Repository
+	sender Broadcaster
+	// interval is the pause between two guilds, keeping a broadcast to many
+	// guilds within Discord's rate limits.
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewBroadcastService(repo ports.Repository, sender Broadcaster, interval time.Duration) *BroadcastService {
+	return &BroadcastService{
+		repo:     repo,
+		sender:   sender,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Broadcast posts message to the death channel of every configured guild
+// that has neither opted out of announcements nor muted the bot, one guild
+// per interval. A failed guild does not stop the others. With dryRun nothing
+// is sent and the result counts the guilds that would receive it.
+func (s *BroadcastService) Broadcast(ctx context.Context, message string, dryRun bool) (BroadcastResult, error) {
+	var result BroadcastResult
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		return result, fmt.Errorf("get guild configs: %w", err)
+	}
+
+	var ticker *time.Ticker
+	if s.interval > 0 && !dryRun {
+		ticker = time.NewTicker(s.interval)
+		defer ticker.Stop()
+	}
+
+	now := s.now()
+	for _, cfg := range configs {
+		switch {
+		case cfg.BroadcastOptOut:
+			result.OptedOut++
+			continue
+		case cfg.IsMuted(now):
+			result.Muted++
+			continue
+		case dryRun:
+			result.Sent++
+			continue
+		}
+
+		if ticker != nil && result.Sent+len(result.Failed) > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-ticker.C:
+			}
+		}
+
+		if err := s.sender.SendBroadcast(cfg, message); err != nil {
+			slog.ErrorContext(ctx, "Failed to send broadcast", "guild_id", cfg.DiscordGuildID, "error", err)
+			result.Failed = append(result.Failed, cfg.DiscordGuildID)
+			continue
+		}
+		result.Sent++
+	}
+	return result, nil
+}
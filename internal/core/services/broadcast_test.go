@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+type mockBroadcaster struct {
+	sent []string
+	at   []time.Time
+	fail map[string]bool
+}
+
+func (b *mockBroadcaster) SendBroadcast(guild domain.GuildConfig, message string) error {
+	b.at = append(b.at, time.Now())
+	if b.fail[guild.DiscordGuildID] {
+		return errors.New("missing access")
+	}
+	b.sent = append(b.sent, guild.DiscordGuildID)
+	return nil
+}
+
+func TestBroadcastService_Broadcast(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{
+				{DiscordGuildID: "a", World: "Antica"},
+				{DiscordGuildID: "opted-out", World: "Antica", BroadcastOptOut: true},
+				{DiscordGuildID: "muted", World: "Antica", MutedUntil: now.Add(time.Hour)},
+				{DiscordGuildID: "broken", World: "Secura"},
+				{DiscordGuildID: "b"},
+			}, nil
+		},
+	}
+
+	t.Run("sends to every guild that did not opt out", func(t *testing.T) {
+		sender := &mockBroadcaster{fail: map[string]bool{"broken": true}}
+		svc := NewBroadcastService(repo, sender, 10*time.Millisecond)
+		svc.now = func() time.Time { return now }
+
+		result, err := svc.Broadcast(context.Background(), "Maintenance tonight", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(sender.sent, []string{"a", "b"}) {
+			t.Errorf("unexpected recipients: %v", sender.sent)
+		}
+		want := BroadcastResult{Sent: 2, OptedOut: 1, Muted: 1, Failed: []string{"broken"}}
+		if result.Sent != want.Sent || result.OptedOut != want.OptedOut || result.Muted != want.Muted || !slices.Equal(result.Failed, want.Failed) {
+			t.Errorf("expected %+v, got %+v", want, result)
+		}
+		for i := 1; i < len(sender.at); i++ {
+			if gap := sender.at[i].Sub(sender.at[i-1]); gap < 5*time.Millisecond {
+				t.Errorf("expected sends to be spaced by the interval, got %v", gap)
+			}
+		}
+	})
+
+	t.Run("dry run sends nothing", func(t *testing.T) {
+		sender := &mockBroadcaster{}
+		svc := NewBroadcastService(repo, sender, time.Hour)
+		svc.now = func() time.Time { return now }
+
+		result, err := svc.Broadcast(context.Background(), "Maintenance tonight", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sender.sent) != 0 || result.Sent != 3 {
+			t.Errorf("expected 3 recipients and nothing sent, got %+v (sent %v)", result, sender.sent)
+		}
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		sender := &mockBroadcaster{}
+		svc := NewBroadcastService(repo, sender, time.Hour)
+		svc.now = func() time.Time { return now }
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := svc.Broadcast(ctx, "Maintenance tonight", false)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline error, got %v", err)
+		}
+		if len(sender.sent) != 1 {
+			t.Errorf("expected only the first guild before cancellation, got %v", sender.sent)
+		}
+	})
+}
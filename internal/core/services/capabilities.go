@@ -0,0 +1,127 @@
+package services
+
+import (
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// Capability is a feature that, while premium gating is on, only premium
+// guilds get.
+type Capability string
+
+const (
+	// CapabilityFastPolling allows poll intervals below the free minimum.
+	CapabilityFastPolling Capability = "fast_polling"
+	// CapabilityExtraGuilds raises the Tibia guild limit to the premium one.
+	CapabilityExtraGuilds Capability = "extra_guilds"
+	// CapabilityCustomTemplates allows custom notification templates.
+	CapabilityCustomTemplates Capability = "custom_templates"
+)
+
+// premiumCapabilities are the capabilities gating holds back from free
+// guilds. Any other capability is open to every guild.
+var premiumCapabilities = map[Capability]bool{
+	CapabilityFastPolling:     true,
+	CapabilityExtraGuilds:     true,
+	CapabilityCustomTemplates: true,
+}
+
+// Premium configures the premium tier. With Gating off every guild gets
+// every capability, so self-hosted instances lose nothing.
+type Premium struct {
+	Gating bool
+	// Guilds are premium whatever their stored flag says.
+	Guilds []string
+	// FreeMinPollInterval is the shortest poll interval free guilds may set.
+	FreeMinPollInterval time.Duration
+	// DefaultPollInterval and WorldPollIntervals are how often worlds are
+	// polled for guilds without an interval of their own, so free guilds
+	// are held to the minimum on them too.
+	DefaultPollInterval time.Duration
+	WorldPollIntervals  map[string]time.Duration
+	// TibiaGuilds replaces Limits.TibiaGuilds for premium guilds; zero means
+	// no limit. A guild's domain.Quota still overrides it.
+	TibiaGuilds int
+}
+
+// CapabilityService decides which gated features a guild gets. Command
+// handlers consult it before saving a setting and the tracker before using
+// one, so settings saved while a guild was premium stop applying once it is
+// not. A nil *CapabilityService gates nothing.
+type CapabilityService struct {
+	premium   Premium
+	allowlist map[string]bool
+}
+
+func NewCapabilityService(premium Premium) *CapabilityService {
+	allowlist := make(map[string]bool, len(premium.Guilds))
+	for _, id := range premium.Guilds {
+		allowlist[id] = true
+	}
+	return &CapabilityService{premium: premium, allowlist: allowlist}
+}
+
+// IsPremium reports whether the guild is premium, by its flag or the
+// allowlist.
+func (s *CapabilityService) IsPremium(cfg domain.GuildConfig) bool {
+	if s == nil {
+		return cfg.Premium
+	}
+	return cfg.Premium || s.allowlist[cfg.DiscordGuildID]
+}
+
+// Has reports whether the guild gets the capability.
+func (s *CapabilityService) Has(cfg domain.GuildConfig, c Capability) bool {
+	if s == nil || !s.premium.Gating || !premiumCapabilities[c] {
+		return true
+	}
+	return s.IsPremium(cfg)
+}
+
+// MinPollInterval is the shortest poll interval the guild may set.
+func (s *CapabilityService) MinPollInterval(cfg domain.GuildConfig) time.Duration {
+	if s.Has(cfg, CapabilityFastPolling) {
+		return 0
+	}
+	return s.premium.FreeMinPollInterval
+}
+
+// TibiaGuildLimit returns the guild's limit on tracked Tibia guilds given the
+// bot-wide one, before its quota applies.
+func (s *CapabilityService) TibiaGuildLimit(cfg domain.GuildConfig, limit int) int {
+	if s == nil || !s.premium.Gating || !s.Has(cfg, CapabilityExtraGuilds) {
+		return limit
+	}
+	return s.premium.TibiaGuilds
+}
+
+// Restrict returns cfg without the gated settings the guild does not get:
+// poll intervals are raised to the free minimum and custom templates are
+// dropped in favour of the default messages. While gating is on, guilds
+// without an interval of their own get the default one spelled out, so it is
+// held to the minimum too and a free guild's raised interval never slows
+// the premium guilds sharing its world.
+func (s *CapabilityService) Restrict(cfg domain.GuildConfig) domain.GuildConfig {
+	if cfg.PollInterval <= 0 && s != nil && s.premium.Gating {
+		cfg.PollInterval = s.defaultPollInterval(cfg.World)
+	}
+	if floor := s.MinPollInterval(cfg); cfg.PollInterval < floor {
+		cfg.PollInterval = floor
+	}
+	if !s.Has(cfg, CapabilityCustomTemplates) {
+		cfg.DeathTemplate = ""
+		cfg.LevelTemplate = ""
+		cfg.LevelDownTemplate = ""
+	}
+	return cfg
+}
+
+// defaultPollInterval is how often world is polled for guilds without an
+// interval of their own.
+func (s *CapabilityService) defaultPollInterval(world string) time.Duration {
+	if d, ok := s.premium.WorldPollIntervals[world]; ok {
+		return d
+	}
+	return s.premium.DefaultPollInterval
+}
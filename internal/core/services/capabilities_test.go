@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestCapabilityService(t *testing.T) {
+	caps := NewCapabilityService(Premium{
+		Gating:              true,
+		Guilds:              []string{"listed"},
+		FreeMinPollInterval: 15 * time.Minute,
+		TibiaGuilds:         10,
+	})
+	free := domain.GuildConfig{DiscordGuildID: "free", PollInterval: 2 * time.Minute, DeathTemplate: "{player} died", LevelDownTemplate: "{player} fell to {level}"}
+	flagged := domain.GuildConfig{DiscordGuildID: "flagged", Premium: true, PollInterval: 2 * time.Minute, DeathTemplate: "{player} died"}
+	listed := domain.GuildConfig{DiscordGuildID: "listed"}
+
+	if caps.IsPremium(free) || !caps.IsPremium(flagged) || !caps.IsPremium(listed) {
+		t.Error("expected the flag or the allowlist to make a guild premium")
+	}
+	if caps.Has(free, CapabilityCustomTemplates) || !caps.Has(listed, CapabilityCustomTemplates) {
+		t.Error("expected only premium guilds to get custom templates")
+	}
+	if got := caps.MinPollInterval(free); got != 15*time.Minute {
+		t.Errorf("expected free guilds to poll at most every 15m, got %v", got)
+	}
+	if got := caps.MinPollInterval(flagged); got != 0 {
+		t.Errorf("expected no minimum for premium guilds, got %v", got)
+	}
+	if got := caps.TibiaGuildLimit(free, 3); got != 3 {
+		t.Errorf("expected the free limit of 3, got %d", got)
+	}
+	if got := caps.TibiaGuildLimit(listed, 3); got != 10 {
+		t.Errorf("expected the premium limit of 10, got %d", got)
+	}
+
+	restricted := caps.Restrict(free)
+	if restricted.PollInterval != 15*time.Minute || restricted.DeathTemplate != "" || restricted.LevelDownTemplate != "" {
+		t.Errorf("expected free settings to be restricted, got %+v", restricted)
+	}
+	if kept := caps.Restrict(flagged); kept.PollInterval != 2*time.Minute || kept.DeathTemplate == "" {
+		t.Errorf("expected premium settings to be kept, got %+v", kept)
+	}
+}
+
+func TestCapabilityService_RestrictDefaultPollInterval(t *testing.T) {
+	caps := NewCapabilityService(Premium{
+		Gating:              true,
+		FreeMinPollInterval: 15 * time.Minute,
+		DefaultPollInterval: 5 * time.Minute,
+		WorldPollIntervals:  map[string]time.Duration{"Secura": 30 * time.Minute},
+	})
+
+	tests := []struct {
+		name string
+		cfg  domain.GuildConfig
+		want time.Duration
+	}{
+		{"Free guild on the default", domain.GuildConfig{World: "Antica"}, 15 * time.Minute},
+		{"Free guild on a slower world", domain.GuildConfig{World: "Secura"}, 30 * time.Minute},
+		{"Premium guild on the default", domain.GuildConfig{World: "Antica", Premium: true}, 5 * time.Minute},
+		{"Premium guild with its own interval", domain.GuildConfig{World: "Antica", Premium: true, PollInterval: 2 * time.Minute}, 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := caps.Restrict(tt.cfg).PollInterval; got != tt.want {
+				t.Errorf("expected a poll interval of %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCapabilityService_HasUngated(t *testing.T) {
+	caps := NewCapabilityService(Premium{Gating: true})
+	free := domain.GuildConfig{DiscordGuildID: "free"}
+
+	if caps.Has(free, CapabilityFastPolling) || caps.Has(free, CapabilityExtraGuilds) {
+		t.Error("expected free guilds to miss the premium capabilities")
+	}
+	if !caps.Has(free, Capability("unknown")) {
+		t.Error("expected capabilities outside the premium tier to be open to every guild")
+	}
+}
+
+func TestCapabilityService_NoGating(t *testing.T) {
+	free := domain.GuildConfig{DiscordGuildID: "free", PollInterval: time.Minute, LevelTemplate: "{player} is {level}"}
+	for name, caps := range map[string]*CapabilityService{
+		"off": NewCapabilityService(Premium{FreeMinPollInterval: 15 * time.Minute, TibiaGuilds: 10}),
+		"nil": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if !caps.Has(free, CapabilityFastPolling) || caps.MinPollInterval(free) != 0 {
+				t.Error("expected every capability without gating")
+			}
+			if got := caps.TibiaGuildLimit(free, 3); got != 3 {
+				t.Errorf("expected the bot-wide limit without gating, got %d", got)
+			}
+			if got := caps.Restrict(free); got.PollInterval != time.Minute || got.LevelTemplate == "" {
+				t.Errorf("expected nothing restricted, got %+v", got)
+			}
+			if got := caps.Restrict(domain.GuildConfig{}); got.PollInterval != 0 {
+				t.Errorf("expected the default poll interval left unset, got %v", got.PollInterval)
+			}
+		})
+	}
+}
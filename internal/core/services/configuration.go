@@ -2,7 +2,14 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"death-level-tracker/internal/core/domain"
 	"death-level-tracker/internal/core/ports"
@@ -11,32 +18,730 @@ import (
 	"golang.org/x/text/language"
 )
 
+// ErrNoWorldTracked means the server has not chosen a world with
+// /track-world yet.
+var ErrNoWorldTracked = errors.New("no world tracked")
+
+// ErrNothingToResume means the server did not stop tracking within
+// domain.StoppedConfigRetention.
+var ErrNothingToResume = errors.New("nothing to resume")
+
+// ErrWorldTracked means the server tracks a world again, so resuming would
+// overwrite its new configuration.
+var ErrWorldTracked = errors.New("world already tracked")
+
+// maxDeathRoutes caps the level brackets a guild can route deaths by.
+const maxDeathRoutes = 5
+
+// ErrTooManyDeathRoutes means the guild already routes deaths by
+// maxDeathRoutes level brackets.
+var ErrTooManyDeathRoutes = fmt.Errorf("at most %d death routes", maxDeathRoutes)
+
+// MaxMinLevel caps a guild's minimum announced level.
+const MaxMinLevel = 5000
+
+// ErrInvalidMinLevel means a guild minimum level is negative or above
+// MaxMinLevel.
+var ErrInvalidMinLevel = fmt.Errorf("minimum level must be 0 to %d", MaxMinLevel)
+
+// ErrInvalidTimezone means a timezone is not a known IANA zone name such as
+// Europe/Warsaw.
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// ErrInvalidQuietHours means a quiet hours window is not two different
+// HH:MM times such as 02:00-08:00.
+var ErrInvalidQuietHours = errors.New("invalid quiet hours")
+
+// ErrInvalidVisibility means a response visibility is not one of the
+// domain.ResponseVisibility values.
+var ErrInvalidVisibility = errors.New("invalid response visibility")
+
+// ErrInvalidVerbosity means a death verbosity is not one of the
+// domain.DeathVerbosity values.
+var ErrInvalidVerbosity = errors.New("invalid death verbosity")
+
+// GuildWorldError rejects a Tibia guild that plays on another world than the
+// one the server tracks.
+type GuildWorldError struct {
+	Guild        string
+	World        string
+	TrackedWorld string
+}
+
+func (e *GuildWorldError) Error() string {
+	return fmt.Sprintf("guild %s is on %s, not %s", e.Guild, e.World, e.TrackedWorld)
+}
+
+// PlayerWorldError rejects a character that plays on another world than the
+// one the server tracks.
+type PlayerWorldError struct {
+	Player       string
+	World        string
+	TrackedWorld string
+}
+
+func (e *PlayerWorldError) Error() string {
+	return fmt.Sprintf("character %s is on %s, not %s", e.Player, e.World, e.TrackedWorld)
+}
+
+// Limits cap what one Discord guild can track, so a public instance cannot
+// be flooded by a single server. Zero means no limit. A guild's domain.Quota
+// overrides TibiaGuilds, IgnoredPlayers and WatchedPlayers.
+type Limits struct {
+	TibiaGuilds    int
+	IgnoredPlayers int
+	// WatchedPlayers caps the characters a guild watches skills of, since
+	// finding them may take several highscore pages per skill.
+	WatchedPlayers int
+	// Worlds caps the distinct worlds tracked by all guilds together, since
+	// every tracked world is polled.
+	Worlds int
+}
+
+// forGuild applies the guild's quota to the bot-wide limits.
+func (l Limits) forGuild(quota domain.Quota) Limits {
+	override := func(limit, quota int) int {
+		switch {
+		case quota < 0:
+			return 0
+		case quota > 0:
+			return quota
+		}
+		return limit
+	}
+	l.TibiaGuilds = override(l.TibiaGuilds, quota.TibiaGuilds)
+	l.IgnoredPlayers = override(l.IgnoredPlayers, quota.IgnoredPlayers)
+	l.WatchedPlayers = override(l.WatchedPlayers, quota.WatchedPlayers)
+	return l
+}
+
+// Resources capped by Limits.
+const (
+	LimitTibiaGuilds    = "tibia_guilds"
+	LimitIgnoredPlayers = "ignored_players"
+	LimitWatchedPlayers = "watched_players"
+	LimitWorlds         = "worlds"
+)
+
+// LimitError means a guild reached one of its Limits.
+type LimitError struct {
+	Resource string
+	Limit    int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("limit of %d %s reached", e.Limit, strings.ReplaceAll(e.Resource, "_", " "))
+}
+
+// worldPvPTypeTimeout keeps a slow TibiaData from holding up /track-status
+// for a detail it can do without. Once known, the fetcher answers from
+// memory.
+const worldPvPTypeTimeout = 5 * time.Second
+
+// worldGuildsTTL is how long a world's guild list is reused. Guilds are
+// founded and disbanded rarely, while autocomplete asks on every keystroke.
+const worldGuildsTTL = time.Hour
+
+type worldGuildsItem struct {
+	names     []string
+	expiresAt time.Time
+}
+
 type ConfigurationService struct {
-	repo ports.Repository
+	repo    ports.Repository
+	fetcher ports.TibiaFetcher
+	limits  Limits
+	caps    *CapabilityService
+
+	guildsMu    sync.Mutex
+	worldGuilds map[string]worldGuildsItem
 }
 
-func NewConfigurationService(repo ports.Repository) *ConfigurationService {
-	return &ConfigurationService{repo: repo}
+func NewConfigurationService(repo ports.Repository, fetcher ports.TibiaFetcher, limits Limits, caps *CapabilityService) *ConfigurationService {
+	return &ConfigurationService{
+		repo:        repo,
+		fetcher:     fetcher,
+		limits:      limits,
+		caps:        caps,
+		worldGuilds: make(map[string]worldGuildsItem),
+	}
 }
 
+// guildLimits resolves the limits of one guild: premium guilds get the
+// premium Tibia guild limit, then the guild's quota overrides both.
+func (s *ConfigurationService) guildLimits(cfg domain.GuildConfig) Limits {
+	limits := s.limits
+	limits.TibiaGuilds = s.caps.TibiaGuildLimit(cfg, limits.TibiaGuilds)
+	return limits.forGuild(cfg.Quota)
+}
+
+// SetWorld tracks the world for the guild. Starting to track a world no other
+// guild tracks fails with a *LimitError once Limits.Worlds are tracked.
 func (s *ConfigurationService) SetWorld(ctx context.Context, guildID, worldName string) (string, error) {
+	return s.setWorld(ctx, s.repo, guildID, worldName)
+}
+
+// TrackWorld sets the world like SetWorld, then runs prepare, such as
+// creating the notification channels, in the same transaction: when prepare
+// or the commit fails, nothing is saved. firstSetup reports whether the guild
+// tracked no world before.
+func (s *ConfigurationService) TrackWorld(ctx context.Context, guildID, worldName string, prepare func(ctx context.Context) error) (world string, firstSetup bool, err error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback(ctx)
+
+	cfg, err := tx.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return "", false, err
+	}
+	if world, err = s.setWorld(ctx, tx, guildID, worldName); err != nil {
+		return world, false, err
+	}
+	if err := prepare(ctx); err != nil {
+		return world, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return world, false, err
+	}
+	return world, cfg == nil || cfg.World == "", nil
+}
+
+func (s *ConfigurationService) setWorld(ctx context.Context, repo ports.Repository, guildID, worldName string) (string, error) {
 	formattedWorld := cases.Title(language.English).String(strings.ToLower(worldName))
-	err := s.repo.SaveGuildWorld(ctx, guildID, formattedWorld)
+	if err := s.checkWorldLimit(ctx, repo, guildID, formattedWorld); err != nil {
+		return formattedWorld, err
+	}
+	err := repo.SaveGuildWorld(ctx, guildID, formattedWorld)
 	return formattedWorld, err
 }
 
+func (s *ConfigurationService) checkWorldLimit(ctx context.Context, repo ports.Repository, guildID, world string) error {
+	if s.limits.Worlds <= 0 {
+		return nil
+	}
+	configs, err := repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	var worlds []string
+	for _, cfg := range configs {
+		if cfg.World == "" || cfg.DiscordGuildID == guildID || slices.Contains(worlds, cfg.World) {
+			continue
+		}
+		if cfg.World == world {
+			return nil
+		}
+		worlds = append(worlds, cfg.World)
+	}
+	if len(worlds) >= s.limits.Worlds {
+		return &LimitError{Resource: LimitWorlds, Limit: s.limits.Worlds}
+	}
+	return nil
+}
+
+// StopTracking deletes the guild's configuration, keeping a snapshot of it
+// that ResumeTracking restores for domain.StoppedConfigRetention.
 func (s *ConfigurationService) StopTracking(ctx context.Context, guildID string) error {
-	return s.repo.DeleteGuildConfig(ctx, guildID)
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cfg, err := tx.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	if cfg != nil {
+		snapshot, err := json.Marshal(newBackupGuild(*cfg))
+		if err != nil {
+			return fmt.Errorf("encode config: %w", err)
+		}
+		stopped := domain.StoppedGuildConfig{DiscordGuildID: guildID, Snapshot: snapshot, StoppedAt: time.Now()}
+		if err := tx.SaveStoppedGuildConfig(ctx, stopped); err != nil {
+			return err
+		}
+	}
+	if err := tx.DeleteGuildConfig(ctx, guildID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ResumeTracking restores the configuration the guild had when it last ran
+// StopTracking and returns its world. It fails with ErrNothingToResume when
+// the guild did not stop tracking within domain.StoppedConfigRetention, with
+// ErrWorldTracked when it tracks a world again, and with a *LimitError when
+// the world can no longer be tracked.
+func (s *ConfigurationService) ResumeTracking(ctx context.Context, guildID string) (string, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	stopped, err := tx.GetStoppedGuildConfig(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if stopped == nil || time.Since(stopped.StoppedAt) > domain.StoppedConfigRetention {
+		return "", ErrNothingToResume
+	}
+	cfg, err := tx.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if cfg != nil && cfg.World != "" {
+		return "", ErrWorldTracked
+	}
+
+	var g backupGuild
+	if err := json.Unmarshal(stopped.Snapshot, &g); err != nil {
+		return "", fmt.Errorf("decode stopped config: %w", err)
+	}
+	g.DiscordGuildID = guildID
+	if err := s.checkWorldLimit(ctx, tx, guildID, g.World); err != nil {
+		return g.World, err
+	}
+	if err := restoreGuild(ctx, tx, g); err != nil {
+		return g.World, err
+	}
+	if err := tx.DeleteStoppedGuildConfig(ctx, guildID); err != nil {
+		return g.World, err
+	}
+	return g.World, tx.Commit(ctx)
+}
+
+// PurgeGuildData deletes everything stored for the guild. Tracking history is
+// kept while another guild still tracks the same world or Tibia guilds.
+func (s *ConfigurationService) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	return s.repo.PurgeGuildData(ctx, guildID)
+}
+
+// CheckAddGuild reports whether AddGuildToTrack can add the Tibia guild
+// without looking it up, failing with ErrNoWorldTracked or a *LimitError.
+func (s *ConfigurationService) CheckAddGuild(ctx context.Context, guildID, tibiaGuildName string) error {
+	_, err := s.addGuildConfig(ctx, guildID, tibiaGuildName)
+	return err
+}
+
+// AddGuildToTrack looks the Tibia guild up and, when it plays on the server's
+// tracked world, tracks it under its name as spelled on TibiaData, which it
+// returns. Guilds that do not exist fail with domain.ErrNotFound, guilds on
+// another world with a *GuildWorldError and guilds past the server's limit
+// with a *LimitError.
+func (s *ConfigurationService) AddGuildToTrack(ctx context.Context, guildID, tibiaGuildName string) (string, error) {
+	cfg, err := s.addGuildConfig(ctx, guildID, tibiaGuildName)
+	if err != nil {
+		return "", err
+	}
+
+	guild, err := s.fetcher.FetchGuild(ctx, tibiaGuildName)
+	if err != nil {
+		return "", fmt.Errorf("look up guild %s: %w", tibiaGuildName, err)
+	}
+	if guild == nil || guild.Name == "" {
+		return "", fmt.Errorf("guild %s: %w", tibiaGuildName, domain.ErrNotFound)
+	}
+	if !strings.EqualFold(guild.World, cfg.World) {
+		return "", &GuildWorldError{Guild: guild.Name, World: guild.World, TrackedWorld: cfg.World}
+	}
+
+	return guild.Name, s.repo.AddGuildToConfig(ctx, guildID, guild.Name)
 }
 
-func (s *ConfigurationService) AddGuildToTrack(ctx context.Context, guildID, tibiaGuildName string) error {
-	return s.repo.AddGuildToConfig(ctx, guildID, tibiaGuildName)
+// addGuildConfig returns the guild's config when it may track another Tibia
+// guild. Guilds it already tracks do not count against the limit.
+func (s *ConfigurationService) addGuildConfig(ctx context.Context, guildID, tibiaGuildName string) (*domain.GuildConfig, error) {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil || cfg.World == "" {
+		return nil, ErrNoWorldTracked
+	}
+	name := domain.NormalizeName(tibiaGuildName)
+	tracked := slices.ContainsFunc(cfg.TibiaGuilds, func(g string) bool { return domain.SameName(g, name) })
+	if limit := s.guildLimits(*cfg).TibiaGuilds; limit > 0 && !tracked && len(cfg.TibiaGuilds) >= limit {
+		return nil, &LimitError{Resource: LimitTibiaGuilds, Limit: limit}
+	}
+	return cfg, nil
 }
 
+// WorldGuildNames lists the guilds on world, cached for worldGuildsTTL. A
+// failed fetch falls back to the stale list when there is one.
+func (s *ConfigurationService) WorldGuildNames(ctx context.Context, world string) ([]string, error) {
+	key := strings.ToLower(world)
+	s.guildsMu.Lock()
+	item, cached := s.worldGuilds[key]
+	s.guildsMu.Unlock()
+
+	if cached && time.Now().Before(item.expiresAt) {
+		return item.names, nil
+	}
+
+	names, err := s.fetcher.FetchWorldGuilds(ctx, world)
+	if err != nil {
+		if cached {
+			slog.InfoContext(ctx, "Using stale guild list", "world", world, "error", err)
+			return item.names, nil
+		}
+		return nil, err
+	}
+
+	s.guildsMu.Lock()
+	s.worldGuilds[key] = worldGuildsItem{names: names, expiresAt: time.Now().Add(worldGuildsTTL)}
+	s.guildsMu.Unlock()
+	return names, nil
+}
+
+// PlayerNames suggests characters stored on world whose name starts with
+// prefix. Nothing is suggested before the first letter is typed.
+func (s *ConfigurationService) PlayerNames(ctx context.Context, world, prefix string) ([]string, error) {
+	if domain.NormalizeName(prefix) == "" {
+		return nil, nil
+	}
+	return s.repo.GetPlayersByPrefix(ctx, world, prefix)
+}
+
+// RemoveGuildFromTrack stops tracking the Tibia guild, however its name is
+// spelled, by removing it under the spelling it was stored with.
 func (s *ConfigurationService) RemoveGuildFromTrack(ctx context.Context, guildID, tibiaGuildName string) error {
-	return s.repo.RemoveGuildFromConfig(ctx, guildID, tibiaGuildName)
+	name := domain.NormalizeName(tibiaGuildName)
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err == nil && cfg != nil {
+		if i := slices.IndexFunc(cfg.TibiaGuilds, func(g string) bool { return domain.SameName(g, name) }); i >= 0 {
+			name = cfg.TibiaGuilds[i]
+		}
+	}
+	return s.repo.RemoveGuildFromConfig(ctx, guildID, name)
+}
+
+func (s *ConfigurationService) SetLanguage(ctx context.Context, guildID, language string) error {
+	return s.repo.SetGuildLanguage(ctx, guildID, language)
+}
+
+func (s *ConfigurationService) SetChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	return s.repo.SetGuildChannel(ctx, guildID, kind, channelID)
+}
+
+func (s *ConfigurationService) SetPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	return s.repo.SetGuildPingRole(ctx, guildID, roleID, minLevel)
+}
+
+func (s *ConfigurationService) SetPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	return s.repo.SetGuildPollInterval(ctx, guildID, interval)
+}
+
+// Mute pauses notifications for the guild for the given duration and returns
+// when they resume. A zero duration unmutes the guild.
+func (s *ConfigurationService) Mute(ctx context.Context, guildID string, duration time.Duration) (time.Time, error) {
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	return until, s.repo.SetGuildMutedUntil(ctx, guildID, until)
+}
+
+// SetShareRange controls whether level ups show the range of levels the
+// character can share party experience with.
+func (s *ConfigurationService) SetShareRange(ctx context.Context, guildID string, enabled bool) error {
+	return s.repo.SetGuildShareRange(ctx, guildID, enabled)
+}
+
+// SetDeathLocation controls whether deaths show the hunting ground or boss
+// area the killers point to.
+func (s *ConfigurationService) SetDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	return s.repo.SetGuildDeathLocation(ctx, guildID, enabled)
+}
+
+// SetLevelDowns controls whether drops of domain.MinLevelDrop or more levels
+// are announced.
+func (s *ConfigurationService) SetLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	return s.repo.SetGuildLevelDowns(ctx, guildID, enabled)
+}
+
+// SetResponseVisibility makes the bot's replies to commands in the guild all
+// ephemeral or all public; domain.VisibilityDefault restores each command's
+// own choice. Unknown visibilities fail with ErrInvalidVisibility.
+func (s *ConfigurationService) SetResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	if !visibility.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidVisibility, visibility)
+	}
+	return s.repo.SetGuildResponseVisibility(ctx, guildID, visibility)
+}
+
+// SetDeathVerbosity sets how much the guild's death notifications tell.
+// Unknown verbosities fail with ErrInvalidVerbosity.
+func (s *ConfigurationService) SetDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	if !verbosity.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidVerbosity, verbosity)
+	}
+	return s.repo.SetGuildDeathVerbosity(ctx, guildID, verbosity)
+}
+
+// SetAnnouncements controls whether operator broadcasts reach the guild.
+func (s *ConfigurationService) SetAnnouncements(ctx context.Context, guildID string, enabled bool) error {
+	return s.repo.SetGuildBroadcastOptOut(ctx, guildID, !enabled)
+}
+
+// SetMassDeathAlert sets how many tracked characters dying within how long
+// raise a possible war or raid alert. A zero alert turns it off.
+func (s *ConfigurationService) SetMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	return s.repo.SetGuildMassDeathAlert(ctx, guildID, alert)
+}
+
+// SetLowLevelDeaths controls whether deaths of tracked guild members below the
+// minimum level are announced.
+func (s *ConfigurationService) SetLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	return s.repo.SetGuildLowLevelDeaths(ctx, guildID, enabled)
+}
+
+// SetMinLevel sets the lowest level at which the guild's deaths and level ups
+// are announced. Zero falls back to the global minimum.
+func (s *ConfigurationService) SetMinLevel(ctx context.Context, guildID string, level int) error {
+	if level < 0 || level > MaxMinLevel {
+		return ErrInvalidMinLevel
+	}
+	return s.repo.SetGuildMinLevel(ctx, guildID, level)
+}
+
+// SetTimezone sets the IANA zone timestamps in the guild's notifications are
+// shown in and returns its name. Unknown zones fail with ErrInvalidTimezone.
+func (s *ConfigurationService) SetTimezone(ctx context.Context, guildID, timezone string) (string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" || timezone == "Local" {
+		return "", ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTimezone, timezone)
+	}
+	return loc.String(), s.repo.SetGuildTimezone(ctx, guildID, loc.String())
+}
+
+// SetQuietHours stores the guild's daily quiet hours from a window such as
+// "22:00-06:00" in its timezone; "off" turns them off. With catchUp, deaths
+// and level ups from the window are posted when it ends.
+func (s *ConfigurationService) SetQuietHours(ctx context.Context, guildID, window string, catchUp bool) (domain.QuietHours, error) {
+	quiet, err := parseQuietHours(window)
+	if err != nil {
+		return domain.QuietHours{}, err
+	}
+	quiet.CatchUp = catchUp && quiet.Enabled()
+	return quiet, s.repo.SetGuildQuietHours(ctx, guildID, quiet)
+}
+
+func parseQuietHours(window string) (domain.QuietHours, error) {
+	window = strings.TrimSpace(window)
+	if strings.EqualFold(window, "off") {
+		return domain.QuietHours{}, nil
+	}
+
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return domain.QuietHours{}, fmt.Errorf("%w: %s", ErrInvalidQuietHours, window)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return domain.QuietHours{}, fmt.Errorf("%w: %s", ErrInvalidQuietHours, window)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return domain.QuietHours{}, fmt.Errorf("%w: %s", ErrInvalidQuietHours, window)
+	}
+
+	quiet := domain.QuietHours{
+		Start: start.Hour()*60 + start.Minute(),
+		End:   end.Hour()*60 + end.Minute(),
+	}
+	if !quiet.Enabled() {
+		return domain.QuietHours{}, fmt.Errorf("%w: %s", ErrInvalidQuietHours, window)
+	}
+	return quiet, nil
+}
+
+// SetTemplate stores the message template for a notification type; an empty
+// template restores the default message.
+func (s *ConfigurationService) SetTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	return s.repo.SetGuildTemplate(ctx, guildID, kind, template)
+}
+
+// SetEmoji stores the emoji prepended to a notification type's messages and
+// whether the bot reacts to them; an empty emoji prepends nothing.
+func (s *ConfigurationService) SetEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	return s.repo.SetGuildEmoji(ctx, guildID, kind, emoji, react)
+}
+
+// SetDeathRoute posts the guild's deaths at or above minLevel to channelID,
+// replacing the channel of an existing route for the same level. The guild
+// must track a world first.
+func (s *ConfigurationService) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || cfg.World == "" {
+		return ErrNoWorldTracked
+	}
+
+	replaces := slices.ContainsFunc(cfg.DeathRoutes, func(r domain.DeathRoute) bool { return r.MinLevel == minLevel })
+	if !replaces && len(cfg.DeathRoutes) >= maxDeathRoutes {
+		return ErrTooManyDeathRoutes
+	}
+	return s.repo.SetDeathRoute(ctx, guildID, minLevel, channelID)
+}
+
+// RemoveDeathRoute stops routing deaths at minLevel and reports whether such
+// a route existed.
+func (s *ConfigurationService) RemoveDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	return s.repo.DeleteDeathRoute(ctx, guildID, minLevel)
+}
+
+// CheckIgnorePlayer reports whether IgnorePlayer can ignore the character
+// without looking it up, failing with a *LimitError when the guild ignores
+// as many characters as it may.
+func (s *ConfigurationService) CheckIgnorePlayer(ctx context.Context, guildID, name string) error {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil || cfg == nil {
+		return err
+	}
+	name = domain.NormalizeName(name)
+	ignored := slices.ContainsFunc(cfg.IgnoredPlayers, func(p string) bool { return domain.SameName(p, name) })
+	if limit := s.guildLimits(*cfg).IgnoredPlayers; limit > 0 && !ignored && len(cfg.IgnoredPlayers) >= limit {
+		return &LimitError{Resource: LimitIgnoredPlayers, Limit: limit}
+	}
+	return nil
+}
+
+// IgnorePlayer stops all notifications about the character in the guild. The
+// character is looked up first and stored under its name as spelled on
+// TibiaData, which it returns, so it matches the names the tracker sees.
+// Characters that do not exist fail with domain.ErrNotFound and characters
+// past the server's limit with a *LimitError.
+func (s *ConfigurationService) IgnorePlayer(ctx context.Context, guildID, name string) (string, error) {
+	if err := s.CheckIgnorePlayer(ctx, guildID, name); err != nil {
+		return "", err
+	}
+	name = domain.NormalizeName(name)
+	player, err := s.fetcher.FetchCharacter(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("look up character %s: %w", name, err)
+	}
+	if player == nil || player.Name == "" {
+		return "", fmt.Errorf("character %s: %w", name, domain.ErrNotFound)
+	}
+
+	return player.Name, s.repo.AddIgnoredPlayer(ctx, guildID, player.Name)
+}
+
+func (s *ConfigurationService) UnignorePlayer(ctx context.Context, guildID, name string) error {
+	return s.repo.RemoveIgnoredPlayer(ctx, guildID, domain.NormalizeName(name))
+}
+
+// WatchPlayer announces the character's skill advances in the guild's skill
+// channel. Skills come from the tracked world's highscores, so the character
+// is looked up first and must play on that world; it is stored under its
+// name as spelled on TibiaData, which it returns. Characters past the
+// server's limit fail with a *LimitError.
+func (s *ConfigurationService) WatchPlayer(ctx context.Context, guildID, name string) (string, error) {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if cfg == nil || cfg.World == "" {
+		return "", ErrNoWorldTracked
+	}
+	name = domain.NormalizeName(name)
+	watched := slices.ContainsFunc(cfg.WatchedPlayers, func(p string) bool { return domain.SameName(p, name) })
+	if limit := s.guildLimits(*cfg).WatchedPlayers; limit > 0 && !watched && len(cfg.WatchedPlayers) >= limit {
+		return "", &LimitError{Resource: LimitWatchedPlayers, Limit: limit}
+	}
+
+	player, err := s.fetcher.FetchCharacter(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("look up character %s: %w", name, err)
+	}
+	if player == nil || player.Name == "" {
+		return "", fmt.Errorf("character %s: %w", name, domain.ErrNotFound)
+	}
+	if !strings.EqualFold(player.World, cfg.World) {
+		return "", &PlayerWorldError{Player: player.Name, World: player.World, TrackedWorld: cfg.World}
+	}
+
+	return player.Name, s.repo.AddWatchedPlayer(ctx, guildID, player.Name)
+}
+
+func (s *ConfigurationService) UnwatchPlayer(ctx context.Context, guildID, name string) error {
+	return s.repo.RemoveWatchedPlayer(ctx, guildID, domain.NormalizeName(name))
+}
+
+// SetSkillThreshold only announces the guild's advances of skill to minValue
+// or higher. The guild must track a world first.
+func (s *ConfigurationService) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || cfg.World == "" {
+		return ErrNoWorldTracked
+	}
+	return s.repo.SetSkillThreshold(ctx, guildID, skill, minValue)
+}
+
+// RemoveSkillThreshold announces every advance of skill again and reports
+// whether a threshold was set.
+func (s *ConfigurationService) RemoveSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	return s.repo.DeleteSkillThreshold(ctx, guildID, skill)
 }
 
 func (s *ConfigurationService) GetGuildConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
 	return s.repo.GetGuildConfig(ctx, guildID)
 }
+
+// GuildRemoved stops tracking a guild that removed the bot, keeping its
+// configuration so it can be restored if the bot is added back.
+func (s *ConfigurationService) GuildRemoved(ctx context.Context, guildID string) error {
+	return s.repo.MarkGuildRemoved(ctx, guildID, time.Now())
+}
+
+// GuildRejoined restores the configuration of a guild that removed the bot
+// and reports whether there was one to restore.
+func (s *ConfigurationService) GuildRejoined(ctx context.Context, guildID string) (bool, error) {
+	return s.repo.RestoreGuildConfig(ctx, guildID)
+}
+
+// TrackStatus aggregates the guild's configuration with its notification
+// backlog. It returns nil when the guild has never been configured.
+func (s *ConfigurationService) TrackStatus(ctx context.Context, guildID string) (*domain.TrackStatus, error) {
+	cfg, err := s.repo.GetGuildConfig(ctx, guildID)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.GetGuildFailedNotifications(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TrackStatus{Config: *cfg, PendingNotifications: len(pending), WorldPvPType: s.worldPvPType(ctx, cfg.World)}, nil
+}
+
+// worldPvPType looks up the PvP type shown by /track-status. It is only
+// informative, so a slow or failed lookup leaves it out instead of failing
+// the status.
+func (s *ConfigurationService) worldPvPType(ctx context.Context, world string) string {
+	if s.fetcher == nil || world == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, worldPvPTypeTimeout)
+	defer cancel()
+	pvpType, err := s.fetcher.FetchWorldPvPType(ctx, world)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to fetch world PvP type", "world", world, "error", err)
+		return ""
+	}
+	return pvpType
+}
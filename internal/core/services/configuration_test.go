@@ -2,19 +2,91 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
 	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
 )
 
 type mockRepository struct {
-	saveGuildWorldFunc        func(ctx context.Context, guildID, world string) error
-	deleteGuildConfigFunc     func(ctx context.Context, guildID string) error
-	getGuildConfigFunc        func(ctx context.Context, guildID string) (*domain.GuildConfig, error)
-	addGuildToConfigFunc      func(ctx context.Context, guildID, guildName string) error
-	removeGuildFromConfigFunc func(ctx context.Context, guildID, guildName string) error
+	saveGuildWorldFunc                   func(ctx context.Context, guildID, world string) error
+	deleteGuildConfigFunc                func(ctx context.Context, guildID string) error
+	getGuildConfigFunc                   func(ctx context.Context, guildID string) (*domain.GuildConfig, error)
+	addGuildToConfigFunc                 func(ctx context.Context, guildID, guildName string) error
+	removeGuildFromConfigFunc            func(ctx context.Context, guildID, guildName string) error
+	upsertPlayerLevelFunc                func(ctx context.Context, name string, level int, world string) error
+	getPlayersLevelsFunc                 func(ctx context.Context, world string) (map[string]int, error)
+	batchUpsertPlayerLevelsFunc          func(ctx context.Context, levels []domain.PlayerLevel) error
+	setGuildLanguageFunc                 func(ctx context.Context, guildID, language string) error
+	setGuildChannelFunc                  func(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error
+	setGuildPingRoleFunc                 func(ctx context.Context, guildID, roleID string, minLevel int) error
+	getDeathCountsSinceFunc              func(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error)
+	setGuildPollIntervalFunc             func(ctx context.Context, guildID string, interval time.Duration) error
+	enqueueFailedNotificationFunc        func(ctx context.Context, n domain.FailedNotification) error
+	getDueFailedNotificationsFunc        func(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error)
+	getGuildFailedNotificationsFunc      func(ctx context.Context, guildID string) ([]domain.FailedNotification, error)
+	rescheduleFailedNotificationFunc     func(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error
+	deleteFailedNotificationFunc         func(ctx context.Context, id int64) error
+	deleteExpiredFailedNotificationsFunc func(ctx context.Context, createdBefore time.Time) (int64, error)
+	setGuildMutedUntilFunc               func(ctx context.Context, guildID string, until time.Time) error
+	addIgnoredPlayerFunc                 func(ctx context.Context, guildID, name string) error
+	removeIgnoredPlayerFunc              func(ctx context.Context, guildID, name string) error
+	setGuildLowLevelDeathsFunc           func(ctx context.Context, guildID string, enabled bool) error
+	setGuildTimezoneFunc                 func(ctx context.Context, guildID, timezone string) error
+	setGuildTemplateFunc                 func(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error
+	setGuildEmojiFunc                    func(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error
+	setDeathRouteFunc                    func(ctx context.Context, guildID string, minLevel int, channelID string) error
+	deleteDeathRouteFunc                 func(ctx context.Context, guildID string, minLevel int) (bool, error)
+	setGuildQuietHoursFunc               func(ctx context.Context, guildID string, quiet domain.QuietHours) error
+	getDeathsPageFunc                    func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error)
+	getLevelUpsPageFunc                  func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error)
+	setGuildLastNotifiedFunc             func(ctx context.Context, guildID string, at time.Time) error
+	markGuildRemovedFunc                 func(ctx context.Context, guildID string, at time.Time) error
+	restoreGuildConfigFunc               func(ctx context.Context, guildID string) (bool, error)
+	deleteRemovedGuildConfigsFunc        func(ctx context.Context, removedBefore time.Time) (int64, error)
+	purgeGuildDataFunc                   func(ctx context.Context, guildID string) (domain.PurgeResult, error)
+	deleteDeathsBeforeFunc               func(ctx context.Context, diedBefore time.Time) (int64, error)
+	getTopKillersSinceFunc               func(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error)
+	recordLevelUpFunc                    func(ctx context.Context, levelUp domain.LevelUp) error
+	getLevelUpsSinceFunc                 func(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error)
+	getDailyLevelGainsFunc               func(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error)
+	deleteLevelUpsBeforeFunc             func(ctx context.Context, reachedBefore time.Time) (int64, error)
+	getAllGuildConfigsFunc               func(ctx context.Context) ([]domain.GuildConfig, error)
+	getHouseAuctionsFunc                 func(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error)
+	replaceHouseAuctionsFunc             func(ctx context.Context, world string, auctions []domain.HouseAuction) error
+	setGuildQuotaFunc                    func(ctx context.Context, guildID string, quota domain.Quota) error
+	setGuildPremiumFunc                  func(ctx context.Context, guildID string, premium bool) error
+	setGuildShareRangeFunc               func(ctx context.Context, guildID string, enabled bool) error
+	addWatchedPlayerFunc                 func(ctx context.Context, guildID, name string) error
+	removeWatchedPlayerFunc              func(ctx context.Context, guildID, name string) error
+	setSkillThresholdFunc                func(ctx context.Context, guildID string, skill domain.Skill, minValue int) error
+	deleteSkillThresholdFunc             func(ctx context.Context, guildID string, skill domain.Skill) (bool, error)
+	getPlayerSkillsFunc                  func(ctx context.Context, world string, skill domain.Skill) (map[string]int, error)
+	savePlayerSkillsFunc                 func(ctx context.Context, world string, skill domain.Skill, values map[string]int) error
+	setGuildMassDeathAlertFunc           func(ctx context.Context, guildID string, alert domain.MassDeathAlert) error
+	setGuildBroadcastOptOutFunc          func(ctx context.Context, guildID string, optOut bool) error
+	setGuildMinLevelFunc                 func(ctx context.Context, guildID string, level int) error
+	commits, rollbacks                   int
+	saveStoppedGuildConfigFunc           func(ctx context.Context, stopped domain.StoppedGuildConfig) error
+	getStoppedGuildConfigFunc            func(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error)
+	deleteStoppedGuildConfigFunc         func(ctx context.Context, guildID string) error
+	getOnlineCountsSinceFunc             func(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error)
+	getWorldOnlineCountsSinceFunc        func(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error)
+	getOnlineTimeSinceFunc               func(ctx context.Context, name string, since time.Time) (time.Duration, error)
+	setGuildDeathLocationFunc            func(ctx context.Context, guildID string, enabled bool) error
+	setGuildLevelDownsFunc               func(ctx context.Context, guildID string, enabled bool) error
+	setGuildResponseVisibilityFunc       func(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error
+	setGuildDeathVerbosityFunc           func(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error
+	getGuildWarFunc                      func(ctx context.Context, guildName string) (*domain.GuildWar, error)
+	startGuildWarFunc                    func(ctx context.Context, guildName string, startedAt time.Time) error
+	endGuildWarFunc                      func(ctx context.Context, guildName string, endedAt time.Time) error
+	countKillsBetweenFunc                func(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error)
+	getFragCountsSinceFunc               func(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error)
 }
 
 func (m *mockRepository) SaveGuildWorld(ctx context.Context, guildID, world string) error {
@@ -53,14 +125,23 @@ func (m *mockRepository) RemoveGuildFromConfig(ctx context.Context, guildID, gui
 }
 
 func (m *mockRepository) GetAllGuildConfigs(ctx context.Context) ([]domain.GuildConfig, error) {
+	if m.getAllGuildConfigsFunc != nil {
+		return m.getAllGuildConfigsFunc(ctx)
+	}
 	return nil, nil
 }
 
 func (m *mockRepository) UpsertPlayerLevel(ctx context.Context, name string, level int, world string) error {
+	if m.upsertPlayerLevelFunc != nil {
+		return m.upsertPlayerLevelFunc(ctx, name, level, world)
+	}
 	return nil
 }
 
 func (m *mockRepository) GetPlayersLevels(ctx context.Context, world string) (map[string]int, error) {
+	if m.getPlayersLevelsFunc != nil {
+		return m.getPlayersLevelsFunc(ctx, world)
+	}
 	return nil, nil
 }
 
@@ -68,6 +149,10 @@ func (m *mockRepository) GetOfflinePlayers(ctx context.Context, world string, on
 	return nil, nil
 }
 
+func (m *mockRepository) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	return nil
+}
+
 func (m *mockRepository) BatchTouchPlayers(ctx context.Context, names []string) error {
 	return nil
 }
@@ -76,6 +161,526 @@ func (m *mockRepository) DeleteOldPlayers(ctx context.Context, world string, max
 	return 0, nil
 }
 
+func (m *mockRepository) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	if m.setGuildLanguageFunc != nil {
+		return m.setGuildLanguageFunc(ctx, guildID, language)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	if m.setGuildChannelFunc != nil {
+		return m.setGuildChannelFunc(ctx, guildID, kind, channelID)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	if m.setGuildPingRoleFunc != nil {
+		return m.setGuildPingRoleFunc(ctx, guildID, roleID, minLevel)
+	}
+	return nil
+}
+
+func (m *mockRepository) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	return nil
+}
+
+func (m *mockRepository) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	if m.getDeathCountsSinceFunc != nil {
+		return m.getDeathCountsSinceFunc(ctx, world, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	if m.setGuildPollIntervalFunc != nil {
+		return m.setGuildPollIntervalFunc(ctx, guildID, interval)
+	}
+	return nil
+}
+
+func (m *mockRepository) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	if m.enqueueFailedNotificationFunc != nil {
+		return m.enqueueFailedNotificationFunc(ctx, n)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	if m.getDueFailedNotificationsFunc != nil {
+		return m.getDueFailedNotificationsFunc(ctx, due, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetGuildFailedNotifications(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+	if m.getGuildFailedNotificationsFunc != nil {
+		return m.getGuildFailedNotificationsFunc(ctx, guildID)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	if m.rescheduleFailedNotificationFunc != nil {
+		return m.rescheduleFailedNotificationFunc(ctx, id, nextAttempt, lastErr)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteFailedNotification(ctx context.Context, id int64) error {
+	if m.deleteFailedNotificationFunc != nil {
+		return m.deleteFailedNotificationFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	if m.deleteExpiredFailedNotificationsFunc != nil {
+		return m.deleteExpiredFailedNotificationsFunc(ctx, createdBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	if m.setGuildMutedUntilFunc != nil {
+		return m.setGuildMutedUntilFunc(ctx, guildID, until)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+
+func (m *mockRepository) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+
+func (m *mockRepository) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	if m.batchUpsertPlayerLevelsFunc != nil {
+		return m.batchUpsertPlayerLevelsFunc(ctx, levels)
+	}
+	return nil
+}
+
+func (m *mockRepository) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	if m.addIgnoredPlayerFunc != nil {
+		return m.addIgnoredPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockRepository) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	if m.removeIgnoredPlayerFunc != nil {
+		return m.removeIgnoredPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildLowLevelDeathsFunc != nil {
+		return m.setGuildLowLevelDeathsFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	if m.setGuildTimezoneFunc != nil {
+		return m.setGuildTimezoneFunc(ctx, guildID, timezone)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	if m.setGuildTemplateFunc != nil {
+		return m.setGuildTemplateFunc(ctx, guildID, kind, template)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	if m.setGuildEmojiFunc != nil {
+		return m.setGuildEmojiFunc(ctx, guildID, kind, emoji, react)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	if m.setDeathRouteFunc != nil {
+		return m.setDeathRouteFunc(ctx, guildID, minLevel, channelID)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	if m.deleteDeathRouteFunc != nil {
+		return m.deleteDeathRouteFunc(ctx, guildID, minLevel)
+	}
+	return false, nil
+}
+
+func (m *mockRepository) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	if m.setGuildQuietHoursFunc != nil {
+		return m.setGuildQuietHoursFunc(ctx, guildID, quiet)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	if m.getDeathsPageFunc != nil {
+		return m.getDeathsPageFunc(ctx, world, guildNames, since, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	if m.getLevelUpsPageFunc != nil {
+		return m.getLevelUpsPageFunc(ctx, world, guildNames, since, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	if m.setGuildLastNotifiedFunc != nil {
+		return m.setGuildLastNotifiedFunc(ctx, guildID, at)
+	}
+	return nil
+}
+
+func (m *mockRepository) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	if m.markGuildRemovedFunc != nil {
+		return m.markGuildRemovedFunc(ctx, guildID, at)
+	}
+	return nil
+}
+
+func (m *mockRepository) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	if m.restoreGuildConfigFunc != nil {
+		return m.restoreGuildConfigFunc(ctx, guildID)
+	}
+	return false, nil
+}
+
+func (m *mockRepository) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	if m.deleteRemovedGuildConfigsFunc != nil {
+		return m.deleteRemovedGuildConfigsFunc(ctx, removedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	if m.purgeGuildDataFunc != nil {
+		return m.purgeGuildDataFunc(ctx, guildID)
+	}
+	return domain.PurgeResult{}, nil
+}
+
+func (m *mockRepository) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	if m.deleteDeathsBeforeFunc != nil {
+		return m.deleteDeathsBeforeFunc(ctx, diedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	if m.getTopKillersSinceFunc != nil {
+		return m.getTopKillersSinceFunc(ctx, world, guildNames, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	if m.recordLevelUpFunc != nil {
+		return m.recordLevelUpFunc(ctx, levelUp)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	if m.getLevelUpsSinceFunc != nil {
+		return m.getLevelUpsSinceFunc(ctx, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	if m.getDailyLevelGainsFunc != nil {
+		return m.getDailyLevelGainsFunc(ctx, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	if m.deleteLevelUpsBeforeFunc != nil {
+		return m.deleteLevelUpsBeforeFunc(ctx, reachedBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	if m.getHouseAuctionsFunc != nil {
+		return m.getHouseAuctionsFunc(ctx, world, seenSince)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	if m.replaceHouseAuctionsFunc != nil {
+		return m.replaceHouseAuctionsFunc(ctx, world, auctions)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	if m.setGuildQuotaFunc != nil {
+		return m.setGuildQuotaFunc(ctx, guildID, quota)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	if m.setGuildPremiumFunc != nil {
+		return m.setGuildPremiumFunc(ctx, guildID, premium)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildShareRangeFunc != nil {
+		return m.setGuildShareRangeFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockRepository) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	if m.addWatchedPlayerFunc != nil {
+		return m.addWatchedPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockRepository) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	if m.removeWatchedPlayerFunc != nil {
+		return m.removeWatchedPlayerFunc(ctx, guildID, name)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	if m.setSkillThresholdFunc != nil {
+		return m.setSkillThresholdFunc(ctx, guildID, skill, minValue)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	if m.deleteSkillThresholdFunc != nil {
+		return m.deleteSkillThresholdFunc(ctx, guildID, skill)
+	}
+	return false, nil
+}
+
+func (m *mockRepository) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	if m.getPlayerSkillsFunc != nil {
+		return m.getPlayerSkillsFunc(ctx, world, skill)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	if m.savePlayerSkillsFunc != nil {
+		return m.savePlayerSkillsFunc(ctx, world, skill, values)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	if m.setGuildMassDeathAlertFunc != nil {
+		return m.setGuildMassDeathAlertFunc(ctx, guildID, alert)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	return nil
+}
+
+func (m *mockRepository) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	if m.setGuildBroadcastOptOutFunc != nil {
+		return m.setGuildBroadcastOptOutFunc(ctx, guildID, optOut)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	if m.setGuildMinLevelFunc != nil {
+		return m.setGuildMinLevelFunc(ctx, guildID, level)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	return &mockRepositoryTx{mockRepository: m}, nil
+}
+
+func (m *mockRepository) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	if m.saveStoppedGuildConfigFunc != nil {
+		return m.saveStoppedGuildConfigFunc(ctx, stopped)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	if m.getStoppedGuildConfigFunc != nil {
+		return m.getStoppedGuildConfigFunc(ctx, guildID)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	if m.deleteStoppedGuildConfigFunc != nil {
+		return m.deleteStoppedGuildConfigFunc(ctx, guildID)
+	}
+	return nil
+}
+
+func (m *mockRepository) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	return nil
+}
+
+func (m *mockRepository) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	if m.getOnlineCountsSinceFunc != nil {
+		return m.getOnlineCountsSinceFunc(ctx, world, guildNames, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	return nil
+}
+
+func (m *mockRepository) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	if m.getWorldOnlineCountsSinceFunc != nil {
+		return m.getWorldOnlineCountsSinceFunc(ctx, world, since)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	return nil
+}
+
+func (m *mockRepository) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	if m.getOnlineTimeSinceFunc != nil {
+		return m.getOnlineTimeSinceFunc(ctx, name, since)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepository) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildDeathLocationFunc != nil {
+		return m.setGuildDeathLocationFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	if m.setGuildLevelDownsFunc != nil {
+		return m.setGuildLevelDownsFunc(ctx, guildID, enabled)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	if m.setGuildResponseVisibilityFunc != nil {
+		return m.setGuildResponseVisibilityFunc(ctx, guildID, visibility)
+	}
+	return nil
+}
+
+func (m *mockRepository) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	if m.setGuildDeathVerbosityFunc != nil {
+		return m.setGuildDeathVerbosityFunc(ctx, guildID, verbosity)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	if m.getGuildWarFunc != nil {
+		return m.getGuildWarFunc(ctx, guildName)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	if m.startGuildWarFunc != nil {
+		return m.startGuildWarFunc(ctx, guildName, startedAt)
+	}
+	return nil
+}
+
+func (m *mockRepository) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	if m.endGuildWarFunc != nil {
+		return m.endGuildWarFunc(ctx, guildName, endedAt)
+	}
+	return nil
+}
+
+func (m *mockRepository) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	if m.countKillsBetweenFunc != nil {
+		return m.countKillsBetweenFunc(ctx, world, victims, killers, since, until)
+	}
+	return 0, nil
+}
+
+func (m *mockRepository) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	if m.getFragCountsSinceFunc != nil {
+		return m.getFragCountsSinceFunc(ctx, world, guildNames, name, since)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) Close() {}
 
 func TestSetWorld_Success(t *testing.T) {
@@ -87,7 +692,7 @@ func TestSetWorld_Success(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	result, err := svc.SetWorld(context.Background(), "guild-1", "antica")
 
 	if err != nil {
@@ -110,7 +715,7 @@ func TestSetWorld_Formatting(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			svc := NewConfigurationService(&mockRepository{})
+			svc := NewConfigurationService(&mockRepository{}, nil, Limits{}, nil)
 			result, _ := svc.SetWorld(context.Background(), "guild-1", tt.input)
 
 			if result != tt.expected {
@@ -120,88 +725,427 @@ func TestSetWorld_Formatting(t *testing.T) {
 	}
 }
 
-func TestSetWorld_Error(t *testing.T) {
+func TestSetWorld_Error(t *testing.T) {
+	repo := &mockRepository{
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			return errors.New("db error")
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	_, err := svc.SetWorld(context.Background(), "guild-1", "antica")
+
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTrackWorld(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *domain.GuildConfig
+		prepareErr error
+		wantFirst  bool
+	}{
+		{"First world", nil, nil, true},
+		{"World changed", &domain.GuildConfig{World: "Secura"}, nil, false},
+		{"Prepare fails", nil, errors.New("no channel"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved string
+			repo := &mockRepository{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.config, nil
+				},
+				saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+					saved = world
+					return nil
+				},
+			}
+			prepared := false
+			svc := NewConfigurationService(repo, nil, Limits{}, nil)
+
+			world, first, err := svc.TrackWorld(context.Background(), "guild-1", "antica", func(ctx context.Context) error {
+				if saved != "Antica" {
+					t.Error("expected the world saved before prepare")
+				}
+				prepared = true
+				return tt.prepareErr
+			})
+
+			if !errors.Is(err, tt.prepareErr) || world != "Antica" || first != tt.wantFirst || !prepared {
+				t.Errorf("got %q, %v, %v", world, first, err)
+			}
+			wantCommits, wantRollbacks := 1, 0
+			if tt.prepareErr != nil {
+				wantCommits, wantRollbacks = 0, 1
+			}
+			if repo.commits != wantCommits || repo.rollbacks != wantRollbacks {
+				t.Errorf("expected %d commits and %d rollbacks, got %d and %d", wantCommits, wantRollbacks, repo.commits, repo.rollbacks)
+			}
+		})
+	}
+}
+
+func TestTrackWorld_LookupError(t *testing.T) {
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+
+	_, _, err := svc.TrackWorld(context.Background(), "guild-1", "antica", func(ctx context.Context) error {
+		t.Error("expected nothing prepared after a failed lookup")
+		return nil
+	})
+
+	if err == nil || repo.commits != 0 {
+		t.Errorf("expected an uncommitted error, got %v and %d commits", err, repo.commits)
+	}
+}
+
+func TestTrackWorld_LimitSkipsPrepare(t *testing.T) {
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "other", World: "Secura"}}, nil
+		},
+	}
+	svc := NewConfigurationService(repo, nil, Limits{Worlds: 1}, nil)
+
+	_, _, err := svc.TrackWorld(context.Background(), "guild-1", "antica", func(ctx context.Context) error {
+		t.Error("expected nothing prepared over the world limit")
+		return nil
+	})
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || repo.rollbacks != 1 {
+		t.Errorf("expected a rolled back limit error, got %v and %d rollbacks", err, repo.rollbacks)
+	}
+}
+
+func TestStopTracking_Success(t *testing.T) {
+	var deletedGuildID string
+	repo := &mockRepository{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			deletedGuildID = guildID
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	err := svc.StopTracking(context.Background(), "guild-123")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deletedGuildID != "guild-123" {
+		t.Errorf("expected 'guild-123', got '%s'", deletedGuildID)
+	}
+}
+
+func TestStopTracking_Error(t *testing.T) {
+	repo := &mockRepository{
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			return errors.New("db error")
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	err := svc.StopTracking(context.Background(), "guild-1")
+
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestStopTracking_LookupError(t *testing.T) {
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return nil, errors.New("connection refused")
+		},
+		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
+			t.Error("expected the config kept when it could not be snapshotted")
+			return nil
+		},
+	}
+
+	err := NewConfigurationService(repo, nil, Limits{}, nil).StopTracking(context.Background(), "guild-1")
+
+	if err == nil || repo.commits != 0 {
+		t.Errorf("expected an uncommitted error, got %v and %d commits", err, repo.commits)
+	}
+}
+
+func TestStopTracking_KeepsSnapshot(t *testing.T) {
+	var stopped domain.StoppedGuildConfig
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica", TibiaGuilds: []string{"Red Rose"}, Language: "pl"}, nil
+		},
+		saveStoppedGuildConfigFunc: func(ctx context.Context, s domain.StoppedGuildConfig) error {
+			stopped = s
+			return nil
+		},
+	}
+
+	if err := NewConfigurationService(repo, nil, Limits{}, nil).StopTracking(context.Background(), "guild-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var g backupGuild
+	if err := json.Unmarshal(stopped.Snapshot, &g); err != nil {
+		t.Fatalf("snapshot is not JSON: %v", err)
+	}
+	if stopped.DiscordGuildID != "guild-1" || g.World != "Antica" || !slices.Equal(g.TibiaGuilds, []string{"Red Rose"}) || g.Language != "pl" {
+		t.Errorf("unexpected snapshot %+v of %s", g, stopped.DiscordGuildID)
+	}
+	if time.Since(stopped.StoppedAt) > time.Minute || repo.commits != 1 {
+		t.Errorf("expected a committed snapshot taken now, got %v and %d commits", stopped.StoppedAt, repo.commits)
+	}
+}
+
+func TestResumeTracking(t *testing.T) {
+	snapshot := []byte(`{"discord_guild_id": "guild-1", "world": "Antica", "tibia_guilds": ["Red Rose"], "low_level_deaths": true}`)
+	tests := []struct {
+		name      string
+		stopped   *domain.StoppedGuildConfig
+		current   *domain.GuildConfig
+		wantErr   error
+		wantWorld string
+	}{
+		{"Restores", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now().Add(-24 * time.Hour)}, nil, nil, "Antica"},
+		{"Restores over settings", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now()}, &domain.GuildConfig{Language: "pl"}, nil, "Antica"},
+		{"Never stopped", nil, nil, ErrNothingToResume, ""},
+		{"Expired", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now().Add(-31 * 24 * time.Hour)}, nil, ErrNothingToResume, ""},
+		{"World tracked again", &domain.StoppedGuildConfig{Snapshot: snapshot, StoppedAt: time.Now()}, &domain.GuildConfig{World: "Secura"}, ErrWorldTracked, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var world string
+			var guilds []string
+			deleted := false
+			repo := &mockRepository{
+				getStoppedGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+					return tt.stopped, nil
+				},
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.current, nil
+				},
+				saveGuildWorldFunc: func(ctx context.Context, guildID, w string) error {
+					world = w
+					return nil
+				},
+				addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+					guilds = append(guilds, guildName)
+					return nil
+				},
+				deleteStoppedGuildConfigFunc: func(ctx context.Context, guildID string) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			got, err := NewConfigurationService(repo, nil, Limits{}, nil).ResumeTracking(context.Background(), "guild-1")
+
+			if !errors.Is(err, tt.wantErr) || got != tt.wantWorld {
+				t.Fatalf("expected %q and %v, got %q and %v", tt.wantWorld, tt.wantErr, got, err)
+			}
+			if tt.wantErr != nil {
+				if world != "" || deleted || repo.commits != 0 {
+					t.Errorf("expected nothing restored, got world %q, deleted %v and %d commits", world, deleted, repo.commits)
+				}
+				return
+			}
+			if world != "Antica" || !slices.Equal(guilds, []string{"Red Rose"}) || !deleted || repo.commits != 1 {
+				t.Errorf("expected a committed restore, got world %q, guilds %v, deleted %v and %d commits", world, guilds, deleted, repo.commits)
+			}
+		})
+	}
+}
+
+func TestResumeTracking_WorldLimit(t *testing.T) {
+	repo := &mockRepository{
+		getStoppedGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+			return &domain.StoppedGuildConfig{Snapshot: []byte(`{"world": "Antica"}`), StoppedAt: time.Now()}, nil
+		},
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "other", World: "Secura"}}, nil
+		},
+		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+			t.Error("expected nothing restored over the world limit")
+			return nil
+		},
+	}
+
+	_, err := NewConfigurationService(repo, nil, Limits{Worlds: 1}, nil).ResumeTracking(context.Background(), "guild-1")
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || repo.rollbacks != 1 {
+		t.Errorf("expected a rolled back limit error, got %v and %d rollbacks", err, repo.rollbacks)
+	}
+}
+
+func anticaConfig(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+	return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+}
+
+func TestAddGuildToTrack_Success(t *testing.T) {
+	var addedGuild string
+	repo := &mockRepository{
+		getGuildConfigFunc: anticaConfig,
+		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+			addedGuild = guildName
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{Name: "Red Rose", World: "antica"}, nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, fetcher, Limits{}, nil)
+	added, err := svc.AddGuildToTrack(context.Background(), "guild-1", "red rose")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != "Red Rose" || addedGuild != "Red Rose" {
+		t.Errorf("expected the TibiaData spelling 'Red Rose', got '%s' and saved '%s'", added, addedGuild)
+	}
+}
+
+func TestAddGuildToTrack_Error(t *testing.T) {
 	repo := &mockRepository{
-		saveGuildWorldFunc: func(ctx context.Context, guildID, world string) error {
+		getGuildConfigFunc: anticaConfig,
+		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
 			return errors.New("db error")
 		},
 	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{Name: name, World: "Antica"}, nil
+		},
+	}
 
-	svc := NewConfigurationService(repo)
-	_, err := svc.SetWorld(context.Background(), "guild-1", "antica")
+	svc := NewConfigurationService(repo, fetcher, Limits{}, nil)
+	_, err := svc.AddGuildToTrack(context.Background(), "guild-1", "Test")
 
 	if err == nil {
 		t.Error("expected error")
 	}
 }
 
-func TestStopTracking_Success(t *testing.T) {
-	var deletedGuildID string
+func TestAddGuildToTrack_NoWorldTracked(t *testing.T) {
+	svc := NewConfigurationService(&mockRepository{}, &mockFetcher{}, Limits{}, nil)
+	_, err := svc.AddGuildToTrack(context.Background(), "guild-1", "Red Rose")
+
+	if !errors.Is(err, ErrNoWorldTracked) {
+		t.Errorf("expected ErrNoWorldTracked, got %v", err)
+	}
+}
+
+func TestAddGuildToTrack_NotFound(t *testing.T) {
 	repo := &mockRepository{
-		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
-			deletedGuildID = guildID
+		getGuildConfigFunc: anticaConfig,
+		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+			t.Error("a missing guild must not be saved")
 			return nil
 		},
 	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{}, nil
+		},
+	}
 
-	svc := NewConfigurationService(repo)
-	err := svc.StopTracking(context.Background(), "guild-123")
+	svc := NewConfigurationService(repo, fetcher, Limits{}, nil)
+	_, err := svc.AddGuildToTrack(context.Background(), "guild-1", "Nobody")
 
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if deletedGuildID != "guild-123" {
-		t.Errorf("expected 'guild-123', got '%s'", deletedGuildID)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
 
-func TestStopTracking_Error(t *testing.T) {
+func TestAddGuildToTrack_OtherWorld(t *testing.T) {
 	repo := &mockRepository{
-		deleteGuildConfigFunc: func(ctx context.Context, guildID string) error {
-			return errors.New("db error")
+		getGuildConfigFunc: anticaConfig,
+		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+			t.Error("a guild on another world must not be saved")
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{Name: "Red Rose", World: "Secura"}, nil
 		},
 	}
 
-	svc := NewConfigurationService(repo)
-	err := svc.StopTracking(context.Background(), "guild-1")
+	svc := NewConfigurationService(repo, fetcher, Limits{}, nil)
+	_, err := svc.AddGuildToTrack(context.Background(), "guild-1", "Red Rose")
 
-	if err == nil {
-		t.Error("expected error")
+	var worldErr *GuildWorldError
+	if !errors.As(err, &worldErr) {
+		t.Fatalf("expected a GuildWorldError, got %v", err)
+	}
+	if worldErr.World != "Secura" || worldErr.TrackedWorld != "Antica" {
+		t.Errorf("unexpected worlds: %+v", worldErr)
 	}
 }
 
-func TestAddGuildToTrack_Success(t *testing.T) {
-	var addedGuild string
-	repo := &mockRepository{
-		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
-			addedGuild = guildName
-			return nil
+func TestWorldGuildNames_Cached(t *testing.T) {
+	calls := 0
+	fetcher := &mockFetcher{
+		fetchGuildsFunc: func(ctx context.Context, world string) ([]string, error) {
+			calls++
+			return []string{"Red Rose", "Blue Army"}, nil
 		},
 	}
 
-	svc := NewConfigurationService(repo)
-	err := svc.AddGuildToTrack(context.Background(), "guild-1", "Red Rose")
-
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	svc := NewConfigurationService(&mockRepository{}, fetcher, Limits{}, nil)
+	for _, world := range []string{"Antica", "antica"} {
+		names, err := svc.WorldGuildNames(context.Background(), world)
+		if err != nil || len(names) != 2 {
+			t.Fatalf("WorldGuildNames(%s) = %v, %v", world, names, err)
+		}
 	}
-	if addedGuild != "Red Rose" {
-		t.Errorf("expected 'Red Rose', got '%s'", addedGuild)
+	if calls != 1 {
+		t.Errorf("expected one fetch for a cached world, got %d", calls)
 	}
 }
 
-func TestAddGuildToTrack_Error(t *testing.T) {
-	repo := &mockRepository{
-		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
-			return errors.New("db error")
+func TestWorldGuildNames_StaleOnError(t *testing.T) {
+	fetchErr := errors.New("upstream down")
+	var failing bool
+	fetcher := &mockFetcher{
+		fetchGuildsFunc: func(ctx context.Context, world string) ([]string, error) {
+			if failing {
+				return nil, fetchErr
+			}
+			return []string{"Red Rose"}, nil
 		},
 	}
 
-	svc := NewConfigurationService(repo)
-	err := svc.AddGuildToTrack(context.Background(), "guild-1", "Test")
+	svc := NewConfigurationService(&mockRepository{}, fetcher, Limits{}, nil)
+	if _, err := svc.WorldGuildNames(context.Background(), "Secura"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if err == nil {
-		t.Error("expected error")
+	failing = true
+	item := svc.worldGuilds["secura"]
+	item.expiresAt = time.Now().Add(-time.Minute)
+	svc.worldGuilds["secura"] = item
+	names, err := svc.WorldGuildNames(context.Background(), "Secura")
+	if err != nil || len(names) != 1 {
+		t.Errorf("expected the stale list, got %v, %v", names, err)
+	}
+
+	if _, err := svc.WorldGuildNames(context.Background(), "Antica"); !errors.Is(err, fetchErr) {
+		t.Errorf("expected the fetch error without a cached list, got %v", err)
 	}
 }
 
@@ -214,7 +1158,7 @@ func TestRemoveGuildFromTrack_Success(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	err := svc.RemoveGuildFromTrack(context.Background(), "guild-1", "Red Rose")
 
 	if err != nil {
@@ -225,6 +1169,27 @@ func TestRemoveGuildFromTrack_Success(t *testing.T) {
 	}
 }
 
+func TestRemoveGuildFromTrack_StoredSpelling(t *testing.T) {
+	var removedGuild string
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{TibiaGuilds: []string{"Blue Moon", "Sír's Order"}}, nil
+		},
+		removeGuildFromConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+			removedGuild = guildName
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	if err := svc.RemoveGuildFromTrack(context.Background(), "guild-1", "sir%27s+order"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removedGuild != "Sír's Order" {
+		t.Errorf("expected the stored spelling to be removed, got '%s'", removedGuild)
+	}
+}
+
 func TestRemoveGuildFromTrack_Error(t *testing.T) {
 	repo := &mockRepository{
 		removeGuildFromConfigFunc: func(ctx context.Context, guildID, guildName string) error {
@@ -232,7 +1197,7 @@ func TestRemoveGuildFromTrack_Error(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	err := svc.RemoveGuildFromTrack(context.Background(), "guild-1", "Test")
 
 	if err == nil {
@@ -253,7 +1218,7 @@ func TestGetGuildConfig_Success(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	result, err := svc.GetGuildConfig(context.Background(), "guild-1")
 
 	if err != nil {
@@ -271,7 +1236,7 @@ func TestGetGuildConfig_NotFound(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	result, err := svc.GetGuildConfig(context.Background(), "guild-1")
 
 	if err != nil {
@@ -289,10 +1254,507 @@ func TestGetGuildConfig_Error(t *testing.T) {
 		},
 	}
 
-	svc := NewConfigurationService(repo)
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
 	_, err := svc.GetGuildConfig(context.Background(), "guild-1")
 
 	if err == nil {
 		t.Error("expected error")
 	}
 }
+
+func TestTrackStatus(t *testing.T) {
+	t.Run("aggregates config and pending notifications", func(t *testing.T) {
+		repo := &mockRepository{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+			},
+			getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+				return []domain.FailedNotification{{ID: 1}, {ID: 2}}, nil
+			},
+		}
+
+		status, err := NewConfigurationService(repo, nil, Limits{}, nil).TrackStatus(context.Background(), "guild-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status.Config.World != "Antica" || status.PendingNotifications != 2 {
+			t.Errorf("unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		repo := &mockRepository{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return nil, nil
+			},
+		}
+
+		status, err := NewConfigurationService(repo, nil, Limits{}, nil).TrackStatus(context.Background(), "guild-1")
+		if err != nil || status != nil {
+			t.Errorf("expected nil status and error, got %+v, %v", status, err)
+		}
+	})
+
+	t.Run("backlog error", func(t *testing.T) {
+		repo := &mockRepository{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return &domain.GuildConfig{}, nil
+			},
+			getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+				return nil, errors.New("db down")
+			},
+		}
+
+		if _, err := NewConfigurationService(repo, nil, Limits{}, nil).TrackStatus(context.Background(), "guild-1"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestTrackStatus_WorldPvPType(t *testing.T) {
+	tests := []struct {
+		name     string
+		pvpType  string
+		fetchErr error
+		want     string
+	}{
+		{"Known", "Open PvP", nil, "Open PvP"},
+		{"Lookup fails", "", errors.New("tibiadata down"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return &domain.GuildConfig{DiscordGuildID: guildID, World: "Antica"}, nil
+				},
+			}
+			fetcher := &mockFetcher{fetchPvPTypeFunc: func(ctx context.Context, world string) (string, error) {
+				return tt.pvpType, tt.fetchErr
+			}}
+
+			status, err := NewConfigurationService(repo, fetcher, Limits{}, nil).TrackStatus(context.Background(), "guild-1")
+			if err != nil {
+				t.Fatalf("expected a failed lookup not to fail the status, got %v", err)
+			}
+			if status.WorldPvPType != tt.want {
+				t.Errorf("expected PvP type %q, got %q", tt.want, status.WorldPvPType)
+			}
+		})
+	}
+}
+
+func TestSetLanguage_Success(t *testing.T) {
+	var savedGuild, savedLanguage string
+	repo := &mockRepository{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			savedGuild = guildID
+			savedLanguage = language
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	if err := svc.SetLanguage(context.Background(), "guild-1", "pl"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if savedGuild != "guild-1" || savedLanguage != "pl" {
+		t.Errorf("unexpected args: guild=%s language=%s", savedGuild, savedLanguage)
+	}
+}
+
+func TestSetLanguage_Error(t *testing.T) {
+	repo := &mockRepository{
+		setGuildLanguageFunc: func(ctx context.Context, guildID, language string) error {
+			return errors.New("db error")
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	if err := svc.SetLanguage(context.Background(), "guild-1", "pl"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestSetTimezone_Success(t *testing.T) {
+	var savedGuild, savedTimezone string
+	repo := &mockRepository{
+		setGuildTimezoneFunc: func(ctx context.Context, guildID, timezone string) error {
+			savedGuild = guildID
+			savedTimezone = timezone
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	got, err := svc.SetTimezone(context.Background(), "guild-1", " Europe/Warsaw ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != "Europe/Warsaw" {
+		t.Errorf("expected Europe/Warsaw, got %s", got)
+	}
+	if savedGuild != "guild-1" || savedTimezone != "Europe/Warsaw" {
+		t.Errorf("unexpected args: guild=%s timezone=%s", savedGuild, savedTimezone)
+	}
+}
+
+func TestSetTimezone_Invalid(t *testing.T) {
+	for _, tz := range []string{"", "Local", "Mars/Olympus", "../etc/passwd"} {
+		t.Run(tz, func(t *testing.T) {
+			repo := &mockRepository{
+				setGuildTimezoneFunc: func(ctx context.Context, guildID, timezone string) error {
+					t.Error("expected invalid timezone not to be stored")
+					return nil
+				},
+			}
+
+			svc := NewConfigurationService(repo, nil, Limits{}, nil)
+			if _, err := svc.SetTimezone(context.Background(), "guild-1", tz); !errors.Is(err, ErrInvalidTimezone) {
+				t.Errorf("expected ErrInvalidTimezone, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSetDeathVerbosity_Invalid(t *testing.T) {
+	repo := &mockRepository{
+		setGuildDeathVerbosityFunc: func(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+			t.Error("expected invalid verbosity not to be stored")
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	if err := svc.SetDeathVerbosity(context.Background(), "guild-1", "chatty"); !errors.Is(err, ErrInvalidVerbosity) {
+		t.Errorf("expected ErrInvalidVerbosity, got %v", err)
+	}
+}
+
+func TestSetDeathRoute(t *testing.T) {
+	fullRoutes := []domain.DeathRoute{{MinLevel: 100}, {MinLevel: 200}, {MinLevel: 300}, {MinLevel: 400}, {MinLevel: 500}}
+
+	tests := []struct {
+		name     string
+		cfg      *domain.GuildConfig
+		minLevel int
+		saved    bool
+		wantErr  error
+	}{
+		{"new route", &domain.GuildConfig{World: "Antica"}, 500, true, nil},
+		{"no world tracked", nil, 500, false, ErrNoWorldTracked},
+		{"limit reached", &domain.GuildConfig{World: "Antica", DeathRoutes: fullRoutes}, 600, false, ErrTooManyDeathRoutes},
+		{"replace at limit", &domain.GuildConfig{World: "Antica", DeathRoutes: fullRoutes}, 300, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			repo := &mockRepository{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.cfg, nil
+				},
+				setDeathRouteFunc: func(ctx context.Context, guildID string, minLevel int, channelID string) error {
+					saved = true
+					return nil
+				},
+			}
+
+			svc := NewConfigurationService(repo, nil, Limits{}, nil)
+			err := svc.SetDeathRoute(context.Background(), "guild-1", tt.minLevel, "channel-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if saved != tt.saved {
+				t.Errorf("expected saved=%v, got %v", tt.saved, saved)
+			}
+		})
+	}
+}
+
+func TestMute_SetsMutedUntil(t *testing.T) {
+	var saved time.Time
+	repo := &mockRepository{
+		setGuildMutedUntilFunc: func(ctx context.Context, guildID string, until time.Time) error {
+			saved = until
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	before := time.Now()
+	until, err := svc.Mute(context.Background(), "guild-1", 3*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !saved.Equal(until) {
+		t.Errorf("expected saved %v to match returned %v", saved, until)
+	}
+	if until.Before(before.Add(3*time.Hour)) || until.After(time.Now().Add(3*time.Hour)) {
+		t.Errorf("expected mute to end in 3h, got %v", until)
+	}
+}
+
+func TestMute_ZeroUnmutes(t *testing.T) {
+	saved := time.Now()
+	repo := &mockRepository{
+		setGuildMutedUntilFunc: func(ctx context.Context, guildID string, until time.Time) error {
+			saved = until
+			return nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, nil, Limits{}, nil)
+	if _, err := svc.Mute(context.Background(), "guild-1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !saved.IsZero() {
+		t.Errorf("expected zero time to unmute, got %v", saved)
+	}
+}
+
+func TestIgnorePlayer_StoresCanonicalName(t *testing.T) {
+	var saved, looked string
+	repo := &mockRepository{
+		addIgnoredPlayerFunc: func(ctx context.Context, guildID, name string) error {
+			saved = name
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+			looked = name
+			return &domain.Player{Name: "Bubble'S Bot"}, nil
+		},
+	}
+
+	svc := NewConfigurationService(repo, fetcher, Limits{}, nil)
+	ignored, err := svc.IgnorePlayer(context.Background(), "guild-1", " bubble's bot ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if looked != "bubble's bot" {
+		t.Errorf("expected the trimmed name to be looked up, got '%s'", looked)
+	}
+	if ignored != "Bubble'S Bot" || saved != "Bubble'S Bot" {
+		t.Errorf("expected the TibiaData spelling, got '%s' and saved '%s'", ignored, saved)
+	}
+}
+
+func TestIgnorePlayer_DecodesName(t *testing.T) {
+	var looked string
+	fetcher := &mockFetcher{
+		fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+			looked = name
+			return &domain.Player{Name: "Bubble's Bot"}, nil
+		},
+	}
+
+	svc := NewConfigurationService(&mockRepository{}, fetcher, Limits{}, nil)
+	if _, err := svc.IgnorePlayer(context.Background(), "guild-1", "Bubble’s%20Bot"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if looked != "Bubble's Bot" {
+		t.Errorf("expected the normalized name to be looked up, got '%s'", looked)
+	}
+}
+
+func TestIgnorePlayer_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		player *domain.Player
+		err    error
+		want   error
+	}{
+		{"not found", nil, fmt.Errorf("character Ghost: %w", domain.ErrNotFound), domain.ErrNotFound},
+		{"empty character", &domain.Player{}, nil, domain.ErrNotFound},
+		{"upstream down", nil, domain.ErrUpstreamDown, domain.ErrUpstreamDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{
+				addIgnoredPlayerFunc: func(ctx context.Context, guildID, name string) error {
+					t.Error("a character that was not found must not be saved")
+					return nil
+				},
+			}
+			fetcher := &mockFetcher{
+				fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+					return tt.player, tt.err
+				},
+			}
+
+			_, err := NewConfigurationService(repo, fetcher, Limits{}, nil).IgnorePlayer(context.Background(), "guild-1", "Ghost")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWatchPlayer(t *testing.T) {
+	limits := Limits{WatchedPlayers: 3}
+	full := []string{"Player 1", "Player 2", "Player 3"}
+
+	tests := []struct {
+		name      string
+		cfg       *domain.GuildConfig
+		player    *domain.Player
+		want      error
+		wantLimit int
+		saved     string
+	}{
+		{"watches", &domain.GuildConfig{World: "Antica"}, &domain.Player{Name: "Mage", World: "Antica"}, nil, 0, "Mage"},
+		{"no world", &domain.GuildConfig{}, &domain.Player{Name: "Mage", World: "Antica"}, ErrNoWorldTracked, 0, ""},
+		{"too many", &domain.GuildConfig{World: "Antica", WatchedPlayers: full}, &domain.Player{Name: "Mage", World: "Antica"}, nil, 3, ""},
+		{"already watched at limit", &domain.GuildConfig{World: "Antica", WatchedPlayers: []string{"Player 1", "Player 2", "Mage"}}, &domain.Player{Name: "Mage", World: "Antica"}, nil, 0, "Mage"},
+		{"quota raises the limit", &domain.GuildConfig{World: "Antica", WatchedPlayers: full, Quota: domain.Quota{WatchedPlayers: 4}}, &domain.Player{Name: "Mage", World: "Antica"}, nil, 0, "Mage"},
+		{"quota lifts the limit", &domain.GuildConfig{World: "Antica", WatchedPlayers: full, Quota: domain.Quota{WatchedPlayers: -1}}, &domain.Player{Name: "Mage", World: "Antica"}, nil, 0, "Mage"},
+		{"not found", &domain.GuildConfig{World: "Antica"}, &domain.Player{}, domain.ErrNotFound, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := ""
+			repo := &mockRepository{
+				getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+					return tt.cfg, nil
+				},
+				addWatchedPlayerFunc: func(ctx context.Context, guildID, name string) error {
+					saved = name
+					return nil
+				},
+			}
+			fetcher := &mockFetcher{
+				fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+					return tt.player, nil
+				},
+			}
+
+			_, err := NewConfigurationService(repo, fetcher, limits, nil).WatchPlayer(context.Background(), "guild-1", " mage ")
+			var limitErr *LimitError
+			switch {
+			case tt.wantLimit > 0:
+				if !errors.As(err, &limitErr) || limitErr.Resource != LimitWatchedPlayers || limitErr.Limit != tt.wantLimit {
+					t.Errorf("expected a limit of %d watched players, got %v", tt.wantLimit, err)
+				}
+			case !errors.Is(err, tt.want):
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if saved != tt.saved {
+				t.Errorf("expected saved '%s', got '%s'", tt.saved, saved)
+			}
+		})
+	}
+
+	t.Run("other world", func(t *testing.T) {
+		repo := &mockRepository{
+			getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+				return &domain.GuildConfig{World: "Antica"}, nil
+			},
+		}
+		fetcher := &mockFetcher{
+			fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+				return &domain.Player{Name: "Mage", World: "Secura"}, nil
+			},
+		}
+
+		_, err := NewConfigurationService(repo, fetcher, Limits{}, nil).WatchPlayer(context.Background(), "guild-1", "Mage")
+		var worldErr *PlayerWorldError
+		if !errors.As(err, &worldErr) || worldErr.World != "Secura" || worldErr.TrackedWorld != "Antica" {
+			t.Errorf("expected a world error, got %v", err)
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	cfg := &domain.GuildConfig{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}, IgnoredPlayers: []string{"Alt"}}
+	var added []string
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return cfg, nil
+		},
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{*cfg, {DiscordGuildID: "g2", World: "Secura"}}, nil
+		},
+		addGuildToConfigFunc: func(ctx context.Context, guildID, guildName string) error {
+			added = append(added, guildName)
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{Name: name, World: "Antica"}, nil
+		},
+		fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+			return &domain.Player{Name: name}, nil
+		},
+	}
+	svc := NewConfigurationService(repo, fetcher, Limits{TibiaGuilds: 1, IgnoredPlayers: 1, Worlds: 2}, nil)
+	ctx := context.Background()
+
+	var limitErr *LimitError
+	if _, err := svc.AddGuildToTrack(ctx, "g1", "Blue Moon"); !errors.As(err, &limitErr) || limitErr.Resource != LimitTibiaGuilds || limitErr.Limit != 1 {
+		t.Errorf("expected the Tibia guild limit, got %v", err)
+	}
+	if _, err := svc.AddGuildToTrack(ctx, "g1", "red rose"); err != nil {
+		t.Errorf("expected re-adding a tracked guild to pass, got %v", err)
+	}
+	if _, err := svc.IgnorePlayer(ctx, "g1", "Other"); !errors.As(err, &limitErr) || limitErr.Resource != LimitIgnoredPlayers {
+		t.Errorf("expected the ignored player limit, got %v", err)
+	}
+
+	cfg.Quota = domain.Quota{TibiaGuilds: 3, IgnoredPlayers: -1}
+	if _, err := svc.AddGuildToTrack(ctx, "g1", "Blue Moon"); err != nil || added[len(added)-1] != "Blue Moon" {
+		t.Errorf("expected the quota to raise the limit, got %v", err)
+	}
+	if err := svc.CheckIgnorePlayer(ctx, "g1", "Other"); err != nil {
+		t.Errorf("expected a negative quota to lift the limit, got %v", err)
+	}
+
+	cfg.Quota = domain.Quota{}
+	svc.caps = NewCapabilityService(Premium{Gating: true, TibiaGuilds: 5})
+	cfg.TibiaGuilds = []string{"Red Rose", "Blue Moon"}
+	if err := svc.CheckAddGuild(ctx, "g1", "Green Leaf"); !errors.As(err, &limitErr) || limitErr.Limit != 1 {
+		t.Errorf("expected free guilds to keep the bot-wide limit, got %v", err)
+	}
+	cfg.Premium = true
+	if err := svc.CheckAddGuild(ctx, "g1", "Green Leaf"); err != nil {
+		t.Errorf("expected the premium limit to apply, got %v", err)
+	}
+
+	if _, err := svc.SetWorld(ctx, "g3", "refugia"); !errors.As(err, &limitErr) || limitErr.Resource != LimitWorlds || limitErr.Limit != 2 {
+		t.Errorf("expected the world limit, got %v", err)
+	}
+	if _, err := svc.SetWorld(ctx, "g3", "secura"); err != nil {
+		t.Errorf("expected an already tracked world to pass, got %v", err)
+	}
+	if _, err := svc.SetWorld(ctx, "g1", "refugia"); err != nil {
+		t.Errorf("expected a guild to switch away from its own world, got %v", err)
+	}
+}
+
+// mockRepositoryTx runs a transaction's calls against the mock itself and counts how
+// it ended on the mock. Rollbacks after a commit are not counted.
+type mockRepositoryTx struct {
+	*mockRepository
+	committed bool
+}
+
+func (t *mockRepositoryTx) Commit(ctx context.Context) error {
+	t.committed = true
+	t.commits++
+	return nil
+}
+
+func (t *mockRepositoryTx) Rollback(ctx context.Context) error {
+	if !t.committed {
+		t.rollbacks++
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// ExportFormat is the file format /export writes.
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+const (
+	exportPageSize = 1000
+	// MaxExportRows keeps exported files within Discord's attachment size
+	// limit.
+	MaxExportRows = 50000
+)
+
+// ExportResult counts the rows an export wrote. Truncated means more rows
+// matched than MaxExportRows.
+type ExportResult struct {
+	Rows      int
+	Truncated bool
+}
+
+// ExportService writes the deaths or level ups recorded for a guild's world
+// as CSV or JSON, paging through storage so large exports are not loaded at
+// once.
+type ExportService struct {
+	repo ports.Repository
+}
+
+func NewExportService(repo ports.Repository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// Export writes the guild's deaths or level ups since the given time to w in
+// the order they were recorded. Like the notifications, it covers only
+// members of the guild's Tibia guilds when it tracks any.
+func (s *ExportService) Export(ctx context.Context, guild domain.GuildConfig, kind domain.NotificationChannel, format ExportFormat, since time.Time, w io.Writer) (ExportResult, error) {
+	if guild.World == "" {
+		return ExportResult{}, ErrNoWorldTracked
+	}
+
+	switch kind {
+	case domain.ChannelDeaths:
+		enc, err := newExportEncoder(format, w, deathExportHeader)
+		if err != nil {
+			return ExportResult{}, err
+		}
+		return exportPages(enc, func(afterID int64) ([]domain.DeathRecord, error) {
+			return s.repo.GetDeathsPage(ctx, guild.World, guild.TibiaGuilds, since, afterID, exportPageSize)
+		}, func(d domain.DeathRecord) (int64, exportRow) {
+			return d.ID, exportedDeath{
+				ID:      d.ID,
+				Name:    d.Name,
+				World:   d.World,
+				Level:   d.Level,
+				Reason:  d.Reason,
+				Killers: d.Killers,
+				DiedAt:  d.DiedAt.UTC(),
+			}
+		})
+	case domain.ChannelLevels:
+		enc, err := newExportEncoder(format, w, levelUpExportHeader)
+		if err != nil {
+			return ExportResult{}, err
+		}
+		return exportPages(enc, func(afterID int64) ([]domain.LevelUpRecord, error) {
+			return s.repo.GetLevelUpsPage(ctx, guild.World, guild.TibiaGuilds, since, afterID, exportPageSize)
+		}, func(l domain.LevelUpRecord) (int64, exportRow) {
+			return l.ID, exportedLevelUp{
+				ID:        l.ID,
+				Name:      l.Name,
+				World:     l.World,
+				OldLevel:  l.OldLevel,
+				NewLevel:  l.NewLevel,
+				ReachedAt: l.ReachedAt.UTC(),
+			}
+		})
+	default:
+		return ExportResult{}, fmt.Errorf("no export for %s", kind)
+	}
+}
+
+// exportPages fetches pages after the last ID written until one comes back
+// short, stopping at MaxExportRows.
+func exportPages[T any](enc exportEncoder, fetch func(afterID int64) ([]T, error), row func(T) (int64, exportRow)) (ExportResult, error) {
+	var result ExportResult
+	var afterID int64
+	for {
+		page, err := fetch(afterID)
+		if err != nil {
+			return result, err
+		}
+		for _, item := range page {
+			if result.Rows == MaxExportRows {
+				result.Truncated = true
+				return result, enc.Close()
+			}
+			id, r := row(item)
+			if err := enc.Write(r); err != nil {
+				return result, err
+			}
+			afterID = id
+			result.Rows++
+		}
+		if len(page) < exportPageSize {
+			return result, enc.Close()
+		}
+	}
+}
+
+var (
+	deathExportHeader   = []string{"id", "name", "world", "level", "reason", "killers", "died_at"}
+	levelUpExportHeader = []string{"id", "name", "world", "old_level", "new_level", "reached_at"}
+)
+
+// exportRow is one exported record. JSON exports marshal it as is; CSV
+// exports write its csvRecord.
+type exportRow interface {
+	csvRecord() []string
+}
+
+type exportedDeath struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	World   string    `json:"world"`
+	Level   int       `json:"level"`
+	Reason  string    `json:"reason"`
+	Killers []string  `json:"killers"`
+	DiedAt  time.Time `json:"died_at"`
+}
+
+func (d exportedDeath) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(d.ID, 10),
+		d.Name,
+		d.World,
+		strconv.Itoa(d.Level),
+		d.Reason,
+		strings.Join(d.Killers, "; "),
+		d.DiedAt.Format(time.RFC3339),
+	}
+}
+
+type exportedLevelUp struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	World     string    `json:"world"`
+	OldLevel  int       `json:"old_level"`
+	NewLevel  int       `json:"new_level"`
+	ReachedAt time.Time `json:"reached_at"`
+}
+
+func (l exportedLevelUp) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(l.ID, 10),
+		l.Name,
+		l.World,
+		strconv.Itoa(l.OldLevel),
+		strconv.Itoa(l.NewLevel),
+		l.ReachedAt.Format(time.RFC3339),
+	}
+}
+
+type exportEncoder interface {
+	Write(row exportRow) error
+	Close() error
+}
+
+func newExportEncoder(format ExportFormat, w io.Writer, header []string) (exportEncoder, error) {
+	switch format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvExportEncoder{w: cw}, nil
+	case ExportJSON:
+		return &jsonExportEncoder{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvExportEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvExportEncoder) Write(row exportRow) error {
+	return e.w.Write(row.csvRecord())
+}
+
+func (e *csvExportEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExportEncoder streams rows as one JSON array, one row per line.
+type jsonExportEncoder struct {
+	w    io.Writer
+	rows int
+}
+
+func (e *jsonExportEncoder) Write(row exportRow) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.rows == 0 {
+		sep = "[\n"
+	}
+	e.rows++
+	_, err = fmt.Fprintf(e.w, "%s%s", sep, data)
+	return err
+}
+
+func (e *jsonExportEncoder) Close() error {
+	if e.rows == 0 {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+var exportGuild = domain.GuildConfig{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}}
+
+// pagedDeaths serves total deaths with IDs 1..total through GetDeathsPage.
+func pagedDeaths(t *testing.T, total int) *mockRepository {
+	return &mockRepository{
+		getDeathsPageFunc: func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+			if world != "Antica" || len(guildNames) != 1 || guildNames[0] != "Red Rose" {
+				t.Errorf("unexpected filter: world=%s guilds=%v", world, guildNames)
+			}
+			var page []domain.DeathRecord
+			for id := afterID + 1; id <= int64(total) && len(page) < limit; id++ {
+				page = append(page, domain.DeathRecord{
+					ID:      id,
+					Name:    "Hero",
+					World:   "Antica",
+					Level:   300,
+					Reason:  "Killed at Level 300 by Foe and a dragon",
+					Killers: []string{"Foe"},
+					DiedAt:  time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC),
+				})
+			}
+			return page, nil
+		},
+	}
+}
+
+func TestExport_DeathsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	result, err := NewExportService(pagedDeaths(t, exportPageSize+1)).Export(context.Background(), exportGuild, domain.ChannelDeaths, ExportCSV, time.Time{}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (ExportResult{Rows: exportPageSize + 1}) {
+		t.Errorf("expected every page exported, got %+v", result)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != exportPageSize+2 {
+		t.Fatalf("expected a header and %d rows, got %d records", exportPageSize+1, len(records))
+	}
+	want := []string{"1", "Hero", "Antica", "300", "Killed at Level 300 by Foe and a dragon", "Foe", "2026-04-01T12:00:00Z"}
+	for i, field := range want {
+		if records[1][i] != field {
+			t.Errorf("field %s: expected %q, got %q", records[0][i], field, records[1][i])
+		}
+	}
+}
+
+func TestExport_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	result, err := NewExportService(pagedDeaths(t, MaxExportRows+1)).Export(context.Background(), exportGuild, domain.ChannelDeaths, ExportCSV, time.Time{}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (ExportResult{Rows: MaxExportRows, Truncated: true}) {
+		t.Errorf("expected the export to stop at the limit, got %+v", result)
+	}
+}
+
+func TestExport_LevelUpsJSON(t *testing.T) {
+	repo := &mockRepository{
+		getLevelUpsPageFunc: func(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+			if afterID > 0 {
+				return nil, nil
+			}
+			return []domain.LevelUpRecord{
+				{ID: 7, Name: "Hero", World: "Antica", OldLevel: 299, NewLevel: 300},
+				{ID: 9, Name: "Other", World: "Antica", OldLevel: 99, NewLevel: 100},
+			}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	result, err := NewExportService(repo).Export(context.Background(), exportGuild, domain.ChannelLevels, ExportJSON, time.Time{}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rows []exportedLevelUp
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if result.Rows != 2 || len(rows) != 2 || rows[1].ID != 9 || rows[1].NewLevel != 100 {
+		t.Errorf("unexpected export %+v: %+v", result, rows)
+	}
+}
+
+func TestExport_EmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewExportService(&mockRepository{}).Export(context.Background(), exportGuild, domain.ChannelLevels, ExportJSON, time.Time{}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("expected an empty array, got %q", buf.String())
+	}
+}
+
+func TestExport_NoWorldTracked(t *testing.T) {
+	_, err := NewExportService(&mockRepository{}).Export(context.Background(), domain.GuildConfig{}, domain.ChannelDeaths, ExportCSV, time.Time{}, &bytes.Buffer{})
+	if !errors.Is(err, ErrNoWorldTracked) {
+		t.Errorf("expected ErrNoWorldTracked, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// staleAuctionAge bounds how old a stored auction may be to count as still
+// running, so a long outage does not announce every stored auction as ended.
+const staleAuctionAge = 24 * time.Hour
+
+// HouseService announces house auctions that start or end on worlds where a
+// guild configured a house channel with /track-houses.
+type HouseService struct {
+	config   *config.Config
+	repo     ports.Repository
+	fetcher  ports.TibiaFetcher
+	notifier ports.NotificationService
+	// leader gates polling when several replicas run; nil means always lead.
+	leader ports.LeaderElector
+	now    func() time.Time
+
+	mu     sync.Mutex
+	primed map[string]bool
+}
+
+func NewHouseService(cfg *config.Config, repo ports.Repository, fetcher ports.TibiaFetcher, notifier ports.NotificationService, leader ports.LeaderElector) *HouseService {
+	return &HouseService{
+		config:   cfg,
+		repo:     repo,
+		fetcher:  fetcher,
+		notifier: notifier,
+		leader:   leader,
+		now:      time.Now,
+		primed:   make(map[string]bool),
+	}
+}
+
+// Start polls house auctions every HOUSE_POLL_INTERVAL until ctx is cancelled.
+func (s *HouseService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.HousePollInterval)
+	defer ticker.Stop()
+
+	slog.Info("House auction service started", "interval", s.config.HousePollInterval)
+
+	s.runCycle(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runCycle(ctx)
+		}
+	}
+}
+
+func (s *HouseService) runCycle(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs", "error", err)
+		return
+	}
+
+	worlds := make(map[string][]domain.GuildConfig)
+	for _, cfg := range configs {
+		if cfg.World == "" || cfg.HouseChannelID == "" {
+			continue
+		}
+		worlds[cfg.World] = append(worlds[cfg.World], cfg)
+	}
+
+	s.mu.Lock()
+	for world := range s.primed {
+		if _, ok := worlds[world]; !ok {
+			delete(s.primed, world)
+		}
+	}
+	s.mu.Unlock()
+
+	for world, guilds := range worlds {
+		if err := s.processWorld(ctx, world, guilds); err != nil {
+			slog.ErrorContext(ctx, "Failed to check house auctions", "world", world, "error", err)
+		}
+	}
+}
+
+// processWorld compares the running auctions of world with the stored ones
+// and announces the difference. The first check of a world with nothing
+// stored only records the auctions, so enabling the feature does not post
+// every auction already running.
+func (s *HouseService) processWorld(ctx context.Context, world string, guilds []domain.GuildConfig) error {
+	current, err := s.fetcher.FetchHouseAuctions(ctx, world)
+	if err != nil {
+		return fmt.Errorf("fetch auctions: %w", err)
+	}
+
+	now := s.now()
+	stored, err := s.repo.GetHouseAuctions(ctx, world, now.Add(-staleAuctionAge))
+	if err != nil {
+		return fmt.Errorf("load auctions: %w", err)
+	}
+
+	s.mu.Lock()
+	baseline := len(stored) == 0 && !s.primed[world]
+	s.mu.Unlock()
+
+	if baseline {
+		slog.InfoContext(ctx, "Recording running house auctions", "world", world, "count", len(current))
+	} else {
+		s.announce(ctx, guilds, current, stored, now)
+	}
+
+	if err := s.repo.ReplaceHouseAuctions(ctx, world, current); err != nil {
+		return fmt.Errorf("store auctions: %w", err)
+	}
+
+	s.mu.Lock()
+	s.primed[world] = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *HouseService) announce(ctx context.Context, guilds []domain.GuildConfig, current, stored []domain.HouseAuction, now time.Time) {
+	running := make(map[int]bool, len(current))
+	for _, a := range current {
+		running[a.HouseID] = true
+	}
+	known := make(map[int]bool, len(stored))
+	for _, a := range stored {
+		known[a.HouseID] = true
+	}
+
+	for _, a := range current {
+		if !known[a.HouseID] {
+			s.notify(ctx, guilds, a, false, now)
+		}
+	}
+	for _, a := range stored {
+		if !running[a.HouseID] {
+			s.notify(ctx, guilds, a, true, now)
+		}
+	}
+}
+
+func (s *HouseService) notify(ctx context.Context, guilds []domain.GuildConfig, auction domain.HouseAuction, ended bool, now time.Time) {
+	for _, guild := range guilds {
+		if guild.IsMuted(now) {
+			continue
+		}
+		if err := s.notifier.SendHouseAuctionNotification(guild, auction, ended); err != nil {
+			slog.ErrorContext(ctx, "Failed to send house auction notification", "guild_id", guild.DiscordGuildID, "house", auction.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+type houseAnnouncement struct {
+	guildID string
+	houseID int
+	ended   bool
+}
+
+func newTestHouseService(stored, current []domain.HouseAuction, guilds []domain.GuildConfig) (*HouseService, *[]houseAnnouncement, *[]domain.HouseAuction) {
+	var sent []houseAnnouncement
+	var replaced []domain.HouseAuction
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return guilds, nil
+		},
+		getHouseAuctionsFunc: func(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+			return stored, nil
+		},
+		replaceHouseAuctionsFunc: func(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+			replaced = auctions
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchHousesFunc: func(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+			return current, nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendHouseFunc: func(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+			sent = append(sent, houseAnnouncement{guild.DiscordGuildID, auction.HouseID, ended})
+			return nil
+		},
+	}
+	svc := NewHouseService(&config.Config{HousePollInterval: 30 * time.Minute}, repo, fetcher, notifier, nil)
+	return svc, &sent, &replaced
+}
+
+func TestHouseService_AnnouncesStartedAndEndedAuctions(t *testing.T) {
+	stored := []domain.HouseAuction{{HouseID: 1, Name: "Old House"}, {HouseID: 2, Name: "Kept House"}}
+	current := []domain.HouseAuction{{HouseID: 2, Name: "Kept House"}, {HouseID: 3, Name: "New House"}}
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica", HouseChannelID: "c1"},
+		{DiscordGuildID: "g2", World: "Antica"},
+		{DiscordGuildID: "g3", World: "Antica", HouseChannelID: "c3", MutedUntil: time.Now().Add(time.Hour)},
+	}
+	svc, sent, replaced := newTestHouseService(stored, current, guilds)
+
+	svc.runCycle(context.Background())
+
+	want := []houseAnnouncement{{"g1", 3, false}, {"g1", 1, true}}
+	if len(*sent) != len(want) {
+		t.Fatalf("expected %d announcements, got %+v", len(want), *sent)
+	}
+	for i, a := range want {
+		if (*sent)[i] != a {
+			t.Errorf("announcement %d: expected %+v, got %+v", i, a, (*sent)[i])
+		}
+	}
+	if len(*replaced) != 2 {
+		t.Errorf("expected current auctions to be stored, got %+v", *replaced)
+	}
+}
+
+func TestHouseService_FirstCheckRecordsSilently(t *testing.T) {
+	current := []domain.HouseAuction{{HouseID: 3, Name: "New House"}}
+	guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", HouseChannelID: "c1"}}
+	svc, sent, replaced := newTestHouseService(nil, current, guilds)
+
+	svc.runCycle(context.Background())
+
+	if len(*sent) != 0 {
+		t.Errorf("expected no announcements on the first check, got %+v", *sent)
+	}
+	if len(*replaced) != 1 {
+		t.Errorf("expected running auctions to be recorded, got %+v", *replaced)
+	}
+
+	// Once primed, an empty store no longer means a fresh start.
+	svc.runCycle(context.Background())
+	if len(*sent) != 1 || (*sent)[0].ended {
+		t.Errorf("expected the auction to be announced once primed, got %+v", *sent)
+	}
+}
+
+func TestHouseService_FetchErrorKeepsStoredAuctions(t *testing.T) {
+	replaced := false
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", HouseChannelID: "c1"}}, nil
+		},
+		replaceHouseAuctionsFunc: func(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+			replaced = true
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchHousesFunc: func(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+			return nil, errors.New("api down")
+		},
+	}
+	svc := NewHouseService(&config.Config{}, repo, fetcher, &mockNotifier{}, nil)
+
+	svc.runCycle(context.Background())
+
+	if replaced {
+		t.Error("expected stored auctions to be left untouched when fetching fails")
+	}
+}
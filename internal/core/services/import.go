@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+const (
+	importBatchSize = 1000
+	// maxImportNameLength matches the players.name column.
+	maxImportNameLength = 64
+	maxImportLevel      = 5000
+)
+
+// importColumns maps the header names used by TibiaLC, GuildStats and
+// similar trackers' CSV exports to the column they hold.
+var importColumns = map[string]string{
+	"name":      "name",
+	"character": "name",
+	"char":      "name",
+	"player":    "name",
+	"nick":      "name",
+	"level":     "level",
+	"lvl":       "level",
+	"world":     "world",
+	"server":    "world",
+}
+
+// ImportRowError is a row the import skipped, by its line in the file.
+type ImportRowError struct {
+	Line   int
+	Reason string
+}
+
+// ImportSummary describes what an import did, or would do on a dry run.
+type ImportSummary struct {
+	Rows      int
+	Invalid   []ImportRowError
+	Duplicate int
+	// Added and Updated players were written. Unchanged players are stored
+	// at the imported level or higher and were left alone, so an old export
+	// cannot lower a level and trigger level ups for levels already seen.
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+// ImportService seeds stored player levels from other trackers' CSV exports,
+// like BackfillService does from a Tibia guild's roster.
+type ImportService struct {
+	repo ports.Repository
+}
+
+func NewImportService(repo ports.Repository) *ImportService {
+	return &ImportService{repo: repo}
+}
+
+// ImportLevels reads a CSV of characters and levels and stores them. The
+// file needs a header row with name and level columns; rows without a world
+// column use defaultWorld. Invalid rows are reported and skipped, and when a
+// character appears more than once the highest level wins. With dryRun
+// nothing is written.
+func (s *ImportService) ImportLevels(ctx context.Context, r io.Reader, defaultWorld string, dryRun bool) (ImportSummary, error) {
+	levels, summary, err := parseImport(r, defaultWorld)
+	if err != nil {
+		return summary, err
+	}
+
+	worlds := make(map[string][]domain.PlayerLevel)
+	for _, l := range levels {
+		worlds[l.World] = append(worlds[l.World], l)
+	}
+
+	var changed []domain.PlayerLevel
+	for world, players := range worlds {
+		stored, err := s.repo.GetPlayersLevels(ctx, world)
+		if err != nil {
+			return summary, fmt.Errorf("get %s levels: %w", world, err)
+		}
+		for _, p := range players {
+			current, ok := stored[p.Name]
+			switch {
+			case !ok:
+				summary.Added++
+			case current < p.Level:
+				summary.Updated++
+			default:
+				summary.Unchanged++
+				continue
+			}
+			changed = append(changed, p)
+		}
+	}
+
+	if dryRun {
+		return summary, nil
+	}
+	for start := 0; start < len(changed); start += importBatchSize {
+		batch := changed[start:min(start+importBatchSize, len(changed))]
+		if err := s.repo.BatchUpsertPlayerLevels(ctx, batch); err != nil {
+			return summary, fmt.Errorf("store levels: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// parseImport validates every row, returning the valid ones in file order.
+func parseImport(r io.Reader, defaultWorld string) ([]domain.PlayerLevel, ImportSummary, error) {
+	var summary ImportSummary
+
+	br := bufio.NewReader(r)
+	cr := csv.NewReader(br)
+	cr.Comma = detectSeparator(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, summary, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, summary, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if column, ok := importColumns[name]; ok {
+			if _, seen := columns[column]; !seen {
+				columns[column] = i
+			}
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, summary, errors.New("header has no name column")
+	}
+	if _, ok := columns["level"]; !ok {
+		return nil, summary, errors.New("header has no level column")
+	}
+	if _, ok := columns["world"]; !ok && defaultWorld == "" {
+		return nil, summary, errors.New("header has no world column and no default world was given")
+	}
+
+	var levels []domain.PlayerLevel
+	index := make(map[string]int)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, summary, fmt.Errorf("read row: %w", err)
+			}
+			summary.Rows++
+			summary.Invalid = append(summary.Invalid, ImportRowError{Line: parseErr.Line, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		summary.Rows++
+
+		line, _ := cr.FieldPos(0)
+		level, reason := parseImportRow(record, columns, defaultWorld)
+		if reason != "" {
+			summary.Invalid = append(summary.Invalid, ImportRowError{Line: line, Reason: reason})
+			continue
+		}
+		key := strings.ToLower(level.Name)
+		if i, ok := index[key]; ok {
+			summary.Duplicate++
+			if level.Level > levels[i].Level {
+				levels[i] = level
+			}
+			continue
+		}
+		index[key] = len(levels)
+		levels = append(levels, level)
+	}
+	return levels, summary, nil
+}
+
+// parseImportRow returns the row's character, or why it is invalid.
+func parseImportRow(record []string, columns map[string]int, defaultWorld string) (domain.PlayerLevel, string) {
+	field := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	name := field("name")
+	switch {
+	case name == "":
+		return domain.PlayerLevel{}, "missing name"
+	case utf8.RuneCountInString(name) > maxImportNameLength:
+		return domain.PlayerLevel{}, fmt.Sprintf("name longer than %d characters", maxImportNameLength)
+	case !validCharacterName(name):
+		return domain.PlayerLevel{}, fmt.Sprintf("invalid name %q", name)
+	}
+
+	level, err := strconv.Atoi(field("level"))
+	if err != nil || level < 1 || level > maxImportLevel {
+		return domain.PlayerLevel{}, fmt.Sprintf("invalid level %q", field("level"))
+	}
+
+	world := field("world")
+	if world == "" {
+		world = defaultWorld
+	}
+	if world == "" {
+		return domain.PlayerLevel{}, "missing world"
+	}
+	return domain.PlayerLevel{Name: name, Level: level, World: world}, ""
+}
+
+// validCharacterName accepts the letters, spaces, apostrophes and hyphens
+// Tibia allows in character names.
+func validCharacterName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == ' ' || r == '\'' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// detectSeparator picks semicolons for exports from spreadsheet locales that
+// use them, and commas otherwise.
+func detectSeparator(br *bufio.Reader) rune {
+	line, _ := br.Peek(br.Size())
+	if i := strings.IndexByte(string(line), '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if strings.Count(string(line), ";") > strings.Count(string(line), ",") {
+		return ';'
+	}
+	return ','
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
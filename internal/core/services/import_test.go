@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestImportLevels(t *testing.T) {
+	var stored []domain.PlayerLevel
+	repo := &mockRepository{
+		getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			if world == "Antica" {
+				return map[string]int{"Alice": 300, "Bob": 200}, nil
+			}
+			return nil, nil
+		},
+		batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+			stored = append(stored, levels...)
+			return nil
+		},
+	}
+
+	csv := "\ufeffCharacter,Vocation,Level,World\n" +
+		"Alice,Knight,250,\n" +
+		"Bob,Druid,210,Antica\n" +
+		"Carol,Sorcerer,100,Secura\n" +
+		"carol,Sorcerer,120,Secura\n" +
+		"Dave1,Paladin,50,\n" +
+		"Eve,Paladin,lots,\n" +
+		"\n"
+	summary, err := NewImportService(repo).ImportLevels(context.Background(), strings.NewReader(csv), "Antica", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := ImportSummary{
+		Rows:      6,
+		Invalid:   []ImportRowError{{Line: 6, Reason: `invalid name "Dave1"`}, {Line: 7, Reason: `invalid level "lots"`}},
+		Duplicate: 1,
+		Added:     1,
+		Updated:   1,
+		Unchanged: 1,
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("expected %+v, got %+v", want, summary)
+	}
+
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Name < stored[j].Name })
+	wantStored := []domain.PlayerLevel{
+		{Name: "Bob", Level: 210, World: "Antica"},
+		{Name: "carol", Level: 120, World: "Secura"},
+	}
+	if !reflect.DeepEqual(stored, wantStored) {
+		t.Errorf("expected only new and raised levels stored, got %+v", stored)
+	}
+}
+
+func TestImportLevels_DryRun(t *testing.T) {
+	repo := &mockRepository{
+		batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+			t.Error("expected a dry run not to write")
+			return nil
+		},
+	}
+
+	summary, err := NewImportService(repo).ImportLevels(context.Background(), strings.NewReader("name;level;world\nAlice;300;Antica\n"), "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Rows != 1 || summary.Added != 1 {
+		t.Errorf("expected the semicolon separated row to be counted, got %+v", summary)
+	}
+}
+
+func TestImportLevels_BadHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		csv   string
+		world string
+	}{
+		{"empty", "", "Antica"},
+		{"no name", "vocation,level\nKnight,100\n", "Antica"},
+		{"no level", "name,world\nAlice,Antica\n", ""},
+		{"no world", "name,level\nAlice,100\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewImportService(&mockRepository{}).ImportLevels(context.Background(), strings.NewReader(tt.csv), tt.world, true); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
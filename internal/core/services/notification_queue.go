@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+const (
+	retryInterval  = time.Minute
+	retryBatchSize = 50
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+// RetryResult summarises one pass over the failed notification queue.
+type RetryResult struct {
+	Sent    int
+	Failed  int
+	Dropped int
+}
+
+type deathPayload struct {
+	Player domain.Player
+	Kill   domain.Kill
+}
+
+type deathStreakPayload struct {
+	PlayerName string
+	Deaths     int
+}
+
+type housePayload struct {
+	Auction domain.HouseAuction
+	Ended   bool
+}
+
+// NotificationQueue wraps a NotificationService and persists notifications
+// that fail to send, retrying them with exponential backoff until they are
+// delivered or older than maxAge.
+type NotificationQueue struct {
+	repo     ports.Repository
+	notifier ports.NotificationService
+	leader   ports.LeaderElector
+	maxAge   time.Duration
+	now      func() time.Time
+}
+
+// NewNotificationQueue wraps notifier. When leader is non-nil, only the
+// leading replica retries queued notifications so they are not sent twice.
+func NewNotificationQueue(repo ports.Repository, notifier ports.NotificationService, leader ports.LeaderElector, maxAge time.Duration) *NotificationQueue {
+	return &NotificationQueue{
+		repo:     repo,
+		notifier: notifier,
+		leader:   leader,
+		maxAge:   maxAge,
+		now:      time.Now,
+	}
+}
+
+func (q *NotificationQueue) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+	err := q.notifier.SendLevelUpNotification(guild, levelUp)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationLevelUp, levelUp, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	err := q.notifier.SendLevelDownNotification(guild, levelDown)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationLevelDown, levelDown, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+	err := q.notifier.SendDeathNotification(guild, player, kill)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationDeath, deathPayload{Player: player, Kill: kill}, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	err := q.notifier.SendDeathStreakNotification(guild, playerName, deaths)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationDeathStreak, deathStreakPayload{PlayerName: playerName, Deaths: deaths}, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	err := q.notifier.SendMassDeathNotification(guild, massDeath)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationMassDeath, massDeath, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	err := q.notifier.SendMembershipNotification(guild, change)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationMembership, change, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	err := q.notifier.SendGuildWarNotification(guild, war)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationGuildWar, war, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	err := q.notifier.SendHouseAuctionNotification(guild, auction, ended)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationHouse, housePayload{Auction: auction, Ended: ended}, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	err := q.notifier.SendCharacterChangeNotification(guild, change)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationCharacter, change, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	err := q.notifier.SendSkillAdvanceNotification(guild, advance)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationSkill, advance, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+// SendRashidNotification is not queued: a missed daily post is superseded by
+// the next one.
+func (q *NotificationQueue) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	if err := q.notifier.SendRashidNotification(guild, city); err != nil {
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+// SendBoostedNotification is not queued either; the boost only lasts until
+// the next server save.
+func (q *NotificationQueue) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	if err := q.notifier.SendBoostedNotification(guild, boosted); err != nil {
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+	err := q.notifier.SendCatchUpNotification(guild, catchUp)
+	if err != nil {
+		q.enqueue(guild.DiscordGuildID, domain.NotificationCatchUp, catchUp, err)
+		return err
+	}
+	q.recordDelivery(guild.DiscordGuildID)
+	return nil
+}
+
+func (q *NotificationQueue) SendGenericMessage(guildID, channelName, message string) error {
+	return q.notifier.SendGenericMessage(guildID, channelName, message)
+}
+
+// Start retries due notifications every minute until ctx is cancelled.
+func (q *NotificationQueue) Start(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if q.leader != nil && !q.leader.IsLeader(ctx) {
+				continue
+			}
+			if _, err := q.RetryDue(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to retry notifications", "error", err)
+			}
+		}
+	}
+}
+
+// RetryDue drops expired notifications and retries those whose backoff has
+// elapsed.
+func (q *NotificationQueue) RetryDue(ctx context.Context) (RetryResult, error) {
+	now := q.now()
+
+	expired, err := q.repo.DeleteExpiredFailedNotifications(ctx, now.Add(-q.maxAge))
+	if err != nil {
+		return RetryResult{}, err
+	}
+	if expired > 0 {
+		slog.WarnContext(ctx, "Dropped expired notifications", "count", expired, "max_age", q.maxAge)
+		metrics.NotificationRetries.WithLabelValues("dropped").Add(float64(expired))
+	}
+
+	pending, err := q.repo.GetDueFailedNotifications(ctx, now, retryBatchSize)
+	if err != nil {
+		return RetryResult{}, err
+	}
+
+	result := q.retry(ctx, pending)
+	result.Dropped += int(expired)
+	return result, nil
+}
+
+// Flush immediately retries every queued notification for a Discord guild,
+// ignoring backoff.
+func (q *NotificationQueue) Flush(ctx context.Context, discordGuildID string) (RetryResult, error) {
+	pending, err := q.repo.GetGuildFailedNotifications(ctx, discordGuildID)
+	if err != nil {
+		return RetryResult{}, err
+	}
+	return q.retry(ctx, pending), nil
+}
+
+func (q *NotificationQueue) retry(ctx context.Context, pending []domain.FailedNotification) RetryResult {
+	var result RetryResult
+	configs := make(map[string]*domain.GuildConfig)
+
+	for _, n := range pending {
+		cfg, ok := configs[n.DiscordGuildID]
+		if !ok {
+			var err error
+			cfg, err = q.repo.GetGuildConfig(ctx, n.DiscordGuildID)
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to get guild config for retry", "guild_id", n.DiscordGuildID, "error", err)
+				continue
+			}
+			configs[n.DiscordGuildID] = cfg
+		}
+
+		if cfg == nil || cfg.IsMuted(q.now()) || q.now().Sub(n.CreatedAt) > q.maxAge {
+			q.drop(ctx, n)
+			result.Dropped++
+			continue
+		}
+		if cfg.InQuietHours(q.now()) {
+			continue
+		}
+
+		if err := q.dispatch(*cfg, n); err != nil {
+			q.reschedule(ctx, n, err)
+			result.Failed++
+			continue
+		}
+
+		q.recordDelivery(n.DiscordGuildID)
+		if err := q.repo.DeleteFailedNotification(ctx, n.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to remove delivered notification", "id", n.ID, "error", err)
+		}
+		metrics.NotificationRetries.WithLabelValues("sent").Inc()
+		result.Sent++
+	}
+
+	if len(pending) > 0 {
+		slog.InfoContext(ctx, "Retried failed notifications", "sent", result.Sent, "failed", result.Failed, "dropped", result.Dropped)
+	}
+	return result
+}
+
+// dispatch re-sends a queued notification using the guild's current config,
+// so channel changes made since the failure are picked up.
+func (q *NotificationQueue) dispatch(guild domain.GuildConfig, n domain.FailedNotification) error {
+	switch n.Kind {
+	case domain.NotificationLevelUp:
+		var levelUp domain.LevelUp
+		if err := json.Unmarshal(n.Payload, &levelUp); err != nil {
+			return fmt.Errorf("decode level up: %w", err)
+		}
+		return q.notifier.SendLevelUpNotification(guild, levelUp)
+	case domain.NotificationLevelDown:
+		var levelDown domain.LevelDown
+		if err := json.Unmarshal(n.Payload, &levelDown); err != nil {
+			return fmt.Errorf("decode level down: %w", err)
+		}
+		return q.notifier.SendLevelDownNotification(guild, levelDown)
+	case domain.NotificationDeath:
+		var p deathPayload
+		if err := json.Unmarshal(n.Payload, &p); err != nil {
+			return fmt.Errorf("decode death: %w", err)
+		}
+		return q.notifier.SendDeathNotification(guild, p.Player, p.Kill)
+	case domain.NotificationDeathStreak:
+		var p deathStreakPayload
+		if err := json.Unmarshal(n.Payload, &p); err != nil {
+			return fmt.Errorf("decode death streak: %w", err)
+		}
+		return q.notifier.SendDeathStreakNotification(guild, p.PlayerName, p.Deaths)
+	case domain.NotificationMembership:
+		var change domain.MembershipChange
+		if err := json.Unmarshal(n.Payload, &change); err != nil {
+			return fmt.Errorf("decode membership change: %w", err)
+		}
+		return q.notifier.SendMembershipNotification(guild, change)
+	case domain.NotificationHouse:
+		var p housePayload
+		if err := json.Unmarshal(n.Payload, &p); err != nil {
+			return fmt.Errorf("decode house auction: %w", err)
+		}
+		return q.notifier.SendHouseAuctionNotification(guild, p.Auction, p.Ended)
+	case domain.NotificationCharacter:
+		var change domain.CharacterChange
+		if err := json.Unmarshal(n.Payload, &change); err != nil {
+			return fmt.Errorf("decode character change: %w", err)
+		}
+		return q.notifier.SendCharacterChangeNotification(guild, change)
+	case domain.NotificationCatchUp:
+		var catchUp domain.CatchUp
+		if err := json.Unmarshal(n.Payload, &catchUp); err != nil {
+			return fmt.Errorf("decode catch-up: %w", err)
+		}
+		return q.notifier.SendCatchUpNotification(guild, catchUp)
+	case domain.NotificationSkill:
+		var advance domain.SkillAdvance
+		if err := json.Unmarshal(n.Payload, &advance); err != nil {
+			return fmt.Errorf("decode skill advance: %w", err)
+		}
+		return q.notifier.SendSkillAdvanceNotification(guild, advance)
+	case domain.NotificationMassDeath:
+		var massDeath domain.MassDeath
+		if err := json.Unmarshal(n.Payload, &massDeath); err != nil {
+			return fmt.Errorf("decode mass death: %w", err)
+		}
+		return q.notifier.SendMassDeathNotification(guild, massDeath)
+	case domain.NotificationGuildWar:
+		var war domain.GuildWar
+		if err := json.Unmarshal(n.Payload, &war); err != nil {
+			return fmt.Errorf("decode guild war: %w", err)
+		}
+		return q.notifier.SendGuildWarNotification(guild, war)
+	default:
+		return fmt.Errorf("unknown notification kind %q", n.Kind)
+	}
+}
+
+// recordDelivery stamps the guild's last notification time after a
+// successful send.
+func (q *NotificationQueue) recordDelivery(guildID string) {
+	if err := q.repo.SetGuildLastNotified(context.Background(), guildID, q.now()); err != nil {
+		slog.Warn("Failed to record notification time", "guild_id", guildID, "error", err)
+	}
+}
+
+func (q *NotificationQueue) enqueue(guildID string, kind domain.NotificationKind, payload any, sendErr error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Failed to encode notification for retry", "guild_id", guildID, "kind", kind, "error", err)
+		return
+	}
+
+	n := domain.FailedNotification{
+		DiscordGuildID: guildID,
+		Kind:           kind,
+		Payload:        data,
+		LastError:      sendErr.Error(),
+		NextAttemptAt:  q.now().Add(retryBaseDelay),
+	}
+	if err := q.repo.EnqueueFailedNotification(context.Background(), n); err != nil {
+		slog.Error("Failed to queue notification for retry", "guild_id", guildID, "kind", kind, "error", err)
+		return
+	}
+
+	metrics.NotificationRetries.WithLabelValues("queued").Inc()
+	slog.Warn("Queued failed notification for retry", "guild_id", guildID, "kind", kind, "error", sendErr)
+}
+
+func (q *NotificationQueue) reschedule(ctx context.Context, n domain.FailedNotification, sendErr error) {
+	next := q.now().Add(retryBackoff(n.Attempts + 1))
+	if err := q.repo.RescheduleFailedNotification(ctx, n.ID, next, sendErr.Error()); err != nil {
+		slog.ErrorContext(ctx, "Failed to reschedule notification", "id", n.ID, "error", err)
+	}
+	metrics.NotificationRetries.WithLabelValues("failed").Inc()
+}
+
+func (q *NotificationQueue) drop(ctx context.Context, n domain.FailedNotification) {
+	if err := q.repo.DeleteFailedNotification(ctx, n.ID); err != nil {
+		slog.ErrorContext(ctx, "Failed to drop notification", "id", n.ID, "error", err)
+	}
+	metrics.NotificationRetries.WithLabelValues("dropped").Inc()
+}
+
+// retryBackoff doubles the delay for every failed attempt, capped at an hour.
+func retryBackoff(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 0; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+type mockNotifier struct {
+	sendLevelUpFunc   func(guild domain.GuildConfig, levelUp domain.LevelUp) error
+	sendLevelDownFunc func(guild domain.GuildConfig, levelDown domain.LevelDown) error
+	sendDeathFunc     func(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error
+	sendHouseFunc     func(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error
+	sendRashidFunc    func(guild domain.GuildConfig, city string) error
+	sendBoostedFunc   func(guild domain.GuildConfig, boosted domain.Boosted) error
+	sendChangeFunc    func(guild domain.GuildConfig, change domain.CharacterChange) error
+	sendCatchUpFunc   func(guild domain.GuildConfig, catchUp domain.CatchUp) error
+	sendSkillFunc     func(guild domain.GuildConfig, advance domain.SkillAdvance) error
+	sendWarFunc       func(guild domain.GuildConfig, war domain.GuildWar) error
+}
+
+func (m *mockNotifier) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+	if m.sendLevelUpFunc != nil {
+		return m.sendLevelUpFunc(guild, levelUp)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	if m.sendLevelDownFunc != nil {
+		return m.sendLevelDownFunc(guild, levelDown)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+	if m.sendDeathFunc != nil {
+		return m.sendDeathFunc(guild, player, kill)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	return nil
+}
+
+func (m *mockNotifier) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	return nil
+}
+
+func (m *mockNotifier) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	if m.sendWarFunc != nil {
+		return m.sendWarFunc(guild, war)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	return nil
+}
+
+func (m *mockNotifier) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	if m.sendHouseFunc != nil {
+		return m.sendHouseFunc(guild, auction, ended)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	if m.sendChangeFunc != nil {
+		return m.sendChangeFunc(guild, change)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	if m.sendSkillFunc != nil {
+		return m.sendSkillFunc(guild, advance)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	if m.sendRashidFunc != nil {
+		return m.sendRashidFunc(guild, city)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	if m.sendBoostedFunc != nil {
+		return m.sendBoostedFunc(guild, boosted)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+	if m.sendCatchUpFunc != nil {
+		return m.sendCatchUpFunc(guild, catchUp)
+	}
+	return nil
+}
+
+func (m *mockNotifier) SendGenericMessage(guildID, channelName, message string) error {
+	return nil
+}
+
+var queueNow = time.Date(2024, 12, 13, 12, 0, 0, 0, time.UTC)
+
+func newTestQueue(repo *mockRepository, notifier *mockNotifier) *NotificationQueue {
+	q := NewNotificationQueue(repo, notifier, nil, 24*time.Hour)
+	q.now = func() time.Time { return queueNow }
+	return q
+}
+
+func TestNotificationQueue_EnqueuesFailedDeath(t *testing.T) {
+	var queued domain.FailedNotification
+	repo := &mockRepository{
+		enqueueFailedNotificationFunc: func(ctx context.Context, n domain.FailedNotification) error {
+			queued = n
+			return nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendDeathFunc: func(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+			return errors.New("missing permissions")
+		},
+	}
+
+	q := newTestQueue(repo, notifier)
+	err := q.SendDeathNotification(domain.GuildConfig{DiscordGuildID: "g1"}, domain.Player{Name: "Hero"}, domain.Kill{Level: 300})
+	if err == nil {
+		t.Fatal("expected send error to be returned")
+	}
+
+	if queued.DiscordGuildID != "g1" || queued.Kind != domain.NotificationDeath {
+		t.Errorf("unexpected queued notification: %+v", queued)
+	}
+	if queued.LastError != "missing permissions" {
+		t.Errorf("expected last error to be recorded, got %q", queued.LastError)
+	}
+	if !queued.NextAttemptAt.Equal(queueNow.Add(retryBaseDelay)) {
+		t.Errorf("expected next attempt at %v, got %v", queueNow.Add(retryBaseDelay), queued.NextAttemptAt)
+	}
+
+	var p deathPayload
+	if err := json.Unmarshal(queued.Payload, &p); err != nil {
+		t.Fatalf("payload not decodable: %v", err)
+	}
+	if p.Player.Name != "Hero" || p.Kill.Level != 300 {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+}
+
+func TestNotificationQueue_CharacterChangeRoundTrip(t *testing.T) {
+	var queued domain.FailedNotification
+	repo := &mockRepository{
+		enqueueFailedNotificationFunc: func(ctx context.Context, n domain.FailedNotification) error {
+			queued = n
+			return nil
+		},
+	}
+	change := domain.CharacterChange{Name: "New Hero", OldName: "Old Hero", World: "Antica"}
+	var sent []domain.CharacterChange
+	notifier := &mockNotifier{
+		sendChangeFunc: func(guild domain.GuildConfig, c domain.CharacterChange) error {
+			sent = append(sent, c)
+			if len(sent) == 1 {
+				return errors.New("channel missing")
+			}
+			return nil
+		},
+	}
+
+	q := newTestQueue(repo, notifier)
+	if err := q.SendCharacterChangeNotification(domain.GuildConfig{DiscordGuildID: "g1"}, change); err == nil {
+		t.Fatal("expected send error to be returned")
+	}
+	if queued.Kind != domain.NotificationCharacter {
+		t.Fatalf("expected a queued character change, got %+v", queued)
+	}
+
+	if err := q.dispatch(domain.GuildConfig{DiscordGuildID: "g1"}, queued); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if len(sent) != 2 || sent[1] != change {
+		t.Errorf("expected the change re-sent unchanged, got %+v", sent)
+	}
+}
+
+func TestNotificationQueue_SuccessIsNotQueued(t *testing.T) {
+	repo := &mockRepository{
+		enqueueFailedNotificationFunc: func(ctx context.Context, n domain.FailedNotification) error {
+			t.Error("successful notification should not be queued")
+			return nil
+		},
+	}
+
+	q := newTestQueue(repo, &mockNotifier{})
+	if err := q.SendLevelUpNotification(domain.GuildConfig{DiscordGuildID: "g1"}, domain.LevelUp{PlayerName: "Hero"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNotificationQueue_RecordsLastNotified(t *testing.T) {
+	var recorded []string
+	repo := &mockRepository{
+		setGuildLastNotifiedFunc: func(ctx context.Context, guildID string, at time.Time) error {
+			if !at.Equal(queueNow) {
+				t.Errorf("expected %v, got %v", queueNow, at)
+			}
+			recorded = append(recorded, guildID)
+			return nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendDeathFunc: func(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+			return errors.New("missing permissions")
+		},
+	}
+
+	q := newTestQueue(repo, notifier)
+	q.SendLevelUpNotification(domain.GuildConfig{DiscordGuildID: "g1"}, domain.LevelUp{PlayerName: "Hero"})
+	q.SendDeathNotification(domain.GuildConfig{DiscordGuildID: "g2"}, domain.Player{Name: "Hero"}, domain.Kill{})
+
+	if len(recorded) != 1 || recorded[0] != "g1" {
+		t.Errorf("expected only the delivered notification to be recorded, got %v", recorded)
+	}
+}
+
+func TestNotificationQueue_RetryDue(t *testing.T) {
+	payload, _ := json.Marshal(domain.LevelUp{PlayerName: "Hero", NewLevel: 501})
+	var deleted []int64
+	var rescheduled []int64
+	var nextAttempt time.Time
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			if guildID == "gone" {
+				return nil, nil
+			}
+			return &domain.GuildConfig{DiscordGuildID: guildID}, nil
+		},
+		getDueFailedNotificationsFunc: func(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+			return []domain.FailedNotification{
+				{ID: 1, DiscordGuildID: "g1", Kind: domain.NotificationLevelUp, Payload: payload, CreatedAt: queueNow},
+				{ID: 2, DiscordGuildID: "g2", Kind: domain.NotificationLevelUp, Payload: payload, Attempts: 2, CreatedAt: queueNow},
+				{ID: 3, DiscordGuildID: "gone", Kind: domain.NotificationLevelUp, Payload: payload, CreatedAt: queueNow},
+			}, nil
+		},
+		deleteFailedNotificationFunc: func(ctx context.Context, id int64) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+		rescheduleFailedNotificationFunc: func(ctx context.Context, id int64, next time.Time, lastErr string) error {
+			rescheduled = append(rescheduled, id)
+			nextAttempt = next
+			return nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendLevelUpFunc: func(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+			if levelUp.PlayerName != "Hero" {
+				t.Errorf("expected decoded level up, got %+v", levelUp)
+			}
+			if guild.DiscordGuildID == "g2" {
+				return errors.New("channel missing")
+			}
+			return nil
+		},
+	}
+
+	q := newTestQueue(repo, notifier)
+	result, err := q.RetryDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result != (RetryResult{Sent: 1, Failed: 1, Dropped: 1}) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(deleted) != 2 || deleted[0] != 1 || deleted[1] != 3 {
+		t.Errorf("expected notifications 1 and 3 removed, got %v", deleted)
+	}
+	if len(rescheduled) != 1 || rescheduled[0] != 2 {
+		t.Errorf("expected notification 2 rescheduled, got %v", rescheduled)
+	}
+	if !nextAttempt.Equal(queueNow.Add(8 * time.Minute)) {
+		t.Errorf("expected backoff of 8m, got %v", nextAttempt.Sub(queueNow))
+	}
+}
+
+func TestNotificationQueue_RetryDueDropsExpired(t *testing.T) {
+	var cutoff time.Time
+	repo := &mockRepository{
+		deleteExpiredFailedNotificationsFunc: func(ctx context.Context, createdBefore time.Time) (int64, error) {
+			cutoff = createdBefore
+			return 4, nil
+		},
+	}
+
+	q := newTestQueue(repo, &mockNotifier{})
+	result, err := q.RetryDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Dropped != 4 {
+		t.Errorf("expected 4 dropped, got %d", result.Dropped)
+	}
+	if !cutoff.Equal(queueNow.Add(-24 * time.Hour)) {
+		t.Errorf("expected cutoff 24h ago, got %v", cutoff)
+	}
+}
+
+func TestNotificationQueue_Flush(t *testing.T) {
+	payload, _ := json.Marshal(deathStreakPayload{PlayerName: "Hero", Deaths: 3})
+	var flushedGuild string
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &domain.GuildConfig{DiscordGuildID: guildID}, nil
+		},
+		getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+			flushedGuild = guildID
+			return []domain.FailedNotification{
+				{ID: 1, DiscordGuildID: guildID, Kind: domain.NotificationDeathStreak, Payload: payload, NextAttemptAt: queueNow.Add(time.Hour), CreatedAt: queueNow},
+			}, nil
+		},
+	}
+
+	q := newTestQueue(repo, &mockNotifier{})
+	result, err := q.Flush(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if flushedGuild != "g1" {
+		t.Errorf("expected guild 'g1', got '%s'", flushedGuild)
+	}
+	if result.Sent != 1 {
+		t.Errorf("expected notification sent regardless of backoff, got %+v", result)
+	}
+}
+
+func TestNotificationQueue_FlushWaitsOutQuietHours(t *testing.T) {
+	payload, _ := json.Marshal(domain.CatchUp{LevelUps: []domain.LevelUp{{PlayerName: "Hero", NewLevel: 300}}})
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			// queueNow is 12:00 UTC.
+			return &domain.GuildConfig{DiscordGuildID: guildID, Timezone: "UTC", QuietHours: domain.QuietHours{Start: 11 * 60, End: 13 * 60}}, nil
+		},
+		getGuildFailedNotificationsFunc: func(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+			return []domain.FailedNotification{
+				{ID: 1, DiscordGuildID: guildID, Kind: domain.NotificationCatchUp, Payload: payload, CreatedAt: queueNow},
+			}, nil
+		},
+		deleteFailedNotificationFunc: func(ctx context.Context, id int64) error {
+			t.Error("expected the notification to stay queued")
+			return nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendCatchUpFunc: func(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+			t.Error("expected nothing sent during quiet hours")
+			return nil
+		},
+	}
+
+	q := newTestQueue(repo, notifier)
+	result, err := q.Flush(context.Background(), "g1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (RetryResult{}) {
+		t.Errorf("expected nothing sent or dropped, got %+v", result)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{3, 8 * time.Minute},
+		{10, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+const (
+	quietHoursInterval = time.Minute
+	// maxCatchUpEvents caps the events held back per guild so a busy night
+	// cannot grow the buffer without bound.
+	maxCatchUpEvents = 200
+)
+
+// QuietHoursNotifier wraps a NotificationService and posts nothing during a
+// guild's quiet hours. Deaths and level ups are held in memory when the guild
+// wants a catch-up and posted as one message once the window ends; every
+// other notification is dropped, as when the guild is muted.
+type QuietHoursNotifier struct {
+	repo     ports.Repository
+	notifier ports.NotificationService
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*domain.CatchUp
+}
+
+func NewQuietHoursNotifier(repo ports.Repository, notifier ports.NotificationService) *QuietHoursNotifier {
+	return &QuietHoursNotifier{
+		repo:     repo,
+		notifier: notifier,
+		now:      time.Now,
+		pending:  make(map[string]*domain.CatchUp),
+	}
+}
+
+func (n *QuietHoursNotifier) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+	if guild.InQuietHours(n.now()) {
+		n.hold(guild, func(c *domain.CatchUp) { c.LevelUps = append(c.LevelUps, levelUp) })
+		return nil
+	}
+	return n.notifier.SendLevelUpNotification(guild, levelUp)
+}
+
+func (n *QuietHoursNotifier) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendLevelDownNotification(guild, levelDown)
+}
+
+func (n *QuietHoursNotifier) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+	if guild.InQuietHours(n.now()) {
+		n.hold(guild, func(c *domain.CatchUp) { c.Deaths = append(c.Deaths, domain.CatchUpDeath{Player: player, Kill: kill}) })
+		return nil
+	}
+	return n.notifier.SendDeathNotification(guild, player, kill)
+}
+
+func (n *QuietHoursNotifier) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendDeathStreakNotification(guild, playerName, deaths)
+}
+
+func (n *QuietHoursNotifier) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendMassDeathNotification(guild, massDeath)
+}
+
+func (n *QuietHoursNotifier) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendMembershipNotification(guild, change)
+}
+
+func (n *QuietHoursNotifier) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendGuildWarNotification(guild, war)
+}
+
+func (n *QuietHoursNotifier) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendHouseAuctionNotification(guild, auction, ended)
+}
+
+func (n *QuietHoursNotifier) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendCharacterChangeNotification(guild, change)
+}
+
+func (n *QuietHoursNotifier) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendSkillAdvanceNotification(guild, advance)
+}
+
+func (n *QuietHoursNotifier) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendRashidNotification(guild, city)
+}
+
+func (n *QuietHoursNotifier) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	if guild.InQuietHours(n.now()) {
+		return nil
+	}
+	return n.notifier.SendBoostedNotification(guild, boosted)
+}
+
+func (n *QuietHoursNotifier) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+	return n.notifier.SendCatchUpNotification(guild, catchUp)
+}
+
+func (n *QuietHoursNotifier) SendGenericMessage(guildID, channelName, message string) error {
+	return n.notifier.SendGenericMessage(guildID, channelName, message)
+}
+
+// Start posts the catch-ups of guilds whose quiet hours ended, checking every
+// minute until ctx is cancelled.
+func (n *QuietHoursNotifier) Start(ctx context.Context) {
+	ticker := time.NewTicker(quietHoursInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.FlushEnded(ctx)
+		}
+	}
+}
+
+// FlushEnded posts the held back events of every guild that is no longer in
+// its quiet hours, using the guild's current config. Guilds that stopped
+// tracking or turned catch-ups off in the meantime get nothing.
+func (n *QuietHoursNotifier) FlushEnded(ctx context.Context) {
+	n.mu.Lock()
+	guildIDs := make([]string, 0, len(n.pending))
+	for guildID := range n.pending {
+		guildIDs = append(guildIDs, guildID)
+	}
+	n.mu.Unlock()
+
+	for _, guildID := range guildIDs {
+		cfg, err := n.repo.GetGuildConfig(ctx, guildID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get guild config for catch-up", "guild_id", guildID, "error", err)
+			continue
+		}
+		if cfg != nil && cfg.InQuietHours(n.now()) {
+			continue
+		}
+
+		n.mu.Lock()
+		catchUp := n.pending[guildID]
+		delete(n.pending, guildID)
+		n.mu.Unlock()
+
+		if cfg == nil || cfg.World == "" || !cfg.QuietHours.CatchUp || catchUp == nil {
+			continue
+		}
+		if err := n.notifier.SendCatchUpNotification(*cfg, *catchUp); err != nil {
+			slog.ErrorContext(ctx, "Failed to send catch-up", "guild_id", guildID, "events", catchUp.Len(), "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "Sent quiet hours catch-up", "guild_id", guildID, "events", catchUp.Len())
+	}
+}
+
+// hold adds an event to the guild's catch-up, or drops it when the guild
+// does not want one or its catch-up is full.
+func (n *QuietHoursNotifier) hold(guild domain.GuildConfig, add func(*domain.CatchUp)) {
+	if !guild.QuietHours.CatchUp {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	catchUp, ok := n.pending[guild.DiscordGuildID]
+	if !ok {
+		catchUp = &domain.CatchUp{}
+		n.pending[guild.DiscordGuildID] = catchUp
+	}
+	if catchUp.Len() >= maxCatchUpEvents {
+		slog.Warn("Catch-up is full, dropping event", "guild_id", guild.DiscordGuildID, "events", catchUp.Len())
+		return
+	}
+	add(catchUp)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+// quietNow is 03:00 UTC, inside the 02:00-08:00 window used below.
+var quietNow = time.Date(2026, 4, 5, 3, 0, 0, 0, time.UTC)
+
+func newTestQuietHours(repo *mockRepository, notifier *mockNotifier) *QuietHoursNotifier {
+	n := NewQuietHoursNotifier(repo, notifier)
+	n.now = func() time.Time { return quietNow }
+	return n
+}
+
+func quietGuild(catchUp bool) domain.GuildConfig {
+	return domain.GuildConfig{
+		DiscordGuildID: "g1",
+		World:          "Antica",
+		Timezone:       "UTC",
+		QuietHours:     domain.QuietHours{Start: 2 * 60, End: 8 * 60, CatchUp: catchUp},
+	}
+}
+
+func TestQuietHoursNotifier_HoldsAndCatchesUp(t *testing.T) {
+	guild := quietGuild(true)
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &guild, nil
+		},
+	}
+	var sent int
+	var catchUps []domain.CatchUp
+	notifier := &mockNotifier{
+		sendDeathFunc: func(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+			sent++
+			return nil
+		},
+		sendRashidFunc: func(guild domain.GuildConfig, city string) error {
+			sent++
+			return nil
+		},
+		sendCatchUpFunc: func(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+			catchUps = append(catchUps, catchUp)
+			return nil
+		},
+	}
+
+	n := newTestQuietHours(repo, notifier)
+	n.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Level: 300})
+	n.SendLevelUpNotification(guild, domain.LevelUp{PlayerName: "Hero", OldLevel: 299, NewLevel: 300})
+	n.SendRashidNotification(guild, "Edron")
+	if sent != 0 {
+		t.Fatalf("expected nothing sent during quiet hours, got %d", sent)
+	}
+
+	n.FlushEnded(context.Background())
+	if len(catchUps) != 0 {
+		t.Fatalf("expected no catch-up before the window ends, got %+v", catchUps)
+	}
+
+	n.now = func() time.Time { return quietNow.Add(5 * time.Hour) }
+	n.FlushEnded(context.Background())
+	n.FlushEnded(context.Background())
+
+	if len(catchUps) != 1 || len(catchUps[0].Deaths) != 1 || len(catchUps[0].LevelUps) != 1 {
+		t.Fatalf("expected one catch-up with the death and level up, got %+v", catchUps)
+	}
+	if catchUps[0].Deaths[0].Player.Name != "Hero" || catchUps[0].LevelUps[0].NewLevel != 300 {
+		t.Errorf("unexpected catch-up: %+v", catchUps[0])
+	}
+
+	n.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Level: 300})
+	if sent != 1 {
+		t.Errorf("expected deaths after the window to be sent directly, got %d", sent)
+	}
+}
+
+func TestQuietHoursNotifier_DropsWithoutCatchUp(t *testing.T) {
+	guild := quietGuild(false)
+	repo := &mockRepository{
+		getGuildConfigFunc: func(ctx context.Context, guildID string) (*domain.GuildConfig, error) {
+			return &guild, nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendDeathFunc: func(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+			t.Error("expected the death to be dropped")
+			return nil
+		},
+		sendCatchUpFunc: func(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+			t.Error("expected no catch-up")
+			return nil
+		},
+	}
+
+	n := newTestQuietHours(repo, notifier)
+	n.SendDeathNotification(guild, domain.Player{Name: "Hero"}, domain.Kill{Level: 300})
+	n.now = func() time.Time { return quietNow.Add(6 * time.Hour) }
+	n.FlushEnded(context.Background())
+}
+
+func TestSetQuietHours(t *testing.T) {
+	tests := []struct {
+		window  string
+		catchUp bool
+		want    domain.QuietHours
+		wantErr bool
+	}{
+		{"02:00-08:00", true, domain.QuietHours{Start: 120, End: 480, CatchUp: true}, false},
+		{" 22:30 - 6:00 ", false, domain.QuietHours{Start: 1350, End: 360}, false},
+		{"off", true, domain.QuietHours{}, false},
+		{"08:00-08:00", false, domain.QuietHours{}, true},
+		{"25:00-08:00", false, domain.QuietHours{}, true},
+		{"night", false, domain.QuietHours{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			var saved *domain.QuietHours
+			repo := &mockRepository{
+				setGuildQuietHoursFunc: func(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+					saved = &quiet
+					return nil
+				},
+			}
+
+			got, err := NewConfigurationService(repo, nil, Limits{}, nil).SetQuietHours(context.Background(), "g1", tt.window, tt.catchUp)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidQuietHours) || saved != nil {
+					t.Errorf("expected ErrInvalidQuietHours and nothing saved, got %v, %+v", err, saved)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || saved == nil || *saved != tt.want {
+				t.Errorf("expected %+v saved, got %+v (saved %+v)", tt.want, got, saved)
+			}
+		})
+	}
+}
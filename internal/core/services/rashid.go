@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// rashidPostDelay keeps the daily post clear of the server save itself so
+// clock skew cannot announce the previous day's city.
+const rashidPostDelay = time.Minute
+
+// RashidService posts where Rashid is to every tracking guild after each
+// server save.
+type RashidService struct {
+	repo     ports.Repository
+	notifier ports.NotificationService
+	// leader gates posting when several replicas run; nil means always lead.
+	leader ports.LeaderElector
+	now    func() time.Time
+}
+
+func NewRashidService(repo ports.Repository, notifier ports.NotificationService, leader ports.LeaderElector) *RashidService {
+	return &RashidService{
+		repo:     repo,
+		notifier: notifier,
+		leader:   leader,
+		now:      time.Now,
+	}
+}
+
+// Start waits for each server save and posts the daily location until ctx is
+// cancelled. Nothing is posted on startup, so restarts never post twice.
+func (s *RashidService) Start(ctx context.Context) {
+	for {
+		next := domain.NextServerSave(s.now()).Add(rashidPostDelay)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.PostDaily(ctx)
+		}
+	}
+}
+
+// PostDaily sends today's city to every guild tracking a world that is not
+// muted.
+func (s *RashidService) PostDaily(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs", "error", err)
+		return
+	}
+
+	now := s.now()
+	city := domain.RashidCity(now)
+	sent := 0
+	for _, guild := range configs {
+		if guild.World == "" || guild.IsMuted(now) {
+			continue
+		}
+		if err := s.notifier.SendRashidNotification(guild, city); err != nil {
+			slog.ErrorContext(ctx, "Failed to post Rashid's location", "guild_id", guild.DiscordGuildID, "error", err)
+			continue
+		}
+		sent++
+	}
+	slog.InfoContext(ctx, "Posted Rashid's location", "city", city, "guilds", sent)
+}
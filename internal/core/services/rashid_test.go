@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestRashidService_PostDaily(t *testing.T) {
+	now := time.Date(2024, 12, 14, 12, 0, 0, 0, domain.ServerSaveLocation)
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{
+				{DiscordGuildID: "tracking", World: "Antica"},
+				{DiscordGuildID: "unconfigured"},
+				{DiscordGuildID: "muted", World: "Antica", MutedUntil: now.Add(time.Hour)},
+			}, nil
+		},
+	}
+	posted := make(map[string]string)
+	notifier := &mockNotifier{
+		sendRashidFunc: func(guild domain.GuildConfig, city string) error {
+			posted[guild.DiscordGuildID] = city
+			return nil
+		},
+	}
+
+	svc := NewRashidService(repo, notifier, nil)
+	svc.now = func() time.Time { return now }
+	svc.PostDaily(context.Background())
+
+	if len(posted) != 1 || posted["tracking"] != "Edron" {
+		t.Errorf("expected only the tracking guild to get Edron, got %v", posted)
+	}
+}
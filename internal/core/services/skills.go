@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// maxHighscorePages bounds how deep a skill's highscores are read. They list
+// the top 1,000 characters of a world, 50 per page.
+const maxHighscorePages = 20
+
+// SkillService announces skill advances of the characters guilds watch with
+// /watch-player on worlds where they configured a skill channel with
+// /track-skills. Character pages do not show skills, so values come from the
+// world highscores and only characters ranked there are tracked.
+type SkillService struct {
+	config   *config.Config
+	repo     ports.Repository
+	fetcher  ports.TibiaFetcher
+	notifier ports.NotificationService
+	// leader gates polling when several replicas run; nil means always lead.
+	leader ports.LeaderElector
+	now    func() time.Time
+}
+
+func NewSkillService(cfg *config.Config, repo ports.Repository, fetcher ports.TibiaFetcher, notifier ports.NotificationService, leader ports.LeaderElector) *SkillService {
+	return &SkillService{
+		config:   cfg,
+		repo:     repo,
+		fetcher:  fetcher,
+		notifier: notifier,
+		leader:   leader,
+		now:      time.Now,
+	}
+}
+
+// Start checks the highscores every SKILL_POLL_INTERVAL until ctx is
+// cancelled.
+func (s *SkillService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.SkillPollInterval)
+	defer ticker.Stop()
+
+	slog.Info("Skill tracking service started", "interval", s.config.SkillPollInterval)
+
+	s.runCycle(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runCycle(ctx)
+		}
+	}
+}
+
+func (s *SkillService) runCycle(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs", "error", err)
+		return
+	}
+
+	worlds := make(map[string][]domain.GuildConfig)
+	for _, cfg := range configs {
+		if cfg.World == "" || cfg.SkillChannelID == "" || len(cfg.WatchedPlayers) == 0 {
+			continue
+		}
+		worlds[cfg.World] = append(worlds[cfg.World], cfg)
+	}
+
+	for world, guilds := range worlds {
+		if err := s.processWorld(ctx, world, guilds); err != nil {
+			slog.ErrorContext(ctx, "Failed to check skill advances", "world", world, "error", err)
+		}
+	}
+}
+
+// processWorld reads each skill's highscores of world, announces the watched
+// characters whose value went up and stores the new values. A character seen
+// for the first time only has its values recorded.
+func (s *SkillService) processWorld(ctx context.Context, world string, guilds []domain.GuildConfig) error {
+	watched := make(map[string]bool)
+	for _, guild := range guilds {
+		for _, name := range guild.WatchedPlayers {
+			watched[strings.ToLower(name)] = true
+		}
+	}
+
+	now := s.now()
+	for _, skill := range domain.Skills {
+		current, err := s.readHighscores(ctx, world, skill, watched, lowestThreshold(guilds, skill))
+		if err != nil {
+			return fmt.Errorf("fetch %s highscores: %w", skill, err)
+		}
+		if len(current) == 0 {
+			continue
+		}
+
+		stored, err := s.repo.GetPlayerSkills(ctx, world, skill)
+		if err != nil {
+			return fmt.Errorf("load %s values: %w", skill, err)
+		}
+		for name, value := range current {
+			old, ok := stored[name]
+			if !ok || value <= old {
+				continue
+			}
+			s.notify(ctx, guilds, domain.SkillAdvance{PlayerName: name, World: world, Skill: skill, OldValue: old, NewValue: value}, now)
+		}
+
+		if err := s.repo.SavePlayerSkills(ctx, world, skill, current); err != nil {
+			return fmt.Errorf("store %s values: %w", skill, err)
+		}
+	}
+	return nil
+}
+
+// readHighscores pages through the highscores of skill and returns the values
+// of the watched characters on them. It stops once every watched character
+// was found or the values drop below floor, since no guild would announce
+// anything lower.
+func (s *SkillService) readHighscores(ctx context.Context, world string, skill domain.Skill, watched map[string]bool, floor int) (map[string]int, error) {
+	values := make(map[string]int)
+	for page := 1; page <= maxHighscorePages; page++ {
+		result, err := s.fetcher.FetchSkillHighscores(ctx, world, skill, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range result.Entries {
+			if watched[strings.ToLower(entry.Name)] {
+				values[entry.Name] = entry.Value
+			}
+		}
+		if len(values) == len(watched) || page >= result.TotalPages || len(result.Entries) == 0 {
+			break
+		}
+		if result.Entries[len(result.Entries)-1].Value < floor {
+			break
+		}
+	}
+	return values, nil
+}
+
+func (s *SkillService) notify(ctx context.Context, guilds []domain.GuildConfig, advance domain.SkillAdvance, now time.Time) {
+	for _, guild := range guilds {
+		if guild.IsMuted(now) || advance.NewValue < guild.SkillThresholds[advance.Skill] {
+			continue
+		}
+		if !slices.ContainsFunc(guild.WatchedPlayers, func(p string) bool { return domain.SameName(p, advance.PlayerName) }) {
+			continue
+		}
+		if err := s.notifier.SendSkillAdvanceNotification(guild, advance); err != nil {
+			slog.ErrorContext(ctx, "Failed to send skill advance notification", "guild_id", guild.DiscordGuildID, "player", advance.PlayerName, "skill", advance.Skill, "error", err)
+		}
+	}
+}
+
+// lowestThreshold is the lowest value of skill any of the guilds announces.
+func lowestThreshold(guilds []domain.GuildConfig, skill domain.Skill) int {
+	lowest := -1
+	for _, guild := range guilds {
+		if t := guild.SkillThresholds[skill]; lowest < 0 || t < lowest {
+			lowest = t
+		}
+	}
+	return max(lowest, 0)
+}
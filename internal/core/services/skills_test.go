@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func newTestSkillService(stored map[domain.Skill]map[string]int, pages map[domain.Skill][]domain.HighscorePage, guilds []domain.GuildConfig) (*SkillService, *[]domain.SkillAdvance, *[]int) {
+	var sent []domain.SkillAdvance
+	var fetched []int
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return guilds, nil
+		},
+		getPlayerSkillsFunc: func(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+			return stored[skill], nil
+		},
+		savePlayerSkillsFunc: func(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+			if stored[skill] == nil {
+				stored[skill] = make(map[string]int)
+			}
+			for name, value := range values {
+				stored[skill][name] = value
+			}
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchSkillsFunc: func(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+			fetched = append(fetched, page)
+			if page > len(pages[skill]) {
+				return &domain.HighscorePage{TotalPages: len(pages[skill])}, nil
+			}
+			return &pages[skill][page-1], nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendSkillFunc: func(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+			sent = append(sent, advance)
+			return nil
+		},
+	}
+	svc := NewSkillService(&config.Config{SkillPollInterval: time.Hour}, repo, fetcher, notifier, nil)
+	return svc, &sent, &fetched
+}
+
+func TestSkillService_AnnouncesAdvances(t *testing.T) {
+	stored := map[domain.Skill]map[string]int{
+		domain.SkillMagic: {"Mage One": 100, "Mage Two": 90},
+	}
+	pages := map[domain.Skill][]domain.HighscorePage{
+		domain.SkillMagic: {{
+			Entries:    []domain.HighscoreEntry{{Name: "Mage One", Value: 101}, {Name: "Mage Two", Value: 90}, {Name: "New Mage", Value: 80}},
+			TotalPages: 1,
+		}},
+	}
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica", SkillChannelID: "c1", WatchedPlayers: []string{"mage one", "Mage Two", "New Mage"}},
+		{DiscordGuildID: "g2", World: "Antica", WatchedPlayers: []string{"Mage One"}},
+	}
+	svc, sent, _ := newTestSkillService(stored, pages, guilds)
+
+	svc.runCycle(context.Background())
+
+	want := domain.SkillAdvance{PlayerName: "Mage One", World: "Antica", Skill: domain.SkillMagic, OldValue: 100, NewValue: 101}
+	if len(*sent) != 1 || (*sent)[0] != want {
+		t.Fatalf("expected only %+v, got %+v", want, *sent)
+	}
+	if stored[domain.SkillMagic]["New Mage"] != 80 {
+		t.Errorf("expected first value of a new character to be recorded, got %+v", stored[domain.SkillMagic])
+	}
+}
+
+func TestSkillService_Thresholds(t *testing.T) {
+	stored := map[domain.Skill]map[string]int{
+		domain.SkillSword: {"Knight": 99},
+	}
+	pages := map[domain.Skill][]domain.HighscorePage{
+		domain.SkillSword: {{Entries: []domain.HighscoreEntry{{Name: "Knight", Value: 100}}, TotalPages: 1}},
+	}
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica", SkillChannelID: "c1", WatchedPlayers: []string{"Knight"}, SkillThresholds: map[domain.Skill]int{domain.SkillSword: 100}},
+		{DiscordGuildID: "g2", World: "Antica", SkillChannelID: "c2", WatchedPlayers: []string{"Knight"}, SkillThresholds: map[domain.Skill]int{domain.SkillSword: 110}},
+		{DiscordGuildID: "g3", World: "Antica", SkillChannelID: "c3", WatchedPlayers: []string{"Knight"}, MutedUntil: time.Now().Add(time.Hour)},
+	}
+	var guildIDs []string
+	svc, _, _ := newTestSkillService(stored, pages, guilds)
+	svc.notifier = &mockNotifier{
+		sendSkillFunc: func(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+			guildIDs = append(guildIDs, guild.DiscordGuildID)
+			return nil
+		},
+	}
+
+	svc.runCycle(context.Background())
+
+	if len(guildIDs) != 1 || guildIDs[0] != "g1" {
+		t.Errorf("expected only g1 to be notified, got %v", guildIDs)
+	}
+}
+
+func TestSkillService_StopsPaging(t *testing.T) {
+	page := func(name string, value int) domain.HighscorePage {
+		return domain.HighscorePage{Entries: []domain.HighscoreEntry{{Name: name, Value: value}}, TotalPages: 5}
+	}
+
+	t.Run("all watched found", func(t *testing.T) {
+		pages := map[domain.Skill][]domain.HighscorePage{
+			domain.SkillFishing: {page("Other", 120), page("Fisher", 110), page("Other Two", 100)},
+		}
+		guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", SkillChannelID: "c1", WatchedPlayers: []string{"Fisher"}}}
+		svc, _, fetched := newTestSkillService(map[domain.Skill]map[string]int{}, pages, guilds)
+
+		values, err := svc.readHighscores(context.Background(), "Antica", domain.SkillFishing, map[string]bool{"fisher": true}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values["Fisher"] != 110 || len(*fetched) != 2 {
+			t.Errorf("expected Fisher at 110 after page 2, got %v after pages %v", values, *fetched)
+		}
+	})
+
+	t.Run("below lowest threshold", func(t *testing.T) {
+		pages := map[domain.Skill][]domain.HighscorePage{
+			domain.SkillFishing: {page("Other", 120), page("Other Two", 90), page("Fisher", 80)},
+		}
+		svc, _, fetched := newTestSkillService(map[domain.Skill]map[string]int{}, pages, nil)
+
+		values, err := svc.readHighscores(context.Background(), "Antica", domain.SkillFishing, map[string]bool{"fisher": true}, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(values) != 0 || len(*fetched) != 2 {
+			t.Errorf("expected to stop after page 2 without values, got %v after pages %v", values, *fetched)
+		}
+	})
+}
+
+func TestSkillService_FetchError(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", SkillChannelID: "c1", WatchedPlayers: []string{"Mage"}}}
+	svc, sent, _ := newTestSkillService(map[domain.Skill]map[string]int{}, nil, guilds)
+	svc.fetcher = &mockFetcher{
+		fetchSkillsFunc: func(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+			return nil, domain.ErrUpstreamDown
+		},
+	}
+
+	err := svc.processWorld(context.Background(), "Antica", guilds)
+	if !errors.Is(err, domain.ErrUpstreamDown) {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("expected no announcements, got %+v", *sent)
+	}
+}
+
+func TestLowestThreshold(t *testing.T) {
+	guilds := []domain.GuildConfig{
+		{SkillThresholds: map[domain.Skill]int{domain.SkillAxe: 100}},
+		{SkillThresholds: map[domain.Skill]int{domain.SkillAxe: 80, domain.SkillClub: 90}},
+	}
+	if got := lowestThreshold(guilds, domain.SkillAxe); got != 80 {
+		t.Errorf("expected 80, got %d", got)
+	}
+	if got := lowestThreshold(guilds, domain.SkillClub); got != 0 {
+		t.Errorf("expected 0 when a guild has no threshold, got %d", got)
+	}
+}
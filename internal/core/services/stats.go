@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+	"death-level-tracker/internal/stats"
+)
+
+// compareWindow is the trailing period /compare measures level pace over.
+const compareWindow = 7 * 24 * time.Hour
+
+// forecastDays is how many complete days /pace averages level gains over.
+const forecastDays = 7
+
+// ErrNoGuildWar is returned by WarScore for a guild that was never seen at
+// war.
+var ErrNoGuildWar = errors.New("guild was never seen at war")
+
+type StatsService struct {
+	repo    ports.Repository
+	fetcher ports.TibiaFetcher
+	now     func() time.Time
+}
+
+func NewStatsService(repo ports.Repository, fetcher ports.TibiaFetcher) *StatsService {
+	return &StatsService{repo: repo, fetcher: fetcher, now: time.Now}
+}
+
+// DeathsToday returns per-player death counts on world since local midnight.
+func (s *StatsService) DeathsToday(ctx context.Context, world string) ([]domain.DeathCount, error) {
+	now := s.now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return s.repo.GetDeathCountsSince(ctx, world, startOfDay)
+}
+
+// LevelPace looks up the character's current level and measures its pace over
+// the last week from the recorded level ups.
+func (s *StatsService) LevelPace(ctx context.Context, name string) (domain.LevelPace, error) {
+	player, err := s.fetchCharacter(ctx, name)
+	if err != nil {
+		return domain.LevelPace{}, err
+	}
+
+	levelUps, err := s.repo.GetLevelUpsSince(ctx, player.Name, s.now().Add(-compareWindow))
+	if err != nil {
+		return domain.LevelPace{}, err
+	}
+	return domain.NewLevelPace(player.Name, player.Level, levelUps, compareWindow), nil
+}
+
+// LevelForecast looks up the character's current level and averages its daily
+// level gains over the last forecastDays complete UTC days, weighing recent
+// days most. Today is left out so a day that just started does not drag the
+// pace down. The time online covers the forecastDays days up to now.
+func (s *StatsService) LevelForecast(ctx context.Context, name string) (domain.LevelForecast, error) {
+	player, err := s.fetchCharacter(ctx, name)
+	if err != nil {
+		return domain.LevelForecast{}, err
+	}
+
+	since := s.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -forecastDays)
+	gains, err := s.repo.GetDailyLevelGains(ctx, player.Name, since)
+	if err != nil {
+		return domain.LevelForecast{}, err
+	}
+
+	daily := make([]float64, forecastDays)
+	for _, g := range gains {
+		if day := int(g.Day.Sub(since).Hours() / 24); day >= 0 && day < forecastDays {
+			daily[day] = float64(g.Levels)
+		}
+	}
+
+	online, err := s.repo.GetOnlineTimeSince(ctx, player.Name, s.now().AddDate(0, 0, -forecastDays))
+	if err != nil {
+		return domain.LevelForecast{}, err
+	}
+	return domain.LevelForecast{
+		Name:   player.Name,
+		Level:  player.Level,
+		PerDay: stats.EMA(daily, stats.SpanAlpha(forecastDays)),
+		Days:   forecastDays,
+		Online: online,
+	}, nil
+}
+
+func (s *StatsService) fetchCharacter(ctx context.Context, name string) (*domain.Player, error) {
+	player, err := s.fetcher.FetchCharacter(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("fetch character %s: %w", name, err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("character %s not found", name)
+	}
+	return player, nil
+}
+
+// TopKillers ranks the characters that killed the most tracked players of the
+// guild's world during the last window.
+func (s *StatsService) TopKillers(ctx context.Context, guild domain.GuildConfig, window time.Duration) ([]domain.KillerCount, error) {
+	return s.repo.GetTopKillersSince(ctx, guild.World, guild.TibiaGuilds, s.now().Add(-window))
+}
+
+// Frags ranks the characters of the guild's tracked Tibia guilds, or of its
+// world when it tracks none, by their frags since the last server save. With
+// name, only that character is counted.
+func (s *StatsService) Frags(ctx context.Context, guild domain.GuildConfig, name string) ([]domain.FragCount, error) {
+	return s.repo.GetFragCountsSince(ctx, guild.World, guild.TibiaGuilds, name, domain.LastServerSave(s.now()))
+}
+
+// Activity charts how many of the guild's tracked characters, and of all
+// characters on its world, were online by weekday and hour over the last
+// domain.ActivityWindow, in the guild's timezone.
+func (s *StatsService) Activity(ctx context.Context, guild domain.GuildConfig) (domain.ActivityHeatmap, error) {
+	since := s.now().Add(-domain.ActivityWindow)
+	counts, err := s.repo.GetOnlineCountsSince(ctx, guild.World, guild.TibiaGuilds, since)
+	if err != nil {
+		return domain.ActivityHeatmap{}, err
+	}
+	world, err := s.repo.GetWorldOnlineCountsSince(ctx, guild.World, since)
+	if err != nil {
+		return domain.ActivityHeatmap{}, err
+	}
+	return domain.NewActivityHeatmap(counts, world, domain.ActivityWindow, guild.Location()), nil
+}
+
+// WarScore tallies the kills between the tracked Tibia guild and opponent on
+// the guild's world during the guild's last war, ongoing or not. TibiaData
+// does not say who the war is against, so the opponent is the caller's word
+// for it, and both sides are counted by their current members.
+func (s *StatsService) WarScore(ctx context.Context, guild domain.GuildConfig, guildName, opponent string) (domain.WarScore, error) {
+	war, err := s.repo.GetGuildWar(ctx, guildName)
+	if err != nil {
+		return domain.WarScore{}, err
+	}
+	if war == nil {
+		return domain.WarScore{}, ErrNoGuildWar
+	}
+
+	ours, err := s.guildMembers(ctx, guildName)
+	if err != nil {
+		return domain.WarScore{}, err
+	}
+	theirs, err := s.guildMembers(ctx, opponent)
+	if err != nil {
+		return domain.WarScore{}, err
+	}
+
+	until := war.Until(s.now())
+	kills, err := s.repo.CountKillsBetween(ctx, guild.World, theirs.names, ours.names, war.StartedAt, until)
+	if err != nil {
+		return domain.WarScore{}, err
+	}
+	deaths, err := s.repo.CountKillsBetween(ctx, guild.World, ours.names, theirs.names, war.StartedAt, until)
+	if err != nil {
+		return domain.WarScore{}, err
+	}
+	return domain.WarScore{War: *war, Opponent: theirs.name, Kills: kills, Deaths: deaths}, nil
+}
+
+type guildRoster struct {
+	name  string
+	names []string
+}
+
+func (s *StatsService) guildMembers(ctx context.Context, name string) (guildRoster, error) {
+	guild, err := s.fetcher.FetchGuild(ctx, name)
+	if err != nil {
+		return guildRoster{}, fmt.Errorf("fetch guild %s: %w", name, err)
+	}
+	names := make([]string, len(guild.Members))
+	for i, m := range guild.Members {
+		names[i] = m.Name
+	}
+	return guildRoster{name: guild.Name, names: names}, nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestDeathsToday_QueriesSinceMidnight(t *testing.T) {
+	var since time.Time
+	var queriedWorld string
+	repo := &mockRepository{
+		getDeathCountsSinceFunc: func(ctx context.Context, world string, s time.Time) ([]domain.DeathCount, error) {
+			queriedWorld = world
+			since = s
+			return []domain.DeathCount{{Name: "Hero", Count: 2}}, nil
+		},
+	}
+
+	svc := NewStatsService(repo, nil)
+	svc.now = func() time.Time { return time.Date(2024, 12, 13, 15, 30, 0, 0, time.UTC) }
+
+	counts, err := svc.DeathsToday(context.Background(), "Antica")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(counts) != 1 || counts[0].Name != "Hero" {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if queriedWorld != "Antica" {
+		t.Errorf("expected world 'Antica', got '%s'", queriedWorld)
+	}
+	expected := time.Date(2024, 12, 13, 0, 0, 0, 0, time.UTC)
+	if !since.Equal(expected) {
+		t.Errorf("expected since %v, got %v", expected, since)
+	}
+}
+
+func TestTopKillers_QueriesWindowForTrackedGuilds(t *testing.T) {
+	var since time.Time
+	var guildNames []string
+	repo := &mockRepository{
+		getTopKillersSinceFunc: func(ctx context.Context, world string, names []string, s time.Time) ([]domain.KillerCount, error) {
+			guildNames = names
+			since = s
+			return []domain.KillerCount{{Name: "Enemy", Kills: 3}}, nil
+		},
+	}
+
+	svc := NewStatsService(repo, nil)
+	now := time.Date(2024, 12, 13, 15, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	guild := domain.GuildConfig{World: "Antica", TibiaGuilds: []string{"Red Rose"}}
+	killers, err := svc.TopKillers(context.Background(), guild, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(killers) != 1 || killers[0].Kills != 3 {
+		t.Errorf("unexpected killers: %v", killers)
+	}
+	if len(guildNames) != 1 || guildNames[0] != "Red Rose" {
+		t.Errorf("expected tracked guilds to be passed, got %v", guildNames)
+	}
+	if expected := now.Add(-7 * 24 * time.Hour); !since.Equal(expected) {
+		t.Errorf("expected since %v, got %v", expected, since)
+	}
+}
+
+func TestFrags_QueriesSinceServerSave(t *testing.T) {
+	var since time.Time
+	var queriedName string
+	repo := &mockRepository{
+		getFragCountsSinceFunc: func(ctx context.Context, world string, guildNames []string, name string, s time.Time) ([]domain.FragCount, error) {
+			queriedName = name
+			since = s
+			return []domain.FragCount{{Name: "Hero", Frags: 4, Deaths: 1}}, nil
+		},
+	}
+
+	svc := NewStatsService(repo, nil)
+	now := time.Date(2026, 7, 1, 7, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	counts, err := svc.Frags(context.Background(), domain.GuildConfig{World: "Antica"}, "Hero")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(counts) != 1 || counts[0].Frags != 4 || queriedName != "Hero" {
+		t.Errorf("unexpected counts %+v for %q", counts, queriedName)
+	}
+	if expected := domain.LastServerSave(now); !since.Equal(expected) || !since.Before(now) {
+		t.Errorf("expected since %v, got %v", expected, since)
+	}
+}
+
+func TestLevelPace_UsesCanonicalNameAndLastWeek(t *testing.T) {
+	var queried string
+	var since time.Time
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return &domain.Player{Name: "Hero", Level: 310}, nil
+	}}
+	repo := &mockRepository{
+		getLevelUpsSinceFunc: func(ctx context.Context, name string, s time.Time) ([]domain.LevelUp, error) {
+			queried = name
+			since = s
+			return []domain.LevelUp{{OldLevel: 296, NewLevel: 297}}, nil
+		},
+	}
+
+	svc := NewStatsService(repo, fetcher)
+	now := time.Date(2024, 12, 13, 15, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	pace, err := svc.LevelPace(context.Background(), "hero")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queried != "Hero" {
+		t.Errorf("expected history of 'Hero', got '%s'", queried)
+	}
+	if expected := now.Add(-7 * 24 * time.Hour); !since.Equal(expected) {
+		t.Errorf("expected since %v, got %v", expected, since)
+	}
+	if pace.Level != 310 || pace.Gained != 14 || pace.PerDay() != 2 {
+		t.Errorf("unexpected pace: %+v", pace)
+	}
+}
+
+func TestLevelForecast_AveragesCompleteDays(t *testing.T) {
+	var since time.Time
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return &domain.Player{Name: "Hero", Level: 310}, nil
+	}}
+	day := func(d int) time.Time { return time.Date(2024, 12, d, 0, 0, 0, 0, time.UTC) }
+	repo := &mockRepository{
+		getDailyLevelGainsFunc: func(ctx context.Context, name string, s time.Time) ([]domain.DailyLevelGain, error) {
+			since = s
+			// Only the last complete day and today gained levels.
+			return []domain.DailyLevelGain{{Day: day(12), Levels: 8}, {Day: day(13), Levels: 40}}, nil
+		},
+		getOnlineTimeSinceFunc: func(ctx context.Context, name string, s time.Time) (time.Duration, error) {
+			if name != "Hero" || !s.Equal(time.Date(2024, 12, 6, 15, 30, 0, 0, time.UTC)) {
+				t.Errorf("expected Hero's online time over the last 7 days, got %q since %v", name, s)
+			}
+			return 90 * time.Minute, nil
+		},
+	}
+
+	svc := NewStatsService(repo, fetcher)
+	svc.now = func() time.Time { return time.Date(2024, 12, 13, 15, 30, 0, 0, time.UTC) }
+
+	forecast, err := svc.LevelForecast(context.Background(), "hero")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !since.Equal(day(6)) {
+		t.Errorf("expected the last 7 complete days from %v, got %v", day(6), since)
+	}
+	// Six idle days seed the average at 0, then 8 levels weigh in by 2/(7+1).
+	if forecast.Name != "Hero" || forecast.Level != 310 || forecast.PerDay != 2 || forecast.Days != 7 || forecast.Online != 90*time.Minute {
+		t.Errorf("unexpected forecast: %+v", forecast)
+	}
+}
+
+func TestLevelPace_CharacterNotFound(t *testing.T) {
+	fetcher := &mockFetcher{fetchCharacterFunc: func(ctx context.Context, name string) (*domain.Player, error) {
+		return nil, nil
+	}}
+
+	svc := NewStatsService(&mockRepository{}, fetcher)
+	if _, err := svc.LevelPace(context.Background(), "Nobody"); err == nil {
+		t.Error("expected an error for a missing character")
+	}
+}
+
+func TestWarScore_CountsBothWaysDuringTheWar(t *testing.T) {
+	start := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(24 * time.Hour)
+	type query struct {
+		victims, killers []string
+		since, until     time.Time
+	}
+	var queries []query
+	repo := &mockRepository{
+		getGuildWarFunc: func(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+			return &domain.GuildWar{GuildName: guildName, StartedAt: start}, nil
+		},
+		countKillsBetweenFunc: func(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+			queries = append(queries, query{victims, killers, since, until})
+			return len(queries) * 2, nil
+		},
+	}
+	fetcher := &mockFetcher{fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+		if name == "Red Rose" {
+			return &domain.Guild{Name: name, Members: []domain.Player{{Name: "Alice"}}}, nil
+		}
+		return &domain.Guild{Name: "Black Thorn", Members: []domain.Player{{Name: "Bob"}}}, nil
+	}}
+
+	svc := NewStatsService(repo, fetcher)
+	svc.now = func() time.Time { return now }
+
+	score, err := svc.WarScore(context.Background(), domain.GuildConfig{World: "Antica"}, "Red Rose", "black thorn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score.Opponent != "Black Thorn" || score.Kills != 2 || score.Deaths != 4 {
+		t.Errorf("unexpected score: %+v", score)
+	}
+	if len(queries) != 2 || queries[0].victims[0] != "Bob" || queries[0].killers[0] != "Alice" || queries[1].victims[0] != "Alice" {
+		t.Fatalf("expected kills and deaths to be counted, got %+v", queries)
+	}
+	if !queries[0].since.Equal(start) || !queries[0].until.Equal(now) {
+		t.Errorf("expected the war window, got %v to %v", queries[0].since, queries[0].until)
+	}
+}
+
+func TestWarScore_NoWar(t *testing.T) {
+	svc := NewStatsService(&mockRepository{}, nil)
+	if _, err := svc.WarScore(context.Background(), domain.GuildConfig{World: "Antica"}, "Red Rose", "Black Thorn"); !errors.Is(err, ErrNoGuildWar) {
+		t.Errorf("expected ErrNoGuildWar, got %v", err)
+	}
+}
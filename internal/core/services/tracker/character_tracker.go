@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// CharacterTracker follows tracked characters through renames and world
+// transfers, which would otherwise look like one character vanishing and
+// another appearing.
+type CharacterTracker struct {
+	storage  ports.Repository
+	notifier ports.NotificationService
+}
+
+func NewCharacterTracker(store ports.Repository, notifier ports.NotificationService) *CharacterTracker {
+	return &CharacterTracker{
+		storage:  store,
+		notifier: notifier,
+	}
+}
+
+// Check migrates what is stored about char when it changed. A stored name
+// among its former names moves to the current name; a character stored on
+// world that now plays elsewhere moves to its new world. Guilds tracking the
+// character are told either way. Check reports whether char left world,
+// after which only its new world tracks it.
+func (t *CharacterTracker) Check(ctx context.Context, char *domain.Player, world string, dbLevels map[string]int, guilds []domain.GuildConfig, memberships map[string]map[string]bool) bool {
+	change := domain.CharacterChange{Name: char.Name, World: char.World}
+
+	for _, former := range char.FormerNames {
+		level, stored := dbLevels[former]
+		if !stored || former == char.Name {
+			continue
+		}
+		if err := t.storage.RenamePlayer(ctx, former, char.Name); err != nil {
+			slog.ErrorContext(ctx, "Failed to migrate renamed character", "old_name", former, "name", char.Name, "error", err)
+			continue
+		}
+		delete(dbLevels, former)
+		if _, ok := dbLevels[char.Name]; !ok {
+			dbLevels[char.Name] = level
+		}
+		if change.OldName == "" {
+			change.OldName = former
+		}
+	}
+
+	_, stored := dbLevels[char.Name]
+	movedAway := stored && char.World != "" && !strings.EqualFold(char.World, world)
+	if movedAway {
+		if err := t.storage.UpsertPlayerLevel(ctx, char.Name, char.Level, char.World); err != nil {
+			slog.ErrorContext(ctx, "Failed to move transferred character", "name", char.Name, "world", char.World, "error", err)
+			return true
+		}
+		delete(dbLevels, char.Name)
+		change.OldWorld = world
+	}
+
+	if change.OldName == "" && change.OldWorld == "" {
+		return movedAway
+	}
+
+	slog.InfoContext(ctx, "Character changed", "name", change.Name, "old_name", change.OldName, "world", change.World, "old_world", change.OldWorld)
+	for _, guild := range guilds {
+		if !t.tracks(guild, change, memberships) {
+			continue
+		}
+		if err := t.notifier.SendCharacterChangeNotification(guild, change); err != nil {
+			slog.ErrorContext(ctx, "Failed to send character change notification", "guild_id", guild.DiscordGuildID, "error", err)
+		}
+	}
+	return movedAway
+}
+
+// tracks reports whether guild follows the character under either name.
+func (t *CharacterTracker) tracks(guild domain.GuildConfig, change domain.CharacterChange, memberships map[string]map[string]bool) bool {
+	if shouldNotifyGuild(change.Name, guild, memberships) {
+		return true
+	}
+	return change.OldName != "" && shouldNotifyGuild(change.OldName, guild, memberships)
+}
@@ -0,0 +1,106 @@
+package tracker
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestCharacterTracker_Check(t *testing.T) {
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "guild-1"},
+		{DiscordGuildID: "guild-2", TibiaGuilds: []string{"Red Rose"}},
+	}
+
+	t.Run("renamed character keeps its history", func(t *testing.T) {
+		var renamed [2]string
+		storage := &mockServiceStorage{
+			renamePlayerFunc: func(ctx context.Context, oldName, newName string) error {
+				renamed = [2]string{oldName, newName}
+				return nil
+			},
+		}
+		var notified []string
+		var got domain.CharacterChange
+		notifier := &mockServiceNotifier{
+			sendChangeFunc: func(guildID string, change domain.CharacterChange) error {
+				notified = append(notified, guildID)
+				got = change
+				return nil
+			},
+		}
+		dbLevels := map[string]int{"Old Hero": 300}
+		char := &domain.Player{Name: "New Hero", Level: 301, World: "Antica", FormerNames: []string{"Old Hero"}}
+
+		moved := NewCharacterTracker(storage, notifier).Check(context.Background(), char, "Antica", dbLevels, guilds, nil)
+
+		if moved {
+			t.Error("expected a rename on the same world not to count as moving away")
+		}
+		if renamed != [2]string{"Old Hero", "New Hero"} {
+			t.Errorf("expected Old Hero renamed to New Hero, got %v", renamed)
+		}
+		if _, ok := dbLevels["Old Hero"]; ok || dbLevels["New Hero"] != 300 {
+			t.Errorf("expected the stored level under the new name, got %v", dbLevels)
+		}
+		if !slices.Equal(notified, []string{"guild-1"}) {
+			t.Errorf("expected only the world-wide guild notified, got %v", notified)
+		}
+		if got.OldName != "Old Hero" || got.OldWorld != "" {
+			t.Errorf("unexpected change: %+v", got)
+		}
+	})
+
+	t.Run("transferred character moves to its new world", func(t *testing.T) {
+		var movedTo string
+		storage := &mockServiceStorage{
+			upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+				movedTo = world
+				return nil
+			},
+		}
+		var got domain.CharacterChange
+		notifier := &mockServiceNotifier{
+			sendChangeFunc: func(guildID string, change domain.CharacterChange) error {
+				got = change
+				return nil
+			},
+		}
+		dbLevels := map[string]int{"Hero": 300}
+		char := &domain.Player{Name: "Hero", Level: 300, World: "Secura"}
+
+		moved := NewCharacterTracker(storage, notifier).Check(context.Background(), char, "Antica", dbLevels, guilds, nil)
+
+		if !moved || movedTo != "Secura" {
+			t.Errorf("expected Hero moved to Secura, got moved=%v world=%q", moved, movedTo)
+		}
+		if _, ok := dbLevels["Hero"]; ok {
+			t.Error("expected Hero dropped from this world's levels")
+		}
+		if got.OldWorld != "Antica" || got.World != "Secura" {
+			t.Errorf("unexpected change: %+v", got)
+		}
+	})
+
+	t.Run("unchanged character is left alone", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			renamePlayerFunc: func(ctx context.Context, oldName, newName string) error {
+				t.Error("expected no rename")
+				return nil
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendChangeFunc: func(guildID string, change domain.CharacterChange) error {
+				t.Error("expected no announcement")
+				return nil
+			},
+		}
+		char := &domain.Player{Name: "Hero", World: "Antica", FormerNames: []string{"Long Gone"}}
+
+		if NewCharacterTracker(storage, notifier).Check(context.Background(), char, "Antica", map[string]int{"Hero": 300}, guilds, nil) {
+			t.Error("expected Hero to stay on Antica")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package tracker
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -34,9 +35,11 @@ func NewDeathTracker(notifier ports.NotificationService) *DeathTracker {
 	}
 }
 
-func (d *DeathTracker) CheckDeaths(player *domain.Player, guilds []domain.GuildConfig, memberships map[string]map[string]bool) {
+// CheckDeaths notifies guilds about unseen recent deaths and returns them.
+func (d *DeathTracker) CheckDeaths(ctx context.Context, player *domain.Player, guilds []domain.GuildConfig, memberships map[string]map[string]bool) []domain.Kill {
 	d.evictOld()
 
+	var newDeaths []domain.Kill
 	for _, death := range player.Deaths {
 		if d.isOldDeath(death.Time) {
 			continue
@@ -46,8 +49,10 @@ func (d *DeathTracker) CheckDeaths(player *domain.Player, guilds []domain.GuildC
 			continue
 		}
 
-		d.notifyDeath(guilds, player.Name, death, memberships)
+		d.notifyDeath(ctx, guilds, player, death, memberships)
+		newDeaths = append(newDeaths, death)
 	}
+	return newDeaths
 }
 
 func (d *DeathTracker) evictOld() {
@@ -80,11 +85,11 @@ func (d *DeathTracker) isDuplicateDeath(name string, t time.Time) bool {
 	return false
 }
 
-func (d *DeathTracker) notifyDeath(guilds []domain.GuildConfig, name string, death domain.Kill, memberships map[string]map[string]bool) {
+func (d *DeathTracker) notifyDeath(ctx context.Context, guilds []domain.GuildConfig, player *domain.Player, death domain.Kill, memberships map[string]map[string]bool) {
 	for _, guild := range guilds {
-		if shouldNotifyGuild(name, guild, memberships) {
-			if err := d.notifier.SendDeathNotification(guild.DiscordGuildID, name, death); err != nil {
-				slog.Error("Failed to send death notification", "guild_id", guild.DiscordGuildID, "error", err)
+		if shouldNotifyGuild(player.Name, guild, memberships) {
+			if err := d.notifier.SendDeathNotification(guild, *player, death); err != nil {
+				slog.ErrorContext(ctx, "Failed to send death notification", "guild_id", guild.DiscordGuildID, "error", err)
 			}
 		}
 	}
@@ -1,6 +1,7 @@
 package tracker
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"testing"
@@ -217,7 +218,7 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 		oldDeath := domain.Kill{Time: time.Now().Add(-3 * time.Hour)}
 		player := &domain.Player{Name: "P1", Deaths: []domain.Kill{oldDeath}}
 
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if notified {
 			t.Error("expected no notification for old death")
@@ -237,7 +238,7 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 		newDeath := domain.Kill{Time: time.Now()}
 		player := &domain.Player{Name: "P1", Deaths: []domain.Kill{newDeath}}
 
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if !notified {
 			t.Error("expected notification for new death")
@@ -257,9 +258,9 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 		death := domain.Kill{Time: time.Now()}
 		player := &domain.Player{Name: "P1", Deaths: []domain.Kill{death}}
 
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if notifyCount != 1 {
 			t.Errorf("expected 1, got %d", notifyCount)
@@ -283,7 +284,7 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 		}
 		player := &domain.Player{Name: "P1", Deaths: deaths}
 
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if notifyCount != 3 {
 			t.Errorf("expected 3, got %d", notifyCount)
@@ -309,7 +310,7 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 		}
 		player := &domain.Player{Name: "P1", Deaths: deaths}
 
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if notifyCount != 2 {
 			t.Errorf("expected 2 (only new deaths), got %d", notifyCount)
@@ -325,12 +326,12 @@ func TestDeathTracker_CheckDeaths(t *testing.T) {
 
 		death := domain.Kill{Time: time.Now()}
 		player := &domain.Player{Name: "P1", Deaths: []domain.Kill{death}}
-		tracker.CheckDeaths(player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		time.Sleep(5 * time.Millisecond)
 
 		player2 := &domain.Player{Name: "P2", Deaths: []domain.Kill{}}
-		tracker.CheckDeaths(player2, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
+		tracker.CheckDeaths(context.Background(), player2, []domain.GuildConfig{{DiscordGuildID: "g1"}}, nil)
 
 		if len(tracker.seenDeaths) != 0 {
 			t.Errorf("expected eviction, got %d entries", len(tracker.seenDeaths))
@@ -354,7 +355,7 @@ func TestDeathTracker_NotifyDeath(t *testing.T) {
 		}
 
 		tracker := &DeathTracker{notifier: notifier}
-		tracker.notifyDeath(guilds, "Player", domain.Kill{}, nil)
+		tracker.notifyDeath(context.Background(), guilds, &domain.Player{Name: "Player"}, domain.Kill{}, nil)
 
 		if len(notifiedGuilds) != 2 {
 			t.Errorf("expected 2, got %d", len(notifiedGuilds))
@@ -370,7 +371,7 @@ func TestDeathTracker_NotifyDeath(t *testing.T) {
 
 		guilds := []domain.GuildConfig{{DiscordGuildID: "g1"}}
 		tracker := &DeathTracker{notifier: notifier}
-		tracker.notifyDeath(guilds, "Player", domain.Kill{}, nil)
+		tracker.notifyDeath(context.Background(), guilds, &domain.Player{Name: "Player"}, domain.Kill{}, nil)
 	})
 
 	t.Run("filters by guild membership", func(t *testing.T) {
@@ -392,7 +393,7 @@ func TestDeathTracker_NotifyDeath(t *testing.T) {
 		}
 
 		tracker := &DeathTracker{notifier: notifier}
-		tracker.notifyDeath(guilds, "Player", domain.Kill{}, memberships)
+		tracker.notifyDeath(context.Background(), guilds, &domain.Player{Name: "Player"}, domain.Kill{}, memberships)
 
 		if len(notifiedGuilds) != 1 || notifiedGuilds[0] != "g1" {
 			t.Errorf("expected only g1, got %v", notifiedGuilds)
@@ -454,17 +455,61 @@ type mockDeathNotifier struct {
 	sendDeathFunc func(guildID, name string, death domain.Kill) error
 }
 
-func (m *mockDeathNotifier) SendDeathNotification(guildID string, playerName string, kill domain.Kill) error {
+func (m *mockDeathNotifier) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
 	if m.onNotify != nil {
 		m.onNotify()
 	}
 	if m.sendDeathFunc != nil {
-		return m.sendDeathFunc(guildID, playerName, kill)
+		return m.sendDeathFunc(guild.DiscordGuildID, player.Name, kill)
 	}
 	return nil
 }
 
-func (m *mockDeathNotifier) SendLevelUpNotification(guildID string, levelUp domain.LevelUp) error {
+func (m *mockDeathNotifier) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	return nil
+}
+
+func (m *mockDeathNotifier) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
 	return nil
 }
 
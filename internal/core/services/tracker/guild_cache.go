@@ -0,0 +1,181 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+const defaultGuildCacheTTL = 15 * time.Minute
+
+type GuildCacheItem struct {
+	Members   []string
+	ExpiresAt time.Time
+}
+
+func (s *Service) guildCacheTTL() time.Duration {
+	if s.config.GuildCacheTTL > 0 {
+		return s.config.GuildCacheTTL
+	}
+	return defaultGuildCacheTTL
+}
+
+// getGuildMembers returns the cached member list of guildName, fetching it
+// when missing or expired. A failed fetch falls back to the stale entry.
+func (s *Service) getGuildMembers(ctx context.Context, guildName string, guilds []domain.GuildConfig) []string {
+	s.cacheMu.RLock()
+	item, cached := s.guildCache[guildName]
+	s.cacheMu.RUnlock()
+
+	if cached && time.Now().Before(item.ExpiresAt) {
+		return item.Members
+	}
+
+	members, err := s.refreshGuild(ctx, guildName, guilds)
+	if err != nil {
+		if cached {
+			slog.InfoContext(ctx, "Using stale cache for guild", "guild", guildName)
+			return item.Members
+		}
+		return nil
+	}
+	return members
+}
+
+// loadGuildCache seeds the cache with the member lists stored by a previous
+// run. Entries keep their fetch time, so expired ones are refetched by the
+// first warm-up but still serve as the stale fallback until then.
+func (s *Service) loadGuildCache(ctx context.Context) {
+	lists, err := s.storage.GetGuildMemberCache(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load stored guild member cache", "error", err)
+		return
+	}
+
+	ttl := s.guildCacheTTL()
+	s.cacheMu.Lock()
+	for _, list := range lists {
+		if _, ok := s.guildCache[list.GuildName]; ok {
+			continue
+		}
+		s.guildCache[list.GuildName] = GuildCacheItem{
+			Members:   list.Members,
+			ExpiresAt: list.FetchedAt.Add(ttl),
+		}
+	}
+	s.cacheMu.Unlock()
+
+	if len(lists) > 0 {
+		slog.InfoContext(ctx, "Loaded stored guild member cache", "guilds", len(lists))
+	}
+}
+
+// refreshGuild fetches guildName, stores it in the cache and in storage, and
+// records membership changes.
+func (s *Service) refreshGuild(ctx context.Context, guildName string, guilds []domain.GuildConfig) ([]string, error) {
+	members, err := s.fetcher.FetchGuildMembers(ctx, guildName)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to fetch guild members", "guild", guildName, "error", err)
+		return nil, err
+	}
+
+	fetchedAt := time.Now()
+	s.cacheMu.Lock()
+	s.guildCache[guildName] = GuildCacheItem{
+		Members:   members,
+		ExpiresAt: fetchedAt.Add(s.guildCacheTTL()),
+	}
+	s.cacheMu.Unlock()
+
+	list := domain.GuildMemberList{GuildName: guildName, Members: members, FetchedAt: fetchedAt}
+	if err := s.storage.SaveGuildMemberCache(ctx, list); err != nil {
+		slog.WarnContext(ctx, "Failed to store guild member cache", "guild", guildName, "error", err)
+	}
+
+	s.memberTracker.Update(ctx, guildName, members, guilds)
+	return members, nil
+}
+
+// warmGuildCache fetches every configured Tibia guild whose entry expires
+// within horizon, WORKER_POOL_SIZE at a time, and drops entries for guilds no
+// longer tracked, in memory and in storage. Standby replicas skip it so
+// membership changes are announced once.
+func (s *Service) warmGuildCache(ctx context.Context, horizon time.Duration) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.storage.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs for guild cache", "error", err)
+		return
+	}
+
+	tracked := make(map[string]bool)
+	for _, cfg := range configs {
+		for _, g := range cfg.TibiaGuilds {
+			tracked[g] = true
+		}
+	}
+
+	deadline := time.Now().Add(horizon)
+	var due []string
+	s.cacheMu.Lock()
+	for name := range s.guildCache {
+		if !tracked[name] {
+			delete(s.guildCache, name)
+		}
+	}
+	for name := range tracked {
+		if item, ok := s.guildCache[name]; !ok || item.ExpiresAt.Before(deadline) {
+			due = append(due, name)
+		}
+	}
+	s.cacheMu.Unlock()
+
+	if _, err := s.storage.DeleteGuildMemberCacheExcept(ctx, slices.Collect(maps.Keys(tracked))); err != nil {
+		slog.WarnContext(ctx, "Failed to prune stored guild member cache", "error", err)
+	}
+
+	if len(due) == 0 {
+		return
+	}
+
+	start := time.Now()
+	sem := make(chan struct{}, max(1, s.config.WorkerPoolSize))
+	var wg sync.WaitGroup
+	for _, name := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.refreshGuild(ctx, name, configs)
+		}()
+	}
+	wg.Wait()
+	slog.InfoContext(ctx, "Refreshed guild member cache", "guilds", len(due), "duration", time.Since(start))
+}
+
+// refreshGuildCache keeps the guild cache warm in the background, refreshing
+// entries in the last half of their TTL so tracking cycles rarely block on a
+// guild fetch.
+func (s *Service) refreshGuildCache(ctx context.Context) {
+	interval := s.guildCacheTTL() / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.warmGuildCache(ctx, interval)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestWarmGuildCache(t *testing.T) {
+	newService := func(fetched *[]string) *Service {
+		var mu sync.Mutex
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return []domain.GuildConfig{
+					{DiscordGuildID: "1", TibiaGuilds: []string{"G1", "G2"}},
+					{DiscordGuildID: "2", TibiaGuilds: []string{"G2", "G3"}},
+				}, nil
+			},
+		}
+		fetcher := &mockServiceFetcher{
+			fetchGuildMembersFunc: func(ctx context.Context, name string) ([]string, error) {
+				mu.Lock()
+				*fetched = append(*fetched, name)
+				mu.Unlock()
+				return []string{name + " Member"}, nil
+			},
+		}
+		return makeService(storage, fetcher, nil, &config.Config{WorkerPoolSize: 2, GuildCacheTTL: time.Hour})
+	}
+
+	t.Run("fetches every tracked guild once", func(t *testing.T) {
+		var fetched []string
+		service := newService(&fetched)
+		service.guildCache["Old Guild"] = GuildCacheItem{Members: []string{"X"}, ExpiresAt: time.Now().Add(time.Hour)}
+
+		service.warmGuildCache(context.Background(), 0)
+
+		if len(fetched) != 3 {
+			t.Errorf("expected 3 fetches, got %v", fetched)
+		}
+		if _, ok := service.guildCache["Old Guild"]; ok {
+			t.Error("expected untracked guild to be dropped from cache")
+		}
+		item := service.guildCache["G2"]
+		if time.Until(item.ExpiresAt) < 59*time.Minute {
+			t.Errorf("expected configured TTL to be applied, expires at %v", item.ExpiresAt)
+		}
+
+		// A cycle right after warm-up is served from the cache.
+		fetched = nil
+		service.fetchGuildMemberships(context.Background(), []domain.GuildConfig{{TibiaGuilds: []string{"G1"}}})
+		if len(fetched) != 0 {
+			t.Errorf("expected no fetch after warm-up, got %v", fetched)
+		}
+	})
+
+	t.Run("refreshes only entries expiring within horizon", func(t *testing.T) {
+		var fetched []string
+		service := newService(&fetched)
+		service.guildCache["G1"] = GuildCacheItem{ExpiresAt: time.Now().Add(time.Minute)}
+		service.guildCache["G2"] = GuildCacheItem{ExpiresAt: time.Now().Add(time.Hour)}
+		service.guildCache["G3"] = GuildCacheItem{ExpiresAt: time.Now().Add(time.Hour)}
+
+		service.warmGuildCache(context.Background(), 30*time.Minute)
+
+		if len(fetched) != 1 || fetched[0] != "G1" {
+			t.Errorf("expected only G1 to be refreshed, got %v", fetched)
+		}
+	})
+
+	t.Run("skipped on standby", func(t *testing.T) {
+		var fetched []string
+		service := newService(&fetched)
+		service.leader = &mockLeader{leader: false}
+
+		service.warmGuildCache(context.Background(), 0)
+
+		if len(fetched) != 0 {
+			t.Errorf("expected no fetch on standby, got %v", fetched)
+		}
+	})
+}
+
+func TestWarmGuildCache_Storage(t *testing.T) {
+	var mu sync.Mutex
+	var saved []string
+	var kept []string
+	storage := &mockServiceStorage{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{{DiscordGuildID: "1", TibiaGuilds: []string{"G1", "G2"}}}, nil
+		},
+		saveGuildMemberCacheFunc: func(ctx context.Context, list domain.GuildMemberList) error {
+			mu.Lock()
+			saved = append(saved, list.GuildName)
+			mu.Unlock()
+			if len(list.Members) != 1 || list.FetchedAt.IsZero() {
+				t.Errorf("unexpected stored list: %+v", list)
+			}
+			return nil
+		},
+		deleteGuildMemberCacheFunc: func(ctx context.Context, keep []string) (int64, error) {
+			kept = keep
+			return 1, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchGuildMembersFunc: func(ctx context.Context, name string) ([]string, error) {
+			return []string{name + " Member"}, nil
+		},
+	}
+	service := makeService(storage, fetcher, nil, &config.Config{WorkerPoolSize: 2, GuildCacheTTL: time.Hour})
+
+	service.warmGuildCache(context.Background(), 0)
+
+	slices.Sort(saved)
+	if !slices.Equal(saved, []string{"G1", "G2"}) {
+		t.Errorf("expected every fetched guild stored, got %v", saved)
+	}
+	slices.Sort(kept)
+	if !slices.Equal(kept, []string{"G1", "G2"}) {
+		t.Errorf("expected storage pruned to tracked guilds, got %v", kept)
+	}
+}
+
+func TestLoadGuildCache(t *testing.T) {
+	var fetched []string
+	storage := &mockServiceStorage{
+		getGuildMemberCacheFunc: func(ctx context.Context) ([]domain.GuildMemberList, error) {
+			return []domain.GuildMemberList{
+				{GuildName: "Fresh", Members: []string{"A"}, FetchedAt: time.Now().Add(-time.Minute)},
+				{GuildName: "Old", Members: []string{"B"}, FetchedAt: time.Now().Add(-2 * time.Hour)},
+			}, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchGuildMembersFunc: func(ctx context.Context, name string) ([]string, error) {
+			fetched = append(fetched, name)
+			return nil, errors.New("upstream down")
+		},
+	}
+	service := makeService(storage, fetcher, nil, &config.Config{GuildCacheTTL: time.Hour})
+
+	service.loadGuildCache(context.Background())
+
+	if members := service.getGuildMembers(context.Background(), "Fresh", nil); !slices.Equal(members, []string{"A"}) {
+		t.Errorf("expected the stored list served from cache, got %v", members)
+	}
+	if len(fetched) != 0 {
+		t.Errorf("expected no fetch for a recent stored list, got %v", fetched)
+	}
+
+	if members := service.getGuildMembers(context.Background(), "Old", nil); !slices.Equal(members, []string{"B"}) {
+		t.Errorf("expected the expired stored list as stale fallback, got %v", members)
+	}
+	if !slices.Equal(fetched, []string{"Old"}) {
+		t.Errorf("expected the expired list to be refetched, got %v", fetched)
+	}
+}
@@ -3,6 +3,9 @@ package tracker
 import (
 	"context"
 	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
 	"death-level-tracker/internal/adapters/metrics"
 	"death-level-tracker/internal/config"
@@ -10,10 +13,24 @@ import (
 	"death-level-tracker/internal/core/ports"
 )
 
+// levelDropConfirmWindow is how soon a level drop must be seen again to be
+// announced; an older unconfirmed drop is forgotten.
+const levelDropConfirmWindow = time.Hour
+
 type LevelTracker struct {
 	config   *config.Config
 	storage  ports.Repository
 	notifier ports.NotificationService
+
+	mu sync.Mutex
+	// drops are level drops seen once and waiting for the next check of the
+	// character to confirm them, keyed by world and name key.
+	drops map[string]pendingDrop
+}
+
+type pendingDrop struct {
+	from, to int
+	seenAt   time.Time
 }
 
 func NewLevelTracker(cfg *config.Config, store ports.Repository, notifier ports.NotificationService) *LevelTracker {
@@ -24,18 +41,111 @@ func NewLevelTracker(cfg *config.Config, store ports.Repository, notifier ports.
 	}
 }
 
-func (l *LevelTracker) CheckLevelUp(ctx context.Context, name string, currentLevel int, world string, dbLevels map[string]int, guilds []domain.GuildConfig, memberships map[string]map[string]bool) {
-	savedLevel, exists := dbLevels[name]
+// CheckLevelUp stores the current level of player and announces a level up
+// unless announced already holds it.
+func (l *LevelTracker) CheckLevelUp(ctx context.Context, player *domain.Player, dbLevels map[string]int, guilds []domain.GuildConfig, memberships map[string]map[string]bool, announced levelUpSet) {
+	savedLevel, exists := dbLevels[player.Name]
+	l.Reconcile(ctx, player, dbLevels)
 
-	if l.shouldUpdateLevel(exists, savedLevel, currentLevel) {
-		if err := l.storage.UpsertPlayerLevel(ctx, name, currentLevel, world); err != nil {
-			slog.Error("Failed to upsert player level", "name", name, "error", err)
+	if l.isLevelUp(exists, savedLevel, player.Level) {
+		slog.InfoContext(ctx, "Level up detected", "name", player.Name, "old_level", savedLevel, "new_level", player.Level)
+		l.notifyLevelUp(ctx, guilds, domain.LevelUp{
+			PlayerName:   player.Name,
+			OldLevel:     savedLevel,
+			NewLevel:     player.Level,
+			World:        player.World,
+			Vocation:     player.Vocation,
+			GuildName:    player.GuildName,
+			GuildRank:    player.GuildRank,
+			WorldPvPType: player.WorldPvPType,
+			ReachedAt:    time.Now(),
+		}, memberships, announced)
+	}
+}
+
+// CheckLevelDown announces a confirmed drop of player's level to the guilds
+// that want level downs and stores the lower level, so the drop is announced
+// once. It does nothing when none of guilds wants level downs.
+func (l *LevelTracker) CheckLevelDown(ctx context.Context, player *domain.Player, dbLevels map[string]int, guilds []domain.GuildConfig, memberships map[string]map[string]bool) {
+	if !wantsLevelDowns(guilds) {
+		return
+	}
+	savedLevel, exists := dbLevels[player.Name]
+	from, ok := l.confirmLevelDrop(player.World, player.Name, savedLevel, exists, player.Level, time.Now())
+	if !ok {
+		return
+	}
+	if savedLevel != player.Level {
+		if err := l.storage.UpsertPlayerLevel(ctx, player.Name, player.Level, player.World); err != nil {
+			slog.ErrorContext(ctx, "Failed to upsert player level", "name", player.Name, "error", err)
 		}
+		dbLevels[player.Name] = player.Level
 	}
+	l.notifyLevelDown(ctx, guilds, domain.LevelDown{
+		PlayerName:   player.Name,
+		OldLevel:     from,
+		NewLevel:     player.Level,
+		World:        player.World,
+		Vocation:     player.Vocation,
+		GuildName:    player.GuildName,
+		GuildRank:    player.GuildRank,
+		WorldPvPType: player.WorldPvPType,
+		DetectedAt:   time.Now(),
+	}, memberships)
+}
 
-	if l.isLevelUp(exists, savedLevel, currentLevel) {
-		slog.Info("Level up detected", "name", name, "old_level", savedLevel, "new_level", currentLevel)
-		l.notifyLevelUp(guilds, name, savedLevel, currentLevel, world, memberships)
+// confirmLevelDrop guards level downs against stale sources: a drop of
+// domain.MinLevelDrop or more is only confirmed when the next check of the
+// character, within levelDropConfirmWindow, still shows it. A cached page
+// lagging behind is corrected by then. It returns the level the confirmed
+// drop started from.
+func (l *LevelTracker) confirmLevelDrop(world, name string, savedLevel int, exists bool, currentLevel int, now time.Time) (int, bool) {
+	key := world + "/" + domain.NameKey(name)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if drop, ok := l.drops[key]; ok {
+		delete(l.drops, key)
+		if now.Sub(drop.seenAt) <= levelDropConfirmWindow && currentLevel <= drop.to {
+			return drop.from, true
+		}
+	}
+	if exists && domain.IsLevelDrop(savedLevel, currentLevel) {
+		if l.drops == nil {
+			l.drops = make(map[string]pendingDrop)
+		}
+		l.drops[key] = pendingDrop{from: savedLevel, to: currentLevel, seenAt: now}
+	}
+	return 0, false
+}
+
+// notifyLevelDown announces the level down to every guild tracking the
+// character that wants level downs at its former level.
+func (l *LevelTracker) notifyLevelDown(ctx context.Context, guilds []domain.GuildConfig, levelDown domain.LevelDown, memberships map[string]map[string]bool) {
+	slog.InfoContext(ctx, "Level down detected", "name", levelDown.PlayerName, "old_level", levelDown.OldLevel, "new_level", levelDown.NewLevel)
+	for _, guild := range guilds {
+		if !guild.LevelDowns || !guild.Announces(levelDown.OldLevel) || !shouldNotifyGuild(levelDown.PlayerName, guild, memberships) {
+			continue
+		}
+		if err := l.notifier.SendLevelDownNotification(guild, levelDown); err != nil {
+			slog.ErrorContext(ctx, "Failed to send level down notification", "guild_id", guild.DiscordGuildID, "error", err)
+		}
+	}
+}
+
+func wantsLevelDowns(guilds []domain.GuildConfig) bool {
+	return slices.ContainsFunc(guilds, func(g domain.GuildConfig) bool { return g.LevelDowns })
+}
+
+// Reconcile stores the current level of player without announcing anything,
+// so a level gained while the bot was down is not reported as a level up.
+func (l *LevelTracker) Reconcile(ctx context.Context, player *domain.Player, dbLevels map[string]int) {
+	savedLevel, exists := dbLevels[player.Name]
+	if !l.shouldUpdateLevel(exists, savedLevel, player.Level) {
+		return
+	}
+	if err := l.storage.UpsertPlayerLevel(ctx, player.Name, player.Level, player.World); err != nil {
+		slog.ErrorContext(ctx, "Failed to upsert player level", "name", player.Name, "error", err)
 	}
 }
 
@@ -50,18 +160,23 @@ func (l *LevelTracker) isLevelUp(exists bool, savedLevel, currentLevel int) bool
 	return exists && currentLevel > savedLevel
 }
 
-func (l *LevelTracker) notifyLevelUp(guilds []domain.GuildConfig, name string, oldLevel, newLevel int, world string, memberships map[string]map[string]bool) {
-	levelUp := domain.LevelUp{
-		PlayerName: name,
-		OldLevel:   oldLevel,
-		NewLevel:   newLevel,
-		World:      world,
+// notifyLevelUp records the level up in the level history and announces it
+// to every guild tracking the character. A level up announced already holds
+// is skipped, whichever level source reported it first.
+func (l *LevelTracker) notifyLevelUp(ctx context.Context, guilds []domain.GuildConfig, levelUp domain.LevelUp, memberships map[string]map[string]bool, announced levelUpSet) {
+	if !announced.claim(levelUp) {
+		slog.DebugContext(ctx, "Skipping level up already announced this cycle", "name", levelUp.PlayerName, "new_level", levelUp.NewLevel)
+		return
+	}
+
+	if err := l.storage.RecordLevelUp(ctx, levelUp); err != nil {
+		slog.ErrorContext(ctx, "Failed to record level up", "name", levelUp.PlayerName, "error", err)
 	}
 
 	for _, guild := range guilds {
-		if shouldNotifyGuild(name, guild, memberships) {
-			if err := l.notifier.SendLevelUpNotification(guild.DiscordGuildID, levelUp); err != nil {
-				slog.Error("Failed to send level up notification", "guild_id", guild.DiscordGuildID, "error", err)
+		if shouldNotifyGuild(levelUp.PlayerName, guild, memberships) {
+			if err := l.notifier.SendLevelUpNotification(guild, levelUp); err != nil {
+				slog.ErrorContext(ctx, "Failed to send level up notification", "guild_id", guild.DiscordGuildID, "error", err)
 			}
 		}
 	}
@@ -70,6 +185,10 @@ func (l *LevelTracker) notifyLevelUp(guilds []domain.GuildConfig, name string, o
 }
 
 func shouldNotifyGuild(characterName string, guild domain.GuildConfig, memberships map[string]map[string]bool) bool {
+	if guild.IsMuted(time.Now()) || guild.IsIgnored(characterName) {
+		return false
+	}
+
 	if len(guild.TibiaGuilds) == 0 {
 		return true
 	}
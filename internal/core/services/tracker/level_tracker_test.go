@@ -8,6 +8,7 @@ import (
 
 	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
 )
 
 func TestNewLevelTracker(t *testing.T) {
@@ -106,7 +107,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 		}
 
 		tracker := &LevelTracker{storage: storage, notifier: notifier}
-		tracker.CheckLevelUp(context.Background(), "NewPlayer", 100, "Antica", map[string]int{}, nil, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "NewPlayer", Level: 100, World: "Antica"}, map[string]int{}, nil, nil, nil)
 
 		if !upserted {
 			t.Error("expected upsert for new player")
@@ -140,7 +141,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 		dbLevels := map[string]int{"Player": 100}
 
 		tracker := &LevelTracker{storage: storage, notifier: notifier}
-		tracker.CheckLevelUp(context.Background(), "Player", 150, "Antica", dbLevels, guilds, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "Player", Level: 150, World: "Antica"}, dbLevels, guilds, nil, nil)
 
 		if !upserted {
 			t.Error("expected upsert")
@@ -150,6 +151,24 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 		}
 	})
 
+	t.Run("level up carries vocation and guild rank", func(t *testing.T) {
+		var got domain.LevelUp
+		notifier := &mockLevelNotifier{
+			sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+				got = levelUp
+				return nil
+			},
+		}
+
+		player := &domain.Player{Name: "Player", Level: 150, World: "Antica", Vocation: "Elite Knight", GuildName: "Red Rose", GuildRank: "Leader"}
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: notifier}
+		tracker.CheckLevelUp(context.Background(), player, map[string]int{"Player": 100}, []domain.GuildConfig{{DiscordGuildID: "guild-1"}}, nil, nil)
+
+		if got.Vocation != "Elite Knight" || got.GuildName != "Red Rose" || got.GuildRank != "Leader" {
+			t.Errorf("unexpected level up profile: %+v", got)
+		}
+	})
+
 	t.Run("same level - no action", func(t *testing.T) {
 		var upserted bool
 		var notified bool
@@ -166,7 +185,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 
 		dbLevels := map[string]int{"Player": 100}
 		tracker := &LevelTracker{storage: storage, notifier: notifier}
-		tracker.CheckLevelUp(context.Background(), "Player", 100, "Antica", dbLevels, nil, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "Player", Level: 100, World: "Antica"}, dbLevels, nil, nil, nil)
 
 		if upserted {
 			t.Error("expected no upsert for same level")
@@ -192,7 +211,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 
 		dbLevels := map[string]int{"Player": 150}
 		tracker := &LevelTracker{storage: storage, notifier: notifier}
-		tracker.CheckLevelUp(context.Background(), "Player", 100, "Antica", dbLevels, nil, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "Player", Level: 100, World: "Antica"}, dbLevels, nil, nil, nil)
 
 		if upserted {
 			t.Error("expected no upsert for level down")
@@ -210,7 +229,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 		}
 
 		tracker := &LevelTracker{storage: storage, notifier: &mockLevelNotifier{}}
-		tracker.CheckLevelUp(context.Background(), "Player", 100, "Antica", map[string]int{}, nil, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "Player", Level: 100, World: "Antica"}, map[string]int{}, nil, nil, nil)
 	})
 
 	t.Run("notification error - continues gracefully", func(t *testing.T) {
@@ -229,7 +248,7 @@ func TestLevelTracker_CheckLevelUp(t *testing.T) {
 		dbLevels := map[string]int{"Player": 100}
 
 		tracker := &LevelTracker{storage: storage, notifier: notifier}
-		tracker.CheckLevelUp(context.Background(), "Player", 150, "Antica", dbLevels, guilds, nil)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "Player", Level: 150, World: "Antica"}, dbLevels, guilds, nil, nil)
 	})
 }
 
@@ -248,8 +267,8 @@ func TestLevelTracker_NotifyLevelUp_GuildFiltering(t *testing.T) {
 			{DiscordGuildID: "g2", TibiaGuilds: []string{}},
 		}
 
-		tracker := &LevelTracker{notifier: notifier}
-		tracker.notifyLevelUp(guilds, "Player", 100, 150, "Antica", nil)
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: notifier}
+		tracker.notifyLevelUp(context.Background(), guilds, domain.LevelUp{PlayerName: "Player", OldLevel: 100, NewLevel: 150, World: "Antica"}, nil, nil)
 
 		if len(notifiedGuilds) != 2 {
 			t.Errorf("expected 2, got %d", len(notifiedGuilds))
@@ -274,8 +293,8 @@ func TestLevelTracker_NotifyLevelUp_GuildFiltering(t *testing.T) {
 			"OtherGuild": {"Someone": true},
 		}
 
-		tracker := &LevelTracker{notifier: notifier}
-		tracker.notifyLevelUp(guilds, "Player", 100, 150, "Antica", memberships)
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: notifier}
+		tracker.notifyLevelUp(context.Background(), guilds, domain.LevelUp{PlayerName: "Player", OldLevel: 100, NewLevel: 150, World: "Antica"}, memberships, nil)
 
 		if len(notifiedGuilds) != 1 || notifiedGuilds[0] != "g1" {
 			t.Errorf("expected only g1, got %v", notifiedGuilds)
@@ -298,13 +317,141 @@ func TestLevelTracker_NotifyLevelUp_GuildFiltering(t *testing.T) {
 			"SomeGuild": {"OtherPlayer": true},
 		}
 
-		tracker := &LevelTracker{notifier: notifier}
-		tracker.notifyLevelUp(guilds, "Player", 100, 150, "Antica", memberships)
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: notifier}
+		tracker.notifyLevelUp(context.Background(), guilds, domain.LevelUp{PlayerName: "Player", OldLevel: 100, NewLevel: 150, World: "Antica"}, memberships, nil)
 
 		if notifyCount != 0 {
 			t.Errorf("expected 0, got %d", notifyCount)
 		}
 	})
+
+	t.Run("records level up in history", func(t *testing.T) {
+		var recorded domain.LevelUp
+		storage := &mockLevelStorage{
+			recordLevelUpFunc: func(ctx context.Context, levelUp domain.LevelUp) error {
+				recorded = levelUp
+				return nil
+			},
+		}
+
+		tracker := &LevelTracker{storage: storage, notifier: &mockLevelNotifier{}}
+		tracker.notifyLevelUp(context.Background(), nil, domain.LevelUp{PlayerName: "Player", OldLevel: 100, NewLevel: 101, World: "Antica"}, nil, nil)
+
+		if recorded.PlayerName != "Player" || recorded.NewLevel != 101 {
+			t.Errorf("expected level up to be recorded, got %+v", recorded)
+		}
+	})
+
+	t.Run("level up already announced this cycle is skipped", func(t *testing.T) {
+		var recorded, sent int
+		storage := &mockLevelStorage{
+			recordLevelUpFunc: func(ctx context.Context, levelUp domain.LevelUp) error {
+				recorded++
+				return nil
+			},
+		}
+		notifier := &mockLevelNotifier{
+			sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+				sent++
+				return nil
+			},
+		}
+
+		tracker := &LevelTracker{storage: storage, notifier: notifier}
+		guilds := []domain.GuildConfig{{DiscordGuildID: "guild-1"}}
+		announced := make(levelUpSet)
+		tracker.notifyLevelUp(context.Background(), guilds, domain.LevelUp{PlayerName: "Player", OldLevel: 100, NewLevel: 101}, nil, announced)
+		tracker.CheckLevelUp(context.Background(), &domain.Player{Name: "player", Level: 101}, map[string]int{"player": 100}, guilds, nil, announced)
+		tracker.notifyLevelUp(context.Background(), guilds, domain.LevelUp{PlayerName: "Player", OldLevel: 101, NewLevel: 102}, nil, announced)
+
+		if recorded != 2 || sent != 2 {
+			t.Errorf("expected the repeated level up to be skipped, got %d recorded and %d sent", recorded, sent)
+		}
+	})
+}
+
+func TestLevelTracker_CheckLevelDown(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "guild-1", LevelDowns: true}, {DiscordGuildID: "guild-2"}}
+
+	t.Run("announces a drop seen twice and stores it", func(t *testing.T) {
+		var upserted []int
+		var sent []string
+		var got domain.LevelDown
+		storage := &mockLevelStorage{
+			upsertFunc: func(ctx context.Context, name string, level int, world string) error {
+				upserted = append(upserted, level)
+				return nil
+			},
+		}
+		notifier := &mockLevelNotifier{
+			sendLevelDownFunc: func(guildID string, levelDown domain.LevelDown) error {
+				sent = append(sent, guildID)
+				got = levelDown
+				return nil
+			},
+		}
+
+		tracker := &LevelTracker{storage: storage, notifier: notifier}
+		dbLevels := map[string]int{"Player": 500}
+		player := &domain.Player{Name: "Player", Level: 497, World: "Antica", Vocation: "Elite Knight"}
+		tracker.CheckLevelDown(context.Background(), player, dbLevels, guilds, nil)
+		if len(sent) != 0 || len(upserted) != 0 {
+			t.Fatalf("expected the first sighting to wait, got %v sent and %v stored", sent, upserted)
+		}
+
+		tracker.CheckLevelDown(context.Background(), player, dbLevels, guilds, nil)
+		if len(sent) != 1 || sent[0] != "guild-1" {
+			t.Fatalf("expected only the guild with level downs notified, got %v", sent)
+		}
+		if got.OldLevel != 500 || got.NewLevel != 497 || got.Vocation != "Elite Knight" {
+			t.Errorf("unexpected level down: %+v", got)
+		}
+		if len(upserted) != 1 || upserted[0] != 497 || dbLevels["Player"] != 497 {
+			t.Errorf("expected the lower level stored, got %v and %d", upserted, dbLevels["Player"])
+		}
+
+		tracker.CheckLevelDown(context.Background(), player, dbLevels, guilds, nil)
+		if len(sent) != 1 {
+			t.Errorf("expected the drop announced once, got %d", len(sent))
+		}
+	})
+
+	t.Run("stale level corrected by the next check is not announced", func(t *testing.T) {
+		notifier := &mockLevelNotifier{
+			sendLevelDownFunc: func(guildID string, levelDown domain.LevelDown) error {
+				t.Errorf("unexpected level down %+v", levelDown)
+				return nil
+			},
+		}
+
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: notifier}
+		dbLevels := map[string]int{"Player": 500}
+		tracker.CheckLevelDown(context.Background(), &domain.Player{Name: "Player", Level: 490, World: "Antica"}, dbLevels, guilds, nil)
+		tracker.CheckLevelDown(context.Background(), &domain.Player{Name: "Player", Level: 500, World: "Antica"}, dbLevels, guilds, nil)
+		tracker.CheckLevelDown(context.Background(), &domain.Player{Name: "Player", Level: 499, World: "Antica"}, dbLevels, guilds, nil)
+		tracker.CheckLevelDown(context.Background(), &domain.Player{Name: "Player", Level: 499, World: "Antica"}, dbLevels, guilds, nil)
+	})
+
+	t.Run("nothing is tracked without a guild wanting level downs", func(t *testing.T) {
+		tracker := &LevelTracker{storage: &mockLevelStorage{}, notifier: &mockLevelNotifier{}}
+		player := &domain.Player{Name: "Player", Level: 400, World: "Antica"}
+		tracker.CheckLevelDown(context.Background(), player, map[string]int{"Player": 500}, guilds[1:], nil)
+		if len(tracker.drops) != 0 {
+			t.Errorf("expected no pending drops, got %v", tracker.drops)
+		}
+	})
+}
+
+func TestLevelTracker_ConfirmLevelDropExpires(t *testing.T) {
+	tracker := &LevelTracker{}
+	now := time.Now()
+	tracker.confirmLevelDrop("Antica", "Player", 500, true, 495, now)
+	if _, ok := tracker.confirmLevelDrop("Antica", "Player", 500, true, 495, now.Add(levelDropConfirmWindow+time.Minute)); ok {
+		t.Error("expected an expired drop to need a new sighting")
+	}
+	if from, ok := tracker.confirmLevelDrop("Antica", "Player", 500, true, 495, now.Add(levelDropConfirmWindow+2*time.Minute)); !ok || from != 500 {
+		t.Errorf("expected the renewed drop from 500 confirmed, got %d %v", from, ok)
+	}
 }
 
 func TestShouldNotifyGuild(t *testing.T) {
@@ -335,6 +482,30 @@ func TestShouldNotifyGuild(t *testing.T) {
 		}
 	})
 
+	t.Run("muted guild - no notify", func(t *testing.T) {
+		guild := domain.GuildConfig{MutedUntil: time.Now().Add(time.Hour)}
+		if shouldNotifyGuild("Player", guild, nil) {
+			t.Error("expected false")
+		}
+	})
+
+	t.Run("ignored player - no notify", func(t *testing.T) {
+		guild := domain.GuildConfig{IgnoredPlayers: []string{"Bubble Bot"}}
+		if shouldNotifyGuild("bubble bot", guild, nil) {
+			t.Error("expected false")
+		}
+		if !shouldNotifyGuild("Player", guild, nil) {
+			t.Error("expected other players to notify")
+		}
+	})
+
+	t.Run("mute expired - notify", func(t *testing.T) {
+		guild := domain.GuildConfig{MutedUntil: time.Now().Add(-time.Minute)}
+		if !shouldNotifyGuild("Player", guild, nil) {
+			t.Error("expected true")
+		}
+	})
+
 	t.Run("guild not in memberships - no notify", func(t *testing.T) {
 		guild := domain.GuildConfig{TibiaGuilds: []string{"NonExistent"}}
 		memberships := map[string]map[string]bool{}
@@ -357,7 +528,8 @@ func TestShouldNotifyGuild(t *testing.T) {
 }
 
 type mockLevelStorage struct {
-	upsertFunc func(ctx context.Context, name string, level int, world string) error
+	upsertFunc        func(ctx context.Context, name string, level int, world string) error
+	recordLevelUpFunc func(ctx context.Context, levelUp domain.LevelUp) error
 }
 
 func (m *mockLevelStorage) UpsertPlayerLevel(ctx context.Context, name string, level int, world string) error {
@@ -390,30 +562,355 @@ func (m *mockLevelStorage) GetOfflinePlayers(ctx context.Context, world string,
 	return nil, nil
 }
 func (m *mockLevelStorage) BatchTouchPlayers(ctx context.Context, names []string) error { return nil }
+func (m *mockLevelStorage) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	return nil
+}
 func (m *mockLevelStorage) DeleteOldPlayers(ctx context.Context, world string, threshold time.Duration) (int64, error) {
 	return 0, nil
 }
+func (m *mockLevelStorage) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	return nil
+}
+func (m *mockLevelStorage) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	return nil
+}
+func (m *mockLevelStorage) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	return nil
+}
+func (m *mockLevelStorage) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	return nil
+}
+func (m *mockLevelStorage) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) GetGuildFailedNotifications(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	return nil
+}
+func (m *mockLevelStorage) DeleteFailedNotification(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *mockLevelStorage) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	return nil
+}
+func (m *mockLevelStorage) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+func (m *mockLevelStorage) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	return nil
+}
+func (m *mockLevelStorage) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	return nil
+}
+func (m *mockLevelStorage) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+func (m *mockLevelStorage) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	return nil
+}
+func (m *mockLevelStorage) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	return false, nil
+}
+func (m *mockLevelStorage) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	return nil
+}
+func (m *mockLevelStorage) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	return nil
+}
+func (m *mockLevelStorage) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	return nil
+}
+
+func (m *mockLevelStorage) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockLevelStorage) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	return domain.PurgeResult{}, nil
+}
+
+func (m *mockLevelStorage) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	if m.recordLevelUpFunc != nil {
+		return m.recordLevelUpFunc(ctx, levelUp)
+	}
+	return nil
+}
+
+func (m *mockLevelStorage) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockLevelStorage) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+
+func (m *mockLevelStorage) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+
+func (m *mockLevelStorage) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	return nil
+}
+
+func (m *mockLevelStorage) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	return false, nil
+}
+
+func (m *mockLevelStorage) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	return nil
+}
+func (m *mockLevelStorage) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	return nil
+}
+
+func (m *mockLevelStorage) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	return nil
+}
+func (m *mockLevelStorage) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	return mockLevelStorageTx{m}, nil
+}
+func (m *mockLevelStorage) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	return nil
+}
+func (m *mockLevelStorage) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	return nil
+}
+func (m *mockLevelStorage) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	return nil
+}
+func (m *mockLevelStorage) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	return nil
+}
+func (m *mockLevelStorage) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	return nil, nil
+}
+func (m *mockLevelStorage) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	return nil
+}
+func (m *mockLevelStorage) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	return nil
+}
+func (m *mockLevelStorage) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	return nil
+}
+func (m *mockLevelStorage) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	return nil, nil
+}
+
+func (m *mockLevelStorage) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	return nil
+}
+
+func (m *mockLevelStorage) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	return nil
+}
+
+func (m *mockLevelStorage) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockLevelStorage) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	return nil, nil
+}
 func (m *mockLevelStorage) Close() {}
 
 type mockLevelNotifier struct {
-	onNotify        func()
-	sendLevelUpFunc func(guildID string, levelUp domain.LevelUp) error
+	onNotify          func()
+	sendLevelUpFunc   func(guildID string, levelUp domain.LevelUp) error
+	sendLevelDownFunc func(guildID string, levelDown domain.LevelDown) error
 }
 
-func (m *mockLevelNotifier) SendLevelUpNotification(guildID string, levelUp domain.LevelUp) error {
+func (m *mockLevelNotifier) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
 	if m.onNotify != nil {
 		m.onNotify()
 	}
 	if m.sendLevelUpFunc != nil {
-		return m.sendLevelUpFunc(guildID, levelUp)
+		return m.sendLevelUpFunc(guild.DiscordGuildID, levelUp)
 	}
 	return nil
 }
 
-func (m *mockLevelNotifier) SendDeathNotification(guildID string, playerName string, kill domain.Kill) error {
+func (m *mockLevelNotifier) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	if m.sendLevelDownFunc != nil {
+		return m.sendLevelDownFunc(guild.DiscordGuildID, levelDown)
+	}
+	return nil
+}
+
+func (m *mockLevelNotifier) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	return nil
+}
+
+func (m *mockLevelNotifier) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
 	return nil
 }
 
 func (m *mockLevelNotifier) SendGenericMessage(guildID, channelName, message string) error {
 	return nil
 }
+
+// mockLevelStorageTx runs a transaction's calls against the mock itself.
+type mockLevelStorageTx struct {
+	*mockLevelStorage
+}
+
+func (mockLevelStorageTx) Commit(ctx context.Context) error   { return nil }
+func (mockLevelStorageTx) Rollback(ctx context.Context) error { return nil }
@@ -0,0 +1,90 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// MassDeathTracker keeps a sliding window of the deaths each guild was told
+// about and warns of a possible war or raid once its MassDeathAlert is
+// reached. Windows are kept in memory; a restart starts them over.
+type MassDeathTracker struct {
+	notifier ports.NotificationService
+
+	mu sync.Mutex
+	// windows holds each guild's victims in its window by guild ID, one entry
+	// per character with its latest death, oldest first.
+	windows map[string][]domain.MassDeathVictim
+}
+
+func NewMassDeathTracker(notifier ports.NotificationService) *MassDeathTracker {
+	return &MassDeathTracker{
+		notifier: notifier,
+		windows:  make(map[string][]domain.MassDeathVictim),
+	}
+}
+
+// RecordDeaths adds name's new deaths to the window of every guild they were
+// announced to. A guild reaching its threshold is alerted and its window
+// emptied, so the next alert needs as many new victims again.
+func (t *MassDeathTracker) RecordDeaths(ctx context.Context, name, world string, deaths []domain.Kill, guilds []domain.GuildConfig, memberships map[string]map[string]bool) {
+	if len(deaths) == 0 {
+		return
+	}
+
+	for _, guild := range guilds {
+		alert := guild.MassDeathAlert
+		if !alert.Enabled() || !shouldNotifyGuild(name, guild, memberships) {
+			continue
+		}
+		victims, ok := t.add(guild.DiscordGuildID, alert, name, deaths)
+		if !ok {
+			continue
+		}
+
+		slog.InfoContext(ctx, "Mass death detected", "guild_id", guild.DiscordGuildID, "world", world, "victims", len(victims))
+		massDeath := domain.MassDeath{World: world, Window: alert.Window, Victims: victims}
+		if err := t.notifier.SendMassDeathNotification(guild, massDeath); err != nil {
+			slog.ErrorContext(ctx, "Failed to send mass death notification", "guild_id", guild.DiscordGuildID, "error", err)
+		}
+	}
+}
+
+// add records name's deaths in the guild's window, drops victims that fell
+// out of it and returns the victims when the threshold is reached.
+func (t *MassDeathTracker) add(guildID string, alert domain.MassDeathAlert, name string, deaths []domain.Kill) ([]domain.MassDeathVictim, bool) {
+	latest := deaths[0]
+	for _, death := range deaths[1:] {
+		if death.Time.After(latest.Time) {
+			latest = death
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	window := slices.DeleteFunc(t.windows[guildID], func(v domain.MassDeathVictim) bool {
+		return domain.SameName(v.Name, name)
+	})
+	window = append(window, domain.MassDeathVictim{Name: name, Level: latest.Level, Time: latest.Time})
+	slices.SortStableFunc(window, func(a, b domain.MassDeathVictim) int { return a.Time.Compare(b.Time) })
+
+	newest := window[len(window)-1].Time
+	start := 0
+	for start < len(window) && window[start].Time.Before(newest.Add(-alert.Window)) {
+		start++
+	}
+	window = slices.Clone(window[start:])
+
+	if len(window) < alert.Deaths {
+		t.windows[guildID] = window
+		return nil, false
+	}
+	delete(t.windows, guildID)
+	return window, true
+}
@@ -0,0 +1,70 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestMassDeathTracker_RecordDeaths(t *testing.T) {
+	now := time.Now()
+	alert := domain.MassDeathAlert{Deaths: 3, Window: 10 * time.Minute}
+
+	t.Run("alerts once the threshold is reached within the window", func(t *testing.T) {
+		var sent []domain.MassDeath
+		notifier := &mockServiceNotifier{
+			sendMassFunc: func(guildID string, massDeath domain.MassDeath) error {
+				sent = append(sent, massDeath)
+				return nil
+			},
+		}
+		tracker := NewMassDeathTracker(notifier)
+		guilds := []domain.GuildConfig{{DiscordGuildID: "guild-1", MassDeathAlert: alert}}
+
+		tracker.RecordDeaths(context.Background(), "Old", "Antica", []domain.Kill{{Time: now.Add(-30 * time.Minute), Level: 100}}, guilds, nil)
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", []domain.Kill{{Time: now.Add(-5 * time.Minute), Level: 300}}, guilds, nil)
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", []domain.Kill{{Time: now.Add(-4 * time.Minute), Level: 299}}, guilds, nil)
+		if len(sent) != 0 {
+			t.Fatalf("expected deaths outside the window and repeated victims not to count, got %+v", sent)
+		}
+		tracker.RecordDeaths(context.Background(), "Mage", "Antica", []domain.Kill{{Time: now, Level: 400}}, guilds, nil)
+		if len(sent) != 0 {
+			t.Fatalf("expected no alert with 2 victims, got %+v", sent)
+		}
+		tracker.RecordDeaths(context.Background(), "Knight", "Antica", []domain.Kill{{Time: now.Add(-time.Minute), Level: 500}}, guilds, nil)
+
+		if len(sent) != 1 {
+			t.Fatalf("expected one alert, got %d", len(sent))
+		}
+		got := sent[0]
+		if got.World != "Antica" || got.Window != alert.Window || len(got.Victims) != 3 {
+			t.Fatalf("unexpected alert: %+v", got)
+		}
+		if got.Victims[0].Name != "Hero" || got.Victims[0].Level != 299 || got.Victims[1].Name != "Knight" || got.Victims[2].Name != "Mage" {
+			t.Errorf("expected victims in order of death, got %+v", got.Victims)
+		}
+
+		tracker.RecordDeaths(context.Background(), "Druid", "Antica", []domain.Kill{{Time: now, Level: 200}}, guilds, nil)
+		if len(sent) != 1 {
+			t.Errorf("expected the window to start over after an alert, got %d alerts", len(sent))
+		}
+	})
+
+	t.Run("skips guilds without alert or not tracking the victim", func(t *testing.T) {
+		notifier := &mockServiceNotifier{
+			sendMassFunc: func(guildID string, massDeath domain.MassDeath) error {
+				t.Errorf("unexpected alert for %s", guildID)
+				return nil
+			},
+		}
+		tracker := NewMassDeathTracker(notifier)
+		guilds := []domain.GuildConfig{
+			{DiscordGuildID: "off"},
+			{DiscordGuildID: "members-only", TibiaGuilds: []string{"Red Rose"}, MassDeathAlert: domain.MassDeathAlert{Deaths: 1, Window: time.Minute}},
+		}
+
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", []domain.Kill{{Time: now}}, guilds, nil)
+	})
+}
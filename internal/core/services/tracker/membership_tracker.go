@@ -0,0 +1,83 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// MembershipTracker compares a Tibia guild's fetched member list with the last
+// stored one and announces characters that joined or left.
+type MembershipTracker struct {
+	storage  ports.Repository
+	notifier ports.NotificationService
+}
+
+func NewMembershipTracker(store ports.Repository, notifier ports.NotificationService) *MembershipTracker {
+	return &MembershipTracker{
+		storage:  store,
+		notifier: notifier,
+	}
+}
+
+// Update stores the current member list of guildName and notifies every
+// Discord guild tracking it about the difference. The first list seen for a
+// guild is stored without announcements.
+func (t *MembershipTracker) Update(ctx context.Context, guildName string, members []string, guilds []domain.GuildConfig) {
+	if len(members) == 0 {
+		return
+	}
+
+	stored, err := t.storage.GetGuildMemberNames(ctx, guildName)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load stored guild members", "guild", guildName, "error", err)
+		return
+	}
+
+	change := diffMembers(guildName, stored, members)
+	if len(change.Joined) > 0 {
+		if err := t.storage.AddGuildMembers(ctx, guildName, change.Joined); err != nil {
+			slog.ErrorContext(ctx, "Failed to store guild joins", "guild", guildName, "error", err)
+			return
+		}
+	}
+	if len(change.Left) > 0 {
+		if err := t.storage.RemoveGuildMembers(ctx, guildName, change.Left); err != nil {
+			slog.ErrorContext(ctx, "Failed to store guild departures", "guild", guildName, "error", err)
+			return
+		}
+	}
+
+	if len(stored) == 0 || (len(change.Joined) == 0 && len(change.Left) == 0) {
+		return
+	}
+
+	slog.InfoContext(ctx, "Guild membership changed", "guild", guildName, "joined", len(change.Joined), "left", len(change.Left))
+	for _, guild := range guilds {
+		if guild.IsMuted(time.Now()) || !slices.Contains(guild.TibiaGuilds, guildName) {
+			continue
+		}
+		if err := t.notifier.SendMembershipNotification(guild, change); err != nil {
+			slog.ErrorContext(ctx, "Failed to send membership notification", "guild_id", guild.DiscordGuildID, "error", err)
+		}
+	}
+}
+
+func diffMembers(guildName string, stored, current []string) domain.MembershipChange {
+	change := domain.MembershipChange{GuildName: guildName}
+	for _, name := range current {
+		if !slices.Contains(stored, name) {
+			change.Joined = append(change.Joined, name)
+		}
+	}
+	for _, name := range stored {
+		if !slices.Contains(current, name) {
+			change.Left = append(change.Left, name)
+		}
+	}
+	return change
+}
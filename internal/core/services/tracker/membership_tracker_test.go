@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestMembershipTracker_Update(t *testing.T) {
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "guild-1", TibiaGuilds: []string{"Red Rose"}},
+		{DiscordGuildID: "guild-2", TibiaGuilds: []string{"Other"}},
+	}
+
+	t.Run("first sync stores members without announcing", func(t *testing.T) {
+		var added []string
+		storage := &mockServiceStorage{
+			addGuildMembersFunc: func(ctx context.Context, guildName string, names []string) error {
+				added = names
+				return nil
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendMemberFunc: func(guildID string, change domain.MembershipChange) error {
+				t.Error("expected no announcement on first sync")
+				return nil
+			},
+		}
+
+		NewMembershipTracker(storage, notifier).Update(context.Background(), "Red Rose", []string{"A", "B"}, guilds)
+
+		if !slices.Equal(added, []string{"A", "B"}) {
+			t.Errorf("expected members stored, got %v", added)
+		}
+	})
+
+	t.Run("announces joins and departures to tracking guilds", func(t *testing.T) {
+		var removed []string
+		storage := &mockServiceStorage{
+			getGuildMemberNamesFunc: func(ctx context.Context, guildName string) ([]string, error) {
+				return []string{"A", "B"}, nil
+			},
+			removeGuildMembersFunc: func(ctx context.Context, guildName string, names []string) error {
+				removed = names
+				return nil
+			},
+		}
+		var notified []string
+		var got domain.MembershipChange
+		notifier := &mockServiceNotifier{
+			sendMemberFunc: func(guildID string, change domain.MembershipChange) error {
+				notified = append(notified, guildID)
+				got = change
+				return nil
+			},
+		}
+
+		NewMembershipTracker(storage, notifier).Update(context.Background(), "Red Rose", []string{"B", "C"}, guilds)
+
+		if !slices.Equal(removed, []string{"A"}) {
+			t.Errorf("expected A removed, got %v", removed)
+		}
+		if !slices.Equal(notified, []string{"guild-1"}) {
+			t.Errorf("expected only guild-1 notified, got %v", notified)
+		}
+		if got.GuildName != "Red Rose" || !slices.Equal(got.Joined, []string{"C"}) || !slices.Equal(got.Left, []string{"A"}) {
+			t.Errorf("unexpected change: %+v", got)
+		}
+	})
+
+	t.Run("muted guild is not notified", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			getGuildMemberNamesFunc: func(ctx context.Context, guildName string) ([]string, error) {
+				return []string{"A"}, nil
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendMemberFunc: func(guildID string, change domain.MembershipChange) error {
+				t.Error("expected no announcement while muted")
+				return nil
+			},
+		}
+		muted := []domain.GuildConfig{{DiscordGuildID: "guild-1", TibiaGuilds: []string{"Red Rose"}, MutedUntil: time.Now().Add(time.Hour)}}
+
+		NewMembershipTracker(storage, notifier).Update(context.Background(), "Red Rose", []string{"B"}, muted)
+	})
+
+	t.Run("empty member list is ignored", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			getGuildMemberNamesFunc: func(ctx context.Context, guildName string) ([]string, error) {
+				t.Error("expected no lookup for empty member list")
+				return nil, nil
+			},
+		}
+
+		NewMembershipTracker(storage, &mockServiceNotifier{}).Update(context.Background(), "Red Rose", nil, guilds)
+	})
+}
@@ -4,16 +4,41 @@ import (
 	"context"
 	"time"
 
+	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
 )
 
 type mockServiceStorage struct {
-	getAllGuildConfigsFunc func(ctx context.Context) ([]domain.GuildConfig, error)
-	getPlayersLevelsFunc   func(ctx context.Context, world string) (map[string]int, error)
-	batchTouchPlayersFunc  func(ctx context.Context, names []string) error
-	upsertPlayerLevelFunc  func(ctx context.Context, name string, level int, world string) error
-	deleteOldPlayersFunc   func(ctx context.Context, world string, threshold time.Duration) (int64, error)
-	getOfflinePlayersFunc  func(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error)
+	getAllGuildConfigsFunc             func(ctx context.Context) ([]domain.GuildConfig, error)
+	getPlayersLevelsFunc               func(ctx context.Context, world string) (map[string]int, error)
+	batchTouchPlayersFunc              func(ctx context.Context, names []string) error
+	upsertPlayerLevelFunc              func(ctx context.Context, name string, level int, world string) error
+	deleteOldPlayersFunc               func(ctx context.Context, world string, threshold time.Duration) (int64, error)
+	getOfflinePlayersFunc              func(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error)
+	recordDeathFunc                    func(ctx context.Context, name, world string, kill domain.Kill) error
+	countDeathsSinceFunc               func(ctx context.Context, name string, since time.Time) (int, error)
+	getGuildMemberNamesFunc            func(ctx context.Context, guildName string) ([]string, error)
+	addGuildMembersFunc                func(ctx context.Context, guildName string, names []string) error
+	removeGuildMembersFunc             func(ctx context.Context, guildName string, names []string) error
+	batchUpsertPlayerLevelsFunc        func(ctx context.Context, levels []domain.PlayerLevel) error
+	deleteRemovedGuildConfigsFunc      func(ctx context.Context, removedBefore time.Time) (int64, error)
+	deleteDeathsBeforeFunc             func(ctx context.Context, diedBefore time.Time) (int64, error)
+	recordLevelUpFunc                  func(ctx context.Context, levelUp domain.LevelUp) error
+	getLevelUpsSinceFunc               func(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error)
+	deleteLevelUpsBeforeFunc           func(ctx context.Context, reachedBefore time.Time) (int64, error)
+	renamePlayerFunc                   func(ctx context.Context, oldName, newName string) error
+	getGuildMemberCacheFunc            func(ctx context.Context) ([]domain.GuildMemberList, error)
+	saveGuildMemberCacheFunc           func(ctx context.Context, list domain.GuildMemberList) error
+	deleteGuildMemberCacheFunc         func(ctx context.Context, keep []string) (int64, error)
+	deleteStoppedGuildConfigsFunc      func(ctx context.Context, stoppedBefore time.Time) (int64, error)
+	recordOnlinePresenceFunc           func(ctx context.Context, world string, names []string, at time.Time) error
+	deleteOnlinePresenceBeforeFunc     func(ctx context.Context, seenBefore time.Time) (int64, error)
+	recordOnlineCountFunc              func(ctx context.Context, world string, players int, at time.Time) error
+	recordOnlineSessionsFunc           func(ctx context.Context, world string, names []string, at, openSince time.Time) error
+	downsampleOnlineCountsFunc         func(ctx context.Context, sampledBefore time.Time) (int64, error)
+	deleteHourlyOnlineCountsBeforeFunc func(ctx context.Context, hourBefore time.Time) (int64, error)
+	deleteOnlineSessionsBeforeFunc     func(ctx context.Context, lastSeenBefore time.Time) (int64, error)
 }
 
 func (m *mockServiceStorage) GetAllGuildConfigs(ctx context.Context) ([]domain.GuildConfig, error) {
@@ -69,14 +94,362 @@ func (m *mockServiceStorage) GetOfflinePlayers(ctx context.Context, world string
 	}
 	return nil, nil
 }
+func (m *mockServiceStorage) RenamePlayer(ctx context.Context, oldName, newName string) error {
+	if m.renamePlayerFunc != nil {
+		return m.renamePlayerFunc(ctx, oldName, newName)
+	}
+	return nil
+}
+func (m *mockServiceStorage) SetGuildLanguage(ctx context.Context, guildID, language string) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildChannel(ctx context.Context, guildID string, kind domain.NotificationChannel, channelID string) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildPingRole(ctx context.Context, guildID, roleID string, minLevel int) error {
+	return nil
+}
+func (m *mockServiceStorage) RecordDeath(ctx context.Context, name, world string, kill domain.Kill) error {
+	if m.recordDeathFunc != nil {
+		return m.recordDeathFunc(ctx, name, world, kill)
+	}
+	return nil
+}
+func (m *mockServiceStorage) CountDeathsSince(ctx context.Context, name string, since time.Time) (int, error) {
+	if m.countDeathsSinceFunc != nil {
+		return m.countDeathsSinceFunc(ctx, name, since)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) GetDeathCountsSince(ctx context.Context, world string, since time.Time) ([]domain.DeathCount, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) SetGuildPollInterval(ctx context.Context, guildID string, interval time.Duration) error {
+	return nil
+}
+func (m *mockServiceStorage) EnqueueFailedNotification(ctx context.Context, n domain.FailedNotification) error {
+	return nil
+}
+func (m *mockServiceStorage) GetDueFailedNotifications(ctx context.Context, due time.Time, limit int) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) GetGuildFailedNotifications(ctx context.Context, guildID string) ([]domain.FailedNotification, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) RescheduleFailedNotification(ctx context.Context, id int64, nextAttempt time.Time, lastErr string) error {
+	return nil
+}
+func (m *mockServiceStorage) DeleteFailedNotification(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *mockServiceStorage) DeleteExpiredFailedNotifications(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockServiceStorage) SetGuildMutedUntil(ctx context.Context, guildID string, until time.Time) error {
+	return nil
+}
+func (m *mockServiceStorage) GetGuildMemberNames(ctx context.Context, guildName string) ([]string, error) {
+	if m.getGuildMemberNamesFunc != nil {
+		return m.getGuildMemberNamesFunc(ctx, guildName)
+	}
+	return nil, nil
+}
+func (m *mockServiceStorage) AddGuildMembers(ctx context.Context, guildName string, names []string) error {
+	if m.addGuildMembersFunc != nil {
+		return m.addGuildMembersFunc(ctx, guildName, names)
+	}
+	return nil
+}
+func (m *mockServiceStorage) RemoveGuildMembers(ctx context.Context, guildName string, names []string) error {
+	if m.removeGuildMembersFunc != nil {
+		return m.removeGuildMembersFunc(ctx, guildName, names)
+	}
+	return nil
+}
+func (m *mockServiceStorage) BatchUpsertPlayerLevels(ctx context.Context, levels []domain.PlayerLevel) error {
+	if m.batchUpsertPlayerLevelsFunc != nil {
+		return m.batchUpsertPlayerLevelsFunc(ctx, levels)
+	}
+	return nil
+}
+func (m *mockServiceStorage) AddIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+func (m *mockServiceStorage) RemoveIgnoredPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildLowLevelDeaths(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildTimezone(ctx context.Context, guildID, timezone string) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildTemplate(ctx context.Context, guildID string, kind domain.NotificationChannel, template string) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildEmoji(ctx context.Context, guildID string, kind domain.NotificationChannel, emoji string, react bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetDeathRoute(ctx context.Context, guildID string, minLevel int, channelID string) error {
+	return nil
+}
+func (m *mockServiceStorage) DeleteDeathRoute(ctx context.Context, guildID string, minLevel int) (bool, error) {
+	return false, nil
+}
+func (m *mockServiceStorage) SetGuildQuietHours(ctx context.Context, guildID string, quiet domain.QuietHours) error {
+	return nil
+}
+func (m *mockServiceStorage) GetDeathsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.DeathRecord, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) GetLevelUpsPage(ctx context.Context, world string, guildNames []string, since time.Time, afterID int64, limit int) ([]domain.LevelUpRecord, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) SetGuildLastNotified(ctx context.Context, guildID string, at time.Time) error {
+	return nil
+}
+func (m *mockServiceStorage) MarkGuildRemoved(ctx context.Context, guildID string, at time.Time) error {
+	return nil
+}
+
+func (m *mockServiceStorage) RestoreGuildConfig(ctx context.Context, guildID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockServiceStorage) DeleteRemovedGuildConfigs(ctx context.Context, removedBefore time.Time) (int64, error) {
+	if m.deleteRemovedGuildConfigsFunc != nil {
+		return m.deleteRemovedGuildConfigsFunc(ctx, removedBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) PurgeGuildData(ctx context.Context, guildID string) (domain.PurgeResult, error) {
+	return domain.PurgeResult{}, nil
+}
+
+func (m *mockServiceStorage) DeleteDeathsBefore(ctx context.Context, diedBefore time.Time) (int64, error) {
+	if m.deleteDeathsBeforeFunc != nil {
+		return m.deleteDeathsBeforeFunc(ctx, diedBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) GetTopKillersSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.KillerCount, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) RecordLevelUp(ctx context.Context, levelUp domain.LevelUp) error {
+	if m.recordLevelUpFunc != nil {
+		return m.recordLevelUpFunc(ctx, levelUp)
+	}
+	return nil
+}
+
+func (m *mockServiceStorage) GetLevelUpsSince(ctx context.Context, name string, since time.Time) ([]domain.LevelUp, error) {
+	if m.getLevelUpsSinceFunc != nil {
+		return m.getLevelUpsSinceFunc(ctx, name, since)
+	}
+	return nil, nil
+}
+
+func (m *mockServiceStorage) GetDailyLevelGains(ctx context.Context, name string, since time.Time) ([]domain.DailyLevelGain, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) DeleteLevelUpsBefore(ctx context.Context, reachedBefore time.Time) (int64, error) {
+	if m.deleteLevelUpsBeforeFunc != nil {
+		return m.deleteLevelUpsBeforeFunc(ctx, reachedBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) GetHouseAuctions(ctx context.Context, world string, seenSince time.Time) ([]domain.HouseAuction, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) ReplaceHouseAuctions(ctx context.Context, world string, auctions []domain.HouseAuction) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildQuota(ctx context.Context, guildID string, quota domain.Quota) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildPremium(ctx context.Context, guildID string, premium bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildShareRange(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockServiceStorage) AddWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+
+func (m *mockServiceStorage) RemoveWatchedPlayer(ctx context.Context, guildID, name string) error {
+	return nil
+}
+
+func (m *mockServiceStorage) SetSkillThreshold(ctx context.Context, guildID string, skill domain.Skill, minValue int) error {
+	return nil
+}
+
+func (m *mockServiceStorage) DeleteSkillThreshold(ctx context.Context, guildID string, skill domain.Skill) (bool, error) {
+	return false, nil
+}
+
+func (m *mockServiceStorage) GetPlayerSkills(ctx context.Context, world string, skill domain.Skill) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) SavePlayerSkills(ctx context.Context, world string, skill domain.Skill, values map[string]int) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildMassDeathAlert(ctx context.Context, guildID string, alert domain.MassDeathAlert) error {
+	return nil
+}
+func (m *mockServiceStorage) GetGuildMemberCache(ctx context.Context) ([]domain.GuildMemberList, error) {
+	if m.getGuildMemberCacheFunc != nil {
+		return m.getGuildMemberCacheFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockServiceStorage) SaveGuildMemberCache(ctx context.Context, list domain.GuildMemberList) error {
+	if m.saveGuildMemberCacheFunc != nil {
+		return m.saveGuildMemberCacheFunc(ctx, list)
+	}
+	return nil
+}
+
+func (m *mockServiceStorage) DeleteGuildMemberCacheExcept(ctx context.Context, keep []string) (int64, error) {
+	if m.deleteGuildMemberCacheFunc != nil {
+		return m.deleteGuildMemberCacheFunc(ctx, keep)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) SetGuildBroadcastOptOut(ctx context.Context, guildID string, optOut bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildMinLevel(ctx context.Context, guildID string, level int) error {
+	return nil
+}
+func (m *mockServiceStorage) GetPlayersByPrefix(ctx context.Context, world, prefix string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockServiceStorage) BeginTx(ctx context.Context) (ports.RepositoryTx, error) {
+	return mockServiceStorageTx{m}, nil
+}
+func (m *mockServiceStorage) SaveStoppedGuildConfig(ctx context.Context, stopped domain.StoppedGuildConfig) error {
+	return nil
+}
+
+func (m *mockServiceStorage) GetStoppedGuildConfig(ctx context.Context, guildID string) (*domain.StoppedGuildConfig, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) DeleteStoppedGuildConfig(ctx context.Context, guildID string) error {
+	return nil
+}
+
+func (m *mockServiceStorage) DeleteStoppedGuildConfigs(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+	if m.deleteStoppedGuildConfigsFunc != nil {
+		return m.deleteStoppedGuildConfigsFunc(ctx, stoppedBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) RecordOnlinePresence(ctx context.Context, world string, names []string, at time.Time) error {
+	if m.recordOnlinePresenceFunc != nil {
+		return m.recordOnlinePresenceFunc(ctx, world, names, at)
+	}
+	return nil
+}
+
+func (m *mockServiceStorage) GetOnlineCountsSince(ctx context.Context, world string, guildNames []string, since time.Time) ([]domain.OnlineCount, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) DeleteOnlinePresenceBefore(ctx context.Context, seenBefore time.Time) (int64, error) {
+	if m.deleteOnlinePresenceBeforeFunc != nil {
+		return m.deleteOnlinePresenceBeforeFunc(ctx, seenBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) RecordOnlineCount(ctx context.Context, world string, players int, at time.Time) error {
+	if m.recordOnlineCountFunc != nil {
+		return m.recordOnlineCountFunc(ctx, world, players, at)
+	}
+	return nil
+}
+
+func (m *mockServiceStorage) GetWorldOnlineCountsSince(ctx context.Context, world string, since time.Time) ([]domain.OnlineCount, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) DownsampleOnlineCounts(ctx context.Context, sampledBefore time.Time) (int64, error) {
+	if m.downsampleOnlineCountsFunc != nil {
+		return m.downsampleOnlineCountsFunc(ctx, sampledBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockServiceStorage) DeleteHourlyOnlineCountsBefore(ctx context.Context, hourBefore time.Time) (int64, error) {
+	if m.deleteHourlyOnlineCountsBeforeFunc != nil {
+		return m.deleteHourlyOnlineCountsBeforeFunc(ctx, hourBefore)
+	}
+	return 0, nil
+}
+
+func (m *mockServiceStorage) RecordOnlineSessions(ctx context.Context, world string, names []string, at, openSince time.Time) error {
+	if m.recordOnlineSessionsFunc != nil {
+		return m.recordOnlineSessionsFunc(ctx, world, names, at, openSince)
+	}
+	return nil
+}
+
+func (m *mockServiceStorage) GetOnlineTimeSince(ctx context.Context, name string, since time.Time) (time.Duration, error) {
+	return 0, nil
+}
+
+func (m *mockServiceStorage) DeleteOnlineSessionsBefore(ctx context.Context, lastSeenBefore time.Time) (int64, error) {
+	if m.deleteOnlineSessionsBeforeFunc != nil {
+		return m.deleteOnlineSessionsBeforeFunc(ctx, lastSeenBefore)
+	}
+	return 0, nil
+}
+func (m *mockServiceStorage) SetGuildDeathLocation(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildLevelDowns(ctx context.Context, guildID string, enabled bool) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildResponseVisibility(ctx context.Context, guildID string, visibility domain.ResponseVisibility) error {
+	return nil
+}
+func (m *mockServiceStorage) SetGuildDeathVerbosity(ctx context.Context, guildID string, verbosity domain.DeathVerbosity) error {
+	return nil
+}
+func (m *mockServiceStorage) GetGuildWar(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+	return nil, nil
+}
+
+func (m *mockServiceStorage) StartGuildWar(ctx context.Context, guildName string, startedAt time.Time) error {
+	return nil
+}
+
+func (m *mockServiceStorage) EndGuildWar(ctx context.Context, guildName string, endedAt time.Time) error {
+	return nil
+}
+
+func (m *mockServiceStorage) CountKillsBetween(ctx context.Context, world string, victims, killers []string, since, until time.Time) (int, error) {
+	return 0, nil
+}
+func (m *mockServiceStorage) GetFragCountsSince(ctx context.Context, world string, guildNames []string, name string, since time.Time) ([]domain.FragCount, error) {
+	return nil, nil
+}
 func (m *mockServiceStorage) Close() {}
 
 type mockServiceFetcher struct {
 	fetchWorldFunc             func(ctx context.Context, world string) ([]domain.Player, error)
 	fetchCharacterDetailsFunc  func(ctx context.Context, names []string) (chan *domain.Player, error)
 	fetchWorldFromTibiaComFunc func(ctx context.Context, world string) (map[string]int, error)
+	fetchOnlinePlayersFunc     func(ctx context.Context, source, world string) (*domain.OnlineList, error)
 	fetchGuildMembersFunc      func(ctx context.Context, name string) ([]string, error)
 	fetchCharacterFunc         func(ctx context.Context, name string) (*domain.Player, error)
+	fetchWorldPvPTypeFunc      func(ctx context.Context, world string) (string, error)
 }
 
 func (m *mockServiceFetcher) FetchGuildMembers(ctx context.Context, name string) ([]string, error) {
@@ -86,6 +459,10 @@ func (m *mockServiceFetcher) FetchGuildMembers(ctx context.Context, name string)
 	return nil, nil
 }
 
+func (m *mockServiceFetcher) FetchGuild(ctx context.Context, name string) (*domain.Guild, error) {
+	return nil, nil
+}
+
 func (m *mockServiceFetcher) FetchWorld(ctx context.Context, world string) ([]domain.Player, error) {
 	if m.fetchWorldFunc != nil {
 		return m.fetchWorldFunc(ctx, world)
@@ -100,6 +477,31 @@ func (m *mockServiceFetcher) FetchWorldFromTibiaCom(ctx context.Context, world s
 	return make(map[string]int), nil
 }
 
+// FetchOnlinePlayers maps each source to its world call without any
+// fallback classification; tests of the source walk set
+// fetchOnlinePlayersFunc instead.
+func (m *mockServiceFetcher) FetchOnlinePlayers(ctx context.Context, source, world string) (*domain.OnlineList, error) {
+	if m.fetchOnlinePlayersFunc != nil {
+		return m.fetchOnlinePlayersFunc(ctx, source, world)
+	}
+	if source == config.LevelSourceTibiaCom {
+		levels, err := m.FetchWorldFromTibiaCom(ctx, world)
+		if err != nil {
+			return nil, err
+		}
+		list := &domain.OnlineList{Source: source, Live: true}
+		for name, level := range levels {
+			list.Players = append(list.Players, domain.Player{Name: name, Level: level})
+		}
+		return list, nil
+	}
+	players, err := m.FetchWorld(ctx, world)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OnlineList{Source: source, Players: players}, nil
+}
+
 func (m *mockServiceFetcher) FetchCharacterDetails(ctx context.Context, names []string) (chan *domain.Player, error) {
 	if m.fetchCharacterDetailsFunc != nil {
 		return m.fetchCharacterDetailsFunc(ctx, names)
@@ -116,25 +518,127 @@ func (m *mockServiceFetcher) FetchCharacter(ctx context.Context, name string) (*
 	return nil, nil
 }
 
+func (m *mockServiceFetcher) FetchWorldGuilds(ctx context.Context, world string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockServiceFetcher) FetchHouseAuctions(ctx context.Context, world string) ([]domain.HouseAuction, error) {
+	return nil, nil
+}
+
+func (m *mockServiceFetcher) FetchSkillHighscores(ctx context.Context, world string, skill domain.Skill, page int) (*domain.HighscorePage, error) {
+	return nil, nil
+}
+
+func (m *mockServiceFetcher) FetchWorldPvPType(ctx context.Context, world string) (string, error) {
+	if m.fetchWorldPvPTypeFunc != nil {
+		return m.fetchWorldPvPTypeFunc(ctx, world)
+	}
+	return "", nil
+}
+
+func (m *mockServiceFetcher) FetchBoosted(ctx context.Context) (*domain.Boosted, error) {
+	return nil, nil
+}
+
 type mockServiceNotifier struct {
-	sendLevelUpFunc func(guildID string, levelUp domain.LevelUp) error
-	sendDeathFunc   func(guildID string, playerName string, kill domain.Kill) error
+	sendLevelUpFunc   func(guildID string, levelUp domain.LevelUp) error
+	sendLevelDownFunc func(guildID string, levelDown domain.LevelDown) error
+	sendDeathFunc     func(guildID string, playerName string, kill domain.Kill) error
+	sendStreakFunc    func(guildID string, playerName string, deaths int) error
+	sendMemberFunc    func(guildID string, change domain.MembershipChange) error
+	sendChangeFunc    func(guildID string, change domain.CharacterChange) error
+	sendMassFunc      func(guildID string, massDeath domain.MassDeath) error
 }
 
-func (m *mockServiceNotifier) SendLevelUpNotification(guildID string, levelUp domain.LevelUp) error {
+func (m *mockServiceNotifier) SendLevelUpNotification(guild domain.GuildConfig, levelUp domain.LevelUp) error {
 	if m.sendLevelUpFunc != nil {
-		return m.sendLevelUpFunc(guildID, levelUp)
+		return m.sendLevelUpFunc(guild.DiscordGuildID, levelUp)
 	}
 	return nil
 }
 
-func (m *mockServiceNotifier) SendDeathNotification(guildID string, playerName string, kill domain.Kill) error {
+func (m *mockServiceNotifier) SendLevelDownNotification(guild domain.GuildConfig, levelDown domain.LevelDown) error {
+	if m.sendLevelDownFunc != nil {
+		return m.sendLevelDownFunc(guild.DiscordGuildID, levelDown)
+	}
+	return nil
+}
+
+func (m *mockServiceNotifier) SendDeathNotification(guild domain.GuildConfig, player domain.Player, kill domain.Kill) error {
 	if m.sendDeathFunc != nil {
-		return m.sendDeathFunc(guildID, playerName, kill)
+		return m.sendDeathFunc(guild.DiscordGuildID, player.Name, kill)
+	}
+	return nil
+}
+
+func (m *mockServiceNotifier) SendDeathStreakNotification(guild domain.GuildConfig, playerName string, deaths int) error {
+	if m.sendStreakFunc != nil {
+		return m.sendStreakFunc(guild.DiscordGuildID, playerName, deaths)
 	}
 	return nil
 }
 
+func (m *mockServiceNotifier) SendMassDeathNotification(guild domain.GuildConfig, massDeath domain.MassDeath) error {
+	if m.sendMassFunc != nil {
+		return m.sendMassFunc(guild.DiscordGuildID, massDeath)
+	}
+	return nil
+}
+
+func (m *mockServiceNotifier) SendGuildWarNotification(guild domain.GuildConfig, war domain.GuildWar) error {
+	return nil
+}
+
+func (m *mockServiceNotifier) SendMembershipNotification(guild domain.GuildConfig, change domain.MembershipChange) error {
+	if m.sendMemberFunc != nil {
+		return m.sendMemberFunc(guild.DiscordGuildID, change)
+	}
+	return nil
+}
+
+func (m *mockServiceNotifier) SendHouseAuctionNotification(guild domain.GuildConfig, auction domain.HouseAuction, ended bool) error {
+	return nil
+}
+
+func (m *mockServiceNotifier) SendSkillAdvanceNotification(guild domain.GuildConfig, advance domain.SkillAdvance) error {
+	return nil
+}
+
+func (m *mockServiceNotifier) SendCharacterChangeNotification(guild domain.GuildConfig, change domain.CharacterChange) error {
+	if m.sendChangeFunc != nil {
+		return m.sendChangeFunc(guild.DiscordGuildID, change)
+	}
+	return nil
+}
+
+func (m *mockServiceNotifier) SendRashidNotification(guild domain.GuildConfig, city string) error {
+	return nil
+}
+
+func (m *mockServiceNotifier) SendBoostedNotification(guild domain.GuildConfig, boosted domain.Boosted) error {
+	return nil
+}
+
+func (m *mockServiceNotifier) SendCatchUpNotification(guild domain.GuildConfig, catchUp domain.CatchUp) error {
+	return nil
+}
+
 func (m *mockServiceNotifier) SendGenericMessage(guildID, channelName, message string) error {
 	return nil
 }
+
+type mockLeader struct {
+	leader bool
+}
+
+func (m *mockLeader) IsLeader(ctx context.Context) bool { return m.leader }
+func (m *mockLeader) Release(ctx context.Context)       {}
+
+// mockServiceStorageTx runs a transaction's calls against the mock itself.
+type mockServiceStorageTx struct {
+	*mockServiceStorage
+}
+
+func (mockServiceStorageTx) Commit(ctx context.Context) error   { return nil }
+func (mockServiceStorageTx) Rollback(ctx context.Context) error { return nil }
@@ -2,22 +2,58 @@ package tracker
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"runtime/debug"
 	"time"
 
+	"death-level-tracker/internal/adapters/metrics"
 	"death-level-tracker/internal/core/domain"
 )
 
+// processWorldSafely recovers from a panic in processWorld, e.g. on a
+// malformed payload, so the other worlds and later cycles keep running.
+func (s *Service) processWorldSafely(ctx context.Context, world string, guilds []domain.GuildConfig) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.TrackerWorldPanics.WithLabelValues(world).Inc()
+			slog.ErrorContext(ctx, "World processing panicked", "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	s.processWorld(ctx, world, guilds)
+}
+
 func (s *Service) processWorld(ctx context.Context, world string, guilds []domain.GuildConfig) {
 	wctx := s.initWorldContext(ctx, world, guilds)
 	if wctx == nil {
 		return
 	}
-	slog.Info("Processing world", "world", world)
-	onlineNames := s.processOnlinePlayers(ctx, wctx)
+	slog.InfoContext(ctx, "Processing world")
+	onlineNames, err := s.processOnlinePlayers(ctx, wctx)
+	if errors.Is(err, domain.ErrWorldMaintenance) {
+		slog.InfoContext(ctx, "World is under maintenance, backing off", "retry_in", maintenanceBackoff)
+		s.deferWorld(world, time.Now().Add(maintenanceBackoff))
+		return
+	}
+	if errors.Is(err, domain.ErrRateLimited) {
+		slog.WarnContext(ctx, "Rate limited, backing off", "retry_in", rateLimitBackoff)
+		s.deferWorld(world, time.Now().Add(rateLimitBackoff))
+		return
+	}
+	// Online players the deadline cut off would pass for logged out, so the
+	// offline pass waits for a cycle that finishes the online one.
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		metrics.TrackerWorldTimeouts.WithLabelValues(world).Inc()
+		slog.WarnContext(ctx, "World cycle ran out of time, skipping offline players", "timeout", s.config.WorldCycleTimeout)
+		return
+	}
 	s.performMaintenance(ctx, world, onlineNames)
 	s.processOfflinePlayers(ctx, wctx, onlineNames)
-	slog.Info("Finished processing world", "world", world)
+	if wctx.quiet {
+		s.markReconciled(world)
+		slog.InfoContext(ctx, "Reconciled levels without announcing level ups")
+	}
+	slog.InfoContext(ctx, "Finished processing world")
 }
 
 func (s *Service) initWorldContext(ctx context.Context, world string, guilds []domain.GuildConfig) *worldContext {
@@ -26,12 +62,46 @@ func (s *Service) initWorldContext(ctx context.Context, world string, guilds []d
 		return nil
 	}
 
+	memberships := s.fetchGuildMemberships(ctx, guilds)
 	return &worldContext{
-		world:       world,
-		guilds:      guilds,
-		dbLevels:    dbLevels,
-		memberships: s.fetchGuildMemberships(ctx, guilds),
+		world:           world,
+		pvpType:         s.worldPvPType(ctx, world),
+		guilds:          guilds,
+		dbLevels:        dbLevels,
+		memberships:     memberships,
+		lowLevelMembers: lowLevelMembers(guilds, memberships),
+		quiet:           s.config.QuietFirstCycle && !s.isReconciled(world),
+		announced:       make(levelUpSet),
+	}
+}
+
+// worldPvPType looks up world's PvP type for its notifications. The
+// fetcher keeps it once known, so this costs a request only until then. A
+// failed lookup is logged and leaves it out of this cycle's notifications.
+func (s *Service) worldPvPType(ctx context.Context, world string) string {
+	pvpType, err := s.fetcher.FetchWorldPvPType(ctx, world)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to fetch world PvP type", "error", err)
+		return ""
+	}
+	return pvpType
+}
+
+// lowLevelMembers collects the members of Tibia guilds tracked by Discord
+// guilds that announce low-level deaths.
+func lowLevelMembers(guilds []domain.GuildConfig, memberships map[string]map[string]bool) map[string]bool {
+	members := make(map[string]bool)
+	for _, guild := range guilds {
+		if !guild.LowLevelDeaths {
+			continue
+		}
+		for _, tibiaGuild := range guild.TibiaGuilds {
+			for name := range memberships[tibiaGuild] {
+				members[name] = true
+			}
+		}
 	}
+	return members
 }
 
 func (s *Service) fetchGuildMemberships(ctx context.Context, guilds []domain.GuildConfig) map[string]map[string]bool {
@@ -44,7 +114,7 @@ func (s *Service) fetchGuildMemberships(ctx context.Context, guilds []domain.Gui
 
 	memberships := make(map[string]map[string]bool)
 	for guildName := range uniqueGuilds {
-		members := s.getGuildMembers(ctx, guildName)
+		members := s.getGuildMembers(ctx, guildName, guilds)
 		if members == nil {
 			continue
 		}
@@ -59,97 +129,196 @@ func (s *Service) fetchGuildMemberships(ctx context.Context, guilds []domain.Gui
 	return memberships
 }
 
-func (s *Service) getGuildMembers(ctx context.Context, guildName string) []string {
-	s.cacheMu.RLock()
-	item, cached := s.guildCache[guildName]
-	s.cacheMu.RUnlock()
-
-	now := time.Now()
-	if cached && now.Before(item.ExpiresAt) {
-		return item.Members
+// processOnlinePlayers returns the names of tracked online players, asking
+// the configured level sources in order until one answers. A source only
+// hands over when its failure wraps domain.ErrSourceUnavailable. Its errors
+// are domain.ErrWorldMaintenance and domain.ErrRateLimited, after which the
+// world should be left alone for a while; other failures are logged and
+// degrade to fewer players.
+func (s *Service) processOnlinePlayers(ctx context.Context, wctx *worldContext) ([]string, error) {
+	list, err := s.fetchOnlinePlayers(ctx, wctx.world)
+	if errors.Is(err, domain.ErrWorldMaintenance) || errors.Is(err, domain.ErrRateLimited) {
+		return nil, err
 	}
-
-	members, err := s.fetcher.FetchGuildMembers(ctx, guildName)
 	if err != nil {
-		slog.Warn("Failed to fetch guild members", "guild", guildName, "error", err)
-		if cached {
-			slog.Info("Using stale cache for guild", "guild", guildName)
-			return item.Members
-		}
-		return nil
+		slog.ErrorContext(ctx, "Failed to fetch online players", "error", err)
+		return nil, nil
 	}
 
-	s.cacheMu.Lock()
-	s.guildCache[guildName] = GuildCacheItem{
-		Members:   members,
-		ExpiresAt: now.Add(15 * time.Minute),
+	slog.InfoContext(ctx, "Processing online players", "source", list.Source, "count", len(list.Players))
+	s.recordPresence(ctx, wctx, list.Players)
+	if list.Live {
+		return s.processLiveLevels(ctx, list.Players, wctx), nil
 	}
-	s.cacheMu.Unlock()
-
-	return members
+	return s.processCharacters(ctx, list.Players, wctx), nil
 }
 
-func (s *Service) processOnlinePlayers(ctx context.Context, wctx *worldContext) []string {
-	if s.config.UseTibiaComForLevels {
-		slog.Info("Processing online players via tibia.com", "world", wctx.world)
-		return s.processViaTibiaCom(ctx, wctx)
+// sessionGapCycles is how many poll intervals a character may go unseen
+// before its online session ends, so one failed cycle does not split it.
+const sessionGapCycles = 3
+
+// recordPresence keeps who is online this hour and how many are online this
+// cycle for /activity, and extends the characters' online sessions, whatever
+// their level.
+func (s *Service) recordPresence(ctx context.Context, wctx *worldContext, players []domain.Player) {
+	now := time.Now()
+	names := playerNames(players)
+	if err := s.storage.RecordOnlinePresence(ctx, wctx.world, names, now); err != nil {
+		slog.ErrorContext(ctx, "Failed to record online presence", "error", err)
+	}
+	if err := s.storage.RecordOnlineCount(ctx, wctx.world, len(players), now); err != nil {
+		slog.ErrorContext(ctx, "Failed to record online count", "error", err)
+	}
+	openSince := now.Add(-sessionGapCycles * s.pollInterval(wctx.world, wctx.guilds))
+	if err := s.storage.RecordOnlineSessions(ctx, wctx.world, names, now, openSince); err != nil {
+		slog.ErrorContext(ctx, "Failed to record online sessions", "error", err)
 	}
-	slog.Info("Processing online players via TibiaData", "world", wctx.world)
-	return s.processViaTibiaData(ctx, wctx)
 }
 
-func (s *Service) processViaTibiaCom(ctx context.Context, wctx *worldContext) []string {
-	levels, err := s.fetcher.FetchWorldFromTibiaCom(ctx, wctx.world)
-	if err != nil {
-		slog.Warn("Failed to fetch from tibia.com, falling back to TibiaData", "world", wctx.world, "error", err)
-		return s.processViaTibiaData(ctx, wctx)
+func (s *Service) fetchOnlinePlayers(ctx context.Context, world string) (*domain.OnlineList, error) {
+	var err error
+	for i, source := range s.config.LevelSources {
+		if i > 0 {
+			slog.WarnContext(ctx, "Level source failed, trying the next one", "source", source, "error", err)
+		}
+		var list *domain.OnlineList
+		list, err = s.fetcher.FetchOnlinePlayers(ctx, source, world)
+		if err == nil {
+			return list, nil
+		}
+		if !errors.Is(err, domain.ErrSourceUnavailable) {
+			return nil, err
+		}
+	}
+	if err == nil {
+		err = errors.New("no level sources configured")
 	}
+	return nil, err
+}
 
-	onlineNames := extractNames(levels)
-	slog.Info("Extracted online players", "world", wctx.world, "count", len(onlineNames))
+// processLiveLevels handles a list whose levels are current: level ups come
+// straight from it and only deaths need each character page.
+func (s *Service) processLiveLevels(ctx context.Context, players []domain.Player, wctx *worldContext) []string {
+	onlineNames := playerNames(players)
 
-	s.processLevelsFromTibiaCom(ctx, levels, wctx)
-	s.performMaintenance(ctx, wctx.world, onlineNames)
-	s.processDeathsForOnlinePlayers(ctx, levelsToPlayers(levels), wctx)
+	s.processLevelsFromTibiaCom(ctx, playerLevels(players), wctx)
+	s.processDeathsForOnlinePlayers(ctx, players, wctx)
 
-	slog.Info("Finished processing online players", "world", wctx.world, "count", len(onlineNames))
+	slog.InfoContext(ctx, "Finished processing online players", "count", len(onlineNames))
 	return onlineNames
 }
 
-func (s *Service) processViaTibiaData(ctx context.Context, wctx *worldContext) []string {
-	players, err := s.fetcher.FetchWorld(ctx, wctx.world)
-	if err != nil {
-		return nil
-	}
-
-	return s.processCharacters(ctx, players, wctx)
-}
-
 func (s *Service) processCharacters(ctx context.Context, players []domain.Player, wctx *worldContext) []string {
-	filteredNames := s.filterByMinLevel(players)
+	filteredNames := s.filterByMinLevel(players, wctx)
 
-	results, err := s.fetcher.FetchCharacterDetails(ctx, filteredNames)
+	results, err := s.fetchDetails(ctx, filteredNames, wctx)
 	if err != nil {
-		slog.Error("Failed to fetch character details", "error", err)
+		slog.ErrorContext(ctx, "Failed to fetch character details", "error", err)
 		return nil
 	}
 
 	var onlineNames []string
 	for char := range results {
+		if s.checkCharacterChange(ctx, char, wctx) {
+			continue
+		}
+		char.WorldPvPType = wctx.pvpType
 		if char.Level < s.config.MinLevelTrack {
+			if wctx.lowLevelMembers[char.Name] {
+				s.checkDeaths(ctx, char, wctx)
+			}
 			continue
 		}
-		s.deathTracker.CheckDeaths(char, wctx.guilds, wctx.memberships)
-		s.levelTracker.CheckLevelUp(ctx, char.Name, char.Level, char.World, wctx.dbLevels, wctx.guilds, wctx.memberships)
+		s.checkDeaths(ctx, char, wctx)
+		s.checkLevelUp(ctx, char, wctx)
 		onlineNames = append(onlineNames, char.Name)
 	}
 	return onlineNames
 }
 
-func (s *Service) filterByMinLevel(players []domain.Player) []string {
+// fetchDetails fetches the characters among names not yet requested this
+// cycle. Each character fetched is checked for both deaths and level ups by
+// whichever pass asked for it, so a cycle costs at most one TibiaData call
+// per character.
+func (s *Service) fetchDetails(ctx context.Context, names []string, wctx *worldContext) (chan *domain.Player, error) {
+	if wctx.requested == nil {
+		wctx.requested = make(map[string]bool, len(names))
+	}
+	fresh := make([]string, 0, len(names))
+	for _, name := range names {
+		if !wctx.requested[name] {
+			wctx.requested[name] = true
+			fresh = append(fresh, name)
+		}
+	}
+	if skipped := len(names) - len(fresh); skipped > 0 {
+		slog.DebugContext(ctx, "Skipping characters already fetched this cycle", "count", skipped)
+	}
+	return s.fetcher.FetchCharacterDetails(ctx, fresh)
+}
+
+func (s *Service) checkLevelUp(ctx context.Context, char *domain.Player, wctx *worldContext) {
+	if wctx.quiet {
+		s.levelTracker.Reconcile(ctx, char, wctx.dbLevels)
+		return
+	}
+	s.levelTracker.CheckLevelDown(ctx, char, wctx.dbLevels, wctx.guilds, wctx.memberships)
+	s.levelTracker.CheckLevelUp(ctx, char, wctx.dbLevels, guildsAtLevel(wctx.guilds, char.Level), wctx.memberships, wctx.announced)
+}
+
+// checkCharacterChange reports whether char moved to another world and should
+// not be processed for this one.
+func (s *Service) checkCharacterChange(ctx context.Context, char *domain.Player, wctx *worldContext) bool {
+	return s.charTracker.Check(ctx, char, wctx.world, wctx.dbLevels, wctx.guilds, wctx.memberships)
+}
+
+func (s *Service) checkDeaths(ctx context.Context, char *domain.Player, wctx *worldContext) {
+	var guilds []domain.GuildConfig
+	if char.Level >= s.config.MinLevelTrack {
+		guilds = guildsAtLevel(wctx.guilds, char.Level)
+	}
+	if len(guilds) < len(wctx.guilds) {
+		guilds = append(guilds, lowLevelGuilds(char, s.config.MinLevelTrack, wctx)...)
+	}
+	newDeaths := s.deathTracker.CheckDeaths(ctx, char, guilds, wctx.memberships)
+	s.streakTracker.RecordDeaths(ctx, char.Name, wctx.world, newDeaths, guilds, wctx.memberships)
+	s.massTracker.RecordDeaths(ctx, char.Name, wctx.world, newDeaths, guilds, wctx.memberships)
+}
+
+// guildsAtLevel returns the guilds whose own minimum level admits level.
+func guildsAtLevel(guilds []domain.GuildConfig, level int) []domain.GuildConfig {
+	var result []domain.GuildConfig
+	for _, guild := range guilds {
+		if guild.Announces(level) {
+			result = append(result, guild)
+		}
+	}
+	return result
+}
+
+// lowLevelGuilds returns the guilds that want deaths of char although it is
+// below minLevel or their own minimum level: those announcing low-level
+// deaths of a Tibia guild it belongs to.
+func lowLevelGuilds(char *domain.Player, minLevel int, wctx *worldContext) []domain.GuildConfig {
+	var guilds []domain.GuildConfig
+	for _, guild := range wctx.guilds {
+		if !guild.LowLevelDeaths || (char.Level >= minLevel && guild.Announces(char.Level)) {
+			continue
+		}
+		for _, tibiaGuild := range guild.TibiaGuilds {
+			if wctx.memberships[tibiaGuild][char.Name] {
+				guilds = append(guilds, guild)
+				break
+			}
+		}
+	}
+	return guilds
+}
+
+func (s *Service) filterByMinLevel(players []domain.Player, wctx *worldContext) []string {
 	var names []string
 	for _, p := range players {
-		if p.Level >= s.config.MinLevelTrack {
+		if p.Level >= s.config.MinLevelTrack || wctx.lowLevelMembers[p.Name] {
 			names = append(names, p.Name)
 		}
 	}
@@ -158,9 +327,9 @@ func (s *Service) filterByMinLevel(players []domain.Player) []string {
 
 func (s *Service) processOfflinePlayers(ctx context.Context, wctx *worldContext, onlineNames []string) {
 	offlinePlayers, err := s.storage.GetOfflinePlayers(ctx, wctx.world, onlineNames)
-	slog.Info("Found offline players", "world", wctx.world, "count", len(offlinePlayers))
+	slog.InfoContext(ctx, "Found offline players", "count", len(offlinePlayers))
 	if err != nil {
-		slog.Error("Failed to get offline players", "world", wctx.world, "error", err)
+		slog.ErrorContext(ctx, "Failed to get offline players", "error", err)
 		return
 	}
 
@@ -168,109 +337,143 @@ func (s *Service) processOfflinePlayers(ctx context.Context, wctx *worldContext,
 		return
 	}
 
-	slog.Info("Checking offline players", "world", wctx.world, "count", len(offlinePlayers))
+	slog.InfoContext(ctx, "Checking offline players", "count", len(offlinePlayers))
 
 	names := playerNames(offlinePlayers)
-	results, err := s.fetcher.FetchCharacterDetails(ctx, names)
+	results, err := s.fetchDetails(ctx, names, wctx)
 	if err != nil {
-		slog.Error("Failed to fetch character details for offline players", "error", err)
+		slog.ErrorContext(ctx, "Failed to fetch character details for offline players", "error", err)
 		return
 	}
-	slog.Info("Fetched details for offline players from TibiaData", "world", wctx.world, "count", len(results))
+	slog.InfoContext(ctx, "Fetched details for offline players from TibiaData", "count", len(results))
 
 	for char := range results {
+		if s.checkCharacterChange(ctx, char, wctx) {
+			continue
+		}
+		char.WorldPvPType = wctx.pvpType
 		if char.Level < s.config.MinLevelTrack {
+			if wctx.lowLevelMembers[char.Name] {
+				s.checkDeaths(ctx, char, wctx)
+			}
 			continue
 		}
-		s.deathTracker.CheckDeaths(char, wctx.guilds, wctx.memberships)
-		s.levelTracker.CheckLevelUp(ctx, char.Name, char.Level, char.World, wctx.dbLevels, wctx.guilds, wctx.memberships)
+		s.checkDeaths(ctx, char, wctx)
+		s.checkLevelUp(ctx, char, wctx)
 	}
-	slog.Info("Finished checking offline players", "world", wctx.world, "count", len(offlinePlayers))
+	slog.InfoContext(ctx, "Finished checking offline players", "count", len(offlinePlayers))
 }
 
 func (s *Service) performMaintenance(ctx context.Context, world string, onlineNames []string) {
-	slog.Info("Performing maintenance", "world", world, "online_count", len(onlineNames))
+	slog.InfoContext(ctx, "Performing maintenance", "online_count", len(onlineNames))
 	if len(onlineNames) > 0 {
 		if err := s.storage.BatchTouchPlayers(ctx, onlineNames); err != nil {
-			slog.Error("Failed to touch players", "world", world, "error", err)
+			slog.ErrorContext(ctx, "Failed to touch players", "error", err)
 		}
 	}
 
 	deletedCount, err := s.storage.DeleteOldPlayers(ctx, world, 30*time.Minute)
 	if err != nil {
-		slog.Error("Failed to prune old players", "world", world, "error", err)
+		slog.ErrorContext(ctx, "Failed to prune old players", "error", err)
 	} else if deletedCount > 0 {
-		slog.Info("Pruned old players", "world", world, "count", deletedCount)
+		slog.InfoContext(ctx, "Pruned old players", "count", deletedCount)
 	}
 }
 
 func (s *Service) fetchPlayerLevels(ctx context.Context, world string) (map[string]int, error) {
 	dbLevels, err := s.storage.GetPlayersLevels(ctx, world)
 	if err != nil {
-		slog.Error("Failed to fetch player levels from DB", "world", world, "error", err)
+		slog.ErrorContext(ctx, "Failed to fetch player levels from DB", "error", err)
 		return nil, err
 	}
 	return dbLevels, nil
 }
 
 func (s *Service) processLevelsFromTibiaCom(ctx context.Context, levels map[string]int, wctx *worldContext) {
+	var changed []domain.PlayerLevel
+	var levelUps []domain.LevelUp
+	var levelDowns []domain.LevelDown
+	levelDownsWanted := wantsLevelDowns(wctx.guilds) && !wctx.quiet
 	for name, currentLevel := range levels {
 		if currentLevel < s.config.MinLevelTrack {
 			continue
 		}
 
 		savedLevel, exists := wctx.dbLevels[name]
+		if levelDownsWanted {
+			if from, ok := s.levelTracker.confirmLevelDrop(wctx.world, name, savedLevel, exists, currentLevel, time.Now()); ok {
+				levelDowns = append(levelDowns, domain.LevelDown{
+					PlayerName:   name,
+					OldLevel:     from,
+					NewLevel:     currentLevel,
+					World:        wctx.world,
+					WorldPvPType: wctx.pvpType,
+					DetectedAt:   time.Now(),
+				})
+			}
+		}
 
 		if !exists || savedLevel != currentLevel {
-			if err := s.storage.UpsertPlayerLevel(ctx, name, currentLevel, wctx.world); err != nil {
-				slog.Error("Failed to upsert player level", "name", name, "error", err)
-			}
+			changed = append(changed, domain.PlayerLevel{Name: name, Level: currentLevel, World: wctx.world})
 			wctx.dbLevels[name] = currentLevel
 		}
 
-		if exists && currentLevel > savedLevel {
-			slog.Info("Level up detected", "name", name, "old_level", savedLevel, "new_level", currentLevel)
-			s.levelTracker.notifyLevelUp(wctx.guilds, name, savedLevel, currentLevel, wctx.world, wctx.memberships)
+		if exists && currentLevel > savedLevel && !wctx.quiet {
+			slog.InfoContext(ctx, "Level up detected", "name", name, "old_level", savedLevel, "new_level", currentLevel)
+			levelUps = append(levelUps, domain.LevelUp{
+				PlayerName:   name,
+				OldLevel:     savedLevel,
+				NewLevel:     currentLevel,
+				World:        wctx.world,
+				WorldPvPType: wctx.pvpType,
+				ReachedAt:    time.Now(),
+			})
 		}
 	}
-	slog.Info("Finished processing players from tibia.com", "world", wctx.world, "count", len(levels))
+
+	if err := s.storage.BatchUpsertPlayerLevels(ctx, changed); err != nil {
+		slog.ErrorContext(ctx, "Failed to upsert player levels", "count", len(changed), "error", err)
+	}
+	for _, levelUp := range levelUps {
+		s.levelTracker.notifyLevelUp(ctx, guildsAtLevel(wctx.guilds, levelUp.NewLevel), levelUp, wctx.memberships, wctx.announced)
+	}
+	for _, levelDown := range levelDowns {
+		s.levelTracker.notifyLevelDown(ctx, wctx.guilds, levelDown, wctx.memberships)
+	}
+	slog.InfoContext(ctx, "Finished processing listed levels", "count", len(levels))
 }
 
 func (s *Service) processDeathsForOnlinePlayers(ctx context.Context, players []domain.Player, wctx *worldContext) {
-	filteredNames := s.filterByMinLevel(players)
+	filteredNames := s.filterByMinLevel(players, wctx)
 	if len(filteredNames) == 0 {
 		return
 	}
 
-	slog.Info("Processing deaths for online players", "world", wctx.world, "count", len(filteredNames))
-	results, err := s.fetcher.FetchCharacterDetails(ctx, filteredNames)
-	slog.Info("Fetched details for online players from TibiaData", "world", wctx.world, "count", len(results))
+	slog.InfoContext(ctx, "Processing deaths for online players", "count", len(filteredNames))
+	results, err := s.fetchDetails(ctx, filteredNames, wctx)
+	slog.InfoContext(ctx, "Fetched details for online players from TibiaData", "count", len(results))
 	if err != nil {
-		slog.Error("Failed to fetch character details for deaths", "error", err)
+		slog.ErrorContext(ctx, "Failed to fetch character details for deaths", "error", err)
 		return
 	}
 
-	slog.Info("Checking deaths for online players", "world", wctx.world, "count", len(results))
+	slog.InfoContext(ctx, "Checking deaths for online players", "count", len(results))
 	for char := range results {
-		s.deathTracker.CheckDeaths(char, wctx.guilds, wctx.memberships)
-	}
-	slog.Info("Finished checking deaths for online players", "world", wctx.world, "count", len(results))
-}
-
-func extractNames(levels map[string]int) []string {
-	names := make([]string, 0, len(levels))
-	for name := range levels {
-		names = append(names, name)
+		if s.checkCharacterChange(ctx, char, wctx) {
+			continue
+		}
+		char.WorldPvPType = wctx.pvpType
+		s.checkDeaths(ctx, char, wctx)
 	}
-	return names
+	slog.InfoContext(ctx, "Finished checking deaths for online players", "count", len(results))
 }
 
-func levelsToPlayers(levels map[string]int) []domain.Player {
-	players := make([]domain.Player, 0, len(levels))
-	for name, level := range levels {
-		players = append(players, domain.Player{Name: name, Level: level})
+func playerLevels(players []domain.Player) map[string]int {
+	levels := make(map[string]int, len(players))
+	for _, p := range players {
+		levels[p.Name] = p.Level
 	}
-	return players
+	return levels
 }
 
 func playerNames(players []domain.Player) []string {
@@ -3,6 +3,9 @@ package tracker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -23,6 +26,9 @@ func makeService(storage *mockServiceStorage, fetcher *mockServiceFetcher, notif
 	if cfg == nil {
 		cfg = &config.Config{MinLevelTrack: 100}
 	}
+	if len(cfg.LevelSources) == 0 {
+		cfg.LevelSources = []string{config.LevelSourceTibiaData}
+	}
 	if storage == nil {
 		storage = &mockServiceStorage{}
 	}
@@ -34,12 +40,15 @@ func makeService(storage *mockServiceStorage, fetcher *mockServiceFetcher, notif
 	}
 
 	return &Service{
-		config:       cfg,
-		storage:      storage,
-		fetcher:      fetcher,
-		levelTracker: NewLevelTracker(cfg, storage, notifier),
-		deathTracker: NewDeathTracker(notifier),
-		guildCache:   make(map[string]GuildCacheItem),
+		config:        cfg,
+		storage:       storage,
+		fetcher:       fetcher,
+		levelTracker:  NewLevelTracker(cfg, storage, notifier),
+		deathTracker:  NewDeathTracker(notifier),
+		streakTracker: NewStreakTracker(storage, notifier),
+		massTracker:   NewMassDeathTracker(notifier),
+		memberTracker: NewMembershipTracker(storage, notifier),
+		guildCache:    make(map[string]GuildCacheItem),
 	}
 }
 
@@ -155,7 +164,7 @@ func TestInitWorldContext(t *testing.T) {
 			},
 		}
 		fetcher := &mockServiceFetcher{}
-		service := &Service{storage: storage, fetcher: fetcher}
+		service := &Service{config: &config.Config{}, storage: storage, fetcher: fetcher}
 		wctx := service.initWorldContext(context.Background(), "Antica", nil)
 		if wctx == nil {
 			t.Fatal("expected non-nil")
@@ -174,6 +183,35 @@ func TestInitWorldContext(t *testing.T) {
 			t.Error("expected nil on error")
 		}
 	})
+
+	t.Run("pvp type", func(t *testing.T) {
+		fetcher := &mockServiceFetcher{
+			fetchWorldPvPTypeFunc: func(ctx context.Context, world string) (string, error) {
+				return "Open PvP", nil
+			},
+		}
+		service := &Service{config: &config.Config{}, storage: &mockServiceStorage{}, fetcher: fetcher}
+		wctx := service.initWorldContext(context.Background(), "Antica", nil)
+		if wctx == nil || wctx.pvpType != "Open PvP" {
+			t.Errorf("expected Open PvP, got %+v", wctx)
+		}
+	})
+
+	t.Run("pvp type lookup fails", func(t *testing.T) {
+		fetcher := &mockServiceFetcher{
+			fetchWorldPvPTypeFunc: func(ctx context.Context, world string) (string, error) {
+				return "", domain.ErrUpstreamDown
+			},
+		}
+		service := &Service{config: &config.Config{}, storage: &mockServiceStorage{}, fetcher: fetcher}
+		wctx := service.initWorldContext(context.Background(), "Antica", nil)
+		if wctx == nil {
+			t.Fatal("expected the world to be processed without its PvP type")
+		}
+		if wctx.pvpType != "" {
+			t.Errorf("expected no PvP type, got %q", wctx.pvpType)
+		}
+	})
 }
 
 func TestProcessCharacters(t *testing.T) {
@@ -222,6 +260,110 @@ func TestProcessCharacters(t *testing.T) {
 	})
 }
 
+func TestProcessCharacters_LowLevelMembers(t *testing.T) {
+	fetcher := &mockServiceFetcher{
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			ch := make(chan *domain.Player, len(names))
+			for _, n := range names {
+				ch <- &domain.Player{Name: n, Level: 30, World: "Antica", Deaths: []domain.Kill{{Time: time.Now()}}}
+			}
+			close(ch)
+			return ch, nil
+		},
+	}
+	storage := &mockServiceStorage{
+		upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+			t.Errorf("expected no level tracking below min level, got %s", name)
+			return nil
+		},
+	}
+	var notified []string
+	notifier := &mockServiceNotifier{
+		sendDeathFunc: func(guildID string, playerName string, kill domain.Kill) error {
+			notified = append(notified, guildID+":"+playerName)
+			return nil
+		},
+	}
+
+	service := makeService(storage, fetcher, notifier, &config.Config{MinLevelTrack: 100})
+	service.deathTracker.startTime = time.Now().Add(-time.Minute)
+
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "opted-in", TibiaGuilds: []string{"Red Rose"}, LowLevelDeaths: true},
+		{DiscordGuildID: "opted-out", TibiaGuilds: []string{"Red Rose"}},
+		{DiscordGuildID: "everyone", LowLevelDeaths: true},
+	}
+	memberships := map[string]map[string]bool{"Red Rose": {"Member": true}}
+	wctx := &worldContext{
+		world:           "Antica",
+		guilds:          guilds,
+		dbLevels:        map[string]int{},
+		memberships:     memberships,
+		lowLevelMembers: lowLevelMembers(guilds, memberships),
+	}
+
+	players := []domain.Player{{Name: "Member", Level: 30}, {Name: "Stranger", Level: 30}}
+	names := service.processCharacters(context.Background(), players, wctx)
+
+	if len(names) != 0 {
+		t.Errorf("expected low-level members not to count as tracked online players, got %v", names)
+	}
+	if len(notified) != 1 || notified[0] != "opted-in:Member" {
+		t.Errorf("expected only opted-in guild to hear about Member, got %v", notified)
+	}
+}
+
+func TestProcessCharacters_GuildMinLevel(t *testing.T) {
+	fetcher := &mockServiceFetcher{
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			ch := make(chan *domain.Player, len(names))
+			for _, n := range names {
+				ch <- &domain.Player{Name: n, Level: 201, World: "Antica", Deaths: []domain.Kill{{Time: time.Now()}}}
+			}
+			close(ch)
+			return ch, nil
+		},
+	}
+	var deaths, levelUps []string
+	notifier := &mockServiceNotifier{
+		sendDeathFunc: func(guildID string, playerName string, kill domain.Kill) error {
+			deaths = append(deaths, guildID+":"+playerName)
+			return nil
+		},
+		sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+			levelUps = append(levelUps, guildID+":"+levelUp.PlayerName)
+			return nil
+		},
+	}
+
+	service := makeService(nil, fetcher, notifier, &config.Config{MinLevelTrack: 100})
+	service.deathTracker.startTime = time.Now().Add(-time.Minute)
+
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "default"},
+		{DiscordGuildID: "high", MinLevel: 300},
+		{DiscordGuildID: "high-members", MinLevel: 300, TibiaGuilds: []string{"Red Rose"}, LowLevelDeaths: true},
+	}
+	memberships := map[string]map[string]bool{"Red Rose": {"Member": true}}
+	wctx := &worldContext{
+		world:       "Antica",
+		guilds:      guilds,
+		dbLevels:    map[string]int{"Member": 200, "Stranger": 200},
+		memberships: memberships,
+	}
+
+	service.processCharacters(context.Background(), []domain.Player{{Name: "Member", Level: 201}, {Name: "Stranger", Level: 201}}, wctx)
+
+	slices.Sort(deaths)
+	if want := []string{"default:Member", "default:Stranger", "high-members:Member"}; !slices.Equal(deaths, want) {
+		t.Errorf("expected deaths %v, got %v", want, deaths)
+	}
+	slices.Sort(levelUps)
+	if want := []string{"default:Member", "default:Stranger"}; !slices.Equal(levelUps, want) {
+		t.Errorf("expected level ups %v, got %v", want, levelUps)
+	}
+}
+
 func TestProcessOfflinePlayers(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var upserted bool
@@ -375,6 +517,53 @@ func TestProcessWorld(t *testing.T) {
 		service.processWorld(context.Background(), "Antica", []domain.GuildConfig{})
 	})
 
+	t.Run("first cycle is quiet", func(t *testing.T) {
+		var levelUps int
+		storage := &mockServiceStorage{
+			getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+				return map[string]int{"P1": 100}, nil
+			},
+			getOfflinePlayersFunc: func(ctx context.Context, world string, online []string) ([]domain.Player, error) {
+				return nil, nil
+			},
+			deleteOldPlayersFunc: func(ctx context.Context, world string, d time.Duration) (int64, error) {
+				return 0, nil
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+				levelUps++
+				return nil
+			},
+		}
+		fetcher := &mockServiceFetcher{
+			fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+				return []domain.Player{{Name: "P1", Level: 150}}, nil
+			},
+			fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+				ch := make(chan *domain.Player, 1)
+				ch <- &domain.Player{Name: "P1", Level: 150, World: "Antica"}
+				close(ch)
+				return ch, nil
+			},
+		}
+		service := makeService(storage, fetcher, notifier, &config.Config{MinLevelTrack: 100, QuietFirstCycle: true})
+		guilds := []domain.GuildConfig{{DiscordGuildID: "G1"}}
+
+		service.processWorld(context.Background(), "Antica", guilds)
+		if levelUps != 0 {
+			t.Fatalf("expected no level ups on the first cycle, got %d", levelUps)
+		}
+		if !service.isReconciled("Antica") {
+			t.Fatal("expected world to be reconciled")
+		}
+
+		service.processWorld(context.Background(), "Antica", guilds)
+		if levelUps != 1 {
+			t.Errorf("expected level up after reconciliation, got %d", levelUps)
+		}
+	})
+
 	t.Run("init fail", func(t *testing.T) {
 		storage := &mockServiceStorage{
 			getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
@@ -390,24 +579,29 @@ func TestProcessWorld(t *testing.T) {
 func TestFilterByMinLevel(t *testing.T) {
 	service := &Service{config: &config.Config{MinLevelTrack: 100}}
 	players := []domain.Player{{Name: "Low", Level: 50}, {Name: "High", Level: 200}}
-	names := service.filterByMinLevel(players)
+	names := service.filterByMinLevel(players, makeWorldContext("Antica"))
 	if len(names) != 1 || names[0] != "High" {
 		t.Errorf("got %v", names)
 	}
 }
 
-func TestHelperFunctions(t *testing.T) {
-	t.Run("extractNames", func(t *testing.T) {
-		names := extractNames(map[string]int{"A": 1, "B": 2})
-		if len(names) != 2 {
-			t.Errorf("expected 2, got %d", len(names))
-		}
-	})
+func TestFilterByMinLevel_LowLevelMembers(t *testing.T) {
+	service := &Service{config: &config.Config{MinLevelTrack: 100}}
+	wctx := makeWorldContext("Antica")
+	wctx.lowLevelMembers = map[string]bool{"Member": true}
+
+	players := []domain.Player{{Name: "Low", Level: 50}, {Name: "Member", Level: 20}}
+	names := service.filterByMinLevel(players, wctx)
+	if len(names) != 1 || names[0] != "Member" {
+		t.Errorf("got %v", names)
+	}
+}
 
-	t.Run("levelsToPlayers", func(t *testing.T) {
-		players := levelsToPlayers(map[string]int{"A": 100})
-		if len(players) != 1 {
-			t.Errorf("expected 1, got %d", len(players))
+func TestHelperFunctions(t *testing.T) {
+	t.Run("playerLevels", func(t *testing.T) {
+		levels := playerLevels([]domain.Player{{Name: "A", Level: 100}})
+		if len(levels) != 1 || levels["A"] != 100 {
+			t.Errorf("got %v", levels)
 		}
 	})
 
@@ -423,8 +617,8 @@ func TestProcessLevelsFromTibiaCom(t *testing.T) {
 	t.Run("upserts", func(t *testing.T) {
 		var upserted bool
 		storage := &mockServiceStorage{
-			upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
-				upserted = true
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+				upserted = len(levels) == 1 && levels[0] == domain.PlayerLevel{Name: "P1", Level: 200, World: "Antica"}
 				return nil
 			},
 		}
@@ -436,6 +630,27 @@ func TestProcessLevelsFromTibiaCom(t *testing.T) {
 		}
 	})
 
+	t.Run("single batch for unchanged and changed levels", func(t *testing.T) {
+		var calls int
+		var got []domain.PlayerLevel
+		storage := &mockServiceStorage{
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+				calls++
+				got = levels
+				return nil
+			},
+		}
+		service := makeService(storage, nil, nil, &config.Config{MinLevelTrack: 100})
+		wctx := &worldContext{world: "Antica", dbLevels: map[string]int{"Same": 300, "Up": 200}}
+		service.processLevelsFromTibiaCom(context.Background(), map[string]int{"Same": 300, "Up": 201, "New": 150}, wctx)
+		if calls != 1 {
+			t.Fatalf("expected 1 batch call, got %d", calls)
+		}
+		if len(got) != 2 {
+			t.Errorf("expected only changed levels, got %v", got)
+		}
+	})
+
 	t.Run("level up", func(t *testing.T) {
 		var notified bool
 		notifier := &mockServiceNotifier{
@@ -446,7 +661,7 @@ func TestProcessLevelsFromTibiaCom(t *testing.T) {
 		}
 
 		storage := &mockServiceStorage{
-			upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
 				return nil
 			},
 		}
@@ -463,9 +678,69 @@ func TestProcessLevelsFromTibiaCom(t *testing.T) {
 		}
 	})
 
+	t.Run("quiet cycle stores levels without announcing", func(t *testing.T) {
+		var upserted int
+		notifier := &mockServiceNotifier{
+			sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+				t.Error("quiet cycle should not announce level ups")
+				return nil
+			},
+		}
+		storage := &mockServiceStorage{
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+				upserted = len(levels)
+				return nil
+			},
+			recordLevelUpFunc: func(ctx context.Context, levelUp domain.LevelUp) error {
+				t.Error("quiet cycle should not record level ups")
+				return nil
+			},
+		}
+		wctx := &worldContext{
+			world:    "Antica",
+			dbLevels: map[string]int{"P1": 100},
+			guilds:   []domain.GuildConfig{{DiscordGuildID: "G1"}},
+			quiet:    true,
+		}
+		service := makeService(storage, nil, notifier, &config.Config{MinLevelTrack: 100})
+		service.processLevelsFromTibiaCom(context.Background(), map[string]int{"P1": 180}, wctx)
+		if upserted != 1 {
+			t.Errorf("expected 1 level stored, got %d", upserted)
+		}
+	})
+
+	t.Run("level down confirmed on the next list", func(t *testing.T) {
+		var sent []domain.LevelDown
+		notifier := &mockServiceNotifier{
+			sendLevelDownFunc: func(guildID string, levelDown domain.LevelDown) error {
+				sent = append(sent, levelDown)
+				return nil
+			},
+		}
+		storage := &mockServiceStorage{
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+				return nil
+			},
+		}
+		wctx := &worldContext{
+			world:    "Antica",
+			dbLevels: map[string]int{"P1": 300},
+			guilds:   []domain.GuildConfig{{DiscordGuildID: "G1", LevelDowns: true}},
+		}
+		service := makeService(storage, nil, notifier, &config.Config{MinLevelTrack: 100})
+		service.processLevelsFromTibiaCom(context.Background(), map[string]int{"P1": 290}, wctx)
+		if len(sent) != 0 {
+			t.Fatalf("expected the first sighting to wait, got %+v", sent)
+		}
+		service.processLevelsFromTibiaCom(context.Background(), map[string]int{"P1": 290}, wctx)
+		if len(sent) != 1 || sent[0].OldLevel != 300 || sent[0].NewLevel != 290 {
+			t.Errorf("expected a level down from 300 to 290, got %+v", sent)
+		}
+	})
+
 	t.Run("upsert error", func(t *testing.T) {
 		storage := &mockServiceStorage{
-			upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
 				return errors.New("db error")
 			},
 		}
@@ -483,7 +758,7 @@ func TestProcessDeathsForOnlinePlayers(t *testing.T) {
 				ch := make(chan *domain.Player, 1)
 				// Create a death that is recent (after boot time)
 				recentDeath := domain.Kill{Time: time.Now()}
-				ch <- &domain.Player{Name: "P1", Deaths: []domain.Kill{recentDeath}}
+				ch <- &domain.Player{Name: "P1", Level: 200, Deaths: []domain.Kill{recentDeath}}
 				close(ch)
 				return ch, nil
 			},
@@ -523,73 +798,276 @@ func TestProcessDeathsForOnlinePlayers(t *testing.T) {
 	})
 }
 
-func TestProcessViaTibiaCom(t *testing.T) {
-	t.Run("fallback on error", func(t *testing.T) {
-		var tibiaDataCalled bool
-		fetcher := &mockServiceFetcher{
-			fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
-				return nil, errors.New("error")
-			},
-			fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
-				tibiaDataCalled = true
-				return []domain.Player{}, nil
-			},
-			fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
-				ch := make(chan *domain.Player)
-				close(ch)
-				return ch, nil
+func TestProcessOnlinePlayers(t *testing.T) {
+	emptyDetails := func(ctx context.Context, names []string) (chan *domain.Player, error) {
+		ch := make(chan *domain.Player)
+		close(ch)
+		return ch, nil
+	}
+
+	tests := []struct {
+		name      string
+		sources   []string
+		errs      map[string]error
+		wantAsked []string
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "first source answers",
+			sources:   []string{"tibiacom", "tibiadata"},
+			wantAsked: []string{"tibiacom"},
+			wantNames: []string{"Alice"},
+		},
+		{
+			name:      "unavailable source hands over",
+			sources:   []string{"tibiacom", "tibiadata"},
+			errs:      map[string]error{"tibiacom": fmt.Errorf("%w: %w", domain.ErrSourceUnavailable, domain.ErrRateLimited)},
+			wantAsked: []string{"tibiacom", "tibiadata"},
+		},
+		{
+			name:      "other failures stop the walk",
+			sources:   []string{"tibiadata", "tibiacom"},
+			errs:      map[string]error{"tibiadata": domain.ErrRateLimited},
+			wantAsked: []string{"tibiadata"},
+			wantErr:   domain.ErrRateLimited,
+		},
+		{
+			name:      "maintenance stops the walk",
+			sources:   []string{"tibiacom", "tibiadata"},
+			errs:      map[string]error{"tibiacom": domain.ErrWorldMaintenance},
+			wantAsked: []string{"tibiacom"},
+			wantErr:   domain.ErrWorldMaintenance,
+		},
+		{
+			name:    "every source unavailable",
+			sources: []string{"tibiadata", "tibiacom"},
+			errs: map[string]error{
+				"tibiadata": fmt.Errorf("%w: %w", domain.ErrSourceUnavailable, domain.ErrUpstreamDown),
+				"tibiacom":  fmt.Errorf("%w: %w", domain.ErrSourceUnavailable, domain.ErrRateLimited),
 			},
-		}
-		service := makeService(nil, fetcher, nil, &config.Config{MinLevelTrack: 100})
-		service.processViaTibiaCom(context.Background(), makeWorldContext("Antica"))
-		if !tibiaDataCalled {
-			t.Error("expected fallback")
-		}
-	})
+			wantAsked: []string{"tibiadata", "tibiacom"},
+			wantErr:   domain.ErrRateLimited,
+		},
+		{
+			name:      "unclassified failure gives up quietly",
+			sources:   []string{"tibiadata", "tibiacom"},
+			errs:      map[string]error{"tibiadata": errors.New("boom")},
+			wantAsked: []string{"tibiadata"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked []string
+			fetcher := &mockServiceFetcher{
+				fetchOnlinePlayersFunc: func(ctx context.Context, source, world string) (*domain.OnlineList, error) {
+					asked = append(asked, source)
+					if err := tt.errs[source]; err != nil {
+						return nil, err
+					}
+					// A live list skips the character pages, so its names
+					// come back even though the details are empty.
+					return &domain.OnlineList{Source: source, Players: []domain.Player{{Name: "Alice", Level: 150}}, Live: source == "tibiacom"}, nil
+				},
+				fetchCharacterDetailsFunc: emptyDetails,
+			}
+			service := makeService(nil, fetcher, nil, &config.Config{LevelSources: tt.sources, MinLevelTrack: 100})
+
+			names, err := service.processOnlinePlayers(context.Background(), makeWorldContext("Antica"))
+
+			if fmt.Sprint(asked) != fmt.Sprint(tt.wantAsked) {
+				t.Errorf("expected sources %v to be asked, got %v", tt.wantAsked, asked)
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("expected names %v, got %v", tt.wantNames, names)
+			}
+		})
+	}
 }
 
-func TestProcessOnlinePlayers(t *testing.T) {
-	t.Run("uses TibiaCom", func(t *testing.T) {
-		var called bool
-		fetcher := &mockServiceFetcher{
-			fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
-				called = true
-				return map[string]int{}, nil
-			},
-			fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
-				ch := make(chan *domain.Player)
+func TestProcessOnlinePlayers_LiveListSkipsLevelPages(t *testing.T) {
+	var detailsFor []string
+	fetcher := &mockServiceFetcher{
+		fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return map[string]int{"Alice": 150}, nil
+		},
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			detailsFor = names
+			ch := make(chan *domain.Player)
+			close(ch)
+			return ch, nil
+		},
+	}
+	storage := &mockServiceStorage{}
+	service := makeService(storage, fetcher, nil, &config.Config{LevelSources: []string{"tibiacom"}, MinLevelTrack: 100})
+	wctx := makeWorldContext("Antica")
+
+	names, err := service.processOnlinePlayers(context.Background(), wctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Alice" {
+		t.Errorf("expected Alice online, got %v", names)
+	}
+	if wctx.dbLevels["Alice"] != 150 {
+		t.Errorf("expected level taken from the live list, got %d", wctx.dbLevels["Alice"])
+	}
+	if len(detailsFor) != 1 {
+		t.Errorf("expected character pages only for deaths, got %v", detailsFor)
+	}
+}
+
+func TestProcessOnlinePlayers_RecordsPresence(t *testing.T) {
+	fetcher := &mockServiceFetcher{
+		fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return map[string]int{"Alice": 150, "Bob": 20}, nil
+		},
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			ch := make(chan *domain.Player)
+			close(ch)
+			return ch, nil
+		},
+	}
+	var world string
+	var names, sessionNames []string
+	var players int
+	var seenAt, openSince time.Time
+	storage := &mockServiceStorage{
+		recordOnlinePresenceFunc: func(ctx context.Context, w string, n []string, at time.Time) error {
+			world, names = w, n
+			return nil
+		},
+		recordOnlineCountFunc: func(ctx context.Context, w string, p int, at time.Time) error {
+			players = p
+			return nil
+		},
+		recordOnlineSessionsFunc: func(ctx context.Context, w string, n []string, at, since time.Time) error {
+			sessionNames, seenAt, openSince = n, at, since
+			return nil
+		},
+	}
+	cfg := &config.Config{LevelSources: []string{"tibiacom"}, MinLevelTrack: 100, TrackerInterval: 5 * time.Minute}
+	service := makeService(storage, fetcher, nil, cfg)
+
+	if _, err := service.processOnlinePlayers(context.Background(), makeWorldContext("Antica")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slices.Sort(names)
+	if world != "Antica" || !slices.Equal(names, []string{"Alice", "Bob"}) {
+		t.Errorf("expected everyone online on Antica recorded, whatever their level, got %v on %q", names, world)
+	}
+	if players != 2 {
+		t.Errorf("expected an online count of 2, got %d", players)
+	}
+	if len(sessionNames) != 2 {
+		t.Errorf("expected sessions of both characters extended, got %v", sessionNames)
+	}
+	if gap := seenAt.Sub(openSince); gap != sessionGapCycles*5*time.Minute {
+		t.Errorf("expected sessions to stay open for %d poll intervals, got %v", sessionGapCycles, gap)
+	}
+}
+
+func TestProcessWorld_MaintenanceBacksOff(t *testing.T) {
+	storage := &mockServiceStorage{
+		getOfflinePlayersFunc: func(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error) {
+			t.Error("expected offline players not to be checked during maintenance")
+			return nil, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return nil, domain.ErrWorldMaintenance
+		},
+	}
+	cfg := &config.Config{LevelSources: []string{config.LevelSourceTibiaCom}, MinLevelTrack: 100, TrackerInterval: time.Minute}
+	service := makeService(storage, fetcher, nil, cfg)
+
+	service.processWorld(context.Background(), "Antica", []domain.GuildConfig{{World: "Antica"}})
+
+	if service.claimWorld("Antica", time.Minute, time.Now().Add(2*time.Minute)) {
+		t.Error("expected world to be deferred after maintenance")
+	}
+	if !service.claimWorld("Antica", time.Minute, time.Now().Add(maintenanceBackoff+time.Second)) {
+		t.Error("expected world to be claimable after the backoff")
+	}
+}
+
+func TestProcessWorld_CycleDeadline(t *testing.T) {
+	storage := &mockServiceStorage{
+		getOfflinePlayersFunc: func(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error) {
+			t.Error("expected offline players not to be checked after the deadline")
+			return nil, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+			return []domain.Player{{Name: "Slow", Level: 200}}, nil
+		},
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			ch := make(chan *domain.Player)
+			go func() {
+				<-ctx.Done()
 				close(ch)
-				return ch, nil
-			},
-		}
-		service := makeService(nil, fetcher, nil, &config.Config{UseTibiaComForLevels: true, MinLevelTrack: 100})
-		service.processOnlinePlayers(context.Background(), makeWorldContext("Antica"))
-		if !called {
-			t.Error("expected TibiaCom")
-		}
-	})
+			}()
+			return ch, nil
+		},
+	}
+	cfg := &config.Config{MinLevelTrack: 100, WorldCycleTimeout: 20 * time.Millisecond}
+	service := makeService(storage, fetcher, nil, cfg)
 
-	t.Run("TibiaData error", func(t *testing.T) {
-		fetcher := &mockServiceFetcher{
-			fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
-				return nil, errors.New("error")
-			},
-		}
-		service := makeService(nil, fetcher, nil, &config.Config{UseTibiaComForLevels: false, MinLevelTrack: 100})
-		// processOnlinePlayers -> processViaTibiaData
-		names := service.processOnlinePlayers(context.Background(), makeWorldContext("Antica"))
-		if names != nil {
-			t.Error("expected nil on error")
-		}
-	})
+	ctx, cancel := service.withCycleDeadline(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatal("expected the cycle context to carry a deadline")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		service.processWorld(ctx, "Antica", []domain.GuildConfig{{World: "Antica"}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the cycle to stop at its deadline")
+	}
+}
+
+func TestProcessWorld_RateLimitBacksOff(t *testing.T) {
+	storage := &mockServiceStorage{
+		getOfflinePlayersFunc: func(ctx context.Context, world string, onlineNames []string) ([]domain.Player, error) {
+			t.Error("expected offline players not to be checked while rate limited")
+			return nil, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+			return nil, domain.ErrRateLimited
+		},
+	}
+	cfg := &config.Config{MinLevelTrack: 100, TrackerInterval: time.Minute}
+	service := makeService(storage, fetcher, nil, cfg)
+
+	service.processWorld(context.Background(), "Antica", []domain.GuildConfig{{World: "Antica"}})
+
+	if service.claimWorld("Antica", time.Minute, time.Now().Add(time.Minute)) {
+		t.Error("expected world to be deferred after being rate limited")
+	}
+	if !service.claimWorld("Antica", time.Minute, time.Now().Add(rateLimitBackoff+time.Second)) {
+		t.Error("expected world to be claimable after the backoff")
+	}
 }
 
 func TestProcessLevelsFromTibiaCom_MinLevel(t *testing.T) {
 	t.Run("ignores low levels", func(t *testing.T) {
 		var upserted bool
 		storage := &mockServiceStorage{
-			upsertPlayerLevelFunc: func(ctx context.Context, name string, level int, world string) error {
-				upserted = true
+			batchUpsertPlayerLevelsFunc: func(ctx context.Context, levels []domain.PlayerLevel) error {
+				upserted = len(levels) > 0
 				return nil
 			},
 		}
@@ -601,3 +1079,120 @@ func TestProcessLevelsFromTibiaCom_MinLevel(t *testing.T) {
 		}
 	})
 }
+
+func TestProcessWorld_FetchesEachCharacterOnce(t *testing.T) {
+	requests := map[string]int{}
+	storage := &mockServiceStorage{
+		getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return map[string]int{"Alice": 150, "Bob": 150, "Carol": 150}, nil
+		},
+		getOfflinePlayersFunc: func(ctx context.Context, world string, online []string) ([]domain.Player, error) {
+			// Bob's page failed to load, so he never made it into the
+			// online names and looks offline to storage.
+			return []domain.Player{{Name: "Bob", Level: 150}, {Name: "Carol", Level: 150}}, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+			return []domain.Player{{Name: "Alice", Level: 151}, {Name: "Bob", Level: 151}}, nil
+		},
+		fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+			ch := make(chan *domain.Player, len(names))
+			for _, name := range names {
+				requests[name]++
+				if name != "Bob" {
+					ch <- &domain.Player{Name: name, Level: 151, World: "Antica"}
+				}
+			}
+			close(ch)
+			return ch, nil
+		},
+	}
+	service := makeService(storage, fetcher, nil, &config.Config{MinLevelTrack: 100})
+
+	service.processWorld(context.Background(), "Antica", []domain.GuildConfig{{DiscordGuildID: "G1"}})
+
+	want := map[string]int{"Alice": 1, "Bob": 1, "Carol": 1}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected one request per character, got %v", requests)
+	}
+}
+
+func TestProcessWorld_LiveListTouchesPlayersOnce(t *testing.T) {
+	var touches int
+	storage := &mockServiceStorage{
+		getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return map[string]int{}, nil
+		},
+		batchTouchPlayersFunc: func(ctx context.Context, names []string) error {
+			touches++
+			return nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
+			return map[string]int{"Alice": 150}, nil
+		},
+	}
+	cfg := &config.Config{LevelSources: []string{config.LevelSourceTibiaCom}, MinLevelTrack: 100}
+	service := makeService(storage, fetcher, nil, cfg)
+
+	service.processWorld(context.Background(), "Antica", []domain.GuildConfig{{DiscordGuildID: "G1"}})
+
+	if touches != 1 {
+		t.Errorf("expected online players to be touched once per cycle, got %d", touches)
+	}
+}
+
+func TestProcessWorld_PvPTypeInLevelUps(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"character pages", config.LevelSourceTibiaData},
+		{"live list", config.LevelSourceTibiaCom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockServiceStorage{
+				getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+					return map[string]int{"Alice": 150}, nil
+				},
+			}
+			fetcher := &mockServiceFetcher{
+				fetchWorldPvPTypeFunc: func(ctx context.Context, world string) (string, error) {
+					return "Retro Open PvP", nil
+				},
+				fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+					return []domain.Player{{Name: "Alice", Level: 151}}, nil
+				},
+				fetchWorldFromTibiaComFunc: func(ctx context.Context, world string) (map[string]int, error) {
+					return map[string]int{"Alice": 151}, nil
+				},
+				fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+					ch := make(chan *domain.Player, len(names))
+					for _, name := range names {
+						ch <- &domain.Player{Name: name, Level: 151, World: "Antica"}
+					}
+					close(ch)
+					return ch, nil
+				},
+			}
+			var pvpTypes []string
+			notifier := &mockServiceNotifier{
+				sendLevelUpFunc: func(guildID string, levelUp domain.LevelUp) error {
+					pvpTypes = append(pvpTypes, levelUp.WorldPvPType)
+					return nil
+				},
+			}
+			service := makeService(storage, fetcher, notifier, &config.Config{MinLevelTrack: 100, LevelSources: []string{tt.source}})
+
+			service.processWorld(context.Background(), "Antica", []domain.GuildConfig{{DiscordGuildID: "G1"}})
+
+			if want := []string{"Retro Open PvP"}; !reflect.DeepEqual(pvpTypes, want) {
+				t.Errorf("expected level ups with PvP types %v, got %v", want, pvpTypes)
+			}
+		})
+	}
+}
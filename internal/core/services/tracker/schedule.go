@@ -0,0 +1,149 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/adapters/metrics"
+	"death-level-tracker/internal/core/domain"
+)
+
+const (
+	schedulerTick = 30 * time.Second
+
+	// maintenanceBackoff is how long a world under maintenance is left alone.
+	maintenanceBackoff = 5 * time.Minute
+
+	// rateLimitBackoff is how long a world is left alone after the upstream
+	// rate limited its fetch.
+	rateLimitBackoff = 2 * time.Minute
+
+	// overrunStreak is how many consecutive cycles must take longer than the
+	// interval before ADAPTIVE_INTERVAL stretches it.
+	overrunStreak = 3
+)
+
+// inServerSaveWindow reports whether now lies within window of the daily
+// server save at 10:00 CET. A zero window disables the pause.
+func inServerSaveWindow(now time.Time, window time.Duration) bool {
+	if window <= 0 {
+		return false
+	}
+	local := now.In(domain.ServerSaveLocation)
+	save := time.Date(local.Year(), local.Month(), local.Day(), domain.ServerSaveHour, 0, 0, 0, domain.ServerSaveLocation)
+	diff := local.Sub(save)
+	return diff > -window && diff < window
+}
+
+// pollInterval resolves how often world is polled: the shortest interval set
+// by a Discord guild via /set-poll-interval, then WORLD_POLL_INTERVALS, then
+// TRACKER_INTERVAL.
+func (s *Service) pollInterval(world string, guilds []domain.GuildConfig) time.Duration {
+	var interval time.Duration
+	for _, g := range guilds {
+		if g.PollInterval > 0 && (interval == 0 || g.PollInterval < interval) {
+			interval = g.PollInterval
+		}
+	}
+	if interval > 0 {
+		return interval
+	}
+	if d, ok := s.config.WorldPollIntervals[world]; ok {
+		return d
+	}
+	return s.config.TrackerInterval
+}
+
+func (s *Service) tickInterval() time.Duration {
+	return min(s.config.TrackerInterval, schedulerTick)
+}
+
+// claimWorld marks world as running when it is due. It returns false if the
+// world ran less than interval ago or its previous cycle is still running.
+// An interval stretched by releaseWorld takes precedence when it is longer.
+func (s *Service) claimWorld(world string, interval time.Duration, now time.Time) bool {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	if s.lastRun == nil {
+		s.lastRun = make(map[string]time.Time)
+		s.running = make(map[string]bool)
+	}
+
+	if s.running[world] {
+		metrics.TrackerCyclesSkipped.WithLabelValues(world).Inc()
+		return false
+	}
+	if now.Before(s.deferredUntil[world]) {
+		return false
+	}
+	interval = max(interval, s.stretched[world])
+	if last, ok := s.lastRun[world]; ok && now.Sub(last) < interval-s.tickInterval()/2 {
+		return false
+	}
+
+	s.lastRun[world] = now
+	s.running[world] = true
+	return true
+}
+
+func (s *Service) isReconciled(world string) bool {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	return s.reconciled[world]
+}
+
+// markReconciled records that world finished its quiet first cycle, so later
+// cycles announce level ups again.
+func (s *Service) markReconciled(world string) {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	if s.reconciled == nil {
+		s.reconciled = make(map[string]bool)
+	}
+	s.reconciled[world] = true
+}
+
+// deferWorld keeps world from being claimed again before until.
+func (s *Service) deferWorld(world string, until time.Time) {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	if s.deferredUntil == nil {
+		s.deferredUntil = make(map[string]time.Time)
+	}
+	s.deferredUntil[world] = until
+}
+
+// releaseWorld ends the running cycle of world and records how long it took.
+// Once overrunStreak cycles in a row exceed interval, the next cycle waits a
+// full interval after this one finished; a cycle within interval restores it.
+func (s *Service) releaseWorld(ctx context.Context, world string, interval, elapsed time.Duration) {
+	metrics.TrackerCycleDuration.WithLabelValues(world).Observe(elapsed.Seconds())
+
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	delete(s.running, world)
+
+	if s.overruns == nil {
+		s.overruns = make(map[string]int)
+		s.stretched = make(map[string]time.Duration)
+	}
+
+	if elapsed <= interval {
+		if _, ok := s.stretched[world]; ok {
+			slog.InfoContext(ctx, "Cycle back within interval, restoring it", "interval", interval, "duration", elapsed)
+		}
+		delete(s.overruns, world)
+		delete(s.stretched, world)
+		return
+	}
+
+	s.overruns[world]++
+	slog.WarnContext(ctx, "Cycle took longer than the interval", "interval", interval, "duration", elapsed, "overruns", s.overruns[world])
+	if s.config.AdaptiveInterval && s.overruns[world] >= overrunStreak {
+		s.stretched[world] = elapsed + interval
+	}
+}
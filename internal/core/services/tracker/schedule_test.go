@@ -0,0 +1,147 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/config"
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestInServerSaveWindow(t *testing.T) {
+	save := time.Date(2024, 12, 13, domain.ServerSaveHour, 0, 0, 0, domain.ServerSaveLocation)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		window   time.Duration
+		expected bool
+	}{
+		{"disabled", save, 0, false},
+		{"at server save", save, 10 * time.Minute, true},
+		{"just before", save.Add(-9 * time.Minute), 10 * time.Minute, true},
+		{"just after", save.Add(9 * time.Minute), 10 * time.Minute, true},
+		{"outside window", save.Add(11 * time.Minute), 10 * time.Minute, false},
+		{"other timezone", save.UTC(), 10 * time.Minute, true},
+		{"evening", save.Add(10 * time.Hour), 10 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inServerSaveWindow(tt.now, tt.window); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	cfg := &config.Config{
+		TrackerInterval:    5 * time.Minute,
+		WorldPollIntervals: map[string]time.Duration{"Antica": 2 * time.Minute},
+	}
+	service := &Service{config: cfg}
+
+	t.Run("defaults to tracker interval", func(t *testing.T) {
+		if got := service.pollInterval("Secura", nil); got != 5*time.Minute {
+			t.Errorf("expected 5m, got %v", got)
+		}
+	})
+
+	t.Run("uses world override", func(t *testing.T) {
+		if got := service.pollInterval("Antica", nil); got != 2*time.Minute {
+			t.Errorf("expected 2m, got %v", got)
+		}
+	})
+
+	t.Run("guild setting wins with shortest interval", func(t *testing.T) {
+		guilds := []domain.GuildConfig{
+			{PollInterval: 0},
+			{PollInterval: 4 * time.Minute},
+			{PollInterval: 3 * time.Minute},
+		}
+		if got := service.pollInterval("Antica", guilds); got != 3*time.Minute {
+			t.Errorf("expected 3m, got %v", got)
+		}
+	})
+}
+
+func TestClaimWorld(t *testing.T) {
+	service := &Service{config: &config.Config{TrackerInterval: 5 * time.Minute}}
+	now := time.Now()
+
+	if !service.claimWorld("Antica", 5*time.Minute, now) {
+		t.Fatal("expected first claim to succeed")
+	}
+	if service.claimWorld("Antica", 5*time.Minute, now.Add(10*time.Minute)) {
+		t.Error("expected claim to fail while world is running")
+	}
+
+	service.releaseWorld(context.Background(), "Antica", 5*time.Minute, time.Minute)
+	if service.claimWorld("Antica", 5*time.Minute, now.Add(2*time.Minute)) {
+		t.Error("expected claim to fail before interval elapsed")
+	}
+	if !service.claimWorld("Antica", 5*time.Minute, now.Add(5*time.Minute)) {
+		t.Error("expected claim to succeed after interval")
+	}
+	if !service.claimWorld("Secura", 5*time.Minute, now) {
+		t.Error("expected other worlds to be independent")
+	}
+}
+
+func TestReleaseWorld_AdaptiveInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		adaptive bool
+		overruns int
+		want     time.Duration
+	}{
+		{"stretches after consecutive overruns", true, overrunStreak, 12 * time.Minute},
+		{"keeps interval below the streak", true, overrunStreak - 1, 0},
+		{"keeps interval when disabled", false, overrunStreak, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &Service{config: &config.Config{TrackerInterval: 5 * time.Minute, AdaptiveInterval: tt.adaptive}}
+			for range tt.overruns {
+				service.releaseWorld(context.Background(), "Antica", 5*time.Minute, 7*time.Minute)
+			}
+			if got := service.stretched["Antica"]; got != tt.want {
+				t.Errorf("expected stretched interval %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("stretched interval delays the next claim", func(t *testing.T) {
+		service := &Service{config: &config.Config{TrackerInterval: 5 * time.Minute, AdaptiveInterval: true}}
+		now := time.Now()
+		for range overrunStreak {
+			service.releaseWorld(context.Background(), "Antica", 5*time.Minute, 7*time.Minute)
+		}
+		if !service.claimWorld("Antica", 5*time.Minute, now) {
+			t.Fatal("expected first claim to succeed")
+		}
+		service.releaseWorld(context.Background(), "Antica", 5*time.Minute, 7*time.Minute)
+		if service.claimWorld("Antica", 5*time.Minute, now.Add(7*time.Minute)) {
+			t.Error("expected claim to wait for the stretched interval")
+		}
+		if !service.claimWorld("Antica", 5*time.Minute, now.Add(12*time.Minute)) {
+			t.Error("expected claim to succeed after the stretched interval")
+		}
+	})
+
+	t.Run("cycle within interval restores it", func(t *testing.T) {
+		service := &Service{config: &config.Config{TrackerInterval: 5 * time.Minute, AdaptiveInterval: true}}
+		for range overrunStreak {
+			service.releaseWorld(context.Background(), "Antica", 5*time.Minute, 7*time.Minute)
+		}
+		service.releaseWorld(context.Background(), "Antica", 5*time.Minute, 2*time.Minute)
+		if _, ok := service.stretched["Antica"]; ok {
+			t.Error("expected stretched interval to be cleared")
+		}
+		if service.overruns["Antica"] != 0 {
+			t.Errorf("expected overruns reset, got %d", service.overruns["Antica"])
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"death-level-tracker/internal/config"
 	"death-level-tracker/internal/core/domain"
 	"death-level-tracker/internal/core/ports"
+	"death-level-tracker/internal/logging"
 )
 
 type Dependencies struct {
@@ -16,67 +17,252 @@ type Dependencies struct {
 	Storage  ports.Repository
 	Fetcher  ports.TibiaFetcher
 	Notifier ports.NotificationService
+	// Leader gates tracking when several replicas run; nil means always lead.
+	Leader ports.LeaderElector
+	// Capabilities strips premium settings from free guilds; nil keeps them.
+	Capabilities ports.Capabilities
 }
 
 type Service struct {
-	config       *config.Config
-	storage      ports.Repository
-	fetcher      ports.TibiaFetcher
-	levelTracker *LevelTracker
-	deathTracker *DeathTracker
+	config        *config.Config
+	storage       ports.Repository
+	fetcher       ports.TibiaFetcher
+	leader        ports.LeaderElector
+	caps          ports.Capabilities
+	levelTracker  *LevelTracker
+	deathTracker  *DeathTracker
+	streakTracker *StreakTracker
+	massTracker   *MassDeathTracker
+	memberTracker *MembershipTracker
+	charTracker   *CharacterTracker
 
 	cacheMu    sync.RWMutex
 	guildCache map[string]GuildCacheItem
-}
 
-type GuildCacheItem struct {
-	Members   []string
-	ExpiresAt time.Time
+	scheduleMu    sync.Mutex
+	lastRun       map[string]time.Time
+	running       map[string]bool
+	deferredUntil map[string]time.Time
+	overruns      map[string]int
+	stretched     map[string]time.Duration
+	reconciled    map[string]bool
+
+	// cycles counts world cycles in flight so shutdown can wait for them;
+	// abortCycles cancels them when it cannot wait any longer.
+	cycles      sync.WaitGroup
+	abortCycles context.CancelFunc
 }
 
 func NewService(deps Dependencies) *Service {
 	return &Service{
-		config:       deps.Config,
-		storage:      deps.Storage,
-		fetcher:      deps.Fetcher,
-		levelTracker: NewLevelTracker(deps.Config, deps.Storage, deps.Notifier),
-		deathTracker: NewDeathTracker(deps.Notifier),
-		guildCache:   make(map[string]GuildCacheItem),
+		config:        deps.Config,
+		storage:       deps.Storage,
+		fetcher:       deps.Fetcher,
+		leader:        deps.Leader,
+		caps:          deps.Capabilities,
+		levelTracker:  NewLevelTracker(deps.Config, deps.Storage, deps.Notifier),
+		deathTracker:  NewDeathTracker(deps.Notifier),
+		streakTracker: NewStreakTracker(deps.Storage, deps.Notifier),
+		massTracker:   NewMassDeathTracker(deps.Notifier),
+		memberTracker: NewMembershipTracker(deps.Storage, deps.Notifier),
+		charTracker:   NewCharacterTracker(deps.Storage, deps.Notifier),
+		guildCache:    make(map[string]GuildCacheItem),
 	}
 }
 
+// Start schedules world cycles until ctx is cancelled. Cycles already running
+// then are left to finish; see Drain.
 func (s *Service) Start(ctx context.Context) {
-	ticker := time.NewTicker(s.config.TrackerInterval)
+	ticker := time.NewTicker(s.tickInterval())
 	defer ticker.Stop()
 
-	slog.Info("Tracker service started", "interval", s.config.TrackerInterval)
+	cycleCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	s.scheduleMu.Lock()
+	s.abortCycles = abort
+	s.scheduleMu.Unlock()
+
+	slog.Info("Tracker service started", "interval", s.config.TrackerInterval, "world_overrides", len(s.config.WorldPollIntervals))
+
+	s.loadGuildCache(ctx)
+	s.warmGuildCache(ctx, 0)
+	go s.refreshGuildCache(ctx)
 
-	s.runLoop(ctx)
+	s.runLoop(cycleCtx)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.runLoop(ctx)
+			s.runLoop(cycleCtx)
+		}
+	}
+}
+
+// Drain waits for the world cycles still running after Start returned. If
+// ctx ends first the cycles are cancelled and ctx's error is returned.
+func (s *Service) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.cycles.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.scheduleMu.Lock()
+		abort := s.abortCycles
+		s.scheduleMu.Unlock()
+		if abort != nil {
+			abort()
 		}
+		return ctx.Err()
 	}
 }
 
 func (s *Service) runLoop(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		slog.Debug("Another instance holds the tracker lock, standing by")
+		return
+	}
+
 	configs, err := s.storage.GetAllGuildConfigs(ctx)
 	if err != nil {
 		slog.Error("Failed to fetch guild configs", "error", err)
 		return
 	}
+	if s.caps != nil {
+		for i := range configs {
+			configs[i] = s.caps.Restrict(configs[i])
+		}
+	}
+	s.purgeRemovedGuilds(ctx)
+	s.purgeStoppedGuilds(ctx)
+	s.purgeExpiredHistory(ctx)
+	s.purgeOnlinePresence(ctx)
+
+	now := time.Now()
+	if inServerSaveWindow(now, s.config.ServerSaveQuietWindow) {
+		slog.Info("Skipping tracking cycle around server save", "window", s.config.ServerSaveQuietWindow)
+		return
+	}
 
 	worlds := groupConfigsByWorld(configs)
+	cycleID := logging.NewCycleID()
 
 	for world, guilds := range worlds {
-		slog.Info("Processing world", "world", world, "guilds_count", len(guilds))
-		go s.processWorld(ctx, world, guilds)
+		interval := s.pollInterval(world, guilds)
+		if !s.claimWorld(world, interval, now) {
+			continue
+		}
+		worldCtx := logging.WithAttrs(ctx, "cycle_id", cycleID, "world", world)
+		slog.InfoContext(worldCtx, "Scheduling world", "guilds_count", len(guilds))
+		s.cycles.Add(1)
+		go func() {
+			defer s.cycles.Done()
+			start := time.Now()
+			defer func() { s.releaseWorld(worldCtx, world, interval, time.Since(start)) }()
+			cycleCtx, cancel := s.withCycleDeadline(worldCtx)
+			defer cancel()
+			s.processWorldSafely(cycleCtx, world, guilds)
+		}()
+	}
+}
+
+// withCycleDeadline bounds one world cycle by WORLD_CYCLE_TIMEOUT, so a hung
+// upstream cannot keep the world claimed past its interval.
+func (s *Service) withCycleDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.config.WorldCycleTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.config.WorldCycleTimeout)
+}
+
+// purgeRemovedGuilds deletes the configuration of guilds that removed the bot
+// more than GUILD_REMOVAL_GRACE ago.
+func (s *Service) purgeRemovedGuilds(ctx context.Context) {
+	deleted, err := s.storage.DeleteRemovedGuildConfigs(ctx, time.Now().Add(-s.config.GuildRemovalGrace))
+	if err != nil {
+		slog.Error("Failed to purge removed guild configs", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged configs of removed guilds", "count", deleted, "grace", s.config.GuildRemovalGrace)
+	}
+}
+
+// purgeStoppedGuilds deletes the configuration snapshots of guilds that ran
+// /stop-tracking more than domain.StoppedConfigRetention ago.
+func (s *Service) purgeStoppedGuilds(ctx context.Context) {
+	deleted, err := s.storage.DeleteStoppedGuildConfigs(ctx, time.Now().Add(-domain.StoppedConfigRetention))
+	if err != nil {
+		slog.Error("Failed to purge stopped guild configs", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged configs of stopped guilds", "count", deleted, "retention", domain.StoppedConfigRetention)
+	}
+}
+
+// purgeOnlinePresence averages cycle online counts older than
+// domain.OnlineSampleRetention into hourly ones and deletes online presence,
+// counts and sessions older than /activity looks back.
+func (s *Service) purgeOnlinePresence(ctx context.Context) {
+	now := time.Now()
+	cutoff := now.Add(-domain.ActivityWindow)
+
+	deleted, err := s.storage.DeleteOnlinePresenceBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge online presence", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged old online presence", "count", deleted)
+	}
+
+	downsampled, err := s.storage.DownsampleOnlineCounts(ctx, now.Add(-domain.OnlineSampleRetention).Truncate(time.Hour))
+	if err != nil {
+		slog.Error("Failed to downsample online counts", "error", err)
+	} else if downsampled > 0 {
+		slog.Info("Downsampled online counts", "hours", downsampled)
+	}
+
+	deleted, err = s.storage.DeleteHourlyOnlineCountsBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge online counts", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged old online counts", "count", deleted)
+	}
+
+	deleted, err = s.storage.DeleteOnlineSessionsBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge online sessions", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged old online sessions", "count", deleted)
+	}
+}
+
+// purgeExpiredHistory enforces PLAYER_HISTORY_RETENTION on the death and
+// level up logs; 0 keeps history forever.
+func (s *Service) purgeExpiredHistory(ctx context.Context) {
+	if s.config.PlayerHistoryRetention <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-s.config.PlayerHistoryRetention)
+
+	deleted, err := s.storage.DeleteDeathsBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge expired death history", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged expired death history", "count", deleted, "retention", s.config.PlayerHistoryRetention)
+	}
+
+	deleted, err = s.storage.DeleteLevelUpsBefore(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge expired level history", "error", err)
+	} else if deleted > 0 {
+		slog.Info("Purged expired level history", "count", deleted, "retention", s.config.PlayerHistoryRetention)
 	}
 }
 
+// groupConfigsByWorld buckets configs by world so each world is fetched once
+// per cycle no matter how many Discord guilds track it; notifications then fan
+// out to every guild in the bucket.
 func groupConfigsByWorld(configs []domain.GuildConfig) map[string][]domain.GuildConfig {
 	worlds := make(map[string][]domain.GuildConfig)
 	for _, cfg := range configs {
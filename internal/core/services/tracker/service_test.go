@@ -82,6 +82,213 @@ func TestRunLoop(t *testing.T) {
 		time.Sleep(50 * time.Millisecond)
 	})
 
+	t.Run("fetches a shared world once", func(t *testing.T) {
+		var worldFetches, detailFetches atomic.Int32
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return []domain.GuildConfig{
+					{DiscordGuildID: "g1", World: "Antica"},
+					{DiscordGuildID: "g2", World: "Antica"},
+					{DiscordGuildID: "g3", World: "Antica"},
+				}, nil
+			},
+			getPlayersLevelsFunc: func(ctx context.Context, world string) (map[string]int, error) {
+				return nil, nil
+			},
+			deleteOldPlayersFunc: func(ctx context.Context, world string, d time.Duration) (int64, error) {
+				return 0, nil
+			},
+		}
+
+		fetcher := &mockServiceFetcher{
+			fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+				worldFetches.Add(1)
+				return []domain.Player{{Name: "Knight", Level: 200}}, nil
+			},
+			fetchCharacterDetailsFunc: func(ctx context.Context, names []string) (chan *domain.Player, error) {
+				detailFetches.Add(1)
+				ch := make(chan *domain.Player)
+				close(ch)
+				return ch, nil
+			},
+		}
+
+		cfg := &config.Config{LevelSources: []string{config.LevelSourceTibiaData}}
+		service := &Service{
+			config:       cfg,
+			storage:      storage,
+			fetcher:      fetcher,
+			levelTracker: NewLevelTracker(cfg, storage, &mockServiceNotifier{}),
+			deathTracker: NewDeathTracker(&mockServiceNotifier{}),
+		}
+
+		service.runLoop(context.Background())
+		time.Sleep(50 * time.Millisecond)
+
+		if n := worldFetches.Load(); n != 1 {
+			t.Errorf("expected 1 world fetch, got %d", n)
+		}
+		if n := detailFetches.Load(); n > 1 {
+			t.Errorf("expected at most 1 character details fetch, got %d", n)
+		}
+	})
+
+	t.Run("restricts configs by capabilities", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return []domain.GuildConfig{
+					{DiscordGuildID: "g1", PollInterval: time.Minute},
+					{DiscordGuildID: "g2", PollInterval: time.Minute},
+				}, nil
+			},
+		}
+		caps := &stubCapabilities{}
+		service := &Service{config: &config.Config{}, storage: storage, caps: caps}
+		service.runLoop(context.Background())
+
+		if len(caps.restricted) != 2 || caps.restricted[0] != "g1" || caps.restricted[1] != "g2" {
+			t.Errorf("expected both guilds restricted, got %v", caps.restricted)
+		}
+	})
+
+	t.Run("purges guilds removed before the grace period", func(t *testing.T) {
+		var cutoff time.Time
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			deleteRemovedGuildConfigsFunc: func(ctx context.Context, removedBefore time.Time) (int64, error) {
+				cutoff = removedBefore
+				return 1, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{GuildRemovalGrace: 48 * time.Hour}, storage: storage}
+		service.runLoop(context.Background())
+
+		if d := time.Since(cutoff); d < 48*time.Hour || d > 48*time.Hour+time.Minute {
+			t.Errorf("expected cutoff 48h ago, got %v ago", d)
+		}
+	})
+
+	t.Run("purges stopped guild configs after 30 days", func(t *testing.T) {
+		var cutoff time.Time
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			deleteStoppedGuildConfigsFunc: func(ctx context.Context, stoppedBefore time.Time) (int64, error) {
+				cutoff = stoppedBefore
+				return 1, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{}, storage: storage}
+		service.runLoop(context.Background())
+
+		if d := time.Since(cutoff); d < domain.StoppedConfigRetention || d > domain.StoppedConfigRetention+time.Minute {
+			t.Errorf("expected cutoff 30 days ago, got %v ago", d)
+		}
+	})
+
+	t.Run("purges online presence older than two weeks", func(t *testing.T) {
+		var cutoff time.Time
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			deleteOnlinePresenceBeforeFunc: func(ctx context.Context, seenBefore time.Time) (int64, error) {
+				cutoff = seenBefore
+				return 10, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{}, storage: storage}
+		service.runLoop(context.Background())
+
+		if d := time.Since(cutoff); d < domain.ActivityWindow || d > domain.ActivityWindow+time.Minute {
+			t.Errorf("expected cutoff two weeks ago, got %v ago", d)
+		}
+	})
+
+	t.Run("downsamples online counts and purges sessions", func(t *testing.T) {
+		var sampledBefore, hourBefore, lastSeenBefore time.Time
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			downsampleOnlineCountsFunc: func(ctx context.Context, before time.Time) (int64, error) {
+				sampledBefore = before
+				return 24, nil
+			},
+			deleteHourlyOnlineCountsBeforeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+				hourBefore = before
+				return 0, nil
+			},
+			deleteOnlineSessionsBeforeFunc: func(ctx context.Context, before time.Time) (int64, error) {
+				lastSeenBefore = before
+				return 5, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{}, storage: storage}
+		service.runLoop(context.Background())
+
+		if !sampledBefore.Equal(sampledBefore.Truncate(time.Hour)) {
+			t.Errorf("expected counts downsampled up to a full hour, got %v", sampledBefore)
+		}
+		if d := time.Since(sampledBefore); d < domain.OnlineSampleRetention || d > domain.OnlineSampleRetention+time.Hour {
+			t.Errorf("expected counts older than two days downsampled, got %v ago", d)
+		}
+		for what, cutoff := range map[string]time.Time{"hourly counts": hourBefore, "sessions": lastSeenBefore} {
+			if d := time.Since(cutoff); d < domain.ActivityWindow || d > domain.ActivityWindow+time.Minute {
+				t.Errorf("expected %s purged after two weeks, got a cutoff %v ago", what, d)
+			}
+		}
+	})
+
+	t.Run("purges history older than the retention", func(t *testing.T) {
+		var deathCutoff, levelCutoff time.Time
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			deleteDeathsBeforeFunc: func(ctx context.Context, diedBefore time.Time) (int64, error) {
+				deathCutoff = diedBefore
+				return 3, nil
+			},
+			deleteLevelUpsBeforeFunc: func(ctx context.Context, reachedBefore time.Time) (int64, error) {
+				levelCutoff = reachedBefore
+				return 2, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{PlayerHistoryRetention: 30 * 24 * time.Hour}, storage: storage}
+		service.runLoop(context.Background())
+
+		if d := time.Since(deathCutoff); d < 30*24*time.Hour || d > 30*24*time.Hour+time.Minute {
+			t.Errorf("expected death cutoff 30 days ago, got %v ago", d)
+		}
+		if !levelCutoff.Equal(deathCutoff) {
+			t.Errorf("expected level up cutoff %v, got %v", deathCutoff, levelCutoff)
+		}
+	})
+
+	t.Run("keeps death history when retention is disabled", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return nil, nil
+			},
+			deleteDeathsBeforeFunc: func(ctx context.Context, diedBefore time.Time) (int64, error) {
+				t.Error("retention 0 should not delete deaths")
+				return 0, nil
+			},
+		}
+
+		service := &Service{config: &config.Config{}, storage: storage}
+		service.runLoop(context.Background())
+	})
+
 	t.Run("handles error", func(t *testing.T) {
 		storage := &mockServiceStorage{
 			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
@@ -92,6 +299,18 @@ func TestRunLoop(t *testing.T) {
 		service := &Service{storage: storage}
 		service.runLoop(context.Background())
 	})
+
+	t.Run("stands by when not leader", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				t.Error("standby instance should not load guild configs")
+				return nil, nil
+			},
+		}
+
+		service := &Service{storage: storage, leader: &mockLeader{leader: false}}
+		service.runLoop(context.Background())
+	})
 }
 
 func TestStart(t *testing.T) {
@@ -175,3 +394,122 @@ func TestGroupConfigsByWorld(t *testing.T) {
 		}
 	})
 }
+
+func TestDrain(t *testing.T) {
+	newBlockingService := func(release <-chan struct{}) *Service {
+		storage := &mockServiceStorage{
+			getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+				return []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica"}}, nil
+			},
+		}
+		fetcher := &mockServiceFetcher{
+			fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+				select {
+				case <-release:
+					return nil, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			},
+		}
+		cfg := &config.Config{LevelSources: []string{config.LevelSourceTibiaData}}
+		return &Service{
+			config:       cfg,
+			storage:      storage,
+			fetcher:      fetcher,
+			levelTracker: NewLevelTracker(cfg, storage, &mockServiceNotifier{}),
+			deathTracker: NewDeathTracker(&mockServiceNotifier{}),
+		}
+	}
+
+	t.Run("waits for running cycles", func(t *testing.T) {
+		release := make(chan struct{})
+		service := newBlockingService(release)
+		service.runLoop(context.Background())
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := service.Drain(ctx); err != nil {
+			t.Fatalf("expected cycles to finish, got %v", err)
+		}
+		if service.running["Antica"] {
+			t.Error("expected the world to be released once drained")
+		}
+	})
+
+	t.Run("aborts cycles at the deadline", func(t *testing.T) {
+		service := newBlockingService(make(chan struct{}))
+		cycleCtx, abort := context.WithCancel(context.Background())
+		service.abortCycles = abort
+		service.runLoop(cycleCtx)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := service.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+
+		waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+		defer waitCancel()
+		if err := service.Drain(waitCtx); err != nil {
+			t.Errorf("expected aborted cycles to return, got %v", err)
+		}
+	})
+}
+
+func TestRunLoop_RecoversWorldPanic(t *testing.T) {
+	var anticaFetched atomic.Bool
+	storage := &mockServiceStorage{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return []domain.GuildConfig{
+				{DiscordGuildID: "g1", World: "Broken"},
+				{DiscordGuildID: "g2", World: "Antica"},
+			}, nil
+		},
+	}
+	fetcher := &mockServiceFetcher{
+		fetchWorldFunc: func(ctx context.Context, world string) ([]domain.Player, error) {
+			if world == "Broken" {
+				panic("malformed payload")
+			}
+			anticaFetched.Store(true)
+			return nil, nil
+		},
+	}
+	cfg := &config.Config{LevelSources: []string{config.LevelSourceTibiaData}}
+	service := &Service{
+		config:       cfg,
+		storage:      storage,
+		fetcher:      fetcher,
+		levelTracker: NewLevelTracker(cfg, storage, &mockServiceNotifier{}),
+		deathTracker: NewDeathTracker(&mockServiceNotifier{}),
+	}
+
+	service.runLoop(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := service.Drain(ctx); err != nil {
+		t.Fatalf("expected cycles to finish, got %v", err)
+	}
+
+	if !anticaFetched.Load() {
+		t.Error("expected the other world to be processed")
+	}
+	if service.running["Broken"] {
+		t.Error("expected the panicking world to be released for the next cycle")
+	}
+}
+
+type stubCapabilities struct {
+	restricted []string
+}
+
+func (c *stubCapabilities) Restrict(cfg domain.GuildConfig) domain.GuildConfig {
+	c.restricted = append(c.restricted, cfg.DiscordGuildID)
+	return cfg
+}
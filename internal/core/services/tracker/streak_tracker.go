@@ -0,0 +1,64 @@
+package tracker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+const (
+	streakThreshold = 3
+	streakWindow    = time.Hour
+)
+
+// StreakTracker records deaths in the event log and announces players who
+// die streakThreshold or more times within streakWindow.
+type StreakTracker struct {
+	storage  ports.Repository
+	notifier ports.NotificationService
+}
+
+func NewStreakTracker(store ports.Repository, notifier ports.NotificationService) *StreakTracker {
+	return &StreakTracker{
+		storage:  store,
+		notifier: notifier,
+	}
+}
+
+func (t *StreakTracker) RecordDeaths(ctx context.Context, name, world string, deaths []domain.Kill, guilds []domain.GuildConfig, memberships map[string]map[string]bool) {
+	if len(deaths) == 0 {
+		return
+	}
+
+	latest := deaths[0].Time
+	for _, death := range deaths {
+		if err := t.storage.RecordDeath(ctx, name, world, death); err != nil {
+			slog.ErrorContext(ctx, "Failed to record death", "name", name, "error", err)
+		}
+		if death.Time.After(latest) {
+			latest = death.Time
+		}
+	}
+
+	count, err := t.storage.CountDeathsSince(ctx, name, latest.Add(-streakWindow))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to count recent deaths", "name", name, "error", err)
+		return
+	}
+
+	if count < streakThreshold {
+		return
+	}
+
+	slog.InfoContext(ctx, "Death streak detected", "name", name, "deaths", count)
+	for _, guild := range guilds {
+		if shouldNotifyGuild(name, guild, memberships) {
+			if err := t.notifier.SendDeathStreakNotification(guild, name, count); err != nil {
+				slog.ErrorContext(ctx, "Failed to send death streak notification", "guild_id", guild.DiscordGuildID, "error", err)
+			}
+		}
+	}
+}
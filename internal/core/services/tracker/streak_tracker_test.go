@@ -0,0 +1,94 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+func TestStreakTracker_RecordDeaths(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "guild-1"}}
+	now := time.Now()
+
+	t.Run("no deaths - nothing recorded", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			recordDeathFunc: func(ctx context.Context, name, world string, kill domain.Kill) error {
+				t.Error("expected no deaths to be recorded")
+				return nil
+			},
+		}
+		tracker := NewStreakTracker(storage, &mockServiceNotifier{})
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", nil, guilds, nil)
+	})
+
+	t.Run("below threshold - records without streak", func(t *testing.T) {
+		recorded := 0
+		storage := &mockServiceStorage{
+			recordDeathFunc: func(ctx context.Context, name, world string, kill domain.Kill) error {
+				recorded++
+				return nil
+			},
+			countDeathsSinceFunc: func(ctx context.Context, name string, since time.Time) (int, error) {
+				return 2, nil
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendStreakFunc: func(guildID, playerName string, deaths int) error {
+				t.Error("expected no streak notification")
+				return nil
+			},
+		}
+		tracker := NewStreakTracker(storage, notifier)
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", []domain.Kill{{Time: now}}, guilds, nil)
+
+		if recorded != 1 {
+			t.Errorf("expected 1 recorded death, got %d", recorded)
+		}
+	})
+
+	t.Run("at threshold - sends streak", func(t *testing.T) {
+		var since time.Time
+		storage := &mockServiceStorage{
+			countDeathsSinceFunc: func(ctx context.Context, name string, s time.Time) (int, error) {
+				since = s
+				return 3, nil
+			},
+		}
+		var sent int
+		notifier := &mockServiceNotifier{
+			sendStreakFunc: func(guildID, playerName string, deaths int) error {
+				sent = deaths
+				return nil
+			},
+		}
+		tracker := NewStreakTracker(storage, notifier)
+		deaths := []domain.Kill{{Time: now.Add(-10 * time.Minute)}, {Time: now}}
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", deaths, guilds, nil)
+
+		if sent != 3 {
+			t.Errorf("expected streak of 3, got %d", sent)
+		}
+		if !since.Equal(now.Add(-streakWindow)) {
+			t.Errorf("expected window to start at %v, got %v", now.Add(-streakWindow), since)
+		}
+	})
+
+	t.Run("count error - no streak", func(t *testing.T) {
+		storage := &mockServiceStorage{
+			countDeathsSinceFunc: func(ctx context.Context, name string, since time.Time) (int, error) {
+				return 0, errors.New("db error")
+			},
+		}
+		notifier := &mockServiceNotifier{
+			sendStreakFunc: func(guildID, playerName string, deaths int) error {
+				t.Error("expected no streak notification")
+				return nil
+			},
+		}
+		tracker := NewStreakTracker(storage, notifier)
+		tracker.RecordDeaths(context.Background(), "Hero", "Antica", []domain.Kill{{Time: now}}, guilds, nil)
+	})
+}
@@ -3,8 +3,46 @@ package tracker
 import "death-level-tracker/internal/core/domain"
 
 type worldContext struct {
-	world       string
+	world string
+	// pvpType is the world's PvP type shown in notifications, or empty when
+	// it could not be looked up.
+	pvpType     string
 	guilds      []domain.GuildConfig
 	dbLevels    map[string]int
 	memberships map[string]map[string]bool
+	// lowLevelMembers are guild members whose deaths are checked even below
+	// the minimum tracked level.
+	lowLevelMembers map[string]bool
+	// quiet marks the first cycle of a world after startup, which only
+	// reconciles stored levels so downtime does not flood channels with
+	// level ups.
+	quiet bool
+	// requested holds the characters whose details were already asked for
+	// this cycle, so the online and offline passes never fetch one twice.
+	requested map[string]bool
+	// announced holds the level ups already announced this cycle, so one
+	// reported by both tibia.com and TibiaData goes out once.
+	announced levelUpSet
+}
+
+type levelUpKey struct {
+	name  string
+	level int
+}
+
+// levelUpSet remembers level ups by character and new level. A nil set
+// remembers nothing.
+type levelUpSet map[levelUpKey]bool
+
+// claim reports whether levelUp is new to the set and adds it.
+func (s levelUpSet) claim(levelUp domain.LevelUp) bool {
+	if s == nil {
+		return true
+	}
+	key := levelUpKey{name: domain.NameKey(levelUp.PlayerName), level: levelUp.NewLevel}
+	if s[key] {
+		return false
+	}
+	s[key] = true
+	return true
 }
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+	"death-level-tracker/internal/core/ports"
+)
+
+// warPollInterval is how often the war status of tracked guilds is checked.
+// Wars last days, so there is no need to check as often as deaths.
+const warPollInterval = 30 * time.Minute
+
+// WarService records when tracked Tibia guilds enter and leave guild wars and
+// announces both to the Discord servers tracking them.
+type WarService struct {
+	repo     ports.Repository
+	fetcher  ports.TibiaFetcher
+	notifier ports.NotificationService
+	// leader gates polling when several replicas run; nil means always lead.
+	leader ports.LeaderElector
+	now    func() time.Time
+}
+
+func NewWarService(repo ports.Repository, fetcher ports.TibiaFetcher, notifier ports.NotificationService, leader ports.LeaderElector) *WarService {
+	return &WarService{
+		repo:     repo,
+		fetcher:  fetcher,
+		notifier: notifier,
+		leader:   leader,
+		now:      time.Now,
+	}
+}
+
+// Start checks the war status of tracked guilds every warPollInterval until
+// ctx is cancelled.
+func (s *WarService) Start(ctx context.Context) {
+	ticker := time.NewTicker(warPollInterval)
+	defer ticker.Stop()
+
+	slog.Info("Guild war service started", "interval", warPollInterval)
+
+	s.runCycle(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runCycle(ctx)
+		}
+	}
+}
+
+func (s *WarService) runCycle(ctx context.Context) {
+	if s.leader != nil && !s.leader.IsLeader(ctx) {
+		return
+	}
+
+	configs, err := s.repo.GetAllGuildConfigs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch guild configs", "error", err)
+		return
+	}
+
+	names := make(map[string]string)
+	tracking := make(map[string][]domain.GuildConfig)
+	for _, cfg := range configs {
+		if cfg.World == "" {
+			continue
+		}
+		for _, name := range cfg.TibiaGuilds {
+			key := domain.NameKey(name)
+			names[key] = name
+			tracking[key] = append(tracking[key], cfg)
+		}
+	}
+
+	for key, guilds := range tracking {
+		if err := s.checkGuild(ctx, names[key], guilds); err != nil {
+			slog.ErrorContext(ctx, "Failed to check guild war", "guild", names[key], "error", err)
+		}
+	}
+}
+
+// checkGuild compares the war status of a Tibia guild with its last stored
+// war. A guild first seen at war only has the war recorded, so deploying the
+// feature or adding a guild does not announce a war that began long ago.
+func (s *WarService) checkGuild(ctx context.Context, name string, guilds []domain.GuildConfig) error {
+	guild, err := s.fetcher.FetchGuild(ctx, name)
+	if err != nil {
+		return fmt.Errorf("fetch guild: %w", err)
+	}
+
+	last, err := s.repo.GetGuildWar(ctx, guild.Name)
+	if err != nil {
+		return fmt.Errorf("load war: %w", err)
+	}
+
+	now := s.now()
+	switch {
+	case guild.InWar && last == nil:
+		slog.InfoContext(ctx, "Recording ongoing guild war", "guild", guild.Name)
+		if err := s.repo.StartGuildWar(ctx, guild.Name, now); err != nil {
+			return fmt.Errorf("start war: %w", err)
+		}
+	case guild.InWar && !last.Ongoing():
+		if err := s.repo.StartGuildWar(ctx, guild.Name, now); err != nil {
+			return fmt.Errorf("start war: %w", err)
+		}
+		s.notify(ctx, guilds, domain.GuildWar{GuildName: guild.Name, StartedAt: now}, now)
+	case !guild.InWar && last != nil && last.Ongoing():
+		if err := s.repo.EndGuildWar(ctx, guild.Name, now); err != nil {
+			return fmt.Errorf("end war: %w", err)
+		}
+		war := *last
+		war.EndedAt = now
+		s.notify(ctx, guilds, war, now)
+	}
+	return nil
+}
+
+func (s *WarService) notify(ctx context.Context, guilds []domain.GuildConfig, war domain.GuildWar, now time.Time) {
+	for _, guild := range guilds {
+		if guild.IsMuted(now) {
+			continue
+		}
+		if err := s.notifier.SendGuildWarNotification(guild, war); err != nil {
+			slog.ErrorContext(ctx, "Failed to send guild war notification", "guild_id", guild.DiscordGuildID, "guild", war.GuildName, "error", err)
+		}
+	}
+}
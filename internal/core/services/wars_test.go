@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"death-level-tracker/internal/core/domain"
+)
+
+type warAnnouncement struct {
+	guildID string
+	ended   bool
+}
+
+func newTestWarService(inWar bool, last *domain.GuildWar, guilds []domain.GuildConfig) (*WarService, *[]warAnnouncement, *[]string) {
+	var sent []warAnnouncement
+	var changes []string
+	repo := &mockRepository{
+		getAllGuildConfigsFunc: func(ctx context.Context) ([]domain.GuildConfig, error) {
+			return guilds, nil
+		},
+		getGuildWarFunc: func(ctx context.Context, guildName string) (*domain.GuildWar, error) {
+			return last, nil
+		},
+		startGuildWarFunc: func(ctx context.Context, guildName string, startedAt time.Time) error {
+			changes = append(changes, "start "+guildName)
+			return nil
+		},
+		endGuildWarFunc: func(ctx context.Context, guildName string, endedAt time.Time) error {
+			changes = append(changes, "end "+guildName)
+			return nil
+		},
+	}
+	fetcher := &mockFetcher{
+		fetchGuildFunc: func(ctx context.Context, name string) (*domain.Guild, error) {
+			return &domain.Guild{Name: "Red Rose", InWar: inWar}, nil
+		},
+	}
+	notifier := &mockNotifier{
+		sendWarFunc: func(guild domain.GuildConfig, war domain.GuildWar) error {
+			sent = append(sent, warAnnouncement{guild.DiscordGuildID, !war.Ongoing()})
+			return nil
+		},
+	}
+	return NewWarService(repo, fetcher, notifier, nil), &sent, &changes
+}
+
+func TestWarService_AnnouncesNewWar(t *testing.T) {
+	guilds := []domain.GuildConfig{
+		{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}},
+		{DiscordGuildID: "g2", World: "Antica", TibiaGuilds: []string{"red rose"}, MutedUntil: time.Now().Add(time.Hour)},
+		{DiscordGuildID: "g3", TibiaGuilds: []string{"Red Rose"}},
+	}
+	ended := &domain.GuildWar{GuildName: "Red Rose", StartedAt: time.Now().Add(-48 * time.Hour), EndedAt: time.Now().Add(-24 * time.Hour)}
+	svc, sent, changes := newTestWarService(true, ended, guilds)
+
+	svc.runCycle(context.Background())
+
+	if len(*changes) != 1 || (*changes)[0] != "start Red Rose" {
+		t.Errorf("expected the war to be recorded once, got %v", *changes)
+	}
+	if len(*sent) != 1 || (*sent)[0] != (warAnnouncement{"g1", false}) {
+		t.Errorf("expected only g1 to be told, got %+v", *sent)
+	}
+}
+
+func TestWarService_AnnouncesEndedWar(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}}}
+	ongoing := &domain.GuildWar{GuildName: "Red Rose", StartedAt: time.Now().Add(-48 * time.Hour)}
+	svc, sent, changes := newTestWarService(false, ongoing, guilds)
+
+	svc.runCycle(context.Background())
+
+	if len(*changes) != 1 || (*changes)[0] != "end Red Rose" {
+		t.Errorf("expected the war to be ended, got %v", *changes)
+	}
+	if len(*sent) != 1 || (*sent)[0] != (warAnnouncement{"g1", true}) {
+		t.Errorf("expected the end to be announced, got %+v", *sent)
+	}
+}
+
+func TestWarService_FirstSeenWarRecordsSilently(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}}}
+	svc, sent, changes := newTestWarService(true, nil, guilds)
+
+	svc.runCycle(context.Background())
+
+	if len(*changes) != 1 || (*changes)[0] != "start Red Rose" {
+		t.Errorf("expected the war to be recorded, got %v", *changes)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("expected no announcement, got %+v", *sent)
+	}
+}
+
+func TestWarService_NothingChanged(t *testing.T) {
+	guilds := []domain.GuildConfig{{DiscordGuildID: "g1", World: "Antica", TibiaGuilds: []string{"Red Rose"}}}
+	svc, sent, changes := newTestWarService(false, nil, guilds)
+
+	svc.runCycle(context.Background())
+
+	if len(*changes) != 0 || len(*sent) != 0 {
+		t.Errorf("expected nothing to happen, got %v and %+v", *changes, *sent)
+	}
+}
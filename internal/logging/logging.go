@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type ctxKey struct{}
+
+// NewHandler returns a text or JSON handler writing to w that also emits the
+// attributes attached to a record's context via WithAttrs. Any format other
+// than "text" produces JSON.
+func NewHandler(w io.Writer, format string) slog.Handler {
+	var h slog.Handler
+	if strings.EqualFold(format, FormatText) {
+		h = slog.NewTextHandler(w, nil)
+	} else {
+		h = slog.NewJSONHandler(w, nil)
+	}
+	return &contextHandler{Handler: h}
+}
+
+// WithAttrs returns a copy of ctx carrying args as key-value log attributes,
+// appended to any attributes already present.
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	r := slog.Record{}
+	r.Add(args...)
+
+	attrs := append([]slog.Attr(nil), attrsFrom(ctx)...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, ctxKey{}, attrs)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	return attrs
+}
+
+// NewCycleID returns a short random identifier for one tracking cycle.
+func NewCycleID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the context attributes to r, skipping keys r already sets so
+// explicit attributes win over inherited ones.
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := attrsFrom(ctx)
+	if len(attrs) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	present := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		present[a.Key] = true
+		return true
+	})
+
+	r = r.Clone()
+	for _, a := range attrs {
+		if !present[a.Key] {
+			r.AddAttrs(a)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
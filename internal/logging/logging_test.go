@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewHandler_JSONIncludesContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, FormatJSON))
+
+	ctx := WithAttrs(context.Background(), "cycle_id", "abc123", "world", "Antica")
+	logger.InfoContext(ctx, "Processing", "count", 3)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if entry["cycle_id"] != "abc123" || entry["world"] != "Antica" {
+		t.Errorf("missing context attrs: %v", entry)
+	}
+	if entry["count"] != float64(3) {
+		t.Errorf("missing record attrs: %v", entry)
+	}
+}
+
+func TestNewHandler_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, "TEXT"))
+
+	logger.InfoContext(WithAttrs(context.Background(), "world", "Antica"), "hello")
+
+	out := buf.String()
+	if strings.HasPrefix(out, "{") || !strings.Contains(out, "world=Antica") {
+		t.Errorf("expected text output with context attrs, got %q", out)
+	}
+}
+
+func TestWithAttrs_Appends(t *testing.T) {
+	ctx := WithAttrs(context.Background(), "cycle_id", "abc")
+	child := WithAttrs(ctx, "world", "Antica")
+
+	if len(attrsFrom(ctx)) != 1 {
+		t.Errorf("expected parent context to be unchanged, got %v", attrsFrom(ctx))
+	}
+	if len(attrsFrom(child)) != 2 {
+		t.Errorf("expected 2 attrs, got %v", attrsFrom(child))
+	}
+}
+
+func TestNewCycleID(t *testing.T) {
+	a, b := NewCycleID(), NewCycleID()
+	if len(a) != 16 {
+		t.Errorf("expected 16 hex chars, got %q", a)
+	}
+	if a == b {
+		t.Error("expected unique cycle IDs")
+	}
+}
+
+func TestNewHandler_RecordAttrsWin(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, FormatJSON))
+
+	logger.InfoContext(WithAttrs(context.Background(), "world", "Antica"), "hello", "world", "Secura")
+
+	if strings.Count(buf.String(), `"world"`) != 1 || !strings.Contains(buf.String(), `"world":"Secura"`) {
+		t.Errorf("expected a single explicit world attribute, got %q", buf.String())
+	}
+}
@@ -0,0 +1,26 @@
+// Package stats holds small numeric helpers for the bot's statistics.
+package stats
+
+// SpanAlpha is the smoothing factor of an exponential moving average over
+// span periods, 2/(span+1). Spans below one give 1, which keeps only the
+// latest value.
+func SpanAlpha(span int) float64 {
+	if span < 1 {
+		return 1
+	}
+	return 2 / float64(span+1)
+}
+
+// EMA returns the exponential moving average of values, oldest first, with
+// smoothing factor alpha in (0, 1]. The first value seeds the average and an
+// empty series averages to 0.
+func EMA(values []float64, alpha float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	avg := values[0]
+	for _, v := range values[1:] {
+		avg = alpha*v + (1-alpha)*avg
+	}
+	return avg
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpanAlpha(t *testing.T) {
+	tests := []struct {
+		span int
+		want float64
+	}{
+		{7, 0.25},
+		{1, 1},
+		{0, 1},
+		{-3, 1},
+	}
+
+	for _, tt := range tests {
+		if got := SpanAlpha(tt.span); got != tt.want {
+			t.Errorf("SpanAlpha(%d) = %v, want %v", tt.span, got, tt.want)
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		alpha  float64
+		want   float64
+	}{
+		{"empty", nil, 0.5, 0},
+		{"single value", []float64{3}, 0.5, 3},
+		{"constant series", []float64{2, 2, 2, 2}, 0.25, 2},
+		{"recent values weigh more", []float64{0, 0, 4}, 0.5, 2},
+		{"alpha one keeps the latest", []float64{5, 1, 9}, 1, 9},
+		{"negative values", []float64{2, -2}, 0.25, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EMA(tt.values, tt.alpha); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EMA(%v, %v) = %v, want %v", tt.values, tt.alpha, got, tt.want)
+			}
+		})
+	}
+}
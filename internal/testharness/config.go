@@ -0,0 +1,38 @@
+package testharness
+
+import (
+	"strings"
+	"time"
+
+	"death-level-tracker/internal/config"
+)
+
+// Config returns a valid configuration that runs the bot on in-memory storage
+// against the fake TibiaData at tibiaDataURL. Levels come from TibiaData, level
+// ups are announced from the first cycle and nothing pauses around server
+// save, so a single cycle shows everything a test set up.
+func Config(tibiaDataURL string) *config.Config {
+	return &config.Config{
+		Token:                  strings.Repeat("x", 60),
+		StorageDriver:          config.StorageDriverMemory,
+		LevelSources:           []string{config.LevelSourceTibiaCom, config.LevelSourceTibiaData},
+		TrackerInterval:        time.Minute,
+		MinLevelTrack:          100,
+		DiscordChannelDeath:    "death-tracker",
+		DiscordChannelLevel:    "level-tracker",
+		DiscordChannelAudit:    "tracker-audit",
+		WorkerPoolSize:         2,
+		HousePollInterval:      time.Hour,
+		SkillPollInterval:      time.Hour,
+		NotificationMaxAge:     time.Hour,
+		GuildCacheTTL:          time.Hour,
+		PlayerHistoryRetention: 90 * 24 * time.Hour,
+		DBMaxConns:             1,
+		DBMaxConnLifetime:      time.Hour,
+		TibiaDataBaseURL:       tibiaDataURL,
+		TibiaComBaseURL:        tibiaDataURL,
+		TibiaDataTimeout:       5 * time.Second,
+		TibiaComTimeout:        5 * time.Second,
+		HTTPMaxIdleConns:       10,
+	}
+}
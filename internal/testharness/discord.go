@@ -0,0 +1,189 @@
+package testharness
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Message is a message the bot posted to a channel.
+type Message struct {
+	ChannelID string
+	Content   string
+}
+
+// Discord fakes the parts of the Discord REST API the bot uses outside the
+// gateway: listing and creating channels, posting messages and answering
+// interactions. Attach routes a real discordgo session to it.
+type Discord struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	channels  map[string][]*discordgo.Channel
+	messages  []Message
+	responses []discordgo.InteractionResponse
+	nextID    int
+}
+
+// NewDiscord starts a fake Discord API that is closed with t.
+func NewDiscord(t testing.TB) *Discord {
+	f := &Discord{channels: make(map[string][]*discordgo.Channel)}
+
+	prefix := "/api/v" + discordgo.APIVersion
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+prefix+"/guilds/{guild}/channels", f.handleListChannels)
+	mux.HandleFunc("POST "+prefix+"/guilds/{guild}/channels", f.handleCreateChannel)
+	mux.HandleFunc("POST "+prefix+"/channels/{channel}/messages", f.handleMessage)
+	mux.HandleFunc("POST "+prefix+"/interactions/{id}/{token}/callback", f.handleInteraction)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// Attach sends every REST request of session to the fake instead of Discord.
+func (f *Discord) Attach(session *discordgo.Session) {
+	target, _ := url.Parse(f.server.URL)
+	session.Client = &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &redirectTransport{target: target},
+	}
+}
+
+// AddChannel adds a text channel named name to the guild and returns its ID.
+func (f *Discord) AddChannel(guildID, name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addChannel(guildID, name).ID
+}
+
+func (f *Discord) addChannel(guildID, name string) *discordgo.Channel {
+	f.nextID++
+	channel := &discordgo.Channel{
+		ID:      fmt.Sprintf("channel-%d", f.nextID),
+		GuildID: guildID,
+		Name:    name,
+		Type:    discordgo.ChannelTypeGuildText,
+	}
+	f.channels[guildID] = append(f.channels[guildID], channel)
+	return channel
+}
+
+// ChannelID returns the ID of the guild's channel named name, or "".
+func (f *Discord) ChannelID(guildID, name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.channels[guildID] {
+		if c.Name == name {
+			return c.ID
+		}
+	}
+	return ""
+}
+
+// Messages returns the messages posted to channelID so far.
+func (f *Discord) Messages(channelID string) []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []Message
+	for _, m := range f.messages {
+		if m.ChannelID == channelID {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// WaitForMessage waits until a message containing substr is posted to
+// channelID and fails t when none arrives within timeout.
+func (f *Discord) WaitForMessage(t testing.TB, channelID, substr string, timeout time.Duration) Message {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		for _, m := range f.Messages(channelID) {
+			if strings.Contains(m.Content, substr) {
+				return m
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no message containing %q in channel %s after %v, got %+v", substr, channelID, timeout, f.Messages(channelID))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Responses returns the interaction responses sent so far.
+func (f *Discord) Responses() []discordgo.InteractionResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]discordgo.InteractionResponse(nil), f.responses...)
+}
+
+func (f *Discord) handleListChannels(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	channels := append([]*discordgo.Channel{}, f.channels[r.PathValue("guild")]...)
+	f.mu.Unlock()
+	writeJSON(w, channels)
+}
+
+func (f *Discord) handleCreateChannel(w http.ResponseWriter, r *http.Request) {
+	var data discordgo.GuildChannelCreateData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	channel := f.addChannel(r.PathValue("guild"), data.Name)
+	f.mu.Unlock()
+	writeJSON(w, channel)
+}
+
+func (f *Discord) handleMessage(w http.ResponseWriter, r *http.Request) {
+	var data discordgo.MessageSend
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	channelID := r.PathValue("channel")
+	f.mu.Lock()
+	f.nextID++
+	f.messages = append(f.messages, Message{ChannelID: channelID, Content: data.Content})
+	id := fmt.Sprintf("message-%d", f.nextID)
+	f.mu.Unlock()
+	writeJSON(w, discordgo.Message{ID: id, ChannelID: channelID, Content: data.Content})
+}
+
+func (f *Discord) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	var resp discordgo.InteractionResponse
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.responses = append(f.responses, resp)
+	f.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// redirectTransport sends requests to target, keeping their path.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,92 @@
+// Package testharness provides fakes for running the bot end to end in tests:
+// a TibiaData server whose worlds and characters tests change between cycles
+// and a Discord API that records every message and interaction response.
+package testharness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"death-level-tracker/internal/adapters/tibiadata/api"
+)
+
+// TibiaData serves the TibiaData v4 world and character endpoints from
+// in-memory state. Unknown worlds and characters get a 404.
+type TibiaData struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	worlds     map[string][]api.OnlinePlayer
+	characters map[string]api.CharacterResponse
+}
+
+// NewTibiaData starts a fake TibiaData server that is closed with t.
+func NewTibiaData(t testing.TB) *TibiaData {
+	f := &TibiaData{
+		worlds:     make(map[string][]api.OnlinePlayer),
+		characters: make(map[string]api.CharacterResponse),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /world/{name}", f.handleWorld)
+	mux.HandleFunc("GET /character/{name}", f.handleCharacter)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL is the base URL to use as TIBIADATA_BASE_URL.
+func (f *TibiaData) URL() string {
+	return f.server.URL
+}
+
+// SetOnline replaces the players online on world.
+func (f *TibiaData) SetOnline(world string, players ...api.OnlinePlayer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.worlds[world] = players
+}
+
+// SetCharacter stores the character page served for info.Name.
+func (f *TibiaData) SetCharacter(info api.CharacterInfo, deaths ...api.Death) {
+	var resp api.CharacterResponse
+	resp.Character.Character = info
+	resp.Character.Deaths = deaths
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.characters[info.Name] = resp
+}
+
+func (f *TibiaData) handleWorld(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	players, ok := f.worlds[r.PathValue("name")]
+	f.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var resp api.WorldResponse
+	resp.World.OnlinePlayers = players
+	writeJSON(w, resp)
+}
+
+func (f *TibiaData) handleCharacter(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	resp, ok := f.characters[r.PathValue("name")]
+	f.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
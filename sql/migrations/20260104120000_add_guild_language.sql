@@ -0,0 +1,2 @@
+-- Add per-guild notification language
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT 'en';
@@ -0,0 +1,3 @@
+-- Add per-guild notification channel overrides (empty means lookup by name)
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_channel_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_channel_id VARCHAR(32) NOT NULL DEFAULT '';
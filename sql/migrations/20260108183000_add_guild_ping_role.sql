@@ -0,0 +1,3 @@
+-- Add role mention for high-level deaths
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS ping_role_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS ping_min_level INT NOT NULL DEFAULT 0;
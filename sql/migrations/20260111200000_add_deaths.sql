@@ -0,0 +1,21 @@
+-- =============================================================================
+-- Migration: Death Event Log
+-- Description: Stores every detected death for streaks and daily summaries
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS deaths (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    world VARCHAR(64) NOT NULL,
+    level INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    died_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, died_at)
+);
+
+-- Index for CountDeathsSince: streak detection per player
+CREATE INDEX IF NOT EXISTS idx_deaths_name_died_at ON deaths (name, died_at);
+
+-- Index for GetDeathCountsSince: daily summaries per world
+CREATE INDEX IF NOT EXISTS idx_deaths_world_died_at ON deaths (world, died_at);
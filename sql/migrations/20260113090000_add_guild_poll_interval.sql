@@ -0,0 +1,2 @@
+-- Per-guild polling interval override (0 = use the default interval)
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS poll_interval_seconds INT NOT NULL DEFAULT 0;
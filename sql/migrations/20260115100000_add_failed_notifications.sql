@@ -0,0 +1,21 @@
+-- =============================================================================
+-- Migration: Failed Notification Queue
+-- Description: Persists Discord notifications that failed to send for retry
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS failed_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for GetDueFailedNotifications: background retry loop
+CREATE INDEX IF NOT EXISTS idx_failed_notifications_next_attempt_at ON failed_notifications (next_attempt_at);
+
+-- Index for GetGuildFailedNotifications: manual /retry-failed flush
+CREATE INDEX IF NOT EXISTS idx_failed_notifications_guild_id ON failed_notifications (guild_id);
@@ -0,0 +1,2 @@
+-- Notifications are suppressed for the guild until this time (NULL = not muted)
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS muted_until TIMESTAMPTZ DEFAULT NULL;
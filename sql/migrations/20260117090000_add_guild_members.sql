@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: Tibia Guild Members
+-- Description: Last known member list per tracked Tibia guild, used to
+-- announce characters joining and leaving
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS guild_members (
+    guild_name VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_name, name)
+);
@@ -0,0 +1,2 @@
+-- Characters whose deaths and level ups are never announced in this Discord guild
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS ignored_players TEXT[] DEFAULT NULL;
@@ -0,0 +1,2 @@
+-- Announce deaths of tracked Tibia guild members below MIN_LEVEL_TRACK
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS low_level_deaths BOOLEAN NOT NULL DEFAULT TRUE;
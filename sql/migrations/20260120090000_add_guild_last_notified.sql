@@ -0,0 +1,2 @@
+-- Record when a guild last received a notification, shown by /track-status
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS last_notified_at TIMESTAMPTZ DEFAULT NULL;
@@ -0,0 +1,2 @@
+-- Soft-delete configurations of servers that removed the bot
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS removed_at TIMESTAMPTZ DEFAULT NULL;
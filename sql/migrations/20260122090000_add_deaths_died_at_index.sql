@@ -0,0 +1,2 @@
+-- Let the retention job find expired deaths without a sequential scan
+CREATE INDEX IF NOT EXISTS idx_deaths_died_at ON deaths (died_at);
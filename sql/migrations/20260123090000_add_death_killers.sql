@@ -0,0 +1,2 @@
+-- Player killers of each recorded death, for /top-killers
+ALTER TABLE deaths ADD COLUMN IF NOT EXISTS killers TEXT[] DEFAULT NULL;
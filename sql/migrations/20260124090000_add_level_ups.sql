@@ -0,0 +1,22 @@
+-- =============================================================================
+-- Migration: Level Up Event Log
+-- Description: Stores every detected level up for /compare and pace estimates
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS level_ups (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    world VARCHAR(64) NOT NULL,
+    old_level INT NOT NULL,
+    new_level INT NOT NULL,
+    reached_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for GetLevelUpsSince: level history per player
+CREATE INDEX IF NOT EXISTS idx_level_ups_name_reached_at ON level_ups (name, reached_at);
+
+-- Index for PurgeGuildData: level history per world
+CREATE INDEX IF NOT EXISTS idx_level_ups_world ON level_ups (world);
+
+-- Index for DeleteLevelUpsBefore: retention
+CREATE INDEX IF NOT EXISTS idx_level_ups_reached_at ON level_ups (reached_at);
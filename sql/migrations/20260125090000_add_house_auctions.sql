@@ -0,0 +1,17 @@
+-- =============================================================================
+-- Migration: House Auctions
+-- Description: Opt-in house auction announcements and the auctions already seen
+-- =============================================================================
+
+-- Channel receiving /track-houses announcements, empty when not opted in
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS house_channel_id VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS house_auctions (
+    world VARCHAR(64) NOT NULL,
+    house_id INT NOT NULL,
+    name TEXT NOT NULL,
+    town VARCHAR(64) NOT NULL,
+    current_bid BIGINT NOT NULL DEFAULT 0,
+    seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (world, house_id)
+);
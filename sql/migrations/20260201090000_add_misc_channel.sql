@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Misc Channel
+-- Description: Channel for daily informational posts such as Rashid's location
+-- =============================================================================
+
+-- Empty falls back to the level channel
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS misc_channel_id VARCHAR(32) NOT NULL DEFAULT '';
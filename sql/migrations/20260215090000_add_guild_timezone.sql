@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Timezone
+-- Description: IANA zone timestamps in a guild's notifications are shown in
+-- =============================================================================
+
+-- Empty uses the bot's local zone
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- =============================================================================
+-- Migration: Message Templates
+-- Description: Per-guild templates replacing the default death and level up messages
+-- =============================================================================
+
+-- Empty uses the default message
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_template TEXT NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_template TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- =============================================================================
+-- Migration: Notification Emojis
+-- Description: Per-guild emojis prepended to death and level up messages, and
+-- whether the bot reacts to its own messages
+-- =============================================================================
+
+-- Empty adds no emoji
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_emoji VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_emoji VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_reaction BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_reaction BOOLEAN NOT NULL DEFAULT FALSE;
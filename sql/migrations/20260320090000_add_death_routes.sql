@@ -0,0 +1,11 @@
+-- =============================================================================
+-- Migration: Death Routes
+-- Description: Per-guild rules sending deaths at or above a level to another channel
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS death_routes (
+    guild_id VARCHAR(32) NOT NULL REFERENCES guild_configs (guild_id) ON DELETE CASCADE,
+    min_level INT NOT NULL,
+    channel_id VARCHAR(32) NOT NULL,
+    PRIMARY KEY (guild_id, min_level)
+);
@@ -0,0 +1,10 @@
+-- =============================================================================
+-- Migration: Quiet Hours
+-- Description: Daily per-guild window without notifications, in minutes after
+-- midnight in the guild's timezone, and whether missed events are caught up
+-- =============================================================================
+
+-- Equal start and end means no quiet hours
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quiet_start INT NOT NULL DEFAULT 0;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quiet_end INT NOT NULL DEFAULT 0;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quiet_catch_up BOOLEAN NOT NULL DEFAULT FALSE;
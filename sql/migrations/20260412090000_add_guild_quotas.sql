@@ -0,0 +1,9 @@
+-- =============================================================================
+-- Migration: Guild Quotas
+-- Description: Per-guild overrides of the bot-wide limits on tracked Tibia
+-- guilds and ignored players
+-- =============================================================================
+
+-- 0 keeps the bot-wide limit, a negative value removes it
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quota_tibia_guilds INT NOT NULL DEFAULT 0;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quota_ignored_players INT NOT NULL DEFAULT 0;
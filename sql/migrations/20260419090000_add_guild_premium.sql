@@ -0,0 +1,6 @@
+-- =============================================================================
+-- Migration: Guild Premium
+-- Description: Premium flag unlocking features gated by PREMIUM_GATING
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS premium BOOLEAN NOT NULL DEFAULT FALSE;
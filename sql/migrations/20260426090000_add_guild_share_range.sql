@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Share Range
+-- Description: Per-guild toggle appending the party experience share range to
+-- level up notifications
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS share_range BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,24 @@
+-- =============================================================================
+-- Migration: Skill Tracking
+-- Description: Opt-in skill advance notifications for watched players, with
+-- per-skill thresholds and the last skill values seen on the highscores
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS skill_channel_id VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS watched_players TEXT[] DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS skill_thresholds (
+    guild_id VARCHAR(32) NOT NULL REFERENCES guild_configs (guild_id) ON DELETE CASCADE,
+    skill VARCHAR(16) NOT NULL,
+    min_value INT NOT NULL,
+    PRIMARY KEY (guild_id, skill)
+);
+
+CREATE TABLE IF NOT EXISTS player_skills (
+    world VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    skill VARCHAR(16) NOT NULL,
+    value INT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (world, name, skill)
+);
@@ -0,0 +1,8 @@
+-- =============================================================================
+-- Migration: Mass Death Alert
+-- Description: Per-guild thresholds for the possible war or raid alert sent
+-- when many tracked characters die close together
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS mass_death_count INT NOT NULL DEFAULT 0;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS mass_death_window_minutes INT NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- =============================================================================
+-- Migration: Guild Member Cache
+-- Description: Last fetched member list per tracked Tibia guild, loaded on
+-- boot so tracking starts from recent data and survives TibiaData outages
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS guild_member_cache (
+    guild_name VARCHAR(64) PRIMARY KEY,
+    members TEXT[] NOT NULL DEFAULT '{}',
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Broadcast Opt-Out
+-- Description: Per-guild flag keeping announcements sent by the bot operator
+-- with the broadcast admin command out of the guild
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS broadcast_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
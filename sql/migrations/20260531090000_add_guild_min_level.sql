@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Minimum Level
+-- Description: Per-guild minimum level raising MIN_LEVEL_TRACK for the deaths
+-- and level ups announced in the guild
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS min_level INT NOT NULL DEFAULT 0;
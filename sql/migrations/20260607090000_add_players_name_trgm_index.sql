@@ -0,0 +1,9 @@
+-- =============================================================================
+-- Migration: Player Name Trigram Index
+-- Description: pg_trgm index on lower(name) so case-insensitive prefix lookups
+-- behind player autocomplete use an index instead of scanning players
+-- =============================================================================
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_players_name_trgm ON players USING gin (lower(name) gin_trgm_ops);
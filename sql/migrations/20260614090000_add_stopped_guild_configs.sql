@@ -0,0 +1,13 @@
+-- =============================================================================
+-- Migration: Stopped Guild Configs
+-- Description: Snapshot of a server's configuration taken by /stop-tracking,
+-- kept for 30 days so /resume-tracking can restore it
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS stopped_guild_configs (
+    guild_id VARCHAR(32) PRIMARY KEY,
+    config JSONB NOT NULL,
+    stopped_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stopped_guild_configs_stopped_at ON stopped_guild_configs (stopped_at);
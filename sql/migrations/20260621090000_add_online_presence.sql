@@ -0,0 +1,14 @@
+-- =============================================================================
+-- Migration: Online Presence
+-- Description: Which characters were online on a world in each hour, kept for
+-- two weeks to chart when a server's Tibia guilds are most active
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS online_presence (
+    world VARCHAR(64) NOT NULL,
+    seen_hour TIMESTAMPTZ NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    PRIMARY KEY (world, seen_hour, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_online_presence_seen_hour ON online_presence (seen_hour);
@@ -0,0 +1,36 @@
+-- =============================================================================
+-- Migration: Online Counts and Sessions
+-- Description: How many characters were online on a world each cycle, averaged
+-- into hourly counts after two days, and each character's online sessions
+-- inferred from when it appears on and drops off the online list
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS online_counts (
+    world VARCHAR(64) NOT NULL,
+    sampled_at TIMESTAMPTZ NOT NULL,
+    players INT NOT NULL,
+    PRIMARY KEY (world, sampled_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_online_counts_sampled_at ON online_counts (sampled_at);
+
+CREATE TABLE IF NOT EXISTS online_counts_hourly (
+    world VARCHAR(64) NOT NULL,
+    hour TIMESTAMPTZ NOT NULL,
+    avg_players REAL NOT NULL,
+    max_players INT NOT NULL,
+    PRIMARY KEY (world, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_online_counts_hourly_hour ON online_counts_hourly (hour);
+
+CREATE TABLE IF NOT EXISTS online_sessions (
+    world VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (world, name, started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_online_sessions_name ON online_sessions (name, last_seen_at);
+CREATE INDEX IF NOT EXISTS idx_online_sessions_last_seen_at ON online_sessions (last_seen_at);
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Death Location
+-- Description: Per-guild switch appending the likely hunting ground or boss
+-- area, guessed from the killers, to death notifications
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_location BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+-- =============================================================================
+-- Migration: Guild Level Downs
+-- Description: Per-guild switch announcing level drops of two or more levels,
+-- with their own message template
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_downs BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS level_down_template TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Response Visibility
+-- Description: Per-guild override making the bot's replies to commands either
+-- all ephemeral or all public; empty keeps each command's own choice
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS response_visibility TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: Guild Wars
+-- Description: When tracked Tibia guilds were seen entering and leaving a war,
+-- bounding the kills /war-score tallies
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS guild_wars (
+    guild_name VARCHAR(64) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    PRIMARY KEY (guild_name, started_at)
+);
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Death Verbosity
+-- Description: Per-guild length of death notifications: compact, detailed, or
+-- empty for the normal message
+-- =============================================================================
+
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS death_verbosity TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- =============================================================================
+-- Migration: Guild Watched Players Quota
+-- Description: Per-guild override of the bot-wide limit on watched players
+-- =============================================================================
+
+-- 0 keeps the bot-wide limit, a negative value removes it
+ALTER TABLE guild_configs ADD COLUMN IF NOT EXISTS quota_watched_players INT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Rolls back the whole schema; all tracked data is lost
+DROP TABLE IF EXISTS players;
+DROP TABLE IF EXISTS guild_configs;
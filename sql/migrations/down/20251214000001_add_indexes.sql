@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_guild_configs_world;
+DROP INDEX IF EXISTS idx_players_world_updated_at;
+DROP INDEX IF EXISTS idx_players_world;
@@ -0,0 +1 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS tibia_guilds;
@@ -0,0 +1,2 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS level_channel_id;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS death_channel_id;
@@ -0,0 +1,2 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS ping_min_level;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS ping_role_id;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_deaths_died_at;
@@ -0,0 +1 @@
+ALTER TABLE deaths DROP COLUMN IF EXISTS killers;
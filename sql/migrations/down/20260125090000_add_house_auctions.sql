@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS house_auctions;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS house_channel_id;
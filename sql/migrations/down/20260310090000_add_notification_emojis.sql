@@ -0,0 +1,4 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS level_reaction;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS death_reaction;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS level_emoji;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS death_emoji;
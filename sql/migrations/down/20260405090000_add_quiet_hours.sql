@@ -0,0 +1,3 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS quiet_catch_up;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS quiet_end;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS quiet_start;
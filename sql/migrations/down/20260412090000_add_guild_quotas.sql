@@ -0,0 +1,2 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS quota_ignored_players;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS quota_tibia_guilds;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS player_skills;
+DROP TABLE IF EXISTS skill_thresholds;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS watched_players;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS skill_channel_id;
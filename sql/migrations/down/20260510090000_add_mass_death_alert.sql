@@ -0,0 +1,2 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS mass_death_window_minutes;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS mass_death_count;
@@ -0,0 +1 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS broadcast_opt_out;
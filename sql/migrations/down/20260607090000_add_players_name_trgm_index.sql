@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_players_name_trgm;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS stopped_guild_configs;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS online_sessions;
+DROP TABLE IF EXISTS online_counts_hourly;
+DROP TABLE IF EXISTS online_counts;
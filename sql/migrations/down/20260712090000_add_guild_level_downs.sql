@@ -0,0 +1,2 @@
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS level_down_template;
+ALTER TABLE guild_configs DROP COLUMN IF EXISTS level_downs;
@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema migrations so the bot can
+// apply them on startup. Each <version>_<name>.sql may have a matching
+// down/<version>_<name>.sql used for rollbacks.
+package migrations
+
+import "embed"
+
+//go:embed *.sql down/*.sql
+var FS embed.FS
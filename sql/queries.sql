@@ -14,11 +14,239 @@ UPDATE guild_configs
 SET tibia_guilds = array_remove(tibia_guilds, @tibia_guild::text), updated_at = NOW()
 WHERE guild_id = $1;
 
+-- name: AddIgnoredPlayer :exec
+INSERT INTO guild_configs (guild_id, world, ignored_players, updated_at)
+VALUES ($1, '', ARRAY[@name::text], NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET ignored_players = array_append(COALESCE(guild_configs.ignored_players, '{}'), @name::text), updated_at = NOW()
+WHERE NOT EXISTS (SELECT 1 FROM unnest(guild_configs.ignored_players) AS p WHERE lower(p) = lower(@name::text));
+
+-- name: RemoveIgnoredPlayer :exec
+UPDATE guild_configs
+SET ignored_players = ARRAY(SELECT p FROM unnest(ignored_players) AS p WHERE lower(p) <> lower(@name::text)), updated_at = NOW()
+WHERE guild_id = $1;
+
+-- name: AddWatchedPlayer :exec
+INSERT INTO guild_configs (guild_id, world, watched_players, updated_at)
+VALUES ($1, '', ARRAY[@name::text], NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET watched_players = array_append(COALESCE(guild_configs.watched_players, '{}'), @name::text), updated_at = NOW()
+WHERE NOT EXISTS (SELECT 1 FROM unnest(guild_configs.watched_players) AS p WHERE lower(p) = lower(@name::text));
+
+-- name: RemoveWatchedPlayer :exec
+UPDATE guild_configs
+SET watched_players = ARRAY(SELECT p FROM unnest(watched_players) AS p WHERE lower(p) <> lower(@name::text)), updated_at = NOW()
+WHERE guild_id = $1;
+
+-- name: SetGuildLanguage :exec
+INSERT INTO guild_configs (guild_id, world, language, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET language = EXCLUDED.language, updated_at = NOW();
+
+-- name: SetGuildDeathChannel :exec
+INSERT INTO guild_configs (guild_id, world, death_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_channel_id = EXCLUDED.death_channel_id, updated_at = NOW();
+
+-- name: SetGuildHouseChannel :exec
+INSERT INTO guild_configs (guild_id, world, house_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET house_channel_id = EXCLUDED.house_channel_id, updated_at = NOW();
+
+-- name: SetGuildLevelChannel :exec
+INSERT INTO guild_configs (guild_id, world, level_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_channel_id = EXCLUDED.level_channel_id, updated_at = NOW();
+
+-- name: SetGuildMiscChannel :exec
+INSERT INTO guild_configs (guild_id, world, misc_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET misc_channel_id = EXCLUDED.misc_channel_id, updated_at = NOW();
+
+-- name: SetGuildSkillChannel :exec
+INSERT INTO guild_configs (guild_id, world, skill_channel_id, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET skill_channel_id = EXCLUDED.skill_channel_id, updated_at = NOW();
+
+-- name: SetGuildPingRole :exec
+INSERT INTO guild_configs (guild_id, world, ping_role_id, ping_min_level, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET ping_role_id = EXCLUDED.ping_role_id, ping_min_level = EXCLUDED.ping_min_level, updated_at = NOW();
+
+-- name: SetGuildPollInterval :exec
+INSERT INTO guild_configs (guild_id, world, poll_interval_seconds, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET poll_interval_seconds = EXCLUDED.poll_interval_seconds, updated_at = NOW();
+
+-- name: SetGuildLastNotified :exec
+UPDATE guild_configs SET last_notified_at = $2 WHERE guild_id = $1;
+
+-- name: SetGuildLowLevelDeaths :exec
+INSERT INTO guild_configs (guild_id, world, low_level_deaths, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET low_level_deaths = EXCLUDED.low_level_deaths, updated_at = NOW();
+
+-- name: SetGuildLevelDowns :exec
+INSERT INTO guild_configs (guild_id, world, level_downs, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_downs = EXCLUDED.level_downs, updated_at = NOW();
+
+-- name: SetGuildDeathLocation :exec
+INSERT INTO guild_configs (guild_id, world, death_location, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_location = EXCLUDED.death_location, updated_at = NOW();
+
+-- name: SetGuildDeathVerbosity :exec
+INSERT INTO guild_configs (guild_id, world, death_verbosity, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_verbosity = EXCLUDED.death_verbosity, updated_at = NOW();
+
+-- name: SetGuildResponseVisibility :exec
+INSERT INTO guild_configs (guild_id, world, response_visibility, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET response_visibility = EXCLUDED.response_visibility, updated_at = NOW();
+
+-- name: SetGuildShareRange :exec
+INSERT INTO guild_configs (guild_id, world, share_range, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET share_range = EXCLUDED.share_range, updated_at = NOW();
+
+-- name: SetGuildMassDeathAlert :exec
+INSERT INTO guild_configs (guild_id, world, mass_death_count, mass_death_window_minutes, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET mass_death_count = EXCLUDED.mass_death_count, mass_death_window_minutes = EXCLUDED.mass_death_window_minutes, updated_at = NOW();
+
+-- name: SetGuildBroadcastOptOut :exec
+INSERT INTO guild_configs (guild_id, world, broadcast_opt_out, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET broadcast_opt_out = EXCLUDED.broadcast_opt_out, updated_at = NOW();
+
+-- name: SetGuildMinLevel :exec
+INSERT INTO guild_configs (guild_id, world, min_level, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET min_level = EXCLUDED.min_level, updated_at = NOW();
+
+-- name: SetGuildMutedUntil :exec
+INSERT INTO guild_configs (guild_id, world, muted_until, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET muted_until = EXCLUDED.muted_until, updated_at = NOW();
+
+-- name: SetGuildTimezone :exec
+INSERT INTO guild_configs (guild_id, world, timezone, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET timezone = EXCLUDED.timezone, updated_at = NOW();
+
+-- name: SetGuildDeathTemplate :exec
+INSERT INTO guild_configs (guild_id, world, death_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_template = EXCLUDED.death_template, updated_at = NOW();
+
+-- name: SetGuildLevelTemplate :exec
+INSERT INTO guild_configs (guild_id, world, level_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_template = EXCLUDED.level_template, updated_at = NOW();
+
+-- name: SetGuildLevelDownTemplate :exec
+INSERT INTO guild_configs (guild_id, world, level_down_template, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_down_template = EXCLUDED.level_down_template, updated_at = NOW();
+
+-- name: SetGuildDeathEmoji :exec
+INSERT INTO guild_configs (guild_id, world, death_emoji, death_reaction, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET death_emoji = EXCLUDED.death_emoji, death_reaction = EXCLUDED.death_reaction, updated_at = NOW();
+
+-- name: SetGuildLevelEmoji :exec
+INSERT INTO guild_configs (guild_id, world, level_emoji, level_reaction, updated_at)
+VALUES ($1, '', $2, $3, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET level_emoji = EXCLUDED.level_emoji, level_reaction = EXCLUDED.level_reaction, updated_at = NOW();
+
+-- name: SetGuildQuietHours :exec
+INSERT INTO guild_configs (guild_id, world, quiet_start, quiet_end, quiet_catch_up, updated_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET quiet_start = EXCLUDED.quiet_start, quiet_end = EXCLUDED.quiet_end, quiet_catch_up = EXCLUDED.quiet_catch_up, updated_at = NOW();
+
+-- name: SetGuildPremium :exec
+INSERT INTO guild_configs (guild_id, world, premium, updated_at)
+VALUES ($1, '', $2, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET premium = EXCLUDED.premium, updated_at = NOW();
+
+-- name: SetGuildQuota :exec
+INSERT INTO guild_configs (guild_id, world, quota_tibia_guilds, quota_ignored_players, quota_watched_players, updated_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+ON CONFLICT (guild_id) DO UPDATE
+SET quota_tibia_guilds = EXCLUDED.quota_tibia_guilds, quota_ignored_players = EXCLUDED.quota_ignored_players, quota_watched_players = EXCLUDED.quota_watched_players, updated_at = NOW();
+
 -- name: GetGuildConfig :one
 SELECT * FROM guild_configs WHERE guild_id = $1;
 
+-- name: GetGuildDeathRoutes :many
+SELECT min_level, channel_id FROM death_routes WHERE guild_id = $1 ORDER BY min_level;
+
+-- name: GetDeathRoutes :many
+SELECT guild_id, min_level, channel_id FROM death_routes ORDER BY guild_id, min_level;
+
+-- name: SetDeathRoute :exec
+INSERT INTO death_routes (guild_id, min_level, channel_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id, min_level) DO UPDATE
+SET channel_id = EXCLUDED.channel_id;
+
+-- name: DeleteDeathRoute :execresult
+DELETE FROM death_routes WHERE guild_id = $1 AND min_level = $2;
+
+-- name: GetGuildSkillThresholds :many
+SELECT skill, min_value FROM skill_thresholds WHERE guild_id = $1 ORDER BY skill;
+
+-- name: GetSkillThresholds :many
+SELECT guild_id, skill, min_value FROM skill_thresholds ORDER BY guild_id, skill;
+
+-- name: SetSkillThreshold :exec
+INSERT INTO skill_thresholds (guild_id, skill, min_value)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id, skill) DO UPDATE
+SET min_value = EXCLUDED.min_value;
+
+-- name: DeleteSkillThreshold :execresult
+DELETE FROM skill_thresholds WHERE guild_id = $1 AND skill = $2;
+
 -- name: GetWorldsMap :many
-SELECT guild_id, world, tibia_guilds FROM guild_configs;
+SELECT guild_id, world, tibia_guilds, language, death_channel_id, level_channel_id, ping_role_id, ping_min_level, poll_interval_seconds, muted_until, ignored_players, low_level_deaths, last_notified_at, house_channel_id, misc_channel_id, timezone, death_template, level_template, death_emoji, level_emoji, death_reaction, level_reaction, quiet_start, quiet_end, quiet_catch_up, quota_tibia_guilds, quota_ignored_players, premium, share_range, skill_channel_id, watched_players, mass_death_count, mass_death_window_minutes, broadcast_opt_out, min_level, death_location, level_downs, level_down_template, response_visibility, death_verbosity, quota_watched_players FROM guild_configs
+WHERE removed_at IS NULL;
+
+-- name: GetPlayersByPrefix :many
+-- Case-insensitive prefix search backed by idx_players_name_trgm. The prefix
+-- must have \, % and _ escaped with a backslash.
+SELECT name FROM players
+WHERE world = $1 AND lower(name) LIKE lower(@prefix::text) || '%' ESCAPE '\'
+ORDER BY lower(name)
+LIMIT @max_results;
 
 -- name: GetPlayersLevels :many
 SELECT name, level FROM players WHERE world = $1;
@@ -29,6 +257,21 @@ VALUES ($1, $2, $3, NOW())
 ON CONFLICT (name) DO UPDATE
 SET level = EXCLUDED.level, world = EXCLUDED.world, updated_at = NOW();
 
+-- name: BatchUpsertPlayerLevels :exec
+INSERT INTO players (name, level, world, updated_at)
+SELECT unnest(@names::text[]), unnest(@levels::int[]), unnest(@worlds::text[]), NOW()
+ON CONFLICT (name) DO UPDATE
+SET level = EXCLUDED.level, world = EXCLUDED.world, updated_at = NOW();
+
+-- name: GetPlayerSkills :many
+SELECT name, value FROM player_skills WHERE world = $1 AND skill = $2;
+
+-- name: BatchUpsertPlayerSkills :exec
+INSERT INTO player_skills (world, name, skill, value, updated_at)
+SELECT @world::text, unnest(@names::text[]), @skill::text, unnest(@skill_values::int[]), NOW()
+ON CONFLICT (world, name, skill) DO UPDATE
+SET value = EXCLUDED.value, updated_at = NOW();
+
 -- name: BatchTouchPlayers :exec
 UPDATE players SET updated_at = NOW() WHERE name = ANY(@names::text[]);
 
@@ -38,5 +281,385 @@ DELETE FROM players WHERE world = $1 AND updated_at < NOW() - @threshold::interv
 -- name: GetOfflinePlayers :many
 SELECT name, level FROM players WHERE world = $1 AND name != ALL(@online_names::text[]);
 
+-- name: RenamePlayer :exec
+-- Moves everything stored under a character's old name to its new one. Rows
+-- already stored under the new name win over their old-name duplicates.
+WITH moved_player AS (
+    UPDATE players SET name = @new_name::text, updated_at = NOW()
+    WHERE players.name = @old_name::text
+      AND NOT EXISTS (SELECT 1 FROM players p WHERE p.name = @new_name::text)
+    RETURNING players.name
+), stale_player AS (
+    DELETE FROM players
+    WHERE players.name = @old_name::text
+      AND EXISTS (SELECT 1 FROM players p WHERE p.name = @new_name::text)
+    RETURNING players.name
+), moved_deaths AS (
+    UPDATE deaths SET name = @new_name::text
+    WHERE deaths.name = @old_name::text
+      AND NOT EXISTS (SELECT 1 FROM deaths d WHERE d.name = @new_name::text AND d.died_at = deaths.died_at)
+    RETURNING deaths.id
+), moved_level_ups AS (
+    UPDATE level_ups SET name = @new_name::text
+    WHERE level_ups.name = @old_name::text
+    RETURNING level_ups.id
+), moved_members AS (
+    UPDATE guild_members SET name = @new_name::text
+    WHERE guild_members.name = @old_name::text
+      AND NOT EXISTS (SELECT 1 FROM guild_members m WHERE m.guild_name = guild_members.guild_name AND m.name = @new_name::text)
+    RETURNING guild_members.name
+), stale_members AS (
+    DELETE FROM guild_members
+    WHERE guild_members.name = @old_name::text
+      AND EXISTS (SELECT 1 FROM guild_members m WHERE m.guild_name = guild_members.guild_name AND m.name = @new_name::text)
+    RETURNING guild_members.name
+)
+UPDATE guild_configs
+SET ignored_players = array_replace(ignored_players, @old_name::text, @new_name::text),
+    watched_players = array_replace(watched_players, @old_name::text, @new_name::text),
+    updated_at = NOW()
+WHERE @old_name::text = ANY(ignored_players) OR @old_name::text = ANY(watched_players);
+
 -- name: DeleteGuildConfig :exec
 DELETE FROM guild_configs WHERE guild_id = $1;
+
+-- name: MarkGuildRemoved :exec
+UPDATE guild_configs SET removed_at = $2 WHERE guild_id = $1 AND removed_at IS NULL;
+
+-- name: RestoreGuildConfig :execresult
+UPDATE guild_configs SET removed_at = NULL WHERE guild_id = $1 AND removed_at IS NOT NULL;
+
+-- name: DeleteRemovedGuildConfigs :execresult
+DELETE FROM guild_configs WHERE removed_at < $1;
+
+-- name: SaveStoppedGuildConfig :exec
+INSERT INTO stopped_guild_configs (guild_id, config, stopped_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_id) DO UPDATE
+SET config = EXCLUDED.config, stopped_at = EXCLUDED.stopped_at;
+
+-- name: GetStoppedGuildConfig :one
+SELECT guild_id, config, stopped_at FROM stopped_guild_configs WHERE guild_id = $1;
+
+-- name: DeleteStoppedGuildConfig :exec
+DELETE FROM stopped_guild_configs WHERE guild_id = $1;
+
+-- name: DeleteStoppedGuildConfigs :execresult
+DELETE FROM stopped_guild_configs WHERE stopped_at < $1;
+
+-- name: PurgeGuildData :one
+WITH config AS (
+    DELETE FROM guild_configs WHERE guild_configs.guild_id = @guild_id
+    RETURNING world, tibia_guilds
+), stopped AS (
+    DELETE FROM stopped_guild_configs WHERE stopped_guild_configs.guild_id = @guild_id
+    RETURNING stopped_guild_configs.guild_id
+), notifications AS (
+    DELETE FROM failed_notifications WHERE failed_notifications.guild_id = @guild_id
+    RETURNING failed_notifications.id
+), members AS (
+    DELETE FROM guild_members USING config
+    WHERE guild_members.guild_name = ANY(config.tibia_guilds)
+      AND NOT EXISTS (
+          SELECT 1 FROM guild_configs other
+          WHERE other.guild_id <> @guild_id AND guild_members.guild_name = ANY(other.tibia_guilds)
+      )
+    RETURNING guild_members.name
+), world_deaths AS (
+    DELETE FROM deaths USING config
+    WHERE deaths.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> @guild_id AND other.world = config.world)
+    RETURNING deaths.id
+), world_players AS (
+    DELETE FROM players USING config
+    WHERE players.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> @guild_id AND other.world = config.world)
+    RETURNING players.name
+), world_level_ups AS (
+    DELETE FROM level_ups USING config
+    WHERE level_ups.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> @guild_id AND other.world = config.world)
+    RETURNING level_ups.id
+), world_skills AS (
+    DELETE FROM player_skills USING config
+    WHERE player_skills.world = config.world
+      AND NOT EXISTS (SELECT 1 FROM guild_configs other WHERE other.guild_id <> @guild_id AND other.world = config.world)
+    RETURNING player_skills.name
+)
+SELECT
+    (SELECT COUNT(*) FROM config) + (SELECT COUNT(*) FROM stopped) AS configs,
+    (SELECT COUNT(*) FROM notifications) AS notifications,
+    (SELECT COUNT(*) FROM members) AS guild_members,
+    (SELECT COUNT(*) FROM world_deaths) AS deaths,
+    (SELECT COUNT(*) FROM world_players) AS players,
+    (SELECT COUNT(*) FROM world_level_ups) AS level_ups;
+
+-- name: RecordDeath :exec
+INSERT INTO deaths (name, world, level, reason, died_at, killers)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (name, died_at) DO NOTHING;
+
+-- name: CountDeathsSince :one
+SELECT COUNT(*) FROM deaths WHERE name = $1 AND died_at >= @since;
+
+-- name: CountKillsBetween :one
+-- Counts deaths on world from since until until of the victims with one of
+-- killers among their player killers.
+SELECT COUNT(*) FROM deaths
+WHERE world = $1 AND died_at >= @since AND died_at < @until
+  AND name = ANY(@victims::text[]) AND killers && @killers::text[];
+
+-- name: DeleteDeathsBefore :execresult
+DELETE FROM deaths WHERE died_at < @died_before;
+
+-- name: GetDeathCountsSince :many
+SELECT name, COUNT(*) AS deaths FROM deaths
+WHERE world = $1 AND died_at >= @since
+GROUP BY name
+ORDER BY deaths DESC, name
+LIMIT 25;
+
+-- name: GetTopKillersSince :many
+-- Counts deaths per player killer. With guild_names, only deaths of their
+-- members count and members killing each other are left out.
+SELECT killer::text AS killer, COUNT(*) AS kills
+FROM deaths, unnest(deaths.killers) AS killer
+WHERE deaths.world = $1 AND deaths.died_at >= @since
+  AND (cardinality(@guild_names::text[]) = 0 OR deaths.name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[])
+  ))
+  AND killer NOT IN (SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[]))
+GROUP BY killer
+ORDER BY kills DESC, killer
+LIMIT 10;
+
+-- name: GetFragCountsSince :many
+-- Counts per character of world the deaths of others it took part in as a
+-- player killer (frags) and its own deaths with a player among the killers.
+-- With guild_names, only their members are counted and killing a fellow
+-- member is no frag; without, only characters with a frag are. With name,
+-- only that character is counted, whatever the case.
+WITH members AS (
+    SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[])
+), frags AS (
+    SELECT killer::text AS name, COUNT(*) AS frags
+    FROM deaths, unnest(deaths.killers) AS killer
+    WHERE deaths.world = $1 AND deaths.died_at >= @since
+      AND deaths.name NOT IN (SELECT name FROM members)
+    GROUP BY killer
+), losses AS (
+    SELECT deaths.name, COUNT(*) AS deaths
+    FROM deaths
+    WHERE deaths.world = $1 AND deaths.died_at >= @since AND cardinality(deaths.killers) > 0
+    GROUP BY deaths.name
+)
+SELECT COALESCE(f.name, l.name)::text AS name, COALESCE(f.frags, 0)::bigint AS frags, COALESCE(l.deaths, 0)::bigint AS deaths
+FROM frags f FULL JOIN losses l ON l.name = f.name
+WHERE (@name::text = '' OR lower(COALESCE(f.name, l.name)) = lower(@name::text))
+  AND (COALESCE(f.name, l.name) IN (SELECT name FROM members)
+       OR (cardinality(@guild_names::text[]) = 0 AND (f.name IS NOT NULL OR @name::text <> '')))
+ORDER BY frags DESC, deaths, name
+LIMIT 25;
+
+-- name: RecordOnlinePresence :exec
+INSERT INTO online_presence (world, seen_hour, name)
+SELECT @world::text, @seen_hour::timestamptz, unnest(@names::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: GetOnlineCountsSince :many
+-- Counts characters online on world per hour. With guild_names, only their
+-- members count.
+SELECT seen_hour, COUNT(*) AS players
+FROM online_presence
+WHERE world = $1 AND seen_hour >= @since
+  AND (cardinality(@guild_names::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[])
+  ))
+GROUP BY seen_hour
+ORDER BY seen_hour;
+
+-- name: DeleteOnlinePresenceBefore :execresult
+DELETE FROM online_presence WHERE seen_hour < @seen_before;
+
+-- name: RecordOnlineCount :exec
+INSERT INTO online_counts (world, sampled_at, players)
+VALUES ($1, $2, $3)
+ON CONFLICT (world, sampled_at) DO UPDATE SET players = EXCLUDED.players;
+
+-- name: GetWorldOnlineCountsSince :many
+-- Averages the characters online on world per hour, from the hourly counts
+-- and the cycle counts not yet downsampled.
+SELECT hour, ROUND(players)::int AS players FROM (
+    SELECT h.hour, h.avg_players::float8 AS players
+    FROM online_counts_hourly h
+    WHERE h.world = $1 AND h.hour >= @since
+    UNION ALL
+    SELECT date_trunc('hour', c.sampled_at, 'UTC') AS hour, AVG(c.players)::float8 AS players
+    FROM online_counts c
+    WHERE c.world = $1 AND c.sampled_at >= @since
+    GROUP BY 1
+) counts
+ORDER BY hour;
+
+-- name: DownsampleOnlineCounts :execresult
+-- Folds cycle counts taken before sampled_before into hourly averages.
+-- sampled_before must fall on the hour so no hour is folded twice.
+WITH folded AS (
+    DELETE FROM online_counts WHERE sampled_at < @sampled_before
+    RETURNING world, sampled_at, players
+)
+INSERT INTO online_counts_hourly (world, hour, avg_players, max_players)
+SELECT world, date_trunc('hour', sampled_at, 'UTC'), AVG(players), MAX(players)
+FROM folded
+GROUP BY 1, 2
+ON CONFLICT (world, hour) DO NOTHING;
+
+-- name: DeleteHourlyOnlineCountsBefore :execresult
+DELETE FROM online_counts_hourly WHERE hour < @hour_before;
+
+-- name: RecordOnlineSessions :exec
+-- Extends the sessions of characters last seen at or after open_since to
+-- seen_at and starts a session for every other character in names.
+WITH seen AS (
+    SELECT unnest(@names::text[]) AS name
+), extended AS (
+    UPDATE online_sessions s SET last_seen_at = @seen_at
+    FROM seen
+    WHERE s.world = @world AND s.name = seen.name AND s.last_seen_at >= @open_since
+    RETURNING s.name
+)
+INSERT INTO online_sessions (world, name, started_at, last_seen_at)
+SELECT @world, seen.name, @seen_at, @seen_at
+FROM seen
+WHERE seen.name NOT IN (SELECT name FROM extended)
+ON CONFLICT DO NOTHING;
+
+-- name: GetOnlineTimeSince :one
+-- Sums how long the character was online since the given time, from the
+-- first to the last sighting of each session.
+SELECT COALESCE(SUM(EXTRACT(EPOCH FROM last_seen_at - GREATEST(started_at, @since))), 0)::bigint AS seconds
+FROM online_sessions
+WHERE name = $1 AND last_seen_at >= @since;
+
+-- name: DeleteOnlineSessionsBefore :execresult
+DELETE FROM online_sessions WHERE last_seen_at < @last_seen_before;
+
+-- name: GetDeathsPage :many
+-- Pages through deaths by ID for exports. With guild_names, only deaths of
+-- their members are returned.
+SELECT id, name, world, level, reason, died_at, killers FROM deaths
+WHERE world = $1 AND died_at >= @since AND id > @after_id
+  AND (cardinality(@guild_names::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[])
+  ))
+ORDER BY id
+LIMIT @page_size;
+
+-- name: RecordLevelUp :exec
+INSERT INTO level_ups (name, world, old_level, new_level, reached_at)
+VALUES ($1, $2, $3, $4, COALESCE(sqlc.narg(reached_at), NOW()));
+
+-- name: GetLevelUpsSince :many
+SELECT name, world, old_level, new_level, reached_at FROM level_ups
+WHERE name = $1 AND reached_at >= @since
+ORDER BY reached_at;
+
+-- name: GetDailyLevelGains :many
+SELECT date_trunc('day', reached_at AT TIME ZONE 'UTC')::timestamp AS day, SUM(new_level - old_level)::int AS levels
+FROM level_ups
+WHERE name = $1 AND reached_at >= @since
+GROUP BY day
+ORDER BY day;
+
+-- name: DeleteLevelUpsBefore :execresult
+DELETE FROM level_ups WHERE reached_at < @reached_before;
+
+-- name: GetLevelUpsPage :many
+-- Pages through level ups by ID for exports. With guild_names, only level
+-- ups of their members are returned.
+SELECT id, name, world, old_level, new_level, reached_at FROM level_ups
+WHERE world = $1 AND reached_at >= @since AND id > @after_id
+  AND (cardinality(@guild_names::text[]) = 0 OR name IN (
+      SELECT gm.name FROM guild_members gm WHERE gm.guild_name = ANY(@guild_names::text[])
+  ))
+ORDER BY id
+LIMIT @page_size;
+
+-- name: GetHouseAuctions :many
+SELECT world, house_id, name, town, current_bid, seen_at FROM house_auctions
+WHERE world = $1 AND seen_at >= @seen_since
+ORDER BY house_id;
+
+-- name: ReplaceHouseAuctions :exec
+-- Stores the auctions currently running on a world and forgets the rest.
+WITH ended AS (
+    DELETE FROM house_auctions
+    WHERE house_auctions.world = @world AND NOT (house_auctions.house_id = ANY(@house_ids::int[]))
+)
+INSERT INTO house_auctions (world, house_id, name, town, current_bid, seen_at)
+SELECT @world::text, unnest(@house_ids::int[]), unnest(@names::text[]), unnest(@towns::text[]), unnest(@current_bids::bigint[]), NOW()
+ON CONFLICT (world, house_id) DO UPDATE
+SET name = EXCLUDED.name, town = EXCLUDED.town, current_bid = EXCLUDED.current_bid, seen_at = NOW();
+
+-- name: EnqueueFailedNotification :exec
+INSERT INTO failed_notifications (guild_id, kind, payload, last_error, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetDueFailedNotifications :many
+SELECT id, guild_id, kind, payload, attempts, last_error, next_attempt_at, created_at FROM failed_notifications
+WHERE next_attempt_at <= @due
+ORDER BY id
+LIMIT @max_items;
+
+-- name: GetGuildFailedNotifications :many
+SELECT id, guild_id, kind, payload, attempts, last_error, next_attempt_at, created_at FROM failed_notifications
+WHERE guild_id = $1
+ORDER BY id;
+
+-- name: RescheduleFailedNotification :exec
+UPDATE failed_notifications
+SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+WHERE id = $1;
+
+-- name: DeleteFailedNotification :exec
+DELETE FROM failed_notifications WHERE id = $1;
+
+-- name: DeleteExpiredFailedNotifications :execresult
+DELETE FROM failed_notifications WHERE created_at < @created_before;
+
+-- name: GetGuildMemberNames :many
+SELECT name FROM guild_members WHERE guild_name = $1 ORDER BY name;
+
+-- name: AddGuildMembers :exec
+INSERT INTO guild_members (guild_name, name)
+SELECT $1, unnest(@names::text[])
+ON CONFLICT (guild_name, name) DO NOTHING;
+
+-- name: RemoveGuildMembers :exec
+DELETE FROM guild_members WHERE guild_name = $1 AND name = ANY(@names::text[]);
+
+-- name: GetGuildMemberCache :many
+SELECT guild_name, members, fetched_at FROM guild_member_cache ORDER BY guild_name;
+
+-- name: SaveGuildMemberCache :exec
+INSERT INTO guild_member_cache (guild_name, members, fetched_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (guild_name) DO UPDATE
+SET members = EXCLUDED.members, fetched_at = EXCLUDED.fetched_at;
+
+-- name: DeleteGuildMemberCacheExcept :execresult
+DELETE FROM guild_member_cache WHERE NOT (guild_name = ANY(@keep::text[]));
+
+-- name: GetLatestGuildWar :one
+SELECT guild_name, started_at, ended_at FROM guild_wars
+WHERE guild_name = $1
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: StartGuildWar :exec
+INSERT INTO guild_wars (guild_name, started_at)
+VALUES ($1, $2)
+ON CONFLICT (guild_name, started_at) DO NOTHING;
+
+-- name: EndGuildWar :exec
+UPDATE guild_wars SET ended_at = $2 WHERE guild_name = $1 AND ended_at IS NULL;
@@ -2,7 +2,46 @@ CREATE TABLE IF NOT EXISTS guild_configs (
     guild_id VARCHAR(32) PRIMARY KEY,
     world VARCHAR(64) NOT NULL,
     tibia_guilds TEXT[] DEFAULT NULL,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    language VARCHAR(8) NOT NULL DEFAULT 'en',
+    death_channel_id VARCHAR(32) NOT NULL DEFAULT '',
+    level_channel_id VARCHAR(32) NOT NULL DEFAULT '',
+    ping_role_id VARCHAR(32) NOT NULL DEFAULT '',
+    ping_min_level INT NOT NULL DEFAULT 0,
+    poll_interval_seconds INT NOT NULL DEFAULT 0,
+    muted_until TIMESTAMPTZ DEFAULT NULL,
+    ignored_players TEXT[] DEFAULT NULL,
+    low_level_deaths BOOLEAN NOT NULL DEFAULT TRUE,
+    last_notified_at TIMESTAMPTZ DEFAULT NULL,
+    removed_at TIMESTAMPTZ DEFAULT NULL,
+    house_channel_id VARCHAR(32) NOT NULL DEFAULT '',
+    misc_channel_id VARCHAR(32) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    death_template TEXT NOT NULL DEFAULT '',
+    level_template TEXT NOT NULL DEFAULT '',
+    death_emoji VARCHAR(64) NOT NULL DEFAULT '',
+    level_emoji VARCHAR(64) NOT NULL DEFAULT '',
+    death_reaction BOOLEAN NOT NULL DEFAULT FALSE,
+    level_reaction BOOLEAN NOT NULL DEFAULT FALSE,
+    quiet_start INT NOT NULL DEFAULT 0,
+    quiet_end INT NOT NULL DEFAULT 0,
+    quiet_catch_up BOOLEAN NOT NULL DEFAULT FALSE,
+    quota_tibia_guilds INT NOT NULL DEFAULT 0,
+    quota_ignored_players INT NOT NULL DEFAULT 0,
+    premium BOOLEAN NOT NULL DEFAULT FALSE,
+    share_range BOOLEAN NOT NULL DEFAULT FALSE,
+    skill_channel_id VARCHAR(32) NOT NULL DEFAULT '',
+    watched_players TEXT[] DEFAULT NULL,
+    mass_death_count INT NOT NULL DEFAULT 0,
+    mass_death_window_minutes INT NOT NULL DEFAULT 0,
+    broadcast_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    min_level INT NOT NULL DEFAULT 0,
+    death_location BOOLEAN NOT NULL DEFAULT FALSE,
+    level_downs BOOLEAN NOT NULL DEFAULT FALSE,
+    level_down_template TEXT NOT NULL DEFAULT '',
+    response_visibility TEXT NOT NULL DEFAULT '',
+    death_verbosity TEXT NOT NULL DEFAULT '',
+    quota_watched_players INT NOT NULL DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS players (
@@ -11,3 +50,128 @@ CREATE TABLE IF NOT EXISTS players (
     world VARCHAR(64) NOT NULL,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_players_name_trgm ON players USING gin (lower(name) gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS deaths (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    world VARCHAR(64) NOT NULL,
+    level INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    died_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    killers TEXT[] DEFAULT NULL,
+    UNIQUE (name, died_at)
+);
+
+CREATE TABLE IF NOT EXISTS level_ups (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    world VARCHAR(64) NOT NULL,
+    old_level INT NOT NULL,
+    new_level INT NOT NULL,
+    reached_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS house_auctions (
+    world VARCHAR(64) NOT NULL,
+    house_id INT NOT NULL,
+    name TEXT NOT NULL,
+    town VARCHAR(64) NOT NULL,
+    current_bid BIGINT NOT NULL DEFAULT 0,
+    seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (world, house_id)
+);
+
+CREATE TABLE IF NOT EXISTS failed_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id VARCHAR(32) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS guild_members (
+    guild_name VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guild_name, name)
+);
+
+CREATE TABLE IF NOT EXISTS guild_member_cache (
+    guild_name VARCHAR(64) PRIMARY KEY,
+    members TEXT[] NOT NULL DEFAULT '{}',
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS guild_wars (
+    guild_name VARCHAR(64) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    PRIMARY KEY (guild_name, started_at)
+);
+
+CREATE TABLE IF NOT EXISTS death_routes (
+    guild_id VARCHAR(32) NOT NULL REFERENCES guild_configs (guild_id) ON DELETE CASCADE,
+    min_level INT NOT NULL,
+    channel_id VARCHAR(32) NOT NULL,
+    PRIMARY KEY (guild_id, min_level)
+);
+
+CREATE TABLE IF NOT EXISTS skill_thresholds (
+    guild_id VARCHAR(32) NOT NULL REFERENCES guild_configs (guild_id) ON DELETE CASCADE,
+    skill VARCHAR(16) NOT NULL,
+    min_value INT NOT NULL,
+    PRIMARY KEY (guild_id, skill)
+);
+
+CREATE TABLE IF NOT EXISTS player_skills (
+    world VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    skill VARCHAR(16) NOT NULL,
+    value INT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (world, name, skill)
+);
+
+CREATE TABLE IF NOT EXISTS stopped_guild_configs (
+    guild_id VARCHAR(32) PRIMARY KEY,
+    config JSONB NOT NULL,
+    stopped_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS online_presence (
+    world VARCHAR(64) NOT NULL,
+    seen_hour TIMESTAMPTZ NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    PRIMARY KEY (world, seen_hour, name)
+);
+
+CREATE TABLE IF NOT EXISTS online_counts (
+    world VARCHAR(64) NOT NULL,
+    sampled_at TIMESTAMPTZ NOT NULL,
+    players INT NOT NULL,
+    PRIMARY KEY (world, sampled_at)
+);
+
+CREATE TABLE IF NOT EXISTS online_counts_hourly (
+    world VARCHAR(64) NOT NULL,
+    hour TIMESTAMPTZ NOT NULL,
+    avg_players REAL NOT NULL,
+    max_players INT NOT NULL,
+    PRIMARY KEY (world, hour)
+);
+
+CREATE TABLE IF NOT EXISTS online_sessions (
+    world VARCHAR(64) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (world, name, started_at)
+);